@@ -84,4 +84,8 @@ var (
 	DefaultRPCModules = []string{"system", "author", "chain", "state"}
 	// DefaultRPCWSPort rpc websocket port
 	DefaultRPCWSPort = uint32(8546)
+	// DefaultRPCWSMaxSubscriptions maximum number of subscriptions per websocket connection
+	DefaultRPCWSMaxSubscriptions = uint32(1024)
+	// DefaultRPCCallTimeout maximum duration in seconds of a runtime call made via RPC
+	DefaultRPCCallTimeout = uint32(10)
 )
@@ -0,0 +1,32 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package gssmr
+
+import (
+	// embed the genesis files
+	_ "embed"
+)
+
+// GenesisRaw is the raw genesis of the gssmr chain, embedded from genesis-raw.json
+//
+//go:embed genesis-raw.json
+var GenesisRaw []byte
+
+// Genesis is the human-readable genesis of the gssmr chain, embedded from genesis.json
+//
+//go:embed genesis.json
+var Genesis []byte
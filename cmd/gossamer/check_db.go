@@ -0,0 +1,66 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/urfave/cli"
+)
+
+// checkDBAction is the action for the "check-db" subcommand
+func checkDBAction(ctx *cli.Context) error {
+	_, err := setupLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := createBuildSpecConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the node must be stopped, since the database can only be opened by one process
+	basepath := utils.ExpandDir(cfg.Global.BasePath)
+	logger.Info("checking database...", "basepath", basepath)
+
+	res, err := dot.CheckDB(&dot.CheckDBConfig{
+		BasePath:            basepath,
+		StateSampleInterval: uint64(ctx.Uint(CheckDBStateSampleFlag.Name)),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, inc := range res.Inconsistencies {
+		fmt.Println(inc)
+	}
+
+	fmt.Printf("blocks checked: %d\n", res.Blocks)
+	fmt.Printf("state roots checked: %d (%d unavailable)\n", res.StateRoots, res.StateUnavailable)
+	fmt.Printf("justifications checked: %d\n", res.Justifications)
+
+	if len(res.Inconsistencies) != 0 {
+		return fmt.Errorf("found %d inconsistencies", len(res.Inconsistencies))
+	}
+
+	fmt.Println("no inconsistencies found")
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmtime"
 
@@ -70,7 +71,7 @@ func createDotConfig(ctx *cli.Context) (cfg *dot.Config, err error) {
 
 	// check --chain flag and load configuration from defaults.go
 	if id := ctx.GlobalString(ChainFlag.Name); id != "" {
-		switch id {
+		switch genesis.PresetName(id) {
 		case "gssmr":
 			logger.Debug("loading default configuration...", "id", id)
 			cfg = dot.GssmrConfig()
@@ -110,7 +111,11 @@ func createDotConfig(ctx *cli.Context) (cfg *dot.Config, err error) {
 
 	// set remaining cli configuration values
 	setDotAccountConfig(ctx, tomlCfg.Account, &cfg.Account)
-	setDotCoreConfig(ctx, tomlCfg.Core, &cfg.Core)
+	err = setDotCoreConfig(ctx, tomlCfg.Core, &cfg.Core)
+	if err != nil {
+		logger.Error("failed to set core configuration", "error", err)
+		return nil, err
+	}
 	setDotNetworkConfig(ctx, tomlCfg.Network, &cfg.Network)
 	setDotRPCConfig(ctx, tomlCfg.RPC, &cfg.RPC)
 
@@ -196,7 +201,11 @@ func createExportConfig(ctx *cli.Context) (*dot.Config, error) {
 
 	// set cli configuration values
 	setDotAccountConfig(ctx, tomlCfg.Account, &cfg.Account)
-	setDotCoreConfig(ctx, tomlCfg.Core, &cfg.Core)
+	err = setDotCoreConfig(ctx, tomlCfg.Core, &cfg.Core)
+	if err != nil {
+		logger.Error("failed to set core configuration", "error", err)
+		return nil, err
+	}
 	setDotNetworkConfig(ctx, tomlCfg.Network, &cfg.Network)
 	setDotRPCConfig(ctx, tomlCfg.RPC, &cfg.RPC)
 
@@ -330,9 +339,17 @@ func setDotInitConfig(ctx *cli.Context, tomlCfg ctoml.InitConfig, cfg *dot.InitC
 		cfg.GenesisRaw = genesis
 	}
 
+	cfg.GenesisChecksum = tomlCfg.GenesisChecksum
+
+	// check --genesis-checksum flag and update init configuration
+	if checksum := ctx.String(GenesisChecksumFlag.Name); checksum != "" {
+		cfg.GenesisChecksum = checksum
+	}
+
 	logger.Debug(
 		"init configuration",
 		"genesis-raw", cfg.GenesisRaw,
+		"genesis-checksum", cfg.GenesisChecksum,
 	)
 }
 
@@ -354,6 +371,14 @@ func setDotGlobalConfig(ctx *cli.Context, tomlCfg *ctoml.Config, cfg *dot.Global
 		if tomlCfg.Global.LogLvl != "" {
 			cfg.LogLvl, _ = log.LvlFromString(tomlCfg.Global.LogLvl)
 		}
+
+		cfg.BackupDB = tomlCfg.Global.BackupDB
+		cfg.DBCache = tomlCfg.Global.DBCache
+		cfg.ExtrinsicIndex = tomlCfg.Global.ExtrinsicIndex
+		cfg.TracingEndpoint = tomlCfg.Global.TracingEndpoint
+		cfg.Hasher = tomlCfg.Global.Hasher
+		cfg.TelemetryURLs = tomlCfg.Global.TelemetryURLs
+		cfg.NoTelemetry = tomlCfg.Global.NoTelemetry
 	}
 
 	// check --name flag and update node configuration
@@ -363,7 +388,7 @@ func setDotGlobalConfig(ctx *cli.Context, tomlCfg *ctoml.Config, cfg *dot.Global
 
 	// check --chain flag and update node configuration
 	if id := ctx.GlobalString(ChainFlag.Name); id != "" {
-		cfg.ID = id
+		cfg.ID = genesis.PresetName(id)
 	}
 
 	// check --basepath flag and update node configuration
@@ -371,6 +396,36 @@ func setDotGlobalConfig(ctx *cli.Context, tomlCfg *ctoml.Config, cfg *dot.Global
 		cfg.BasePath = basepath
 	}
 
+	// check --backupdb flag and update node configuration
+	if backupDB := ctx.Bool(BackupDBFlag.Name); backupDB {
+		cfg.BackupDB = true
+	}
+
+	// check --db-cache flag and update node configuration
+	if dbCache := ctx.String(DBCacheFlag.Name); dbCache != "" {
+		cfg.DBCache = dbCache
+	}
+
+	// check --extrinsic-index flag and update node configuration
+	if ctx.Bool(ExtrinsicIndexFlag.Name) {
+		cfg.ExtrinsicIndex = true
+	}
+
+	// check --tracing-endpoint flag and update node configuration
+	if endpoint := ctx.String(TracingEndpointFlag.Name); endpoint != "" {
+		cfg.TracingEndpoint = endpoint
+	}
+
+	// check --telemetry-url flags and update node configuration
+	if urls := ctx.StringSlice(TelemetryURLFlag.Name); len(urls) != 0 {
+		cfg.TelemetryURLs = urls
+	}
+
+	// check --no-telemetry flag and update node configuration
+	if ctx.Bool(NoTelemetryFlag.Name) {
+		cfg.NoTelemetry = true
+	}
+
 	// check if cfg.BasePath his been set, if not set to default
 	if cfg.BasePath == "" {
 		cfg.BasePath = dot.GssmrConfig().Global.BasePath
@@ -387,6 +442,13 @@ func setDotGlobalConfig(ctx *cli.Context, tomlCfg *ctoml.Config, cfg *dot.Global
 		"name", cfg.Name,
 		"id", cfg.ID,
 		"basepath", cfg.BasePath,
+		"backupdb", cfg.BackupDB,
+		"dbcache", cfg.DBCache,
+		"extrinsicindex", cfg.ExtrinsicIndex,
+		"tracingendpoint", cfg.TracingEndpoint,
+		"hasher", cfg.Hasher,
+		"telemetryurls", cfg.TelemetryURLs,
+		"notelemetry", cfg.NoTelemetry,
 	)
 }
 
@@ -418,11 +480,22 @@ func setDotAccountConfig(ctx *cli.Context, tomlCfg ctoml.AccountConfig, cfg *dot
 }
 
 // setDotCoreConfig sets dot.CoreConfig using flag values from the cli context
-func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreConfig) {
+func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreConfig) error {
 	cfg.Roles = tomlCfg.Roles
 	cfg.BabeAuthority = tomlCfg.Roles == types.AuthorityRole
 	cfg.GrandpaAuthority = tomlCfg.Roles == types.AuthorityRole
 	cfg.SlotDuration = tomlCfg.SlotDuration
+	cfg.HeapPages = tomlCfg.HeapPages
+	cfg.TxBanDuration = tomlCfg.TxBanDuration
+	cfg.PreferLocalTxs = tomlCfg.PreferLocalTxs
+	cfg.NoPropagate = tomlCfg.NoPropagate
+	cfg.LocalTxsOnly = tomlCfg.LocalTxsOnly
+	cfg.PoolLimit = tomlCfg.PoolLimit
+	cfg.PoolKBytes = tomlCfg.PoolKBytes
+	cfg.HeadersOnly = tomlCfg.HeadersOnly
+	cfg.HostStats = tomlCfg.HostStats
+	cfg.SkipEmptyBlocks = tomlCfg.SkipEmptyBlocks
+	cfg.HaltAtBlock = tomlCfg.HaltAtBlock
 
 	// check --roles flag and update node configuration
 	if roles := ctx.GlobalString(RolesFlag.Name); roles != "" {
@@ -480,13 +553,124 @@ func setDotCoreConfig(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *dot.CoreC
 		logger.Warn("invalid wasm interpreter set in config", "defaulting to", gssmr.DefaultWasmInterpreter)
 	}
 
+	// check --heappages flag and update node configuration
+	if heapPages := ctx.GlobalUint(HeapPagesFlag.Name); heapPages != 0 {
+		cfg.HeapPages = uint32(heapPages)
+	}
+
+	// check --preferlocaltxs flag and update node configuration
+	if preferLocal := ctx.GlobalBool(PreferLocalTxsFlag.Name); preferLocal {
+		cfg.PreferLocalTxs = true
+	}
+
+	// check --no-propagate flag and update node configuration
+	if noPropagate := ctx.GlobalBool(NoPropagateFlag.Name); noPropagate {
+		cfg.NoPropagate = true
+	}
+
+	// check --local-txs-only flag and update node configuration
+	if localOnly := ctx.GlobalBool(LocalTxsOnlyFlag.Name); localOnly {
+		cfg.LocalTxsOnly = true
+	}
+
+	// check --pool-limit flag and update node configuration
+	if limit := ctx.GlobalUint(PoolLimitFlag.Name); limit != 0 {
+		cfg.PoolLimit = uint32(limit)
+	}
+
+	// check --pool-kbytes flag and update node configuration
+	if kbytes := ctx.GlobalUint(PoolKBytesFlag.Name); kbytes != 0 {
+		cfg.PoolKBytes = uint32(kbytes)
+	}
+
+	// check --headers-only flag and update node configuration
+	if headersOnly := ctx.GlobalBool(HeadersOnlyFlag.Name); headersOnly {
+		cfg.HeadersOnly = true
+	}
+
+	// check --host-stats flag and update node configuration
+	if hostStats := ctx.GlobalBool(HostStatsFlag.Name); hostStats {
+		cfg.HostStats = true
+	}
+
+	// check --skip-empty-blocks flag and update node configuration
+	if skipEmpty := ctx.GlobalBool(SkipEmptyBlocksFlag.Name); skipEmpty {
+		cfg.SkipEmptyBlocks = true
+	}
+
+	// check --halt-at-block flag and update node configuration
+	if halt := ctx.GlobalUint64(HaltAtBlockFlag.Name); halt != 0 {
+		cfg.HaltAtBlock = halt
+	}
+
+	if err := setExecutionStrategies(ctx, tomlCfg, &cfg.Execution); err != nil {
+		return err
+	}
+
 	logger.Debug(
 		"core configuration",
 		"babe-authority", cfg.BabeAuthority,
 		"grandpa-authority", cfg.GrandpaAuthority,
 		"babe-threshold", cfg.BabeThreshold,
 		"wasm-interpreter", cfg.WasmInterpreter,
+		"heap-pages", cfg.HeapPages,
+		"tx-ban-duration", cfg.TxBanDuration,
+		"prefer-local-txs", cfg.PreferLocalTxs,
+		"no-propagate", cfg.NoPropagate,
+		"local-txs-only", cfg.LocalTxsOnly,
+		"pool-limit", cfg.PoolLimit,
+		"pool-kbytes", cfg.PoolKBytes,
+		"headers-only", cfg.HeadersOnly,
+		"host-stats", cfg.HostStats,
+		"skip-empty-blocks", cfg.SkipEmptyBlocks,
+		"halt-at-block", cfg.HaltAtBlock,
+		"execution-syncing", cfg.Execution.Strategy(runtime.ContextSyncing),
+		"execution-import-block", cfg.Execution.Strategy(runtime.ContextImportBlock),
+		"execution-block-construction", cfg.Execution.Strategy(runtime.ContextBlockConstruction),
+		"execution-other", cfg.Execution.Strategy(runtime.ContextOther),
 	)
+
+	return nil
+}
+
+// setExecutionStrategies sets the runtime execution strategy of each context. The strategy set by --execution
+// (or execution in the toml) applies to every context, and is overridden by the per-context values.
+// An invalid strategy for any context returns an error.
+func setExecutionStrategies(ctx *cli.Context, tomlCfg ctoml.CoreConfig, cfg *runtime.ExecutionStrategies) error {
+	all := tomlCfg.Execution
+	if execution := ctx.GlobalString(ExecutionFlag.Name); execution != "" {
+		all = execution
+	}
+
+	var err error
+	strategy := func(flag cli.StringFlag, tomlValue string) runtime.ExecutionStrategy {
+		name := all
+		if tomlValue != "" {
+			name = tomlValue
+		}
+		if value := ctx.GlobalString(flag.Name); value != "" {
+			name = value
+		}
+
+		if name == "" || err != nil {
+			return ""
+		}
+
+		s, parseErr := runtime.ParseExecutionStrategy(name)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid %s: %w", flag.Name, parseErr)
+			return ""
+		}
+
+		return s
+	}
+
+	cfg.Syncing = strategy(ExecutionSyncingFlag, tomlCfg.ExecutionSyncing)
+	cfg.ImportBlock = strategy(ExecutionImportBlockFlag, tomlCfg.ExecutionImportBlock)
+	cfg.BlockConstruction = strategy(ExecutionBlockConstructionFlag, tomlCfg.ExecutionBlockConstruction)
+	cfg.Other = strategy(ExecutionOtherFlag, tomlCfg.ExecutionOther)
+
+	return err
 }
 
 // setDotNetworkConfig sets dot.NetworkConfig using flag values from the cli context
@@ -496,12 +680,24 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 	cfg.ProtocolID = tomlCfg.ProtocolID
 	cfg.NoBootstrap = tomlCfg.NoBootstrap
 	cfg.NoMDNS = tomlCfg.NoMDNS
+	cfg.DisplayName = tomlCfg.DisplayName
+	cfg.Location = tomlCfg.Location
+	cfg.Contact = tomlCfg.Contact
+	cfg.ListenAddrs = tomlCfg.ListenAddrs
+	cfg.MaxSyncPeers = tomlCfg.MaxSyncPeers
+	cfg.MaxTransactionPeers = tomlCfg.MaxTransactionPeers
+	cfg.MaxLightPeers = tomlCfg.MaxLightPeers
 
 	// check --port flag and update node configuration
 	if port := ctx.GlobalUint(PortFlag.Name); port != 0 {
 		cfg.Port = uint32(port)
 	}
 
+	// check --listen-addr flags and update node configuration
+	if addrs := ctx.GlobalStringSlice(ListenAddrFlag.Name); len(addrs) != 0 {
+		cfg.ListenAddrs = addrs
+	}
+
 	// check --bootnodes flag and update node configuration
 	if bootnodes := ctx.GlobalString(BootnodesFlag.Name); bootnodes != "" {
 		cfg.Bootnodes = strings.Split(ctx.GlobalString(BootnodesFlag.Name), ",")
@@ -527,6 +723,19 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 		cfg.NoMDNS = true
 	}
 
+	// check --display-name, --location and --contact flags and update node configuration
+	if name := ctx.GlobalString(DisplayNameFlag.Name); name != "" {
+		cfg.DisplayName = name
+	}
+
+	if location := ctx.GlobalString(LocationFlag.Name); location != "" {
+		cfg.Location = location
+	}
+
+	if contact := ctx.GlobalString(ContactFlag.Name); contact != "" {
+		cfg.Contact = contact
+	}
+
 	logger.Debug(
 		"network configuration",
 		"port", cfg.Port,
@@ -534,6 +743,13 @@ func setDotNetworkConfig(ctx *cli.Context, tomlCfg ctoml.NetworkConfig, cfg *dot
 		"protocol", cfg.ProtocolID,
 		"nobootstrap", cfg.NoBootstrap,
 		"nomdns", cfg.NoMDNS,
+		"display-name", cfg.DisplayName,
+		"location", cfg.Location,
+		"contact", cfg.Contact,
+		"listen-addrs", cfg.ListenAddrs,
+		"max-sync-peers", cfg.MaxSyncPeers,
+		"max-transaction-peers", cfg.MaxTransactionPeers,
+		"max-light-peers", cfg.MaxLightPeers,
 	)
 }
 
@@ -545,6 +761,15 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 	cfg.Modules = tomlCfg.Modules
 	cfg.WSPort = tomlCfg.WSPort
 	cfg.WSEnabled = tomlCfg.WSEnabled
+	cfg.WSMaxSubscriptions = tomlCfg.WSMaxSubscriptions
+	cfg.CallTimeout = tomlCfg.CallTimeout
+	cfg.FinalizedOnly = tomlCfg.FinalizedOnly
+	cfg.LogRequests = tomlCfg.LogRequests
+	cfg.AuthToken = tomlCfg.AuthToken
+	cfg.UnsafeMethods = tomlCfg.UnsafeMethods
+	cfg.Tracing = tomlCfg.Tracing
+	cfg.Unsafe = tomlCfg.Unsafe
+	cfg.SlowQuery = tomlCfg.SlowQuery
 
 	// check --rpc flag and update node configuration
 	if enabled := ctx.GlobalBool(RPCEnabledFlag.Name); enabled {
@@ -578,6 +803,31 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 		cfg.WSEnabled = false
 	}
 
+	// check --wsmaxsubs flag and update node configuration
+	if maxSubs := ctx.GlobalUint(WSMaxSubscriptionsFlag.Name); maxSubs != 0 {
+		cfg.WSMaxSubscriptions = uint32(maxSubs)
+	}
+
+	// check --rpccalltimeout flag and update node configuration
+	if timeout := ctx.GlobalUint(RPCCallTimeoutFlag.Name); timeout != 0 {
+		cfg.CallTimeout = uint32(timeout)
+	}
+
+	// check --finalizedonly flag and update node configuration
+	if finalizedOnly := ctx.GlobalBool(FinalizedOnlyFlag.Name); finalizedOnly {
+		cfg.FinalizedOnly = true
+	}
+
+	// check --rpc-unsafe flag and update node configuration
+	if unsafe := ctx.GlobalBool(RPCUnsafeFlag.Name); unsafe {
+		cfg.Unsafe = true
+	}
+
+	// check --rpc-slow-query flag and update node configuration
+	if slowQuery := ctx.GlobalUint(RPCSlowQueryFlag.Name); slowQuery != 0 {
+		cfg.SlowQuery = uint32(slowQuery)
+	}
+
 	// format rpc modules
 	if len(cfg.Modules) == 0 {
 		cfg.Modules = []string(nil)
@@ -591,17 +841,25 @@ func setDotRPCConfig(ctx *cli.Context, tomlCfg ctoml.RPCConfig, cfg *dot.RPCConf
 		"modules", cfg.Modules,
 		"ws", cfg.WSEnabled,
 		"wsport", cfg.WSPort,
+		"wsmaxsubs", cfg.WSMaxSubscriptions,
+		"rpccalltimeout", cfg.CallTimeout,
+		"finalizedonly", cfg.FinalizedOnly,
+		"logrequests", cfg.LogRequests,
+		"auth", cfg.AuthToken != "",
+		"tracing", cfg.Tracing,
+		"unsafe", cfg.Unsafe,
+		"slowquery", cfg.SlowQuery,
 	)
 }
 
 func setSystemInfoConfig(ctx *cli.Context, cfg *dot.Config) {
 	// load system information
 	if ctx.App != nil {
-		cfg.System.SystemName = ctx.App.Name
+		cfg.System.SystemName = implementationName
 		cfg.System.SystemVersion = ctx.App.Version
 	}
 
-	// TODO lookup system properties from genesis file and set here (See issue #865)
+	// the system properties are set from the genesis file, or from the database once the node is initialized
 	cfg.System.NodeName = cfg.Global.Name
 	props := make(map[string]interface{})
 	cfg.System.SystemProperties = props
@@ -623,7 +881,7 @@ func updateDotConfigFromGenesisJSONRaw(ctx *cli.Context, tomlCfg ctoml.Config, c
 	}
 
 	// load Genesis from genesis configuration file
-	gen, err := genesis.NewGenesisFromJSONRaw(cfg.Init.GenesisRaw)
+	gen, err := dot.LoadGenesis(&cfg.Init)
 	if err != nil {
 		logger.Error("failed to load genesis from file", "error", err)
 		return // exit
@@ -665,6 +923,10 @@ func updateDotConfigFromGenesisJSONRaw(ctx *cli.Context, tomlCfg ctoml.Config, c
 		cfg.Network.ProtocolID = gen.ProtocolID
 	}
 
+	if gen.Properties != nil {
+		cfg.System.SystemProperties = gen.Properties
+	}
+
 	logger.Debug(
 		"configuration after genesis json",
 		"name", cfg.Global.Name,
@@ -24,6 +24,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/utils"
 
 	database "github.com/ChainSafe/chaindb"
@@ -104,6 +105,15 @@ func TestInitConfigFromFlags(t *testing.T) {
 				GenesisRaw: "test_genesis",
 			},
 		},
+		{
+			"Test gossamer --genesis-checksum",
+			[]string{"config", "genesis-raw", "genesis-checksum"},
+			[]interface{}{testCfgFile.Name(), "https://example.com/genesis.json.gz", "0x1234"},
+			dot.InitConfig{
+				GenesisRaw:      "https://example.com/genesis.json.gz",
+				GenesisChecksum: "0x1234",
+			},
+		},
 	}
 
 	for _, c := range testcases {
@@ -157,6 +167,17 @@ func TestGlobalConfigFromFlags(t *testing.T) {
 				LogLvl:   log.LvlInfo,
 			},
 		},
+		{
+			"Test gossamer --chain kusama",
+			[]string{"config", "chain"},
+			[]interface{}{testCfgFile.Name(), "kusama"},
+			dot.GlobalConfig{
+				Name:     testCfg.Global.Name,
+				ID:       "ksmcc",
+				BasePath: testCfg.Global.BasePath,
+				LogLvl:   log.LvlInfo,
+			},
+		},
 		{
 			"Test gossamer --name",
 			[]string{"config", "name"},
@@ -179,6 +200,54 @@ func TestGlobalConfigFromFlags(t *testing.T) {
 				LogLvl:   log.LvlInfo,
 			},
 		},
+		{
+			"Test gossamer --backupdb",
+			[]string{"config", "backupdb"},
+			[]interface{}{testCfgFile.Name(), "true"},
+			dot.GlobalConfig{
+				Name:     testCfg.Global.Name,
+				ID:       testCfg.Global.ID,
+				BasePath: testCfg.Global.BasePath,
+				LogLvl:   log.LvlInfo,
+				BackupDB: true,
+			},
+		},
+		{
+			"Test gossamer --db-cache",
+			[]string{"config", "db-cache"},
+			[]interface{}{testCfgFile.Name(), "header=16,body=64"},
+			dot.GlobalConfig{
+				Name:     testCfg.Global.Name,
+				ID:       testCfg.Global.ID,
+				BasePath: testCfg.Global.BasePath,
+				LogLvl:   log.LvlInfo,
+				DBCache:  "header=16,body=64",
+			},
+		},
+		{
+			"Test gossamer --extrinsic-index",
+			[]string{"config", "extrinsic-index"},
+			[]interface{}{testCfgFile.Name(), "true"},
+			dot.GlobalConfig{
+				Name:           testCfg.Global.Name,
+				ID:             testCfg.Global.ID,
+				BasePath:       testCfg.Global.BasePath,
+				LogLvl:         log.LvlInfo,
+				ExtrinsicIndex: true,
+			},
+		},
+		{
+			"Test gossamer --no-telemetry",
+			[]string{"config", "no-telemetry"},
+			[]interface{}{testCfgFile.Name(), "true"},
+			dot.GlobalConfig{
+				Name:        testCfg.Global.Name,
+				ID:          testCfg.Global.ID,
+				BasePath:    testCfg.Global.BasePath,
+				LogLvl:      log.LvlInfo,
+				NoTelemetry: true,
+			},
+		},
 		{
 			"Test gossamer --roles",
 			[]string{"config", "roles"},
@@ -293,6 +362,51 @@ func TestCoreConfigFromFlags(t *testing.T) {
 				WasmInterpreter:  gssmr.DefaultWasmInterpreter,
 			},
 		},
+		{
+			"Test gossamer --execution --execution-other",
+			[]string{"config", "roles", "execution", "execution-other"},
+			[]interface{}{testCfgFile.Name(), "0", "wasm", "native-else-wasm"},
+			dot.CoreConfig{
+				Roles:           0,
+				WasmInterpreter: gssmr.DefaultWasmInterpreter,
+				Execution: runtime.ExecutionStrategies{
+					Syncing:           runtime.ExecutionWasm,
+					ImportBlock:       runtime.ExecutionWasm,
+					BlockConstruction: runtime.ExecutionWasm,
+					Other:             runtime.ExecutionNativeElseWasm,
+				},
+			},
+		},
+		{
+			"Test gossamer --host-stats",
+			[]string{"config", "roles", "host-stats"},
+			[]interface{}{testCfgFile.Name(), "0", true},
+			dot.CoreConfig{
+				Roles:           0,
+				WasmInterpreter: gssmr.DefaultWasmInterpreter,
+				HostStats:       true,
+			},
+		},
+		{
+			"Test gossamer --skip-empty-blocks",
+			[]string{"config", "roles", "skip-empty-blocks"},
+			[]interface{}{testCfgFile.Name(), "0", true},
+			dot.CoreConfig{
+				Roles:           0,
+				WasmInterpreter: gssmr.DefaultWasmInterpreter,
+				SkipEmptyBlocks: true,
+			},
+		},
+		{
+			"Test gossamer --halt-at-block",
+			[]string{"config", "roles", "halt-at-block"},
+			[]interface{}{testCfgFile.Name(), "0", "100"},
+			dot.CoreConfig{
+				Roles:           0,
+				WasmInterpreter: gssmr.DefaultWasmInterpreter,
+				HaltAtBlock:     100,
+			},
+		},
 	}
 
 	for _, c := range testcases {
@@ -307,6 +421,42 @@ func TestCoreConfigFromFlags(t *testing.T) {
 	}
 }
 
+// TestCoreConfigFromFlags_InvalidExecution tests createDotConfig with invalid execution strategies
+func TestCoreConfigFromFlags_InvalidExecution(t *testing.T) {
+	testCfg, testCfgFile := newTestConfigWithFile(t)
+	require.NotNil(t, testCfg)
+	require.NotNil(t, testCfgFile)
+
+	defer utils.RemoveTestDir(t)
+
+	testcases := []struct {
+		description string
+		flags       []string
+		values      []interface{}
+	}{
+		{
+			"Test gossamer --execution invalid",
+			[]string{"config", "execution"},
+			[]interface{}{testCfgFile.Name(), "both"},
+		},
+		{
+			"Test gossamer --execution-syncing invalid",
+			[]string{"config", "execution-syncing"},
+			[]interface{}{testCfgFile.Name(), "interpreted"},
+		},
+	}
+
+	for _, c := range testcases {
+		c := c // bypass scopelint false positive
+		t.Run(c.description, func(t *testing.T) {
+			ctx, err := newTestContext(c.description, c.flags, c.values)
+			require.Nil(t, err)
+			_, err = createDotConfig(ctx)
+			require.Error(t, err)
+		})
+	}
+}
+
 // TestNetworkConfigFromFlags tests createDotNetworkConfig using relevant network flags
 func TestNetworkConfigFromFlags(t *testing.T) {
 	testCfg, testCfgFile := newTestConfigWithFile(t)
@@ -420,11 +570,13 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "rpc"},
 			[]interface{}{testCfgFile.Name(), "true"},
 			dot.RPCConfig{
-				Enabled: true,
-				Port:    testCfg.RPC.Port,
-				Host:    testCfg.RPC.Host,
-				Modules: testCfg.RPC.Modules,
-				WSPort:  testCfg.RPC.WSPort,
+				Enabled:            true,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
 			},
 		},
 		{
@@ -432,11 +584,13 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "rpc"},
 			[]interface{}{testCfgFile.Name(), "false"},
 			dot.RPCConfig{
-				Enabled: false,
-				Port:    testCfg.RPC.Port,
-				Host:    testCfg.RPC.Host,
-				Modules: testCfg.RPC.Modules,
-				WSPort:  testCfg.RPC.WSPort,
+				Enabled:            false,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
 			},
 		},
 		{
@@ -444,11 +598,13 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "rpchost"},
 			[]interface{}{testCfgFile.Name(), "testhost"}, // rpc must be enabled
 			dot.RPCConfig{
-				Enabled: testCfg.RPC.Enabled,
-				Port:    testCfg.RPC.Port,
-				Host:    "testhost",
-				Modules: testCfg.RPC.Modules,
-				WSPort:  testCfg.RPC.WSPort,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               "testhost",
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
 			},
 		},
 		{
@@ -456,11 +612,13 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "rpcport"},
 			[]interface{}{testCfgFile.Name(), "5678"}, // rpc must be enabled
 			dot.RPCConfig{
-				Enabled: testCfg.RPC.Enabled,
-				Port:    5678,
-				Host:    testCfg.RPC.Host,
-				Modules: testCfg.RPC.Modules,
-				WSPort:  testCfg.RPC.WSPort,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               5678,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
 			},
 		},
 		{
@@ -468,11 +626,13 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "rpcmods"},
 			[]interface{}{testCfgFile.Name(), "mod1,mod2"}, // rpc must be enabled
 			dot.RPCConfig{
-				Enabled: testCfg.RPC.Enabled,
-				Port:    testCfg.RPC.Port,
-				Host:    testCfg.RPC.Host,
-				Modules: []string{"mod1", "mod2"},
-				WSPort:  testCfg.RPC.WSPort,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            []string{"mod1", "mod2"},
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
 			},
 		},
 		{
@@ -480,12 +640,14 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "wsport"},
 			[]interface{}{testCfgFile.Name(), "7070"},
 			dot.RPCConfig{
-				Enabled:   testCfg.RPC.Enabled,
-				Port:      testCfg.RPC.Port,
-				Host:      testCfg.RPC.Host,
-				Modules:   testCfg.RPC.Modules,
-				WSPort:    7070,
-				WSEnabled: false,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             7070,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+				WSEnabled:          false,
 			},
 		},
 		{
@@ -493,12 +655,14 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "ws"},
 			[]interface{}{testCfgFile.Name(), false},
 			dot.RPCConfig{
-				Enabled:   testCfg.RPC.Enabled,
-				Port:      testCfg.RPC.Port,
-				Host:      testCfg.RPC.Host,
-				Modules:   testCfg.RPC.Modules,
-				WSPort:    testCfg.RPC.WSPort,
-				WSEnabled: false,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+				WSEnabled:          false,
 			},
 		},
 		{
@@ -506,12 +670,72 @@ func TestRPCConfigFromFlags(t *testing.T) {
 			[]string{"config", "ws"},
 			[]interface{}{testCfgFile.Name(), true},
 			dot.RPCConfig{
-				Enabled:   testCfg.RPC.Enabled,
-				Port:      testCfg.RPC.Port,
-				Host:      testCfg.RPC.Host,
-				Modules:   testCfg.RPC.Modules,
-				WSPort:    testCfg.RPC.WSPort,
-				WSEnabled: true,
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+				WSEnabled:          true,
+			},
+		},
+		{
+			"Test gossamer --wsmaxsubs",
+			[]string{"config", "wsmaxsubs"},
+			[]interface{}{testCfgFile.Name(), "16"},
+			dot.RPCConfig{
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: 16,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+			},
+		},
+		{
+			"Test gossamer --rpccalltimeout",
+			[]string{"config", "rpccalltimeout"},
+			[]interface{}{testCfgFile.Name(), "30"},
+			dot.RPCConfig{
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        30,
+			},
+		},
+		{
+			"Test gossamer --finalizedonly",
+			[]string{"config", "finalizedonly"},
+			[]interface{}{testCfgFile.Name(), "true"},
+			dot.RPCConfig{
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+				FinalizedOnly:      true,
+			},
+		},
+		{
+			"Test gossamer --rpc-slow-query",
+			[]string{"config", "rpc-slow-query"},
+			[]interface{}{testCfgFile.Name(), "500"},
+			dot.RPCConfig{
+				Enabled:            testCfg.RPC.Enabled,
+				Port:               testCfg.RPC.Port,
+				Host:               testCfg.RPC.Host,
+				Modules:            testCfg.RPC.Modules,
+				WSPort:             testCfg.RPC.WSPort,
+				WSMaxSubscriptions: testCfg.RPC.WSMaxSubscriptions,
+				CallTimeout:        testCfg.RPC.CallTimeout,
+				SlowQuery:          500,
 			},
 		},
 	}
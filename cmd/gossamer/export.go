@@ -85,6 +85,14 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		ID:       dcfg.Global.ID,
 		BasePath: dcfg.Global.BasePath,
 		LogLvl:   dcfg.Global.LogLvl.String(),
+		BackupDB: dcfg.Global.BackupDB,
+		DBCache:  dcfg.Global.DBCache,
+
+		ExtrinsicIndex:  dcfg.Global.ExtrinsicIndex,
+		TracingEndpoint: dcfg.Global.TracingEndpoint,
+		Hasher:          dcfg.Global.Hasher,
+		TelemetryURLs:   dcfg.Global.TelemetryURLs,
+		NoTelemetry:     dcfg.Global.NoTelemetry,
 	}
 
 	cfg.Log = ctoml.LogConfig{
@@ -99,7 +107,8 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 	}
 
 	cfg.Init = ctoml.InitConfig{
-		GenesisRaw: dcfg.Init.GenesisRaw,
+		GenesisRaw:      dcfg.Init.GenesisRaw,
+		GenesisChecksum: dcfg.Init.GenesisChecksum,
 	}
 
 	cfg.Account = ctoml.AccountConfig{
@@ -113,6 +122,22 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		GrandpaAuthority: dcfg.Core.GrandpaAuthority,
 		BabeThreshold:    babeThresholdToString(dcfg.Core.BabeThreshold),
 		SlotDuration:     dcfg.Core.SlotDuration,
+		HeapPages:        dcfg.Core.HeapPages,
+		TxBanDuration:    dcfg.Core.TxBanDuration,
+		PreferLocalTxs:   dcfg.Core.PreferLocalTxs,
+		NoPropagate:      dcfg.Core.NoPropagate,
+		LocalTxsOnly:     dcfg.Core.LocalTxsOnly,
+		PoolLimit:        dcfg.Core.PoolLimit,
+		PoolKBytes:       dcfg.Core.PoolKBytes,
+		HeadersOnly:      dcfg.Core.HeadersOnly,
+		HostStats:        dcfg.Core.HostStats,
+		SkipEmptyBlocks:  dcfg.Core.SkipEmptyBlocks,
+		HaltAtBlock:      dcfg.Core.HaltAtBlock,
+
+		ExecutionSyncing:           string(dcfg.Core.Execution.Syncing),
+		ExecutionImportBlock:       string(dcfg.Core.Execution.ImportBlock),
+		ExecutionBlockConstruction: string(dcfg.Core.Execution.BlockConstruction),
+		ExecutionOther:             string(dcfg.Core.Execution.Other),
 	}
 
 	cfg.Network = ctoml.NetworkConfig{
@@ -121,15 +146,32 @@ func dotConfigToToml(dcfg *dot.Config) *ctoml.Config {
 		ProtocolID:  dcfg.Network.ProtocolID,
 		NoBootstrap: dcfg.Network.NoBootstrap,
 		NoMDNS:      dcfg.Network.NoMDNS,
+		DisplayName: dcfg.Network.DisplayName,
+		Location:    dcfg.Network.Location,
+		Contact:     dcfg.Network.Contact,
+		ListenAddrs: dcfg.Network.ListenAddrs,
+
+		MaxSyncPeers:        dcfg.Network.MaxSyncPeers,
+		MaxTransactionPeers: dcfg.Network.MaxTransactionPeers,
+		MaxLightPeers:       dcfg.Network.MaxLightPeers,
 	}
 
 	cfg.RPC = ctoml.RPCConfig{
-		Enabled:   dcfg.RPC.Enabled,
-		Port:      dcfg.RPC.Port,
-		Host:      dcfg.RPC.Host,
-		Modules:   dcfg.RPC.Modules,
-		WSPort:    dcfg.RPC.WSPort,
-		WSEnabled: dcfg.RPC.WSEnabled,
+		Enabled:            dcfg.RPC.Enabled,
+		Port:               dcfg.RPC.Port,
+		Host:               dcfg.RPC.Host,
+		Modules:            dcfg.RPC.Modules,
+		WSPort:             dcfg.RPC.WSPort,
+		WSEnabled:          dcfg.RPC.WSEnabled,
+		WSMaxSubscriptions: dcfg.RPC.WSMaxSubscriptions,
+		CallTimeout:        dcfg.RPC.CallTimeout,
+		FinalizedOnly:      dcfg.RPC.FinalizedOnly,
+		LogRequests:        dcfg.RPC.LogRequests,
+		AuthToken:          dcfg.RPC.AuthToken,
+		UnsafeMethods:      dcfg.RPC.UnsafeMethods,
+		Tracing:            dcfg.RPC.Tracing,
+		Unsafe:             dcfg.RPC.Unsafe,
+		SlowQuery:          dcfg.RPC.SlowQuery,
 	}
 
 	return cfg
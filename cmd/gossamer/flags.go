@@ -43,6 +43,81 @@ var (
 		Name:  "roles",
 		Usage: "Roles of the gossamer node",
 	}
+	// HeapPagesFlag number of wasm heap pages for the runtime
+	HeapPagesFlag = cli.UintFlag{
+		Name:  "heappages",
+		Usage: "Number of 64KiB wasm heap pages for the runtime, overrides the value in storage",
+	}
+	// PreferLocalTxsFlag prefers transactions submitted via RPC over transactions received from peers
+	PreferLocalTxsFlag = cli.BoolFlag{
+		Name:  "preferlocaltxs",
+		Usage: "Prefer transactions submitted via RPC over transactions received from peers",
+	}
+	// NoPropagateFlag disables gossiping transactions submitted via RPC
+	NoPropagateFlag = cli.BoolFlag{
+		Name:  "no-propagate",
+		Usage: "Don't gossip transactions submitted via RPC to peers",
+	}
+	// LocalTxsOnlyFlag ignores transactions gossiped by peers
+	LocalTxsOnlyFlag = cli.BoolFlag{
+		Name:  "local-txs-only",
+		Usage: "Ignore transactions gossiped by peers, only accept transactions submitted via RPC",
+	}
+	// PoolLimitFlag maximum number of transactions in the transaction pool
+	PoolLimitFlag = cli.UintFlag{
+		Name:  "pool-limit",
+		Usage: "Maximum number of transactions in the transaction pool (default: 8192)",
+	}
+	// PoolKBytesFlag maximum total size of the transactions in the transaction pool
+	PoolKBytesFlag = cli.UintFlag{
+		Name:  "pool-kbytes",
+		Usage: "Maximum total size in kB of the transactions in the transaction pool (default: 20480)",
+	}
+	// HeadersOnlyFlag stores only block headers and justifications
+	HeadersOnlyFlag = cli.BoolFlag{
+		Name:  "headers-only",
+		Usage: "Store only block headers and justifications, block bodies are fetched from peers when requested via RPC",
+	}
+	// HostStatsFlag enables runtime host function call statistics
+	HostStatsFlag = cli.BoolFlag{
+		Name:  "host-stats",
+		Usage: "Count and time runtime host function calls, served as RPC metrics and logged per block at the runtime debug level",
+	}
+	// SkipEmptyBlocksFlag only authors blocks when there are ready transactions
+	SkipEmptyBlocksFlag = cli.BoolFlag{
+		Name:  "skip-empty-blocks",
+		Usage: "Only author blocks when there are ready transactions, intended for development chains",
+	}
+	// HaltAtBlockFlag stops the node once the block with the given number is imported
+	HaltAtBlockFlag = cli.Uint64Flag{
+		Name:  "halt-at-block",
+		Usage: "Stop importing and authoring blocks past the given block number, and shut down once it's imported",
+	}
+	// ExecutionFlag sets the runtime execution strategy of every context
+	ExecutionFlag = cli.StringFlag{
+		Name:  "execution",
+		Usage: "Runtime execution strategy for all contexts: wasm, native or native-else-wasm",
+	}
+	// ExecutionSyncingFlag sets the runtime execution strategy used when syncing
+	ExecutionSyncingFlag = cli.StringFlag{
+		Name:  "execution-syncing",
+		Usage: "Runtime execution strategy used when syncing, overrides --execution",
+	}
+	// ExecutionImportBlockFlag sets the runtime execution strategy used when importing blocks
+	ExecutionImportBlockFlag = cli.StringFlag{
+		Name:  "execution-import-block",
+		Usage: "Runtime execution strategy used when importing blocks, overrides --execution",
+	}
+	// ExecutionBlockConstructionFlag sets the runtime execution strategy used when authoring blocks
+	ExecutionBlockConstructionFlag = cli.StringFlag{
+		Name:  "execution-block-construction",
+		Usage: "Runtime execution strategy used when authoring blocks, overrides --execution",
+	}
+	// ExecutionOtherFlag sets the runtime execution strategy used for all other calls
+	ExecutionOtherFlag = cli.StringFlag{
+		Name:  "execution-other",
+		Usage: "Runtime execution strategy used for all other calls, eg. RPC queries, overrides --execution",
+	}
 )
 
 // Global node configuration flags
@@ -61,7 +136,7 @@ var (
 	// ChainFlag is chain id used to load default configuration for specified chain
 	ChainFlag = cli.StringFlag{
 		Name:  "chain",
-		Usage: "Chain id used to load default configuration for specified chain",
+		Usage: "Chain id used to load default configuration and genesis for specified chain, eg. gssmr, ksmcc or kusama",
 	}
 	// ConfigFlag TOML configuration file
 	ConfigFlag = cli.StringFlag{
@@ -73,6 +148,36 @@ var (
 		Name:  "basepath",
 		Usage: "Data directory for the node",
 	}
+	// BackupDBFlag backs up the database before it's migrated to a newer layout
+	BackupDBFlag = cli.BoolFlag{
+		Name:  "backupdb",
+		Usage: "Back up the database before migrating it to a newer layout",
+	}
+	// DBCacheFlag cache sizes of the database column families
+	DBCacheFlag = cli.StringFlag{
+		Name:  "db-cache",
+		Usage: "Cache sizes in MiB of the database column families, eg. header=8,body=32,justification=4",
+	}
+	// ExtrinsicIndexFlag indexes the extrinsics of the finalized blocks by their hash
+	ExtrinsicIndexFlag = cli.BoolFlag{
+		Name:  "extrinsic-index",
+		Usage: "Index the extrinsics of all finalized blocks by their hash, uses disk space that grows with the chain",
+	}
+	// TracingEndpointFlag OTLP/HTTP collector endpoint that spans are exported to
+	TracingEndpointFlag = cli.StringFlag{
+		Name:  "tracing-endpoint",
+		Usage: "OpenTelemetry collector (OTLP/HTTP) endpoint to export spans to, eg. http://localhost:4318",
+	}
+	// TelemetryURLFlag telemetry server to report to, may be repeated
+	TelemetryURLFlag = cli.StringSliceFlag{
+		Name:  "telemetry-url",
+		Usage: "Telemetry server URL and verbosity to report to, eg. 'wss://telemetry.polkadot.io/submit/ 0'. May be repeated, overrides the chain spec's telemetry endpoints",
+	}
+	// NoTelemetryFlag disables reporting to the telemetry servers
+	NoTelemetryFlag = cli.BoolFlag{
+		Name:  "no-telemetry",
+		Usage: "Disable reporting to the telemetry servers, including those of the chain spec",
+	}
 	CPUProfFlag = cli.StringFlag{
 		Name:  "cpuprof",
 		Usage: "File to write CPU profile to",
@@ -88,7 +193,12 @@ var (
 	// GenesisRawFlag Path to raw genesis JSON file
 	GenesisRawFlag = cli.StringFlag{
 		Name:  "genesis-raw",
-		Usage: "Path to raw genesis JSON file",
+		Usage: "Path or http(s) URL of raw genesis JSON file, which may be gzip compressed",
+	}
+	// GenesisChecksumFlag Expected sha256 checksum of a remote raw genesis JSON file
+	GenesisChecksumFlag = cli.StringFlag{
+		Name:  "genesis-checksum",
+		Usage: "Hex encoded sha256 checksum of the raw genesis JSON file, required if --genesis-raw is a URL",
 	}
 )
 
@@ -102,6 +212,14 @@ var (
 		Name:  "genesis",
 		Usage: "Path to human-readable genesis JSON file",
 	}
+	OutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Path to file to write genesis JSON to, instead of stdout",
+	}
+	LightSyncStateFlag = cli.BoolFlag{
+		Name:  "light-sync-state",
+		Usage: "Embed the light sync state of the latest finalized block in the genesis JSON built from the database",
+	}
 )
 
 // Network service configuration flags
@@ -111,6 +229,11 @@ var (
 		Name:  "port",
 		Usage: "Set network listening port",
 	}
+	// ListenAddrFlag network listening multiaddress, may be repeated
+	ListenAddrFlag = cli.StringSliceFlag{
+		Name:  "listen-addr",
+		Usage: "Listen on the given multiaddress, eg. /ip6/::/tcp/7001 or /ip4/0.0.0.0/tcp/7002/ws. May be repeated, DNS multiaddresses are only advertised",
+	}
 	// BootnodesFlag Network service settings
 	BootnodesFlag = cli.StringFlag{
 		Name:  "bootnodes",
@@ -131,6 +254,21 @@ var (
 		Name:  "nomdns",
 		Usage: "Disables network mDNS discovery",
 	}
+	// DisplayNameFlag operator display name of the node
+	DisplayNameFlag = cli.StringFlag{
+		Name:  "display-name",
+		Usage: "Display name of the node, signed with the node key and shown by system_nodeIdentity",
+	}
+	// LocationFlag operator location label of the node
+	LocationFlag = cli.StringFlag{
+		Name:  "location",
+		Usage: "Location label of the node, signed with the node key",
+	}
+	// ContactFlag operator contact label of the node
+	ContactFlag = cli.StringFlag{
+		Name:  "contact",
+		Usage: "Operator contact label of the node, signed with the node key",
+	}
 )
 
 // RPC service configuration flags
@@ -163,6 +301,31 @@ var (
 		Name:  "ws",
 		Usage: "Enable the websockets server",
 	}
+	// WSMaxSubscriptionsFlag maximum number of subscriptions per websocket connection
+	WSMaxSubscriptionsFlag = cli.UintFlag{
+		Name:  "wsmaxsubs",
+		Usage: "Maximum number of subscriptions per websockets connection",
+	}
+	// RPCCallTimeoutFlag maximum duration of a runtime call made via RPC
+	RPCCallTimeoutFlag = cli.UintFlag{
+		Name:  "rpccalltimeout",
+		Usage: "Maximum duration in seconds of a runtime call made via RPC",
+	}
+	// FinalizedOnlyFlag resolves RPC queries and subscriptions against the finalized head
+	FinalizedOnlyFlag = cli.BoolFlag{
+		Name:  "finalizedonly",
+		Usage: "Resolve RPC queries and subscriptions against the finalized head instead of the best block",
+	}
+	// RPCUnsafeFlag enables RPC methods that modify the chain state
+	RPCUnsafeFlag = cli.BoolFlag{
+		Name:  "rpc-unsafe",
+		Usage: "Enable RPC methods that modify the chain state, eg. state_insertStorage (dev chains only)",
+	}
+	// RPCSlowQueryFlag duration above which RPC requests are logged
+	RPCSlowQueryFlag = cli.UintFlag{
+		Name:  "rpc-slow-query",
+		Usage: "Log RPC requests that take longer than the given duration in milliseconds, along with their params",
+	}
 )
 
 // Account management flags
@@ -209,6 +372,77 @@ var (
 	}
 )
 
+// Watch-only flags
+var (
+	// WatchEndpointFlag websockets endpoint of the node to watch
+	WatchEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "Websockets endpoint of the node to watch",
+		Value: defaultWatchEndpoint,
+	}
+	// WatchIntervalFlag interval at which the peer count and pool size are refreshed
+	WatchIntervalFlag = cli.UintFlag{
+		Name:  "interval",
+		Usage: "Interval in seconds at which the peer count and transaction pool size are refreshed",
+		Value: 2,
+	}
+)
+
+// TryRuntime-only flags
+var (
+	// TryRuntimeWasmFlag path to the runtime to test
+	TryRuntimeWasmFlag = cli.StringFlag{
+		Name:  "wasm",
+		Usage: "Path to the wasm runtime to execute the upgrade with",
+	}
+	// TryRuntimeEndpointFlag HTTP RPC endpoint of the node to load state from
+	TryRuntimeEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "HTTP RPC endpoint of a live node to load state from (if not set, state is loaded from the database)",
+	}
+	// TryRuntimeInterpreterFlag wasm interpreter used to execute the runtime
+	TryRuntimeInterpreterFlag = cli.StringFlag{
+		Name:  "wasm-interpreter",
+		Usage: "Wasm interpreter used to execute the runtime; wasmer or wasmtime",
+		Value: "wasmer",
+	}
+)
+
+// CheckDB-only flags
+var (
+	// CheckDBStateSampleFlag interval between blocks whose state root is recomputed
+	CheckDBStateSampleFlag = cli.UintFlag{
+		Name:  "state-sample",
+		Usage: "Recompute the state root of every nth block; 0 disables state root checks",
+		Value: 1000,
+	}
+)
+
+// key subcommand flags
+var (
+	// KeystorePathFlag keystore directory listed by the key list subcommand
+	KeystorePathFlag = cli.StringFlag{
+		Name:  "keystore-path",
+		Usage: "Keystore directory (if not set, the keystore in the node's base path is used)",
+	}
+	// KeyTypeFlag key type of the key inserted by the key insert subcommand
+	KeyTypeFlag = cli.StringFlag{
+		Name:  "key-type",
+		Usage: "Key type of the key, it's only unlocked as a key of this type; babe, gran or acco",
+		Value: "babe",
+	}
+	// SchemeFlag key scheme of the key inserted by the key insert subcommand
+	SchemeFlag = cli.StringFlag{
+		Name:  "scheme",
+		Usage: "Key scheme of the key; sr25519, ed25519 or secp256k1 (if not set, the scheme of the key type is used)",
+	}
+	// SuriFlag secret URI of the key inserted by the key insert subcommand
+	SuriFlag = cli.StringFlag{
+		Name:  "suri",
+		Usage: "Secret URI of the key; a development account, eg. //Alice, or a hex encoded seed",
+	}
+)
+
 // flag sets that are shared by multiple commands
 var (
 	// GlobalFlags are flags that are valid for use with the root command and all subcommands
@@ -230,11 +464,44 @@ var (
 
 		// network flags
 		PortFlag,
+		ListenAddrFlag,
 		BootnodesFlag,
 		ProtocolFlag,
 		RolesFlag,
 		NoBootstrapFlag,
 		NoMDNSFlag,
+		DisplayNameFlag,
+		LocationFlag,
+		ContactFlag,
+
+		// database flags
+		BackupDBFlag,
+		DBCacheFlag,
+		ExtrinsicIndexFlag,
+
+		// tracing flags
+		TracingEndpointFlag,
+
+		// telemetry flags
+		TelemetryURLFlag,
+		NoTelemetryFlag,
+
+		// core flags
+		HeapPagesFlag,
+		PreferLocalTxsFlag,
+		NoPropagateFlag,
+		LocalTxsOnlyFlag,
+		PoolLimitFlag,
+		PoolKBytesFlag,
+		HeadersOnlyFlag,
+		HostStatsFlag,
+		SkipEmptyBlocksFlag,
+		HaltAtBlockFlag,
+		ExecutionFlag,
+		ExecutionSyncingFlag,
+		ExecutionImportBlockFlag,
+		ExecutionBlockConstructionFlag,
+		ExecutionOtherFlag,
 
 		// rpc flags
 		RPCEnabledFlag,
@@ -243,6 +510,11 @@ var (
 		RPCModulesFlag,
 		WSEnabledFlag,
 		WSPortFlag,
+		WSMaxSubscriptionsFlag,
+		RPCCallTimeoutFlag,
+		FinalizedOnlyFlag,
+		RPCUnsafeFlag,
+		RPCSlowQueryFlag,
 	}
 )
 
@@ -255,19 +527,61 @@ var (
 	InitFlags = append([]cli.Flag{
 		ForceFlag,
 		GenesisRawFlag,
+		GenesisChecksumFlag,
 	}, GlobalFlags...)
 
 	BuildSpecFlags = append([]cli.Flag{
 		RawFlag,
 		GenesisFlag,
+		OutputFlag,
+		LightSyncStateFlag,
 	}, GlobalFlags...)
 
 	// ExportFlags are the flags that are valid for use with the export subcommand
 	ExportFlags = append([]cli.Flag{
 		ForceFlag,
 		GenesisRawFlag,
+		GenesisChecksumFlag,
 	}, append(GlobalFlags, StartupFlags...)...)
 
+	// WatchFlags are flags that are valid for use with the watch subcommand
+	WatchFlags = append([]cli.Flag{
+		WatchEndpointFlag,
+		WatchIntervalFlag,
+	}, GlobalFlags...)
+
+	// TryRuntimeFlags are flags that are valid for use with the try-runtime subcommand
+	TryRuntimeFlags = append([]cli.Flag{
+		TryRuntimeWasmFlag,
+		TryRuntimeEndpointFlag,
+		TryRuntimeInterpreterFlag,
+	}, GlobalFlags...)
+
+	// CheckDBFlags are flags that are valid for use with the check-db subcommand
+	CheckDBFlags = append([]cli.Flag{
+		CheckDBStateSampleFlag,
+	}, GlobalFlags...)
+
+	// KeyListFlags are flags that are valid for use with the key list subcommand
+	KeyListFlags = append([]cli.Flag{
+		KeystorePathFlag,
+	}, GlobalFlags...)
+
+	// KeyInspectFlags are flags that are valid for use with the key inspect subcommand
+	KeyInspectFlags = []cli.Flag{
+		Ed25519Flag,
+		Sr25519Flag,
+		Secp256k1Flag,
+	}
+
+	// KeyInsertFlags are flags that are valid for use with the key insert subcommand
+	KeyInsertFlags = append([]cli.Flag{
+		KeyTypeFlag,
+		SchemeFlag,
+		SuriFlag,
+		PasswordFlag,
+	}, GlobalFlags...)
+
 	// AccountFlags are flags that are valid for use with the account subcommand
 	AccountFlags = append([]cli.Flag{
 		GenerateFlag,
@@ -0,0 +1,353 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/urfave/cli"
+)
+
+// keyInfo describes a key without its private part
+type keyInfo struct {
+	file      string
+	keyTypes  []keystore.Name
+	scheme    crypto.KeyType
+	publicKey crypto.PublicKey
+}
+
+func (k *keyInfo) String() string {
+	types := make([]string, len(k.keyTypes))
+	for i, t := range k.keyTypes {
+		types[i] = string(t)
+	}
+
+	return fmt.Sprintf("key types: %s\nscheme: %s\npublic key: %s\nSS58 address: %s",
+		strings.Join(types, ","), k.scheme, k.publicKey.Hex(), k.publicKey.Address())
+}
+
+// keyListAction is the action for the "key list" subcommand, it prints the keys stored in the keystore
+func keyListAction(ctx *cli.Context) error {
+	dir := ctx.String(KeystorePathFlag.Name)
+	if dir == "" {
+		cfg, err := createDotConfig(ctx)
+		if err != nil {
+			return err
+		}
+
+		dir, err = utils.KeystoreDir(cfg.Global.BasePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	keys, err := listKeys(dir)
+	if err != nil {
+		return err
+	}
+
+	for i, k := range keys {
+		fmt.Printf("[%d] %s\n%s\n\n", i, k.file, k)
+	}
+
+	return nil
+}
+
+// keyInspectAction is the action for the "key inspect" subcommand, it prints the public key and address of
+// a secret URI or keystore file
+func keyInspectAction(ctx *cli.Context) error {
+	arg := ctx.Args().First()
+	if arg == "" {
+		return errors.New("no secret URI or key file provided")
+	}
+
+	var (
+		info *keyInfo
+		err  error
+	)
+
+	if utils.PathExists(arg) {
+		info, err = readKeyFile(arg)
+	} else {
+		info, err = inspectSURI(arg, keySchemeFromFlags(ctx))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(info)
+	return nil
+}
+
+// keyInsertAction is the action for the "key insert" subcommand, it writes the key of a secret URI into the
+// keystore of the node's base path, so that keys can be provisioned without enabling the unsafe author_insertKey RPC
+func keyInsertAction(ctx *cli.Context) error {
+	suri := ctx.String(SuriFlag.Name)
+	if suri == "" {
+		return errors.New("no secret URI provided, set it with --suri")
+	}
+
+	cfg, err := createDotConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	fp, idx, err := insertKey(cfg.Global.BasePath, ctx.String(KeyTypeFlag.Name), ctx.String(SchemeFlag.Name), suri,
+		getKeystorePassword(ctx))
+	if err != nil {
+		return err
+	}
+
+	logger.Info("inserted key, unlock it with --unlock when starting the node", "file", fp, "index", idx)
+	return nil
+}
+
+// insertKey writes the key of a secret URI into the keystore of the given base path as a key of the given key type,
+// encrypted with the given password. It returns the path of the key file and the index to unlock the key with.
+func insertKey(basepath, keyType, scheme, suri string, password []byte) (string, int, error) {
+	if scheme == "" {
+		scheme = keystore.DetermineKeyType(keyType)
+	}
+
+	switch scheme {
+	case crypto.Sr25519Type, crypto.Ed25519Type, crypto.Secp256k1Type:
+	default:
+		return "", 0, fmt.Errorf("invalid key scheme %q", scheme)
+	}
+
+	if !containsKeyType(keyTypesForScheme(scheme), keystore.Name(keyType)) {
+		return "", 0, fmt.Errorf("%s keys are not used as %q keys", scheme, keyType)
+	}
+
+	kp, err := keypairFromSURI(suri, scheme)
+	if err != nil {
+		return "", 0, err
+	}
+
+	fp, err := keystore.InsertKeypair(keystore.Name(keyType), kp, basepath, password)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	files, err := utils.KeystoreFiles(basepath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	for i, f := range files {
+		if f == filepath.Base(fp) {
+			return fp, i, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("key file %s not found in keystore", fp)
+}
+
+func containsKeyType(names []keystore.Name, name keystore.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keySchemeFromFlags returns the key scheme set by the --ed25519, --sr25519 and --secp256k1 flags
+func keySchemeFromFlags(ctx *cli.Context) crypto.KeyType {
+	switch {
+	case ctx.Bool(Ed25519Flag.Name):
+		return crypto.Ed25519Type
+	case ctx.Bool(Secp256k1Flag.Name):
+		return crypto.Secp256k1Type
+	default:
+		return crypto.Sr25519Type
+	}
+}
+
+// keyTypesForScheme returns the keystores that a key of the given scheme is loaded into when it's unlocked
+func keyTypesForScheme(scheme crypto.KeyType) []keystore.Name {
+	switch scheme {
+	case crypto.Sr25519Type:
+		return []keystore.Name{keystore.AccoName, keystore.BabeName}
+	case crypto.Ed25519Type:
+		return []keystore.Name{keystore.AccoName, keystore.GranName}
+	default:
+		return []keystore.Name{keystore.AccoName}
+	}
+}
+
+// listKeys returns the keys stored in the given keystore directory
+func listKeys(dir string) ([]*keyInfo, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore directory: %w", err)
+	}
+
+	keys := []*keyInfo{}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != ".key" {
+			continue
+		}
+
+		info, err := readKeyFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, info)
+	}
+
+	return keys, nil
+}
+
+// readKeyFile reads the public key of a keystore file, the private key is encrypted and isn't read
+func readKeyFile(fp string) (*keyInfo, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return nil, err
+	}
+
+	ks := new(keystore.EncryptedKeystore)
+	err = json.Unmarshal(data, ks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key file %s: %w", fp, err)
+	}
+
+	enc, err := common.HexToBytes(ks.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in key file %s: %w", fp, err)
+	}
+
+	var pub crypto.PublicKey
+	switch ks.Type {
+	case crypto.Sr25519Type:
+		pub, err = sr25519.NewPublicKey(enc)
+	case crypto.Ed25519Type:
+		pub, err = ed25519.NewPublicKey(enc)
+	case crypto.Secp256k1Type:
+		pub = new(secp256k1.PublicKey)
+		err = pub.Decode(enc)
+	default:
+		return nil, fmt.Errorf("invalid key type %q in key file %s", ks.Type, fp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in key file %s: %w", fp, err)
+	}
+
+	keyTypes := keyTypesForScheme(ks.Type)
+	if ks.KeyType != "" {
+		keyTypes = []keystore.Name{ks.KeyType}
+	}
+
+	return &keyInfo{
+		file:      filepath.Base(fp),
+		keyTypes:  keyTypes,
+		scheme:    ks.Type,
+		publicKey: pub,
+	}, nil
+}
+
+// inspectSURI returns the key for the given secret URI. Supported secret URIs are the development accounts,
+// eg. //Alice, and hex encoded secret seeds.
+func inspectSURI(suri string, scheme crypto.KeyType) (*keyInfo, error) {
+	kp, err := keypairFromSURI(suri, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyInfo{
+		keyTypes:  keyTypesForScheme(scheme),
+		scheme:    scheme,
+		publicKey: kp.Public(),
+	}, nil
+}
+
+func keypairFromSURI(suri string, scheme crypto.KeyType) (crypto.Keypair, error) {
+	if strings.HasPrefix(suri, "0x") {
+		seed, err := common.HexToBytes(suri)
+		if err != nil {
+			return nil, err
+		}
+
+		switch scheme {
+		case crypto.Sr25519Type:
+			return sr25519.NewKeypairFromSeed(seed)
+		case crypto.Ed25519Type:
+			return ed25519.NewKeypairFromSeed(seed)
+		default:
+			return secp256k1.NewKeypairFromPrivateKeyString(suri)
+		}
+	}
+
+	if !strings.HasPrefix(suri, "//") || strings.Contains(suri[2:], "/") {
+		return nil, fmt.Errorf("unsupported secret URI %q; only development accounts and hex encoded seeds are supported", suri)
+	}
+
+	var (
+		kr  keystore.Keyring
+		err error
+	)
+
+	switch scheme {
+	case crypto.Sr25519Type:
+		kr, err = keystore.NewSr25519Keyring()
+	case crypto.Ed25519Type:
+		kr, err = keystore.NewEd25519Keyring()
+	default:
+		return nil, fmt.Errorf("no development accounts for key scheme %s", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(suri[2:]) {
+	case "alice":
+		return kr.Alice(), nil
+	case "bob":
+		return kr.Bob(), nil
+	case "charlie":
+		return kr.Charlie(), nil
+	case "dave":
+		return kr.Dave(), nil
+	case "eve":
+		return kr.Eve(), nil
+	case "ferdie":
+		return kr.Ferdie(), nil
+	case "george":
+		return kr.George(), nil
+	case "heather":
+		return kr.Heather(), nil
+	case "ian":
+		return kr.Ian(), nil
+	default:
+		return nil, fmt.Errorf("unknown development account %q", suri)
+	}
+}
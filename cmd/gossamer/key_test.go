@@ -0,0 +1,103 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListKeys(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	kp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	_, err = keystore.GenerateKeypair(crypto.Ed25519Type, kp, testDir, []byte("1234"))
+	require.NoError(t, err)
+
+	dir, err := utils.KeystoreDir(testDir)
+	require.NoError(t, err)
+
+	keys, err := listKeys(dir)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, crypto.Ed25519Type, keys[0].scheme)
+	require.Equal(t, []keystore.Name{keystore.AccoName, keystore.GranName}, keys[0].keyTypes)
+	require.Equal(t, kp.Public().Hex(), keys[0].publicKey.Hex())
+	require.Equal(t, kp.Public().Address(), keys[0].publicKey.Address())
+}
+
+func TestInspectSURI(t *testing.T) {
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	info, err := inspectSURI("//Alice", crypto.Sr25519Type)
+	require.NoError(t, err)
+	require.Equal(t, kr.Alice().Public().Address(), info.publicKey.Address())
+
+	info, err = inspectSURI("0xfa67fb4f3a4d4e6e6e7a5b1a3ea7c8d5d4a6f1e1c2b3a4958677a8b9c0d1e2f3", crypto.Ed25519Type)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Ed25519Type, info.scheme)
+
+	_, err = inspectSURI("//Alice/stash", crypto.Sr25519Type)
+	require.Error(t, err)
+
+	_, err = inspectSURI("//Nobody", crypto.Sr25519Type)
+	require.Error(t, err)
+}
+
+func TestInsertKey(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	fp, idx, err := insertKey(testDir, "babe", crypto.Sr25519Type, "//Alice", []byte("1234"))
+	require.NoError(t, err)
+	require.Equal(t, 0, idx)
+
+	info, err := readKeyFile(fp)
+	require.NoError(t, err)
+	require.Equal(t, kr.Alice().Public().Hex(), info.publicKey.Hex())
+	require.Equal(t, []keystore.Name{keystore.BabeName}, info.keyTypes)
+
+	// the key is only unlocked into the keystore of its key type
+	ks := keystore.NewGlobalKeystore()
+	require.NoError(t, keystore.UnlockKeys(ks.Babe, testDir, "0", "1234"))
+	require.NoError(t, keystore.UnlockKeys(ks.Acco, testDir, "0", "1234"))
+	require.Equal(t, 1, ks.Babe.Size())
+	require.Equal(t, 0, ks.Acco.Size())
+
+	// the scheme defaults to the scheme of the key type
+	_, _, err = insertKey(testDir, "gran", "", "//Alice", []byte("1234"))
+	require.NoError(t, err)
+
+	_, _, err = insertKey(testDir, "gran", crypto.Sr25519Type, "//Bob", []byte("1234"))
+	require.Error(t, err)
+
+	_, _, err = insertKey(testDir, "babe", crypto.Sr25519Type, "//Alice", []byte("1234"))
+	require.Error(t, err)
+}
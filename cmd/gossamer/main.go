@@ -17,8 +17,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/ChainSafe/gossamer/dot"
 	"github.com/ChainSafe/gossamer/lib/keystore"
@@ -32,6 +35,9 @@ import (
 var app = cli.NewApp()
 var logger = log.New("pkg", "cmd")
 
+// implementationName is the name of the node implementation, returned by system_name and reported to telemetry
+const implementationName = "Gossamer"
+
 var (
 	// exportCommand defines the "export" subcommand (ie, `gossamer export`)
 	exportCommand = cli.Command{
@@ -69,6 +75,38 @@ var (
 			"\tTo import a keystore file: gossamer account --import=path/to/file\n" +
 			"\tTo list keys: gossamer account --list",
 	}
+	// keyCommand defines the "key" subcommand (ie, `gossamer key`)
+	keyCommand = cli.Command{
+		Name:     "key",
+		Usage:    "Inspect and insert the keys of the node keystore",
+		Category: "KEY",
+		Description: "The key command is used to audit and provision the keys a node will author with.\n" +
+			"\tTo list the keys of a keystore: gossamer key list --keystore-path ~/.gossamer/gssmr/keystore\n" +
+			"\tTo inspect a secret URI: gossamer key inspect //Alice\n" +
+			"\tTo inspect a keystore file: gossamer key inspect path/to/file.key\n" +
+			"\tTo insert a BABE key: gossamer key insert --key-type babe --scheme sr25519 --suri <suri>",
+		Subcommands: []cli.Command{
+			{
+				Action: FixFlagOrder(keyListAction),
+				Name:   "list",
+				Usage:  "List the key type, scheme, public key and SS58 address of each key in the keystore",
+				Flags:  KeyListFlags,
+			},
+			{
+				Action:    keyInspectAction,
+				Name:      "inspect",
+				Usage:     "Print the public key and SS58 address of a secret URI or keystore file",
+				ArgsUsage: "<suri|file>",
+				Flags:     KeyInspectFlags,
+			},
+			{
+				Action: FixFlagOrder(keyInsertAction),
+				Name:   "insert",
+				Usage:  "Write the key of a secret URI into the node's keystore",
+				Flags:  KeyInsertFlags,
+			},
+		},
+	}
 	// initCommand defines the "init" subcommand (ie, `gossamer init`)
 	buildSpecCommand = cli.Command{
 		Action:    FixFlagOrder(buildSpecAction),
@@ -79,7 +117,48 @@ var (
 		Category:  "BUILD-SPEC",
 		Description: "The build-spec command outputs current genesis JSON data.\n" +
 			"\tUsage: gossamer build-spec\n" +
-			"\tTo generate raw genesis file: gossamer build-spec --raw",
+			"\tTo generate raw genesis file: gossamer build-spec --raw\n" +
+			"\tTo write the output to a file: gossamer build-spec --raw --output genesis-raw.json",
+	}
+	// watchCommand defines the "watch" subcommand (ie, `gossamer watch`)
+	watchCommand = cli.Command{
+		Action:    FixFlagOrder(watchAction),
+		Name:      "watch",
+		Usage:     "Stream a live view of a node's chain head, peers and transaction pool",
+		ArgsUsage: "",
+		Flags:     WatchFlags,
+		Category:  "WATCH",
+		Description: "The watch command connects to a node's websockets endpoint and displays its best and finalized " +
+			"block numbers, peer count and transaction pool size as they change.\n" +
+			"\tUsage: gossamer watch --endpoint ws://localhost:8546",
+	}
+	// tryRuntimeCommand defines the "try-runtime" subcommand (ie, `gossamer try-runtime`)
+	tryRuntimeCommand = cli.Command{
+		Action:    FixFlagOrder(tryRuntimeAction),
+		Name:      "try-runtime",
+		Usage:     "Execute a runtime upgrade against existing state and report the storage changes and weight",
+		ArgsUsage: "",
+		Flags:     TryRuntimeFlags,
+		Category:  "TRY-RUNTIME",
+		Description: "The try-runtime command loads state from a stopped node's database or a live node's RPC " +
+			"endpoint, replaces the runtime code with the provided runtime and executes its migrations. " +
+			"The loaded state is not modified.\n" +
+			"\tUsage: gossamer try-runtime --wasm runtime.wasm --basepath ~/.gossamer/gssmr\n" +
+			"\tTo load state from a live node: gossamer try-runtime --wasm runtime.wasm --endpoint http://localhost:8545",
+	}
+	// checkDBCommand defines the "check-db" subcommand (ie, `gossamer check-db`)
+	checkDBCommand = cli.Command{
+		Action:    FixFlagOrder(checkDBAction),
+		Name:      "check-db",
+		Usage:     "Verify the consistency of a stopped node's block database",
+		ArgsUsage: "",
+		Flags:     CheckDBFlags,
+		Category:  "CHECK-DB",
+		Description: "The check-db command walks the chain from the best block down to genesis, verifying parent links, " +
+			"block numbers, sampled state roots and justifications, and reports the blocks that are inconsistent. " +
+			"The node must be stopped.\n" +
+			"\tUsage: gossamer check-db --basepath ~/.gossamer/gssmr\n" +
+			"\tTo recompute the state root of every block: gossamer check-db --basepath ~/.gossamer/gssmr --state-sample 1",
 	}
 )
 
@@ -90,14 +169,20 @@ func init() {
 	app.Name = "gossamer"
 	app.Usage = "Official gossamer command-line interface"
 	app.Author = "ChainSafe Systems 2019"
-	app.Version = "0.0.1"
+	app.Version = versionWithCommit()
 	app.Commands = []cli.Command{
 		exportCommand,
 		initCommand,
 		accountCommand,
+		keyCommand,
 		buildSpecCommand,
+		watchCommand,
+		tryRuntimeCommand,
+		checkDBCommand,
 	}
 	app.Flags = RootFlags
+
+	cli.VersionPrinter = printVersion
 }
 
 // main runs the cli application
@@ -200,7 +285,34 @@ func gossamerAction(ctx *cli.Context) error {
 		return err
 	}
 
+	err = keystore.LoadSessionKeys(ks, cfg.Global.BasePath)
+	if err != nil {
+		logger.Error("failed to load session keys", "error", err)
+		return err
+	}
+
 	node, err := dot.NewNode(cfg, ks, stopFunc)
+	if errors.Is(err, dot.ErrGenesisMismatch) {
+		logger.Error("failed to create node services", "error", err)
+
+		// the node is initialized with the configured genesis once its database is purged
+		if !confirmMessage("Are you sure you want to purge the database and reinitialize the node? [Y/n]") {
+			return err
+		}
+
+		err = dot.PurgeDatabase(cfg.Global.BasePath)
+		if err != nil {
+			logger.Error("failed to purge database", "error", err)
+			return err
+		}
+
+		if stopFunc != nil {
+			stopFunc()
+		}
+
+		logger.Info("purged database", "basepath", cfg.Global.BasePath)
+		return gossamerAction(ctx)
+	}
 	if err != nil {
 		logger.Error("failed to create node services", "error", err)
 		return err
@@ -296,7 +408,7 @@ func buildSpecAction(ctx *cli.Context) error {
 		// from createDotConfig because dot config should not include expanded path)
 		cfg.Global.BasePath = utils.ExpandDir(cfg.Global.BasePath)
 
-		bspec, e := dot.BuildFromDB(cfg.Global.BasePath)
+		bspec, e := dot.BuildFromDB(cfg.Global.BasePath, ctx.Bool(LightSyncStateFlag.Name))
 		if e != nil {
 			return fmt.Errorf("error building spec from database, init must be run before build-spec or run build-spec with --genesis flag Error %s", e)
 		}
@@ -315,8 +427,10 @@ func buildSpecAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	// TODO implement --output flag so that user can specify redirecting output a file.
-	//   then this can be removed (See issue #1029)
+	if output := ctx.String(OutputFlag.Name); output != "" {
+		return ioutil.WriteFile(filepath.Clean(output), res, 0600)
+	}
+
 	fmt.Printf("%s", res)
 
 	return nil
@@ -0,0 +1,86 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ChainSafe/gossamer/dot"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/urfave/cli"
+)
+
+// tryRuntimeAction is the action for the "try-runtime" subcommand
+func tryRuntimeAction(ctx *cli.Context) error {
+	lvl, err := setupLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	fp := ctx.String(TryRuntimeWasmFlag.Name)
+	if fp == "" {
+		return fmt.Errorf("--%s must be provided", TryRuntimeWasmFlag.Name)
+	}
+
+	code, err := ioutil.ReadFile(fp) //nolint
+	if err != nil {
+		return fmt.Errorf("failed to read runtime: %w", err)
+	}
+
+	tryCfg := &dot.TryRuntimeConfig{
+		Endpoint:        ctx.String(TryRuntimeEndpointFlag.Name),
+		Code:            code,
+		WasmInterpreter: ctx.String(TryRuntimeInterpreterFlag.Name),
+		LogLvl:          lvl,
+	}
+
+	if tryCfg.Endpoint == "" {
+		cfg, e := createBuildSpecConfig(ctx)
+		if e != nil {
+			return e
+		}
+
+		// the node must be stopped, since the database can only be opened by one process
+		tryCfg.BasePath = utils.ExpandDir(cfg.Global.BasePath)
+		logger.Info("loading state from database...", "basepath", tryCfg.BasePath)
+	} else {
+		logger.Info("loading state from node...", "endpoint", tryCfg.Endpoint)
+	}
+
+	res, err := dot.TryRuntime(tryCfg)
+	if err != nil {
+		return err
+	}
+
+	for _, diff := range res.Diffs {
+		switch {
+		case diff.Before == nil:
+			fmt.Printf("+ %s: %s\n", common.BytesToHex(diff.Key), common.BytesToHex(diff.After))
+		case diff.After == nil:
+			fmt.Printf("- %s: %s\n", common.BytesToHex(diff.Key), common.BytesToHex(diff.Before))
+		default:
+			fmt.Printf("~ %s: %s -> %s\n", common.BytesToHex(diff.Key), common.BytesToHex(diff.Before), common.BytesToHex(diff.After))
+		}
+	}
+
+	fmt.Printf("storage changes: %d\n", len(res.Diffs))
+	fmt.Printf("weight: %d\n", res.Weight)
+	return nil
+}
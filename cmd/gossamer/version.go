@@ -0,0 +1,60 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/urfave/cli"
+)
+
+// baseVersion is the semantic version of the gossamer node
+const baseVersion = "0.0.1"
+
+// build metadata, set at compile time using
+// -ldflags "-X main.gitCommit=<commit> -X main.buildDate=<date>" (see scripts/ci.go)
+var (
+	gitCommit = ""
+	buildDate = ""
+)
+
+// versionWithCommit returns the node version followed by the short git commit hash, if known
+func versionWithCommit() string {
+	if len(gitCommit) >= 8 {
+		return fmt.Sprintf("%s-%s", baseVersion, gitCommit[:8])
+	}
+
+	return baseVersion
+}
+
+// printVersion prints the full build information, used for the --version flag
+func printVersion(ctx *cli.Context) {
+	commit, date := gitCommit, buildDate
+	if commit == "" {
+		commit = "unknown"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+
+	_, _ = fmt.Fprintf(ctx.App.Writer, "%s version %s\n", ctx.App.Name, ctx.App.Version)
+	_, _ = fmt.Fprintf(ctx.App.Writer, "git commit: %s\n", commit)
+	_, _ = fmt.Fprintf(ctx.App.Writer, "build date: %s\n", date)
+	_, _ = fmt.Fprintf(ctx.App.Writer, "go version: %s\n", runtime.Version())
+	_, _ = fmt.Fprintf(ctx.App.Writer, "os/arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestVersionWithCommit(t *testing.T) {
+	defer func(commit string) {
+		gitCommit = commit
+	}(gitCommit)
+
+	gitCommit = ""
+	require.Equal(t, baseVersion, versionWithCommit())
+
+	gitCommit = "85da2ca0d1e6a8f3b6f0b1c2d3e4f5a6b7c8d9e0"
+	require.Equal(t, baseVersion+"-85da2ca0", versionWithCommit())
+}
+
+func TestPrintVersion(t *testing.T) {
+	defer func(commit, date string) {
+		gitCommit, buildDate = commit, date
+	}(gitCommit, buildDate)
+
+	gitCommit = "85da2ca0d1e6a8f3b6f0b1c2d3e4f5a6b7c8d9e0"
+	buildDate = "2020-10-16T00:00:00Z"
+
+	buf := &bytes.Buffer{}
+	testApp := cli.NewApp()
+	testApp.Name = "gossamer"
+	testApp.Version = versionWithCommit()
+	testApp.Writer = buf
+
+	printVersion(cli.NewContext(testApp, flag.NewFlagSet("version", 0), nil))
+
+	out := buf.String()
+	require.Contains(t, out, "gossamer version "+baseVersion+"-85da2ca0\n")
+	require.Contains(t, out, "git commit: "+gitCommit+"\n")
+	require.Contains(t, out, "build date: "+buildDate+"\n")
+	require.Contains(t, out, "go version: "+runtime.Version()+"\n")
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/gorilla/websocket"
+	"github.com/urfave/cli"
+)
+
+// request ids used by the watch command
+const (
+	watchNewHeadsReqID = iota + 1
+	watchFinalizedHeadsReqID
+	watchHealthReqID
+	watchPendingReqID
+)
+
+// defaultWatchEndpoint is the websockets endpoint the watch command connects to if none is provided
+var defaultWatchEndpoint = "ws://localhost:8546"
+
+// watchView is the chain state displayed by the watch command
+type watchView struct {
+	best      *big.Int
+	finalized *big.Int
+	peers     int
+	poolSize  int
+}
+
+// watchMessage is a json-rpc response or subscription notification received by the watch command
+type watchMessage struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// watchAction is the action for the "watch" subcommand
+func watchAction(ctx *cli.Context) error {
+	endpoint := ctx.String(WatchEndpointFlag.Name)
+	if endpoint == "" {
+		endpoint = defaultWatchEndpoint
+	}
+
+	interval := time.Duration(ctx.Uint(WatchIntervalFlag.Name)) * time.Second
+	if interval == 0 {
+		interval = time.Second * 2
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", endpoint, err)
+	}
+
+	// close the connection on interrupt, which ends the read loop below
+	interrupted := make(chan struct{})
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	go func() {
+		<-sigc
+		close(interrupted)
+		_ = conn.Close()
+	}()
+
+	var mu sync.Mutex
+	send := func(id int, method string, params string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		req := fmt.Sprintf(`{"jsonrpc":"2.0","method":"%s","params":%s,"id":%d}`, method, params, id)
+		return conn.WriteMessage(websocket.TextMessage, []byte(req))
+	}
+
+	err = send(watchNewHeadsReqID, "chain_subscribeNewHeads", "[]")
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	err = send(watchFinalizedHeadsReqID, "chain_subscribeFinalizedHeads", "[]")
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// poll the peer count and pool size, since there are no subscriptions for them
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if send(watchHealthReqID, "system_health", "{}") != nil ||
+				send(watchPendingReqID, "author_pendingExtrinsics", "{}") != nil {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("watching %s, press Ctrl+C to stop\n", endpoint)
+
+	view := new(watchView)
+	for {
+		var msg []byte
+		_, msg, err = conn.ReadMessage()
+		if err != nil {
+			fmt.Println()
+			_ = conn.Close()
+
+			select {
+			case <-interrupted:
+				return nil
+			default:
+				return fmt.Errorf("connection to %s closed: %w", endpoint, err)
+			}
+		}
+
+		if view.update(msg) {
+			fmt.Printf("\r%s", view)
+		}
+	}
+}
+
+// update updates the view with the given message, returning true if the view changed
+func (v *watchView) update(msg []byte) bool {
+	var m watchMessage
+	err := json.Unmarshal(msg, &m)
+	if err != nil {
+		logger.Debug("failed to decode message", "error", err)
+		return false
+	}
+
+	if m.Error != nil {
+		logger.Debug("received error response", "id", m.ID, "error", m.Error.Message)
+		return false
+	}
+
+	switch m.Method {
+	case "chain_newHead":
+		num, decErr := decodeWatchHeaderNumber(m.Params.Result)
+		if decErr != nil {
+			return false
+		}
+		v.best = num
+		return true
+	case "chain_finalizedHead":
+		num, decErr := decodeWatchHeaderNumber(m.Params.Result)
+		if decErr != nil {
+			return false
+		}
+		v.finalized = num
+		return true
+	}
+
+	switch m.ID {
+	case watchHealthReqID:
+		var res struct {
+			Health common.Health `json:"health"`
+		}
+		if json.Unmarshal(m.Result, &res) != nil {
+			return false
+		}
+		v.peers = res.Health.Peers
+		return true
+	case watchPendingReqID:
+		var res []json.RawMessage
+		if json.Unmarshal(m.Result, &res) != nil {
+			return false
+		}
+		v.poolSize = len(res)
+		return true
+	}
+
+	return false
+}
+
+// String returns the view as a single line
+func (v *watchView) String() string {
+	return fmt.Sprintf(
+		"best: #%s | finalized: #%s | peers: %d | pool: %d   ",
+		watchNumberString(v.best),
+		watchNumberString(v.finalized),
+		v.peers,
+		v.poolSize,
+	)
+}
+
+func watchNumberString(num *big.Int) string {
+	if num == nil {
+		return "?"
+	}
+	return num.String()
+}
+
+// decodeWatchHeaderNumber returns the number of a header received from a head subscription
+func decodeWatchHeaderNumber(header json.RawMessage) (*big.Int, error) {
+	var res struct {
+		Number string `json:"number"`
+	}
+
+	err := json.Unmarshal(header, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := common.HexToBytes(res.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
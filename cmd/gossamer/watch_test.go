@@ -0,0 +1,62 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchView_Update(t *testing.T) {
+	v := new(watchView)
+	require.Equal(t, "best: #? | finalized: #? | peers: 0 | pool: 0   ", v.String())
+
+	newHead := `{"jsonrpc":"2.0","method":"chain_newHead","params":{"result":{"number":"0x0a"},"subscription":1}}`
+	require.True(t, v.update([]byte(newHead)))
+	require.Equal(t, big.NewInt(10), v.best)
+
+	finalized := `{"jsonrpc":"2.0","method":"chain_finalizedHead","params":{"result":{"number":"0x08"},"subscription":2}}`
+	require.True(t, v.update([]byte(finalized)))
+	require.Equal(t, big.NewInt(8), v.finalized)
+
+	health := fmt.Sprintf(`{"jsonrpc":"2.0","result":{"health":{"Peers":3}},"id":%d}`, watchHealthReqID)
+	require.True(t, v.update([]byte(health)))
+	require.Equal(t, 3, v.peers)
+
+	pending := fmt.Sprintf(`{"jsonrpc":"2.0","result":["AQI=","AwQ="],"id":%d}`, watchPendingReqID)
+	require.True(t, v.update([]byte(pending)))
+	require.Equal(t, 2, v.poolSize)
+
+	require.Equal(t, "best: #10 | finalized: #8 | peers: 3 | pool: 2   ", v.String())
+}
+
+func TestWatchView_Update_Ignored(t *testing.T) {
+	v := new(watchView)
+
+	// subscription responses, errors and invalid messages don't change the view
+	subscribed := fmt.Sprintf(`{"jsonrpc":"2.0","result":1,"id":%d}`, watchNewHeadsReqID)
+	require.False(t, v.update([]byte(subscribed)))
+
+	errRes := fmt.Sprintf(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"fail"},"id":%d}`, watchHealthReqID)
+	require.False(t, v.update([]byte(errRes)))
+
+	require.False(t, v.update([]byte("not json")))
+	require.Equal(t, new(watchView), v)
+}
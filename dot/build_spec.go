@@ -40,22 +40,19 @@ func (b *BuildSpec) ToJSON() ([]byte, error) {
 		Genesis: genesis.Fields{
 			Runtime: b.genesis.GenesisFields().Runtime,
 		},
+		Properties:         b.genesis.Properties,
+		CodeSubstitutes:    b.genesis.CodeSubstitutes,
+		BadBlocks:          b.genesis.BadBlocks,
+		ForkBlocks:         b.genesis.ForkBlocks,
+		LightSyncState:     b.genesis.LightSyncState,
+		TelemetryEndpoints: b.genesis.TelemetryEndpoints,
 	}
 	return json.MarshalIndent(tmpGen, "", "    ")
 }
 
-// ToJSONRaw outputs genesis JSON in raw form
+// ToJSONRaw outputs genesis JSON in raw form, using the raw chain spec format consumed by Substrate nodes
 func (b *BuildSpec) ToJSONRaw() ([]byte, error) {
-	tmpGen := &genesis.Genesis{
-		Name:       b.genesis.Name,
-		ID:         b.genesis.ID,
-		Bootnodes:  b.genesis.Bootnodes,
-		ProtocolID: b.genesis.ProtocolID,
-		Genesis: genesis.Fields{
-			Raw: b.genesis.GenesisFields().Raw,
-		},
-	}
-	return json.MarshalIndent(tmpGen, "", "    ")
+	return b.genesis.ToJSONRaw()
 }
 
 // BuildFromGenesis builds a BuildSpec based on the human-readable genesis file at path
@@ -70,8 +67,22 @@ func BuildFromGenesis(path string, authCount int) (*BuildSpec, error) {
 	return bs, nil
 }
 
-// BuildFromDB builds a BuildSpec from the DB located at path
-func BuildFromDB(path string) (*BuildSpec, error) {
+// BuildFromDB builds a BuildSpec from the DB located at path. If lightSyncState is set, the light sync state of the
+// latest finalized block is embedded in the spec.
+func BuildFromDB(path string, lightSyncState bool) (*BuildSpec, error) {
+	stateSrvc := state.NewService(path, log.LvlCrit)
+
+	// start state service (initialize state database)
+	err := stateSrvc.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildFromState(stateSrvc, lightSyncState)
+}
+
+// buildFromState builds a BuildSpec from the given started state service
+func buildFromState(stateSrvc *state.Service, lightSyncState bool) (*BuildSpec, error) {
 	tmpGen := &genesis.Genesis{
 		Name:       "",
 		ID:         "",
@@ -84,14 +95,6 @@ func BuildFromDB(path string) (*BuildSpec, error) {
 	tmpGen.Genesis.Raw[0] = make(map[string]string)
 	tmpGen.Genesis.Runtime = make(map[string]map[string]interface{})
 
-	stateSrvc := state.NewService(path, log.LvlCrit)
-
-	// start state service (initialize state database)
-	err := stateSrvc.Start()
-	if err != nil {
-		return nil, err
-	}
-
 	// set genesis fields data
 	ent, err := stateSrvc.Storage.Entries(nil)
 	if err != nil {
@@ -118,6 +121,21 @@ func BuildFromDB(path string) (*BuildSpec, error) {
 	//tmpGen.Bootnodes = gData.(*genesis.Data).Bootnodes
 	tmpGen.ProtocolID = gData.(*genesis.Data).ProtocolID
 
+	props, err := state.LoadGenesisProperties(stateSrvc.DB())
+	if err != nil {
+		return nil, err
+	}
+	if len(props) > 0 {
+		tmpGen.Properties = props
+	}
+
+	if lightSyncState {
+		tmpGen.LightSyncState, err = newLightSyncState(stateSrvc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	bs := &BuildSpec{
 		genesis: tmpGen,
 	}
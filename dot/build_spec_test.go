@@ -17,10 +17,13 @@ package dot
 
 import (
 	"encoding/json"
+	"math/big"
 	"os"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	log "github.com/ChainSafe/log15"
 	"github.com/stretchr/testify/require"
 )
 
@@ -61,7 +64,7 @@ func TestBuildFromDB(t *testing.T) {
 	err = InitNode(cfg)
 	require.NoError(t, err)
 
-	bs, err := BuildFromDB(cfg.Global.BasePath)
+	bs, err := BuildFromDB(cfg.Global.BasePath, false)
 	require.NoError(t, err)
 	res, err := bs.ToJSON()
 	require.NoError(t, err)
@@ -71,3 +74,57 @@ func TestBuildFromDB(t *testing.T) {
 
 	require.Equal(t, expected.Genesis.Raw[0]["0x3a636f6465"], jGen.Genesis.Runtime["system"]["code"])
 }
+
+func TestBuildFromDB_LightSyncState(t *testing.T) {
+	cfg := NewTestConfig(t)
+	cfg.Init.GenesisRaw = "../chain/gssmr/genesis-raw.json"
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	bs, err := BuildFromDB(cfg.Global.BasePath, true)
+	require.NoError(t, err)
+	res, err := bs.ToJSONRaw()
+	require.NoError(t, err)
+	jGen := genesis.Genesis{}
+	err = json.Unmarshal(res, &jGen)
+	require.NoError(t, err)
+	require.NotNil(t, jGen.LightSyncState)
+
+	checkpoint, err := jGen.LightSyncState.Checkpoint()
+	require.NoError(t, err)
+
+	// the genesis block is the latest finalized block
+	require.Equal(t, big.NewInt(0), checkpoint.Header.Number)
+	require.Equal(t, uint64(1), checkpoint.Epoch.Index)
+	require.NotZero(t, checkpoint.Epoch.Duration)
+	require.NotEmpty(t, checkpoint.Epoch.Authorities)
+	require.Equal(t, uint64(0), checkpoint.GrandpaSetID)
+	require.NotEmpty(t, checkpoint.GrandpaAuthorities)
+}
+
+func TestSyncStateAPI_GenSyncSpec_Cached(t *testing.T) {
+	cfg := NewTestConfig(t)
+	cfg.Init.GenesisRaw = "../chain/gssmr/genesis-raw.json"
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	stateSrvc := state.NewService(cfg.Global.BasePath, log.LvlCrit)
+	err = stateSrvc.Start()
+	require.NoError(t, err)
+	defer stateSrvc.Stop()
+
+	api := &syncStateAPI{stateSrvc: stateSrvc}
+	spec, err := api.GenSyncSpec(true)
+	require.NoError(t, err)
+
+	// the spec of the same finalized block isn't built again
+	again, err := api.GenSyncSpec(true)
+	require.NoError(t, err)
+	require.True(t, &spec[0] == &again[0])
+
+	human, err := api.GenSyncSpec(false)
+	require.NoError(t, err)
+	require.NotEqual(t, spec, human)
+}
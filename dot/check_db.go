@@ -0,0 +1,186 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/grandpa"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	database "github.com/ChainSafe/chaindb"
+	log "github.com/ChainSafe/log15"
+)
+
+// CheckDBConfig configures a consistency check of a stopped node's database
+type CheckDBConfig struct {
+	// BasePath the data directory of the node
+	BasePath string
+	// StateSampleInterval the state root of every StateSampleInterval-th block is recomputed from the stored trie,
+	// state roots aren't checked if it is 0
+	StateSampleInterval uint64
+}
+
+// Inconsistency is a problem found in the block database
+type Inconsistency struct {
+	Hash   common.Hash
+	Number *big.Int
+	Reason string
+}
+
+// String formats the Inconsistency as a string
+func (i *Inconsistency) String() string {
+	return fmt.Sprintf("block %s (#%s): %s", i.Hash, i.Number, i.Reason)
+}
+
+// CheckDBResult is the outcome of a database consistency check
+type CheckDBResult struct {
+	// Blocks the number of blocks checked
+	Blocks uint64
+	// StateRoots the number of state roots recomputed
+	StateRoots uint64
+	// StateUnavailable the number of sampled blocks whose state was not in the database, eg. because it was pruned
+	StateUnavailable uint64
+	// Justifications the number of justifications checked
+	Justifications uint64
+	// Inconsistencies the problems found, from the best block down to genesis
+	Inconsistencies []*Inconsistency
+}
+
+// CheckDB walks the chain of a stopped node's database from the best block down to genesis, verifying parent
+// links, number continuity and the number to hash index, the state roots of sampled blocks against the root
+// recomputed from the stored trie, and the stored justifications.
+func CheckDB(cfg *CheckDBConfig) (*CheckDBResult, error) {
+	stateSrvc := state.NewService(cfg.BasePath, log.LvlCrit)
+
+	err := stateSrvc.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = stateSrvc.Stop()
+	}()
+
+	return checkDB(stateSrvc, cfg.StateSampleInterval)
+}
+
+func checkDB(stateSrvc *state.Service, stateSampleInterval uint64) (*CheckDBResult, error) {
+	bs := stateSrvc.Block
+	res := &CheckDBResult{}
+
+	header, err := bs.BestBlockHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best block: %w", err)
+	}
+
+	for {
+		hash := header.Hash()
+		report := func(format string, args ...interface{}) {
+			res.Inconsistencies = append(res.Inconsistencies, &Inconsistency{
+				Hash:   hash,
+				Number: header.Number,
+				Reason: fmt.Sprintf(format, args...),
+			})
+		}
+
+		res.Blocks++
+
+		indexed, err := bs.GetBlockHash(header.Number)
+		if err != nil {
+			report("block number is not indexed: %s", err)
+		} else if *indexed != hash {
+			report("block number is indexed to %s", indexed)
+		}
+
+		if has, _ := bs.HasBlockBody(hash); !has {
+			report("block body is missing")
+		}
+
+		if stateSampleInterval != 0 && header.Number.Uint64()%stateSampleInterval == 0 {
+			root, err := loadStateRoot(stateSrvc.DB(), header.StateRoot, stateSrvc.Storage.Hasher())
+			switch {
+			case errors.Is(err, database.ErrKeyNotFound):
+				res.StateUnavailable++
+			case err != nil:
+				res.StateRoots++
+				report("failed to load state %s: %s", header.StateRoot, err)
+			case root != header.StateRoot:
+				res.StateRoots++
+				report("state root is %s, but the stored state hashes to %s", header.StateRoot, root)
+			default:
+				res.StateRoots++
+			}
+		}
+
+		if has, _ := bs.HasJustification(hash); has {
+			res.Justifications++
+
+			just, err := bs.GetJustification(hash)
+			if err != nil {
+				report("failed to get justification: %s", err)
+			} else if err = grandpa.VerifyStoredJustification(just, hash, header.Number.Uint64()); err != nil {
+				report("%s", err)
+			}
+		}
+
+		if header.Number.Sign() == 0 {
+			if hash != bs.GenesisHash() {
+				report("block number 0 is not the genesis block %s", bs.GenesisHash())
+			}
+			return res, nil
+		}
+
+		parent, err := bs.GetHeader(header.ParentHash)
+		if err != nil {
+			// the chain can't be walked any further
+			report("failed to get parent %s: %s", header.ParentHash, err)
+			return res, nil
+		}
+
+		if parent.Hash() != header.ParentHash {
+			report("parent header stored as %s hashes to %s", header.ParentHash, parent.Hash())
+		}
+
+		if expected := new(big.Int).Sub(header.Number, big.NewInt(1)); parent.Number.Cmp(expected) != 0 {
+			report("parent %s has number %s, expected %s", header.ParentHash, parent.Number, expected)
+
+			// don't follow parent links that don't lead towards genesis, they could loop
+			if parent.Number.Cmp(header.Number) >= 0 {
+				return res, nil
+			}
+		}
+
+		header = parent
+	}
+}
+
+// loadStateRoot loads the trie with the given root from the database and returns its root recomputed with the
+// given hash function
+func loadStateRoot(db database.Database, root common.Hash, hash common.HashFunc) (common.Hash, error) {
+	t := trie.NewEmptyTrieWithHasher(hash)
+	err := state.LoadTrie(db, t, root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return t.Hash()
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDB(t *testing.T) {
+	cfg := NewTestConfig(t)
+	cfg.Init.GenesisRaw = "../chain/gssmr/genesis-raw.json"
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	checkCfg := &CheckDBConfig{
+		BasePath:            cfg.Global.BasePath,
+		StateSampleInterval: 1,
+	}
+
+	res, err := CheckDB(checkCfg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.Blocks)
+	require.Equal(t, uint64(1), res.StateRoots)
+	require.Empty(t, res.Inconsistencies)
+
+	// add a block whose state root doesn't match its stored state and that has an invalid justification
+	stateSrvc := state.NewService(cfg.Global.BasePath, log.LvlCrit)
+	require.NoError(t, stateSrvc.Start())
+
+	genesisHeader, err := stateSrvc.Block.BestBlockHeader()
+	require.NoError(t, err)
+
+	enc, err := stateSrvc.DB().Get(genesisHeader.StateRoot[:])
+	require.NoError(t, err)
+
+	badRoot := common.Hash{0x1}
+	require.NoError(t, stateSrvc.DB().Put(badRoot[:], enc))
+
+	header1, err := types.NewHeader(genesisHeader.Hash(), big.NewInt(1), badRoot, common.Hash{}, types.NewEmptyDigest())
+	require.NoError(t, err)
+	err = stateSrvc.Block.AddBlock(&types.Block{Header: header1, Body: types.NewBody([]byte{})})
+	require.NoError(t, err)
+	require.NoError(t, stateSrvc.Block.SetJustification(header1.Hash(), []byte{1, 2, 3}))
+
+	header2, err := types.NewHeader(header1.Hash(), big.NewInt(2), genesisHeader.StateRoot, common.Hash{}, types.NewEmptyDigest())
+	require.NoError(t, err)
+	err = stateSrvc.Block.AddBlock(&types.Block{Header: header2, Body: types.NewBody([]byte{})})
+	require.NoError(t, err)
+	require.NoError(t, stateSrvc.Stop())
+
+	res, err = CheckDB(checkCfg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), res.Blocks)
+	require.Equal(t, uint64(3), res.StateRoots)
+	require.Equal(t, uint64(1), res.Justifications)
+	require.Len(t, res.Inconsistencies, 2)
+
+	for _, inc := range res.Inconsistencies {
+		require.Equal(t, header1.Hash(), inc.Hash)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// runtimeCode returns the runtime code of the best block. This is the code substitute of the latest canonical
+// block with a substitute if the on-chain code hasn't been upgraded since that block, otherwise the on-chain code.
+func runtimeCode(st *state.Service) ([]byte, error) {
+	code, err := st.Storage.LoadCode(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := state.LoadCodeSubstitutes(st.DB())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(subs) == 0 {
+		return code, nil
+	}
+
+	best, err := st.Block.BestBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	codeHash, err := common.Blake2bHash(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var substitute []byte
+	var number int64 = -1
+	for hash, sub := range subs {
+		header, err := st.Block.GetHeader(hash)
+		if err != nil {
+			// the block hasn't been imported yet
+			continue
+		}
+
+		if header.Number.Cmp(best.Number) > 0 || header.Number.Int64() <= number {
+			continue
+		}
+
+		canonical, err := st.Block.GetHeaderByNumber(header.Number)
+		if err != nil || canonical.Hash() != hash {
+			continue
+		}
+
+		// the trie of the block may not be in memory
+		subCodeHash, err := st.Storage.LoadCodeHash(&header.StateRoot)
+		if err != nil {
+			if _, err = st.Storage.LoadFromDB(header.StateRoot); err != nil {
+				logger.Warn("cannot load state of substituted block", "block", hash, "error", err)
+				continue
+			}
+
+			subCodeHash, err = st.Storage.LoadCodeHash(&header.StateRoot)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if subCodeHash != codeHash {
+			// the runtime was upgraded since the substituted block
+			continue
+		}
+
+		substitute = sub
+		number = header.Number.Int64()
+	}
+
+	if substitute != nil {
+		logger.Info("using runtime code substitute", "block", number)
+		return substitute, nil
+	}
+
+	return code, nil
+}
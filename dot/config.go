@@ -23,6 +23,7 @@ import (
 	"github.com/ChainSafe/gossamer/chain/gssmr"
 	"github.com/ChainSafe/gossamer/chain/ksmcc"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	log "github.com/ChainSafe/log15"
 )
 
@@ -47,6 +48,24 @@ type GlobalConfig struct {
 	ID       string
 	BasePath string
 	LogLvl   log.Lvl
+	BackupDB bool
+	// ExtrinsicIndex indexes the extrinsics of all the finalized blocks by their hash, at the cost of disk space
+	ExtrinsicIndex bool
+	// DBCache is the cache sizes of the database column families, eg. header=8,body=32 (in MiB)
+	DBCache string
+	// TracingEndpoint is the OTLP/HTTP collector that spans are exported to, tracing is disabled if empty
+	TracingEndpoint string
+	// Hasher is the name of the hash function used for trie and header hashing, which is stored in the database
+	// when it's initialized. A new database uses blake2b if it's empty, an existing one the hash function it stores.
+	Hasher string
+	// TelemetryURLs are the telemetry servers to report to, in the "URL VERBOSITY" format. The chain spec's
+	// telemetry endpoints are used if empty.
+	TelemetryURLs []string
+	// NoTelemetry disables reporting to the telemetry servers, including those of the chain spec
+	NoTelemetry bool
+	// InMemory keeps the node's database in memory, the node is initialized from genesis when it's created and
+	// its state is lost when it stops. It's meant for nodes embedded in tests and tools, see NewNode.
+	InMemory bool
 }
 
 // LogConfig represents the log levels for individual packages
@@ -64,6 +83,10 @@ type LogConfig struct {
 // InitConfig is the configuration for the node initialization
 type InitConfig struct {
 	GenesisRaw string
+	// GenesisChecksum is the hex encoded sha256 checksum of the genesis, checked if GenesisRaw is a URL
+	GenesisChecksum string
+	// Genesis is the raw genesis JSON, used instead of the GenesisRaw file if set, see GenesisPreset
+	Genesis []byte
 	// TestFirstEpoch determines whether to use test data for the first epoch
 	// If set to false, node initialization will load the babe configuration from the runtime to use as first epoch data
 	TestFirstEpoch bool
@@ -82,6 +105,15 @@ type NetworkConfig struct {
 	ProtocolID  string
 	NoBootstrap bool
 	NoMDNS      bool
+	DisplayName string // operator labels, signed with the network key
+	Location    string
+	Contact     string
+	ListenAddrs []string // multiaddresses to listen on, DNS multiaddresses are only advertised
+	// MaxSyncPeers, MaxTransactionPeers and MaxLightPeers limit the peer sets of the sync and transactions
+	// protocols and of light clients, the network package defaults are used if 0
+	MaxSyncPeers        int
+	MaxTransactionPeers int
+	MaxLightPeers       int
 }
 
 // CoreConfig is to marshal/unmarshal toml core config vars
@@ -92,16 +124,37 @@ type CoreConfig struct {
 	BabeThreshold    *big.Int
 	SlotDuration     uint64
 	WasmInterpreter  string
+	HeapPages        uint32
+	TxBanDuration    uint32 // seconds, transaction.DefaultBanDuration is used if 0
+	PreferLocalTxs   bool
+	NoPropagate      bool                        // don't gossip transactions submitted via RPC
+	LocalTxsOnly     bool                        // ignore transactions gossiped by peers
+	PoolLimit        uint32                      // maximum number of transactions, transaction.DefaultPoolMaxSize is used if 0
+	PoolKBytes       uint32                      // maximum total size of transactions in kB, transaction.DefaultPoolMaxBytes is used if 0
+	HeadersOnly      bool                        // store only headers and justifications, fetch block bodies from peers on demand
+	HostStats        bool                        // count and time runtime host function calls
+	SkipEmptyBlocks  bool                        // only author blocks when there are ready transactions
+	HaltAtBlock      uint64                      // stop the node once this block is imported, disabled if 0
+	Execution        runtime.ExecutionStrategies // unset contexts use runtime.DefaultExecutionStrategy
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
 type RPCConfig struct {
-	Enabled   bool
-	Port      uint32
-	Host      string
-	Modules   []string
-	WSPort    uint32
-	WSEnabled bool
+	Enabled            bool
+	Port               uint32
+	Host               string
+	Modules            []string
+	WSPort             uint32
+	WSEnabled          bool
+	WSMaxSubscriptions uint32
+	CallTimeout        uint32
+	FinalizedOnly      bool
+	LogRequests        bool
+	AuthToken          string
+	UnsafeMethods      []string
+	Tracing            bool
+	Unsafe             bool   // enable methods that modify the chain state, eg. state_insertStorage
+	SlowQuery          uint32 // milliseconds, requests that take longer are logged, 0 to disable
 }
 
 // String will return the json representation for a Config
@@ -160,10 +213,12 @@ func GssmrConfig() *Config {
 			NoMDNS:      gssmr.DefaultNoMDNS,
 		},
 		RPC: RPCConfig{
-			Port:    gssmr.DefaultRPCHTTPPort,
-			Host:    gssmr.DefaultRPCHTTPHost,
-			Modules: gssmr.DefaultRPCModules,
-			WSPort:  gssmr.DefaultRPCWSPort,
+			Port:               gssmr.DefaultRPCHTTPPort,
+			Host:               gssmr.DefaultRPCHTTPHost,
+			Modules:            gssmr.DefaultRPCModules,
+			WSPort:             gssmr.DefaultRPCWSPort,
+			WSMaxSubscriptions: gssmr.DefaultRPCWSMaxSubscriptions,
+			CallTimeout:        gssmr.DefaultRPCCallTimeout,
 		},
 		System: types.SystemInfo{
 			NodeName:         gssmr.DefaultName,
@@ -210,10 +265,12 @@ func KsmccConfig() *Config {
 			NoMDNS:      ksmcc.DefaultNoMDNS,
 		},
 		RPC: RPCConfig{
-			Port:    ksmcc.DefaultRPCHTTPPort,
-			Host:    ksmcc.DefaultRPCHTTPHost,
-			Modules: ksmcc.DefaultRPCModules,
-			WSPort:  ksmcc.DefaultRPCWSPort,
+			Port:               ksmcc.DefaultRPCHTTPPort,
+			Host:               ksmcc.DefaultRPCHTTPHost,
+			Modules:            ksmcc.DefaultRPCModules,
+			WSPort:             ksmcc.DefaultRPCWSPort,
+			WSMaxSubscriptions: ksmcc.DefaultRPCWSMaxSubscriptions,
+			CallTimeout:        ksmcc.DefaultRPCCallTimeout,
 		},
 		System: types.SystemInfo{
 			NodeName:         ksmcc.DefaultName,
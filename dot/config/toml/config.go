@@ -33,6 +33,14 @@ type GlobalConfig struct {
 	ID       string `toml:"id,omitempty"`
 	BasePath string `toml:"basepath,omitempty"`
 	LogLvl   string `toml:"log,omitempty"`
+	BackupDB bool   `toml:"backup-db,omitempty"`
+	DBCache  string `toml:"db-cache,omitempty"`
+
+	ExtrinsicIndex  bool     `toml:"extrinsic-index,omitempty"`
+	TracingEndpoint string   `toml:"tracing-endpoint,omitempty"`
+	Hasher          string   `toml:"hasher,omitempty"`
+	TelemetryURLs   []string `toml:"telemetry-urls,omitempty"`
+	NoTelemetry     bool     `toml:"no-telemetry,omitempty"`
 }
 
 // LogConfig represents the log levels for individual packages
@@ -49,7 +57,8 @@ type LogConfig struct {
 
 // InitConfig is the configuration for the node initialization
 type InitConfig struct {
-	GenesisRaw string `toml:"genesis-raw,omitempty"`
+	GenesisRaw      string `toml:"genesis-raw,omitempty"`
+	GenesisChecksum string `toml:"genesis-checksum,omitempty"`
 }
 
 // AccountConfig is to marshal/unmarshal account config vars
@@ -65,6 +74,14 @@ type NetworkConfig struct {
 	ProtocolID  string   `toml:"protocol,omitempty"`
 	NoBootstrap bool     `toml:"nobootstrap,omitempty"`
 	NoMDNS      bool     `toml:"nomdns,omitempty"`
+	DisplayName string   `toml:"display-name,omitempty"`
+	Location    string   `toml:"location,omitempty"`
+	Contact     string   `toml:"contact,omitempty"`
+	ListenAddrs []string `toml:"listen-addrs,omitempty"`
+
+	MaxSyncPeers        int `toml:"max-sync-peers,omitempty"`
+	MaxTransactionPeers int `toml:"max-transaction-peers,omitempty"`
+	MaxLightPeers       int `toml:"max-light-peers,omitempty"`
 }
 
 // CoreConfig is to marshal/unmarshal toml core config vars
@@ -75,14 +92,41 @@ type CoreConfig struct {
 	BabeThreshold    string `toml:"babe-threshold,omitempty"`
 	SlotDuration     uint64 `toml:"slot-duration,omitempty"`
 	WasmInterpreter  string `toml:"wasm-interpreter,omitempty"`
+	HeapPages        uint32 `toml:"heap-pages,omitempty"`
+	TxBanDuration    uint32 `toml:"tx-ban-duration,omitempty"`
+	PreferLocalTxs   bool   `toml:"prefer-local-txs,omitempty"`
+	NoPropagate      bool   `toml:"no-propagate,omitempty"`
+	LocalTxsOnly     bool   `toml:"local-txs-only,omitempty"`
+	PoolLimit        uint32 `toml:"pool-limit,omitempty"`
+	PoolKBytes       uint32 `toml:"pool-kbytes,omitempty"`
+	HeadersOnly      bool   `toml:"headers-only,omitempty"`
+	HostStats        bool   `toml:"host-stats,omitempty"`
+	SkipEmptyBlocks  bool   `toml:"skip-empty-blocks,omitempty"`
+	HaltAtBlock      uint64 `toml:"halt-at-block,omitempty"`
+
+	// Execution sets the execution strategy of every context, the per-context values override it
+	Execution                  string `toml:"execution,omitempty"`
+	ExecutionSyncing           string `toml:"execution-syncing,omitempty"`
+	ExecutionImportBlock       string `toml:"execution-import-block,omitempty"`
+	ExecutionBlockConstruction string `toml:"execution-block-construction,omitempty"`
+	ExecutionOther             string `toml:"execution-other,omitempty"`
 }
 
 // RPCConfig is to marshal/unmarshal toml RPC config vars
 type RPCConfig struct {
-	Enabled   bool     `toml:"enabled,omitempty"`
-	Port      uint32   `toml:"port,omitempty"`
-	Host      string   `toml:"host,omitempty"`
-	Modules   []string `toml:"modules,omitempty"`
-	WSPort    uint32   `toml:"ws-port,omitempty"`
-	WSEnabled bool     `toml:"ws-enabled,omitempty"`
+	Enabled            bool     `toml:"enabled,omitempty"`
+	Port               uint32   `toml:"port,omitempty"`
+	Host               string   `toml:"host,omitempty"`
+	Modules            []string `toml:"modules,omitempty"`
+	WSPort             uint32   `toml:"ws-port,omitempty"`
+	WSEnabled          bool     `toml:"ws-enabled,omitempty"`
+	WSMaxSubscriptions uint32   `toml:"ws-max-subscriptions,omitempty"`
+	CallTimeout        uint32   `toml:"call-timeout,omitempty"`
+	FinalizedOnly      bool     `toml:"finalized-only,omitempty"`
+	LogRequests        bool     `toml:"log-requests,omitempty"`
+	AuthToken          string   `toml:"auth-token,omitempty"`
+	UnsafeMethods      []string `toml:"unsafe-methods,omitempty"`
+	Tracing            bool     `toml:"tracing,omitempty"`
+	Unsafe             bool     `toml:"unsafe,omitempty"`
+	SlowQuery          uint32   `toml:"slow-query,omitempty"`
 }
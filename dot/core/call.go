@@ -0,0 +1,327 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// DefaultRuntimeCallTimeout is the maximum duration of a runtime call made with CallRuntime if none is configured
+var DefaultRuntimeCallTimeout = time.Second * 10
+
+// maxRuntimeCalls is the maximum number of runtime calls made with CallRuntime that may execute at once.
+// A call that times out keeps its slot until it returns, which bounds the CPU that pathological calls can use.
+const maxRuntimeCalls = 4
+
+// maxCallInstanceCodes is the maximum number of runtime codes that idle call instances are kept for. The idle
+// instances are dropped once it's reached, which happens after runtime upgrades.
+const maxCallInstanceCodes = 2
+
+// callInstance is a runtime instance used for runtime calls made with CallRuntime
+type callInstance struct {
+	*wasmer.LegacyInstance
+	codeHash common.Hash
+	// reusable is false if the instance was created with a custom configuration
+	reusable bool
+}
+
+type runtimeCallResult struct {
+	res []byte
+	err error
+}
+
+// CallRuntime executes the given runtime function with the given data against the state of the block with the
+// given hash, or the best block if the hash is nil. The call is executed on a separate runtime instance and on
+// a copy of the block's state, so it can neither block the node's runtime nor change the node's state. The call
+// is interrupted and runtime.ErrExecutionTimeout is returned if it doesn't complete within the configured timeout.
+func (s *Service) CallRuntime(function string, data []byte, bhash *common.Hash) ([]byte, error) {
+	var (
+		sr  common.Hash
+		err error
+	)
+
+	if bhash == nil {
+		sr, err = s.blockState.BestBlockStateRoot()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var block *types.Block
+		block, err = s.blockState.GetBlockByHash(*bhash)
+		if err != nil {
+			return nil, err
+		}
+		sr = block.Header.StateRoot
+	}
+
+	return s.callRuntimeAt(function, sr, nil, func(*runtime.VersionAPI) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// callRuntimeAt executes the given runtime function against a copy of the state with the given root on a separate
+// runtime instance. If configure is not nil, it's applied to the instance configuration. The call data is created
+// by args, which is given the version of the runtime being called.
+func (s *Service) callRuntimeAt(function string, sr common.Hash, configure func(*wasmer.Config), args func(*runtime.VersionAPI) ([]byte, error)) ([]byte, error) {
+	select {
+	case s.runtimeCalls <- struct{}{}:
+	default:
+		return nil, ErrTooManyRuntimeCalls
+	}
+
+	code, err := s.storageState.LoadCode(&sr)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, err
+	}
+
+	ts, err := s.storageState.TrieStateCopy(&sr)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, err
+	}
+
+	rt, err := s.newCallInstance(code, ts, configure)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, err
+	}
+
+	// calls to APIs the runtime doesn't implement are rejected rather than trapping in wasm
+	ver, err := s.callVersion(rt)
+	if err == nil {
+		err = ver.CheckCall(function)
+	}
+
+	var data []byte
+	if err == nil {
+		data, err = args(ver)
+	}
+	if err != nil {
+		s.releaseCallInstance(rt, true)
+		<-s.runtimeCalls
+		return nil, err
+	}
+
+	return s.runCall(function, rt, func() ([]byte, error) {
+		return rt.Exec(function, data)
+	})
+}
+
+// DecodeSessionKeys splits the given SCALE-concatenated session public keys into their key types and public keys.
+// The layout of the keys is defined by the runtime of the best block, so it's decoded by the runtime rather than here.
+func (s *Service) DecodeSessionKeys(enc []byte) ([]*runtime.SessionKey, error) {
+	data, err := scale.Encode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := s.CallRuntime(runtime.SessionKeysDecodeSessionKeys, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.DecodeSessionKeysResult(ret)
+}
+
+// newCallInstance returns a runtime instance with the given code that executes against the given state. An idle
+// instance with the code is reused if there's one, unless configure is set, in which case a new instance is always
+// created and configured by it.
+func (s *Service) newCallInstance(code []byte, ts runtime.Storage, configure func(*wasmer.Config)) (*callInstance, error) {
+	codeHash, err := common.Blake2bHash(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if configure == nil {
+		if rt := s.idleCallInstance(codeHash); rt != nil {
+			rt.SetContext(ts)
+			return &callInstance{
+				LegacyInstance: rt,
+				codeHash:       codeHash,
+				reusable:       true,
+			}, nil
+		}
+	}
+
+	cfg := &wasmer.Config{
+		Imports: wasmer.ImportsLegacyNodeRuntime,
+	}
+	cfg.Storage = ts
+	cfg.Keystore = s.keys.Acco.(*keystore.GenericKeystore)
+	cfg.LogLvl = -1
+	cfg.NodeStorage = s.rt.NodeStorage()
+	cfg.Network = s.rt.NetworkService()
+	cfg.HeapPages = s.heapPages
+	cfg.CodeCache = s.codeCache
+
+	if configure != nil {
+		configure(cfg)
+	}
+
+	rt, err := wasmer.NewLegacyInstance(code, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &callInstance{
+		LegacyInstance: rt,
+		codeHash:       codeHash,
+		reusable:       configure == nil,
+	}, nil
+}
+
+// idleCallInstance removes and returns an idle runtime instance with the code with the given hash, or nil if
+// there's none
+func (s *Service) idleCallInstance(codeHash common.Hash) *wasmer.LegacyInstance {
+	s.callInstancesLock.Lock()
+	defer s.callInstancesLock.Unlock()
+
+	idle := s.callInstances[codeHash]
+	if len(idle) == 0 {
+		return nil
+	}
+
+	rt := idle[len(idle)-1]
+	s.callInstances[codeHash] = idle[:len(idle)-1]
+	return rt
+}
+
+// releaseCallInstance keeps the given instance for later calls if reuse is true and the instance is reusable,
+// otherwise it's stopped
+func (s *Service) releaseCallInstance(rt *callInstance, reuse bool) {
+	if !reuse || !rt.reusable {
+		rt.Stop()
+		return
+	}
+
+	// the instance keeps no reference to the state copy while it's idle
+	rt.SetContext(nil)
+
+	s.callInstancesLock.Lock()
+	defer s.callInstancesLock.Unlock()
+
+	idle, has := s.callInstances[rt.codeHash]
+	if !has && len(s.callInstances) >= maxCallInstanceCodes {
+		s.stopCallInstances()
+	}
+
+	if len(idle) >= maxRuntimeCalls {
+		rt.Stop()
+		return
+	}
+
+	s.callInstances[rt.codeHash] = append(idle, rt.LegacyInstance)
+}
+
+// stopCallInstances stops and drops all idle call instances, s.callInstancesLock must be held
+func (s *Service) stopCallInstances() {
+	for _, idle := range s.callInstances {
+		for _, rt := range idle {
+			rt.Stop()
+		}
+	}
+
+	s.callInstances = make(map[common.Hash][]*wasmer.LegacyInstance)
+}
+
+// runCall runs the given call on a separate goroutine and waits for it to complete, up to the configured timeout.
+// The instance is released and the runtime call slot is freed once the call returns.
+//
+// If the call times out, ErrExecutionTimeout is returned, the runtime call slot is freed right away, and the
+// instance is interrupted and stopped rather than reused once the call returns. wasmer can't stop a call from
+// outside of it, so an interrupted call only traps at its next memory allocation, and a call that doesn't
+// allocate, such as a tight loop in the runtime, keeps running in the background until it completes.
+func (s *Service) runCall(function string, rt *callInstance, call func() ([]byte, error)) ([]byte, error) {
+	var (
+		lock              sync.Mutex
+		done, interrupted bool
+	)
+
+	// buffered so that an abandoned call doesn't leak the goroutine once it completes
+	resCh := make(chan *runtimeCallResult, 1)
+	go func() {
+		res, execErr := call()
+
+		// an instance that trapped may be left in an inconsistent state, so it isn't reused
+		lock.Lock()
+		done = true
+		reuse := execErr == nil && !interrupted
+		timedOut := interrupted
+		lock.Unlock()
+
+		s.releaseCallInstance(rt, reuse)
+
+		// the slot of a call that timed out was already freed
+		if !timedOut {
+			<-s.runtimeCalls
+		}
+
+		resCh <- &runtimeCallResult{
+			res: res,
+			err: execErr,
+		}
+	}()
+
+	timer := time.NewTimer(s.runtimeCallTimeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-resCh:
+		return r.res, r.err
+	case <-timer.C:
+		lock.Lock()
+		if !done {
+			interrupted = true
+			rt.Interrupt()
+			<-s.runtimeCalls
+		}
+		lock.Unlock()
+
+		s.logger.Warn("runtime call timed out", "function", function, "timeout", s.runtimeCallTimeout)
+		return nil, runtime.ErrExecutionTimeout
+	}
+}
+
+// callVersion returns the version of the given instance's runtime, calling Core_version on the instance if the
+// version isn't cached
+func (s *Service) callVersion(rt *callInstance) (*runtime.VersionAPI, error) {
+	s.callVersionsLock.RLock()
+	ver, has := s.callVersions[rt.codeHash]
+	s.callVersionsLock.RUnlock()
+	if has {
+		return ver, nil
+	}
+
+	ver, err := rt.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	s.callVersionsLock.Lock()
+	defer s.callVersionsLock.Unlock()
+	s.callVersions[rt.codeHash] = ver
+	return ver, nil
+}
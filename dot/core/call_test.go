@@ -0,0 +1,206 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServiceWithCode returns a test service whose best block state contains the legacy node runtime
+func newTestServiceWithCode(t *testing.T) *Service {
+	s := NewTestService(t, nil)
+
+	fp, url := runtime.GetRuntimeVars(runtime.LEGACY_NODE_RUNTIME)
+	_, err := runtime.GetRuntimeBlob(fp, url)
+	require.NoError(t, err)
+
+	code, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+
+	ts, err := s.storageState.TrieState(nil)
+	require.NoError(t, err)
+
+	err = ts.Set(common.CodeKey, code)
+	require.NoError(t, err)
+
+	root, err := ts.Root()
+	require.NoError(t, err)
+
+	err = s.storageState.StoreTrie(root, ts)
+	require.NoError(t, err)
+
+	err = s.blockState.AddBlock(&types.Block{
+		Header: &types.Header{
+			ParentHash: s.blockState.BestBlockHash(),
+			Number:     big.NewInt(1),
+			StateRoot:  root,
+			Digest:     types.NewEmptyDigest(),
+		},
+		Body: types.NewBody([]byte{}),
+	})
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestService_CallRuntime(t *testing.T) {
+	s := newTestServiceWithCode(t)
+
+	expected, err := s.rt.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	res, err := s.CallRuntime(runtime.CoreVersion, []byte{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	bhash := s.blockState.BestBlockHash()
+	res, err = s.CallRuntime(runtime.CoreVersion, []byte{}, &bhash)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func TestService_CallRuntime_ReusesInstance(t *testing.T) {
+	s := newTestServiceWithCode(t)
+
+	sr, err := s.blockState.BestBlockStateRoot()
+	require.NoError(t, err)
+	code, err := s.storageState.LoadCode(&sr)
+	require.NoError(t, err)
+	codeHash, err := common.Blake2bHash(code)
+	require.NoError(t, err)
+
+	_, err = s.CallRuntime(runtime.CoreVersion, []byte{}, nil)
+	require.NoError(t, err)
+	require.Len(t, s.callInstances[codeHash], 1)
+	rt := s.callInstances[codeHash][0]
+
+	_, err = s.CallRuntime(runtime.CoreVersion, []byte{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*wasmer.LegacyInstance{rt}, s.callInstances[codeHash])
+}
+
+func TestService_CallRuntime_Timeout(t *testing.T) {
+	s := newTestServiceWithCode(t)
+	s.runtimeCallTimeout = time.Nanosecond
+
+	_, err := s.CallRuntime(runtime.Metadata, []byte{}, nil)
+	require.Equal(t, runtime.ErrExecutionTimeout, err)
+
+	// the interrupted instance is stopped rather than kept for later calls
+	require.Eventually(t, func() bool {
+		return len(s.runtimeCalls) == 0
+	}, time.Second*10, time.Millisecond*10)
+
+	s.callInstancesLock.Lock()
+	defer s.callInstancesLock.Unlock()
+	for _, idle := range s.callInstances {
+		require.Len(t, idle, 0)
+	}
+}
+
+func TestService_CallRuntime_StateUnchanged(t *testing.T) {
+	s := newTestServiceWithCode(t)
+
+	sr, err := s.blockState.BestBlockStateRoot()
+	require.NoError(t, err)
+
+	// initialising a block writes to storage
+	header := &types.Header{
+		ParentHash: s.blockState.BestBlockHash(),
+		Number:     big.NewInt(2),
+		Digest:     types.NewEmptyDigest(),
+	}
+	enc, err := header.Encode()
+	require.NoError(t, err)
+
+	_, err = s.CallRuntime(runtime.CoreInitializeBlock, enc, nil)
+	require.NoError(t, err)
+
+	ts, err := s.storageState.TrieState(&sr)
+	require.NoError(t, err)
+	root, err := ts.Root()
+	require.NoError(t, err)
+	require.Equal(t, sr, root)
+}
+
+func TestService_RunCall_TimeoutFreesSlot(t *testing.T) {
+	s := newTestServiceWithCode(t)
+	s.runtimeCallTimeout = time.Millisecond
+
+	sr, err := s.blockState.BestBlockStateRoot()
+	require.NoError(t, err)
+	code, err := s.storageState.LoadCode(&sr)
+	require.NoError(t, err)
+	ts, err := s.storageState.TrieStateCopy(&sr)
+	require.NoError(t, err)
+	rt, err := s.newCallInstance(code, ts, nil)
+	require.NoError(t, err)
+
+	// a call that isn't stopped by the interrupt, eg. a loop that doesn't allocate
+	s.runtimeCalls <- struct{}{}
+	unblock := make(chan struct{})
+	returned := make(chan struct{})
+	_, err = s.runCall("test", rt, func() ([]byte, error) {
+		defer close(returned)
+		<-unblock
+		return nil, nil
+	})
+	require.Equal(t, runtime.ErrExecutionTimeout, err)
+
+	// the slot is freed on timeout rather than once the call returns
+	require.Len(t, s.runtimeCalls, 0)
+
+	// and isn't freed again once it returns
+	s.runtimeCalls <- struct{}{}
+	close(unblock)
+	<-returned
+	time.Sleep(time.Millisecond * 100)
+	require.Len(t, s.runtimeCalls, 1)
+}
+
+func TestService_CallRuntime_TooManyCalls(t *testing.T) {
+	s := newTestServiceWithCode(t)
+	for i := 0; i < maxRuntimeCalls; i++ {
+		s.runtimeCalls <- struct{}{}
+	}
+
+	_, err := s.CallRuntime(runtime.CoreVersion, []byte{}, nil)
+	require.Equal(t, ErrTooManyRuntimeCalls, err)
+
+	<-s.runtimeCalls
+	_, err = s.CallRuntime(runtime.CoreVersion, []byte{}, nil)
+	require.NoError(t, err)
+}
+
+func TestService_CallRuntime_MethodUnavailable(t *testing.T) {
+	s := newTestServiceWithCode(t)
+
+	_, err := s.CallRuntime("BeefyApi_validator_set", []byte{}, nil)
+	require.True(t, errors.Is(err, runtime.ErrMethodUnavailable))
+	require.Len(t, s.runtimeCalls, 0)
+}
@@ -18,7 +18,6 @@ package core
 
 import (
 	"context"
-	"errors"
 	"math/big"
 
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -48,23 +47,15 @@ type DigestHandler struct {
 	finalizedID byte
 
 	// BABE changes
-	babeScheduledChange *babeChange
-	babeForcedChange    *babeChange
-	babePause           *pause
-	babeResume          *resume
-	babeAuths           []*types.Authority // saved in case of pause
+	babePause  *pause
+	babeResume *resume
+	babeAuths  []*types.Authority // saved in case of pause
 
 	// GRANDPA changes
 	grandpaScheduledChange *grandpaChange
 	grandpaForcedChange    *grandpaChange
 	grandpaPause           *pause
 	grandpaResume          *resume
-	grandpaAuths           []*types.Authority // saved in case of pause
-}
-
-type babeChange struct {
-	auths   []*types.Authority
-	atBlock *big.Int
 }
 
 type grandpaChange struct {
@@ -163,9 +154,11 @@ func (h *DigestHandler) NextGrandpaAuthorityChange() uint64 {
 
 // HandleConsensusDigest is the function used by the syncer to handle a consensus digest
 func (h *DigestHandler) HandleConsensusDigest(d *types.ConsensusDigest) error {
-	t := d.DataType()
+	if d.ConsensusEngineID == types.BabeEngineID {
+		return h.handleBABEConsensusDigest(d)
+	}
 
-	switch t {
+	switch d.DataType() {
 	case types.ScheduledChangeType:
 		return h.handleScheduledChange(d)
 	case types.ForcedChangeType:
@@ -177,7 +170,25 @@ func (h *DigestHandler) HandleConsensusDigest(d *types.ConsensusDigest) error {
 	case types.ResumeType:
 		return h.handleResume(d)
 	default:
-		return errors.New("invalid consensus digest data")
+		return ErrUnknownConsensusDigest
+	}
+}
+
+// handleBABEConsensusDigest handles a BABE consensus digest, whose types are those of Substrate's BABE
+// ConsensusLog rather than the GRANDPA ones
+func (h *DigestHandler) handleBABEConsensusDigest(d *types.ConsensusDigest) error {
+	switch d.DataType() {
+	case types.NextEpochDataType, types.NextConfigDataType:
+		// the BABE verifier applies the next epoch's data when it verifies the blocks of that epoch
+		return nil
+	case types.BABEOnDisabledType:
+		return h.handleBABEOnDisabled(d)
+	case types.PauseType:
+		return h.handlePause(d)
+	case types.ResumeType:
+		return h.handleResume(d)
+	default:
+		return ErrUnknownConsensusDigest
 	}
 }
 
@@ -235,16 +246,6 @@ func (h *DigestHandler) handleBABEChangesOnImport(header *types.Header) {
 		h.verifier.SetAuthorityChangeAtBlock(header, h.babeAuths)
 		h.babeResume = nil
 	}
-
-	fc := h.babeForcedChange
-	if fc != nil && num.Cmp(fc.atBlock) == 0 {
-		err := h.babe.SetAuthorities(fc.auths)
-		if err != nil {
-			log.Warn("error setting authorities", "error", err)
-		}
-		h.verifier.SetAuthorityChangeAtBlock(header, fc.auths)
-		h.babeForcedChange = nil
-	}
 }
 
 func (h *DigestHandler) handleBABEChangesOnFinalization(header *types.Header) {
@@ -260,25 +261,12 @@ func (h *DigestHandler) handleBABEChangesOnFinalization(header *types.Header) {
 		h.verifier.SetAuthorityChangeAtBlock(header, []*types.Authority{})
 		h.babePause = nil
 	}
-
-	sc := h.babeScheduledChange
-	if sc != nil && num.Cmp(sc.atBlock) == 0 {
-		err := h.babe.SetAuthorities(sc.auths)
-		if err != nil {
-			log.Warn("error setting authorities", "error", err)
-		}
-		h.verifier.SetAuthorityChangeAtBlock(header, sc.auths)
-		h.babeScheduledChange = nil
-	}
-
-	// if blocks get finalized before forced change takes place, disregard it
-	h.babeForcedChange = nil
 }
 
 func (h *DigestHandler) handleGrandpaChangesOnImport(num *big.Int) {
 	resume := h.grandpaResume
 	if resume != nil && num.Cmp(resume.atBlock) == 0 {
-		h.grandpa.UpdateAuthorities(h.grandpaAuths)
+		h.grandpa.ResumeVoting()
 		h.grandpaResume = nil
 	}
 
@@ -292,9 +280,8 @@ func (h *DigestHandler) handleGrandpaChangesOnImport(num *big.Int) {
 func (h *DigestHandler) handleGrandpaChangesOnFinalization(num *big.Int) {
 	pause := h.grandpaPause
 	if pause != nil && num.Cmp(pause.atBlock) == 0 {
-		// save authority data for Resume
-		h.grandpaAuths = h.grandpa.Authorities()
-		h.grandpa.UpdateAuthorities([]*types.Authority{})
+		// stop voting, the authority set is kept for Resume
+		h.grandpa.PauseVoting()
 		h.grandpaPause = nil
 	}
 
@@ -314,44 +301,23 @@ func (h *DigestHandler) handleScheduledChange(d *types.ConsensusDigest) error {
 		return err
 	}
 
-	if d.ConsensusEngineID == types.BabeEngineID {
-		if h.babeScheduledChange != nil {
-			return errors.New("already have scheduled change scheduled")
-		}
-
-		sc := &types.BABEScheduledChange{}
-		dec, err := scale.Decode(d.Data[1:], sc)
-		if err != nil {
-			return err
-		}
-		sc = dec.(*types.BABEScheduledChange)
-
-		c, err := newBABEChange(sc.Auths, sc.Delay, curr.Number)
-		if err != nil {
-			return err
-		}
-
-		h.babeScheduledChange = c
-	} else {
-		if h.grandpaScheduledChange != nil {
-			return errors.New("already have scheduled change scheduled")
-		}
-
-		sc := &types.GrandpaScheduledChange{}
-		dec, err := scale.Decode(d.Data[1:], sc)
-		if err != nil {
-			return err
-		}
-		sc = dec.(*types.GrandpaScheduledChange)
+	if h.grandpaScheduledChange != nil {
+		return ErrScheduledChangeExists
+	}
 
-		c, err := newGrandpaChange(sc.Auths, sc.Delay, curr.Number)
-		if err != nil {
-			return err
-		}
+	sc := &types.GrandpaScheduledChange{}
+	dec, err := scale.Decode(d.Data[1:], sc)
+	if err != nil {
+		return err
+	}
+	sc = dec.(*types.GrandpaScheduledChange)
 
-		h.grandpaScheduledChange = c
+	c, err := newGrandpaChange(sc.Auths, sc.Delay, curr.Number)
+	if err != nil {
+		return err
 	}
 
+	h.grandpaScheduledChange = c
 	return nil
 }
 
@@ -361,44 +327,23 @@ func (h *DigestHandler) handleForcedChange(d *types.ConsensusDigest) error {
 		return err
 	}
 
-	if d.ConsensusEngineID == types.BabeEngineID {
-		if h.babeForcedChange != nil {
-			return errors.New("already have forced change scheduled")
-		}
-
-		fc := &types.BABEForcedChange{}
-		dec, err := scale.Decode(d.Data[1:], fc)
-		if err != nil {
-			return err
-		}
-		fc = dec.(*types.BABEForcedChange)
-
-		c, err := newBABEChange(fc.Auths, fc.Delay, curr.Number)
-		if err != nil {
-			return err
-		}
-
-		h.babeForcedChange = c
-	} else {
-		if h.grandpaForcedChange != nil {
-			return errors.New("already have forced change scheduled")
-		}
-
-		fc := &types.GrandpaForcedChange{}
-		dec, err := scale.Decode(d.Data[1:], fc)
-		if err != nil {
-			return err
-		}
-		fc = dec.(*types.GrandpaForcedChange)
+	if h.grandpaForcedChange != nil {
+		return ErrForcedChangeExists
+	}
 
-		c, err := newGrandpaChange(fc.Auths, fc.Delay, curr.Number)
-		if err != nil {
-			return err
-		}
+	fc := &types.GrandpaForcedChange{}
+	dec, err := scale.Decode(d.Data[1:], fc)
+	if err != nil {
+		return err
+	}
+	fc = dec.(*types.GrandpaForcedChange)
 
-		h.grandpaForcedChange = c
+	c, err := newGrandpaChange(fc.Auths, fc.Delay, curr.Number)
+	if err != nil {
+		return err
 	}
 
+	h.grandpaForcedChange = c
 	return nil
 }
 
@@ -410,34 +355,38 @@ func (h *DigestHandler) handleOnDisabled(d *types.ConsensusDigest) error {
 	}
 	od = dec.(*types.OnDisabled)
 
-	if d.ConsensusEngineID == types.BabeEngineID {
-		curr := h.babe.Authorities()
-		next := []*types.Authority{}
+	curr := h.grandpa.Authorities()
+	next := []*types.Authority{}
 
-		for i, auth := range curr {
-			if uint64(i) != od.ID {
-				next = append(next, auth)
-			}
+	// authorities are disabled by their index in the set, not their weight
+	for i, auth := range curr {
+		if uint64(i) != od.ID {
+			next = append(next, auth)
 		}
+	}
 
-		err := h.babe.SetAuthorities(next)
-		if err != nil {
-			return err
-		}
-	} else {
-		curr := h.grandpa.Authorities()
-		next := []*types.Authority{}
+	h.grandpa.UpdateAuthorities(next)
+	return nil
+}
 
-		for _, auth := range curr {
-			if auth.Weight != od.ID {
-				next = append(next, auth)
-			}
-		}
+func (h *DigestHandler) handleBABEOnDisabled(d *types.ConsensusDigest) error {
+	od := &types.BABEOnDisabled{}
+	dec, err := scale.Decode(d.Data[1:], od)
+	if err != nil {
+		return err
+	}
+	od = dec.(*types.BABEOnDisabled)
+
+	curr := h.babe.Authorities()
+	next := []*types.Authority{}
 
-		h.grandpa.UpdateAuthorities(next)
+	for i, auth := range curr {
+		if uint32(i) != od.ID {
+			next = append(next, auth)
+		}
 	}
 
-	return nil
+	return h.babe.SetAuthorities(next)
 }
 
 func (h *DigestHandler) handlePause(d *types.ConsensusDigest) error {
@@ -509,17 +458,3 @@ func newGrandpaChange(raw []*types.GrandpaAuthorityDataRaw, delay uint32, currBl
 		atBlock: big.NewInt(-1).Add(currBlock, d),
 	}, nil
 }
-
-func newBABEChange(raw []*types.AuthorityRaw, delay uint32, currBlock *big.Int) (*babeChange, error) {
-	auths, err := types.BABEAuthorityRawToAuthority(raw)
-	if err != nil {
-		return nil, err
-	}
-
-	d := big.NewInt(int64(delay))
-
-	return &babeChange{
-		auths:   auths,
-		atBlock: big.NewInt(-1).Add(currBlock, d),
-	}, nil
-}
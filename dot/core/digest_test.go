@@ -60,7 +60,7 @@ func newTestDigestHandler(t *testing.T, withBABE, withGrandpa bool) *DigestHandl
 	return dh
 }
 
-func TestDigestHandler_BABEScheduledChange(t *testing.T) {
+func TestDigestHandler_BABEEpochDigests(t *testing.T) {
 	handler := newTestDigestHandler(t, true, false)
 	handler.Start()
 	defer handler.Stop()
@@ -68,78 +68,34 @@ func TestDigestHandler_BABEScheduledChange(t *testing.T) {
 	kr, err := keystore.NewSr25519Keyring()
 	require.NoError(t, err)
 
-	sc := &types.BABEScheduledChange{
-		Auths: []*types.AuthorityRaw{
-			{Key: kr.Alice().Public().(*sr25519.PublicKey).AsBytes(), Weight: 0},
+	handler.babe.SetAuthorities([]*types.Authority{
+		{Key: kr.Alice().Public().(*sr25519.PublicKey), Weight: 1},
+	})
+
+	ned := &types.NextEpochData{
+		Authorities: []*types.AuthorityRaw{
+			{Key: kr.Bob().Public().(*sr25519.PublicKey).AsBytes(), Weight: 1},
 		},
-		Delay: 3,
 	}
-
-	data, err := sc.Encode()
+	nedData, err := ned.Encode()
 	require.NoError(t, err)
+	require.Equal(t, byte(1), nedData[0])
 
-	d := &types.ConsensusDigest{
-		ConsensusEngineID: types.BabeEngineID,
-		Data:              data,
-	}
-
-	err = handler.HandleConsensusDigest(d)
+	ncd := &types.NextConfigData{C1: 1, C2: 4, SecondarySlots: 1}
+	ncdData, err := ncd.Encode()
 	require.NoError(t, err)
+	require.Equal(t, []byte{3, 1}, ncdData[:2])
 
-	headers := addTestBlocksToState(t, 2, handler.blockState)
-	for _, h := range headers {
-		handler.blockState.SetFinalizedHash(h.Hash(), 0, 0)
+	// the epoch data is applied by the verifier, not by the digest handler
+	for _, data := range [][]byte{nedData, ncdData} {
+		err = handler.HandleConsensusDigest(&types.ConsensusDigest{
+			ConsensusEngineID: types.BabeEngineID,
+			Data:              data,
+		})
+		require.NoError(t, err)
 	}
 
 	auths := handler.babe.Authorities()
-	require.Nil(t, auths)
-
-	// authorities should change on start of block 3 from start
-	headers = addTestBlocksToState(t, 1, handler.blockState)
-	for _, h := range headers {
-		handler.blockState.SetFinalizedHash(h.Hash(), 0, 0)
-	}
-
-	time.Sleep(time.Millisecond * 100)
-	auths = handler.babe.Authorities()
-	require.Equal(t, 1, len(auths))
-}
-
-func TestDigestHandler_BABEForcedChange(t *testing.T) {
-	handler := newTestDigestHandler(t, true, false)
-	handler.Start()
-	defer handler.Stop()
-
-	kr, err := keystore.NewSr25519Keyring()
-	require.NoError(t, err)
-
-	fc := &types.BABEForcedChange{
-
-		Auths: []*types.AuthorityRaw{
-			{Key: kr.Alice().Public().(*sr25519.PublicKey).AsBytes(), Weight: 0},
-		},
-		Delay: 3,
-	}
-
-	data, err := fc.Encode()
-	require.NoError(t, err)
-
-	d := &types.ConsensusDigest{
-		ConsensusEngineID: types.BabeEngineID,
-		Data:              data,
-	}
-
-	err = handler.HandleConsensusDigest(d)
-	require.NoError(t, err)
-
-	addTestBlocksToState(t, 2, handler.blockState)
-	auths := handler.babe.Authorities()
-	require.Nil(t, auths)
-
-	// authorities should change on start of block 3 from start
-	addTestBlocksToState(t, 1, handler.blockState)
-	time.Sleep(time.Millisecond * 100)
-	auths = handler.babe.Authorities()
 	require.Equal(t, 1, len(auths))
 }
 
@@ -156,7 +112,7 @@ func TestDigestHandler_BABEOnDisabled(t *testing.T) {
 	})
 
 	// try with ID that doesn't exist
-	od := &types.OnDisabled{
+	od := &types.BABEOnDisabled{
 		ID: 1,
 	}
 
@@ -175,7 +131,7 @@ func TestDigestHandler_BABEOnDisabled(t *testing.T) {
 	require.Equal(t, 1, len(auths))
 
 	// try with ID that does exist
-	od = &types.OnDisabled{
+	od = &types.BABEOnDisabled{
 		ID: 0,
 	}
 
@@ -297,6 +253,34 @@ func TestDigestHandler_GrandpaScheduledChange(t *testing.T) {
 	require.Equal(t, 1, len(auths))
 }
 
+func TestDigestHandler_GrandpaScheduledChange_AlreadyScheduled(t *testing.T) {
+	handler := newTestDigestHandler(t, false, true)
+
+	kr, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+
+	sc := &types.GrandpaScheduledChange{
+		Auths: []*types.GrandpaAuthorityDataRaw{
+			{Key: kr.Alice().Public().(*ed25519.PublicKey).AsBytes(), ID: 0},
+		},
+		Delay: 3,
+	}
+
+	data, err := sc.Encode()
+	require.NoError(t, err)
+
+	d := &types.ConsensusDigest{
+		ConsensusEngineID: types.GrandpaEngineID,
+		Data:              data,
+	}
+
+	err = handler.HandleConsensusDigest(d)
+	require.NoError(t, err)
+
+	err = handler.HandleConsensusDigest(d)
+	require.Equal(t, ErrScheduledChangeExists, err)
+}
+
 func TestDigestHandler_GrandpaForcedChange(t *testing.T) {
 	handler := newTestDigestHandler(t, false, true)
 	handler.Start()
@@ -418,8 +402,13 @@ func TestDigestHandler_GrandpaPauseAndResume(t *testing.T) {
 	}
 
 	time.Sleep(time.Millisecond * 100)
-	auths := handler.grandpa.Authorities()
-	require.Equal(t, 0, len(auths))
+	fg := handler.grandpa.(*mockFinalityGadget)
+	require.True(t, fg.paused)
+	require.Equal(t, 1, len(fg.Authorities()))
+
+	// blocks continue to be imported while voting is paused
+	addTestBlocksToState(t, 2, handler.blockState)
+	require.True(t, fg.paused)
 
 	r := &types.Resume{
 		Delay: 3,
@@ -438,8 +427,8 @@ func TestDigestHandler_GrandpaPauseAndResume(t *testing.T) {
 
 	addTestBlocksToState(t, 3, handler.blockState)
 	time.Sleep(time.Millisecond * 110)
-	auths = handler.grandpa.Authorities()
-	require.Equal(t, 1, len(auths))
+	require.False(t, fg.paused)
+	require.Equal(t, 1, len(fg.Authorities()))
 }
 
 func TestNextGrandpaAuthorityChange_OneChange(t *testing.T) {
@@ -19,6 +19,8 @@ package core
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/blocktree"
 )
 
 // ErrNilBlockState is returned when BlockState is nik
@@ -45,12 +47,36 @@ var ErrNilRuntime = errors.New("cannot have nil runtime")
 // ErrNilBlockProducer is returned when trying to instantiate a block producing Service without a block producer
 var ErrNilBlockProducer = errors.New("cannot have nil BlockProducer")
 
+// ErrNotBlockProducer is returned when trying to control block production on a node that isn't a block producer
+var ErrNotBlockProducer = errors.New("node is not a block producer")
+
 // ErrNilFinalityGadget is returned when trying to instantiate a finalizing Service without a finality gadget
 var ErrNilFinalityGadget = errors.New("cannot have nil FinalityGadget")
 
 // ErrNilConsensusMessageHandler is returned when trying to instantiate a Service without a FinalityMessageHandler
 var ErrNilConsensusMessageHandler = errors.New("cannot have nil ErrNilFinalityMessageHandler")
 
+// ErrTooManyRuntimeCalls is returned when the maximum number of concurrent runtime calls are already executing
+var ErrTooManyRuntimeCalls = errors.New("too many runtime calls in progress")
+
+// ErrBlockExists is returned when adding a block that has already been imported
+var ErrBlockExists = blocktree.ErrBlockExists
+
+// ErrParentUnknown is returned when adding a block whose parent has not been imported
+var ErrParentUnknown = blocktree.ErrParentNotFound
+
+// ErrUnknownConsensusDigest is returned when a consensus digest has an unknown type
+var ErrUnknownConsensusDigest = errors.New("invalid consensus digest data")
+
+// ErrScheduledChangeExists is returned when a scheduled authority change is received while one is still pending
+var ErrScheduledChangeExists = errors.New("already have scheduled change scheduled")
+
+// ErrForcedChangeExists is returned when a forced authority change is received while one is still pending
+var ErrForcedChangeExists = errors.New("already have forced change scheduled")
+
+// ErrInvalidSessionKeys is returned when session keys aren't the concatenated public keys of each session key type
+var ErrInvalidSessionKeys = errors.New("invalid session keys")
+
 // ErrNilChannel is returned if a channel is nil
 func ErrNilChannel(s string) error {
 	return fmt.Errorf("cannot have nil channel %s", s)
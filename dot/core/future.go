@@ -0,0 +1,70 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// addFutureTransaction holds a transaction that isn't valid yet until it's revalidated by promoteFutureTransactions.
+// Only block producers hold future transactions, since other nodes don't use the transaction pool.
+func (s *Service) addFutureTransaction(ext types.Extrinsic) {
+	if !s.isBlockProducer {
+		return
+	}
+
+	hash, err := s.transactionState.AddFuture(ext)
+	if err != nil {
+		s.logger.Debug("failed to add future transaction", "hash", hash, "error", err)
+		return
+	}
+
+	s.logger.Trace("added future transaction", "hash", hash)
+}
+
+// promoteFutureTransactions revalidates the future transactions that are due at the given block with the given
+// runtime instance. Transactions that are now valid are moved to the pool, and those that still aren't are
+// revalidated after more blocks.
+func (s *Service) promoteFutureTransactions(rt runtime.LegacyInstance, number *big.Int) {
+	for _, ft := range s.transactionState.ReadyFuture(number.Uint64()) {
+		txv, err := rt.ValidateTransaction(ft.Extrinsic)
+		if errors.Is(err, runtime.ErrFutureTransaction) {
+			if !s.transactionState.RescheduleFuture(ft, number.Uint64()) {
+				s.logger.Debug("dropped future transaction", "hash", ft.Extrinsic.Hash(), "since", ft.Since)
+			}
+			continue
+		}
+		if err != nil {
+			s.logger.Trace("future transaction is invalid", "hash", ft.Extrinsic.Hash(), "error", err)
+			s.recordValidationFailure(ft.Extrinsic)
+			continue
+		}
+
+		vtx := transaction.NewValidTransaction(ft.Extrinsic, txv)
+		if _, err = s.transactionState.PromoteFuture(vtx); err != nil {
+			s.logger.Debug("failed to add future transaction to pool", "hash", ft.Extrinsic.Hash(), "error", err)
+			continue
+		}
+
+		s.logger.Trace("promoted future transaction", "hash", ft.Extrinsic.Hash())
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/services"
@@ -63,15 +64,28 @@ type StorageState interface {
 	LoadCode(root *common.Hash) ([]byte, error)
 	LoadCodeHash(root *common.Hash) (common.Hash, error)
 	TrieState(root *common.Hash) (*state.TrieState, error)
+	TrieStateCopy(root *common.Hash) (*state.TrieState, error)
+	OverlayState(root *common.Hash) (*state.OverlayState, error)
 }
 
 // TransactionState is the interface for transaction state methods
 type TransactionState interface {
 	Push(vt *transaction.ValidTransaction) (common.Hash, error)
-	AddToPool(vt *transaction.ValidTransaction) common.Hash
+	AddToPool(vt *transaction.ValidTransaction) (common.Hash, error)
+	PromoteFuture(vt *transaction.ValidTransaction) (common.Hash, error)
 	RemoveExtrinsic(ext types.Extrinsic)
+	RemoveIncludedExtrinsic(ext types.Extrinsic, block common.Hash)
 	RemoveExtrinsicFromPool(ext types.Extrinsic)
 	PendingInPool() []*transaction.ValidTransaction
+	IsBanned(ext types.Extrinsic) bool
+	RecordValidationFailure(ext types.Extrinsic) bool
+	BanStats() transaction.BanStats
+	IsLocal(ext types.Extrinsic) bool
+	RecordResubmission(result transaction.ResubmitResult)
+	AddFuture(ext types.Extrinsic) (common.Hash, error)
+	ReadyFuture(number uint64) []*transaction.FutureTransaction
+	RescheduleFuture(ft *transaction.FutureTransaction, number uint64) bool
+	NotifyBroadcast(ext types.Extrinsic)
 }
 
 // FinalityGadget is the interface that a finality gadget must implement
@@ -83,6 +97,8 @@ type FinalityGadget interface {
 	GetFinalizedChannel() <-chan FinalityMessage
 	UpdateAuthorities(ad []*types.Authority)
 	Authorities() []*types.Authority
+	PauseVoting()
+	ResumeVoting()
 }
 
 // FinalityMessage is the interface a finality message must implement
@@ -102,6 +118,7 @@ type BlockProducer interface {
 	SetRuntime(runtime.LegacyInstance) error
 	Authorities() []*types.Authority
 	SetAuthorities([]*types.Authority) error
+	Restart(*babe.RestartConfig) error
 }
 
 // Verifier is the interface for the block verifier
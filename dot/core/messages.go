@@ -17,7 +17,10 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 )
 
@@ -26,16 +29,32 @@ import (
 func (s *Service) ProcessTransactionMessage(msg *network.TransactionMessage) error {
 	s.logger.Debug("received TransactionMessage")
 
+	if s.localTxsOnly {
+		s.logger.Trace("ignoring gossiped transactions", "count", len(msg.Extrinsics))
+		return nil
+	}
+
 	// get transactions from message extrinsics
 	txs := msg.Extrinsics
 
 	for _, tx := range txs {
 		tx := tx // pin
 
+		// ignore transactions that have repeatedly failed validation
+		if s.transactionState.IsBanned(tx) {
+			s.logger.Trace("ignoring banned transaction", "hash", tx.Hash())
+			continue
+		}
+
 		// validate each transaction
 		val, err := s.rt.ValidateTransaction(tx)
+		if errors.Is(err, runtime.ErrFutureTransaction) {
+			s.addFutureTransaction(tx)
+			continue
+		}
 		if err != nil {
 			s.logger.Error("failed to validate transaction", "err", err)
+			s.recordValidationFailure(tx)
 			return err // exit
 		}
 
@@ -44,7 +63,11 @@ func (s *Service) ProcessTransactionMessage(msg *network.TransactionMessage) err
 
 		if s.isBlockProducer {
 			// push to the transaction queue of BABE session
-			hash := s.transactionState.AddToPool(vtx)
+			hash, err := s.transactionState.AddToPool(vtx)
+			if err != nil {
+				s.logger.Debug("failed to add transaction to pool", "hash", hash, "error", err)
+				continue
+			}
 			s.logger.Trace("Added transaction to queue", "hash", hash)
 		}
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
 	log "github.com/ChainSafe/log15"
@@ -110,3 +111,32 @@ func TestService_ProcessTransactionMessage(t *testing.T) {
 	tx := []byte(pending[0].Extrinsic)
 	require.Equal(t, ext, tx)
 }
+
+func TestService_ProcessTransactionMessage_BannedTransaction(t *testing.T) {
+	s := NewTestService(t, nil)
+
+	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{{1, 2, 3}}}
+
+	for i := 0; i < transaction.DefaultMaxValidationFailures; i++ {
+		err := s.ProcessTransactionMessage(msg)
+		require.Error(t, err)
+	}
+
+	// the transaction is now banned, so it is ignored without being validated
+	err := s.ProcessTransactionMessage(msg)
+	require.NoError(t, err)
+
+	stats := s.transactionState.BanStats()
+	require.Equal(t, 1, stats.Banned)
+	require.Equal(t, uint64(1), stats.Rejected)
+}
+
+func TestService_ProcessTransactionMessage_LocalTxsOnly(t *testing.T) {
+	s := NewTestService(t, &Config{LocalTxsOnly: true})
+
+	// gossiped transactions are ignored without being validated
+	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{{1, 2, 3}}}
+	err := s.ProcessTransactionMessage(msg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), s.transactionState.BanStats().Rejected)
+}
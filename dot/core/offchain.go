@@ -0,0 +1,67 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+)
+
+// runOffchainWorkers returns true if the runtime's offchain workers should be run on new best blocks. They're only
+// needed by authorities with an ImOnline key, so the runtime can submit the heartbeat that keeps them from being
+// reported offline.
+func (s *Service) runOffchainWorkers() bool {
+	return s.isBlockProducer && s.keys != nil && s.keys.Imon != nil && s.keys.Imon.Size() > 0
+}
+
+// handleOffchainWorkers runs the runtime's offchain workers on top of the given block. The ImOnline pallet uses
+// them to sign its heartbeat with the imon key and to submit it as an unsigned extrinsic to the transaction pool.
+// They always run the block's wasm code on their own instance, so they don't have an execution strategy.
+func (s *Service) handleOffchainWorkers(header *types.Header) error {
+	// the runtime looks up its keys by type, so it only gets to see the ImOnline keys
+	ks := keystore.NewGenericKeystore(keystore.ImonName)
+	for _, kp := range s.keys.Imon.Keypairs() {
+		ks.Insert(kp)
+	}
+
+	configure := func(cfg *wasmer.Config) {
+		cfg.Keystore = ks
+		cfg.Role = types.AuthorityRole
+		cfg.Transaction = s.transactionState
+	}
+
+	_, err := s.callRuntimeAt(runtime.OffchainWorkerAPIOffchainWorker, header.StateRoot, configure, func(ver *runtime.VersionAPI) ([]byte, error) {
+		return offchainWorkerArgs(ver, header)
+	})
+	return err
+}
+
+// offchainWorkerArgs encodes the argument of OffchainWorkerApi_offchain_worker, which is the block number before
+// version 2 of the API and the block header since
+func offchainWorkerArgs(ver *runtime.VersionAPI, header *types.Header) ([]byte, error) {
+	if v, _ := ver.APIVersion(runtime.OffchainWorkerAPIName); v < 2 {
+		number := make([]byte, 4)
+		binary.LittleEndian.PutUint32(number, uint32(header.Number.Uint64()))
+		return number, nil
+	}
+
+	return header.Encode()
+}
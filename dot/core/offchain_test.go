@@ -0,0 +1,70 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RunOffchainWorkers(t *testing.T) {
+	s := NewTestService(t, nil)
+	require.False(t, s.runOffchainWorkers())
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	s.keys.Imon.Insert(kp)
+	require.False(t, s.runOffchainWorkers())
+
+	s.isBlockProducer = true
+	require.True(t, s.runOffchainWorkers())
+}
+
+func TestOffchainWorkerArgs(t *testing.T) {
+	header := &types.Header{
+		Number: big.NewInt(0x0102),
+		Digest: types.NewEmptyDigest(),
+	}
+
+	v1 := &runtime.VersionAPI{
+		API: []*runtime.API_Item{
+			{Name: runtime.APIID(runtime.OffchainWorkerAPIName), Ver: 1},
+		},
+	}
+
+	args, err := offchainWorkerArgs(v1, header)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2, 1, 0, 0}, args)
+
+	v2 := &runtime.VersionAPI{
+		API: []*runtime.API_Item{
+			{Name: runtime.APIID(runtime.OffchainWorkerAPIName), Ver: 2},
+		},
+	}
+
+	args, err = offchainWorkerArgs(v2, header)
+	require.NoError(t, err)
+	enc, err := header.Encode()
+	require.NoError(t, err)
+	require.Equal(t, enc, args)
+}
@@ -18,18 +18,26 @@ package core
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/services"
+	"github.com/ChainSafe/gossamer/lib/trace"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 
 	log "github.com/ChainSafe/log15"
@@ -50,10 +58,31 @@ type Service struct {
 	storageState     StorageState
 	transactionState TransactionState
 
-	// Current runtime and hash of the current runtime code
+	// Current runtime and hash of the current on-chain runtime code
 	rt       runtime.LegacyInstance
 	codeHash common.Hash
 
+	// Runtime code replacing the on-chain code from the blocks with the given hashes
+	codeSubstitutes map[common.Hash][]byte
+
+	// Cache of compiled runtime modules, may be nil
+	codeCache *wasmer.CodeCache
+
+	// Number of wasm heap pages to use for new runtimes, overrides the value in storage if set
+	heapPages uint32
+
+	// Runtime calls made with CallRuntime
+	runtimeCallTimeout time.Duration
+	runtimeCalls       chan struct{}
+	callVersions       map[common.Hash]*runtime.VersionAPI // runtime versions by code hash, used to check calls are implemented
+	callVersionsLock   sync.RWMutex
+	callInstances      map[common.Hash][]*wasmer.LegacyInstance // idle call instances by code hash
+	callInstancesLock  sync.Mutex
+
+	// Transaction propagation
+	noPropagate  bool
+	localTxsOnly bool
+
 	// Block production variables
 	blockProducer   BlockProducer
 	isBlockProducer bool
@@ -66,8 +95,9 @@ type Service struct {
 	// Block verification
 	verifier Verifier
 
-	// Keystore
-	keys *keystore.GlobalKeystore
+	// Keystore, and the base path that rotated session keys are written to
+	keys     *keystore.GlobalKeystore
+	basePath string
 
 	// Channels and interfaces for inter-process communication
 	blkRec <-chan types.Block // receive blocks from BABE session
@@ -95,6 +125,13 @@ type Config struct {
 	IsFinalityAuthority     bool
 	ConsensusMessageHandler ConsensusMessageHandler
 	Verifier                Verifier
+	HeapPages               uint32
+	RuntimeCallTimeout      time.Duration
+	NoPropagate             bool // don't gossip transactions submitted via RPC
+	LocalTxsOnly            bool // ignore transactions gossiped by peers
+	CodeSubstitutes         map[common.Hash][]byte
+	CodeCache               *wasmer.CodeCache
+	BasePath                string // session keys generated by RotateKeys are written to its keystore if it's set
 
 	NewBlocks     chan types.Block // only used for testing purposes
 	BabeThreshold *big.Int         // used by Verifier, for development purposes
@@ -160,7 +197,17 @@ func NewService(cfg *Config) (*Service, error) {
 		cancel:                  cancel,
 		rt:                      cfg.Runtime,
 		codeHash:                codeHash,
+		codeSubstitutes:         cfg.CodeSubstitutes,
+		codeCache:               cfg.CodeCache,
+		heapPages:               cfg.HeapPages,
+		runtimeCallTimeout:      cfg.RuntimeCallTimeout,
+		runtimeCalls:            make(chan struct{}, maxRuntimeCalls),
+		callVersions:            make(map[common.Hash]*runtime.VersionAPI),
+		callInstances:           make(map[common.Hash][]*wasmer.LegacyInstance),
+		noPropagate:             cfg.NoPropagate,
+		localTxsOnly:            cfg.LocalTxsOnly,
 		keys:                    cfg.Keystore,
+		basePath:                cfg.BasePath,
 		blkRec:                  cfg.NewBlocks,
 		blockState:              cfg.BlockState,
 		storageState:            cfg.StorageState,
@@ -177,6 +224,10 @@ func NewService(cfg *Config) (*Service, error) {
 		blockAddChID:            id,
 	}
 
+	if srv.runtimeCallTimeout == 0 {
+		srv.runtimeCallTimeout = DefaultRuntimeCallTimeout
+	}
+
 	if cfg.NewBlocks != nil {
 		srv.blkRec = cfg.NewBlocks
 	} else if cfg.IsBlockProducer {
@@ -223,6 +274,10 @@ func (s *Service) Stop() error {
 	s.blockState.UnregisterImportedChannel(s.blockAddChID)
 	close(s.blockAddCh)
 
+	s.callInstancesLock.Lock()
+	s.stopCallInstances()
+	s.callInstancesLock.Unlock()
+
 	return nil
 }
 
@@ -254,7 +309,7 @@ func (s *Service) handleBlocks(ctx context.Context) {
 				s.logger.Warn("failed to store storage trie in database", "error", err)
 			}
 
-			if err := s.handleChainReorg(prev, block.Header.Hash()); err != nil {
+			if err := s.handleChainReorg(prev, s.blockState.BestBlockHash()); err != nil {
 				s.logger.Warn("failed to re-add transactions to chain upon re-org", "error", err)
 			}
 
@@ -265,6 +320,14 @@ func (s *Service) handleBlocks(ctx context.Context) {
 			if err := s.handleRuntimeChanges(block.Header); err != nil {
 				s.logger.Warn("failed to handle runtime change for block", "block", block.Header.Hash(), "error", err)
 			}
+
+			if s.runOffchainWorkers() && s.blockState.BestBlockHash() == block.Header.Hash() {
+				go func(header *types.Header) {
+					if err := s.handleOffchainWorkers(header); err != nil {
+						s.logger.Debug("failed to run offchain workers", "block", header.Hash(), "error", err)
+					}
+				}(block.Header)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -312,8 +375,17 @@ func (s *Service) handleReceivedBlock(block *types.Block) (err error) {
 		return ErrNilBlockState
 	}
 
+	_, span := trace.StartSpan(s.ctx, "core.handle_authored_block",
+		trace.Attribute{Key: "number", Value: block.Header.Number.String()},
+	)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	// the BABE session adds the blocks it builds itself, while it holds the storage lock
 	err = s.blockState.AddBlock(block)
-	if err != nil {
+	if err != nil && !errors.Is(err, blocktree.ErrBlockExists) {
 		return err
 	}
 
@@ -357,7 +429,8 @@ func (s *Service) handleReceivedMessage(msg network.Message) (err error) {
 	return err
 }
 
-// handleRuntimeChanges checks if changes to the runtime code have occurred; if so, load the new runtime
+// handleRuntimeChanges checks if changes to the runtime code have occurred, or if the code of the block is
+// substituted; if so, load the new runtime
 // It also updates the BABE service and block verifier with the new runtime
 func (s *Service) handleRuntimeChanges(header *types.Header) error {
 	sr, err := s.blockState.BestBlockStateRoot()
@@ -370,52 +443,65 @@ func (s *Service) handleRuntimeChanges(header *types.Header) error {
 		return err
 	}
 
-	if !bytes.Equal(currentCodeHash[:], s.codeHash[:]) {
-		code, err := s.storageState.LoadCode(&sr)
-		if err != nil {
-			return err
-		}
+	// a substitute replaces the on-chain code until the next runtime upgrade
+	code, substituted := s.codeSubstitutes[header.Hash()]
+	if !substituted && bytes.Equal(currentCodeHash[:], s.codeHash[:]) {
+		return nil
+	}
 
-		s.rt.Stop()
+	s.codeHash = currentCodeHash
 
-		ts, err := s.storageState.TrieState(&sr)
+	if substituted {
+		s.logger.Info("substituting runtime code", "block", header.Hash())
+	} else {
+		code, err = s.storageState.LoadCode(&sr)
 		if err != nil {
 			return err
 		}
+	}
 
-		cfg := &wasmer.Config{
-			Imports: wasmer.ImportsLegacyNodeRuntime,
-		}
-		cfg.Storage = ts
-		cfg.Keystore = s.keys.Acco.(*keystore.GenericKeystore)
-		cfg.LogLvl = -1
-		cfg.NodeStorage = s.rt.NodeStorage()
-		cfg.Network = s.rt.NetworkService()
+	s.rt.Stop()
 
-		s.rt, err = wasmer.NewLegacyInstance(code, cfg)
-		if err != nil {
-			return err
-		}
+	ts, err := s.storageState.OverlayState(&sr)
+	if err != nil {
+		return err
+	}
 
-		if s.isBlockProducer {
-			err = s.blockProducer.SetRuntime(s.rt)
-			if err != nil {
-				return err
-			}
-		}
+	cfg := &wasmer.Config{
+		Imports:   wasmer.ImportsLegacyNodeRuntime,
+		CodeCache: s.codeCache,
+	}
+	cfg.Storage = ts
+	cfg.Keystore = s.keys.Acco.(*keystore.GenericKeystore)
+	cfg.LogLvl = -1
+	cfg.NodeStorage = s.rt.NodeStorage()
+	cfg.Network = s.rt.NetworkService()
+	cfg.HeapPages = s.heapPages
+
+	s.rt, err = wasmer.NewLegacyInstance(code, cfg)
+	if err != nil {
+		return err
+	}
 
-		err = s.verifier.SetRuntimeChangeAtBlock(header, s.rt)
+	if s.isBlockProducer {
+		err = s.blockProducer.SetRuntime(s.rt)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = s.verifier.SetRuntimeChangeAtBlock(header, s.rt)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // handleChainReorg checks if there is a chain re-org (ie. new chain head is on a different chain than the
-// previous chain head). If there is a re-org, it moves the transactions that were included on the previous
-// chain back into the transaction pool.
+// previous chain head). If there is a re-org, the transactions that were included in the retracted blocks of the
+// previous chain are revalidated against the new chain head and resubmitted to the transaction pool, unless they
+// are also included in the new chain.
 func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 	ancestor, err := s.blockState.HighestCommonAncestor(prev, curr)
 	if err != nil {
@@ -428,42 +514,140 @@ func (s *Service) handleChainReorg(prev, curr common.Hash) error {
 		return nil
 	}
 
-	subchain, err := s.blockState.SubChain(ancestor, prev)
+	retracted, err := s.blockState.SubChain(ancestor, prev)
 	if err != nil {
 		return err
 	}
 
-	// for each block in the previous chain, re-add its extrinsics back into the pool
-	for _, hash := range subchain {
-		body, err := s.blockState.GetBlockBody(hash)
-		if err != nil {
-			continue
-		}
+	enacted, err := s.blockState.SubChain(ancestor, curr)
+	if err != nil {
+		return err
+	}
 
-		exts, err := body.AsExtrinsics()
-		if err != nil {
-			continue
+	included := make(map[common.Hash]struct{})
+	for _, hash := range enacted {
+		for _, ext := range s.blockExtrinsics(hash) {
+			included[ext.Hash()] = struct{}{}
 		}
+	}
 
-		for _, ext := range exts {
-			s.logger.Trace("validating transaction on re-org chain", "extrinsic", ext)
+	// the instance executing against the state of the new chain head is only created if there are extrinsics to
+	// revalidate
+	var rt *callInstance
+	defer func() {
+		if rt != nil {
+			s.releaseCallInstance(rt, true)
+		}
+	}()
 
-			txv, err := s.rt.ValidateTransaction(ext)
-			if err != nil {
-				s.logger.Trace("failed to validate transaction", "extrinsic", ext)
+	// for each block in the previous chain, re-add its extrinsics back into the pool
+	for _, hash := range retracted {
+		for _, ext := range s.blockExtrinsics(hash) {
+			if _, has := included[ext.Hash()]; has {
+				s.transactionState.RecordResubmission(transaction.ResubmitSkipped)
 				continue
 			}
 
-			vtx := transaction.NewValidTransaction(ext, txv)
-			s.transactionState.AddToPool(vtx)
+			if rt == nil {
+				rt, err = s.newHeadInstance(curr)
+				if err != nil {
+					return fmt.Errorf("failed to load state of new chain head: %w", err)
+				}
+			}
+
+			result := s.resubmitExtrinsic(rt, ext)
+			s.transactionState.RecordResubmission(result)
+			s.logger.Trace("resubmitted transaction from re-org chain", "hash", ext.Hash(), "result", result)
 		}
 	}
 
 	return nil
 }
 
-// maintainTransactionPool removes any transactions that were included in the new block, revalidates the transactions in the pool,
-// and moves them to the queue if valid.
+// blockExtrinsics returns the extrinsics in the body of the block with the given hash, or nil if the body isn't
+// available
+func (s *Service) blockExtrinsics(hash common.Hash) []types.Extrinsic {
+	body, err := s.blockState.GetBlockBody(hash)
+	if err != nil {
+		return nil
+	}
+
+	exts, err := body.AsExtrinsics()
+	if err != nil {
+		return nil
+	}
+
+	return exts
+}
+
+// newHeadInstance returns a runtime instance that executes against a copy of the state of the given chain head, so
+// that transactions are revalidated against it without modifying the stored state or changing the storage of the
+// node's runtime, which BABE may be building a block with. The instance is released with releaseCallInstance.
+func (s *Service) newHeadInstance(head common.Hash) (*callInstance, error) {
+	header, err := s.blockState.GetHeader(head)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.storageState.LoadCode(&header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := s.storageState.TrieStateCopy(&header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newCallInstance(code, ts, nil)
+}
+
+// resubmitExtrinsic revalidates an extrinsic of a retracted block with the given runtime instance and adds it back
+// to the pool, or to the future queue if it isn't valid yet. Extrinsics that were submitted to this node keep their
+// preference over remote ones.
+func (s *Service) resubmitExtrinsic(rt runtime.LegacyInstance, ext types.Extrinsic) transaction.ResubmitResult {
+	if s.transactionState.IsBanned(ext) {
+		return transaction.ResubmitSkipped
+	}
+
+	txv, err := rt.ValidateTransaction(ext)
+	if errors.Is(err, runtime.ErrFutureTransaction) {
+		s.addFutureTransaction(ext)
+		return transaction.ResubmitFuture
+	}
+	if err != nil {
+		s.logger.Trace("failed to validate transaction", "extrinsic", ext)
+		s.recordValidationFailure(ext)
+		return transaction.ResubmitInvalid
+	}
+
+	vtx := transaction.NewValidTransaction(ext, txv)
+	vtx.Local = s.transactionState.IsLocal(ext)
+	if _, err = s.transactionState.AddToPool(vtx); err != nil {
+		s.logger.Debug("failed to add transaction from re-org chain to pool", "extrinsic", ext, "error", err)
+		return transaction.ResubmitInvalid
+	}
+
+	return transaction.ResubmitReady
+}
+
+// recordValidationFailure records that the extrinsic failed validation, logging if it's now banned
+func (s *Service) recordValidationFailure(ext types.Extrinsic) {
+	if !s.transactionState.RecordValidationFailure(ext) {
+		return
+	}
+
+	stats := s.transactionState.BanStats()
+	s.logger.Debug("banned transaction after repeated validation failures",
+		"hash", ext.Hash(),
+		"banned", stats.Banned,
+		"total bans", stats.TotalBans,
+		"rejected", stats.Rejected,
+	)
+}
+
+// maintainTransactionPool removes any transactions that were included in the new block, revalidates the transactions in the pool
+// against the state of the best block, and moves them to the queue if valid.
 // See https://github.com/paritytech/substrate/blob/74804b5649eccfb83c90aec87bdca58e5d5c8789/client/transaction-pool/src/lib.rs#L545
 func (s *Service) maintainTransactionPool(block *types.Block) error {
 	exts, err := block.Body.AsExtrinsics()
@@ -472,23 +656,40 @@ func (s *Service) maintainTransactionPool(block *types.Block) error {
 	}
 
 	// remove extrinsics included in a block
+	hash := block.Header.Hash()
 	for _, ext := range exts {
-		s.transactionState.RemoveExtrinsic(ext)
+		s.transactionState.RemoveIncludedExtrinsic(ext, hash)
 	}
 
+	rt, err := s.newHeadInstance(s.blockState.BestBlockHash())
+	if err != nil {
+		return err
+	}
+	defer s.releaseCallInstance(rt, true)
+
+	s.promoteFutureTransactions(rt, block.Header.Number)
+
 	// re-validate transactions in the pool and move them to the queue
 	txs := s.transactionState.PendingInPool()
 	for _, tx := range txs {
-		// TODO: re-add this on update to v0.8
-
-		// val, err := s.rt.ValidateTransaction(tx.Extrinsic)
-		// if err != nil {
-		// 	// failed to validate tx, remove it from the pool or queue
-		// 	s.transactionState.RemoveExtrinsic(ext)
-		// 	continue
-		// }
+		val, err := rt.ValidateTransaction(tx.Extrinsic)
+		if errors.Is(err, runtime.ErrFutureTransaction) {
+			// the transaction isn't valid at the new best block yet, it's revalidated after more blocks
+			s.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
+			s.addFutureTransaction(tx.Extrinsic)
+			continue
+		}
+		if err != nil {
+			// failed to validate tx, remove it from the pool
+			s.logger.Trace("pool transaction is no longer valid", "hash", tx.Extrinsic.Hash(), "error", err)
+			s.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
+			s.recordValidationFailure(tx.Extrinsic)
+			continue
+		}
 
-		// tx = transaction.NewValidTransaction(tx.Extrinsic, val)
+		local := tx.Local
+		tx = transaction.NewValidTransaction(tx.Extrinsic, val)
+		tx.Local = local
 
 		h, err := s.transactionState.Push(tx)
 		if err != nil && err == transaction.ErrTransactionExists {
@@ -497,6 +698,12 @@ func (s *Service) maintainTransactionPool(block *types.Block) error {
 			continue
 		}
 
+		if errors.Is(err, transaction.ErrPoolCountLimit) || errors.Is(err, transaction.ErrPoolSizeLimit) {
+			// the queue is full, the remaining transactions are kept in the pool
+			s.logger.Debug("transaction queue is full", "error", err)
+			break
+		}
+
 		s.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
 		s.logger.Trace("moved transaction to queue", "hash", h)
 	}
@@ -504,21 +711,157 @@ func (s *Service) maintainTransactionPool(block *types.Block) error {
 	return nil
 }
 
-// InsertKey inserts keypair into the account keystore
-// TODO: define which keystores need to be updated and create separate insert funcs for each
-func (s *Service) InsertKey(kp crypto.Keypair) {
-	s.keys.Acco.Insert(kp)
+// sessionKeyTypes are the keystores of the session keys, in the order of the session keys of runtimes that don't
+// provide the SessionKeys runtime API
+var sessionKeyTypes = []keystore.Name{keystore.GranName, keystore.BabeName, keystore.ImonName, keystore.AudiName}
+
+// sessionKeyLength is the length of each public key in the session keys
+const sessionKeyLength = 32
+
+// maxSessionKeys bounds the number of session keys looked for in the runtime's session keys
+const maxSessionKeys = 8
+
+// sessionKeyNames returns the keystores of the runtime's session keys, in the order of its session keys. The runtime
+// only decodes session keys of the right length, and all session public keys are 32 bytes long, so the key types are
+// found by decoding zeroed keys of increasing length.
+func (s *Service) sessionKeyNames() ([]keystore.Name, error) {
+	for n := 1; n <= maxSessionKeys; n++ {
+		keys, err := s.DecodeSessionKeys(make([]byte, n*sessionKeyLength))
+		if errors.Is(err, runtime.ErrInvalidSessionKeys) {
+			continue
+		}
+		if err != nil {
+			s.logger.Debug("runtime can't decode session keys, using default session key types", "error", err)
+			return sessionKeyTypes, nil
+		}
+
+		names := make([]keystore.Name, len(keys))
+		for i, key := range keys {
+			names[i] = keystore.Name(key.Type.String())
+			if s.keys.Get(names[i]) == nil {
+				return nil, fmt.Errorf("no keystore for session key type %s", key.Type)
+			}
+		}
+		return names, nil
+	}
+
+	return nil, fmt.Errorf("runtime session keys have more than %d keys", maxSessionKeys)
 }
 
-// HasKey returns true if given hex encoded public key string is found in keystore, false otherwise, error if there
-//  are issues decoding string
+// InsertKey inserts keypair into the keystore of the given key type
+func (s *Service) InsertKey(kp crypto.Keypair, keyType string) error {
+	ks := s.keys.Get(keystore.Name(keyType))
+	if ks == nil {
+		return fmt.Errorf("unknown key type: %s", keyType)
+	}
+
+	ks.Insert(kp)
+	return nil
+}
+
+// HasKey returns true if given hex encoded public key string is found in the keystore of the given key type, false
+// otherwise, error if there are issues decoding string
 func (s *Service) HasKey(pubKeyStr string, keyType string) (bool, error) {
-	return keystore.HasKey(pubKeyStr, keyType, s.keys.Acco)
+	ks := s.keys.Get(keystore.Name(keyType))
+	if ks == nil {
+		return false, fmt.Errorf("unknown key type: %s", keyType)
+	}
+
+	return keystore.HasKey(pubKeyStr, keyType, ks)
+}
+
+// HasSessionKeys returns true if all the public keys in the given concatenated session keys, as returned by
+// RotateKeys, are found in the keystores of their key types. The keys are split into key types by the runtime.
+func (s *Service) HasSessionKeys(enc []byte) (bool, error) {
+	keys, err := s.decodeSessionKeys(enc)
+	if err != nil {
+		return false, err
+	}
+
+	var has bool
+	for _, key := range keys {
+		ks := s.keys.Get(keystore.Name(key.Type.String()))
+		if ks == nil {
+			return false, nil
+		}
+
+		has, err = keystore.HasKey(common.BytesToHex(key.Public), key.Type.String(), ks)
+		if err != nil || !has {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// decodeSessionKeys splits the given concatenated session keys into their key types and public keys with the
+// runtime, or with the default session key types if the runtime doesn't provide the SessionKeys runtime API
+func (s *Service) decodeSessionKeys(enc []byte) ([]*runtime.SessionKey, error) {
+	keys, err := s.DecodeSessionKeys(enc)
+	if errors.Is(err, runtime.ErrInvalidSessionKeys) {
+		return nil, ErrInvalidSessionKeys
+	}
+	if err == nil {
+		return keys, nil
+	}
+
+	s.logger.Debug("runtime can't decode session keys, using default session key types", "error", err)
+	if len(enc) != len(sessionKeyTypes)*sessionKeyLength {
+		return nil, ErrInvalidSessionKeys
+	}
+
+	keys = make([]*runtime.SessionKey, len(sessionKeyTypes))
+	for i, name := range sessionKeyTypes {
+		keys[i] = &runtime.SessionKey{
+			Public: enc[i*sessionKeyLength : (i+1)*sessionKeyLength],
+		}
+		copy(keys[i].Type[:], name)
+	}
+
+	return keys, nil
+}
+
+// RotateKeys generates new session keys of the runtime's key types, inserts them into their keystores and returns
+// their public keys concatenated in the order of the runtime session keys. The keys are also written to the keystore
+// of the node's base path, so they're loaded again when the node restarts.
+func (s *Service) RotateKeys() ([]byte, error) {
+	names, err := s.sessionKeyNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var enc []byte
+	for _, name := range names {
+		ks := s.keys.Get(name)
+
+		var kp crypto.Keypair
+		if ks.Type() == crypto.Ed25519Type {
+			kp, err = ed25519.GenerateKeypair()
+		} else {
+			kp, err = sr25519.GenerateKeypair()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if s.basePath != "" {
+			_, err = keystore.WriteSessionKey(s.basePath, name, kp)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ks.Insert(kp)
+		enc = append(enc, kp.Public().Encode()...)
+	}
+
+	s.logger.Info("rotated session keys", "keys", common.BytesToHex(enc))
+	return enc, nil
 }
 
 // GetRuntimeVersion gets the current RuntimeVersion
 func (s *Service) GetRuntimeVersion() (*runtime.VersionAPI, error) {
-	ts, err := s.storageState.TrieState(nil)
+	ts, err := s.storageState.OverlayState(nil)
 	if err != nil {
 		return nil, err
 	}
@@ -532,10 +875,29 @@ func (s *Service) IsBlockProducer() bool {
 	return s.isBlockProducer
 }
 
-// HandleSubmittedExtrinsic is used to send a Transaction message containing a Extrinsic @ext
+// RestartBlockProducer restarts the BABE session with the given configuration, eg. to use new keys or epoch data
+// after a long desync. The session keeps sending blocks to the core service, so the node doesn't need to be
+// restarted and keeps its peers and transaction pool.
+func (s *Service) RestartBlockProducer(cfg *babe.RestartConfig) error {
+	if !s.isBlockProducer || s.blockProducer == nil {
+		return ErrNotBlockProducer
+	}
+
+	s.logger.Info("restarting BABE session")
+	return s.blockProducer.Restart(cfg)
+}
+
+// HandleSubmittedExtrinsic is used to send a Transaction message containing a Extrinsic @ext.
+// It does nothing if the node is configured not to propagate transactions.
 func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
+	if s.noPropagate {
+		s.logger.Trace("not propagating submitted extrinsic", "hash", ext.Hash())
+		return nil
+	}
+
 	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}}
 	s.net.SendMessage(msg)
+	s.transactionState.NotifyBroadcast(ext)
 	return nil
 }
 
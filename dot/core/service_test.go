@@ -19,13 +19,13 @@ package core
 import (
 	"io/ioutil"
 	"math/big"
-	"sort"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
@@ -34,6 +34,7 @@ import (
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/lib/utils"
 	log "github.com/ChainSafe/log15"
 	"github.com/stretchr/testify/require"
 )
@@ -54,7 +55,8 @@ func addTestBlocksToStateWithParent(t *testing.T, previousHash common.Hash, dept
 			Header: &types.Header{
 				ParentHash: previousHash,
 				Number:     big.NewInt(int64(i)).Add(previousNum, big.NewInt(int64(i))),
-				Digest:     [][]byte{},
+				StateRoot:  prevHeader.StateRoot,
+				Digest:     types.NewEmptyDigest(),
 			},
 			Body: &types.Body{},
 		}
@@ -153,7 +155,7 @@ func TestHandleRuntimeChanges(t *testing.T) {
 		ParentHash: s.blockState.BestBlockHash(),
 		Number:     big.NewInt(1),
 		StateRoot:  root,
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	err = s.blockState.AddBlock(&types.Block{
@@ -170,11 +172,78 @@ func TestHandleRuntimeChanges(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestHandleRuntimeChanges_CodeSubstitute(t *testing.T) {
+	tt := trie.NewEmptyTrie()
+	rt := wasmer.NewTestLegacyInstanceWithTrie(t, runtime.LEGACY_NODE_RUNTIME, tt, log.LvlTrace)
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	ks := keystore.NewGlobalKeystore()
+	ks.Acco.Insert(kp)
+
+	_, err = runtime.GetRuntimeBlob(runtime.TESTS_FP, runtime.TEST_WASM_URL)
+	require.NoError(t, err)
+
+	testRuntime, err := ioutil.ReadFile(runtime.TESTS_FP)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		Runtime:          rt,
+		Keystore:         ks,
+		TransactionState: state.NewTransactionState(),
+		CodeSubstitutes:  make(map[common.Hash][]byte),
+	}
+
+	s := NewTestService(t, cfg)
+
+	root, err := s.blockState.BestBlockStateRoot()
+	require.NoError(t, err)
+
+	// the on-chain code doesn't change, but the code of the block is substituted
+	head := &types.Header{
+		ParentHash: s.blockState.BestBlockHash(),
+		Number:     big.NewInt(1),
+		StateRoot:  root,
+		Digest:     types.NewEmptyDigest(),
+	}
+	s.codeSubstitutes[head.Hash()] = testRuntime
+
+	err = s.blockState.AddBlock(&types.Block{
+		Header: head,
+		Body:   types.NewBody([]byte{}),
+	})
+	require.NoError(t, err)
+
+	err = s.handleRuntimeChanges(head)
+	require.NoError(t, err)
+	require.NotEqual(t, rt, s.rt)
+
+	// the substitute is kept for the following blocks
+	substitute := s.rt
+	next := &types.Header{
+		ParentHash: head.Hash(),
+		Number:     big.NewInt(2),
+		StateRoot:  root,
+		Digest:     types.NewEmptyDigest(),
+	}
+
+	err = s.blockState.AddBlock(&types.Block{
+		Header: next,
+		Body:   types.NewBody([]byte{}),
+	})
+	require.NoError(t, err)
+
+	err = s.handleRuntimeChanges(next)
+	require.NoError(t, err)
+	require.Equal(t, substitute, s.rt)
+}
+
 func TestService_HasKey(t *testing.T) {
 	ks := keystore.NewGlobalKeystore()
 	kr, err := keystore.NewSr25519Keyring()
 	require.NoError(t, err)
-	ks.Acco.Insert(kr.Alice())
+	ks.Babe.Insert(kr.Alice())
 
 	cfg := &Config{
 		Keystore: ks,
@@ -184,6 +253,58 @@ func TestService_HasKey(t *testing.T) {
 	res, err := svc.HasKey(kr.Alice().Public().Hex(), "babe")
 	require.NoError(t, err)
 	require.True(t, res)
+
+	// the key is only in the babe keystore
+	res, err = svc.HasKey(kr.Alice().Public().Hex(), "imon")
+	require.NoError(t, err)
+	require.False(t, res)
+}
+
+func TestService_InsertKey(t *testing.T) {
+	ks := keystore.NewGlobalKeystore()
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	svc := NewTestService(t, &Config{
+		Keystore: ks,
+	})
+
+	err = svc.InsertKey(kr.Alice(), "audi")
+	require.NoError(t, err)
+	require.Equal(t, 1, ks.Audi.Size())
+	require.Equal(t, 0, ks.Acco.Size())
+
+	err = svc.InsertKey(kr.Alice(), "xxxx")
+	require.EqualError(t, err, "unknown key type: xxxx")
+}
+
+func TestService_HasSessionKeys(t *testing.T) {
+	svc := NewTestService(t, &Config{
+		Keystore: keystore.NewGlobalKeystore(),
+	})
+
+	keys, err := svc.RotateKeys()
+	require.NoError(t, err)
+
+	has, err := svc.HasSessionKeys(keys)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	// a key of another node
+	other := append([]byte{}, keys...)
+	other[len(other)-1]++
+	has, err = svc.HasSessionKeys(other)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	// the keys are checked against the keystores of their key types
+	swapped := append(append(append([]byte{}, keys[32:64]...), keys[:32]...), keys[64:]...)
+	has, err = svc.HasSessionKeys(swapped)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	_, err = svc.HasSessionKeys(keys[1:])
+	require.Equal(t, ErrInvalidSessionKeys, err)
 }
 
 func TestService_HasKey_UnknownType(t *testing.T) {
@@ -202,6 +323,76 @@ func TestService_HasKey_UnknownType(t *testing.T) {
 	require.False(t, res)
 }
 
+func TestService_RotateKeys(t *testing.T) {
+	ks := keystore.NewGlobalKeystore()
+	svc := NewTestService(t, &Config{
+		Keystore: ks,
+	})
+
+	res, err := svc.RotateKeys()
+	require.NoError(t, err)
+	require.Len(t, res, 4*32)
+
+	for i, k := range []keystore.Keystore{ks.Gran, ks.Babe, ks.Imon, ks.Audi} {
+		require.Equal(t, 1, k.Size())
+		require.Equal(t, res[i*32:(i+1)*32], k.PublicKeys()[0].Encode())
+	}
+
+	next, err := svc.RotateKeys()
+	require.NoError(t, err)
+	require.NotEqual(t, res, next)
+	require.Equal(t, 2, ks.Babe.Size())
+}
+
+func TestService_SessionKeyNames(t *testing.T) {
+	svc := NewTestService(t, &Config{
+		Keystore: keystore.NewGlobalKeystore(),
+	})
+
+	// the node runtime's session keys are grandpa, babe, im_online and authority_discovery keys
+	names, err := svc.sessionKeyNames()
+	require.NoError(t, err)
+	require.Equal(t, sessionKeyTypes, names)
+}
+
+func TestService_RotateKeys_Persisted(t *testing.T) {
+	basepath := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	svc := NewTestService(t, &Config{
+		Keystore: keystore.NewGlobalKeystore(),
+		BasePath: basepath,
+	})
+
+	res, err := svc.RotateKeys()
+	require.NoError(t, err)
+
+	// the keys are found by a node restarted with the same base path
+	ks := keystore.NewGlobalKeystore()
+	err = keystore.LoadSessionKeys(ks, basepath)
+	require.NoError(t, err)
+
+	for i, k := range []keystore.Keystore{ks.Gran, ks.Babe, ks.Imon, ks.Audi} {
+		require.Equal(t, 1, k.Size())
+		require.Equal(t, res[i*32:(i+1)*32], k.PublicKeys()[0].Encode())
+	}
+}
+
+func TestService_RestartBlockProducer(t *testing.T) {
+	s := NewTestService(t, nil)
+	err := s.RestartBlockProducer(&babe.RestartConfig{})
+	require.Equal(t, ErrNotBlockProducer, err)
+
+	bp := &mockBlockProducer{}
+	s.isBlockProducer = true
+	s.blockProducer = bp
+
+	cfg := &babe.RestartConfig{StartSlot: 100}
+	err = s.RestartBlockProducer(cfg)
+	require.NoError(t, err)
+	require.Equal(t, cfg, bp.restart)
+}
+
 func TestHandleChainReorg_NoReorg(t *testing.T) {
 	s := NewTestService(t, nil)
 	addTestBlocksToState(t, 4, s.blockState.(*state.BlockState))
@@ -266,7 +457,8 @@ func TestHandleChainReorg_WithReorg_Transactions(t *testing.T) {
 		Header: &types.Header{
 			ParentHash: ancestor.Header.Hash(),
 			Number:     big.NewInt(0).Add(ancestor.Header.Number, big.NewInt(1)),
-			Digest:     [][]byte{{1}},
+			StateRoot:  ancestor.Header.StateRoot,
+			Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{1}}),
 		},
 		Body: body,
 	}
@@ -293,95 +485,187 @@ func TestHandleChainReorg_WithReorg_Transactions(t *testing.T) {
 	require.Equal(t, transaction.NewValidTransaction(tx, validity), pending[0])
 }
 
-func TestMaintainTransactionPool_EmptyBlock(t *testing.T) {
-	// TODO" update these to real extrinsics on update to v0.8
-	txs := []*transaction.ValidTransaction{
-		{
-			Extrinsic: []byte("a"),
-			Validity:  &transaction.Validity{Priority: 1},
-		},
-		{
-			Extrinsic: []byte("b"),
-			Validity:  &transaction.Validity{Priority: 4},
-		},
-		{
-			Extrinsic: []byte("c"),
-			Validity:  &transaction.Validity{Priority: 2},
-		},
-		{
-			Extrinsic: []byte("d"),
-			Validity:  &transaction.Validity{Priority: 17},
-		},
-		{
-			Extrinsic: []byte("e"),
-			Validity:  &transaction.Validity{Priority: 2},
+// contextRecordingInstance counts the storage contexts set on the runtime instance it wraps
+type contextRecordingInstance struct {
+	runtime.LegacyInstance
+	contexts int
+}
+
+func (in *contextRecordingInstance) SetContext(s runtime.Storage) {
+	in.contexts++
+	in.LegacyInstance.SetContext(s)
+}
+
+func TestHandleChainReorg_WithReorg_LocalTransaction(t *testing.T) {
+	rt := &contextRecordingInstance{
+		LegacyInstance: wasmer.NewTestLegacyInstance(t, runtime.SUBSTRATE_TEST_RUNTIME),
+	}
+	cfg := &Config{
+		Runtime: rt,
+	}
+
+	s := NewTestService(t, cfg)
+	addTestBlocksToState(t, 5, s.blockState.(*state.BlockState))
+	ts := s.transactionState.(*state.TransactionState)
+
+	tx, err := extrinsic.NewIncludeDataExt([]byte("nootwashere")).Encode()
+	require.NoError(t, err)
+
+	validity, err := s.rt.ValidateTransaction(tx)
+	require.NoError(t, err)
+
+	ancestor, err := s.blockState.(*state.BlockState).GetBlockByNumber(big.NewInt(2))
+	require.NoError(t, err)
+
+	// the transaction was submitted to this node, and included in a block that is then retracted
+	_, err = ts.Push(&transaction.ValidTransaction{Extrinsic: tx, Validity: validity, Local: true})
+	require.NoError(t, err)
+
+	block := newTestForkBlock(t, ancestor.Header, 1, tx)
+	err = s.blockState.AddBlock(block)
+	require.NoError(t, err)
+	ts.RemoveIncludedExtrinsic(tx, block.Header.Hash())
+	require.Empty(t, ts.Pending())
+
+	// the extrinsic is revalidated on a separate instance, the node's runtime keeps its storage for BABE
+	contexts := rt.contexts
+	err = s.handleChainReorg(block.Header.Hash(), s.blockState.BestBlockHash())
+	require.NoError(t, err)
+	require.Equal(t, contexts, rt.contexts)
+
+	pending := ts.Pending()
+	require.Equal(t, 1, len(pending))
+	require.True(t, pending[0].Local)
+	require.Equal(t, uint64(1), ts.ResubmitStats().Count(transaction.ResubmitReady))
+}
+
+func TestHandleChainReorg_WithReorg_IncludedInNewChain(t *testing.T) {
+	cfg := &Config{
+		Runtime: wasmer.NewTestLegacyInstance(t, runtime.SUBSTRATE_TEST_RUNTIME),
+	}
+
+	s := NewTestService(t, cfg)
+	addTestBlocksToState(t, 5, s.blockState.(*state.BlockState))
+	ts := s.transactionState.(*state.TransactionState)
+
+	tx, err := extrinsic.NewIncludeDataExt([]byte("nootwashere")).Encode()
+	require.NoError(t, err)
+
+	ancestor, err := s.blockState.(*state.BlockState).GetBlockByNumber(big.NewInt(2))
+	require.NoError(t, err)
+
+	// the transaction is included in both the retracted and the enacted chain
+	retracted := newTestForkBlock(t, ancestor.Header, 1, tx)
+	err = s.blockState.AddBlock(retracted)
+	require.NoError(t, err)
+
+	enacted := newTestForkBlock(t, ancestor.Header, 2, tx)
+	err = s.blockState.AddBlock(enacted)
+	require.NoError(t, err)
+
+	err = s.handleChainReorg(retracted.Header.Hash(), enacted.Header.Hash())
+	require.NoError(t, err)
+
+	require.Empty(t, ts.Pending())
+	require.Equal(t, uint64(1), ts.ResubmitStats().Count(transaction.ResubmitSkipped))
+	require.Equal(t, uint64(0), ts.ResubmitStats().Count(transaction.ResubmitReady))
+}
+
+// newTestForkBlock returns a child of the given parent including the given extrinsics. The digest distinguishes
+// siblings from each other.
+func newTestForkBlock(t *testing.T, parent *types.Header, digest byte, exts ...types.Extrinsic) *types.Block {
+	body, err := types.NewBodyFromExtrinsics(exts)
+	require.NoError(t, err)
+
+	return &types.Block{
+		Header: &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(0).Add(parent.Number, big.NewInt(1)),
+			StateRoot:  parent.StateRoot,
+			Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{digest}}),
 		},
+		Body: body,
 	}
+}
 
-	ts := state.NewTransactionState()
-	hashes := make([]common.Hash, len(txs))
+// newTestPoolTransactions adds valid transactions with the given data to the pool of the service
+func newTestPoolTransactions(t *testing.T, s *Service, data ...string) []*transaction.ValidTransaction {
+	ts := s.transactionState.(*state.TransactionState)
 
-	for i, tx := range txs {
-		h := ts.AddToPool(tx)
-		hashes[i] = h
+	txs := make([]*transaction.ValidTransaction, len(data))
+	for i, d := range data {
+		tx, err := extrinsic.NewIncludeDataExt([]byte(d)).Encode()
+		require.NoError(t, err)
+
+		validity, err := s.rt.ValidateTransaction(tx)
+		require.NoError(t, err)
+
+		txs[i] = transaction.NewValidTransaction(tx, validity)
+		_, err = ts.AddToPool(txs[i])
+		require.NoError(t, err)
 	}
 
-	s := &Service{
-		transactionState: ts,
-		logger:           log.New("pkg", "core"),
+	return txs
+}
+
+func TestMaintainTransactionPool_EmptyBlock(t *testing.T) {
+	cfg := &Config{
+		Runtime: wasmer.NewTestLegacyInstance(t, runtime.SUBSTRATE_TEST_RUNTIME),
 	}
 
-	err := s.maintainTransactionPool(&types.Block{
-		Body: types.NewBody([]byte{}),
+	s := NewTestService(t, cfg)
+	ts := s.transactionState.(*state.TransactionState)
+	txs := newTestPoolTransactions(t, s, "a", "b", "c", "d", "e")
+
+	// a transaction that is no longer valid is dropped when the pool is revalidated
+	_, err := ts.AddToPool(&transaction.ValidTransaction{
+		Extrinsic: []byte("invalid"),
+		Validity:  &transaction.Validity{Priority: 1},
 	})
 	require.NoError(t, err)
 
-	res := make([]*transaction.ValidTransaction, len(txs))
-	for i := range txs {
-		res[i] = ts.Pop()
-	}
+	head, err := s.blockState.BestBlockHeader()
+	require.NoError(t, err)
 
-	sort.Slice(res, func(i, j int) bool {
-		return res[i].Extrinsic[0] < res[j].Extrinsic[0]
+	err = s.maintainTransactionPool(&types.Block{
+		Header: head,
+		Body:   types.NewBody([]byte{}),
 	})
-	require.Equal(t, txs, res)
+	require.NoError(t, err)
+	require.Empty(t, ts.PendingInPool())
 
-	for _, tx := range txs {
-		ts.RemoveExtrinsic(tx.Extrinsic)
+	res := []*transaction.ValidTransaction{}
+	for {
+		tx := ts.Pop()
+		if tx == nil {
+			break
+		}
+		res = append(res, tx)
 	}
-	head := ts.Pop()
-	require.Nil(t, head)
+	require.ElementsMatch(t, txs, res)
 }
 
 func TestMaintainTransactionPool_BlockWithExtrinsics(t *testing.T) {
-	txs := []*transaction.ValidTransaction{
-		{
-			Extrinsic: []byte("a"),
-			Validity:  &transaction.Validity{Priority: 1},
-		},
-		{
-			Extrinsic: []byte("b"),
-			Validity:  &transaction.Validity{Priority: 4},
-		},
+	cfg := &Config{
+		Runtime: wasmer.NewTestLegacyInstance(t, runtime.SUBSTRATE_TEST_RUNTIME),
 	}
 
-	ts := state.NewTransactionState()
-	hashes := make([]common.Hash, len(txs))
-
-	for i, tx := range txs {
-		h := ts.AddToPool(tx)
-		hashes[i] = h
-	}
+	s := NewTestService(t, cfg)
+	ts := s.transactionState.(*state.TransactionState)
+	txs := newTestPoolTransactions(t, s, "a", "b")
 
-	s := &Service{
-		transactionState: ts,
-		logger:           log.New("pkg", "core"),
-	}
+	head, err := s.blockState.BestBlockHeader()
+	require.NoError(t, err)
 
 	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{txs[0].Extrinsic})
 	require.NoError(t, err)
 
 	err = s.maintainTransactionPool(&types.Block{
+		Header: &types.Header{
+			ParentHash: head.Hash(),
+			Number:     big.NewInt(0).Add(head.Number, big.NewInt(1)),
+			Digest:     types.NewEmptyDigest(),
+		},
 		Body: body,
 	})
 	require.NoError(t, err)
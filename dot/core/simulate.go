@@ -0,0 +1,102 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+// SimulateExtrinsic applies the given extrinsic on top of the block with the given hash, or the best block if
+// the hash is nil, without importing anything. The block's state is read in place and the changes made by the
+// extrinsic are kept in an overlay, so the node's state is left untouched. It returns the encoded
+// ApplyExtrinsicResult and the encoded events deposited by the extrinsic.
+func (s *Service) SimulateExtrinsic(ext types.Extrinsic, bhash *common.Hash) ([]byte, []byte, error) {
+	var (
+		parent *types.Header
+		err    error
+	)
+
+	if bhash == nil {
+		parent, err = s.blockState.BestBlockHeader()
+	} else {
+		var block *types.Block
+		block, err = s.blockState.GetBlockByHash(*bhash)
+		if block != nil {
+			parent = block.Header
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case s.runtimeCalls <- struct{}{}:
+	default:
+		return nil, nil, ErrTooManyRuntimeCalls
+	}
+
+	sr := parent.StateRoot
+	code, err := s.storageState.LoadCode(&sr)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, nil, err
+	}
+
+	ts, err := s.storageState.OverlayState(&sr)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, nil, err
+	}
+
+	rt, err := s.newCallInstance(code, ts, nil)
+	if err != nil {
+		<-s.runtimeCalls
+		return nil, nil, err
+	}
+
+	var events []byte
+	res, err := s.runCall(runtime.BlockBuilderApplyExtrinsic, rt, func() ([]byte, error) {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Digest:     types.NewEmptyDigest(),
+		}
+
+		if err := rt.InitializeBlock(header); err != nil {
+			return nil, err
+		}
+
+		res, err := rt.ApplyExtrinsic(ext)
+		if err != nil {
+			return nil, err
+		}
+
+		// the events of the simulated block only contain the ones deposited by the block initialization
+		// and the extrinsic
+		events, err = ts.Get(runtime.SystemEventsKey())
+		return res, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, events, nil
+}
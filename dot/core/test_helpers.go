@@ -24,6 +24,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/keystore"
@@ -61,7 +62,8 @@ func (v *mockVerifier) SetAuthorityChangeAtBlock(header *types.Header, auths []*
 
 // mockBlockProducer implements the BlockProducer interface
 type mockBlockProducer struct {
-	auths []*types.Authority
+	auths   []*types.Authority
+	restart *babe.RestartConfig
 }
 
 // Start mocks starting
@@ -93,6 +95,12 @@ func (bp *mockBlockProducer) SetRuntime(rt runtime.LegacyInstance) error {
 	return nil
 }
 
+// Restart mocks restarting, recording the given config
+func (bp *mockBlockProducer) Restart(cfg *babe.RestartConfig) error {
+	bp.restart = cfg
+	return nil
+}
+
 type mockNetwork struct {
 	Message network.Message
 }
@@ -107,6 +115,7 @@ type mockFinalityGadget struct {
 	out       chan FinalityMessage
 	finalized chan FinalityMessage
 	auths     []*types.Authority
+	paused    bool
 }
 
 // Start mocks starting
@@ -147,6 +156,14 @@ func (fg *mockFinalityGadget) Authorities() []*types.Authority {
 	return fg.auths
 }
 
+func (fg *mockFinalityGadget) PauseVoting() {
+	fg.paused = true
+}
+
+func (fg *mockFinalityGadget) ResumeVoting() {
+	fg.paused = false
+}
+
 var testConsensusMessage = &network.ConsensusMessage{
 	ConsensusEngineID: types.GrandpaEngineID,
 	Data:              []byte("nootwashere"),
@@ -293,6 +310,10 @@ func (s *mockSyncer) CreateBlockResponse(msg *network.BlockRequestMessage) (*net
 	return nil, nil
 }
 
+func (s *mockSyncer) ValidateBlockAnnounce(msg *network.BlockAnnounceMessage) error {
+	return nil
+}
+
 func (s *mockSyncer) HandleBlockAnnounce(msg *network.BlockAnnounceMessage) *network.BlockRequestMessage {
 	if msg.Number.Cmp(s.highestSeen) > 0 {
 		s.highestSeen = msg.Number
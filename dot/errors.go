@@ -25,3 +25,9 @@ var ErrNoKeysProvided = errors.New("no keys provided for authority node")
 
 // ErrInvalidKeystoreType when trying to create a service with the wrong keystore type
 var ErrInvalidKeystoreType = errors.New("invalid keystore type")
+
+// ErrGenesisMismatch is returned when starting a node whose database was initialized with another genesis
+var ErrGenesisMismatch = errors.New("database was initialized with a different genesis")
+
+// ErrRPCDisabled is returned when calling an RPC method of a node whose rpc service is disabled
+var ErrRPCDisabled = errors.New("rpc service is disabled")
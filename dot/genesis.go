@@ -0,0 +1,65 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"os"
+
+	"github.com/ChainSafe/gossamer/chain/gssmr"
+	"github.com/ChainSafe/gossamer/chain/ksmcc"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+)
+
+// genesisPresetPaths are the paths of the genesis files in the default configurations of the chains whose genesis
+// is embedded in the binary, by chain id
+var genesisPresetPaths = map[string]string{
+	"gssmr": gssmr.DefaultGenesisRaw,
+	"ksmcc": ksmcc.DefaultGenesisRaw,
+}
+
+// GenesisPreset returns the raw genesis embedded in the binary for the given chain id or alias, eg. "gssmr"
+func GenesisPreset(id string) ([]byte, error) {
+	return genesis.GetPresetData(id)
+}
+
+// LoadGenesis returns the raw genesis of the given init configuration. It's the genesis data of the
+// configuration if it's set, otherwise the file at the configured path. The genesis files of the default
+// chain configurations are embedded in the binary, so they're used if there's no file at their default path,
+// eg. when the binary is run outside of the repository. A file at the default path is loaded instead, so that
+// changes made to it aren't ignored.
+// If the path is an http or https URL, the genesis is fetched and checked against the configured checksum.
+func LoadGenesis(cfg *InitConfig) (*genesis.Genesis, error) {
+	if len(cfg.Genesis) != 0 {
+		return genesis.NewGenesisFromJSONRawBytes(cfg.Genesis)
+	}
+
+	for id, path := range genesisPresetPaths {
+		if cfg.GenesisRaw != path {
+			continue
+		}
+
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return genesis.GetPreset(id)
+		}
+	}
+
+	if genesis.IsURL(cfg.GenesisRaw) {
+		return genesis.NewGenesisFromURL(cfg.GenesisRaw, cfg.GenesisChecksum)
+	}
+
+	return genesis.NewGenesisFromJSONRaw(cfg.GenesisRaw)
+}
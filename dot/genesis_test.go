@@ -0,0 +1,86 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/chain/gssmr"
+	"github.com/ChainSafe/gossamer/chain/ksmcc"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGenesis_Presets(t *testing.T) {
+	gen, err := LoadGenesis(&InitConfig{GenesisRaw: gssmr.DefaultGenesisRaw})
+	require.NoError(t, err)
+	require.Equal(t, "gssmr", gen.ID)
+
+	gen, err = LoadGenesis(&InitConfig{GenesisRaw: ksmcc.DefaultGenesisRaw})
+	require.NoError(t, err)
+	require.Equal(t, "ksmcc", gen.ID)
+
+	data, err := GenesisPreset("ksmcc")
+	require.NoError(t, err)
+
+	// the genesis data is used rather than the genesis file
+	gen, err = LoadGenesis(&InitConfig{GenesisRaw: gssmr.DefaultGenesisRaw, Genesis: data})
+	require.NoError(t, err)
+	require.Equal(t, "ksmcc", gen.ID)
+
+	_, err = GenesisPreset("unknown")
+	require.Error(t, err)
+}
+
+func TestLoadGenesis_PresetFileChanged(t *testing.T) {
+	file, err := genesis.CreateTestGenesisJSONFile(true)
+	require.NoError(t, err)
+	defer os.Remove(file)
+
+	defaultPath := genesisPresetPaths["gssmr"]
+	genesisPresetPaths["gssmr"] = file
+	defer func() {
+		genesisPresetPaths["gssmr"] = defaultPath
+	}()
+
+	// the file at the default path of a preset is loaded if it exists
+	gen, err := LoadGenesis(&InitConfig{GenesisRaw: file})
+	require.NoError(t, err)
+	require.Equal(t, "test", gen.Name)
+
+	err = os.Remove(file)
+	require.NoError(t, err)
+
+	gen, err = LoadGenesis(&InitConfig{GenesisRaw: file})
+	require.NoError(t, err)
+	require.Equal(t, "gssmr", gen.ID)
+}
+
+func TestLoadGenesis_File(t *testing.T) {
+	file, err := genesis.CreateTestGenesisJSONFile(true)
+	require.NoError(t, err)
+	defer os.Remove(file)
+
+	gen, err := LoadGenesis(&InitConfig{GenesisRaw: file})
+	require.NoError(t, err)
+	require.Equal(t, "test", gen.Name)
+
+	_, err = LoadGenesis(&InitConfig{GenesisRaw: file + ".missing"})
+	require.Error(t, err)
+}
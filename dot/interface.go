@@ -2,8 +2,10 @@ package dot
 
 import (
 	"math/big"
+	"net/http"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/services"
 )
@@ -16,8 +18,16 @@ type BlockProducer interface {
 	SetRuntime(runtime.LegacyInstance) error
 	Pause() error
 	Resume() error
+	Restart(*babe.RestartConfig) error
 	Authorities() []*types.Authority
 	SetAuthorities([]*types.Authority) error
 	SetRandomness([types.RandomnessLength]byte)
 	SetThreshold(*big.Int)
+	Configuration() *types.BabeConfiguration
+	CurrentEpoch() (uint64, error)
+	Descriptor() *babe.Descriptor
+	AuthorshipStats() []*babe.AuthorshipStats
+	AuthorshipMetrics() http.Handler
+	AuthorshipProof(header *types.Header) (*babe.AuthorshipProof, error)
+	EpochSchedules() ([]*babe.EpochSchedule, error)
 }
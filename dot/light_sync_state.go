@@ -0,0 +1,236 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// grandpaAuthoritiesVersion prefixes the GRANDPA authorities stored at runtime.GrandpaAuthorityDataKey
+const grandpaAuthoritiesVersion = 1
+
+// newLightSyncState returns the light sync state of the latest finalized block
+func newLightSyncState(stateSrvc *state.Service) (*genesis.LightSyncState, error) {
+	header, err := stateSrvc.Block.GetFinalizedHeader(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the trie of the finalized block may not be in memory if the node was just started
+	ts, err := stateSrvc.Storage.TrieStateCopy(&header.StateRoot)
+	if err != nil {
+		if _, err = stateSrvc.Storage.LoadFromDB(header.StateRoot); err != nil {
+			return nil, err
+		}
+
+		ts, err = stateSrvc.Storage.TrieStateCopy(&header.StateRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	epoch, err := finalizedBabeEpoch(stateSrvc.Epoch, header, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	grandpaAuths, err := grandpaAuthorities(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	// runtimes that don't store the set ID have never changed their GRANDPA authorities
+	var setID uint64
+	if enc, _ := ts.Get(runtime.GrandpaCurrentSetIDKey()); len(enc) == 8 {
+		setID = binary.LittleEndian.Uint64(enc)
+	}
+
+	return genesis.NewLightSyncState(&genesis.Checkpoint{
+		Header:             header,
+		Epoch:              epoch,
+		GrandpaSetID:       setID,
+		GrandpaAuthorities: grandpaAuths,
+	})
+}
+
+// finalizedBabeEpoch returns the BABE epoch of the given finalized block, whose state is the given trie state
+func finalizedBabeEpoch(epochState *state.EpochState, header *types.Header,
+	ts *state.TrieState) (*genesis.BabeEpoch, error) {
+	index, err := epochState.GetCurrentEpoch()
+	if err != nil {
+		return nil, err
+	}
+
+	startSlot, err := epochState.GetStartSlotForEpoch(index)
+	if err != nil {
+		return nil, err
+	}
+
+	// the finalized block may be in an earlier epoch than the best block, the genesis block has no slot
+	if slot, err := types.GetSlotFromHeader(header); err == nil {
+		for index > 1 && slot < startSlot {
+			index--
+			if startSlot, err = epochState.GetStartSlotForEpoch(index); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	info, err := epochState.GetEpochInfo(index)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := babeConfiguration(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the runtime's current authorities, or its genesis authorities if it doesn't store them
+	auths := cfg.GenesisAuthorities
+	if enc, _ := ts.Get(runtime.BABEAuthorityDataKey()); len(enc) > 0 {
+		dec, err := scale.Decode(enc, []*types.AuthorityRaw{})
+		if err != nil {
+			return nil, err
+		}
+		auths = dec.([]*types.AuthorityRaw)
+	}
+
+	babeAuths, err := types.BABEAuthorityRawToAuthority(auths)
+	if err != nil {
+		return nil, err
+	}
+
+	// the configuration doesn't tell whether secondary slots are VRF slots, so they're taken to be plain slots
+	var allowedSlots byte
+	if cfg.SecondarySlots {
+		allowedSlots = 1
+	}
+
+	return &genesis.BabeEpoch{
+		Index:       index,
+		StartSlot:   startSlot,
+		Duration:    info.Duration,
+		Authorities: babeAuths,
+		Randomness:  info.Randomness,
+		Config: genesis.BabeEpochConfig{
+			C1:           cfg.C1,
+			C2:           cfg.C2,
+			AllowedSlots: allowedSlots,
+		},
+	}, nil
+}
+
+// babeConfiguration returns the BABE configuration of the runtime in the given trie state
+func babeConfiguration(ts *state.TrieState) (*types.BabeConfiguration, error) {
+	code, err := ts.Get(common.CodeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &wasmer.Config{
+		Imports: wasmer.ImportsLegacyNodeRuntime,
+	}
+	cfg.Storage = ts
+	cfg.LogLvl = -1
+
+	rt, err := wasmer.NewLegacyInstance(code, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer rt.Stop()
+
+	return rt.BabeConfiguration()
+}
+
+// grandpaAuthorities returns the GRANDPA authorities stored in the given trie state
+func grandpaAuthorities(ts *state.TrieState) ([]*types.Authority, error) {
+	enc, err := ts.Get(runtime.GrandpaAuthorityDataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(enc) == 0 || enc[0] != grandpaAuthoritiesVersion {
+		return nil, errors.New("unknown encoding version of the GRANDPA authorities")
+	}
+
+	raw, err := scale.Decode(enc[1:], []*types.GrandpaAuthorityDataRaw{})
+	if err != nil {
+		return nil, err
+	}
+
+	return types.GrandpaAuthorityDataRawToAuthorityData(raw.([]*types.GrandpaAuthorityDataRaw))
+}
+
+// syncStateAPI builds chain specs with the light sync state of a running node. Building a chain spec copies the
+// whole state, so the specs are only rebuilt when the finalized block changes.
+type syncStateAPI struct {
+	stateSrvc *state.Service
+
+	lock      sync.Mutex
+	finalized common.Hash
+	specs     map[bool][]byte // chain specs of the finalized block, by raw format
+}
+
+// GenSyncSpec returns the chain spec of the node with the light sync state of the latest finalized block embedded
+func (s *syncStateAPI) GenSyncSpec(raw bool) ([]byte, error) {
+	finalized, err := s.stateSrvc.Block.GetFinalizedHash(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// concurrent calls wait for the spec that's being built rather than building it again
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if finalized != s.finalized || s.specs == nil {
+		s.finalized = finalized
+		s.specs = make(map[bool][]byte)
+	}
+
+	if spec, has := s.specs[raw]; has {
+		return spec, nil
+	}
+
+	bs, err := buildFromState(s.stateSrvc, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec []byte
+	if raw {
+		spec, err = bs.ToJSONRaw()
+	} else {
+		spec, err = bs.ToJSON()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.specs[raw] = spec
+	return spec, nil
+}
@@ -0,0 +1,487 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+const (
+	// authorityDiscoveryNamespace is the DHT namespace used for authority records, named after the key type
+	// that signs them
+	authorityDiscoveryNamespace = "audi"
+
+	// authorityPublishInterval is how often we re-publish our authority record
+	authorityPublishInterval = time.Hour
+
+	// authorityQueryInterval is how often we resolve the addresses of the other authorities
+	authorityQueryInterval = time.Minute * 10
+
+	// authorityQueryTimeout is the timeout for a single DHT query
+	authorityQueryTimeout = time.Second * 30
+)
+
+var (
+	errInvalidAuthorityRecord    = errors.New("invalid authority record")
+	errInvalidAuthoritySignature = errors.New("invalid authority record signature")
+)
+
+// authorityRecord is the signed record that an authority publishes to the DHT, using the authority discovery
+// layout of Substrate:
+//
+//	message AuthorityRecord { repeated bytes addresses = 1; TimestampInfo creation_time = 2; }
+//	message TimestampInfo { bytes timestamp = 1; }
+//	message SignedAuthorityRecord { bytes record = 1; bytes auth_signature = 2; }
+//
+// The record is stored under the hash of the authority's `audi` public key, which signs the encoded
+// AuthorityRecord. The timestamp is the SCALE encoded creation time as a u128 of unix nanoseconds.
+type authorityRecord struct {
+	Addresses [][]byte // encoded multiaddresses, including the /p2p/ peer ID component
+	Created   uint64   // unix time in nanoseconds, used to select the newest record
+	Signature []byte
+	payload   []byte // the AuthorityRecord as received, which is what the signature covers
+}
+
+// protobuf wire types and the field numbers of the authority discovery messages
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+
+	fieldAddresses     = 1
+	fieldCreationTime  = 2
+	fieldTimestamp     = 1
+	fieldRecord        = 1
+	fieldAuthSignature = 2
+)
+
+// newAuthorityRecord creates a new authority record for the given addresses signed with the given keypair
+func newAuthorityRecord(kp *sr25519.Keypair, addrs []ma.Multiaddr) (*authorityRecord, error) {
+	r := &authorityRecord{
+		Created: uint64(time.Now().UnixNano()),
+	}
+
+	for _, addr := range addrs {
+		r.Addresses = append(r.Addresses, addr.Bytes())
+	}
+
+	var err error
+	r.Signature, err = kp.Sign(r.signingPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// signingPayload returns the encoded AuthorityRecord covered by the record's signature
+func (r *authorityRecord) signingPayload() []byte {
+	if r.payload != nil {
+		return r.payload
+	}
+
+	buf := &bytes.Buffer{}
+	for _, addr := range r.Addresses {
+		writeProtoBytes(buf, fieldAddresses, addr)
+	}
+
+	timestamp := make([]byte, 16)
+	binary.LittleEndian.PutUint64(timestamp, r.Created)
+	info := &bytes.Buffer{}
+	writeProtoBytes(info, fieldTimestamp, timestamp)
+	writeProtoBytes(buf, fieldCreationTime, info.Bytes())
+
+	return buf.Bytes()
+}
+
+// Encode encodes the record as a SignedAuthorityRecord
+func (r *authorityRecord) Encode() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writeProtoBytes(buf, fieldRecord, r.signingPayload())
+	writeProtoBytes(buf, fieldAuthSignature, r.Signature)
+	return buf.Bytes(), nil
+}
+
+// decodeAuthorityRecord decodes an encoded SignedAuthorityRecord
+func decodeAuthorityRecord(in []byte) (*authorityRecord, error) {
+	r := new(authorityRecord)
+	err := readProtoFields(in, func(field uint64, value []byte) error {
+		switch field {
+		case fieldRecord:
+			r.payload = value
+		case fieldAuthSignature:
+			r.Signature = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.payload == nil || r.Signature == nil {
+		return nil, errInvalidAuthorityRecord
+	}
+
+	err = readProtoFields(r.payload, func(field uint64, value []byte) error {
+		switch field {
+		case fieldAddresses:
+			r.Addresses = append(r.Addresses, value)
+		case fieldCreationTime:
+			return readProtoFields(value, func(field uint64, value []byte) error {
+				if field != fieldTimestamp {
+					return nil
+				}
+
+				// the creation time is a u128, which doesn't overflow a uint64 for the foreseeable future
+				if len(value) != 16 {
+					return errInvalidAuthorityRecord
+				}
+				r.Created = binary.LittleEndian.Uint64(value)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// writeProtoBytes writes a length-delimited protobuf field
+func writeProtoBytes(buf *bytes.Buffer, field uint64, value []byte) {
+	writeUvarint(buf, field<<3|wireBytes)
+	writeUvarint(buf, uint64(len(value)))
+	_, _ = buf.Write(value)
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	enc := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(enc, v)
+	_, _ = buf.Write(enc[:n])
+}
+
+// readProtoFields calls the given function for each length-delimited field of the protobuf message, other fields
+// are skipped
+func readProtoFields(msg []byte, fn func(field uint64, value []byte) error) error {
+	r := bytes.NewReader(msg)
+	for r.Len() > 0 {
+		tag, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errInvalidAuthorityRecord
+		}
+
+		switch tag & 7 {
+		case wireVarint:
+			_, err = binary.ReadUvarint(r)
+		case wireFixed64:
+			_, err = r.Seek(8, io.SeekCurrent)
+		case wireFixed32:
+			_, err = r.Seek(4, io.SeekCurrent)
+		case wireBytes:
+			var n uint64
+			n, err = binary.ReadUvarint(r)
+			if err != nil || n > uint64(r.Len()) {
+				return errInvalidAuthorityRecord
+			}
+
+			value := make([]byte, n)
+			_, _ = r.Read(value)
+			err = fn(tag>>3, value)
+		default:
+			return errInvalidAuthorityRecord
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verify checks that the record is signed by the authority with the given public key
+func (r *authorityRecord) verify(key []byte) error {
+	pub, err := sr25519.NewPublicKey(key)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pub.Verify(r.signingPayload(), r.Signature)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errInvalidAuthoritySignature
+	}
+
+	return nil
+}
+
+// addrInfo returns the peer address info contained in the record
+func (r *authorityRecord) addrInfo() (*peer.AddrInfo, error) {
+	addrs := []ma.Multiaddr{}
+	for _, b := range r.Addresses {
+		addr, err := ma.NewMultiaddrBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+
+	infos, err := peer.AddrInfosFromP2pAddrs(addrs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(infos) != 1 {
+		return nil, errors.New("authority record must contain addresses for exactly one peer")
+	}
+
+	return &infos[0], nil
+}
+
+// authorityRecordKey returns the DHT key for the authority with the given public key, which is the sha2-256
+// multihash of the key as in Substrate
+func authorityRecordKey(pub []byte) string {
+	h := sha256.Sum256(pub)
+	return "/" + authorityDiscoveryNamespace + "/" + string(append([]byte{0x12, 0x20}, h[:]...))
+}
+
+// authorityRecordValidator validates authority records stored in the DHT (implements record.Validator). The DHT
+// key is a hash of the authority's public key, so signatures are checked when a record is looked up instead.
+type authorityRecordValidator struct{}
+
+// Validate checks that the value is a well formed authority record
+func (authorityRecordValidator) Validate(key string, value []byte) error {
+	_, err := decodeAuthorityRecord(value)
+	return err
+}
+
+// Select returns the index of the most recently created valid record
+func (v authorityRecordValidator) Select(key string, values [][]byte) (int, error) {
+	best := -1
+	var created uint64
+
+	for i, value := range values {
+		r, err := decodeAuthorityRecord(value)
+		if err != nil {
+			continue
+		}
+
+		if best == -1 || r.Created > created {
+			best = i
+			created = r.Created
+		}
+	}
+
+	if best == -1 {
+		return 0, errors.New("no valid authority records")
+	}
+
+	return best, nil
+}
+
+// authorityDiscovery submodule publishes our authority record to the DHT when we are an authority and resolves
+// the addresses of the other authorities in the current authority set
+type authorityDiscovery struct {
+	logger      log.Logger
+	host        *host
+	keypair     *sr25519.Keypair // nil if we are not an authority
+	authorities AuthorityState
+
+	sync.RWMutex
+	peers map[peer.ID][]byte // discovered authority peers, mapped to their public keys
+}
+
+// newAuthorityDiscovery creates a new authority discovery instance from the host
+func newAuthorityDiscovery(host *host, kp *sr25519.Keypair, authorities AuthorityState) *authorityDiscovery {
+	return &authorityDiscovery{
+		logger:      logger.New("module", "authority-discovery"),
+		host:        host,
+		keypair:     kp,
+		authorities: authorities,
+		peers:       make(map[peer.ID][]byte),
+	}
+}
+
+// start begins publishing and resolving authority records until the host context is cancelled
+func (d *authorityDiscovery) start() {
+	if d.keypair == nil && d.authorities == nil {
+		return
+	}
+
+	go d.run()
+}
+
+func (d *authorityDiscovery) run() {
+	publishTicker := time.NewTicker(authorityPublishInterval)
+	defer publishTicker.Stop()
+	queryTicker := time.NewTicker(authorityQueryInterval)
+	defer queryTicker.Stop()
+
+	// wait for the DHT routing table to be populated before the first publish and query
+	select {
+	case <-time.After(time.Second * 10):
+	case <-d.host.ctx.Done():
+		return
+	}
+
+	d.publish()
+	d.resolve()
+
+	for {
+		select {
+		case <-publishTicker.C:
+			d.publish()
+		case <-queryTicker.C:
+			d.resolve()
+		case <-d.host.ctx.Done():
+			return
+		}
+	}
+}
+
+// publish signs our current listening addresses and stores the record in the DHT
+func (d *authorityDiscovery) publish() {
+	if d.keypair == nil {
+		return
+	}
+
+	r, err := newAuthorityRecord(d.keypair, d.host.multiaddrs())
+	if err != nil {
+		d.logger.Error("failed to create authority record", "error", err)
+		return
+	}
+
+	enc, err := r.Encode()
+	if err != nil {
+		d.logger.Error("failed to encode authority record", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.host.ctx, authorityQueryTimeout)
+	defer cancel()
+
+	err = d.host.dht.PutValue(ctx, authorityRecordKey(d.keypair.Public().Encode()), enc)
+	if err != nil {
+		d.logger.Debug("failed to publish authority record", "error", err)
+		return
+	}
+
+	d.logger.Debug("published authority record", "addresses", len(r.Addresses))
+}
+
+// resolve looks up the records of the other authorities in the current authority set and connects to them
+func (d *authorityDiscovery) resolve() {
+	if d.authorities == nil {
+		return
+	}
+
+	var own []byte
+	if d.keypair != nil {
+		own = d.keypair.Public().Encode()
+	}
+
+	keys, err := d.authorities.AuthorityDiscoveryKeys()
+	if err != nil {
+		d.logger.Debug("failed to get authority discovery keys", "error", err)
+		return
+	}
+
+	peers := make(map[peer.ID][]byte)
+	for _, pub := range keys {
+		key := pub.Encode()
+		if bytes.Equal(key, own) {
+			continue
+		}
+
+		info, err := d.lookup(key)
+		if err != nil {
+			d.logger.Trace("failed to resolve authority", "authority", common.BytesToHex(key), "error", err)
+			continue
+		}
+
+		if info.ID == d.host.id() {
+			continue
+		}
+
+		peers[info.ID] = key
+		d.host.h.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.AddressTTL)
+
+		if !d.host.peerConnected(info.ID) {
+			err = d.host.connect(*info)
+			if err != nil {
+				d.logger.Trace("failed to connect to authority", "peer", info.ID, "error", err)
+			}
+		}
+	}
+
+	d.Lock()
+	d.peers = peers
+	d.Unlock()
+
+	d.logger.Debug("resolved authorities", "count", len(peers))
+}
+
+// lookup gets and verifies the record for the authority with the given public key
+func (d *authorityDiscovery) lookup(pub []byte) (*peer.AddrInfo, error) {
+	ctx, cancel := context.WithTimeout(d.host.ctx, authorityQueryTimeout)
+	defer cancel()
+
+	val, err := d.host.dht.GetValue(ctx, authorityRecordKey(pub))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decodeAuthorityRecord(val)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.verify(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.addrInfo()
+}
+
+// isAuthority returns true if the peer was discovered as one of the current authorities
+func (d *authorityDiscovery) isAuthority(p peer.ID) bool {
+	d.RLock()
+	defer d.RUnlock()
+	_, has := d.peers[p]
+	return has
+}
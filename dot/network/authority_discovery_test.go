@@ -0,0 +1,126 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthorityRecord(t *testing.T, kp *sr25519.Keypair, id peer.ID) *authorityRecord {
+	addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/7001/p2p/%s", id))
+	require.NoError(t, err)
+
+	r, err := newAuthorityRecord(kp, []ma.Multiaddr{addr})
+	require.NoError(t, err)
+	return r
+}
+
+func TestAuthorityRecord_EncodeDecode(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	id := peer.ID("QmWCPBrMiLs8sHoSpYSvhyumNfJhDLA2aXrg8uQ8cXRFgC")
+	r := newTestAuthorityRecord(t, kp, id)
+	require.NoError(t, r.verify(kp.Public().Encode()))
+
+	enc, err := r.Encode()
+	require.NoError(t, err)
+
+	res, err := decodeAuthorityRecord(enc)
+	require.NoError(t, err)
+	require.Equal(t, r.Addresses, res.Addresses)
+	require.Equal(t, r.Created, res.Created)
+	require.Equal(t, r.Signature, res.Signature)
+	require.Equal(t, r.signingPayload(), res.signingPayload())
+	require.NoError(t, res.verify(kp.Public().Encode()))
+
+	// the record is only valid for the authority that signed it
+	other, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	require.Equal(t, errInvalidAuthoritySignature, res.verify(other.Public().Encode()))
+
+	info, err := res.addrInfo()
+	require.NoError(t, err)
+	require.Equal(t, id, info.ID)
+	require.Equal(t, 1, len(info.Addrs))
+}
+
+func TestAuthorityRecordValidator_Validate(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	r := newTestAuthorityRecord(t, kp, peer.ID("QmWCPBrMiLs8sHoSpYSvhyumNfJhDLA2aXrg8uQ8cXRFgC"))
+	enc, err := r.Encode()
+	require.NoError(t, err)
+
+	v := authorityRecordValidator{}
+	key := authorityRecordKey(kp.Public().Encode())
+	require.NoError(t, v.Validate(key, enc))
+
+	err = v.Validate(key, []byte{1, 2, 3})
+	require.Equal(t, errInvalidAuthorityRecord, err)
+
+	// record with a creation time that wasn't signed by the authority
+	r.Created++
+	enc, err = r.Encode()
+	require.NoError(t, err)
+	res, err := decodeAuthorityRecord(enc)
+	require.NoError(t, err)
+	require.Equal(t, errInvalidAuthoritySignature, res.verify(kp.Public().Encode()))
+}
+
+func TestAuthorityRecordKey(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	// the key is the sha2-256 multihash of the public key
+	key := authorityRecordKey(kp.Public().Encode())
+	require.Equal(t, len("/audi/")+34, len(key))
+	require.Equal(t, "/audi/\x12\x20", key[:8])
+}
+
+func TestAuthorityRecordValidator_Select(t *testing.T) {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	id := peer.ID("QmWCPBrMiLs8sHoSpYSvhyumNfJhDLA2aXrg8uQ8cXRFgC")
+	older := newTestAuthorityRecord(t, kp, id)
+	newer := newTestAuthorityRecord(t, kp, id)
+	require.True(t, newer.Created > older.Created)
+
+	olderEnc, err := older.Encode()
+	require.NoError(t, err)
+	newerEnc, err := newer.Encode()
+	require.NoError(t, err)
+
+	v := authorityRecordValidator{}
+	key := authorityRecordKey(kp.Public().Encode())
+
+	idx, err := v.Select(key, [][]byte{olderEnc, newerEnc, {1, 2, 3}})
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+
+	_, err = v.Select(key, [][]byte{{1, 2, 3}})
+	require.Error(t, err)
+}
@@ -20,13 +20,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/scale"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
+var errPeerSetsFull = errors.New("no room for peer in the peer sets of its roles")
+
+// maxInvalidBlockAnnounces is the number of invalid block announcements a peer may send before we disconnect from it
+const maxInvalidBlockAnnounces = 3
+
+// invalidBlockAnnounces counts the invalid block announcements received from each peer
+type invalidBlockAnnounces struct {
+	sync.Mutex
+	counts map[peer.ID]int
+}
+
 func decodeBlockAnnounceHandshake(r io.Reader) (Handshake, error) {
 	sd := scale.Decoder{Reader: r}
 	hs := new(BlockAnnounceHandshake)
@@ -99,15 +112,20 @@ func (s *Service) getBlockAnnounceHandshake() (Handshake, error) {
 	}, nil
 }
 
-func (s *Service) validateBlockAnnounceHandshake(hs Handshake) error {
-	if _, ok := hs.(*BlockAnnounceHandshake); !ok {
+func (s *Service) validateBlockAnnounceHandshake(p peer.ID, hs Handshake) error {
+	bhs, ok := hs.(*BlockAnnounceHandshake)
+	if !ok {
 		return errors.New("invalid handshake type")
 	}
 
-	if hs.(*BlockAnnounceHandshake).GenesisHash != s.blockState.GenesisHash() {
+	if bhs.GenesisHash != s.blockState.GenesisHash() {
 		return errors.New("genesis hash mismatch")
 	}
 
+	if !s.admitPeer(p, bhs.Roles) {
+		return errPeerSetsFull
+	}
+
 	return nil
 }
 
@@ -116,10 +134,26 @@ func (s *Service) validateBlockAnnounceHandshake(hs Handshake) error {
 // with its peer and send a BlockRequest message
 func (s *Service) handleBlockAnnounceMessage(peer peer.ID, msg Message) error {
 	if an, ok := msg.(*BlockAnnounceMessage); ok {
+		err := s.syncer.ValidateBlockAnnounce(an)
+		if err != nil {
+			logger.Debug("rejected BlockAnnounce", "peer", peer, "error", err)
+			s.reportInvalidBlockAnnounce(peer)
+			return nil
+		}
+
+		if s.blockObserver != nil {
+			s.observeBlockAnnounce(an)
+		}
+
+		// only sync from the peers in the sync peer set
+		if !s.host.peerSets.allowed(syncPeers, peer) {
+			return nil
+		}
+
 		req := s.syncer.HandleBlockAnnounce(an)
 		if req != nil {
 			s.requestTracker.addRequestedBlockID(req.ID)
-			err := s.host.send(peer, syncID, req)
+			err = s.host.send(peer, syncID, req)
 			if err != nil {
 				logger.Error("failed to send BlockRequest message", "peer", peer)
 			}
@@ -128,3 +162,35 @@ func (s *Service) handleBlockAnnounceMessage(peer peer.ID, msg Message) error {
 
 	return nil
 }
+
+// observeBlockAnnounce notifies the block observer of the arrival of the announced block
+func (s *Service) observeBlockAnnounce(an *BlockAnnounceMessage) {
+	header, err := types.NewHeader(an.ParentHash, an.Number, an.StateRoot, an.ExtrinsicsRoot, an.Digest)
+	if err != nil {
+		return
+	}
+
+	s.blockObserver.BlockAnnounced(header)
+}
+
+// reportInvalidBlockAnnounce records an invalid block announcement from the peer, and disconnects from the peer
+// if it has sent maxInvalidBlockAnnounces invalid announcements
+func (s *Service) reportInvalidBlockAnnounce(p peer.ID) {
+	s.invalidBlockAnnounces.Lock()
+	s.invalidBlockAnnounces.counts[p]++
+	count := s.invalidBlockAnnounces.counts[p]
+	if count >= maxInvalidBlockAnnounces {
+		delete(s.invalidBlockAnnounces.counts, p)
+	}
+	s.invalidBlockAnnounces.Unlock()
+
+	if count < maxInvalidBlockAnnounces {
+		return
+	}
+
+	logger.Debug("disconnecting from peer sending invalid block announcements", "peer", p, "count", count)
+	err := s.host.closePeer(p)
+	if err != nil {
+		logger.Debug("failed to close connection to peer", "peer", p, "error", err)
+	}
+}
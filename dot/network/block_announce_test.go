@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/utils"
 
@@ -53,7 +54,7 @@ func TestDecodeBlockAnnounceMessage(t *testing.T) {
 		Number:         big.NewInt(77),
 		StateRoot:      common.Hash{2},
 		ExtrinsicsRoot: common.Hash{3},
-		Digest:         [][]byte{},
+		Digest:         types.NewEmptyDigest(),
 	}
 
 	enc, err := testBlockAnnounce.Encode()
@@ -92,3 +93,31 @@ func TestHandleBlockAnnounceMessage(t *testing.T) {
 	s.handleBlockAnnounceMessage(peerID, msg)
 	require.True(t, s.requestTracker.hasRequestedBlockID(99))
 }
+
+func TestReportInvalidBlockAnnounce(t *testing.T) {
+	basePath := utils.NewTestBasePath(t, "nodeA")
+
+	// removes all data directories created within test directory
+	defer utils.RemoveTestDir(t)
+
+	config := &Config{
+		BasePath:    basePath,
+		Port:        7001,
+		RandSeed:    1,
+		NoBootstrap: true,
+		NoMDNS:      true,
+		NoStatus:    true,
+	}
+
+	s := createTestService(t, config)
+
+	peerID := peer.ID("noot")
+	for i := 1; i < maxInvalidBlockAnnounces; i++ {
+		s.reportInvalidBlockAnnounce(peerID)
+		require.Equal(t, i, s.invalidBlockAnnounces.counts[peerID])
+	}
+
+	// the count is reset once we disconnect from the peer
+	s.reportInvalidBlockAnnounce(peerID)
+	require.Equal(t, 0, s.invalidBlockAnnounces.counts[peerID])
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+	"github.com/ChainSafe/gossamer/lib/common/variadic"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// bodyRequestTimeout is how long to wait for a peer to respond to a block body request
+var bodyRequestTimeout = 5 * time.Second
+
+// ErrBodyUnavailable is returned when none of the connected peers returned the requested block body
+var ErrBodyUnavailable = errors.New("block body not available from any peer")
+
+// RequestBlockBody requests the body of the given block from the connected peers, one at a time, until one
+// of them returns it. It's used to fetch block bodies on demand when only headers are stored.
+func (s *Service) RequestBlockBody(hash common.Hash) (*types.Body, error) {
+	start, err := variadic.NewUint64OrHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range s.host.peers() {
+		req := &BlockRequestMessage{
+			ID:            rand.Uint64(), //nolint
+			RequestedData: RequestedDataBody,
+			StartingBlock: start,
+			EndBlockHash:  optional.NewHash(true, hash),
+			Direction:     1,
+			Max:           optional.NewUint32(true, 1),
+		}
+
+		body, err := s.requestBlockBody(p, req, hash)
+		if err != nil {
+			logger.Debug("failed to fetch block body from peer", "peer", p, "hash", hash, "error", err)
+			continue
+		}
+
+		return body, nil
+	}
+
+	return nil, ErrBodyUnavailable
+}
+
+func (s *Service) requestBlockBody(p peer.ID, req *BlockRequestMessage, hash common.Hash) (*types.Body, error) {
+	respCh := make(chan *BlockResponseMessage, 1)
+	s.bodyRequests.Store(req.ID, respCh)
+	defer s.bodyRequests.Delete(req.ID)
+
+	err := s.host.send(p, syncID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respCh:
+		for _, bd := range resp.BlockData {
+			if bd.Hash == hash && bd.Body != nil && bd.Body.Exists {
+				return types.NewBodyFromOptional(bd.Body)
+			}
+		}
+		return nil, errors.New("response does not contain block body")
+	case <-time.After(bodyRequestTimeout):
+		return nil, errors.New("timeout")
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// handleBodyResponse forwards a BlockResponse to the pending block body request with the same ID, if any
+func (s *Service) handleBodyResponse(resp *BlockResponseMessage) bool {
+	ch, has := s.bodyRequests.Load(resp.ID)
+	if !has {
+		return false
+	}
+
+	select {
+	case ch.(chan *BlockResponseMessage) <- resp:
+	default:
+	}
+
+	return true
+}
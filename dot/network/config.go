@@ -18,12 +18,16 @@ package network
 
 import (
 	"errors"
+	"fmt"
 	"path"
 	"strconv"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
 	log "github.com/ChainSafe/log15"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 // DefaultKeyFile the default value for KeyFile
@@ -47,6 +51,15 @@ const DefaultProtocolVersion = 0
 // DefaultRoles the default value for Config.Roles (0 = no network, 1 = full node)
 const DefaultRoles = byte(1)
 
+// DefaultMaxSyncPeers the default value for Config.MaxSyncPeers
+const DefaultMaxSyncPeers = 5
+
+// DefaultMaxTransactionPeers the default value for Config.MaxTransactionPeers
+const DefaultMaxTransactionPeers = 5
+
+// DefaultMaxLightPeers the default value for Config.MaxLightPeers
+const DefaultMaxLightPeers = 2
+
 // DefaultBootnodes the default value for Config.Bootnodes
 var DefaultBootnodes = []string(nil)
 
@@ -68,8 +81,17 @@ type Config struct {
 
 	Syncer Syncer
 
+	// AuthorityState the source of the authorities' `audi` keys, used to resolve the addresses of other authorities
+	AuthorityState AuthorityState
+	// AuthorityKey the `audi` keypair used to sign our authority discovery record (nil if we are not an authority)
+	AuthorityKey *sr25519.Keypair
+
 	// Port the network port used for listening
 	Port uint32
+	// ListenAddrs the multiaddresses to listen on, eg. /ip6/::/tcp/7001 or /ip4/0.0.0.0/tcp/7002/ws. DNS
+	// multiaddresses can't be listened on and are only advertised to peers. If empty, the node listens on Port
+	// on all IPv4 interfaces.
+	ListenAddrs []string
 	// RandSeed the seed used to generate the network p2p identity (0 = non-deterministic random seed)
 	RandSeed int64
 	// Bootnodes the peer addresses used for bootstrapping
@@ -87,10 +109,28 @@ type Config struct {
 	// NoStatus disables the status message exchange protocol
 	NoStatus bool
 
+	// MaxSyncPeers the maximum number of full nodes that we sync from and serve blocks to
+	MaxSyncPeers int
+	// MaxTransactionPeers the maximum number of full nodes that we gossip transactions with
+	MaxTransactionPeers int
+	// MaxLightPeers the maximum number of light clients that we serve, they can't use the slots of full nodes
+	MaxLightPeers int
+
+	// DisplayName, Location and Contact are operator labels that are signed with the node's network key
+	DisplayName string
+	Location    string
+	Contact     string
+
 	MessageHandler MessageHandler
 
+	// Faults injects network faults for testing, it must be nil outside of tests
+	Faults *Faults
+
 	// privateKey the private key for the network p2p identity
 	privateKey crypto.PrivKey
+	// listenAddrs and announceAddrs are the parsed ListenAddrs
+	listenAddrs   []ma.Multiaddr
+	announceAddrs []ma.Multiaddr
 }
 
 // build checks the configuration, sets up the private key for the network service,
@@ -115,6 +155,23 @@ func (c *Config) build() error {
 		c.Port = DefaultPort
 	}
 
+	if c.MaxSyncPeers == 0 {
+		c.MaxSyncPeers = DefaultMaxSyncPeers
+	}
+
+	if c.MaxTransactionPeers == 0 {
+		c.MaxTransactionPeers = DefaultMaxTransactionPeers
+	}
+
+	if c.MaxLightPeers == 0 {
+		c.MaxLightPeers = DefaultMaxLightPeers
+	}
+
+	err = c.buildListenAddrs()
+	if err != nil {
+		return err
+	}
+
 	// build identity configuration
 	err = c.buildIdentity()
 	if err != nil {
@@ -148,6 +205,53 @@ func (c *Config) checkState() (err error) {
 	return err
 }
 
+// buildListenAddrs parses the configured listen addresses, separating the addresses to listen on from the DNS
+// addresses that are only advertised
+func (c *Config) buildListenAddrs() error {
+	c.listenAddrs = nil
+	c.announceAddrs = nil
+
+	if len(c.ListenAddrs) == 0 {
+		addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", c.Port))
+		if err != nil {
+			return err
+		}
+
+		c.listenAddrs = []ma.Multiaddr{addr}
+		return nil
+	}
+
+	for _, s := range c.ListenAddrs {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return fmt.Errorf("invalid listen address %q: %w", s, err)
+		}
+
+		if isDNSAddr(addr) {
+			c.announceAddrs = append(c.announceAddrs, addr)
+			continue
+		}
+
+		c.listenAddrs = append(c.listenAddrs, addr)
+	}
+
+	if len(c.listenAddrs) == 0 {
+		return errors.New("no listen address that isn't a DNS address")
+	}
+
+	return nil
+}
+
+// isDNSAddr returns true if the multiaddress is resolved using DNS, ie. it has a dns, dns4, dns6 or dnsaddr component
+func isDNSAddr(addr ma.Multiaddr) bool {
+	for _, p := range addr.Protocols() {
+		if strings.HasPrefix(p.Name, "dns") {
+			return true
+		}
+	}
+	return false
+}
+
 // buildIdentity attempts to load the private key required to start the network
 // service, if a key does not exist or cannot be loaded, it creates a new key
 // using the random seed (if random seed is not set, creates new random key)
@@ -207,7 +311,14 @@ func (c *Config) buildProtocol() error {
 		c.ProtocolID = DefaultProtocolID
 	}
 
-	if c.ProtocolVersion == 0 {
+	// Substrate chain specs use a bare protocol id such as "dot", which namespaces the protocols as eg. "/dot/sync/2"
+	// and doesn't include a protocol version
+	bare := !strings.HasPrefix(c.ProtocolID, "/")
+	if bare {
+		c.ProtocolID = "/" + c.ProtocolID
+	}
+
+	if c.ProtocolVersion == 0 && !bare {
 		s := strings.Split(c.ProtocolID, "/")
 		// expecting the default protocol format ("/gossamer/gssmr/0")
 		if len(s) != 4 {
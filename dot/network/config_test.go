@@ -114,4 +114,60 @@ func TestBuild(t *testing.T) {
 	require.Equal(t, DefaultProtocolID, cfg.ProtocolID)
 	require.Equal(t, false, cfg.NoBootstrap)
 	require.Equal(t, false, cfg.NoMDNS)
+	require.Equal(t, DefaultMaxSyncPeers, cfg.MaxSyncPeers)
+	require.Equal(t, DefaultMaxTransactionPeers, cfg.MaxTransactionPeers)
+	require.Equal(t, DefaultMaxLightPeers, cfg.MaxLightPeers)
+}
+
+func TestBuildProtocol_ChainSpecID(t *testing.T) {
+	cfg := &Config{
+		logger:     log.New("srvc", "NET"),
+		ProtocolID: "dot",
+	}
+
+	err := cfg.buildProtocol()
+	require.NoError(t, err)
+	require.Equal(t, "/dot", cfg.ProtocolID)
+	require.Equal(t, uint32(DefaultProtocolVersion), cfg.ProtocolVersion)
+
+	cfg = &Config{
+		logger:     log.New("srvc", "NET"),
+		ProtocolID: "/gossamer/test/2",
+	}
+
+	err = cfg.buildProtocol()
+	require.NoError(t, err)
+	require.Equal(t, "/gossamer/test/2", cfg.ProtocolID)
+	require.Equal(t, uint32(2), cfg.ProtocolVersion)
+}
+
+func TestBuildListenAddrs(t *testing.T) {
+	cfg := &Config{
+		Port: 7001,
+	}
+
+	err := cfg.buildListenAddrs()
+	require.NoError(t, err)
+	require.Len(t, cfg.listenAddrs, 1)
+	require.Equal(t, "/ip4/0.0.0.0/tcp/7001", cfg.listenAddrs[0].String())
+	require.Empty(t, cfg.announceAddrs)
+
+	cfg.ListenAddrs = []string{
+		"/ip4/0.0.0.0/tcp/7001",
+		"/ip6/::/tcp/7001",
+		"/ip4/0.0.0.0/tcp/7002/ws",
+		"/dns4/node.example.com/tcp/7001",
+	}
+
+	err = cfg.buildListenAddrs()
+	require.NoError(t, err)
+	require.Len(t, cfg.listenAddrs, 3)
+	require.Len(t, cfg.announceAddrs, 1)
+	require.Equal(t, "/dns4/node.example.com/tcp/7001", cfg.announceAddrs[0].String())
+
+	cfg.ListenAddrs = []string{"/dns4/node.example.com/tcp/7001"}
+	require.Error(t, cfg.buildListenAddrs())
+
+	cfg.ListenAddrs = []string{"0.0.0.0:7001"}
+	require.Error(t, cfg.buildListenAddrs())
 }
@@ -19,6 +19,7 @@ package network
 import (
 	"context"
 	"math/rand"
+	"sync"
 
 	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/network"
@@ -27,9 +28,17 @@ import (
 	ma "github.com/multiformats/go-multiaddr"
 )
 
+// authorityReservedSlotsPercent is the percentage of peer slots reserved for other authorities when we are an authority
+const authorityReservedSlotsPercent = 40
+
 // ConnManager implements connmgr.ConnManager
 type ConnManager struct {
 	max int // maximum number of peers
+
+	sync.RWMutex
+	reserved    int                // number of peer slots that only authorities may use
+	isAuthority func(peer.ID) bool // returns true if the peer is a known authority
+	peerSets    *peerSets          // light clients are disconnected before full nodes
 }
 
 func newConnManager(max int) *ConnManager {
@@ -38,6 +47,65 @@ func newConnManager(max int) *ConnManager {
 	}
 }
 
+// reserveAuthoritySlots reserves a portion of the peer slots for peers that isAuthority reports as authorities
+func (cm *ConnManager) reserveAuthoritySlots(isAuthority func(peer.ID) bool) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.reserved = cm.max * authorityReservedSlotsPercent / 100
+	cm.isAuthority = isAuthority
+}
+
+// setPeerSets sets the peer sets that are updated when peers disconnect, and used to find light clients
+func (cm *ConnManager) setPeerSets(ps *peerSets) {
+	cm.Lock()
+	defer cm.Unlock()
+
+	cm.peerSets = ps
+}
+
+// peerToEvict returns a peer to disconnect from if the given peers exceed the peer limits. Non-authority peers
+// may not use the reserved authority slots, and are always disconnected before authorities. Light clients are
+// disconnected before full nodes, so that they can't take the slots of the peers we sync from.
+func (cm *ConnManager) peerToEvict(peers []peer.ID) (peer.ID, bool) {
+	cm.RLock()
+	defer cm.RUnlock()
+
+	others := peers
+	if cm.isAuthority != nil {
+		others = []peer.ID{}
+		for _, p := range peers {
+			if !cm.isAuthority(p) {
+				others = append(others, p)
+			}
+		}
+	}
+
+	if len(peers) <= cm.max && len(others) <= cm.max-cm.reserved {
+		return "", false
+	}
+
+	candidates := peers
+	if len(others) > 0 {
+		candidates = others
+	}
+
+	if cm.peerSets != nil {
+		light := []peer.ID{}
+		for _, p := range candidates {
+			if cm.peerSets.isLight(p) {
+				light = append(light, p)
+			}
+		}
+
+		if len(light) > 0 {
+			candidates = light
+		}
+	}
+
+	return candidates[rand.Intn(len(candidates))], true
+}
+
 // Notifee is used to monitor changes to a connection
 func (cm *ConnManager) Notifee() network.Notifiee {
 	nb := new(network.NotifyBundle)
@@ -107,13 +175,11 @@ func (cm *ConnManager) Connected(n network.Network, c network.Conn) {
 		"peer", c.RemotePeer(),
 	)
 
-	if len(n.Peers()) > cm.max {
-		i := rand.Intn(len(n.Peers()))
-		peers := n.Peers()
-		logger.Trace("Over max peer count, disconnecting from random peer", "peer", peers[i])
-		err := n.ClosePeer(peers[i])
+	if p, ok := cm.peerToEvict(n.Peers()); ok {
+		logger.Trace("Over max peer count, disconnecting from random peer", "peer", p)
+		err := n.ClosePeer(p)
 		if err != nil {
-			logger.Debug("failed to close connection to peer", "peer", peers[i], "num peers", len(n.Peers()))
+			logger.Debug("failed to close connection to peer", "peer", p, "num peers", len(n.Peers()))
 		}
 	}
 }
@@ -125,6 +191,14 @@ func (cm *ConnManager) Disconnected(n network.Network, c network.Conn) {
 		"host", c.LocalPeer(),
 		"peer", c.RemotePeer(),
 	)
+
+	// free the peer's slots once its last connection is closed
+	cm.RLock()
+	ps := cm.peerSets
+	cm.RUnlock()
+	if ps != nil && n.Connectedness(c.RemotePeer()) != network.Connected {
+		ps.remove(c.RemotePeer())
+	}
 }
 
 // OpenedStream is called when a stream opened
@@ -19,6 +19,7 @@ package network
 import (
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/require"
 )
@@ -55,3 +56,55 @@ func TestMaxPeers(t *testing.T) {
 	p := nodes[0].host.h.Peerstore().Peers()
 	require.LessOrEqual(t, defaultMaxPeerCount, len(p))
 }
+
+func TestPeerToEvict_ReservedAuthoritySlots(t *testing.T) {
+	cm := newConnManager(5)
+
+	authorities := map[peer.ID]bool{"a1": true, "a2": true, "a3": true}
+	cm.reserveAuthoritySlots(func(p peer.ID) bool {
+		return authorities[p]
+	})
+	require.Equal(t, 2, cm.reserved)
+
+	// non-authority peers may only use the unreserved slots
+	_, ok := cm.peerToEvict([]peer.ID{"p1", "p2", "p3"})
+	require.False(t, ok)
+
+	p, ok := cm.peerToEvict([]peer.ID{"p1", "p2", "p3", "p4"})
+	require.True(t, ok)
+	require.False(t, authorities[p])
+
+	// authorities can use the reserved slots
+	_, ok = cm.peerToEvict([]peer.ID{"p1", "p2", "p3", "a1", "a2"})
+	require.False(t, ok)
+
+	// a non-authority is disconnected when another authority connects
+	p, ok = cm.peerToEvict([]peer.ID{"p1", "p2", "p3", "a1", "a2", "a3"})
+	require.True(t, ok)
+	require.False(t, authorities[p])
+}
+
+func TestPeerToEvict_LightClientsFirst(t *testing.T) {
+	cm := newConnManager(2)
+	ps := newPeerSets(2, 2, 2)
+	cm.setPeerSets(ps)
+
+	require.True(t, ps.setRoles("full", types.FullNodeRole))
+	require.True(t, ps.setRoles("light", types.LightClientRole))
+
+	for i := 0; i < 10; i++ {
+		p, ok := cm.peerToEvict([]peer.ID{"full", "light", "unknown"})
+		require.True(t, ok)
+		require.Equal(t, peer.ID("light"), p)
+	}
+}
+
+func TestPeerToEvict_NoReservedSlots(t *testing.T) {
+	cm := newConnManager(2)
+
+	_, ok := cm.peerToEvict([]peer.ID{"p1", "p2"})
+	require.False(t, ok)
+
+	_, ok = cm.peerToEvict([]peer.ID{"p1", "p2", "p3"})
+	require.True(t, ok)
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Faults simulates a faulty or byzantine network between in-process test nodes. Messages sent to peers may be
+// corrupted, delayed or duplicated, and messages to and from partitioned peers are dropped. It's only meant to
+// be used by tests, so consensus and sync can be exercised under adversarial conditions.
+type Faults struct {
+	lock        sync.Mutex
+	rand        *rand.Rand
+	corrupt     float64 // probability that a sent message has a byte flipped
+	duplicate   float64 // probability that a sent message is sent twice
+	delay       time.Duration
+	partitioned map[peer.ID]struct{}
+}
+
+// NewFaults returns a Faults that doesn't inject any faults until configured. The seed makes the injected
+// faults reproducible.
+func NewFaults(seed int64) *Faults {
+	return &Faults{
+		rand:        rand.New(rand.NewSource(seed)), //nolint
+		partitioned: make(map[peer.ID]struct{}),
+	}
+}
+
+// SetCorruption sets the probability in [0, 1] that a sent message is corrupted
+func (f *Faults) SetCorruption(p float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.corrupt = p
+}
+
+// SetDuplication sets the probability in [0, 1] that a sent message is sent twice
+func (f *Faults) SetDuplication(p float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.duplicate = p
+}
+
+// SetDelay sets the delay before each message is sent
+func (f *Faults) SetDelay(d time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.delay = d
+}
+
+// Partition cuts the node off from the given peers, messages to and from them are dropped
+func (f *Faults) Partition(peers ...peer.ID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, p := range peers {
+		f.partitioned[p] = struct{}{}
+	}
+}
+
+// Heal removes all partitions
+func (f *Faults) Heal() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.partitioned = make(map[peer.ID]struct{})
+}
+
+// isPartitioned returns true if the node is partitioned from the given peer
+func (f *Faults) isPartitioned(p peer.ID) bool {
+	if f == nil {
+		return false
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	_, has := f.partitioned[p]
+	return has
+}
+
+// outbound applies the configured faults to a message sent to the given peer. It returns the messages to send in
+// its place, which is none if the peer is partitioned, and how long to wait before sending them.
+func (f *Faults) outbound(p peer.ID, msg []byte) ([][]byte, time.Duration) {
+	if f == nil {
+		return [][]byte{msg}, 0
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, has := f.partitioned[p]; has {
+		return nil, 0
+	}
+
+	if len(msg) > 0 && f.rand.Float64() < f.corrupt {
+		corrupted := make([]byte, len(msg))
+		copy(corrupted, msg)
+		corrupted[f.rand.Intn(len(msg))] ^= 0xff
+		msg = corrupted
+	}
+
+	msgs := [][]byte{msg}
+	if f.rand.Float64() < f.duplicate {
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, f.delay
+}
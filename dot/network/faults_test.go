@@ -0,0 +1,114 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/utils"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaults_Outbound(t *testing.T) {
+	var nilFaults *Faults
+	msgs, delay := nilFaults.outbound("a", []byte{1, 2, 3})
+	require.Equal(t, [][]byte{{1, 2, 3}}, msgs)
+	require.Zero(t, delay)
+	require.False(t, nilFaults.isPartitioned("a"))
+
+	f := NewFaults(1)
+	msgs, delay = f.outbound("a", []byte{1, 2, 3})
+	require.Equal(t, [][]byte{{1, 2, 3}}, msgs)
+	require.Zero(t, delay)
+
+	msg := []byte{1, 2, 3}
+	f.SetCorruption(1)
+	f.SetDuplication(1)
+	f.SetDelay(time.Millisecond)
+	msgs, delay = f.outbound("a", msg)
+	require.Len(t, msgs, 2)
+	require.NotEqual(t, msg, msgs[0])
+	require.Equal(t, []byte{1, 2, 3}, msg, "the original message must not be modified")
+	require.Equal(t, msgs[0], msgs[1])
+	require.Equal(t, time.Millisecond, delay)
+
+	f.Partition(peer.ID("a"))
+	require.True(t, f.isPartitioned("a"))
+	require.False(t, f.isPartitioned("b"))
+	msgs, _ = f.outbound("a", msg)
+	require.Empty(t, msgs)
+
+	f.Heal()
+	require.False(t, f.isPartitioned("a"))
+}
+
+func TestSend_Partitioned(t *testing.T) {
+	faults := NewFaults(1)
+	configA := &Config{
+		BasePath:    utils.NewTestBasePath(t, "nodeA"),
+		Port:        7001,
+		RandSeed:    1,
+		NoBootstrap: true,
+		NoMDNS:      true,
+		Faults:      faults,
+	}
+
+	nodeA := createTestService(t, configA)
+	nodeA.noGossip = true
+	nodeA.noStatus = true
+
+	mmh := new(MockMessageHandler)
+	configB := &Config{
+		BasePath:       utils.NewTestBasePath(t, "nodeB"),
+		Port:           7002,
+		RandSeed:       2,
+		NoBootstrap:    true,
+		NoMDNS:         true,
+		MessageHandler: mmh,
+	}
+
+	nodeB := createTestService(t, configB)
+	nodeB.noGossip = true
+	nodeB.noStatus = true
+
+	addrInfosB, err := nodeB.host.addrInfos()
+	require.NoError(t, err)
+
+	err = nodeA.host.connect(*addrInfosB[0])
+	// retry connect if "failed to dial" error
+	if failedToDial(err) {
+		time.Sleep(TestBackoffTimeout)
+		err = nodeA.host.connect(*addrInfosB[0])
+	}
+	require.NoError(t, err)
+
+	faults.Partition(addrInfosB[0].ID)
+	err = nodeA.host.send(addrInfosB[0].ID, "", TestMessage)
+	require.NoError(t, err)
+
+	time.Sleep(TestMessageTimeout)
+	require.Nil(t, mmh.Message)
+
+	faults.Heal()
+	err = nodeA.host.send(addrInfosB[0].ID, "", TestMessage)
+	require.NoError(t, err)
+
+	time.Sleep(TestMessageTimeout)
+	require.Equal(t, TestMessage, mmh.Message)
+}
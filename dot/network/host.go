@@ -18,7 +18,9 @@ package network
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"time"
 
 	ds "github.com/ipfs/go-datastore"
 	dsync "github.com/ipfs/go-datastore/sync"
@@ -41,8 +43,11 @@ type host struct {
 	ctx        context.Context
 	h          libp2phost.Host
 	dht        *kaddht.IpfsDHT
+	cm         *ConnManager
+	peerSets   *peerSets
 	bootnodes  []peer.AddrInfo
 	protocolID protocol.ID
+	faults     *Faults
 }
 
 // newHost creates a host wrapper with a new libp2p host instance
@@ -51,18 +56,21 @@ func newHost(ctx context.Context, cfg *Config) (*host, error) {
 	// use "p2p" for multiaddress format
 	ma.SwapToP2pMultiaddrs()
 
-	// create multiaddress (without p2p identity)
-	addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", cfg.Port))
-	if err != nil {
-		return nil, err
-	}
-
 	// create connection manager
 	cm := newConnManager(defaultMaxPeerCount)
+	ps := newPeerSets(cfg.MaxSyncPeers, cfg.MaxTransactionPeers, cfg.MaxLightPeers)
+	cm.setPeerSets(ps)
+
+	// the DNS addresses that can't be listened on are advertised along with the listening addresses
+	announce := cfg.announceAddrs
+	addrsFactory := func(addrs []ma.Multiaddr) []ma.Multiaddr {
+		return append(addrs, announce...)
+	}
 
 	// set libp2p host options
 	opts := []libp2p.Option{
-		libp2p.ListenAddrs(addr),
+		libp2p.ListenAddrs(cfg.listenAddrs...),
+		libp2p.AddrsFactory(addrsFactory),
 		libp2p.DisableRelay(),
 		libp2p.Identity(cfg.privateKey),
 		libp2p.NATPortMap(),
@@ -76,8 +84,16 @@ func newHost(ctx context.Context, cfg *Config) (*host, error) {
 		return nil, err
 	}
 
-	// create DHT service
-	dht := kaddht.NewDHT(ctx, h, dsync.MutexWrap(ds.NewMapDatastore()))
+	// create DHT service, including the validator for authority discovery records
+	dht, err := kaddht.New(
+		ctx,
+		h,
+		kaddht.Datastore(dsync.MutexWrap(ds.NewMapDatastore())),
+		kaddht.NamespacedValidator(authorityDiscoveryNamespace, authorityRecordValidator{}),
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	// wrap host and DHT service with routed host
 	h = rhost.Wrap(h, dht)
@@ -95,8 +111,11 @@ func newHost(ctx context.Context, cfg *Config) (*host, error) {
 		ctx:        ctx,
 		h:          h,
 		dht:        dht,
+		cm:         cm,
+		peerSets:   ps,
 		bootnodes:  bns,
 		protocolID: pid,
+		faults:     cfg.Faults,
 	}, nil
 
 }
@@ -172,6 +191,15 @@ func (h *host) send(p peer.ID, sub protocol.ID, msg Message) (err error) {
 }
 
 func (h *host) sendBytes(p peer.ID, sub protocol.ID, msg []byte) (err error) {
+	msgs, delay := h.faults.outbound(p, msg)
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
 	// get outbound stream for given peer
 	s := h.getStream(p, sub)
 
@@ -192,17 +220,28 @@ func (h *host) sendBytes(p peer.ID, sub protocol.ID, msg []byte) (err error) {
 		)
 	}
 
-	msgLen := uint64(len(msg))
-	lenBytes := uint64ToLEB128(msgLen)
-	msg = append(lenBytes, msg...)
+	// frame the message with its length in a pooled buffer, so that it is written with a single call
+	// without copying the message into a newly allocated slice
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	_, err = s.Write(msg)
+	for _, msg := range msgs {
+		buf.Grow(binary.MaxVarintLen64 + len(msg))
+		_, _ = buf.Write(uint64ToLEB128(uint64(len(msg))))
+		_, _ = buf.Write(msg)
+	}
+
+	_, err = s.Write(buf.Bytes())
 	return err
 }
 
-// broadcast sends a message to each connected peer
+// broadcast sends a message to each connected peer, transactions are only sent to the transactions peer set
 func (h *host) broadcast(msg Message) {
 	for _, p := range h.peers() {
+		if !h.accepts(p, msg) {
+			continue
+		}
+
 		err := h.send(p, "", msg)
 		if err != nil {
 			logger.Error("Failed to broadcast message to peer", "peer", p, "error", err)
@@ -210,10 +249,11 @@ func (h *host) broadcast(msg Message) {
 	}
 }
 
-// broadcastExcluding sends a message to each connected peer except specified peer
+// broadcastExcluding sends a message to each connected peer except specified peer, transactions are only sent to
+// the transactions peer set
 func (h *host) broadcastExcluding(msg Message, peer peer.ID) {
 	for _, p := range h.peers() {
-		if p != peer {
+		if p != peer && h.accepts(p, msg) {
 			err := h.send(p, "", msg)
 			if err != nil {
 				logger.Error("Failed to send message during broadcast", "peer", p, "err", err)
@@ -222,6 +262,14 @@ func (h *host) broadcastExcluding(msg Message, peer peer.ID) {
 	}
 }
 
+// accepts returns false if the message belongs to a protocol that isn't used with the peer
+func (h *host) accepts(p peer.ID, msg Message) bool {
+	if msg.Type() == TransactionMsgType {
+		return h.peerSets.allowed(transactionPeers, p)
+	}
+	return true
+}
+
 // getStream returns the outbound message stream for the given peer or returns
 // nil if no outbound message stream exists. For each peer, each host opens an
 // outbound message stream and writes to the same stream until closed or reset.
@@ -0,0 +1,100 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"errors"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+var errInvalidIdentitySignature = errors.New("invalid node identity signature")
+
+// newNodeIdentity returns the node identity with the given labels signed with the node's network key
+func newNodeIdentity(key crypto.PrivKey, id peer.ID, name, location, contact string) (*common.NodeIdentity, error) {
+	ni := &common.NodeIdentity{
+		PeerID:      id.String(),
+		DisplayName: name,
+		Location:    location,
+		Contact:     contact,
+	}
+
+	msg, err := identitySigningPayload(ni)
+	if err != nil {
+		return nil, err
+	}
+
+	ni.Signature, err = key.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return ni, nil
+}
+
+// identitySigningPayload returns the encoded data covered by the identity's signature
+func identitySigningPayload(ni *common.NodeIdentity) ([]byte, error) {
+	return scale.Encode(&struct {
+		PeerID      []byte
+		DisplayName []byte
+		Location    []byte
+		Contact     []byte
+	}{
+		PeerID:      []byte(ni.PeerID),
+		DisplayName: []byte(ni.DisplayName),
+		Location:    []byte(ni.Location),
+		Contact:     []byte(ni.Contact),
+	})
+}
+
+// VerifyNodeIdentity checks that the identity is signed by the network key of the peer it belongs to
+func VerifyNodeIdentity(ni *common.NodeIdentity) error {
+	id, err := peer.Decode(ni.PeerID)
+	if err != nil {
+		return err
+	}
+
+	pub, err := id.ExtractPublicKey()
+	if err != nil {
+		return err
+	}
+
+	msg, err := identitySigningPayload(ni)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pub.Verify(msg, ni.Signature)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errInvalidIdentitySignature
+	}
+
+	return nil
+}
+
+// NodeIdentity returns the signed operator identity of the node, or nil if no labels are configured
+func (s *Service) NodeIdentity() *common.NodeIdentity {
+	return s.identity
+}
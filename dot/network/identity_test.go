@@ -0,0 +1,54 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeIdentity_Verify(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	key, err := generateKey(1, testDir)
+	require.NoError(t, err)
+
+	id, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+
+	ni, err := newNodeIdentity(key, id, "validator-1", "eu-west", "ops@example.com")
+	require.NoError(t, err)
+	require.Equal(t, id.String(), ni.PeerID)
+	require.NoError(t, VerifyNodeIdentity(ni))
+
+	// labels that weren't signed by the node
+	ni.DisplayName = "validator-2"
+	require.Equal(t, errInvalidIdentitySignature, VerifyNodeIdentity(ni))
+
+	// identity signed by another node
+	other, err := generateKey(2, testDir)
+	require.NoError(t, err)
+
+	ni, err = newNodeIdentity(other, id, "validator-1", "eu-west", "ops@example.com")
+	require.NoError(t, err)
+	require.Equal(t, errInvalidIdentitySignature, VerifyNodeIdentity(ni))
+}
@@ -32,7 +32,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 )
 
-//nolint
+// nolint
 const (
 	StatusMsgType             byte = 0
 	BlockRequestMsgType       byte = 1
@@ -93,13 +93,7 @@ func decodeMessage(r io.Reader) (m Message, err error) {
 
 // decodeMessageBytes decodes the message based on message type
 func decodeMessageBytes(in []byte, _ peer.ID) (m Message, err error) {
-	r := &bytes.Buffer{}
-	_, err = r.Write(in)
-	if err != nil {
-		return nil, err
-	}
-
-	return decodeMessage(r)
+	return decodeMessage(bytes.NewReader(in))
 }
 
 // StatusMessage struct
@@ -156,6 +150,10 @@ func (sm *StatusMessage) IsHandshake() bool {
 	return false
 }
 
+// blockRequestMessageMaxSize is the size of an encoded BlockRequestMessage with every optional field set
+// and a hash as the starting block
+const blockRequestMessageMaxSize = 1 + 8 + 1 + 33 + 33 + 1 + 5
+
 // BlockRequestMessage for optionals, if first byte is 0, then it is None
 // otherwise it is Some
 type BlockRequestMessage struct {
@@ -200,11 +198,12 @@ func (bm *BlockRequestMessage) String() string {
 
 // Encode encodes a block request message using SCALE and appends the type byte to the start
 func (bm *BlockRequestMessage) Encode() ([]byte, error) {
-	encMsg := []byte{BlockRequestMsgType}
+	encMsg := make([]byte, 0, blockRequestMessageMaxSize)
+	encMsg = append(encMsg, BlockRequestMsgType)
 
-	encID := make([]byte, 8)
-	binary.LittleEndian.PutUint64(encID, bm.ID)
-	encMsg = append(encMsg, encID...)
+	var encID [8]byte
+	binary.LittleEndian.PutUint64(encID[:], bm.ID)
+	encMsg = append(encMsg, encID[:]...)
 
 	encMsg = append(encMsg, bm.RequestedData)
 
@@ -218,7 +217,8 @@ func (bm *BlockRequestMessage) Encode() ([]byte, error) {
 		encMsg = append(encMsg, []byte{0, 0}...)
 	} else {
 		val := bm.EndBlockHash.Value()
-		encMsg = append(encMsg, append([]byte{1}, val[:]...)...)
+		encMsg = append(encMsg, 1)
+		encMsg = append(encMsg, val[:]...)
 	}
 
 	encMsg = append(encMsg, bm.Direction)
@@ -226,9 +226,10 @@ func (bm *BlockRequestMessage) Encode() ([]byte, error) {
 	if !bm.Max.Exists() {
 		encMsg = append(encMsg, []byte{0, 0}...)
 	} else {
-		max := make([]byte, 4)
-		binary.LittleEndian.PutUint32(max, bm.Max.Value())
-		encMsg = append(encMsg, append([]byte{1}, max...)...)
+		var max [4]byte
+		binary.LittleEndian.PutUint32(max[:], bm.Max.Value())
+		encMsg = append(encMsg, 1)
+		encMsg = append(encMsg, max[:]...)
 	}
 
 	return encMsg, nil
@@ -313,7 +314,7 @@ type BlockAnnounceMessage struct {
 	Number         *big.Int
 	StateRoot      common.Hash
 	ExtrinsicsRoot common.Hash
-	Digest         [][]byte // any additional block info eg. logs, seal
+	Digest         types.Digest // any additional block info eg. logs, seal
 }
 
 // Type returns BlockAnnounceMsgType
@@ -323,7 +324,7 @@ func (bm *BlockAnnounceMessage) Type() byte {
 
 // string formats a BlockAnnounceMessage as a string
 func (bm *BlockAnnounceMessage) String() string {
-	return fmt.Sprintf("BlockAnnounceMessage ParentHash=0x%x Number=%d StateRoot=0x%x ExtrinsicsRoot=0x%x Digest=0x%x",
+	return fmt.Sprintf("BlockAnnounceMessage ParentHash=0x%x Number=%d StateRoot=0x%x ExtrinsicsRoot=0x%x Digest=%s",
 		bm.ParentHash,
 		bm.Number,
 		bm.StateRoot,
@@ -384,17 +385,15 @@ func (bm *BlockResponseMessage) String() string {
 
 // Encode encodes a block response message using SCALE and appends the type byte to the start
 func (bm *BlockResponseMessage) Encode() ([]byte, error) {
-	encMsg := []byte{BlockResponseMsgType}
-
-	encID := make([]byte, 8)
-	binary.LittleEndian.PutUint64(encID, bm.ID)
-	encMsg = append(encMsg, encID...)
-
 	encData, err := types.EncodeBlockDataArray(bm.BlockData)
 	if err != nil {
 		return nil, err
 	}
 
+	// the block data is the bulk of the message, allocate the output once instead of growing it
+	encMsg := make([]byte, 9, 9+len(encData))
+	encMsg[0] = BlockResponseMsgType
+	binary.LittleEndian.PutUint64(encMsg[1:9], bm.ID)
 	return append(encMsg, encData...), nil
 }
 
@@ -437,21 +436,25 @@ func (tm *TransactionMessage) String() string {
 
 // Encode will encode TransactionMessage using scale.Encode
 func (tm *TransactionMessage) Encode() ([]byte, error) {
-	// scale encode each extrinsic
-	var encodedExtrinsics = make([]byte, 0)
+	// scale encode each extrinsic into a pooled scratch buffer
+	scratch := getBuffer()
+	defer putBuffer(scratch)
+
+	se := scale.Encoder{Writer: scratch}
 	for _, extrinsic := range tm.Extrinsics {
-		encExt, err := scale.Encode([]byte(extrinsic))
-		if err != nil {
+		if _, err := se.Encode([]byte(extrinsic)); err != nil {
 			return nil, err
 		}
-		encodedExtrinsics = append(encodedExtrinsics, encExt...)
 	}
 
-	// scale encode the set of all extrinsics
-	encodedMessage, err := scale.Encode(encodedExtrinsics)
+	// prepend message type and scale encode the set of all extrinsics, the length prefix is at most
+	// 5 bytes for messages under 4GiB
+	out := bytes.NewBuffer(make([]byte, 0, 1+5+scratch.Len()))
+	_ = out.WriteByte(TransactionMsgType)
 
-	// prepend message type to message
-	return append([]byte{TransactionMsgType}, encodedMessage...), err
+	se = scale.Encoder{Writer: out}
+	_, err := se.Encode(scratch.Bytes())
+	return out.Bytes(), err
 }
 
 // Decode the message into a TransactionMessage, it assumes the type byte han been removed
@@ -515,7 +518,8 @@ func (cm *ConsensusMessage) String() string {
 
 // Encode encodes a block response message using SCALE and appends the type byte to the start
 func (cm *ConsensusMessage) Encode() ([]byte, error) {
-	encMsg := []byte{ConsensusMsgType}
+	encMsg := make([]byte, 0, 1+len(cm.ConsensusEngineID)+len(cm.Data))
+	encMsg = append(encMsg, ConsensusMsgType)
 	encMsg = append(encMsg, cm.ConsensusEngineID.ToBytes()...)
 	return append(encMsg, cm.Data...), nil
 }
@@ -403,10 +403,10 @@ func TestEncodeBlockAnnounceMessage(t *testing.T) {
 	//	Number: *big.Int // block number: 1
 	//	StateRoot:  Hash: 0xb3266de137d20a5d0ff3a6401eb57127525fd9b2693701f0bf5a8a853fa3ebe0
 	//	ExtrinsicsRoot: Hash: 0x03170a2e7597b7b7e3d84c05391d139a62b157e78786d8c082f29dcf4c111314
-	//	Digest: []byte
+	//	Digest: Digest
 
 	//                                    mtparenthash                                                      bnstateroot                                                       extrinsicsroot                                                  di
-	expected, err := common.HexToBytes("0x454545454545454545454545454545454545454545454545454545454545454504b3266de137d20a5d0ff3a6401eb57127525fd9b2693701f0bf5a8a853fa3ebe003170a2e7597b7b7e3d84c05391d139a62b157e78786d8c082f29dcf4c1113140400")
+	expected, err := common.HexToBytes("0x454545454545454545454545454545454545454545454545454545454545454504b3266de137d20a5d0ff3a6401eb57127525fd9b2693701f0bf5a8a853fa3ebe003170a2e7597b7b7e3d84c05391d139a62b157e78786d8c082f29dcf4c111314040108")
 	require.Nil(t, err)
 
 	parentHash, err := common.HexToHash("0x4545454545454545454545454545454545454545454545454545454545454545")
@@ -423,7 +423,7 @@ func TestEncodeBlockAnnounceMessage(t *testing.T) {
 		Number:         big.NewInt(1),
 		StateRoot:      stateRoot,
 		ExtrinsicsRoot: extrinsicsRoot,
-		Digest:         [][]byte{{}},
+		Digest:         types.NewDigest(&types.RuntimeEnvironmentUpdatedDigest{}),
 	}
 	encMsg, err := bhm.Encode()
 	require.Nil(t, err)
@@ -457,7 +457,7 @@ func TestDecode_BlockAnnounceMessage(t *testing.T) {
 		Number:         big.NewInt(1),
 		StateRoot:      stateRoot,
 		ExtrinsicsRoot: extrinsicsRoot,
-		Digest:         [][]byte{},
+		Digest:         types.NewEmptyDigest(),
 	}
 
 	require.Equal(t, expected, bhm)
@@ -574,3 +574,85 @@ func TestDecodeConsensusMessage(t *testing.T) {
 	require.Equal(t, encMsg, encodedMessage[1:])
 
 }
+
+func newBenchmarkBlockResponseMessage() *BlockResponseMessage {
+	bds := make([]*types.BlockData, 128)
+	for i := range bds {
+		header := &optional.CoreHeader{
+			ParentHash:     common.Hash{byte(i)},
+			Number:         big.NewInt(int64(i)),
+			StateRoot:      common.Hash{0x1},
+			ExtrinsicsRoot: common.Hash{0x2},
+			Digest:         [][]byte{make([]byte, 64)},
+		}
+
+		bds[i] = &types.BlockData{
+			Hash:          common.Hash{byte(i)},
+			Header:        optional.NewHeader(true, header),
+			Body:          optional.NewBody(true, make([]byte, 512)),
+			Receipt:       optional.NewBytes(false, nil),
+			MessageQueue:  optional.NewBytes(false, nil),
+			Justification: optional.NewBytes(false, nil),
+		}
+	}
+
+	return &BlockResponseMessage{
+		ID:        1,
+		BlockData: bds,
+	}
+}
+
+func BenchmarkBlockResponseMessage_Encode(b *testing.B) {
+	bm := newBenchmarkBlockResponseMessage()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := bm.Encode()
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkBlockResponseMessage_Decode(b *testing.B) {
+	enc, err := newBenchmarkBlockResponseMessage().Encode()
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := decodeMessageBytes(enc, "")
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkBlockRequestMessage_Encode(b *testing.B) {
+	bm := &BlockRequestMessage{
+		ID:            1,
+		RequestedData: 3,
+		StartingBlock: variadic.NewUint64OrHashFromBytes([]byte{1, 1, 0, 0, 0, 0, 0, 0, 0}),
+		EndBlockHash:  optional.NewHash(true, common.Hash{0x1}),
+		Direction:     1,
+		Max:           optional.NewUint32(true, 128),
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := bm.Encode()
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkTransactionMessage_Encode(b *testing.B) {
+	tm := &TransactionMessage{}
+	for i := 0; i < 64; i++ {
+		tm.Extrinsics = append(tm.Extrinsics, make([]byte, 256))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := tm.Encode()
+		require.NoError(b, err)
+	}
+}
@@ -40,8 +40,8 @@ type (
 	// HandshakeDecoder is a custom decoder for a handshake
 	HandshakeDecoder = func(io.Reader) (Handshake, error)
 
-	// HandshakeValidator validates a handshake. It returns an error if it is invalid
-	HandshakeValidator = func(Handshake) error
+	// HandshakeValidator validates a handshake received from the peer. It returns an error if it is invalid
+	HandshakeValidator = func(peer.ID, Handshake) error
 
 	// MessageDecoder is a custom decoder for a message
 	MessageDecoder = func(io.Reader) (Message, error)
@@ -64,11 +64,7 @@ type handshakeData struct {
 
 func createDecoder(info *notificationsProtocol, handshakeDecoder HandshakeDecoder, messageDecoder MessageDecoder) messageDecoder {
 	return func(in []byte, peer peer.ID) (Message, error) {
-		r := &bytes.Buffer{}
-		_, err := r.Write(in)
-		if err != nil {
-			return nil, err
-		}
+		r := bytes.NewReader(in)
 
 		// if we don't have handshake data on this peer, or we haven't received the handshake from them already,
 		// assume we are receiving the handshake
@@ -98,7 +94,7 @@ func (s *Service) createNotificationsMessageHandler(info *notificationsProtocol,
 			// if we are the receiver and haven't received the handshake already, validate it
 			if _, has := info.handshakeData[peer]; !has {
 				logger.Trace("receiver: validating handshake", "sub-protocol", info.subProtocol)
-				err := handshakeValidator(hs)
+				err := handshakeValidator(peer, hs)
 				if err != nil {
 					logger.Error("failed to validate handshake", "sub-protocol", info.subProtocol, "peer", peer, "error", err)
 					info.handshakeData[peer] = &handshakeData{
@@ -131,7 +127,7 @@ func (s *Service) createNotificationsMessageHandler(info *notificationsProtocol,
 			// if we are the initiator and haven't received the handshake already, validate it
 			if hsData, has := info.handshakeData[peer]; has && !hsData.validated {
 				logger.Trace("sender: validating handshake")
-				err := handshakeValidator(hs)
+				err := handshakeValidator(peer, hs)
 				if err != nil {
 					logger.Error("failed to validate handshake", "sub-protocol", info.subProtocol, "peer", peer, "error", err)
 					// TODO: also delete on stream close
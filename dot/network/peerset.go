@@ -0,0 +1,183 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// peerSet is a group of peers that a protocol is used with, each peer set has its own limit so that the peers of
+// one protocol can't take the slots of another
+type peerSet int
+
+const (
+	// syncPeers are the full nodes that we sync from and serve blocks to
+	syncPeers peerSet = iota
+	// transactionPeers are the full nodes that we gossip transactions with
+	transactionPeers
+	// lightPeers are the light clients that we serve blocks to
+	lightPeers
+
+	numPeerSets
+)
+
+// String returns the name of the peer set
+func (s peerSet) String() string {
+	switch s {
+	case syncPeers:
+		return "sync"
+	case transactionPeers:
+		return "transactions"
+	case lightPeers:
+		return "light"
+	default:
+		return "unknown"
+	}
+}
+
+// peerSets tracks the roles that the peers reported in their handshakes, and the peer sets they are members of
+type peerSets struct {
+	sync.RWMutex
+	limits  [numPeerSets]int
+	members [numPeerSets]map[peer.ID]struct{}
+	roles   map[peer.ID]byte
+}
+
+func newPeerSets(maxSync, maxTransaction, maxLight int) *peerSets {
+	ps := &peerSets{
+		roles: make(map[peer.ID]byte),
+	}
+
+	ps.limits[syncPeers] = maxSync
+	ps.limits[transactionPeers] = maxTransaction
+	ps.limits[lightPeers] = maxLight
+
+	for i := range ps.members {
+		ps.members[i] = make(map[peer.ID]struct{})
+	}
+
+	return ps
+}
+
+// isLightClient returns true if the roles are those of a light client
+func isLightClient(roles byte) bool {
+	return roles&types.LightClientRole != 0 && roles&(types.FullNodeRole|types.AuthorityRole) == 0
+}
+
+// setRoles records the roles the peer reported in a handshake, and adds it to the peer sets of its roles that have
+// room for it. Light clients are only members of the light peer set, full nodes and authorities may be members of
+// the sync and transactions peer sets. It returns false if the peer isn't a member of any peer set.
+func (ps *peerSets) setRoles(p peer.ID, roles byte) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if r, has := ps.roles[p]; has && r == roles && ps.isMember(p) {
+		return true
+	}
+
+	ps.removePeer(p)
+	ps.roles[p] = roles
+
+	if isLightClient(roles) {
+		ps.join(lightPeers, p)
+	} else {
+		ps.join(syncPeers, p)
+		ps.join(transactionPeers, p)
+	}
+
+	return ps.isMember(p)
+}
+
+func (ps *peerSets) join(set peerSet, p peer.ID) {
+	if len(ps.members[set]) < ps.limits[set] {
+		ps.members[set][p] = struct{}{}
+	}
+}
+
+func (ps *peerSets) isMember(p peer.ID) bool {
+	for _, m := range ps.members {
+		if _, has := m[p]; has {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns true if the peer is a member of the peer set. Peers whose roles aren't known yet aren't assigned
+// to peer sets, and are allowed in all of them.
+func (ps *peerSets) allowed(set peerSet, p peer.ID) bool {
+	ps.RLock()
+	defer ps.RUnlock()
+
+	if _, has := ps.roles[p]; !has {
+		return true
+	}
+
+	_, has := ps.members[set][p]
+	return has
+}
+
+// isLight returns true if the peer reported the roles of a light client
+func (ps *peerSets) isLight(p peer.ID) bool {
+	ps.RLock()
+	defer ps.RUnlock()
+
+	roles, has := ps.roles[p]
+	return has && isLightClient(roles)
+}
+
+// size returns the number of members of the peer set
+func (ps *peerSets) size(set peerSet) int {
+	ps.RLock()
+	defer ps.RUnlock()
+
+	return len(ps.members[set])
+}
+
+// remove removes the peer from all peer sets, freeing its slots
+func (ps *peerSets) remove(p peer.ID) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.removePeer(p)
+}
+
+func (ps *peerSets) removePeer(p peer.ID) {
+	delete(ps.roles, p)
+	for _, m := range ps.members {
+		delete(m, p)
+	}
+}
+
+// admitPeer records the roles the peer reported in a handshake, and disconnects from it if there's no room for it
+// in the peer sets of its roles. It returns false if the peer was disconnected.
+func (s *Service) admitPeer(p peer.ID, roles byte) bool {
+	if s.host.peerSets.setRoles(p, roles) {
+		return true
+	}
+
+	logger.Debug("peer sets are full, disconnecting from peer", "peer", p, "roles", roles)
+	err := s.host.closePeer(p)
+	if err != nil {
+		logger.Debug("failed to close connection to peer", "peer", p, "error", err)
+	}
+	return false
+}
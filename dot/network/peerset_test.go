@@ -0,0 +1,89 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerSets_LightClientsDontUseSyncSlots(t *testing.T) {
+	ps := newPeerSets(2, 3, 1)
+
+	require.True(t, ps.setRoles("light1", types.LightClientRole))
+	require.False(t, ps.setRoles("light2", types.LightClientRole))
+	require.Equal(t, 1, ps.size(lightPeers))
+	require.Equal(t, 0, ps.size(syncPeers))
+	require.Equal(t, 0, ps.size(transactionPeers))
+
+	// full nodes still get the sync slots
+	require.True(t, ps.setRoles("full1", types.FullNodeRole))
+	require.True(t, ps.setRoles("auth1", types.AuthorityRole))
+	require.Equal(t, 2, ps.size(syncPeers))
+	require.True(t, ps.allowed(syncPeers, "full1"))
+	require.False(t, ps.allowed(syncPeers, "light1"))
+	require.False(t, ps.allowed(transactionPeers, "light1"))
+	require.True(t, ps.allowed(lightPeers, "light1"))
+}
+
+func TestPeerSets_IndependentLimits(t *testing.T) {
+	ps := newPeerSets(1, 2, 1)
+
+	require.True(t, ps.setRoles("full1", types.FullNodeRole))
+	// the sync peer set is full, but there's room in the transactions peer set
+	require.True(t, ps.setRoles("full2", types.FullNodeRole))
+	require.False(t, ps.allowed(syncPeers, "full2"))
+	require.True(t, ps.allowed(transactionPeers, "full2"))
+
+	// no room left for full nodes
+	require.False(t, ps.setRoles("full3", types.FullNodeRole))
+
+	// a disconnected peer frees its slots
+	ps.remove("full1")
+	require.True(t, ps.setRoles("full3", types.FullNodeRole))
+	require.True(t, ps.allowed(syncPeers, "full3"))
+}
+
+func TestPeerSets_UnknownRoles(t *testing.T) {
+	ps := newPeerSets(1, 1, 1)
+
+	// peers whose roles aren't known yet aren't restricted
+	p := peer.ID("unknown")
+	for set := peerSet(0); set < numPeerSets; set++ {
+		require.True(t, ps.allowed(set, p), set)
+	}
+	require.False(t, ps.isLight(p))
+}
+
+func TestPeerSets_SetRolesTwice(t *testing.T) {
+	ps := newPeerSets(1, 1, 1)
+
+	// the roles are reported in both the status message and the block announce handshake
+	require.True(t, ps.setRoles("full", types.FullNodeRole))
+	require.True(t, ps.setRoles("full", types.FullNodeRole))
+	require.Equal(t, 1, ps.size(syncPeers))
+
+	// a peer that changes its roles moves to the peer sets of its new roles
+	require.True(t, ps.setRoles("full", types.LightClientRole))
+	require.Equal(t, 0, ps.size(syncPeers))
+	require.Equal(t, 1, ps.size(lightPeers))
+	require.True(t, ps.isLight("full"))
+}
@@ -22,10 +22,12 @@ import (
 	"errors"
 	"math/big"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/services"
+	"github.com/ChainSafe/gossamer/lib/trace"
 
 	log "github.com/ChainSafe/log15"
 	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
@@ -64,11 +66,15 @@ type Service struct {
 	cfg                    *Config
 	host                   *host
 	mdns                   *mdns
+	authorityDiscovery     *authorityDiscovery
 	status                 *status
 	gossip                 *gossip
 	requestTracker         *requestTracker
+	bodyRequests           sync.Map // pending block body request ID -> chan *BlockResponseMessage
 	errCh                  chan<- error
 	notificationsProtocols map[byte]*notificationsProtocol // map of sub-protocol msg ID to protocol info
+	invalidBlockAnnounces  *invalidBlockAnnounces
+	identity               *common.NodeIdentity
 
 	// Service interfaces
 	blockState   BlockState
@@ -77,6 +83,8 @@ type Service struct {
 
 	// Interface for inter-process communication
 	messageHandler MessageHandler
+	// blockObserver is notified of valid block announcements, may be nil
+	blockObserver BlockObserver
 
 	// Configuration options
 	noBootstrap bool
@@ -110,12 +118,20 @@ func NewService(cfg *Config) (*Service, error) {
 		return nil, err
 	}
 
+	ad := newAuthorityDiscovery(host, cfg.AuthorityKey, cfg.AuthorityState)
+
+	// when we are an authority, keep peer slots free for the other authorities
+	if cfg.AuthorityKey != nil {
+		host.cm.reserveAuthoritySlots(ad.isAuthority)
+	}
+
 	network := &Service{
 		ctx:                    ctx,
 		cancel:                 cancel,
 		cfg:                    cfg,
 		host:                   host,
 		mdns:                   newMDNS(host),
+		authorityDiscovery:     ad,
 		status:                 newStatus(host),
 		gossip:                 newGossip(host),
 		requestTracker:         newRequestTracker(logger),
@@ -128,6 +144,14 @@ func NewService(cfg *Config) (*Service, error) {
 		syncer:                 cfg.Syncer,
 		errCh:                  cfg.ErrChan,
 		notificationsProtocols: make(map[byte]*notificationsProtocol),
+		invalidBlockAnnounces:  &invalidBlockAnnounces{counts: make(map[peer.ID]int)},
+	}
+
+	if cfg.DisplayName != "" || cfg.Location != "" || cfg.Contact != "" {
+		network.identity, err = newNodeIdentity(cfg.privateKey, host.id(), cfg.DisplayName, cfg.Location, cfg.Contact)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return network, err
@@ -176,6 +200,8 @@ func (s *Service) Start() error {
 		s.mdns.start()
 	}
 
+	s.authorityDiscovery.start()
+
 	return nil
 }
 
@@ -355,7 +381,12 @@ func (s *Service) readStream(stream libp2pnetwork.Stream, peer peer.ID, decoder
 	// create buffer stream for non-blocking read
 	r := bufio.NewReader(stream)
 
+	// scratch space reused for every message read from the stream, decoders copy what they keep
+	scratch := make([]byte, defaultBufferSize)
+
 	for {
+		msgBytes := scratch
+
 		length, err := readLEB128ToUint64(r)
 		if err != nil {
 			logger.Error("Failed to read LEB128 encoding", "error", err)
@@ -368,7 +399,16 @@ func (s *Service) readStream(stream libp2pnetwork.Stream, peer peer.ID, decoder
 			continue
 		}
 
-		msgBytes := make([]byte, length)
+		if length > uint64(cap(scratch)) {
+			buf := make([]byte, length)
+			// don't keep oversized buffers alive for the lifetime of the stream
+			if length <= maxPooledBufferSize {
+				scratch = buf
+			}
+			msgBytes = buf
+		}
+		msgBytes = msgBytes[:length]
+
 		tot := uint64(0)
 		for i := 0; i < maxReads; i++ {
 			n, err := r.Read(msgBytes[tot:]) //nolint
@@ -390,6 +430,11 @@ func (s *Service) readStream(stream libp2pnetwork.Stream, peer peer.ID, decoder
 			continue
 		}
 
+		// messages from peers we're partitioned from are dropped
+		if s.host.faults.isPartitioned(peer) {
+			continue
+		}
+
 		// decode message based on message type
 		msg, err := decoder(msgBytes, peer)
 		if err != nil {
@@ -405,7 +450,13 @@ func (s *Service) readStream(stream libp2pnetwork.Stream, peer peer.ID, decoder
 		)
 
 		// handle message based on peer status and message type
-		err = handler(peer, msg)
+		attrs := []trace.Attribute{
+			{Key: "type", Value: int(msg.Type())},
+			{Key: "peer", Value: peer.String()},
+		}
+		err = trace.WithSpan(s.ctx, "network.handle_message", func(context.Context) error {
+			return handler(peer, msg)
+		}, attrs...)
 		if err != nil {
 			logger.Error("Failed to handle message from stream", "message", msg, "error", err)
 			_ = stream.Close()
@@ -421,6 +472,11 @@ func (s *Service) handleSyncMessage(peer peer.ID, msg Message) error {
 		return nil
 	}
 
+	// if it's a BlockResponse to a block body request, forward it to the caller
+	if resp, ok := msg.(*BlockResponseMessage); ok && s.handleBodyResponse(resp) {
+		return nil
+	}
+
 	// if it's a BlockResponse with an ID corresponding to a BlockRequest we sent, forward
 	// message to the sync service
 	if resp, ok := msg.(*BlockResponseMessage); ok && s.requestTracker.hasRequestedBlockID(resp.ID) {
@@ -437,6 +493,12 @@ func (s *Service) handleSyncMessage(peer peer.ID, msg Message) error {
 
 	// if it's a BlockRequest, call core for processing
 	if req, ok := msg.(*BlockRequestMessage); ok {
+		// only the peers in the sync and light peer sets are served
+		if !s.host.peerSets.allowed(syncPeers, peer) && !s.host.peerSets.allowed(lightPeers, peer) {
+			logger.Debug("ignoring BlockRequest from peer outside of the sync peer sets", "peer", peer, "id", req.ID)
+			return nil
+		}
+
 		resp, err := s.syncer.CreateBlockResponse(req)
 		if err != nil {
 			logger.Debug("cannot create response for request", "id", req.ID)
@@ -455,6 +517,11 @@ func (s *Service) handleSyncMessage(peer peer.ID, msg Message) error {
 // handleMessage handles the message based on peer status and message type
 // TODO: deprecate this handler, messages will be handled via their sub-protocols
 func (s *Service) handleMessage(peer peer.ID, msg Message) error {
+	// transactions are only accepted from the peers in the transactions peer set
+	if msg.Type() == TransactionMsgType && !s.host.peerSets.allowed(transactionPeers, peer) {
+		return nil
+	}
+
 	if msg.Type() != StatusMsgType {
 
 		// check if status is disabled or peer status is confirmed
@@ -483,6 +550,14 @@ func (s *Service) handleMessage(peer peer.ID, msg Message) error {
 
 			// check if peer status confirmed
 			if s.status.confirmed(peer) {
+				if !s.admitPeer(peer, msg.(*StatusMessage).Roles) {
+					return nil
+				}
+
+				// only sync from the peers in the sync peer set
+				if !s.host.peerSets.allowed(syncPeers, peer) {
+					return nil
+				}
 
 				// send a block request message if peer best block number is greater than host best block number
 				req := s.handleStatusMesssage(msg.(*StatusMessage))
@@ -572,3 +647,9 @@ func (s *Service) NodeRoles() byte {
 func (s *Service) SetMessageHandler(handler MessageHandler) {
 	s.messageHandler = handler
 }
+
+// SetBlockObserver sets the BlockObserver that is notified of the blocks announced by peers, it must be called
+// before the service is started
+func (s *Service) SetBlockObserver(observer BlockObserver) {
+	s.blockObserver = observer
+}
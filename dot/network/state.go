@@ -21,6 +21,7 @@ import (
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 )
 
 // BlockState interface for block state methods
@@ -29,6 +30,11 @@ type BlockState interface {
 	GenesisHash() common.Hash
 }
 
+// AuthorityState interface for retrieving the `audi` keys of the current authorities, used by authority discovery
+type AuthorityState interface {
+	AuthorityDiscoveryKeys() ([]*sr25519.PublicKey, error)
+}
+
 // NetworkState interface for network state methods
 //nolint:golint
 type NetworkState interface {
@@ -64,6 +70,11 @@ func (ns *MockNetworkState) SetPeers(peers []common.PeerInfo) {
 	ns.Peers = peers
 }
 
+// BlockObserver is notified of the blocks announced by peers as they arrive, eg. to measure block propagation
+type BlockObserver interface {
+	BlockAnnounced(header *types.Header)
+}
+
 // Syncer is implemented by the syncing service
 type Syncer interface {
 	// CreateBlockResponse is called upon receipt of a BlockRequestMessage to create the response
@@ -73,6 +84,10 @@ type Syncer interface {
 	// If another request needs to be sent to the peer, this function will return it.
 	HandleBlockResponse(*BlockResponseMessage) *BlockRequestMessage
 
+	// ValidateBlockAnnounce is called upon receipt of a BlockAnnounceMessage, before it is processed.
+	// If it returns an error, the announcement is discarded and the peer that sent it is penalized.
+	ValidateBlockAnnounce(*BlockAnnounceMessage) error
+
 	// HandleBlockAnnounce is called upon receipt of a BlockAnnounceMessage to process it.
 	// If a request needs to be sent to the peer to retrieve the full block, this function will return it.
 	HandleBlockAnnounce(*BlockAnnounceMessage) *BlockRequestMessage
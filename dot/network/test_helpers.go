@@ -25,6 +25,10 @@ func (s *mockSyncer) HandleBlockResponse(msg *BlockResponseMessage) *BlockReques
 	return nil
 }
 
+func (s *mockSyncer) ValidateBlockAnnounce(msg *BlockAnnounceMessage) error {
+	return nil
+}
+
 func (s *mockSyncer) HandleBlockAnnounce(msg *BlockAnnounceMessage) *BlockRequestMessage {
 	if msg.Number.Cmp(s.highestSeen) > 0 {
 		s.highestSeen = msg.Number
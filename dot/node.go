@@ -17,19 +17,24 @@
 package dot
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
 	"os/signal"
 	"path"
+	"path/filepath"
 	"sync"
 	"syscall"
 
 	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/rpc"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/services"
 
 	database "github.com/ChainSafe/chaindb"
@@ -38,12 +43,18 @@ import (
 
 var logger = log.New("pkg", "dot")
 
+// databaseFiles are the patterns of the files of the badger database in the node's data directory
+var databaseFiles = []string{"KEYREGISTRY", "MANIFEST", "DISCARD", "LOCK", "*.sst", "*.vlog", "*.mem"}
+
 // Node is a container for all the components of a node.
 type Node struct {
 	Name     string
 	Services *services.ServiceRegistry // registry of all node services
 	StopFunc func()                    // func to call when node stops, currently used for profiling
-	wg       sync.WaitGroup
+	rpc      *rpc.HTTPServer           // nil if the rpc service is disabled
+	halted   <-chan struct{}           // closed once the halt block is imported
+	stopped  chan struct{}
+	stopOnce sync.Once
 }
 
 // InitNode initializes a new dot node from the provided dot node configuration
@@ -58,49 +69,80 @@ func InitNode(cfg *Config) error {
 		"genesis-raw", cfg.Init.GenesisRaw,
 	)
 
+	// create new state service
+	stateSrvc := state.NewService(cfg.Global.BasePath, cfg.Global.LogLvl)
+
+	header, err := initState(cfg, stateSrvc)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(
+		"node initialized",
+		"name", cfg.Global.Name,
+		"id", cfg.Global.ID,
+		"basepath", cfg.Global.BasePath,
+		"genesis-raw", cfg.Init.GenesisRaw,
+		"block", header.Number,
+	)
+
+	return nil
+}
+
+// initState initializes the given state service with the genesis of the configuration, and returns the genesis
+// block header
+func initState(cfg *Config, stateSrvc *state.Service) (*types.Header, error) {
 	// create genesis from configuration file
-	gen, err := genesis.NewGenesisFromJSONRaw(cfg.Init.GenesisRaw)
+	gen, err := LoadGenesis(&cfg.Init)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load genesis: %w", err)
+	}
+
+	// refuse to initialize the node with a genesis it can't run, eg. one without runtime code
+	err = gen.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := setupHasher(cfg, stateSrvc)
 	if err != nil {
-		return fmt.Errorf("failed to load genesis from file: %w", err)
+		return nil, err
 	}
 
 	// create trie from genesis
-	t, err := genesis.NewTrieFromGenesis(gen)
+	t, err := genesis.NewTrieFromGenesisWithHasher(gen, hash)
 	if err != nil {
-		return fmt.Errorf("failed to create trie from genesis: %w", err)
+		return nil, fmt.Errorf("failed to create trie from genesis: %w", err)
 	}
 
 	// create genesis block from trie
 	header, err := genesis.NewGenesisBlockFromTrie(t)
 	if err != nil {
-		return fmt.Errorf("failed to create genesis block from trie: %w", err)
+		return nil, fmt.Errorf("failed to create genesis block from trie: %w", err)
 	}
 
-	// create new state service
-	stateSrvc := state.NewService(cfg.Global.BasePath, cfg.Global.LogLvl)
-
 	var genEpochInfo *types.EpochInfo
 	if !cfg.Init.TestFirstEpoch {
 		// load genesis trie state for loading runtime info
 		genTrie, err := state.NewTrieState(database.NewMemDatabase(), t) //nolint
 		if err != nil {
-			return fmt.Errorf("failed to instantiate TrieState: %w", err)
+			return nil, fmt.Errorf("failed to instantiate TrieState: %w", err)
 		}
 
 		err = genTrie.WriteTrieToDB()
 		if err != nil {
-			return fmt.Errorf("failed to write trie to db: %w", err)
+			return nil, fmt.Errorf("failed to write trie to db: %w", err)
 		}
 
 		// create genesis runtime
 		r, err := genesis.NewLegacyRuntimeFromGenesis(gen, genTrie) //nolint
 		if err != nil {
-			return fmt.Errorf("failed to create genesis runtime: %w", err)
+			return nil, fmt.Errorf("failed to create genesis runtime: %w", err)
 		}
 
 		babeCfg, err := r.BabeConfiguration()
 		if err != nil {
-			return fmt.Errorf("failed to fetch genesis babe configuration: %w", err)
+			return nil, fmt.Errorf("failed to fetch genesis babe configuration: %w", err)
 		}
 
 		genEpochInfo = &types.EpochInfo{
@@ -128,22 +170,38 @@ func InitNode(cfg *Config) error {
 	data.Bootnodes = common.StringArrayToBytes(cfg.Network.Bootnodes)
 	data.ProtocolID = cfg.Network.ProtocolID
 
+	_, err = genesis.ParseCodeSubstitutes(gen.CodeSubstitutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse code substitutes: %w", err)
+	}
+
+	_, err = gen.BlockRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block rules: %w", err)
+	}
+
+	if gen.LightSyncState != nil {
+		// the light sync state is only informative, a chain spec from another client shouldn't stop the node
+		_, err = gen.LightSyncState.Checkpoint()
+		if err != nil {
+			logger.Warn("ignoring light sync state that failed to parse", "error", err)
+			gen.LightSyncState = nil
+		}
+	}
+
+	stateSrvc.SetGenesisProperties(gen.Properties)
+	stateSrvc.SetCodeSubstitutes(gen.CodeSubstitutes)
+	stateSrvc.SetBlockRules(gen.BadBlocks, gen.ForkBlocks)
+	stateSrvc.SetLightSyncState(gen.LightSyncState)
+	stateSrvc.SetTelemetryEndpoints(gen.TelemetryEndpoints)
+
 	// initialize state service with genesis data, block, and trie
 	err = stateSrvc.Initialize(data, header, t, genEpochInfo)
 	if err != nil {
-		return fmt.Errorf("failed to initialize state service: %s", err)
+		return nil, fmt.Errorf("failed to initialize state service: %s", err)
 	}
 
-	logger.Info(
-		"node initialized",
-		"name", cfg.Global.Name,
-		"id", cfg.Global.ID,
-		"basepath", cfg.Global.BasePath,
-		"genesis-raw", cfg.Init.GenesisRaw,
-		"block", header.Number,
-	)
-
-	return nil
+	return header, nil
 }
 
 // NodeInitialized returns true if, within the configured data directory for the
@@ -208,10 +266,80 @@ func NodeInitialized(basepath string, expected bool) bool {
 	return true
 }
 
-// NewNode creates a new dot node from a dot node configuration
+// checkGenesisHash returns ErrGenesisMismatch if the genesis of the configuration isn't the one the database was
+// initialized with, so that the node doesn't run on the wrong chain
+func checkGenesisHash(cfg *Config, stateSrvc *state.Service) error {
+	if cfg.Global.InMemory || (cfg.Init.GenesisRaw == "" && len(cfg.Init.Genesis) == 0) {
+		return nil
+	}
+
+	gen, err := LoadGenesis(&cfg.Init)
+	if err != nil {
+		logger.Warn("cannot load genesis to check it against the database",
+			"genesis-raw", cfg.Init.GenesisRaw, "error", err)
+		return nil
+	}
+
+	// the database may have been initialized with another hash function than blake2b
+	name, err := state.LoadHasher(stateSrvc.DB())
+	if err != nil {
+		return fmt.Errorf("failed to load hasher: %w", err)
+	}
+
+	hasher, err := common.GetHasher(name)
+	if err != nil {
+		return err
+	}
+
+	hash, err := genesis.GenesisHash(gen, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to compute genesis hash: %w", err)
+	}
+
+	// the block tree is rooted at the last finalized block, so the genesis hash is read from the block at number 0
+	stored, err := stateSrvc.Block.GetBlockHash(big.NewInt(0))
+	if err != nil {
+		return err
+	}
+
+	if hash != *stored {
+		return fmt.Errorf("%w: database genesis %s, configured genesis %s (%s)", ErrGenesisMismatch, stored, hash,
+			cfg.Init.GenesisRaw)
+	}
+
+	return nil
+}
+
+// PurgeDatabase removes the state database of the node in the given data directory, leaving its keystore and
+// other files in place. The node must be initialized again before it's started.
+func PurgeDatabase(basepath string) error {
+	for _, pattern := range databaseFiles {
+		matches, err := filepath.Glob(filepath.Join(basepath, pattern))
+		if err != nil {
+			return err
+		}
+
+		for _, file := range matches {
+			err = os.Remove(file)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewNode creates a new dot node from a dot node configuration. The keystore may be nil for nodes that aren't
+// authorities. Programs embedding a node usually set Global.InMemory, start the node with StartServices and
+// query it with CallRPC.
 func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node, error) {
 	setupLogger(cfg)
 
+	if ks == nil {
+		ks = keystore.NewGlobalKeystore()
+	}
+
 	// if authority node, should have at least 1 key in keystore
 	if cfg.Core.Roles == types.AuthorityRole && (ks.Babe.Size() == 0 || ks.Gran.Size() == 0) {
 		return nil, ErrNoKeysProvided
@@ -228,6 +356,12 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 
 	var nodeSrvcs []services.Service
 	var networkSrvc *network.Service
+	var rpcSrvc *rpc.HTTPServer
+
+	// start recording spans before any other service starts
+	if cfg.Global.TracingEndpoint != "" {
+		nodeSrvcs = append(nodeSrvcs, createTracer(cfg))
+	}
 
 	// State Service
 
@@ -237,19 +371,48 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 		return nil, fmt.Errorf("failed to create state service: %s", err)
 	}
 
+	err = checkGenesisHash(cfg, stateSrvc)
+	if err != nil {
+		if stopErr := stateSrvc.Stop(); stopErr != nil {
+			logger.Error("failed to stop state service", "error", stopErr)
+		}
+		return nil, err
+	}
+
+	// use the chain spec properties the node was initialized with, unless they were configured
+	if len(cfg.System.SystemProperties) == 0 {
+		cfg.System.SystemProperties, err = state.LoadGenesisProperties(stateSrvc.DB())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load genesis properties: %s", err)
+		}
+	}
+
+	// the compiled runtime modules are shared by every runtime instance of the node
+	codeCache, err := createCodeCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// create runtime
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc)
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc, codeCache)
 	if err != nil {
 		return nil, err
 	}
 
-	ver, err := createBlockVerifier(cfg, stateSrvc, rt)
+	// select the runtime used in each execution context; there is no native runtime yet
+	executor, err := runtime.NewExecutor(cfg.Core.Execution, rt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime executor: %w", err)
+	}
+
+	importRt := executor.Instance(runtime.ContextImportBlock)
+	ver, err := createBlockVerifier(cfg, stateSrvc, importRt)
 	if err != nil {
 		return nil, err
 	}
 
 	// create BABE service
-	bp, err := createBABEService(cfg, rt, stateSrvc, ks.Babe)
+	bp, err := createBABEService(cfg, executor.Instance(runtime.ContextBlockConstruction), stateSrvc, ks.Babe)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +425,7 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	}
 
 	// create GRANDPA service
-	fg, err := createGRANDPAService(cfg, rt, stateSrvc, dh, ks.Gran)
+	fg, err := createGRANDPAService(cfg, executor.Instance(runtime.ContextOther), stateSrvc, dh, ks.Gran)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +433,7 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	dh.SetFinalityGadget(fg)
 
 	// Syncer
-	syncer, err := createSyncService(cfg, stateSrvc, bp, dh, ver, rt)
+	syncer, err := createSyncService(cfg, stateSrvc, bp, dh, ver, executor.Instance(runtime.ContextSyncing))
 	if err != nil {
 		return nil, err
 	}
@@ -280,11 +443,16 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	// check if network service is enabled
 	if enabled := networkServiceEnabled(cfg); enabled {
 		// create network service and append network service to node services
-		networkSrvc, err = createNetworkService(cfg, stateSrvc, syncer)
+		networkSrvc, err = createNetworkService(cfg, stateSrvc, syncer, ks.Audi)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create network service: %s", err)
 		}
 		nodeSrvcs = append(nodeSrvcs, networkSrvc)
+
+		// fetch block bodies from peers if only headers are stored
+		if cfg.Core.HeadersOnly {
+			stateSrvc.Block.SetBodyFetcher(networkSrvc)
+		}
 	} else {
 		// do not create or append network service if network service is not enabled
 		logger.Debug("network service disabled", "network", enabled, "roles", cfg.Core.Roles)
@@ -293,7 +461,7 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	// Core Service
 
 	// create core service and append core service to node services
-	coreSrvc, err := createCoreService(cfg, bp, fg, ver, rt, ks, stateSrvc, networkSrvc)
+	coreSrvc, err := createCoreService(cfg, bp, fg, ver, importRt, ks, stateSrvc, networkSrvc, codeCache)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create core service: %s", err)
 	}
@@ -302,19 +470,37 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	}
 	nodeSrvcs = append(nodeSrvcs, coreSrvc)
 
+	// observe imported blocks for the block time and propagation stats, using the time they were announced by
+	// peers as their arrival
+	stats := createBlockStats(stateSrvc, bp)
+	if networkSrvc != nil {
+		networkSrvc.SetBlockObserver(stats)
+	}
+	nodeSrvcs = append(nodeSrvcs, stats)
+
 	// System Service
 
 	// create system service and append to node services
 	sysSrvc := createSystemService(&cfg.System)
 	nodeSrvcs = append(nodeSrvcs, sysSrvc)
 
+	// report to the telemetry servers
+	telemetrySrvc, err := createTelemetryService(cfg, stateSrvc, networkSrvc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry service: %w", err)
+	}
+	if telemetrySrvc != nil {
+		nodeSrvcs = append(nodeSrvcs, telemetrySrvc)
+	}
+
 	// RPC Service
 
 	// check if rpc service is enabled
 	if enabled := RPCServiceEnabled(cfg); enabled {
 
 		// create rpc service and append rpc service to node services
-		rpcSrvc := createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, bp, rt, sysSrvc)
+		rpcRt := executor.Instance(runtime.ContextOther)
+		rpcSrvc = createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, bp, rpcRt, sysSrvc, syncer, stats)
 		nodeSrvcs = append(nodeSrvcs, rpcSrvc)
 
 	} else {
@@ -331,6 +517,9 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 		Name:     cfg.Global.Name,
 		StopFunc: stopFunc,
 		Services: services.NewServiceRegistry(),
+		rpc:      rpcSrvc,
+		halted:   stateSrvc.Block.Halted(),
+		stopped:  make(chan struct{}),
 	}
 
 	for _, srvc := range nodeSrvcs {
@@ -340,36 +529,69 @@ func NewNode(cfg *Config, ks *keystore.GlobalKeystore, stopFunc func()) (*Node,
 	return node, nil
 }
 
-// Start starts all dot node services
-func (n *Node) Start() error {
+// StartServices starts all dot node services and returns, without waiting for the node to stop or handling
+// interrupt signals. It's meant for nodes embedded in other programs, which stop the node with Stop.
+func (n *Node) StartServices() {
 	logger.Info("starting node services...")
-
-	// start all dot node services
 	n.Services.StartAll()
 
+	// shut down cleanly once the halt block is imported, see --halt-at-block
+	go func() {
+		select {
+		case <-n.halted:
+			logger.Info("imported halt block, shutting down...")
+			n.Stop()
+		case <-n.stopped:
+		}
+	}()
+}
+
+// Start starts all dot node services, and blocks until the node is stopped or the process is interrupted
+func (n *Node) Start() error {
+	n.StartServices()
+
 	go func() {
 		sigc := make(chan os.Signal, 1)
 		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
 		defer signal.Stop(sigc)
-		<-sigc
-		logger.Info("signal interrupt, shutting down...")
-		n.Stop()
-		os.Exit(130)
-	}()
 
-	n.wg.Add(1)
-	n.wg.Wait()
+		select {
+		case <-sigc:
+			logger.Info("signal interrupt, shutting down...")
+			n.Stop()
+			os.Exit(130)
+		case <-n.stopped:
+		}
+	}()
 
+	<-n.stopped
 	return nil
 }
 
-// Stop stops all dot node services
+// Stop stops all dot node services, it does nothing if the node is already stopped
 func (n *Node) Stop() {
-	if n.StopFunc != nil {
-		n.StopFunc()
+	n.stopOnce.Do(func() {
+		if n.StopFunc != nil {
+			n.StopFunc()
+		}
+
+		// stop all node services
+		n.Services.StopAll()
+		close(n.stopped)
+	})
+}
+
+// Done returns a channel that's closed when the node has stopped
+func (n *Node) Done() <-chan struct{} {
+	return n.stopped
+}
+
+// CallRPC handles the RPC call of the given method in-process, without going through the RPC server's
+// listeners, and returns its JSON encoded result. The params are encoded as the JSON-RPC request params.
+func (n *Node) CallRPC(method string, params interface{}) (json.RawMessage, error) {
+	if n.rpc == nil {
+		return nil, ErrRPCDisabled
 	}
 
-	// stop all node services
-	n.Services.StopAll()
-	n.wg.Done()
+	return n.rpc.Call(method, params)
 }
@@ -18,9 +18,11 @@ package dot
 
 import (
 	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"math/big"
 	"reflect"
-	"sync"
 	"testing"
 	"time"
 
@@ -62,6 +64,25 @@ func TestInitNode(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestInitNode_InvalidGenesis(t *testing.T) {
+	cfg := NewTestConfig(t)
+	require.NotNil(t, cfg)
+
+	cfg.Global.ID = ""
+	genFile := NewTestGenesisRawFile(t, cfg)
+	require.NotNil(t, genFile)
+
+	defer utils.RemoveTestDir(t)
+
+	cfg.Init.GenesisRaw = genFile.Name()
+
+	err := InitNode(cfg)
+	verr, ok := err.(*genesis.ValidationError)
+	require.True(t, ok, err)
+	require.Equal(t, []error{genesis.ErrMissingID}, verr.Errors)
+	require.False(t, NodeInitialized(cfg.Global.BasePath, false))
+}
+
 // TestNodeInitialized
 func TestNodeInitialized(t *testing.T) {
 	cfg := NewTestConfig(t)
@@ -117,6 +138,73 @@ func TestNewNode(t *testing.T) {
 	require.NotNil(t, fg)
 }
 
+func TestNewNode_GenesisMismatch(t *testing.T) {
+	cfg := NewTestConfig(t)
+	require.NotNil(t, cfg)
+
+	genFile := NewTestGenesisRawFile(t, cfg)
+	require.NotNil(t, genFile)
+
+	defer utils.RemoveTestDir(t)
+
+	cfg.Init.GenesisRaw = genFile.Name()
+	cfg.Core.Roles = types.FullNodeRole
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	// the same chain with an extra genesis storage entry
+	gen, err := genesis.NewGenesisFromJSONRaw(genFile.Name())
+	require.NoError(t, err)
+	gen.Genesis.Raw[0]["0x6b6579"] = "0x76616c7565"
+
+	otherFile, err := ioutil.TempFile(utils.NewTestDir(t), "genesis-")
+	require.NoError(t, err)
+	b, err := json.Marshal(gen)
+	require.NoError(t, err)
+	_, err = otherFile.Write(b)
+	require.NoError(t, err)
+
+	cfg.Init.GenesisRaw = otherFile.Name()
+	_, err = NewNode(cfg, nil, nil)
+	require.True(t, errors.Is(err, ErrGenesisMismatch))
+
+	err = PurgeDatabase(cfg.Global.BasePath)
+	require.NoError(t, err)
+	require.False(t, NodeInitialized(cfg.Global.BasePath, false))
+
+	err = InitNode(cfg)
+	require.NoError(t, err)
+	node, err := NewNode(cfg, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, node)
+}
+
+func TestNewNode_GenesisHasher(t *testing.T) {
+	cfg := NewTestConfig(t)
+	require.NotNil(t, cfg)
+
+	genFile := NewTestGenesisRawFile(t, cfg)
+	require.NotNil(t, genFile)
+
+	defer utils.RemoveTestDir(t)
+	defer func() {
+		require.NoError(t, common.SetDefaultHasher(common.Blake2bHasher))
+	}()
+
+	cfg.Init.GenesisRaw = genFile.Name()
+	cfg.Core.Roles = types.FullNodeRole
+	cfg.Global.Hasher = common.KeccakHasher
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	// the genesis is checked against the database with the hash function the database was initialized with
+	node, err := NewNode(cfg, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, node)
+}
+
 func TestNewNode_Authority(t *testing.T) {
 	cfg := NewTestConfig(t)
 	require.NotNil(t, cfg)
@@ -244,7 +332,7 @@ func TestInitNode_LoadGenesisData(t *testing.T) {
 	require.NoError(t, err)
 
 	stateRoot := genesisHeader.StateRoot
-	expectedHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), stateRoot, trie.EmptyHash, [][]byte{})
+	expectedHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), stateRoot, trie.EmptyHash, types.NewEmptyDigest())
 	require.NoError(t, err)
 	require.Equal(t, expectedHeader.Hash(), genesisHeader.Hash())
 }
@@ -365,10 +453,46 @@ func TestNode_StopFunc(t *testing.T) {
 	node := &Node{
 		Services: &services.ServiceRegistry{},
 		StopFunc: stopFunc,
-		wg:       sync.WaitGroup{},
+		stopped:  make(chan struct{}),
 	}
-	node.wg.Add(1)
 
 	node.Stop()
 	require.Equal(t, testvar, "after")
+
+	// stopping the node again does nothing
+	node.Stop()
+	<-node.Done()
+}
+
+func TestNewNode_InMemory(t *testing.T) {
+	cfg := NewTestConfig(t)
+	require.NotNil(t, cfg)
+
+	genFile := NewTestGenesisRawFile(t, cfg)
+	require.NotNil(t, genFile)
+
+	defer utils.RemoveTestDir(t)
+
+	cfg.Init.GenesisRaw = genFile.Name()
+	cfg.Global.InMemory = true
+	cfg.Core.Roles = types.FullNodeRole
+	cfg.RPC.Enabled = true
+	cfg.RPC.Modules = []string{"chain"}
+
+	// the node is initialized when it is created, and a full node needs no keystore
+	node, err := NewNode(cfg, nil, nil)
+	require.NoError(t, err)
+	require.False(t, NodeInitialized(cfg.Global.BasePath, false))
+
+	stateSrvc := node.Services.Get(&state.Service{}).(*state.Service)
+	res, err := node.CallRPC("chain_getBlockHash", []interface{}{0})
+	require.NoError(t, err)
+
+	var hash string
+	err = json.Unmarshal(res, &hash)
+	require.NoError(t, err)
+	require.Equal(t, stateSrvc.Block.GenesisHash().String(), hash)
+
+	_, err = node.CallRPC("chain_unknownMethod", nil)
+	require.Error(t, err)
 }
@@ -0,0 +1,119 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+var (
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfRequest = reflect.TypeOf((*http.Request)(nil))
+)
+
+// Call calls the given RPC method of the registered modules in-process and returns its JSON encoded result. The
+// params are decoded into the method's arguments the same way as the params of a JSON-RPC request. The call
+// doesn't go through the HTTP listener or the middleware, so methods that require the auth token can be called.
+func (h *HTTPServer) Call(method string, params interface{}) (json.RawMessage, error) {
+	fn, err := h.moduleMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	args := reflect.New(fn.Type().In(1).Elem())
+	reply := reflect.New(fn.Type().In(2).Elem())
+
+	err = decodeCallParams(params, args.Interface())
+	if err != nil {
+		return nil, &json2.Error{
+			Code:    json2.E_INVALID_REQ,
+			Message: err.Error(),
+		}
+	}
+
+	out := fn.Call([]reflect.Value{reflect.ValueOf(new(http.Request)), args, reply})
+	if callErr, ok := out[0].Interface().(error); ok && callErr != nil {
+		return nil, callErr
+	}
+
+	return json.Marshal(reply.Interface())
+}
+
+// moduleMethod returns the method of the registered module that handles the given RPC method, eg. the
+// GetBlockHash method of the chain module for chain_getBlockHash
+func (h *HTTPServer) moduleMethod(method string) (reflect.Value, error) {
+	notFound := &json2.Error{
+		Code:    json2.E_NO_METHOD,
+		Message: "rpc: can't find method " + method,
+	}
+
+	// split on the last underscore, since module names such as sync_state may contain underscores
+	idx := strings.LastIndex(method, "_")
+	if idx <= 0 || idx == len(method)-1 {
+		return reflect.Value{}, notFound
+	}
+
+	srvc, has := h.modules[method[:idx]]
+	if !has {
+		return reflect.Value{}, notFound
+	}
+
+	name := method[idx+1:]
+	r, n := utf8.DecodeRuneInString(name)
+	fn := reflect.ValueOf(srvc).MethodByName(string(unicode.ToUpper(r)) + name[n:])
+	if !fn.IsValid() || !isModuleMethod(fn.Type()) {
+		return reflect.Value{}, notFound
+	}
+
+	return fn, nil
+}
+
+// isModuleMethod returns true if the method has the signature of an RPC method, ie.
+// func(*http.Request, *Args, *Reply) error
+func isModuleMethod(t reflect.Type) bool {
+	return t.NumIn() == 3 && t.NumOut() == 1 &&
+		t.In(0) == typeOfRequest &&
+		t.In(1).Kind() == reflect.Ptr &&
+		t.In(2).Kind() == reflect.Ptr &&
+		t.Out(0) == typeOfError
+}
+
+// decodeCallParams decodes the params into args like a JSON-RPC codec, either as a structured object or as an
+// array holding the args
+func decodeCallParams(params, args interface{}) error {
+	if params == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, args); err == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, &[1]interface{}{args})
+}
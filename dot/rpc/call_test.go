@@ -0,0 +1,75 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/system"
+	"github.com/ChainSafe/gossamer/dot/types"
+
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_Call(t *testing.T) {
+	si := &types.SystemInfo{
+		SystemVersion: "0.0.1",
+	}
+	cfg := &HTTPServerConfig{
+		Modules:   []string{"system"},
+		RPCAPI:    NewService(),
+		SystemAPI: system.NewService(si),
+	}
+	s := NewHTTPServer(cfg)
+
+	res, err := s.Call("system_version", nil)
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`"0.0.1"`), res)
+
+	res, err = s.Call("system_version", []interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`"0.0.1"`), res)
+
+	// unknown modules and methods, and module methods that aren't RPC methods, can't be called
+	for _, method := range []string{"chain_getBlockHash", "system_unknown", "system_useMemoryAPI", "system", "system_"} {
+		_, err = s.Call(method, nil)
+		require.Error(t, err, method)
+		require.Equal(t, json2.E_NO_METHOD, err.(*json2.Error).Code, method)
+	}
+}
+
+func TestDecodeCallParams(t *testing.T) {
+	type args struct {
+		Bhash string
+	}
+
+	a := new(args)
+	err := decodeCallParams(map[string]interface{}{"Bhash": "0x01"}, a)
+	require.NoError(t, err)
+	require.Equal(t, "0x01", a.Bhash)
+
+	// params given as an array hold the args
+	a = new(args)
+	err = decodeCallParams([]interface{}{map[string]interface{}{"Bhash": "0x02"}}, a)
+	require.NoError(t, err)
+	require.Equal(t, "0x02", a.Bhash)
+
+	err = decodeCallParams("0x03", a)
+	require.Error(t, err)
+}
@@ -72,11 +72,12 @@ type DotUpCodecRequest struct {
 func (c *DotUpCodecRequest) Method() (string, error) {
 	m, err := c.CodecRequest.Method()
 	if len(m) > 1 && err == nil {
-		parts := strings.Split(m, "_")
-		if len(parts) < 2 {
+		// split on the last underscore, since service names such as sync_state may contain underscores
+		idx := strings.LastIndex(m, "_")
+		if idx < 0 {
 			return "", fmt.Errorf("rpc error method %s not found", m)
 		}
-		service, method := parts[0], parts[1]
+		service, method := m[:idx], m[idx+1:]
 		r, n := utf8.DecodeRuneInString(method) // get the first rune, and it's length
 		if unicode.IsLower(r) {
 			upMethod := service + "." + string(unicode.ToUpper(r)) + method[n:]
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	log "github.com/ChainSafe/log15"
@@ -35,6 +36,10 @@ type HTTPServer struct {
 	rpcServer    *rpc.Server // Actual RPC call handler
 	serverConfig *HTTPServerConfig
 	wsConns      []*WSConn
+	wsConnsLock  sync.Mutex
+	middleware   []Middleware
+	metrics      *Metrics
+	modules      map[string]interface{} // registered modules by name, used by Call
 }
 
 // HTTPServerConfig configures the HTTPServer
@@ -49,11 +54,24 @@ type HTTPServerConfig struct {
 	TransactionQueueAPI modules.TransactionStateAPI
 	RPCAPI              modules.RPCAPI
 	SystemAPI           modules.SystemAPI
+	SyncAPI             modules.SyncAPI
+	MemoryAPI           modules.MemoryAPI
+	BlockStatsAPI       modules.BlockStatsAPI
+	SyncStateAPI        modules.SyncStateAPI
 	Host                string
 	RPCPort             uint32
 	WSEnabled           bool
 	WSPort              uint32
+	WSMaxSubscriptions  uint32 // maximum number of subscriptions per websocket connection, 0 for no limit
+	FinalizedOnly       bool   // resolve queries and subscriptions against the finalized head instead of the best block
 	Modules             []string
+	LogRequests         bool           // log every request with its latency
+	AuthToken           string         // bearer token required to call unsafe methods, empty to disable auth
+	UnsafeMethods       []string       // methods that require the auth token, DefaultUnsafeMethods if empty
+	Tracing             bool           // propagate W3C trace context headers
+	Unsafe              bool           // enable methods that modify the chain state
+	SlowQueryThreshold  time.Duration  // log requests that take longer than this, 0 to disable
+	Metrics             []http.Handler // other Prometheus metrics served at /metrics, after the RPC metrics
 }
 
 // WSConn struct to hold WebSocket Connection references
@@ -64,8 +82,15 @@ type WSConn struct {
 	storageSubChannels map[int]byte
 	qtyListeners       int
 	subscriptions      map[int]Listener
+	subscriptionsLock  sync.RWMutex
+	watches            *extrinsicWatches
+	maxSubscriptions   uint32
+	metrics            *Metrics // counts the subscriptions rejected by the limit, may be nil
+	finalizedOnly      bool
+	authHeader         string
 	storageAPI         modules.StorageAPI
 	blockAPI           modules.BlockAPI
+	txStateAPI         modules.TransactionStateAPI
 }
 
 var logger log.Logger
@@ -81,9 +106,18 @@ func NewHTTPServer(cfg *HTTPServerConfig) *HTTPServer {
 		logger:       logger,
 		rpcServer:    rpc.NewServer(),
 		serverConfig: cfg,
+		metrics:      NewMetrics(),
+		modules:      make(map[string]interface{}),
 	}
 
+	// use our DotUpCodec which will capture methods passed in json as _x that is
+	//  underscore followed by lower case letter, instead of default RPC calls which
+	//  use . followed by Upper case letter
+	server.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
+	server.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json;charset=UTF-8")
+
 	server.RegisterModules(cfg.Modules)
+	server.registerMiddleware()
 	return server
 }
 
@@ -95,17 +129,45 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 		var srvc interface{}
 		switch mod {
 		case "system":
-			srvc = modules.NewSystemModule(h.serverConfig.NetworkAPI, h.serverConfig.SystemAPI)
+			sys := modules.NewSystemModule(h.serverConfig.NetworkAPI, h.serverConfig.SystemAPI, h.serverConfig.SyncAPI)
+			if h.serverConfig.MemoryAPI != nil {
+				sys.UseMemoryAPI(h.serverConfig.MemoryAPI)
+			}
+			if h.serverConfig.BlockStatsAPI != nil {
+				sys.UseBlockStatsAPI(h.serverConfig.BlockStatsAPI)
+			}
+			srvc = sys
 		case "author":
 			srvc = modules.NewAuthorModule(h.logger, h.serverConfig.CoreAPI, h.serverConfig.RuntimeAPI, h.serverConfig.TransactionQueueAPI)
 		case "chain":
-			srvc = modules.NewChainModule(h.serverConfig.BlockAPI)
+			chain := modules.NewChainModule(h.serverConfig.BlockAPI)
+			if h.serverConfig.FinalizedOnly {
+				chain.UseFinalizedHead()
+			}
+			srvc = chain
 		case "state":
-			srvc = modules.NewStateModule(h.serverConfig.NetworkAPI, h.serverConfig.StorageAPI, h.serverConfig.CoreAPI)
+			st := modules.NewStateModule(h.serverConfig.NetworkAPI, h.serverConfig.StorageAPI, h.serverConfig.CoreAPI)
+			if h.serverConfig.FinalizedOnly {
+				st.UseFinalizedHead(h.serverConfig.BlockAPI)
+			}
+			if h.serverConfig.Unsafe {
+				st.EnableUnsafe()
+			}
+			srvc = st
 		case "rpc":
 			srvc = modules.NewRPCModule(h.serverConfig.RPCAPI)
 		case "dev":
 			srvc = modules.NewDevModule(h.serverConfig.BlockProducerAPI, h.serverConfig.NetworkAPI)
+		case "babe":
+			srvc = modules.NewBabeModule(h.serverConfig.BlockProducerAPI, h.serverConfig.BlockAPI)
+		case "payment":
+			srvc = modules.NewPaymentModule(h.serverConfig.CoreAPI)
+		case "admin":
+			srvc = modules.NewAdminModule(h.serverConfig.BlockAPI)
+		case "sync_state":
+			srvc = modules.NewSyncStateModule(h.serverConfig.SyncStateAPI)
+		case "gssmr":
+			srvc = modules.NewGssmrModule(h.serverConfig.StorageAPI, h.serverConfig.BlockAPI)
 		default:
 			h.logger.Warn("Unrecognized module", "module", mod)
 			continue
@@ -115,6 +177,8 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 
 		if err != nil {
 			h.logger.Warn("Failed to register module", "mod", mod, "err", err)
+		} else {
+			h.modules[mod] = srvc
 		}
 
 		h.serverConfig.RPCAPI.BuildMethodNames(srvc, mod)
@@ -123,15 +187,10 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 
 // Start registers the rpc handler function and starts the rpc http and websocket server
 func (h *HTTPServer) Start() error {
-	// use our DotUpCodec which will capture methods passed in json as _x that is
-	//  underscore followed by lower case letter, instead of default RPC calls which
-	//  use . followed by Upper case letter
-	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
-	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json;charset=UTF-8")
-
 	h.logger.Info("Starting HTTP Server...", "host", h.serverConfig.Host, "port", h.serverConfig.RPCPort)
 	r := mux.NewRouter()
-	r.Handle("/", h.rpcServer)
+	r.Handle("/", h.handler())
+	r.Handle("/metrics", h.metricsHandler()).Methods(http.MethodGet)
 	go func() {
 		err := http.ListenAndServe(fmt.Sprintf(":%d", h.serverConfig.RPCPort), r)
 		if err != nil {
@@ -159,24 +218,91 @@ func (h *HTTPServer) Start() error {
 // Stop stops the server
 func (h *HTTPServer) Stop() error {
 	if h.serverConfig.WSEnabled {
+		h.wsConnsLock.Lock()
+		defer h.wsConnsLock.Unlock()
+
 		// close all channels and websocket connections
 		for _, conn := range h.wsConns {
-			for _, sub := range conn.subscriptions {
-				switch v := sub.(type) {
-				case *StorageChangeListener:
-					h.serverConfig.StorageAPI.UnregisterStorageChangeChannel(v.chanID)
-					close(v.channel)
-				case *BlockListener:
-					h.serverConfig.BlockAPI.UnregisterImportedChannel(v.chanID)
-					close(v.channel)
-				}
-			}
+			conn.closeSubscriptions()
 
 			err := conn.wsconn.Close()
 			if err != nil {
 				h.logger.Error("error closing websocket connection", "error", err)
 			}
 		}
+
+		h.wsConns = nil
 	}
 	return nil
 }
+
+// ActiveWSConnections returns the number of currently open websocket connections
+func (h *HTTPServer) ActiveWSConnections() int {
+	h.wsConnsLock.Lock()
+	defer h.wsConnsLock.Unlock()
+
+	return len(h.wsConns)
+}
+
+// ActiveSubscriptions returns the total number of subscriptions across all open websocket connections
+func (h *HTTPServer) ActiveSubscriptions() int {
+	h.wsConnsLock.Lock()
+	defer h.wsConnsLock.Unlock()
+
+	total := 0
+	for _, conn := range h.wsConns {
+		total += conn.SubscriptionCount()
+	}
+
+	return total
+}
+
+const (
+	// wsConnSize is the approximate memory held by a websocket connection, mostly its read and write buffers
+	wsConnSize = 2 * 4096
+	// subscriptionSize is the approximate memory held by a subscription, ie. its listener, channels and goroutine
+	subscriptionSize = 4096
+)
+
+// MemoryUsage returns the approximate number of bytes held by the open websocket connections and their subscriptions
+func (h *HTTPServer) MemoryUsage() uint64 {
+	h.wsConnsLock.Lock()
+	defer h.wsConnsLock.Unlock()
+
+	var size uint64
+	for _, conn := range h.wsConns {
+		size += wsConnSize + uint64(conn.SubscriptionCount())*subscriptionSize
+	}
+
+	return size
+}
+
+func (h *HTTPServer) addWSConn(conn *WSConn) {
+	h.wsConnsLock.Lock()
+	defer h.wsConnsLock.Unlock()
+
+	h.wsConns = append(h.wsConns, conn)
+}
+
+// removeWSConn closes the subscriptions of the given connection and stops tracking it
+func (h *HTTPServer) removeWSConn(conn *WSConn) {
+	h.wsConnsLock.Lock()
+	defer h.wsConnsLock.Unlock()
+
+	for i, c := range h.wsConns {
+		if c != conn {
+			continue
+		}
+
+		conn.closeSubscriptions()
+		h.wsConns = append(h.wsConns[:i], h.wsConns[i+1:]...)
+
+		err := conn.wsconn.Close()
+		if err != nil {
+			h.logger.Debug("error closing websocket connection", "error", err)
+		}
+		break
+	}
+
+	h.logger.Debug("websocket connection closed", "connections", len(h.wsConns))
+}
@@ -0,0 +1,302 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of the buckets of the RPC latency histograms
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// maxTrackedMethods is the number of distinct methods that metrics are kept for, calls to other methods are
+// counted under otherMethod so that clients can't grow the metrics by calling arbitrary method names
+const maxTrackedMethods = 256
+
+const (
+	otherMethod = "other"
+
+	// maxLoggedParams is the maximum length of the params logged for a slow request
+	maxLoggedParams = 256
+)
+
+// MethodStats holds the metrics of the calls to an RPC method
+type MethodStats struct {
+	Method  string
+	Calls   uint64
+	Errors  uint64
+	Latency time.Duration // total latency of the calls
+	Buckets []uint64      // cumulative number of calls within each of the latency buckets
+}
+
+// Metrics holds per-method counters and latency histograms of RPC calls
+type Metrics struct {
+	mu       sync.Mutex
+	methods  map[string]*MethodStats
+	rejected uint64 // number of websocket subscriptions rejected because of the per-connection limit
+}
+
+// NewMetrics returns a new Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		methods: make(map[string]*MethodStats),
+	}
+}
+
+// observe records a call to the given method
+func (m *Metrics) observe(method string, latency time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, has := m.methods[method]
+	if !has {
+		if len(m.methods) >= maxTrackedMethods {
+			method = otherMethod
+		}
+
+		stats, has = m.methods[method]
+		if !has {
+			stats = &MethodStats{
+				Method:  method,
+				Buckets: make([]uint64, len(latencyBuckets)),
+			}
+			m.methods[method] = stats
+		}
+	}
+
+	stats.Calls++
+	stats.Latency += latency
+	if failed {
+		stats.Errors++
+	}
+
+	for i, bound := range latencyBuckets {
+		if latency <= bound {
+			stats.Buckets[i]++
+		}
+	}
+}
+
+// rejectSubscription counts a websocket subscription that was rejected because of the per-connection limit
+func (m *Metrics) rejectSubscription() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejected++
+}
+
+// RejectedSubscriptions returns the number of websocket subscriptions rejected because of the per-connection limit
+func (m *Metrics) RejectedSubscriptions() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rejected
+}
+
+// Stats returns the metrics of every method that has been called, sorted by method
+func (m *Metrics) Stats() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]MethodStats, 0, len(m.methods))
+	for _, s := range m.methods {
+		cpy := *s
+		cpy.Buckets = append([]uint64{}, s.Buckets...)
+		stats = append(stats, cpy)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	stats := m.Stats()
+
+	buf.WriteString("# HELP gossamer_rpc_calls_total Number of RPC calls.\n")
+	buf.WriteString("# TYPE gossamer_rpc_calls_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(&buf, "gossamer_rpc_calls_total{method=%q} %d\n", s.Method, s.Calls)
+	}
+
+	buf.WriteString("# HELP gossamer_rpc_errors_total Number of RPC calls that returned an error.\n")
+	buf.WriteString("# TYPE gossamer_rpc_errors_total counter\n")
+	for _, s := range stats {
+		fmt.Fprintf(&buf, "gossamer_rpc_errors_total{method=%q} %d\n", s.Method, s.Errors)
+	}
+
+	buf.WriteString("# HELP gossamer_rpc_latency_seconds Latency of RPC calls.\n")
+	buf.WriteString("# TYPE gossamer_rpc_latency_seconds histogram\n")
+	for _, s := range stats {
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(&buf, "gossamer_rpc_latency_seconds_bucket{method=%q,le=\"%g\"} %d\n", s.Method, bound.Seconds(), s.Buckets[i])
+		}
+		fmt.Fprintf(&buf, "gossamer_rpc_latency_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", s.Method, s.Calls)
+		fmt.Fprintf(&buf, "gossamer_rpc_latency_seconds_sum{method=%q} %g\n", s.Method, s.Latency.Seconds())
+		fmt.Fprintf(&buf, "gossamer_rpc_latency_seconds_count{method=%q} %d\n", s.Method, s.Calls)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// metricsHandler returns the handler serving the RPC metrics, the websocket metrics if it's enabled, and the other
+// metrics of the server config
+func (h *HTTPServer) metricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.metrics.ServeHTTP(w, r)
+		if h.serverConfig.WSEnabled {
+			h.serveWSMetrics(w)
+		}
+		for _, m := range h.serverConfig.Metrics {
+			m.ServeHTTP(w, r)
+		}
+	})
+}
+
+// serveWSMetrics writes the websocket connection and subscription metrics in the Prometheus text format
+func (h *HTTPServer) serveWSMetrics(w http.ResponseWriter) {
+	var buf bytes.Buffer
+
+	buf.WriteString("# HELP gossamer_rpc_ws_connections Number of open websocket connections.\n")
+	buf.WriteString("# TYPE gossamer_rpc_ws_connections gauge\n")
+	fmt.Fprintf(&buf, "gossamer_rpc_ws_connections %d\n", h.ActiveWSConnections())
+
+	buf.WriteString("# HELP gossamer_rpc_ws_subscriptions Number of active websocket subscriptions.\n")
+	buf.WriteString("# TYPE gossamer_rpc_ws_subscriptions gauge\n")
+	fmt.Fprintf(&buf, "gossamer_rpc_ws_subscriptions %d\n", h.ActiveSubscriptions())
+
+	buf.WriteString("# HELP gossamer_rpc_ws_subscriptions_rejected_total Number of websocket subscriptions rejected " +
+		"because the connection reached its subscription limit.\n")
+	buf.WriteString("# TYPE gossamer_rpc_ws_subscriptions_rejected_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_rpc_ws_subscriptions_rejected_total %d\n", h.metrics.RejectedSubscriptions())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// responseRecorder captures the status code and the start of the body written by the wrapped handler, so that
+// JSON-RPC error responses can be told apart from results
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	head   []byte
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if len(rec.head) < 64 {
+		n := 64 - len(rec.head)
+		if n > len(b) {
+			n = len(b)
+		}
+		rec.head = append(rec.head, b[:n]...)
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// failed returns true if the response is an HTTP or JSON-RPC error
+func (rec *responseRecorder) failed() bool {
+	if rec.status >= http.StatusBadRequest {
+		return true
+	}
+
+	// error responses don't have a result, so the error follows the version
+	return bytes.HasPrefix(bytes.TrimSpace(rec.head), []byte(`{"jsonrpc":"2.0","error"`))
+}
+
+// metricsMiddleware records the metrics of every RPC request, and logs requests that take longer than the
+// configured slow query threshold
+func (h *HTTPServer) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		next.ServeHTTP(rec, r)
+
+		latency := time.Since(start)
+		req := requestFromContext(r)
+		h.metrics.observe(req.Method, latency, rec.failed())
+
+		threshold := h.serverConfig.SlowQueryThreshold
+		if threshold == 0 || latency < threshold {
+			return
+		}
+
+		h.logger.Warn("slow rpc request",
+			"method", req.Method,
+			"latency", latency,
+			"params", h.sanitizeParams(req.Method, req.Params),
+			"remote", r.RemoteAddr,
+		)
+	})
+}
+
+// sanitizeParams returns the params of a request for logging. The params of unsafe methods, which may hold secret
+// keys, are redacted and long params are truncated.
+func (h *HTTPServer) sanitizeParams(method string, params json.RawMessage) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	unsafe := h.serverConfig.UnsafeMethods
+	if len(unsafe) == 0 {
+		unsafe = DefaultUnsafeMethods
+	}
+
+	if isUnsafeMethod(method, unsafe) {
+		return "<redacted>"
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return "<invalid>"
+	}
+
+	if buf.Len() > maxLoggedParams {
+		return fmt.Sprintf("%s... (%d bytes)", buf.Bytes()[:maxLoggedParams], buf.Len())
+	}
+	return buf.String()
+}
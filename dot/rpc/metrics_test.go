@@ -0,0 +1,95 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	h := &HTTPServer{
+		logger:       log.New("pkg", "rpc"),
+		serverConfig: &HTTPServerConfig{SlowQueryThreshold: time.Nanosecond},
+		metrics:      NewMetrics(),
+	}
+
+	handler := h.methodMiddleware(h.metricsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestFromContext(r).Method == "chain_getBlock" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"not found"},"id":2}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"gossamer","id":1}`))
+	})))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, `{"jsonrpc":"2.0","method":"system_name","params":[],"id":1}`))
+	handler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, `{"jsonrpc":"2.0","method":"system_name","params":[],"id":1}`))
+	handler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, `{"jsonrpc":"2.0","method":"chain_getBlock","params":["0x01"],"id":2}`))
+
+	stats := h.metrics.Stats()
+	require.Len(t, stats, 2)
+	require.Equal(t, "chain_getBlock", stats[0].Method)
+	require.Equal(t, uint64(1), stats[0].Calls)
+	require.Equal(t, uint64(1), stats[0].Errors)
+	require.Equal(t, "system_name", stats[1].Method)
+	require.Equal(t, uint64(2), stats[1].Calls)
+	require.Equal(t, uint64(0), stats[1].Errors)
+
+	rec := httptest.NewRecorder()
+	h.metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, `gossamer_rpc_calls_total{method="system_name"} 2`))
+	require.True(t, strings.Contains(body, `gossamer_rpc_errors_total{method="chain_getBlock"} 1`))
+	require.True(t, strings.Contains(body, `gossamer_rpc_latency_seconds_bucket{method="system_name",le="+Inf"} 2`))
+}
+
+func TestMetrics_MaxTrackedMethods(t *testing.T) {
+	m := NewMetrics()
+	for i := 0; i < maxTrackedMethods+10; i++ {
+		m.observe(strings.Repeat("a", i+1), time.Millisecond, false)
+	}
+
+	stats := m.Stats()
+	require.Len(t, stats, maxTrackedMethods+1)
+
+	for _, s := range stats {
+		if s.Method == otherMethod {
+			require.Equal(t, uint64(10), s.Calls)
+			require.Equal(t, uint64(10), s.Buckets[0])
+		}
+	}
+}
+
+func TestSanitizeParams(t *testing.T) {
+	h := &HTTPServer{
+		serverConfig: &HTTPServerConfig{},
+	}
+
+	require.Equal(t, `["0x01",true]`, h.sanitizeParams("chain_getBlock", json.RawMessage(`[ "0x01", true ]`)))
+	require.Equal(t, "<redacted>", h.sanitizeParams("author_insertKey", json.RawMessage(`["babe","secret","0x01"]`)))
+	require.Equal(t, "", h.sanitizeParams("system_name", nil))
+
+	long := `["` + strings.Repeat("a", maxLoggedParams) + `"]`
+	require.True(t, strings.HasSuffix(h.sanitizeParams("state_getStorage", json.RawMessage(long)), "... (260 bytes)"))
+}
@@ -0,0 +1,308 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/trace"
+)
+
+// Middleware wraps the handler of an RPC request, it may inspect or reject the request before
+// passing it on to the next handler
+type Middleware func(next http.Handler) http.Handler
+
+// DefaultUnsafeMethods are the RPC methods that require the auth token when one is configured
+var DefaultUnsafeMethods = []string{
+	"author_insertKey",
+	"author_rotateKeys",
+	"author_removeExtrinsic",
+	"state_insertStorage",
+	"gssmr_storageDiff",      // compares the whole state of two blocks
+	"sync_state_genSyncSpec", // copies the whole state of the finalized block
+	"dev_*",
+	"admin_*",
+}
+
+const (
+	// traceparentHeader is the W3C trace context header used to propagate traces
+	traceparentHeader = "traceparent"
+	traceVersion      = "00"
+	traceFlagSampled  = "01"
+)
+
+type contextKey int
+
+const (
+	rpcMethodKey contextKey = iota
+	traceContextKey
+)
+
+// TraceContext is the W3C trace context of an RPC request
+type TraceContext struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+}
+
+// String returns the trace context formatted as a traceparent header value
+func (tc *TraceContext) String() string {
+	return strings.Join([]string{traceVersion, tc.TraceID, tc.SpanID, traceFlagSampled}, "-")
+}
+
+// TraceContextFromRequest returns the trace context of the request, or nil if tracing is disabled
+func TraceContextFromRequest(r *http.Request) *TraceContext {
+	tc, ok := r.Context().Value(traceContextKey).(*TraceContext)
+	if !ok {
+		return nil
+	}
+	return tc
+}
+
+// Use appends the given middleware to the chain run before every RPC call handler. Middleware
+// run in the order they are added and must be added before the server is started.
+func (h *HTTPServer) Use(mw ...Middleware) {
+	h.middleware = append(h.middleware, mw...)
+}
+
+// handler returns the rpc server wrapped in the configured middleware chain
+func (h *HTTPServer) handler() http.Handler {
+	var handler http.Handler = h.rpcServer
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handler = h.middleware[i](handler)
+	}
+
+	return h.methodMiddleware(handler)
+}
+
+// registerMiddleware adds the middleware enabled in the server config
+func (h *HTTPServer) registerMiddleware() {
+	h.Use(h.metricsMiddleware)
+
+	if h.serverConfig.Tracing {
+		h.Use(tracingMiddleware)
+	}
+
+	if h.serverConfig.LogRequests {
+		h.Use(h.loggingMiddleware)
+	}
+
+	if h.serverConfig.AuthToken != "" {
+		unsafe := h.serverConfig.UnsafeMethods
+		if len(unsafe) == 0 {
+			unsafe = DefaultUnsafeMethods
+		}
+		h.Use(h.authMiddleware(h.serverConfig.AuthToken, unsafe))
+	}
+}
+
+type rpcRequest struct {
+	Method string          `json:"method"`
+	ID     interface{}     `json:"id"`
+	Params json.RawMessage `json:"params"`
+}
+
+// methodMiddleware decodes the method of the JSON-RPC request and stores it in the request context,
+// the request body is restored so it can be read again by the rpc server
+func (h *HTTPServer) methodMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			h.logger.Debug("failed to read request body", "error", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		req := &rpcRequest{}
+		if err = json.Unmarshal(body, req); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rpcMethodKey, req)))
+	})
+}
+
+func requestFromContext(r *http.Request) *rpcRequest {
+	req, ok := r.Context().Value(rpcMethodKey).(*rpcRequest)
+	if !ok {
+		return &rpcRequest{}
+	}
+	return req
+}
+
+// tracingMiddleware continues the trace of an incoming traceparent header, or starts a new trace,
+// and returns the traceparent of the request's span in the response. If span recording is enabled,
+// the request is recorded as a span of the trace.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc := &TraceContext{
+			SpanID: randomHex(8),
+		}
+
+		ctx := r.Context()
+		parts := strings.Split(r.Header.Get(traceparentHeader), "-")
+		if len(parts) == 4 && isValidTraceID(parts[1], 32) && isValidTraceID(parts[2], 16) {
+			tc.TraceID = parts[1]
+			tc.ParentID = parts[2]
+
+			if parent, err := trace.ParseSpanContext(tc.TraceID, tc.ParentID); err == nil {
+				ctx = trace.ContextWithRemoteParent(ctx, parent)
+			}
+		} else {
+			tc.TraceID = randomHex(16)
+		}
+
+		ctx, span := trace.StartSpan(ctx, "rpc.request",
+			trace.Attribute{Key: "method", Value: requestFromContext(r).Method},
+		)
+		defer span.End()
+
+		if span != nil {
+			tc.TraceID = span.Context().TraceID.String()
+			tc.SpanID = span.Context().SpanID.String()
+		}
+
+		w.Header().Set(traceparentHeader, tc.String())
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, traceContextKey, tc)))
+	})
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// loggingMiddleware logs every RPC request along with its latency
+func (h *HTTPServer) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		next.ServeHTTP(rec, r)
+
+		ctx := []interface{}{
+			"method", requestFromContext(r).Method,
+			"status", rec.status,
+			"latency", time.Since(start),
+			"remote", r.RemoteAddr,
+		}
+
+		if tc := TraceContextFromRequest(r); tc != nil {
+			ctx = append(ctx, "trace", tc.TraceID, "span", tc.SpanID)
+		}
+
+		h.logger.Info("rpc request", ctx...)
+	})
+}
+
+// authMiddleware rejects calls to unsafe methods that do not provide the given bearer token
+func (h *HTTPServer) authMiddleware(token string, unsafeMethods []string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := requestFromContext(r)
+			if !isUnsafeMethod(req.Method, unsafeMethods) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.logger.Debug("rejected unauthorized rpc call", "method", req.Method, "remote", r.RemoteAddr)
+
+			id, _ := req.ID.(float64)
+			res := &ErrorResponseJSON{
+				Jsonrpc: "2.0",
+				Error: &ErrorMessageJSON{
+					Code:    big.NewInt(-32001),
+					Message: "Unauthorized",
+				},
+				ID: id,
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			if err := json.NewEncoder(w).Encode(res); err != nil {
+				h.logger.Debug("failed to write rpc response", "error", err)
+			}
+		})
+	}
+}
+
+// isUnsafeMethod returns true if the method matches one of the unsafe methods, entries ending
+// in * match every method with the given prefix
+func isUnsafeMethod(method string, unsafeMethods []string) bool {
+	for _, m := range unsafeMethods {
+		if strings.HasSuffix(m, "*") && strings.HasPrefix(method, strings.TrimSuffix(m, "*")) {
+			return true
+		}
+
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isValidTraceID(id string, size int) bool {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != size {
+		return false
+	}
+
+	// an all-zero ID is invalid
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
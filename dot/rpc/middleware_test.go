@@ -0,0 +1,129 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMiddlewareServer() *HTTPServer {
+	return &HTTPServer{
+		logger: log.New("pkg", "rpc"),
+	}
+}
+
+func newTestRequest(t *testing.T, body string) *http.Request {
+	req, err := http.NewRequest("POST", "/", bytes.NewBufferString(body))
+	require.NoError(t, err)
+	return req
+}
+
+func TestMethodMiddleware_RestoresBody(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"system_name","params":[],"id":1}`
+
+	var method, read string
+	handler := newTestMiddlewareServer().methodMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = requestFromContext(r).Method
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		read = string(b)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, body))
+	require.Equal(t, "system_name", method)
+	require.Equal(t, body, read)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h := newTestMiddlewareServer()
+	handler := h.methodMiddleware(h.authMiddleware("secret", DefaultUnsafeMethods)(next))
+
+	// safe methods do not require the token
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestRequest(t, `{"method":"system_name","id":1}`))
+	require.True(t, called)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// unsafe methods are rejected without the token
+	called = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestRequest(t, `{"method":"dev_control","id":2}`))
+	require.False(t, called)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), "Unauthorized")
+
+	// and with the wrong token
+	req := newTestRequest(t, `{"method":"author_insertKey","id":3}`)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.False(t, called)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = newTestRequest(t, `{"method":"author_insertKey","id":4}`)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.True(t, called)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	var tc *TraceContext
+	handler := tracingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc = TraceContextFromRequest(r)
+	}))
+
+	// a new trace is started if the request has none
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestRequest(t, ""))
+	require.NotNil(t, tc)
+	require.Len(t, tc.TraceID, 32)
+	require.Empty(t, tc.ParentID)
+	require.Equal(t, tc.String(), rec.Header().Get(traceparentHeader))
+
+	// an incoming trace is continued
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentID := "00f067aa0ba902b7"
+	req := newTestRequest(t, "")
+	req.Header.Set(traceparentHeader, strings.Join([]string{"00", traceID, parentID, "01"}, "-"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, traceID, tc.TraceID)
+	require.Equal(t, parentID, tc.ParentID)
+	require.NotEqual(t, parentID, tc.SpanID)
+}
+
+func TestIsUnsafeMethod(t *testing.T) {
+	unsafe := []string{"author_insertKey", "dev_*"}
+	require.True(t, isUnsafeMethod("author_insertKey", unsafe))
+	require.True(t, isUnsafeMethod("dev_control", unsafe))
+	require.False(t, isUnsafeMethod("author_submitExtrinsic", unsafe))
+	require.False(t, isUnsafeMethod("", unsafe))
+}
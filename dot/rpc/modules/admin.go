@@ -0,0 +1,139 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"math/big"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// AdminModule is an RPC module providing emergency controls over the node's chain selection.
+// Its methods are unsafe and require the RPC auth token when one is configured.
+type AdminModule struct {
+	blockAPI BlockAPI
+}
+
+// AdminBlockHashRequest holds a hex encoded block hash
+type AdminBlockHashRequest struct {
+	Hash string `json:"hash"`
+}
+
+// AdminSteeringResponse holds the bad blocks, the forced best block and the resulting best block
+type AdminSteeringResponse struct {
+	BadBlocks       []string `json:"badBlocks"`
+	ForcedBestBlock string   `json:"forcedBestBlock,omitempty"`
+	BestBlock       string   `json:"bestBlock"`
+}
+
+// AdminHaltRequest holds the number of the block to halt at, 0 clears the halt block
+type AdminHaltRequest struct {
+	Number uint64 `json:"number"`
+}
+
+// AdminHaltResponse holds the number of the block the node halts at, 0 if it isn't set
+type AdminHaltResponse struct {
+	HaltAtBlock uint64 `json:"haltAtBlock"`
+}
+
+// NewAdminModule creates a new Admin module.
+func NewAdminModule(api BlockAPI) *AdminModule {
+	return &AdminModule{
+		blockAPI: api,
+	}
+}
+
+// MarkBadBlock marks a block as bad, so that neither it nor its descendants are ever part of the best chain
+func (am *AdminModule) MarkBadBlock(r *http.Request, req *AdminBlockHashRequest, res *AdminSteeringResponse) error {
+	hash, err := common.HexToHash(req.Hash)
+	if err != nil {
+		return err
+	}
+
+	err = am.blockAPI.MarkBadBlock(hash)
+	if err != nil {
+		return err
+	}
+
+	*res = am.steering()
+	return nil
+}
+
+// ForceBestBlock sets a block as the best block, only blocks descending from it are considered for the best
+// chain afterwards. An empty hash clears the forced best block.
+func (am *AdminModule) ForceBestBlock(r *http.Request, req *AdminBlockHashRequest, res *AdminSteeringResponse) error {
+	var hash common.Hash
+	if req.Hash != "" {
+		var err error
+		hash, err = common.HexToHash(req.Hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := am.blockAPI.ForceBestBlock(hash)
+	if err != nil {
+		return err
+	}
+
+	*res = am.steering()
+	return nil
+}
+
+// Steering returns the bad blocks, the forced best block and the resulting best block
+func (am *AdminModule) Steering(r *http.Request, req *EmptyRequest, res *AdminSteeringResponse) error {
+	*res = am.steering()
+	return nil
+}
+
+func (am *AdminModule) steering() AdminSteeringResponse {
+	res := AdminSteeringResponse{
+		BadBlocks: []string{},
+		BestBlock: am.blockAPI.BestBlockHash().String(),
+	}
+
+	for _, hash := range am.blockAPI.BadBlocks() {
+		res.BadBlocks = append(res.BadBlocks, hash.String())
+	}
+
+	if forced := am.blockAPI.ForcedBestBlock(); forced != (common.Hash{}) {
+		res.ForcedBestBlock = forced.String()
+	}
+
+	return res
+}
+
+// HaltAtBlock sets the number of the last block the node imports or authors, the node shuts down once it's imported.
+// Operators use it to stop at an exact height for snapshots and migrations. A number of 0 clears the halt block.
+func (am *AdminModule) HaltAtBlock(r *http.Request, req *AdminHaltRequest, res *AdminHaltResponse) error {
+	var num *big.Int
+	if req.Number != 0 {
+		num = new(big.Int).SetUint64(req.Number)
+	}
+
+	err := am.blockAPI.SetHaltAtBlock(num)
+	if err != nil {
+		return err
+	}
+
+	if halt := am.blockAPI.HaltAtBlock(); halt != nil {
+		res.HaltAtBlock = halt.Uint64()
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminModule_MarkBadBlock(t *testing.T) {
+	st := newTestStateService(t)
+	am := NewAdminModule(st.Block)
+
+	best := st.Block.BestBlockHash()
+	header, err := st.Block.GetHeader(best)
+	require.NoError(t, err)
+
+	res := AdminSteeringResponse{}
+	err = am.MarkBadBlock(nil, &AdminBlockHashRequest{Hash: best.String()}, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{best.String()}, res.BadBlocks)
+	require.Equal(t, header.ParentHash.String(), res.BestBlock)
+
+	err = am.MarkBadBlock(nil, &AdminBlockHashRequest{Hash: "0x01"}, &res)
+	require.NoError(t, err)
+	require.Len(t, res.BadBlocks, 2)
+}
+
+func TestAdminModule_ForceBestBlock(t *testing.T) {
+	st := newTestStateService(t)
+	am := NewAdminModule(st.Block)
+
+	best := st.Block.BestBlockHash()
+	header, err := st.Block.GetHeader(best)
+	require.NoError(t, err)
+
+	res := AdminSteeringResponse{}
+	err = am.ForceBestBlock(nil, &AdminBlockHashRequest{Hash: header.ParentHash.String()}, &res)
+	require.NoError(t, err)
+	require.Equal(t, header.ParentHash.String(), res.ForcedBestBlock)
+	require.Equal(t, header.ParentHash.String(), res.BestBlock)
+
+	err = am.ForceBestBlock(nil, &AdminBlockHashRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, "", res.ForcedBestBlock)
+	require.Equal(t, best.String(), res.BestBlock)
+
+	err = am.Steering(nil, &EmptyRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{}, res.BadBlocks)
+}
+
+func TestAdminModule_HaltAtBlock(t *testing.T) {
+	st := newTestStateService(t)
+	am := NewAdminModule(st.Block)
+
+	best, err := st.Block.BestBlockNumber()
+	require.NoError(t, err)
+
+	res := AdminHaltResponse{}
+	err = am.HaltAtBlock(nil, &AdminHaltRequest{Number: best.Uint64() + 10}, &res)
+	require.NoError(t, err)
+	require.Equal(t, best.Uint64()+10, res.HaltAtBlock)
+	require.Equal(t, new(big.Int).Add(best, big.NewInt(10)), st.Block.HaltAtBlock())
+
+	err = am.HaltAtBlock(nil, &AdminHaltRequest{}, &res)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), res.HaltAtBlock)
+	require.Nil(t, st.Block.HaltAtBlock())
+
+	// the test state has blocks past genesis, so the block before the best block is in the past
+	err = am.HaltAtBlock(nil, &AdminHaltRequest{Number: best.Uint64() - 1}, &res)
+	require.True(t, errors.Is(err, state.ErrHaltInPast))
+}
@@ -5,8 +5,11 @@ import (
 
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/blockstats"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/memstats"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 )
@@ -18,19 +21,33 @@ type StorageAPI interface {
 	Entries(root *common.Hash) (map[string][]byte, error)
 	RegisterStorageChangeChannel(ch chan<- *state.KeyValue) (byte, error)
 	UnregisterStorageChangeChannel(id byte)
+	InsertStorage(entries []*state.KeyValue) (common.Hash, error)
+	GetKeysPaged(bhash *common.Hash, prefix, startKey []byte, count uint32) (common.Hash, [][]byte, error)
+	StorageDiff(from, to common.Hash, prefix []byte) ([]*state.StorageChange, error)
 }
 
 // BlockAPI is the interface for the block state
 type BlockAPI interface {
 	GetHeader(hash common.Hash) (*types.Header, error)
+	GetHeaderEncoding(hash common.Hash) ([]byte, error)
 	BestBlockHash() common.Hash
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
 	GetBlockHash(blockNumber *big.Int) (*common.Hash, error)
+	IsDescendantOf(parent, child common.Hash) (bool, error)
 	GetFinalizedHash(uint64, uint64) (common.Hash, error)
+	HasJustification(hash common.Hash) (bool, error)
+	GetJustification(hash common.Hash) ([]byte, error)
 	RegisterImportedChannel(ch chan<- *types.Block) (byte, error)
 	UnregisterImportedChannel(id byte)
 	RegisterFinalizedChannel(ch chan<- *types.Header) (byte, error)
 	UnregisterFinalizedChannel(id byte)
+	MarkBadBlock(hash common.Hash) error
+	BadBlocks() []common.Hash
+	ForceBestBlock(hash common.Hash) error
+	ForcedBestBlock() common.Hash
+	SetHaltAtBlock(num *big.Int) error
+	HaltAtBlock() *big.Int
+	GetExtrinsicInclusions(hash common.Hash) ([]*state.ExtrinsicInclusion, error)
 }
 
 // NetworkAPI interface for network state methods
@@ -39,6 +56,7 @@ type NetworkAPI interface {
 	NetworkState() common.NetworkState
 	Peers() []common.PeerInfo
 	NodeRoles() byte
+	NodeIdentity() *common.NodeIdentity
 	Stop() error
 	Start() error
 	IsStopped() bool
@@ -51,24 +69,40 @@ type BlockProducerAPI interface {
 	SetAuthorities(data []*types.Authority) error
 	SetRandomness([types.RandomnessLength]byte)
 	SetThreshold(*big.Int)
+	Configuration() *types.BabeConfiguration
+	CurrentEpoch() (uint64, error)
+	Descriptor() *babe.Descriptor
+	AuthorshipStats() []*babe.AuthorshipStats
+	AuthorshipProof(header *types.Header) (*babe.AuthorshipProof, error)
+	EpochSchedules() ([]*babe.EpochSchedule, error)
 }
 
 // TransactionStateAPI ...
 type TransactionStateAPI interface {
-	AddToPool(*transaction.ValidTransaction) common.Hash
+	AddToPool(*transaction.ValidTransaction) (common.Hash, error)
 	Pop() *transaction.ValidTransaction
 	Peek() *transaction.ValidTransaction
 	Pending() []*transaction.ValidTransaction
+	IsBanned(ext types.Extrinsic) bool
+	RecordValidationFailure(ext types.Extrinsic) bool
+	RemoveAndBan(hash common.Hash) bool
+	AddFuture(ext types.Extrinsic) (common.Hash, error)
+	RegisterPoolEventChannel(ch chan<- *transaction.PoolEvent) (byte, error)
+	UnregisterPoolEventChannel(id byte)
 }
 
 // CoreAPI is the interface for the core methods
 type CoreAPI interface {
-	InsertKey(kp crypto.Keypair)
+	InsertKey(kp crypto.Keypair, keyType string) error
 	HasKey(pubKeyStr string, keyType string) (bool, error)
+	HasSessionKeys(keys []byte) (bool, error)
+	RotateKeys() ([]byte, error)
 	GetRuntimeVersion() (*runtime.VersionAPI, error)
 	IsBlockProducer() bool
 	HandleSubmittedExtrinsic(types.Extrinsic) error
 	GetMetadata() ([]byte, error)
+	CallRuntime(function string, data []byte, bhash *common.Hash) ([]byte, error)
+	SimulateExtrinsic(ext types.Extrinsic, bhash *common.Hash) ([]byte, []byte, error)
 }
 
 // RPCAPI is the interface for methods related to RPC service
@@ -89,3 +123,23 @@ type SystemAPI interface {
 	NodeName() string
 	Properties() map[string]interface{}
 }
+
+// SyncAPI is the interface for the block synchronisation status
+type SyncAPI interface {
+	SyncState() *types.SyncState
+}
+
+// SyncStateAPI is the interface for building a chain spec with the light sync state of the node
+type SyncStateAPI interface {
+	GenSyncSpec(raw bool) ([]byte, error)
+}
+
+// MemoryAPI is the interface for the memory usage of the node's subsystems
+type MemoryAPI interface {
+	Usage() []memstats.Usage
+}
+
+// BlockStatsAPI is the interface for the block time, propagation delay and missed slot stats of the node
+type BlockStatsAPI interface {
+	Stats() blockstats.Stats
+}
@@ -17,6 +17,8 @@
 package modules
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -24,6 +26,8 @@ import (
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/scale"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	log "github.com/ChainSafe/log15"
 )
@@ -42,15 +46,104 @@ type KeyInsertRequest []string
 // Extrinsic represents a hex-encoded extrinsic
 type Extrinsic string
 
+// SubmitExtrinsicOptions are the per-submission options of author_submitExtrinsicWithOptions
+type SubmitExtrinsicOptions struct {
+	// Propagate is false if the extrinsic must not be gossiped to peers, it defaults to true.
+	// Extrinsics are never gossiped if the node is run with --no-propagate.
+	Propagate *bool `json:"propagate"`
+}
+
+// SubmitExtrinsicWithOptionsRequest holds a hex-encoded extrinsic and its submission options
+type SubmitExtrinsicWithOptionsRequest struct {
+	Extrinsic Extrinsic
+	Options   SubmitExtrinsicOptions
+}
+
+// UnmarshalJSON decodes the request from the [extrinsic, options] params array, the options are optional
+func (r *SubmitExtrinsicWithOptionsRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	err := json.Unmarshal(data, &params)
+	if err != nil {
+		return err
+	}
+
+	if len(params) == 0 || len(params) > 2 {
+		return errors.New("expected extrinsic and options parameters")
+	}
+
+	err = json.Unmarshal(params[0], &r.Extrinsic)
+	if err != nil {
+		return err
+	}
+
+	if len(params) == 1 {
+		return nil
+	}
+
+	return json.Unmarshal(params[1], &r.Options)
+}
+
 // ExtrinsicOrHash is a type for Hash and Extrinsic array of bytes
 type ExtrinsicOrHash struct {
 	Hash      common.Hash
 	Extrinsic []byte
 }
 
+// UnmarshalJSON decodes an object holding either a hex-encoded "hash" or a hex-encoded "extrinsic"
+func (e *ExtrinsicOrHash) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		Hash      *string `json:"hash"`
+		Extrinsic *string `json:"extrinsic"`
+	}
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case obj.Hash != nil && obj.Extrinsic == nil:
+		hash, err := common.HexToBytes(*obj.Hash)
+		if err != nil {
+			return err
+		}
+		if len(hash) != len(common.Hash{}) {
+			return errors.New("invalid hash length")
+		}
+		e.Hash = common.BytesToHash(hash)
+		return nil
+	case obj.Extrinsic != nil && obj.Hash == nil:
+		e.Extrinsic, err = common.HexToBytes(*obj.Extrinsic)
+		return err
+	}
+
+	return errors.New("expected either a hash or an extrinsic")
+}
+
 // ExtrinsicOrHashRequest is a array of ExtrinsicOrHash
 type ExtrinsicOrHashRequest []ExtrinsicOrHash
 
+// UnmarshalJSON decodes the request from the [[extrinsicOrHash, ...]] params array
+func (r *ExtrinsicOrHashRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	err := json.Unmarshal(data, &params)
+	if err != nil {
+		return err
+	}
+
+	if len(params) != 1 {
+		return errors.New("expected a list of extrinsics or hashes")
+	}
+
+	var items []ExtrinsicOrHash
+	err = json.Unmarshal(params[0], &items)
+	if err != nil {
+		return err
+	}
+
+	*r = items
+	return nil
+}
+
 // KeyInsertResponse []byte
 type KeyInsertResponse []byte
 
@@ -63,6 +156,11 @@ type RemoveExtrinsicsResponse []common.Hash
 // KeyRotateResponse is a byte array used to rotate
 type KeyRotateResponse []byte
 
+// MarshalJSON encodes the concatenated public keys as a 0x prefixed hex string
+func (r KeyRotateResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(common.BytesToHex(r))
+}
+
 // ExtrinsicStatus holds the actual valid statuses
 type ExtrinsicStatus struct {
 	IsFuture    bool
@@ -80,6 +178,27 @@ type ExtrinsicStatus struct {
 // ExtrinsicHashResponse is used as Extrinsic hash response
 type ExtrinsicHashResponse string
 
+// SimulateExtrinsicRequest holds the hex-encoded extrinsic to simulate and an optional block hash to simulate it on
+type SimulateExtrinsicRequest struct {
+	Ext  string       `json:"ext"`
+	Hash *common.Hash `json:"hash"`
+}
+
+// SimulateExtrinsicResponse holds the outcome of a simulated extrinsic. Weight, Class and PartialFee are the dispatch
+// info given by the runtime's TransactionPaymentApi, they're empty if the runtime doesn't implement it. Events is the
+// encoded System.Events storage value after the extrinsic was applied, and DecodedEvents are the events decoded with
+// the runtime metadata, up to the first one that couldn't be decoded.
+type SimulateExtrinsicResponse struct {
+	Success       bool            `json:"success"`
+	Error         string          `json:"error,omitempty"`
+	Weight        uint64          `json:"weight"`
+	Class         string          `json:"class"`
+	PartialFee    string          `json:"partialFee"`
+	Events        string          `json:"events"`
+	DecodedEvents []*RuntimeEvent `json:"decodedEvents"`
+	Result        string          `json:"result"`
+}
+
 // NewAuthorModule creates a new Author module.
 func NewAuthorModule(logger log.Logger, coreAPI CoreAPI, runtimeAPI RuntimeAPI, txStateAPI TransactionStateAPI) *AuthorModule {
 	if logger == nil {
@@ -117,7 +236,11 @@ func (cm *AuthorModule) InsertKey(r *http.Request, req *KeyInsertRequest, res *K
 		return fmt.Errorf("generated public key does not equal provide public key")
 	}
 
-	cm.coreAPI.InsertKey(keyPair)
+	err = cm.coreAPI.InsertKey(keyPair, keyReq[0])
+	if err != nil {
+		return err
+	}
+
 	cm.logger.Info("inserted key into keystore", "key", keyPair.Public().Hex())
 	return nil
 }
@@ -130,6 +253,22 @@ func (cm *AuthorModule) HasKey(r *http.Request, req *[]string, res *bool) error
 	return err
 }
 
+// HasSessionKeys Checks if the keystore has private keys for all the public keys in the given session keys, which
+// are concatenated like the result of RotateKeys.
+func (cm *AuthorModule) HasSessionKeys(r *http.Request, req *[]string, res *bool) error {
+	if len(*req) == 0 {
+		return errors.New("expected session keys parameter")
+	}
+
+	keys, err := common.HexToBytes((*req)[0])
+	if err != nil {
+		return err
+	}
+
+	*res, err = cm.coreAPI.HasSessionKeys(keys)
+	return err
+}
+
 // PendingExtrinsics Returns all pending extrinsics
 func (cm *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, res *PendingExtrinsicsResponse) error {
 	pending := cm.txStateAPI.Pending()
@@ -147,23 +286,143 @@ func (cm *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, re
 }
 
 // RemoveExtrinsic Remove given extrinsic from the pool and temporarily ban it to prevent reimporting
+// The returned hashes are those of the extrinsics that were actually removed.
 func (cm *AuthorModule) RemoveExtrinsic(r *http.Request, req *ExtrinsicOrHashRequest, res *RemoveExtrinsicsResponse) error {
+	removed := RemoveExtrinsicsResponse{}
+	for _, item := range *req {
+		hash := item.Hash
+		if len(item.Extrinsic) > 0 {
+			hash = types.Extrinsic(item.Extrinsic).Hash()
+		}
+
+		if cm.txStateAPI.RemoveAndBan(hash) {
+			removed = append(removed, hash)
+		}
+	}
+
+	*res = removed
 	return nil
 }
 
 // RotateKeys Generate new session keys and returns the corresponding public keys
 func (cm *AuthorModule) RotateKeys(r *http.Request, req *EmptyRequest, res *KeyRotateResponse) error {
+	keys, err := cm.coreAPI.RotateKeys()
+	if err != nil {
+		return err
+	}
+
+	*res = KeyRotateResponse(keys)
 	return nil
 }
 
-// SubmitAndWatchExtrinsic Submit and subscribe to watch an extrinsic until unsubscribed
+// SubmitAndWatchExtrinsic Submit and subscribe to watch an extrinsic until unsubscribed. Watches are subscriptions,
+// so they are only available over websocket, which handles this call itself.
 func (cm *AuthorModule) SubmitAndWatchExtrinsic(r *http.Request, req *Extrinsic, res *ExtrinsicStatus) error {
+	return ErrSubscriptionTransport
+}
+
+// UnwatchExtrinsic cancels a watch started with SubmitAndWatchExtrinsic. Watches are subscriptions, so they are
+// only available over websocket, which handles this call itself.
+func (cm *AuthorModule) UnwatchExtrinsic(r *http.Request, req *[]interface{}, res *bool) error {
+	return errors.New("extrinsic watches are only available over websocket")
+}
+
+// SimulateExtrinsic applies the given extrinsic on top of the given block, or the best block, without importing it
+// and returns its dispatch result, the weight it consumed and the events it deposited
+func (cm *AuthorModule) SimulateExtrinsic(r *http.Request, req *SimulateExtrinsicRequest, res *SimulateExtrinsicResponse) error {
+	ext, err := common.HexToBytes(req.Ext)
+	if err != nil {
+		return err
+	}
+
+	ret, events, err := cm.coreAPI.SimulateExtrinsic(types.Extrinsic(ext), req.Hash)
+	if err != nil {
+		return err
+	}
+
+	applyErr, err := decodeApplyExtrinsicResult(ret)
+	if err != nil {
+		return err
+	}
+
+	*res = SimulateExtrinsicResponse{
+		Success: applyErr == "",
+		Error:   applyErr,
+		Events:  common.BytesToHex(events),
+		Result:  common.BytesToHex(ret),
+	}
+
+	info, err := queryDispatchInfo(cm.coreAPI, ext, req.Hash)
+	if err != nil {
+		cm.logger.Debug("failed to query dispatch info of simulated extrinsic", "error", err)
+	} else {
+		res.Weight = info.Weight
+		res.Class = info.Class
+		res.PartialFee = info.PartialFee
+	}
+
+	res.DecodedEvents, err = cm.decodeEvents(events, req.Hash)
+	if err != nil {
+		cm.logger.Debug("failed to decode events of simulated extrinsic", "error", err)
+	}
 	return nil
 }
 
+// decodeEvents decodes the given encoded events using the runtime metadata at the block with the given hash, or
+// the best block if the hash is nil
+func (cm *AuthorModule) decodeEvents(events []byte, bhash *common.Hash) ([]*RuntimeEvent, error) {
+	ret, err := cm.coreAPI.CallRuntime(runtime.Metadata, []byte{}, bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	// the metadata is returned as an encoded byte array
+	metadata, err := scale.Decode(ret, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := decodeMetadataEvents(metadata.([]byte))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEvents(events, modules)
+}
+
 // SubmitExtrinsic Submit a fully formatted extrinsic for block inclusion
 func (cm *AuthorModule) SubmitExtrinsic(r *http.Request, req *Extrinsic, res *ExtrinsicHashResponse) error {
-	extBytes, err := common.HexToBytes(string(*req))
+	return cm.submitExtrinsic(*req, true, res)
+}
+
+// SubmitExtrinsicWithOptions submits a fully formatted extrinsic for block inclusion, with options controlling
+// whether it is gossiped to peers
+func (cm *AuthorModule) SubmitExtrinsicWithOptions(r *http.Request, req *SubmitExtrinsicWithOptionsRequest, res *ExtrinsicHashResponse) error {
+	propagate := req.Options.Propagate == nil || *req.Options.Propagate
+	return cm.submitExtrinsic(req.Extrinsic, propagate, res)
+}
+
+// submitFutureExtrinsic holds an extrinsic that isn't valid yet until it becomes valid, rather than rejecting it
+func (cm *AuthorModule) submitFutureExtrinsic(ext types.Extrinsic, propagate bool, res *ExtrinsicHashResponse) error {
+	if cm.coreAPI.IsBlockProducer() {
+		hash, err := cm.txStateAPI.AddFuture(ext)
+		if err != nil {
+			return err
+		}
+
+		cm.logger.Trace("submitted future extrinsic", "hash", hash)
+	}
+
+	*res = ExtrinsicHashResponse(ext.Hash().String())
+	if !propagate {
+		return nil
+	}
+
+	return cm.coreAPI.HandleSubmittedExtrinsic(ext)
+}
+
+func (cm *AuthorModule) submitExtrinsic(req Extrinsic, propagate bool, res *ExtrinsicHashResponse) error {
+	extBytes, err := common.HexToBytes(string(req))
 	if err != nil {
 		return err
 	}
@@ -171,20 +430,38 @@ func (cm *AuthorModule) SubmitExtrinsic(r *http.Request, req *Extrinsic, res *Ex
 	cm.logger.Trace("[rpc]", "extrinsic", extBytes)
 
 	ext := types.Extrinsic(extBytes)
+	if cm.txStateAPI.IsBanned(ext) {
+		return ErrTransactionBanned
+	}
+
 	// validate the transaction
 	txv, err := cm.runtimeAPI.ValidateTransaction(ext)
+	if errors.Is(err, runtime.ErrFutureTransaction) {
+		return cm.submitFutureExtrinsic(ext, propagate, res)
+	}
 	if err != nil {
+		cm.txStateAPI.RecordValidationFailure(ext)
 		return err
 	}
 
 	vtx := transaction.NewValidTransaction(ext, txv)
+	vtx.Local = true
 
 	if cm.coreAPI.IsBlockProducer() {
-		hash := cm.txStateAPI.AddToPool(vtx)
+		hash, err := cm.txStateAPI.AddToPool(vtx)
+		if err != nil {
+			return err
+		}
+
 		*res = ExtrinsicHashResponse(hash.String())
 		cm.logger.Trace("submitted extrinsic", "tx", vtx, "hash", hash.String())
 	}
 
+	if !propagate {
+		cm.logger.Trace("not propagating submitted extrinsic", "hash", ext.Hash())
+		return nil
+	}
+
 	//broadcast
 	err = cm.coreAPI.HandleSubmittedExtrinsic(ext)
 	if err != nil {
@@ -193,3 +470,62 @@ func (cm *AuthorModule) SubmitExtrinsic(r *http.Request, req *Extrinsic, res *Ex
 
 	return err
 }
+
+var (
+	invalidTransactionErrors = []string{"Call", "Payment", "Future", "Stale", "BadProof", "AncientBirthBlock",
+		"ExhaustsResources", "Custom", "BadMandatory", "MandatoryDispatch"}
+	unknownTransactionErrors = []string{"CannotLookup", "NoUnsignedValidator", "Custom"}
+	dispatchErrors           = []string{"Other", "CannotLookup", "BadOrigin", "Module"}
+)
+
+// decodeApplyExtrinsicResult decodes an ApplyExtrinsicResult, which is a Result<DispatchOutcome, TransactionValidityError>
+// where DispatchOutcome is a Result<(), DispatchError>. It returns an empty string if the extrinsic was dispatched
+// successfully, and a description of the error otherwise.
+func decodeApplyExtrinsicResult(in []byte) (string, error) {
+	if len(in) < 2 {
+		return "", errors.New("invalid apply extrinsic result length")
+	}
+
+	switch in[0] {
+	case 0:
+		if in[1] == 0 {
+			return "", nil
+		}
+		if in[1] != 1 || len(in) < 3 || int(in[2]) >= len(dispatchErrors) {
+			return "", fmt.Errorf("invalid dispatch outcome 0x%x", in[1:])
+		}
+
+		name := dispatchErrors[in[2]]
+		if name == "Module" {
+			if len(in) < 5 {
+				return "", errors.New("invalid module error length")
+			}
+			return fmt.Sprintf("DispatchError: Module { index: %d, error: %d }", in[3], in[4]), nil
+		}
+		return "DispatchError: " + name, nil
+	case 1:
+		if len(in) < 3 {
+			return "", errors.New("invalid transaction validity error length")
+		}
+
+		var names []string
+		switch in[1] {
+		case 0:
+			names = invalidTransactionErrors
+		case 1:
+			names = unknownTransactionErrors
+		default:
+			return "", fmt.Errorf("invalid transaction validity error %d", in[1])
+		}
+
+		if int(in[2]) >= len(names) {
+			return "", fmt.Errorf("invalid transaction validity error %d", in[2])
+		}
+		if in[1] == 0 {
+			return "InvalidTransaction: " + names[in[2]], nil
+		}
+		return "UnknownTransaction: " + names[in[2]], nil
+	default:
+		return "", fmt.Errorf("invalid apply extrinsic result %d", in[0])
+	}
+}
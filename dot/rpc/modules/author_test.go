@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
@@ -86,6 +88,7 @@ func TestAuthorModule_SubmitExtrinsic(t *testing.T) {
 	expected := &transaction.ValidTransaction{
 		Extrinsic: types.NewExtrinsic(testExt),
 		Validity:  val,
+		Local:     true,
 	}
 	expectedHash := ExtrinsicHashResponse("0xb20777f4db60ea55b1aeedde2d7b7aff3efeda736b7e2a840b5713348f766078")
 
@@ -112,6 +115,57 @@ func TestAuthorModule_SubmitExtrinsic_invalid(t *testing.T) {
 	require.EqualError(t, err, runtime.ErrInvalidTransaction.Message)
 }
 
+func TestAuthorModule_SubmitExtrinsic_Banned(t *testing.T) {
+	txQueue := state.NewTransactionState()
+	auth := setupAuthModule(t, txQueue)
+
+	ext := Extrinsic("0x010203")
+	res := new(ExtrinsicHashResponse)
+
+	for i := 0; i < transaction.DefaultMaxValidationFailures; i++ {
+		err := auth.SubmitExtrinsic(nil, &ext, res)
+		require.Error(t, err)
+	}
+
+	err := auth.SubmitExtrinsic(nil, &ext, res)
+	require.Equal(t, ErrTransactionBanned, err)
+}
+
+func TestAuthorModule_RemoveExtrinsic(t *testing.T) {
+	txQueue := state.NewTransactionState()
+	auth := NewAuthorModule(nil, nil, nil, txQueue)
+
+	exts := []types.Extrinsic{{1}, {2}}
+	for _, ext := range exts {
+		_, err := txQueue.Push(&transaction.ValidTransaction{
+			Extrinsic: ext,
+			Validity:  &transaction.Validity{Priority: 1},
+		})
+		require.NoError(t, err)
+	}
+
+	req := &ExtrinsicOrHashRequest{
+		{Hash: exts[0].Hash()},
+		{Extrinsic: exts[1]},
+		{Hash: common.Hash{0xff}},
+	}
+	res := new(RemoveExtrinsicsResponse)
+
+	err := auth.RemoveExtrinsic(nil, req, res)
+	require.NoError(t, err)
+	require.Equal(t, RemoveExtrinsicsResponse{exts[0].Hash(), exts[1].Hash()}, *res)
+	require.Nil(t, txQueue.Peek())
+
+	for _, ext := range exts {
+		require.True(t, txQueue.IsBanned(ext))
+	}
+
+	// banned extrinsics can't be resubmitted
+	ext := Extrinsic(common.BytesToHex(exts[0]))
+	err = auth.SubmitExtrinsic(nil, &ext, new(ExtrinsicHashResponse))
+	require.Equal(t, ErrTransactionBanned, err)
+}
+
 func TestAuthorModule_SubmitExtrinsic_invalid_input(t *testing.T) {
 	// setup service
 	// setup auth module
@@ -252,6 +306,47 @@ func TestAuthorModule_HasKey_InvalidKeyType(t *testing.T) {
 	require.False(t, res)
 }
 
+func TestAuthorModule_RotateKeys(t *testing.T) {
+	auth := setupAuthModule(t, nil)
+
+	res := &KeyRotateResponse{}
+	err := auth.RotateKeys(nil, &EmptyRequest{}, res)
+	require.NoError(t, err)
+	require.Len(t, *res, 4*32)
+
+	enc, err := json.Marshal(res)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%q", common.BytesToHex(*res)), string(enc))
+}
+
+func TestAuthorModule_HasSessionKeys(t *testing.T) {
+	auth := setupAuthModule(t, nil)
+
+	keys := &KeyRotateResponse{}
+	err := auth.RotateKeys(nil, &EmptyRequest{}, keys)
+	require.NoError(t, err)
+
+	var res bool
+	req := []string{common.BytesToHex(*keys)}
+	err = auth.HasSessionKeys(nil, &req, &res)
+	require.NoError(t, err)
+	require.True(t, res)
+
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	// the babe key is replaced by one that isn't in the keystore
+	other := append(append(append([]byte{}, (*keys)[:32]...), kr.Bob().Public().Encode()...), (*keys)[64:]...)
+	req = []string{common.BytesToHex(other)}
+	err = auth.HasSessionKeys(nil, &req, &res)
+	require.NoError(t, err)
+	require.False(t, res)
+
+	req = []string{"0x0102"}
+	err = auth.HasSessionKeys(nil, &req, &res)
+	require.Equal(t, core.ErrInvalidSessionKeys, err)
+}
+
 func newCoreService(t *testing.T) *core.Service {
 	// setup service
 	tt := trie.NewEmptyTrie()
@@ -262,6 +357,7 @@ func newCoreService(t *testing.T) *core.Service {
 	kr, err := keystore.NewSr25519Keyring()
 	require.NoError(t, err)
 	ks.Acco.Insert(kr.Alice())
+	ks.Babe.Insert(kr.Alice())
 
 	cfg := &core.Config{
 		Runtime:          rt,
@@ -278,3 +374,56 @@ func setupAuthModule(t *testing.T, txq *state.TransactionState) *AuthorModule {
 	rt := wasmer.NewTestLegacyInstance(t, runtime.LEGACY_NODE_RUNTIME)
 	return NewAuthorModule(nil, cs, rt, txq)
 }
+
+func TestSubmitExtrinsicWithOptionsRequest_UnmarshalJSON(t *testing.T) {
+	req := new(SubmitExtrinsicWithOptionsRequest)
+	err := json.Unmarshal([]byte(`["0x0102", {"propagate": false}]`), req)
+	require.NoError(t, err)
+	require.Equal(t, Extrinsic("0x0102"), req.Extrinsic)
+	require.False(t, *req.Options.Propagate)
+
+	req = new(SubmitExtrinsicWithOptionsRequest)
+	err = json.Unmarshal([]byte(`["0x0102"]`), req)
+	require.NoError(t, err)
+	require.Nil(t, req.Options.Propagate)
+
+	err = json.Unmarshal([]byte(`[]`), req)
+	require.Error(t, err)
+}
+
+func TestExtrinsicOrHashRequest_UnmarshalJSON(t *testing.T) {
+	hash := common.Hash{1}
+	req := new(ExtrinsicOrHashRequest)
+	err := json.Unmarshal([]byte(fmt.Sprintf(`[[{"hash": "%s"}, {"extrinsic": "0x0102"}]]`, hash)), req)
+	require.NoError(t, err)
+	require.Equal(t, ExtrinsicOrHashRequest{{Hash: hash}, {Extrinsic: []byte{1, 2}}}, *req)
+
+	for _, in := range []string{
+		`[]`,
+		`[[{}]]`,
+		`[[{"hash": "0x01"}]]`,
+		`[[{"hash": "0x01", "extrinsic": "0x01"}]]`,
+	} {
+		err = json.Unmarshal([]byte(in), new(ExtrinsicOrHashRequest))
+		require.Error(t, err, in)
+	}
+}
+
+func TestDecodeApplyExtrinsicResult(t *testing.T) {
+	for in, exp := range map[string]string{
+		"0x0000":       "",
+		"0x000102":     "DispatchError: BadOrigin",
+		"0x0001030502": "DispatchError: Module { index: 5, error: 2 }",
+		"0x010003":     "InvalidTransaction: Stale",
+		"0x010101":     "UnknownTransaction: NoUnsignedValidator",
+	} {
+		res, err := decodeApplyExtrinsicResult(common.MustHexToBytes(in))
+		require.NoError(t, err)
+		require.Equal(t, exp, res)
+	}
+
+	_, err := decodeApplyExtrinsicResult([]byte{2, 0})
+	require.Error(t, err)
+	_, err = decodeApplyExtrinsicResult([]byte{0, 1, 3})
+	require.Error(t, err)
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// BabeModule is an RPC module providing access to the BABE block producer
+type BabeModule struct {
+	blockProducerAPI BlockProducerAPI
+	blockAPI         BlockAPI
+}
+
+// BabeConfigurationResponse holds the active BABE configuration
+type BabeConfigurationResponse struct {
+	SlotDuration   uint64    `json:"slotDuration"`
+	EpochLength    uint64    `json:"epochLength"`
+	C              [2]uint64 `json:"c"`
+	SecondarySlots bool      `json:"secondarySlots"`
+	EpochIndex     uint64    `json:"epochIndex"`
+	Randomness     string    `json:"randomness"`
+}
+
+// AuthorshipStatsResponse holds the block authorship statistics of the node for an epoch
+type AuthorshipStatsResponse struct {
+	Epoch          uint64            `json:"epoch"`
+	ClaimedSlots   uint64            `json:"claimedSlots"`
+	AuthoredBlocks uint64            `json:"authoredBlocks"`
+	MissedSlots    uint64            `json:"missedSlots"`
+	MissedReasons  map[string]uint64 `json:"missedReasons"`
+}
+
+// BabeAuthorshipProofRequest holds the hex encoded hash of a block
+type BabeAuthorshipProofRequest struct {
+	Hash string `json:"hash"`
+}
+
+// BabeAuthorshipProofResponse holds the proof of a block author's right to author the block in its slot
+type BabeAuthorshipProofResponse struct {
+	Hash           string `json:"hash"`
+	Number         uint64 `json:"number"`
+	Slot           uint64 `json:"slot"`
+	AuthorityIndex uint64 `json:"authorityIndex"`
+	VrfOutput      string `json:"vrfOutput"`
+	VrfProof       string `json:"vrfProof"`
+	AuthoredByUs   bool   `json:"authoredByUs"`
+}
+
+// EpochScheduleResponse holds the predicted start, authority set and randomness of an epoch
+type EpochScheduleResponse struct {
+	Epoch       uint64                   `json:"epoch"`
+	StartSlot   uint64                   `json:"startSlot"`
+	StartTime   int64                    `json:"startTime"` // in milliseconds since the unix epoch
+	Authorities []EpochAuthorityResponse `json:"authorities"`
+	Randomness  string                   `json:"randomness"`
+}
+
+// EpochAuthorityResponse holds a BABE authority and its weight
+type EpochAuthorityResponse struct {
+	Key    string `json:"key"`
+	Weight uint64 `json:"weight"`
+}
+
+// NewBabeModule creates a new BABE module.
+func NewBabeModule(bp BlockProducerAPI, api BlockAPI) *BabeModule {
+	return &BabeModule{
+		blockProducerAPI: bp,
+		blockAPI:         api,
+	}
+}
+
+// GetConfiguration returns the BABE configuration in use by the node, along with the current epoch index
+// and randomness
+func (bm *BabeModule) GetConfiguration(r *http.Request, req *EmptyRequest, res *BabeConfigurationResponse) error {
+	if bm.blockProducerAPI == nil {
+		return errors.New("BABE service is not available")
+	}
+
+	cfg := bm.blockProducerAPI.Configuration()
+	epoch, err := bm.blockProducerAPI.CurrentEpoch()
+	if err != nil {
+		return err
+	}
+
+	randomness := bm.blockProducerAPI.Descriptor().Randomness
+
+	*res = BabeConfigurationResponse{
+		SlotDuration:   cfg.SlotDuration,
+		EpochLength:    cfg.EpochLength,
+		C:              [2]uint64{cfg.C1, cfg.C2},
+		SecondarySlots: cfg.SecondarySlots,
+		EpochIndex:     epoch,
+		Randomness:     common.BytesToHex(randomness[:]),
+	}
+	return nil
+}
+
+// GetAuthorshipStats returns the block authorship statistics of the node for the most recent epochs,
+// ordered by epoch
+func (bm *BabeModule) GetAuthorshipStats(r *http.Request, req *EmptyRequest, res *[]AuthorshipStatsResponse) error {
+	if bm.blockProducerAPI == nil {
+		return errors.New("BABE service is not available")
+	}
+
+	stats := bm.blockProducerAPI.AuthorshipStats()
+	*res = make([]AuthorshipStatsResponse, len(stats))
+	for i, s := range stats {
+		reasons := make(map[string]uint64, len(s.MissedSlots))
+		for reason, n := range s.MissedSlots {
+			reasons[string(reason)] = n
+		}
+
+		(*res)[i] = AuthorshipStatsResponse{
+			Epoch:          s.Epoch,
+			ClaimedSlots:   s.ClaimedSlots,
+			AuthoredBlocks: s.AuthoredBlocks,
+			MissedSlots:    s.TotalMissedSlots(),
+			MissedReasons:  reasons,
+		}
+	}
+
+	return nil
+}
+
+// GetEpochSchedule returns the start slot, start time, authorities and randomness of the current and next epochs
+func (bm *BabeModule) GetEpochSchedule(r *http.Request, req *EmptyRequest, res *[]EpochScheduleResponse) error {
+	if bm.blockProducerAPI == nil {
+		return errors.New("BABE service is not available")
+	}
+
+	schedules, err := bm.blockProducerAPI.EpochSchedules()
+	if err != nil {
+		return err
+	}
+
+	*res = make([]EpochScheduleResponse, len(schedules))
+	for i, s := range schedules {
+		auths := make([]EpochAuthorityResponse, len(s.Authorities))
+		for j, a := range s.Authorities {
+			auths[j] = EpochAuthorityResponse{
+				Key:    common.BytesToHex(a.Key.Encode()),
+				Weight: a.Weight,
+			}
+		}
+
+		(*res)[i] = EpochScheduleResponse{
+			Epoch:       s.Epoch,
+			StartSlot:   s.StartSlot,
+			StartTime:   s.StartTime.UnixNano() / int64(time.Millisecond),
+			Authorities: auths,
+			Randomness:  common.BytesToHex(s.Randomness[:]),
+		}
+	}
+
+	return nil
+}
+
+// GetAuthorshipProof returns the slot, VRF output and proof, and authority index of the block with the given hash,
+// decoded from its pre-digest, and whether the block was authored by the node
+func (bm *BabeModule) GetAuthorshipProof(r *http.Request, req *BabeAuthorshipProofRequest, res *BabeAuthorshipProofResponse) error {
+	if bm.blockProducerAPI == nil {
+		return errors.New("BABE service is not available")
+	}
+
+	hash, err := common.HexToHash(req.Hash)
+	if err != nil {
+		return err
+	}
+
+	header, err := bm.blockAPI.GetHeader(hash)
+	if err != nil {
+		return err
+	}
+
+	proof, err := bm.blockProducerAPI.AuthorshipProof(header)
+	if err != nil {
+		return err
+	}
+
+	*res = BabeAuthorshipProofResponse{
+		Hash:           hash.String(),
+		Number:         header.Number.Uint64(),
+		Slot:           proof.Slot,
+		AuthorityIndex: proof.AuthorityIndex,
+		VrfOutput:      common.BytesToHex(proof.VrfOutput[:]),
+		VrfProof:       common.BytesToHex(proof.VrfProof[:]),
+		AuthoredByUs:   proof.AuthoredByUs,
+	}
+	return nil
+}
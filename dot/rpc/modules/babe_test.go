@@ -0,0 +1,140 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBabeModule_GetConfiguration(t *testing.T) {
+	bs := newBABEService(t)
+	m := NewBabeModule(bs, nil)
+
+	var res BabeConfigurationResponse
+	err := m.GetConfiguration(nil, nil, &res)
+	require.NoError(t, err)
+
+	cfg := bs.Configuration()
+	require.Equal(t, cfg.SlotDuration, res.SlotDuration)
+	require.Equal(t, cfg.EpochLength, res.EpochLength)
+	require.Equal(t, [2]uint64{cfg.C1, cfg.C2}, res.C)
+	require.Equal(t, cfg.SecondarySlots, res.SecondarySlots)
+	require.Equal(t, uint64(1), res.EpochIndex)
+
+	randomness := bs.Descriptor().Randomness
+	require.Equal(t, common.BytesToHex(randomness[:]), res.Randomness)
+}
+
+func TestBabeModule_GetConfiguration_NoBlockProducer(t *testing.T) {
+	m := NewBabeModule(nil, nil)
+
+	var res BabeConfigurationResponse
+	err := m.GetConfiguration(nil, nil, &res)
+	require.Error(t, err)
+}
+
+func TestBabeModule_GetAuthorshipStats(t *testing.T) {
+	bs := newBABEService(t)
+	m := NewBabeModule(bs, nil)
+
+	var res []AuthorshipStatsResponse
+	err := m.GetAuthorshipStats(nil, nil, &res)
+	require.NoError(t, err)
+	require.Equal(t, []AuthorshipStatsResponse{}, res)
+}
+
+func TestBabeModule_GetAuthorshipStats_NoBlockProducer(t *testing.T) {
+	m := NewBabeModule(nil, nil)
+
+	var res []AuthorshipStatsResponse
+	err := m.GetAuthorshipStats(nil, nil, &res)
+	require.Error(t, err)
+}
+
+func TestBabeModule_GetEpochSchedule_NoBlockProducer(t *testing.T) {
+	m := NewBabeModule(nil, nil)
+
+	var res []EpochScheduleResponse
+	err := m.GetEpochSchedule(nil, nil, &res)
+	require.Error(t, err)
+}
+
+func TestBabeModule_GetAuthorshipProof(t *testing.T) {
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	babeHeader := &types.BabeHeader{
+		VrfOutput:          [32]byte{1},
+		VrfProof:           [64]byte{2},
+		BlockProducerIndex: 3,
+		SlotNumber:         77,
+	}
+	preDigest := &types.PreRuntimeDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              babeHeader.Encode(),
+	}
+
+	header, err := types.NewHeader(genesisHeader.Hash(), big.NewInt(1), common.Hash{}, common.Hash{}, types.NewDigest(preDigest))
+	require.NoError(t, err)
+
+	enc, err := header.Encode()
+	require.NoError(t, err)
+	sig, err := kr.Alice().Sign(enc)
+	require.NoError(t, err)
+
+	seal := &types.SealDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              sig,
+	}
+	header.Digest = append(header.Digest, seal)
+
+	bs, _ := newState(t)
+	err = bs.SetHeader(header)
+	require.NoError(t, err)
+
+	m := NewBabeModule(newBABEService(t), bs)
+
+	var res BabeAuthorshipProofResponse
+	err = m.GetAuthorshipProof(nil, &BabeAuthorshipProofRequest{Hash: header.Hash().String()}, &res)
+	require.NoError(t, err)
+
+	expected := BabeAuthorshipProofResponse{
+		Hash:           header.Hash().String(),
+		Number:         1,
+		Slot:           77,
+		AuthorityIndex: 3,
+		VrfOutput:      common.BytesToHex(babeHeader.VrfOutput[:]),
+		VrfProof:       common.BytesToHex(babeHeader.VrfProof[:]),
+		AuthoredByUs:   true,
+	}
+	require.Equal(t, expected, res)
+}
+
+func TestBabeModule_GetAuthorshipProof_NoBlockProducer(t *testing.T) {
+	m := NewBabeModule(nil, nil)
+
+	var res BabeAuthorshipProofResponse
+	err := m.GetAuthorshipProof(nil, &BabeAuthorshipProofRequest{Hash: common.Hash{}.String()}, &res)
+	require.Error(t, err)
+}
@@ -58,7 +58,8 @@ type ChainBlock struct {
 
 // ChainBlockResponse struct
 type ChainBlockResponse struct {
-	Block ChainBlock `json:"block"`
+	Block         ChainBlock `json:"block"`
+	Justification *string    `json:"justification"` // hex-encoded GRANDPA justification, if the block has one
 }
 
 // ChainHashResponse interface to handle response
@@ -66,7 +67,8 @@ type ChainHashResponse interface{}
 
 // ChainModule is an RPC module providing access to storage API points.
 type ChainModule struct {
-	blockAPI BlockAPI
+	blockAPI      BlockAPI
+	finalizedOnly bool
 }
 
 // NewChainModule creates a new State module.
@@ -76,6 +78,12 @@ func NewChainModule(api BlockAPI) *ChainModule {
 	}
 }
 
+// UseFinalizedHead makes queries that don't specify a block resolve against the finalized head instead of the
+// best block. Blocks above the finalized head can then only be queried by hash.
+func (cm *ChainModule) UseFinalizedHead() {
+	cm.finalizedOnly = true
+}
+
 // GetBlock Get header and body of a relay chain block. If no block hash is provided,
 //  the latest block body will be returned.
 func (cm *ChainModule) GetBlock(r *http.Request, req *ChainHashRequest, res *ChainBlockResponse) error {
@@ -89,7 +97,10 @@ func (cm *ChainModule) GetBlock(r *http.Request, req *ChainHashRequest, res *Cha
 		return err
 	}
 
-	res.Block.Header = HeaderToJSON(*block.Header)
+	res.Block.Header, err = HeaderToJSON(*block.Header)
+	if err != nil {
+		return err
+	}
 
 	if *block.Body != nil {
 		ext, err := block.Body.AsExtrinsics()
@@ -100,6 +111,16 @@ func (cm *ChainModule) GetBlock(r *http.Request, req *ChainHashRequest, res *Cha
 			res.Block.Body = append(res.Block.Body, fmt.Sprintf("0x%x", e))
 		}
 	}
+
+	if has, _ := cm.blockAPI.HasJustification(hash); has {
+		just, err := cm.blockAPI.GetJustification(hash)
+		if err != nil {
+			return err
+		}
+
+		justHex := common.BytesToHex(just)
+		res.Justification = &justHex
+	}
 	return nil
 }
 
@@ -108,7 +129,12 @@ func (cm *ChainModule) GetBlock(r *http.Request, req *ChainHashRequest, res *Cha
 func (cm *ChainModule) GetBlockHash(r *http.Request, req *ChainBlockNumberRequest, res *ChainHashResponse) error {
 	// if request is empty, return highest hash
 	if *req == nil || reflect.ValueOf(*req).Len() == 0 {
-		*res = cm.blockAPI.BestBlockHash().String()
+		hash, err := cm.headHash()
+		if err != nil {
+			return err
+		}
+
+		*res = hash.String()
 		return nil
 	}
 
@@ -164,8 +190,8 @@ func (cm *ChainModule) GetHeader(r *http.Request, req *ChainHashRequest, res *Ch
 		return err
 	}
 
-	*res = HeaderToJSON(*header)
-	return nil
+	*res, err = HeaderToJSON(*header)
+	return err
 }
 
 // SubscribeFinalizedHeads handled by websocket handler, but this func should remain
@@ -188,12 +214,20 @@ func (cm *ChainModule) SubscribeNewHeads(r *http.Request, req *EmptyRequest, res
 
 func (cm *ChainModule) hashLookup(req *ChainHashRequest) (common.Hash, error) {
 	if len(*req) == 0 {
-		hash := cm.blockAPI.BestBlockHash()
-		return hash, nil
+		return cm.headHash()
 	}
 	return common.HexToHash(string(*req))
 }
 
+// headHash returns the hash of the block that queries without a block hash resolve against
+func (cm *ChainModule) headHash() (common.Hash, error) {
+	if cm.finalizedOnly {
+		return cm.blockAPI.GetFinalizedHash(0, 0)
+	}
+
+	return cm.blockAPI.BestBlockHash(), nil
+}
+
 // unwindRequest takes request interface slice and makes call for each element
 func (cm *ChainModule) unwindRequest(req interface{}) ([]string, error) {
 	res := make([]string, 0)
@@ -242,6 +276,23 @@ func (cm *ChainModule) lookupHashByInterface(i interface{}) (string, error) {
 		return "", fmt.Errorf("unknown request number type: %T", x)
 	}
 
+	// blocks above the finalized head may still be re-orged, so they can't be looked up by number
+	if cm.finalizedOnly {
+		fin, err := cm.blockAPI.GetFinalizedHash(0, 0)
+		if err != nil {
+			return "", err
+		}
+
+		header, err := cm.blockAPI.GetHeader(fin)
+		if err != nil {
+			return "", err
+		}
+
+		if num.Cmp(header.Number) > 0 {
+			return "", fmt.Errorf("block %s is above the finalized head", num)
+		}
+	}
+
 	h, err := cm.blockAPI.GetBlockHash(num)
 	if err != nil {
 		return "", err
@@ -251,7 +302,7 @@ func (cm *ChainModule) lookupHashByInterface(i interface{}) (string, error) {
 }
 
 // HeaderToJSON converts types.Header to ChainBlockHeaderResponse
-func HeaderToJSON(header types.Header) ChainBlockHeaderResponse {
+func HeaderToJSON(header types.Header) (ChainBlockHeaderResponse, error) {
 	res := ChainBlockHeaderResponse{
 		ParentHash:     header.ParentHash.String(),
 		StateRoot:      header.StateRoot.String(),
@@ -264,7 +315,11 @@ func HeaderToJSON(header types.Header) ChainBlockHeaderResponse {
 		res.Number = common.BytesToHex(header.Number.Bytes())
 	}
 	for _, item := range header.Digest {
-		res.Digest.Logs = append(res.Digest.Logs, common.BytesToHex(item))
+		enc, err := item.Encode()
+		if err != nil {
+			return ChainBlockHeaderResponse{}, err
+		}
+		res.Digest.Logs = append(res.Digest.Logs, common.BytesToHex(enc))
 	}
-	return res
+	return res, nil
 }
@@ -113,6 +113,29 @@ func TestChainGetBlock_Genesis(t *testing.T) {
 	require.Equal(t, expected, res)
 }
 
+func TestChainGetBlock_Justification(t *testing.T) {
+	chain := newTestStateService(t)
+	svc := NewChainModule(chain.Block)
+
+	hash := chain.Block.BestBlockHash()
+	err := chain.Block.SetJustification(hash, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	res := &ChainBlockResponse{}
+	req := ChainHashRequest(hash.String())
+	err = svc.GetBlock(nil, &req, res)
+	require.NoError(t, err)
+	require.NotNil(t, res.Justification)
+	require.Equal(t, "0x010203", *res.Justification)
+
+	// blocks without a justification have none in the response
+	req = ChainHashRequest(chain.Block.GenesisHash().String())
+	res = &ChainBlockResponse{}
+	err = svc.GetBlock(nil, &req, res)
+	require.NoError(t, err)
+	require.Nil(t, res.Justification)
+}
+
 func TestChainGetBlock_Latest(t *testing.T) {
 	chain := newTestStateService(t)
 	svc := NewChainModule(chain.Block)
@@ -217,6 +240,29 @@ func TestChainGetBlockHash_Array(t *testing.T) {
 	require.Equal(t, []string{"0x8b38e3b4dda30540c1245eab842b8d5ceefd8abcb46c5752348f5b0742e49d21", "0x12ee07bf9e9f12e8edc7ec24e323debe693c04b40d121ae23bcd6fcf2a7dcc3b"}, res)
 }
 
+func TestChainGetBlockHash_FinalizedOnly(t *testing.T) {
+	chain := newTestStateService(t)
+	svc := NewChainModule(chain.Block)
+	svc.UseFinalizedHead()
+
+	var res ChainHashResponse
+	req := ChainBlockNumberRequest(nil)
+	err := svc.GetBlockHash(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, genesisHeader.Hash().String(), res)
+
+	// block 1 isn't finalized yet
+	req = ChainBlockNumberRequest("1")
+	err = svc.GetBlockHash(nil, &req, &res)
+	require.Error(t, err)
+
+	hdr := &ChainBlockHeaderResponse{}
+	hreq := ChainHashRequest("")
+	err = svc.GetHeader(nil, &hreq, hdr)
+	require.NoError(t, err)
+	require.Equal(t, "0x00", hdr.Number)
+}
+
 func TestChainGetFinalizedHead(t *testing.T) {
 	chain := newTestStateService(t)
 	svc := NewChainModule(chain.Block)
@@ -249,7 +295,7 @@ func TestChainGetFinalizedHeadByRound(t *testing.T) {
 	require.Equal(t, common.BytesToHex(testhash[:]), res)
 }
 
-var genesisHeader, _ = types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, [][]byte{})
+var genesisHeader, _ = types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, types.NewEmptyDigest())
 
 var firstEpochInfo = &types.EpochInfo{
 	Duration:   200,
@@ -287,7 +333,7 @@ func loadTestBlocks(gh common.Hash, bs *state.BlockState) error {
 	// Create header
 	header0 := &types.Header{
 		Number:     big.NewInt(0),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 		ParentHash: gh,
 		StateRoot:  trie.EmptyHash,
 	}
@@ -309,7 +355,7 @@ func loadTestBlocks(gh common.Hash, bs *state.BlockState) error {
 	// Create header & blockData for block 1
 	header1 := &types.Header{
 		Number:     big.NewInt(1),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 		ParentHash: blockHash0,
 		StateRoot:  trie.EmptyHash,
 	}
@@ -15,7 +15,23 @@
 // along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
 package modules
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
 
 // ErrSubscriptionTransport error sent when trying to access websocket subscriptions via http
 var ErrSubscriptionTransport = errors.New("subscriptions are not available on this transport")
+
+// ErrUnsafeDisabled is returned when calling a method that modifies the chain state without enabling unsafe methods
+var ErrUnsafeDisabled = errors.New("unsafe RPC methods are disabled, enable them with --rpc-unsafe")
+
+// ErrSnapshotPruned is returned when a paged query continues from a block whose state has since been pruned
+var ErrSnapshotPruned = errors.New("state snapshot of paged query has been pruned, restart from the first page")
+
+// ErrSyncStateUnavailable is returned when the node can't build chain specs with its light sync state
+var ErrSyncStateUnavailable = errors.New("sync state is not available")
+
+// ErrTransactionBanned is returned when a submitted transaction is banned after repeatedly failing validation
+var ErrTransactionBanned = &json2.Error{Code: 1012, Message: "Transaction is temporarily banned"}
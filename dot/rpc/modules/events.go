@@ -0,0 +1,541 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// metadataMagic is the prefix of the encoded runtime metadata
+var metadataMagic = []byte("meta")
+
+// errUnknownType is returned when an event argument has a type whose encoding isn't known, in which case the
+// following events can't be decoded either
+var errUnknownType = errors.New("unknown type")
+
+// eventTypeSizes are the encoded sizes of the fixed size types used by the arguments of the runtime events, as
+// named by the runtime metadata
+var eventTypeSizes = map[string]int{
+	"()":              0,
+	"bool":            1,
+	"u8":              1,
+	"i8":              1,
+	"u16":             2,
+	"i16":             2,
+	"u32":             4,
+	"i32":             4,
+	"u64":             8,
+	"i64":             8,
+	"u128":            16,
+	"i128":            16,
+	"BlockNumber":     4,
+	"AccountIndex":    4,
+	"SessionIndex":    4,
+	"EraIndex":        4,
+	"PropIndex":       4,
+	"ReferendumIndex": 4,
+	"ProposalIndex":   4,
+	"MemberCount":     4,
+	"RegistrarIndex":  4,
+	"AuthorityIndex":  4,
+	"Moment":          8,
+	"Weight":          8,
+	"Balance":         16,
+	"BalanceOf":       16,
+	"AccountId":       32,
+	"AuthorityId":     32,
+	"ValidatorId":     32,
+	"Hash":            32,
+	"H256":            32,
+	"CallHash":        32,
+	"ProposalHash":    32,
+	"DispatchInfo":    10, // u64 weight, dispatch class and pays fee flag
+}
+
+// eventMetadata is an event of a runtime module, with the types of its arguments
+type eventMetadata struct {
+	name string
+	args []string
+}
+
+// moduleEvents are the events of a runtime module
+type moduleEvents struct {
+	name   string
+	events []*eventMetadata
+}
+
+// RuntimeEvent is an event deposited by the runtime, decoded using the runtime metadata. The arguments and topics
+// are hex-encoded.
+type RuntimeEvent struct {
+	Phase  string   `json:"phase"`
+	Module string   `json:"module"`
+	Event  string   `json:"event"`
+	Args   []string `json:"args"`
+	Topics []string `json:"topics"`
+}
+
+// decodeMetadataEvents returns the events of the runtime modules described by the given encoded runtime metadata,
+// by module index. Metadata versions 11 and 12 are supported.
+func decodeMetadataEvents(metadata []byte) (map[byte]*moduleEvents, error) {
+	if len(metadata) < 5 || !bytes.Equal(metadata[:4], metadataMagic) {
+		return nil, errors.New("invalid metadata prefix")
+	}
+
+	version := metadata[4]
+	if version != 11 && version != 12 {
+		return nil, fmt.Errorf("unsupported metadata version %d", version)
+	}
+
+	d := &scale.Decoder{Reader: bytes.NewReader(metadata[5:])}
+	modules := make(map[byte]*moduleEvents)
+
+	// before version 12, the modules with events are indexed in order
+	var next byte
+	err := decodeVec(d, func() error {
+		module, err := decodeModuleMetadata(d)
+		if err != nil {
+			return err
+		}
+
+		index := next
+		if version == 12 {
+			index, err = d.ReadByte()
+			if err != nil {
+				return err
+			}
+		}
+
+		if module.events != nil {
+			modules[index] = module
+			next++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// decodeModuleMetadata decodes the metadata of a module, up to its index, and returns its events. The events are
+// nil if the module has none.
+func decodeModuleMetadata(d *scale.Decoder) (*moduleEvents, error) {
+	name, err := d.DecodeByteArray()
+	if err != nil {
+		return nil, err
+	}
+
+	module := &moduleEvents{name: string(name)}
+
+	// storage prefix and entries
+	err = decodeOption(d, func() error {
+		if err = skipStrings(d, 1); err != nil {
+			return err
+		}
+		return decodeVec(d, func() error {
+			return skipStorageEntryMetadata(d)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode storage of module %s: %w", name, err)
+	}
+
+	// calls, with their names, arguments and documentation
+	err = decodeOption(d, func() error {
+		return decodeVec(d, func() error {
+			if err = skipStrings(d, 1); err != nil {
+				return err
+			}
+			err = decodeVec(d, func() error {
+				return skipStrings(d, 2)
+			})
+			if err != nil {
+				return err
+			}
+			_, err = d.DecodeStringArray()
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode calls of module %s: %w", name, err)
+	}
+
+	// events, with their names, argument types and documentation
+	err = decodeOption(d, func() error {
+		module.events = []*eventMetadata{}
+		return decodeVec(d, func() error {
+			var ev []byte
+			ev, err = d.DecodeByteArray()
+			if err != nil {
+				return err
+			}
+
+			var args []string
+			args, err = d.DecodeStringArray()
+			if err != nil {
+				return err
+			}
+
+			module.events = append(module.events, &eventMetadata{name: string(ev), args: args})
+			_, err = d.DecodeStringArray()
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode events of module %s: %w", name, err)
+	}
+
+	// constants, with their names, types, values and documentation
+	err = decodeVec(d, func() error {
+		if err = skipStrings(d, 3); err != nil {
+			return err
+		}
+		_, err = d.DecodeStringArray()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode constants of module %s: %w", name, err)
+	}
+
+	// errors, with their names and documentation
+	err = decodeVec(d, func() error {
+		if err = skipStrings(d, 1); err != nil {
+			return err
+		}
+		_, err = d.DecodeStringArray()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode errors of module %s: %w", name, err)
+	}
+
+	return module, nil
+}
+
+// skipStorageEntryMetadata decodes the metadata of a storage entry and discards it
+func skipStorageEntryMetadata(d *scale.Decoder) error {
+	// name and modifier
+	if err := skipStrings(d, 1); err != nil {
+		return err
+	}
+	if err := skipBytes(d, 1); err != nil {
+		return err
+	}
+
+	kind, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case 0: // plain: value type
+		err = skipStrings(d, 1)
+	case 1: // map: hasher, key and value types, unused flag
+		if err = skipBytes(d, 1); err == nil {
+			if err = skipStrings(d, 2); err == nil {
+				err = skipBytes(d, 1)
+			}
+		}
+	case 2: // double map: hasher, key, key and value types, second hasher
+		if err = skipBytes(d, 1); err == nil {
+			if err = skipStrings(d, 3); err == nil {
+				err = skipBytes(d, 1)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid storage entry type %d", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	// default value and documentation
+	if err = skipStrings(d, 1); err != nil {
+		return err
+	}
+	_, err = d.DecodeStringArray()
+	return err
+}
+
+// decodeEvents decodes the encoded System.Events storage value using the events of the runtime modules. If an
+// event can't be decoded, the events decoded before it are returned along with the error.
+func decodeEvents(enc []byte, modules map[byte]*moduleEvents) ([]*RuntimeEvent, error) {
+	r := bytes.NewReader(enc)
+	d := &scale.Decoder{Reader: r}
+
+	events := []*RuntimeEvent{}
+	err := decodeVec(d, func() error {
+		ev, err := decodeEventRecord(d, r, enc, modules)
+		if err != nil {
+			return err
+		}
+		events = append(events, ev)
+		return nil
+	})
+
+	return events, err
+}
+
+// decodeEventRecord decodes an event record, which is the phase of the block it was deposited in, the event and
+// its topics. The reader must be the one of the decoder, reading the given encoding.
+func decodeEventRecord(d *scale.Decoder, r *bytes.Reader, enc []byte,
+	modules map[byte]*moduleEvents) (*RuntimeEvent, error) {
+	ev := &RuntimeEvent{
+		Args:   []string{},
+		Topics: []string{},
+	}
+
+	phase, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch phase {
+	case 0:
+		buf := make([]byte, 4)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		ev.Phase = fmt.Sprintf("ApplyExtrinsic(%d)", binary.LittleEndian.Uint32(buf))
+	case 1:
+		ev.Phase = "Finalization"
+	case 2:
+		ev.Phase = "Initialization"
+	default:
+		return nil, fmt.Errorf("invalid event phase %d", phase)
+	}
+
+	index := make([]byte, 2)
+	if _, err = io.ReadFull(r, index); err != nil {
+		return nil, err
+	}
+
+	module := modules[index[0]]
+	if module == nil || int(index[1]) >= len(module.events) {
+		return nil, fmt.Errorf("unknown event %d of module %d", index[1], index[0])
+	}
+
+	meta := module.events[index[1]]
+	ev.Module = module.name
+	ev.Event = meta.name
+
+	for _, ty := range meta.args {
+		start := len(enc) - r.Len()
+		if err = skipType(d, ty); err != nil {
+			return nil, fmt.Errorf("failed to decode argument of event %s.%s: %w", module.name, meta.name, err)
+		}
+		ev.Args = append(ev.Args, common.BytesToHex(enc[start:len(enc)-r.Len()]))
+	}
+
+	err = decodeVec(d, func() error {
+		topic := make([]byte, 32)
+		if _, err = io.ReadFull(r, topic); err != nil {
+			return err
+		}
+		ev.Topics = append(ev.Topics, common.BytesToHex(topic))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+// skipType decodes a value of the given type, as named by the runtime metadata, and discards it
+func skipType(d *scale.Decoder, ty string) error {
+	ty = normalizeType(ty)
+	if size, has := eventTypeSizes[ty]; has {
+		return skipBytes(d, size)
+	}
+
+	switch {
+	case ty == "Bytes" || ty == "Vec<u8>":
+		return skipStrings(d, 1)
+	case ty == "DispatchError":
+		return skipDispatchError(d)
+	case ty == "DispatchResult":
+		ok, err := d.ReadByte()
+		if err != nil || ok == 0 {
+			return err
+		}
+		return skipDispatchError(d)
+	case strings.HasPrefix(ty, "Vec<") && strings.HasSuffix(ty, ">"):
+		return decodeVec(d, func() error {
+			return skipType(d, ty[4:len(ty)-1])
+		})
+	case strings.HasPrefix(ty, "Option<") && strings.HasSuffix(ty, ">"):
+		return decodeOption(d, func() error {
+			return skipType(d, ty[7:len(ty)-1])
+		})
+	case strings.HasPrefix(ty, "Compact<") && strings.HasSuffix(ty, ">"):
+		_, err := d.DecodeBigInt()
+		return err
+	case strings.HasPrefix(ty, "(") && strings.HasSuffix(ty, ")"):
+		for _, item := range splitTypes(ty[1 : len(ty)-1]) {
+			if err := skipType(d, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.HasPrefix(ty, "[") && strings.HasSuffix(ty, "]"):
+		// fixed size arrays, eg. [u8; 32]
+		parts := strings.Split(ty[1:len(ty)-1], ";")
+		if len(parts) != 2 {
+			break
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			break
+		}
+
+		for i := 0; i < n; i++ {
+			if err = skipType(d, parts[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w %s", errUnknownType, ty)
+}
+
+// skipDispatchError decodes a DispatchError and discards it. Module errors carry the module and error indexes,
+// token and arithmetic errors carry their kind.
+func skipDispatchError(d *scale.Decoder) error {
+	kind, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case 3:
+		return skipBytes(d, 2)
+	case 6, 7:
+		return skipBytes(d, 1)
+	}
+	return nil
+}
+
+// normalizeType removes the paths of qualified types and the parameters of the generic types with a known size,
+// eg. <T as frame_system::Trait>::AccountId becomes AccountId and BalanceOf<T> becomes BalanceOf
+func normalizeType(ty string) string {
+	ty = strings.TrimSpace(ty)
+	if strings.HasPrefix(ty, "(") || strings.HasPrefix(ty, "[") {
+		return ty
+	}
+
+	if strings.HasPrefix(ty, "<") {
+		if i := strings.Index(ty, ">::"); i >= 0 {
+			ty = ty[i+3:]
+		}
+	}
+
+	if i := strings.Index(ty, "<"); i > 0 {
+		if _, has := eventTypeSizes[ty[:i]]; has {
+			return ty[:i]
+		}
+		return ty
+	}
+
+	if i := strings.LastIndex(ty, "::"); i >= 0 {
+		ty = ty[i+2:]
+	}
+	return ty
+}
+
+// splitTypes splits the comma separated types of a tuple, ignoring the commas nested in generic types and tuples
+func splitTypes(types string) []string {
+	items := []string{}
+	depth, start := 0, 0
+	for i, c := range types {
+		switch c {
+		case '<', '(', '[':
+			depth++
+		case '>', ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, types[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	if last := strings.TrimSpace(types[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
+
+// decodeVec decodes a sequence, decoding each item with the given function
+func decodeVec(d *scale.Decoder, decode func() error) error {
+	n, err := d.DecodeUnsignedInteger()
+	if err != nil {
+		return err
+	}
+
+	for i := uint64(0); i < n; i++ {
+		if err = decode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeOption decodes an optional value with the given function if it's present
+func decodeOption(d *scale.Decoder, decode func() error) error {
+	b, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch b {
+	case 0:
+		return nil
+	case 1:
+		return decode()
+	}
+	return fmt.Errorf("invalid option %d", b)
+}
+
+// skipStrings decodes the given number of strings, or byte arrays, and discards them
+func skipStrings(d *scale.Decoder, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := d.DecodeByteArray(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipBytes discards the given number of bytes
+func skipBytes(d *scale.Decoder, n int) error {
+	_, err := io.ReadFull(d.Reader, make([]byte, n))
+	return err
+}
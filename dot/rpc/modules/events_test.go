@@ -0,0 +1,175 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeStr encodes a string shorter than 64 bytes
+func encodeStr(s string) []byte {
+	return append([]byte{byte(len(s) << 2)}, s...)
+}
+
+// encodeStrs encodes a sequence of less than 64 strings
+func encodeStrs(strs ...string) []byte {
+	enc := []byte{byte(len(strs) << 2)}
+	for _, s := range strs {
+		enc = append(enc, encodeStr(s)...)
+	}
+	return enc
+}
+
+// testMetadata returns the version 12 metadata of a runtime with a System module at index 0, that has storage and
+// calls, and a Balances module with a transfer event at index 5
+func testMetadata() []byte {
+	buf := bytes.Buffer{}
+	buf.Write(metadataMagic)
+	buf.WriteByte(12)
+	buf.WriteByte(2 << 2)
+
+	// System module, with a plain and a map storage entry and a call
+	buf.Write(encodeStr("System"))
+	buf.WriteByte(1)
+	buf.Write(encodeStr("System"))
+	buf.WriteByte(2 << 2)
+	buf.Write(encodeStr("Number"))
+	buf.Write([]byte{0, 0})
+	buf.Write(encodeStr("T::BlockNumber"))
+	buf.Write(encodeStr("\x00\x00\x00\x00"))
+	buf.Write(encodeStrs("The current block number"))
+	buf.Write(encodeStr("Account"))
+	buf.Write([]byte{1, 1, 2})
+	buf.Write(encodeStr("T::AccountId"))
+	buf.Write(encodeStr("AccountInfo"))
+	buf.WriteByte(0)
+	buf.Write(encodeStr(""))
+	buf.Write(encodeStrs())
+	buf.WriteByte(1)
+	buf.WriteByte(1 << 2)
+	buf.Write(encodeStr("remark"))
+	buf.WriteByte(1 << 2)
+	buf.Write(encodeStr("_remark"))
+	buf.Write(encodeStr("Vec<u8>"))
+	buf.Write(encodeStrs("Make some on-chain remark"))
+	buf.WriteByte(1)
+	buf.WriteByte(2 << 2)
+	buf.Write(encodeStr("ExtrinsicSuccess"))
+	buf.Write(encodeStrs("DispatchInfo"))
+	buf.Write(encodeStrs())
+	buf.Write(encodeStr("ExtrinsicFailed"))
+	buf.Write(encodeStrs("DispatchError", "DispatchInfo"))
+	buf.Write(encodeStrs())
+	buf.WriteByte(1 << 2)
+	buf.Write(encodeStr("BlockHashCount"))
+	buf.Write(encodeStr("T::BlockNumber"))
+	buf.Write(encodeStr("\x60\x09\x00\x00"))
+	buf.Write(encodeStrs())
+	buf.WriteByte(1 << 2)
+	buf.Write(encodeStr("InvalidSpecName"))
+	buf.Write(encodeStrs())
+	buf.WriteByte(0)
+
+	// Balances module
+	buf.Write(encodeStr("Balances"))
+	buf.Write([]byte{0, 0, 1})
+	buf.WriteByte(1 << 2)
+	buf.Write(encodeStr("Transfer"))
+	buf.Write(encodeStrs("<T as frame_system::Trait>::AccountId", "AccountId", "Balance"))
+	buf.Write(encodeStrs())
+	buf.Write([]byte{0, 0})
+	buf.WriteByte(5)
+
+	return buf.Bytes()
+}
+
+func TestDecodeEvents(t *testing.T) {
+	modules, err := decodeMetadataEvents(testMetadata())
+	require.NoError(t, err)
+	require.Len(t, modules, 2)
+	require.Equal(t, "System", modules[0].name)
+	require.Len(t, modules[0].events, 2)
+	require.Equal(t, "Balances", modules[5].name)
+
+	from := bytes.Repeat([]byte{1}, 32)
+	to := bytes.Repeat([]byte{2}, 32)
+	amount := append([]byte{100}, make([]byte, 15)...)
+	topic := bytes.Repeat([]byte{3}, 32)
+
+	enc := []byte{3 << 2}
+	// ApplyExtrinsic(1), Balances.Transfer with a topic
+	enc = append(enc, 0, 1, 0, 0, 0, 5, 0)
+	enc = append(enc, from...)
+	enc = append(enc, to...)
+	enc = append(enc, amount...)
+	enc = append(enc, 1<<2)
+	enc = append(enc, topic...)
+	// ApplyExtrinsic(1), System.ExtrinsicFailed with a module error, weight 10000, normal class, pays fee
+	enc = append(enc, 0, 1, 0, 0, 0, 0, 1)
+	enc = append(enc, 3, 5, 2)
+	enc = append(enc, 0x10, 0x27, 0, 0, 0, 0, 0, 0, 0, 0)
+	enc = append(enc, 0)
+	// Finalization, unknown event
+	enc = append(enc, 1, 5, 1, 0)
+
+	events, err := decodeEvents(enc, modules)
+	require.Error(t, err)
+	require.Equal(t, []*RuntimeEvent{
+		{
+			Phase:  "ApplyExtrinsic(1)",
+			Module: "Balances",
+			Event:  "Transfer",
+			Args:   []string{common.BytesToHex(from), common.BytesToHex(to), common.BytesToHex(amount)},
+			Topics: []string{common.BytesToHex(topic)},
+		},
+		{
+			Phase:  "ApplyExtrinsic(1)",
+			Module: "System",
+			Event:  "ExtrinsicFailed",
+			Args:   []string{"0x030502", "0x10270000000000000000"},
+			Topics: []string{},
+		},
+	}, events)
+}
+
+func TestSkipType(t *testing.T) {
+	for ty, enc := range map[string]string{
+		"T::BlockNumber":              "0x01000000",
+		"BalanceOf<T, I>":             "0x0100000000000000000000000000000000",
+		"Vec<(T::AccountId, u8)>":     "0x04010101010101010101010101010101010101010101010101010101010101010105",
+		"Option<Vec<u8>>":             "0x01080102",
+		"Compact<Balance>":            "0x0b00407a10f35a",
+		"[u8; 4]":                     "0x01020304",
+		"DispatchResult":              "0x00",
+		"Option<<T as Trait>::Hash>":  "0x00",
+		"(AccountIndex, bool, Bytes)": "0x01000000000401",
+	} {
+		in := common.MustHexToBytes(enc)
+		d := &scale.Decoder{Reader: bytes.NewReader(in)}
+		require.NoError(t, skipType(d, ty), ty)
+		require.Equal(t, 0, d.Reader.(*bytes.Reader).Len(), ty)
+	}
+
+	err := skipType(&scale.Decoder{Reader: bytes.NewReader([]byte{0})}, "Perbill")
+	require.True(t, errors.Is(err, errUnknownType))
+}
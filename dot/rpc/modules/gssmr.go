@@ -0,0 +1,192 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// GssmrModule is an RPC module providing gossamer specific methods for inspecting the node's state
+type GssmrModule struct {
+	storageAPI StorageAPI
+	blockAPI   BlockAPI
+}
+
+// GssmrStorageChange is a hex encoded storage entry that differs between two blocks, Old is empty if the entry was
+// added and New is empty if it was removed
+type GssmrStorageChange struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// GssmrStorageDiffResponse holds the storage entries added, changed and removed between two blocks
+type GssmrStorageDiffResponse struct {
+	From    string               `json:"from"`
+	To      string               `json:"to"`
+	Added   []GssmrStorageChange `json:"added"`
+	Changed []GssmrStorageChange `json:"changed"`
+	Removed []GssmrStorageChange `json:"removed"`
+}
+
+// GssmrExtrinsicInclusion is a finalized block that includes an extrinsic, with the index of the extrinsic in the block
+type GssmrExtrinsicInclusion struct {
+	BlockHash   string `json:"blockHash"`
+	BlockNumber string `json:"blockNumber"`
+	Index       uint32 `json:"index"`
+}
+
+// NewGssmrModule creates a new Gssmr module.
+func NewGssmrModule(storageAPI StorageAPI, blockAPI BlockAPI) *GssmrModule {
+	return &GssmrModule{
+		storageAPI: storageAPI,
+		blockAPI:   blockAPI,
+	}
+}
+
+// StorageDiff returns the storage entries that were added, changed or removed between two blocks, with parameters
+// [fromHash, toHash, prefix]. If toHash is null, the best block is used. Only the entries whose key starts with
+// the optional prefix are compared, so that runtime migrations and state growth can be checked per pallet.
+func (gm *GssmrModule) StorageDiff(r *http.Request, req *[]interface{}, res *GssmrStorageDiffResponse) error {
+	pReq := *req
+	if len(pReq) < 1 {
+		return errors.New("expected fromHash parameter")
+	}
+
+	from, err := hashParam(pReq[0])
+	if err != nil {
+		return err
+	}
+
+	to := gm.blockAPI.BestBlockHash()
+	if len(pReq) > 1 && pReq[1] != nil {
+		to, err = hashParam(pReq[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	var prefix []byte
+	if len(pReq) > 2 {
+		prefix, err = optionalHexParam(pReq[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	changes, err := gm.storageAPI.StorageDiff(from, to, prefix)
+	if err != nil {
+		return err
+	}
+
+	*res = GssmrStorageDiffResponse{
+		From:    from.String(),
+		To:      to.String(),
+		Added:   []GssmrStorageChange{},
+		Changed: []GssmrStorageChange{},
+		Removed: []GssmrStorageChange{},
+	}
+
+	for _, c := range changes {
+		change := GssmrStorageChange{
+			Key: common.BytesToHex(c.Key),
+		}
+
+		switch {
+		case c.Old == nil:
+			change.New = common.BytesToHex(c.New)
+			res.Added = append(res.Added, change)
+		case c.New == nil:
+			change.Old = common.BytesToHex(c.Old)
+			res.Removed = append(res.Removed, change)
+		default:
+			change.Old = common.BytesToHex(c.Old)
+			change.New = common.BytesToHex(c.New)
+			res.Changed = append(res.Changed, change)
+		}
+	}
+
+	return nil
+}
+
+// ExtrinsicInclusions returns the finalized blocks that include the extrinsic with the given hash, with parameters
+// [extrinsicHash]. It requires the node to run with the extrinsic index enabled.
+func (gm *GssmrModule) ExtrinsicInclusions(r *http.Request, req *[]interface{}, res *[]GssmrExtrinsicInclusion) error {
+	pReq := *req
+	if len(pReq) < 1 {
+		return errors.New("expected extrinsicHash parameter")
+	}
+
+	hash, err := hashParam(pReq[0])
+	if err != nil {
+		return err
+	}
+
+	incs, err := gm.blockAPI.GetExtrinsicInclusions(hash)
+	if err != nil {
+		return err
+	}
+
+	*res = []GssmrExtrinsicInclusion{}
+	for _, inc := range incs {
+		header, err := gm.blockAPI.GetHeader(inc.BlockHash)
+		if err != nil {
+			return err
+		}
+
+		*res = append(*res, GssmrExtrinsicInclusion{
+			BlockHash:   inc.BlockHash.String(),
+			BlockNumber: common.BytesToHex(header.Number.Bytes()),
+			Index:       inc.Index,
+		})
+	}
+
+	return nil
+}
+
+// HeaderEncoding returns the SCALE encoding of the header of a block as a hex string, with parameters [blockHash].
+// If blockHash is omitted, the best block is used.
+func (gm *GssmrModule) HeaderEncoding(r *http.Request, req *[]interface{}, res *string) error {
+	hash := gm.blockAPI.BestBlockHash()
+	if len(*req) > 0 && (*req)[0] != nil {
+		var err error
+		hash, err = hashParam((*req)[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	enc, err := gm.blockAPI.GetHeaderEncoding(hash)
+	if err != nil {
+		return err
+	}
+
+	*res = common.BytesToHex(enc)
+	return nil
+}
+
+func hashParam(param interface{}) (common.Hash, error) {
+	str, ok := param.(string)
+	if !ok {
+		return common.Hash{}, errors.New("block hash must be a hex string")
+	}
+
+	return common.HexToHash(str)
+}
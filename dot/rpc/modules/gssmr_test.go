@@ -0,0 +1,156 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGssmrModule_StorageDiff(t *testing.T) {
+	_, chain := setupStateModuleWithState(t)
+	gm := NewGssmrModule(chain.Storage, chain.Block)
+	from := chain.Block.BestBlockHash()
+
+	ts, err := chain.Storage.TrieStateCopy(nil)
+	require.NoError(t, err)
+	err = ts.Set([]byte(`:key1`), []byte(`value3`))
+	require.NoError(t, err)
+	err = ts.Delete([]byte(`:key2`))
+	require.NoError(t, err)
+	err = ts.Set([]byte(`:key4`), []byte(`value4`))
+	require.NoError(t, err)
+	err = ts.Set([]byte(`other`), []byte(`value`))
+	require.NoError(t, err)
+
+	sr, err := ts.Root()
+	require.NoError(t, err)
+	err = chain.Storage.StoreTrie(sr, ts)
+	require.NoError(t, err)
+
+	err = chain.Block.AddBlock(&types.Block{
+		Header: &types.Header{
+			ParentHash: from,
+			Number:     big.NewInt(3),
+			StateRoot:  sr,
+		},
+		Body: types.NewBody([]byte{}),
+	})
+	require.NoError(t, err)
+	to := chain.Block.BestBlockHash()
+
+	req := []interface{}{from.String(), nil, "0x3a6b6579"} // :key
+	res := GssmrStorageDiffResponse{}
+	err = gm.StorageDiff(nil, &req, &res)
+	require.NoError(t, err)
+
+	expected := GssmrStorageDiffResponse{
+		From:    from.String(),
+		To:      to.String(),
+		Added:   []GssmrStorageChange{{Key: "0x3a6b657934", New: "0x76616c756534"}},
+		Changed: []GssmrStorageChange{{Key: "0x3a6b657931", Old: "0x76616c756531", New: "0x76616c756533"}},
+		Removed: []GssmrStorageChange{{Key: "0x3a6b657932", Old: "0x76616c756532"}},
+	}
+	require.Equal(t, expected, res)
+
+	req = []interface{}{to.String(), to.String()}
+	err = gm.StorageDiff(nil, &req, &res)
+	require.NoError(t, err)
+	require.Empty(t, res.Added)
+	require.Empty(t, res.Changed)
+	require.Empty(t, res.Removed)
+
+	req = []interface{}{float64(1)}
+	err = gm.StorageDiff(nil, &req, &res)
+	require.Error(t, err)
+}
+
+func TestGssmrModule_ExtrinsicInclusions(t *testing.T) {
+	_, chain := setupStateModuleWithState(t)
+	gm := NewGssmrModule(chain.Storage, chain.Block)
+
+	ext := types.NewExtrinsic([]byte("withdrawal"))
+	req := []interface{}{ext.Hash().String()}
+	res := []GssmrExtrinsicInclusion{}
+	err := gm.ExtrinsicInclusions(nil, &req, &res)
+	require.True(t, errors.Is(err, state.ErrExtrinsicIndexDisabled))
+
+	chain.Block.EnableExtrinsicIndex()
+
+	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{ext})
+	require.NoError(t, err)
+
+	best, err := chain.Block.BestBlockHeader()
+	require.NoError(t, err)
+	header := &types.Header{
+		ParentHash: best.Hash(),
+		Number:     big.NewInt(0).Add(best.Number, big.NewInt(1)),
+		StateRoot:  best.StateRoot,
+	}
+	err = chain.Block.AddBlock(&types.Block{
+		Header: header,
+		Body:   body,
+	})
+	require.NoError(t, err)
+
+	err = gm.ExtrinsicInclusions(nil, &req, &res)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	err = chain.Block.SetFinalizedHash(header.Hash(), 0, 0)
+	require.NoError(t, err)
+
+	err = gm.ExtrinsicInclusions(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, []GssmrExtrinsicInclusion{{
+		BlockHash:   header.Hash().String(),
+		BlockNumber: common.BytesToHex(header.Number.Bytes()),
+		Index:       0,
+	}}, res)
+}
+
+func TestGssmrModule_HeaderEncoding(t *testing.T) {
+	_, chain := setupStateModuleWithState(t)
+	gm := NewGssmrModule(chain.Storage, chain.Block)
+
+	best, err := chain.Block.BestBlockHeader()
+	require.NoError(t, err)
+
+	req := []interface{}{}
+	var res string
+	err = gm.HeaderEncoding(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHex(best.MustEncode()), res)
+
+	genesis, err := chain.Block.GetHeader(chain.Block.GenesisHash())
+	require.NoError(t, err)
+
+	req = []interface{}{genesis.Hash().String()}
+	err = gm.HeaderEncoding(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHex(genesis.MustEncode()), res)
+
+	req = []interface{}{common.Hash{1}.String()}
+	err = gm.HeaderEncoding(nil, &req, &res)
+	require.Error(t, err)
+}
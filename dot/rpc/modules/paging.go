@@ -0,0 +1,115 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+const (
+	// maxPagingCursors is the maximum number of pagination cursors that are remembered at once
+	maxPagingCursors = 1024
+	// pagingCursorTTL is how long a pagination cursor is remembered after its page was served
+	pagingCursorTTL = 5 * time.Minute
+)
+
+type pagingKey struct {
+	prefix string
+	last   string
+}
+
+type pagingCursor struct {
+	hash    common.Hash
+	expires time.Time
+}
+
+// pagingCursors pins paged queries to the block they started at. When a page is served, the block it was read from
+// is remembered under the query's prefix and the last key of the page, so a request for the following page that
+// doesn't specify a block reads from the same state snapshot, even if new blocks were imported in between.
+type pagingCursors struct {
+	sync.Mutex
+	cursors map[pagingKey]*pagingCursor
+	now     func() time.Time
+}
+
+func newPagingCursors() *pagingCursors {
+	return &pagingCursors{
+		cursors: make(map[pagingKey]*pagingCursor),
+		now:     time.Now,
+	}
+}
+
+// get returns the block hash that the page ending at the given key was read from, if it is still remembered
+func (p *pagingCursors) get(prefix, last []byte) (common.Hash, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	key := pagingKey{prefix: string(prefix), last: string(last)}
+	c, has := p.cursors[key]
+	if !has {
+		return common.Hash{}, false
+	}
+
+	if p.now().After(c.expires) {
+		delete(p.cursors, key)
+		return common.Hash{}, false
+	}
+
+	return c.hash, true
+}
+
+// put remembers that the page ending at the given key was read from the block with the given hash
+func (p *pagingCursors) put(prefix, last []byte, hash common.Hash) {
+	p.Lock()
+	defer p.Unlock()
+
+	now := p.now()
+	if len(p.cursors) >= maxPagingCursors {
+		p.evict(now)
+	}
+
+	p.cursors[pagingKey{prefix: string(prefix), last: string(last)}] = &pagingCursor{
+		hash:    hash,
+		expires: now.Add(pagingCursorTTL),
+	}
+}
+
+// evict removes expired cursors, or the cursor closest to expiry if none have expired
+func (p *pagingCursors) evict(now time.Time) {
+	var (
+		oldest    pagingKey
+		oldestExp time.Time
+	)
+
+	for k, c := range p.cursors {
+		if now.After(c.expires) {
+			delete(p.cursors, k)
+			continue
+		}
+
+		if oldestExp.IsZero() || c.expires.Before(oldestExp) {
+			oldest, oldestExp = k, c.expires
+		}
+	}
+
+	if len(p.cursors) >= maxPagingCursors {
+		delete(p.cursors, oldest)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// queryInfo is the runtime call providing the dispatch info of an extrinsic, it is only
+// available if the runtime implements TransactionPaymentApi
+const queryInfo = "TransactionPaymentApi_query_info"
+
+// dispatch classes, as encoded by the runtime
+var dispatchClasses = []string{"normal", "operational", "mandatory"}
+
+// PaymentQueryInfoRequest holds the hex-encoded extrinsic to query and an optional block hash
+type PaymentQueryInfoRequest struct {
+	Ext  string
+	Hash *common.Hash
+}
+
+// UnmarshalJSON decodes the request from the [extrinsic, hash] params array, the hash is optional
+func (r *PaymentQueryInfoRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	err := json.Unmarshal(data, &params)
+	if err != nil {
+		return err
+	}
+
+	if len(params) == 0 || len(params) > 2 {
+		return errors.New("expected extrinsic and block hash parameters")
+	}
+
+	err = json.Unmarshal(params[0], &r.Ext)
+	if err != nil {
+		return err
+	}
+
+	var hash *string
+	if len(params) == 2 {
+		err = json.Unmarshal(params[1], &hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hash == nil {
+		return nil
+	}
+
+	h, err := common.HexToBytes(*hash)
+	if err != nil {
+		return err
+	}
+	if len(h) != len(common.Hash{}) {
+		return errors.New("invalid hash length")
+	}
+
+	r.Hash = new(common.Hash)
+	*r.Hash = common.BytesToHash(h)
+	return nil
+}
+
+// PaymentQueryInfoResponse holds the dispatch info of an extrinsic
+type PaymentQueryInfoResponse struct {
+	Weight     uint64 `json:"weight"`
+	Class      string `json:"class"`
+	PartialFee string `json:"partialFee"`
+}
+
+// PaymentModule is an RPC module providing the fees of extrinsics
+type PaymentModule struct {
+	coreAPI CoreAPI
+}
+
+// NewPaymentModule creates a new payment module.
+func NewPaymentModule(api CoreAPI) *PaymentModule {
+	return &PaymentModule{
+		coreAPI: api,
+	}
+}
+
+// QueryInfo returns the weight, dispatch class and fee of the given extrinsic. It returns an error if the
+// runtime doesn't implement TransactionPaymentApi.
+func (pm *PaymentModule) QueryInfo(r *http.Request, req *PaymentQueryInfoRequest, res *PaymentQueryInfoResponse) error {
+	ext, err := common.HexToBytes(req.Ext)
+	if err != nil {
+		return err
+	}
+
+	info, err := queryDispatchInfo(pm.coreAPI, ext, req.Hash)
+	if err != nil {
+		return err
+	}
+
+	*res = *info
+	return nil
+}
+
+// queryDispatchInfo returns the dispatch info of the given extrinsic at the block with the given hash, or the best
+// block if the hash is nil
+func queryDispatchInfo(coreAPI CoreAPI, ext []byte, bhash *common.Hash) (*PaymentQueryInfoResponse, error) {
+	// the runtime takes the extrinsic followed by its encoded length
+	data := make([]byte, len(ext)+4)
+	copy(data, ext)
+	binary.LittleEndian.PutUint32(data[len(ext):], uint32(len(ext)))
+
+	ret, err := coreAPI.CallRuntime(queryInfo, data, bhash)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDispatchInfo(ret)
+}
+
+// decodeDispatchInfo decodes a RuntimeDispatchInfo, which is a u64 weight, a dispatch class and a u128 fee
+func decodeDispatchInfo(in []byte) (*PaymentQueryInfoResponse, error) {
+	if len(in) != 25 {
+		return nil, errors.New("invalid dispatch info length")
+	}
+
+	if int(in[8]) >= len(dispatchClasses) {
+		return nil, fmt.Errorf("invalid dispatch class %d", in[8])
+	}
+
+	// the fee is little endian, big.Int expects big endian
+	fee := make([]byte, 16)
+	for i := range fee {
+		fee[i] = in[24-i]
+	}
+
+	return &PaymentQueryInfoResponse{
+		Weight:     binary.LittleEndian.Uint64(in[:8]),
+		Class:      dispatchClasses[in[8]],
+		PartialFee: new(big.Int).SetBytes(fee).String(),
+	}, nil
+}
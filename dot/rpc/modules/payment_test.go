@@ -0,0 +1,61 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentQueryInfoRequest_UnmarshalJSON(t *testing.T) {
+	hash := common.Hash{1}
+	req := new(PaymentQueryInfoRequest)
+	err := json.Unmarshal([]byte(fmt.Sprintf(`["0x0102", "%s"]`, hash)), req)
+	require.NoError(t, err)
+	require.Equal(t, &PaymentQueryInfoRequest{Ext: "0x0102", Hash: &hash}, req)
+
+	for _, in := range []string{`["0x0102"]`, `["0x0102", null]`} {
+		req = new(PaymentQueryInfoRequest)
+		err = json.Unmarshal([]byte(in), req)
+		require.NoError(t, err, in)
+		require.Equal(t, &PaymentQueryInfoRequest{Ext: "0x0102"}, req, in)
+	}
+
+	for _, in := range []string{`[]`, `{"ext": "0x0102"}`, `["0x0102", "0x01"]`} {
+		err = json.Unmarshal([]byte(in), new(PaymentQueryInfoRequest))
+		require.Error(t, err, in)
+	}
+}
+
+func TestDecodeDispatchInfo(t *testing.T) {
+	// weight 195000000, operational, fee 1000000000000000000000
+	in := common.MustHexToBytes("0xc0769f0b00000000010000a0dec5adc9353600000000000000")
+	info, err := decodeDispatchInfo(in)
+	require.NoError(t, err)
+	require.Equal(t, &PaymentQueryInfoResponse{
+		Weight:     195000000,
+		Class:      "operational",
+		PartialFee: "1000000000000000000000",
+	}, info)
+
+	_, err = decodeDispatchInfo(in[:24])
+	require.Error(t, err)
+}
@@ -18,20 +18,16 @@ package modules
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/scale"
 )
 
-// StateCallRequest holds json fields
-type StateCallRequest struct {
-	Method string      `json:"method"`
-	Data   []byte      `json:"data"`
-	Block  common.Hash `json:"block"`
-}
-
 // StateChildStorageRequest holds json fields
 type StateChildStorageRequest struct {
 	ChildStorageKey []byte      `json:"childStorageKey"`
@@ -58,11 +54,6 @@ type StateStorageQueryRangeRequest struct {
 // StateStorageKeysQuery field to store storage keys
 type StateStorageKeysQuery [][]byte
 
-// StateCallResponse holds json fields
-type StateCallResponse struct {
-	StateCallResponse []byte `json:"stateCallResponse"`
-}
-
 // StateKeysResponse field to store the state keys
 type StateKeysResponse [][]byte
 
@@ -78,6 +69,9 @@ type StateStorageSizeResponse uint64
 // StateStorageKeysResponse field for storage keys
 type StateStorageKeysResponse [][]byte
 
+// StateInsertStorageRequest is a list of hex encoded key-value pairs, a null value deletes the key
+type StateInsertStorageRequest [][2]*string
+
 // StateMetadataResponse holds the metadata
 //TODO: Determine actual type
 type StateMetadataResponse string
@@ -107,11 +101,17 @@ type StateRuntimeVersionResponse struct {
 	Apis             []interface{} `json:"apis"`
 }
 
+// maxKeysPagedCount is the maximum number of keys that can be requested in one page
+const maxKeysPagedCount = 1000
+
 // StateModule is an RPC module providing access to storage API points.
 type StateModule struct {
 	networkAPI NetworkAPI
 	storageAPI StorageAPI
 	coreAPI    CoreAPI
+	blockAPI   BlockAPI // only set if queries resolve against the finalized head
+	unsafe     bool     // enables methods that modify the chain state
+	cursors    *pagingCursors
 }
 
 // NewStateModule creates a new State module.
@@ -120,7 +120,79 @@ func NewStateModule(net NetworkAPI, storage StorageAPI, core CoreAPI) *StateModu
 		networkAPI: net,
 		storageAPI: storage,
 		coreAPI:    core,
+		cursors:    newPagingCursors(),
+	}
+}
+
+// UseFinalizedHead makes queries that don't specify a block resolve against the state of the finalized head
+// instead of the best block.
+func (sm *StateModule) UseFinalizedHead(api BlockAPI) {
+	sm.blockAPI = api
+}
+
+// EnableUnsafe enables the methods that modify the chain state, such as InsertStorage
+func (sm *StateModule) EnableUnsafe() {
+	sm.unsafe = true
+}
+
+// headHash returns the hash of the block that queries without a block hash resolve against, or nil if they
+// resolve against the best block
+func (sm *StateModule) headHash() (*common.Hash, error) {
+	if sm.blockAPI == nil {
+		return nil, nil
+	}
+
+	hash, err := sm.blockAPI.GetFinalizedHash(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hash, nil
+}
+
+// headStateRoot returns the state root of the block that queries without a block hash resolve against, or nil if
+// they resolve against the best block
+func (sm *StateModule) headStateRoot() (*common.Hash, error) {
+	hash, err := sm.headHash()
+	if err != nil || hash == nil {
+		return nil, err
+	}
+
+	header, err := sm.blockAPI.GetHeader(*hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &header.StateRoot, nil
+}
+
+// storageAt returns the value of the key at the state of the block with the hash in the request parameters,
+// if there is one
+func (sm *StateModule) storageAt(pReq []string, key []byte) ([]byte, error) {
+	var (
+		bhash *common.Hash
+		err   error
+	)
+
+	if len(pReq) > 1 {
+		var hash common.Hash
+		hash, err = common.HexToHash(pReq[1])
+		if err != nil {
+			return nil, err
+		}
+		bhash = &hash
+	} else {
+		bhash, err = sm.headHash()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if bhash == nil {
+		return sm.storageAPI.GetStorage(nil, key)
 	}
+
+	return sm.storageAPI.GetStorageByBlockHash(*bhash, key)
 }
 
 // GetPairs returns the keys with prefix, leave empty to get all the keys.
@@ -129,8 +201,14 @@ func (sm *StateModule) GetPairs(r *http.Request, req *[]string, res *[]interface
 	pReq := *req
 	reqBytes, _ := common.HexToBytes(pReq[0])
 
+	root, err := sm.headStateRoot()
+	if err != nil {
+		return err
+	}
+
 	if len(reqBytes) < 1 {
-		pairs, err := sm.storageAPI.Entries(nil)
+		var pairs map[string][]byte
+		pairs, err = sm.storageAPI.Entries(root)
 		if err != nil {
 			return err
 		}
@@ -140,7 +218,8 @@ func (sm *StateModule) GetPairs(r *http.Request, req *[]string, res *[]interface
 	} else {
 		// TODO this should return all keys with same prefix, currently only returning
 		//  matches.  Implement when #837 is done.
-		resI, err := sm.storageAPI.GetStorage(nil, reqBytes)
+		var resI []byte
+		resI, err = sm.storageAPI.GetStorage(root, reqBytes)
 		if err != nil {
 			return err
 		}
@@ -154,10 +233,42 @@ func (sm *StateModule) GetPairs(r *http.Request, req *[]string, res *[]interface
 	return nil
 }
 
-// Call isn't implemented properly yet.
-func (sm *StateModule) Call(r *http.Request, req *StateCallRequest, res *StateCallResponse) {
-	_ = sm.networkAPI
-	_ = sm.storageAPI
+// Call executes the runtime function with the given hex-encoded data at a specific block's state. If no block
+// hash is provided, the call is executed at the best block's state, or the finalized block's state if the node
+// is in finalized-only mode. The call is aborted if it exceeds the node's runtime call timeout.
+func (sm *StateModule) Call(r *http.Request, req *[]string, res *string) error {
+	pReq := *req
+	if len(pReq) < 2 {
+		return errors.New("expected method and data parameters")
+	}
+
+	data, err := common.HexToBytes(pReq[1])
+	if err != nil {
+		return err
+	}
+
+	var bhash *common.Hash
+	if len(pReq) > 2 {
+		var hash common.Hash
+		hash, err = common.HexToHash(pReq[2])
+		if err != nil {
+			return err
+		}
+		bhash = &hash
+	} else {
+		bhash, err = sm.headHash()
+		if err != nil {
+			return err
+		}
+	}
+
+	ret, err := sm.coreAPI.CallRuntime(pReq[0], data, bhash)
+	if err != nil {
+		return err
+	}
+
+	*res = common.BytesToHex(ret)
+	return nil
 }
 
 // GetChildKeys isn't implemented properly yet.
@@ -185,6 +296,99 @@ func (sm *StateModule) GetKeys(r *http.Request, req *StateStorageKeyRequest, res
 	// TODO implement change storage trie so that block hash parameter works (See issue #834)
 }
 
+// GetKeysPaged returns up to count storage keys with the given prefix that come after the given start key, with
+// parameters [prefix, count, startKey, hash]. If no block hash is given, a request whose start key is the last key of
+// a previously served page reads from the same block as that page, so that paginating through storage is consistent
+// even if new blocks are imported in between. If that block's state has since been pruned, an error is returned and
+// pagination has to be restarted.
+func (sm *StateModule) GetKeysPaged(r *http.Request, req *[]interface{}, res *[]string) error {
+	pReq := *req
+	if len(pReq) < 2 {
+		return errors.New("expected prefix and count parameters")
+	}
+
+	prefix, err := optionalHexParam(pReq[0])
+	if err != nil {
+		return err
+	}
+
+	fcount, ok := pReq[1].(float64)
+	if !ok || fcount < 0 {
+		return errors.New("count must be a positive number")
+	}
+	count := uint32(fcount)
+	if count > maxKeysPagedCount {
+		return fmt.Errorf("count exceeds the maximum of %d", maxKeysPagedCount)
+	}
+
+	var startKey []byte
+	if len(pReq) > 2 {
+		startKey, err = optionalHexParam(pReq[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	var bhash *common.Hash
+	if len(pReq) > 3 && pReq[3] != nil {
+		hashStr, ok := pReq[3].(string)
+		if !ok {
+			return errors.New("block hash must be a hex string")
+		}
+
+		var hash common.Hash
+		hash, err = common.HexToHash(hashStr)
+		if err != nil {
+			return err
+		}
+		bhash = &hash
+	} else if hash, has := sm.cursors.get(prefix, startKey); has {
+		bhash = &hash
+	} else {
+		bhash, err = sm.headHash()
+		if err != nil {
+			return err
+		}
+	}
+
+	hash, keys, err := sm.storageAPI.GetKeysPaged(bhash, prefix, startKey, count)
+	if errors.Is(err, state.ErrTrieDoesNotExist) && bhash != nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotPruned, bhash)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(keys) > 0 && uint32(len(keys)) == count {
+		sm.cursors.put(prefix, keys[len(keys)-1], hash)
+	}
+
+	*res = make([]string, len(keys))
+	for i, k := range keys {
+		(*res)[i] = common.BytesToHex(k)
+	}
+
+	return nil
+}
+
+// optionalHexParam decodes a hex-encoded request parameter, treating a missing value as empty
+func optionalHexParam(param interface{}) ([]byte, error) {
+	if param == nil {
+		return nil, nil
+	}
+
+	str, ok := param.(string)
+	if !ok {
+		return nil, errors.New("expected hex string parameter")
+	}
+
+	if str == "" || str == "0x" {
+		return nil, nil
+	}
+
+	return common.HexToBytes(str)
+}
+
 // GetMetadata calls runtime Metadata_metadata function
 func (sm *StateModule) GetMetadata(r *http.Request, req *StateRuntimeMetadataQuery, res *string) error {
 	// TODO implement change storage trie so that block hash parameter works (See issue #834)
@@ -217,27 +421,9 @@ func (sm *StateModule) GetStorage(r *http.Request, req *[]string, res *interface
 	pReq := *req
 	reqBytes, _ := common.HexToBytes(pReq[0]) // no need to catch error here
 
-	var (
-		item  []byte
-		bhash common.Hash
-		err   error
-	)
-
-	if len(pReq) > 1 {
-		bhash, err = common.HexToHash(pReq[1])
-		if err != nil {
-			return err
-		}
-
-		item, err = sm.storageAPI.GetStorageByBlockHash(bhash, reqBytes)
-		if err != nil {
-			return err
-		}
-	} else {
-		item, err = sm.storageAPI.GetStorage(nil, reqBytes)
-		if err != nil {
-			return err
-		}
+	item, err := sm.storageAt(pReq, reqBytes)
+	if err != nil {
+		return err
 	}
 
 	if len(item) > 0 {
@@ -256,27 +442,9 @@ func (sm *StateModule) GetStorageHash(r *http.Request, req *[]string, res *inter
 	pReq := *req
 	reqBytes, _ := common.HexToBytes(pReq[0])
 
-	var (
-		item  []byte
-		bhash common.Hash
-		err   error
-	)
-
-	if len(pReq) > 1 {
-		bhash, err = common.HexToHash(pReq[1])
-		if err != nil {
-			return err
-		}
-
-		item, err = sm.storageAPI.GetStorageByBlockHash(bhash, reqBytes)
-		if err != nil {
-			return err
-		}
-	} else {
-		item, err = sm.storageAPI.GetStorage(nil, reqBytes)
-		if err != nil {
-			return err
-		}
+	item, err := sm.storageAt(pReq, reqBytes)
+	if err != nil {
+		return err
 	}
 
 	if len(item) > 0 {
@@ -295,27 +463,9 @@ func (sm *StateModule) GetStorageSize(r *http.Request, req *[]string, res *inter
 	pReq := *req
 	reqBytes, _ := common.HexToBytes(pReq[0])
 
-	var (
-		item  []byte
-		bhash common.Hash
-		err   error
-	)
-
-	if len(pReq) > 1 {
-		bhash, err = common.HexToHash(pReq[1])
-		if err != nil {
-			return err
-		}
-
-		item, err = sm.storageAPI.GetStorageByBlockHash(bhash, reqBytes)
-		if err != nil {
-			return err
-		}
-	} else {
-		item, err = sm.storageAPI.GetStorage(nil, reqBytes)
-		if err != nil {
-			return err
-		}
+	item, err := sm.storageAt(pReq, reqBytes)
+	if err != nil {
+		return err
 	}
 
 	if len(item) > 0 {
@@ -330,6 +480,7 @@ func (sm *StateModule) GetStorageSize(r *http.Request, req *[]string, res *inter
 // QueryStorage isn't implemented properly yet.
 func (sm *StateModule) QueryStorage(r *http.Request, req *StateStorageQueryRangeRequest, res *StorageChangeSetResponse) {
 	// TODO implement change storage trie so that block hash parameter works (See issue #834)
+	_ = sm.networkAPI
 }
 
 // SubscribeRuntimeVersion isn't implemented properly yet.
@@ -354,3 +505,41 @@ func convertAPIs(in []*runtime.API_Item) []interface{} {
 	}
 	return ret
 }
+
+// InsertStorage writes the given key-value pairs into the state of the best block and returns the new state root.
+// The next block is built on top of the modified state. It's only available if unsafe methods are enabled.
+func (sm *StateModule) InsertStorage(r *http.Request, req *StateInsertStorageRequest, res *string) error {
+	if !sm.unsafe {
+		return ErrUnsafeDisabled
+	}
+
+	entries := make([]*state.KeyValue, len(*req))
+	for i, kv := range *req {
+		if kv[0] == nil {
+			return errors.New("storage key is required")
+		}
+
+		key, err := common.HexToBytes(*kv[0])
+		if err != nil {
+			return err
+		}
+
+		entries[i] = &state.KeyValue{Key: key}
+		if kv[1] == nil {
+			continue
+		}
+
+		entries[i].Value, err = common.HexToBytes(*kv[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	root, err := sm.storageAPI.InsertStorage(entries)
+	if err != nil {
+		return err
+	}
+
+	*res = root.String()
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/stretchr/testify/require"
@@ -109,6 +110,70 @@ func TestStateModule_GetStorage(t *testing.T) {
 	require.Equal(t, expected, hex)
 }
 
+func TestStateModule_GetStorage_FinalizedOnly(t *testing.T) {
+	sm, chain := setupStateModuleWithState(t)
+	sm.UseFinalizedHead(chain.Block)
+
+	// queries without a block hash resolve against the finalized head, which isn't a known block here
+	err := chain.Block.SetFinalizedHash(common.Hash{1, 2, 3}, 0, 0)
+	require.NoError(t, err)
+
+	req := []string{"0x3a6b657931"} // :key1
+	var res interface{}
+
+	err = sm.GetStorage(nil, &req, &res)
+	require.Error(t, err)
+
+	// an explicit block hash is still respected
+	req = append(req, chain.Block.BestBlockHash().String())
+	err = sm.GetStorage(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHex([]byte(`value1`)), res)
+}
+
+func TestStateModule_GetKeysPaged(t *testing.T) {
+	sm, chain := setupStateModuleWithState(t)
+
+	req := []interface{}{"0x3a6b6579", float64(1)} // :key
+	var res []string
+	err := sm.GetKeysPaged(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0x3a6b657931"}, res) // :key1
+
+	// import a block with a key that sorts between the keys of the first state
+	ts, err := chain.Storage.TrieState(nil)
+	require.NoError(t, err)
+	err = ts.Set([]byte(`:key15`), []byte(`value15`))
+	require.NoError(t, err)
+
+	sr, err := ts.Root()
+	require.NoError(t, err)
+	err = chain.Storage.StoreTrie(sr, ts)
+	require.NoError(t, err)
+
+	err = chain.Block.AddBlock(&types.Block{
+		Header: &types.Header{
+			ParentHash: chain.Block.BestBlockHash(),
+			Number:     big.NewInt(3),
+			StateRoot:  sr,
+		},
+		Body: types.NewBody([]byte{}),
+	})
+	require.NoError(t, err)
+
+	// the next page is read from the block the first page was read from
+	req = []interface{}{"0x3a6b6579", float64(1), res[0]}
+	err = sm.GetKeysPaged(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0x3a6b657932"}, res) // :key2
+
+	// a new query starts at the best block
+	req = []interface{}{"0x3a6b6579", float64(10)}
+	err = sm.GetKeysPaged(nil, &req, &res)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0x3a6b657931", "0x3a6b65793135", "0x3a6b657932"}, res)
+}
+
 func TestStateModule_GetStorage_NotFound(t *testing.T) {
 	sm := setupStateModule(t)
 
@@ -162,6 +227,19 @@ func TestStateModule_GetStorageSize_NotFound(t *testing.T) {
 	require.Equal(t, nil, res)
 }
 
+func TestStateModule_Call_InvalidParams(t *testing.T) {
+	sm := setupStateModule(t)
+	var res string
+
+	req := []string{"Core_version"}
+	err := sm.Call(nil, &req, &res)
+	require.Error(t, err)
+
+	req = []string{"Core_version", "0x", "0xzz"}
+	err = sm.Call(nil, &req, &res)
+	require.Error(t, err)
+}
+
 func TestStateModule_GetMetadata(t *testing.T) {
 	sm := setupStateModule(t)
 	var res string
@@ -172,7 +250,37 @@ func TestStateModule_GetMetadata(t *testing.T) {
 	require.Equal(t, expected, res)
 }
 
+func TestStateModule_InsertStorage(t *testing.T) {
+	sm := setupStateModule(t)
+	key, value := "0x3a6b657933", "0x76616c756533" // :key3, value3
+	remove := "0x3a6b657931"                       // :key1
+	req := StateInsertStorageRequest{{&key, &value}, {&remove, nil}}
+	var root string
+
+	err := sm.InsertStorage(nil, &req, &root)
+	require.Equal(t, ErrUnsafeDisabled, err)
+
+	sm.EnableUnsafe()
+	err = sm.InsertStorage(nil, &req, &root)
+	require.NoError(t, err)
+	require.NotEmpty(t, root)
+
+	var res interface{}
+	err = sm.GetStorage(nil, &[]string{key}, &res)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	err = sm.GetStorage(nil, &[]string{remove}, &res)
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
 func setupStateModule(t *testing.T) *StateModule {
+	sm, _ := setupStateModuleWithState(t)
+	return sm
+}
+
+func setupStateModuleWithState(t *testing.T) (*StateModule, *state.Service) {
 	// setup service
 	net := newNetworkService(t)
 	chain := newTestStateService(t)
@@ -201,5 +309,5 @@ func setupStateModule(t *testing.T) *StateModule {
 	require.NoError(t, err)
 
 	core := newCoreService(t)
-	return NewStateModule(net, chain.Storage, core)
+	return NewStateModule(net, chain.Storage, core), chain
 }
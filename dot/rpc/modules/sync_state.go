@@ -0,0 +1,55 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SyncStateModule is an RPC module providing chain specs with the light sync state of the node
+type SyncStateModule struct {
+	syncStateAPI SyncStateAPI
+}
+
+// NewSyncStateModule creates a new sync state module.
+func NewSyncStateModule(api SyncStateAPI) *SyncStateModule {
+	return &SyncStateModule{
+		syncStateAPI: api,
+	}
+}
+
+// GenSyncSpec returns the chain spec of the node with the light sync state of the latest finalized block embedded,
+// in raw format if the first parameter is true
+func (sm *SyncStateModule) GenSyncSpec(r *http.Request, req *[]bool, res *json.RawMessage) error {
+	if sm.syncStateAPI == nil {
+		return ErrSyncStateUnavailable
+	}
+
+	raw := false
+	if len(*req) > 0 {
+		raw = (*req)[0]
+	}
+
+	spec, err := sm.syncStateAPI.GenSyncSpec(raw)
+	if err != nil {
+		return err
+	}
+
+	*res = spec
+	return nil
+}
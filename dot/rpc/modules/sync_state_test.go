@@ -0,0 +1,51 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockSyncStateAPI struct {
+	raw bool
+}
+
+func (m *mockSyncStateAPI) GenSyncSpec(raw bool) ([]byte, error) {
+	m.raw = raw
+	return []byte(`{"name":"test"}`), nil
+}
+
+func TestSyncStateModule_GenSyncSpec(t *testing.T) {
+	api := new(mockSyncStateAPI)
+	sm := NewSyncStateModule(api)
+
+	var res json.RawMessage
+	err := sm.GenSyncSpec(nil, &[]bool{true}, &res)
+	require.NoError(t, err)
+	require.True(t, api.raw)
+	require.Equal(t, `{"name":"test"}`, string(res))
+
+	err = sm.GenSyncSpec(nil, &[]bool{}, &res)
+	require.NoError(t, err)
+	require.False(t, api.raw)
+
+	err = NewSyncStateModule(nil).GenSyncSpec(nil, &[]bool{}, &res)
+	require.Equal(t, ErrSyncStateUnavailable, err)
+}
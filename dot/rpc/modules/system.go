@@ -17,15 +17,22 @@
 package modules
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/blockstats"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/memstats"
 )
 
 // SystemModule is an RPC module providing access to core API points
 type SystemModule struct {
 	networkAPI NetworkAPI
 	systemAPI  SystemAPI
+	syncAPI    SyncAPI
+	memoryAPI  MemoryAPI
+	statsAPI   BlockStatsAPI
 }
 
 // EmptyRequest represents an RPC request with no fields
@@ -55,26 +62,89 @@ type SystemPeersResponse struct {
 	Peers []common.PeerInfo `json:"peers"`
 }
 
+// SystemNodeIdentityResponse is the operator identity of the node, signed with the node's network key
+type SystemNodeIdentityResponse struct {
+	PeerID      string `json:"peerId"`
+	DisplayName string `json:"displayName"`
+	Location    string `json:"location"`
+	Contact     string `json:"contact"`
+	Signature   string `json:"signature"`
+}
+
+// SystemSyncStateResponse is the sync status of the node
+type SystemSyncStateResponse struct {
+	StartingBlock uint64 `json:"startingBlock"`
+	CurrentBlock  uint64 `json:"currentBlock"`
+	HighestBlock  uint64 `json:"highestBlock"`
+	Resumed       bool   `json:"resumed"`
+}
+
+// SystemMemoryUsageResponse is the approximate memory held by each subsystem of the node, and their total
+type SystemMemoryUsageResponse struct {
+	Subsystems []memstats.Usage `json:"subsystems"`
+	Total      uint64           `json:"total"`
+}
+
+func newSystemNodeIdentityResponse(ni *common.NodeIdentity) *SystemNodeIdentityResponse {
+	return &SystemNodeIdentityResponse{
+		PeerID:      ni.PeerID,
+		DisplayName: ni.DisplayName,
+		Location:    ni.Location,
+		Contact:     ni.Contact,
+		Signature:   common.BytesToHex(ni.Signature),
+	}
+}
+
 // NewSystemModule creates a new API instance
-func NewSystemModule(net NetworkAPI, sys SystemAPI) *SystemModule {
+func NewSystemModule(net NetworkAPI, sys SystemAPI, syncAPI SyncAPI) *SystemModule {
 	return &SystemModule{
 		networkAPI: net, // TODO: migrate to network state
 		systemAPI:  sys,
+		syncAPI:    syncAPI,
 	}
 }
 
+// UseMemoryAPI enables the MemoryUsage method, which reports the memory usage of the given subsystems
+func (sm *SystemModule) UseMemoryAPI(api MemoryAPI) {
+	sm.memoryAPI = api
+}
+
+// UseBlockStatsAPI enables the BlockStats method, which reports the block stats observed by the node
+func (sm *SystemModule) UseBlockStatsAPI(api BlockStatsAPI) {
+	sm.statsAPI = api
+}
+
 // Chain returns the runtime chain
 func (sm *SystemModule) Chain(r *http.Request, req *EmptyRequest, res *string) error {
 	*res = sm.systemAPI.NodeName()
 	return nil
 }
 
-// Name returns the runtime name
+// Name returns the name of the node implementation
 func (sm *SystemModule) Name(r *http.Request, req *EmptyRequest, res *string) error {
 	*res = sm.systemAPI.SystemName()
 	return nil
 }
 
+func (sm *SystemModule) nodeIdentity() *common.NodeIdentity {
+	if sm.networkAPI == nil {
+		return nil
+	}
+
+	return sm.networkAPI.NodeIdentity()
+}
+
+// NodeIdentity returns the operator identity of the node, signed with the node's network key
+func (sm *SystemModule) NodeIdentity(r *http.Request, req *EmptyRequest, res *SystemNodeIdentityResponse) error {
+	ni := sm.nodeIdentity()
+	if ni == nil {
+		return errors.New("node identity is not configured")
+	}
+
+	*res = *newSystemNodeIdentityResponse(ni)
+	return nil
+}
+
 // Properties returns the runtime properties
 func (sm *SystemModule) Properties(r *http.Request, req *EmptyRequest, res *interface{}) error {
 	*res = sm.systemAPI.Properties()
@@ -111,25 +181,57 @@ func (sm *SystemModule) Peers(r *http.Request, req *EmptyRequest, res *SystemPee
 	return nil
 }
 
-// NodeRoles Returns the roles the node is running as.
+// NodeRoles returns the roles the node is running as, as Substrate reports them: Authority for an authority node,
+// LightClient for a light client and Full otherwise
 func (sm *SystemModule) NodeRoles(r *http.Request, req *EmptyRequest, res *[]interface{}) error {
-	resultArray := []interface{}{}
-
-	role := sm.networkAPI.NodeRoles()
-	switch role {
-	case 1:
-		resultArray = append(resultArray, "Full")
-	case 2:
-		resultArray = append(resultArray, "LightClient")
-	case 4:
-		resultArray = append(resultArray, "Authority")
-	default:
-		resultArray = append(resultArray, "UnknownRole")
-		uknrole := []interface{}{}
-		uknrole = append(uknrole, role)
-		resultArray = append(resultArray, uknrole)
+	role := "Full"
+	switch sm.networkAPI.NodeRoles() {
+	case types.LightClientRole:
+		role = "LightClient"
+	case types.AuthorityRole:
+		role = "Authority"
+	}
+
+	*res = []interface{}{role}
+	return nil
+}
+
+// SyncState returns the block number the node started syncing from, the best block number and the highest
+// block number seen from peers. Resumed is true if the node resumed a sync interrupted by a restart.
+func (sm *SystemModule) SyncState(r *http.Request, req *EmptyRequest, res *SystemSyncStateResponse) error {
+	if sm.syncAPI == nil {
+		return errors.New("sync state not available")
+	}
+
+	state := sm.syncAPI.SyncState()
+	res.StartingBlock = state.StartingBlock.Uint64()
+	res.CurrentBlock = state.CurrentBlock.Uint64()
+	res.HighestBlock = state.HighestBlock.Uint64()
+	res.Resumed = state.Resumed
+	return nil
+}
+
+// MemoryUsage returns the approximate memory held by the trie cache, blocktree, transaction pool, pending blocks
+// and subscriptions, so that operators can see which subsystem is responsible when the node's memory grows
+func (sm *SystemModule) MemoryUsage(r *http.Request, req *EmptyRequest, res *SystemMemoryUsageResponse) error {
+	if sm.memoryAPI == nil {
+		return errors.New("memory usage not available")
+	}
+
+	res.Subsystems = sm.memoryAPI.Usage()
+	for _, u := range res.Subsystems {
+		res.Total += u.Bytes
+	}
+	return nil
+}
+
+// BlockStats returns the time between recent blocks, their propagation delay and the slots without a block, so
+// that operators can tell network-wide degradation from local issues by comparing the stats of several nodes
+func (sm *SystemModule) BlockStats(r *http.Request, req *EmptyRequest, res *blockstats.Stats) error {
+	if sm.statsAPI == nil {
+		return errors.New("block stats not available")
 	}
 
-	*res = resultArray
+	*res = sm.statsAPI.Stats()
 	return nil
 }
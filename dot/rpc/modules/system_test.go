@@ -24,7 +24,10 @@ import (
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/blockstats"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/memstats"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,6 +50,10 @@ func (s *mockSyncer) HandleBlockResponse(msg *network.BlockResponseMessage) *net
 	return nil
 }
 
+func (s *mockSyncer) ValidateBlockAnnounce(msg *network.BlockAnnounceMessage) error {
+	return nil
+}
+
 func (s *mockSyncer) HandleBlockAnnounce(msg *network.BlockAnnounceMessage) *network.BlockRequestMessage {
 	return nil
 }
@@ -55,6 +62,15 @@ func (s *mockSyncer) HandleSeenBlocks(num *big.Int) *network.BlockRequestMessage
 	return nil
 }
 
+func (s *mockSyncer) SyncState() *types.SyncState {
+	return &types.SyncState{
+		StartingBlock: big.NewInt(10),
+		CurrentBlock:  big.NewInt(12),
+		HighestBlock:  big.NewInt(20),
+		Resumed:       true,
+	}
+}
+
 func newNetworkService(t *testing.T) *network.Service {
 	testDir := path.Join(os.TempDir(), "test_data")
 
@@ -76,7 +92,7 @@ func newNetworkService(t *testing.T) *network.Service {
 // Test RPC's System.Health() response
 func TestSystemModule_Health(t *testing.T) {
 	net := newNetworkService(t)
-	sys := NewSystemModule(net, nil)
+	sys := NewSystemModule(net, nil, nil)
 
 	res := &SystemHealthResponse{}
 	err := sys.Health(nil, nil, res)
@@ -90,7 +106,7 @@ func TestSystemModule_Health(t *testing.T) {
 // Test RPC's System.NetworkState() response
 func TestSystemModule_NetworkState(t *testing.T) {
 	net := newNetworkService(t)
-	sys := NewSystemModule(net, nil)
+	sys := NewSystemModule(net, nil, nil)
 
 	res := &SystemNetworkStateResponse{}
 	err := sys.NetworkState(nil, nil, res)
@@ -106,7 +122,7 @@ func TestSystemModule_NetworkState(t *testing.T) {
 // Test RPC's System.Peers() response
 func TestSystemModule_Peers(t *testing.T) {
 	net := newNetworkService(t)
-	sys := NewSystemModule(net, nil)
+	sys := NewSystemModule(net, nil, nil)
 
 	res := &SystemPeersResponse{}
 	err := sys.Peers(nil, nil, res)
@@ -119,11 +135,125 @@ func TestSystemModule_Peers(t *testing.T) {
 
 func TestSystemModule_NodeRoles(t *testing.T) {
 	net := newNetworkService(t)
-	sys := NewSystemModule(net, nil)
+	sys := NewSystemModule(net, nil, nil)
 	expected := []interface{}{"Full"}
 
 	var res []interface{}
 	err := sys.NodeRoles(nil, nil, &res)
 	require.NoError(t, err)
 	require.Equal(t, expected, res)
+
+	for roles, expected := range map[byte]string{
+		types.NoNetworkRole:   "Full",
+		types.FullNodeRole:    "Full",
+		types.LightClientRole: "LightClient",
+		types.AuthorityRole:   "Authority",
+	} {
+		sys = NewSystemModule(&mockRolesNetwork{NetworkAPI: net, roles: roles}, nil, nil)
+		err = sys.NodeRoles(nil, nil, &res)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{expected}, res, roles)
+	}
+}
+
+type mockRolesNetwork struct {
+	NetworkAPI
+	roles byte
+}
+
+func (n *mockRolesNetwork) NodeRoles() byte {
+	return n.roles
+}
+
+func TestSystemModule_NodeIdentity(t *testing.T) {
+	net := newNetworkService(t)
+	sys := NewSystemModule(net, nil, nil)
+
+	err := sys.NodeIdentity(nil, nil, &SystemNodeIdentityResponse{})
+	require.Error(t, err)
+
+	cfg := &network.Config{
+		NoStatus:     true,
+		NetworkState: &state.NetworkState{},
+		BasePath:     path.Join(os.TempDir(), "test_data_identity"),
+		Syncer:       &mockSyncer{},
+		DisplayName:  "validator-1",
+		Location:     "eu-west",
+	}
+
+	net, err = network.NewService(cfg)
+	require.NoError(t, err)
+	sys = NewSystemModule(net, nil, nil)
+
+	res := &SystemNodeIdentityResponse{}
+	err = sys.NodeIdentity(nil, nil, res)
+	require.NoError(t, err)
+	require.Equal(t, net.NetworkState().PeerID, res.PeerID)
+	require.Equal(t, "validator-1", res.DisplayName)
+	require.Equal(t, "eu-west", res.Location)
+	require.NoError(t, network.VerifyNodeIdentity(net.NodeIdentity()))
+
+	// the identity isn't reported as a role
+	var roles []interface{}
+	err = sys.NodeRoles(nil, nil, &roles)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"Full"}, roles)
+}
+
+func TestSystemModule_SyncState(t *testing.T) {
+	sys := NewSystemModule(nil, nil, nil)
+	err := sys.SyncState(nil, nil, &SystemSyncStateResponse{})
+	require.Error(t, err)
+
+	sys = NewSystemModule(nil, nil, &mockSyncer{})
+	res := &SystemSyncStateResponse{}
+	err = sys.SyncState(nil, nil, res)
+	require.NoError(t, err)
+
+	expected := &SystemSyncStateResponse{
+		StartingBlock: 10,
+		CurrentBlock:  12,
+		HighestBlock:  20,
+		Resumed:       true,
+	}
+	require.Equal(t, expected, res)
+}
+
+func TestSystemModule_MemoryUsage(t *testing.T) {
+	sys := NewSystemModule(nil, nil, nil)
+	err := sys.MemoryUsage(nil, nil, &SystemMemoryUsageResponse{})
+	require.Error(t, err)
+
+	st := newTestStateService(t)
+	mem := memstats.NewRegistry()
+	mem.Register(memstats.BlockTree, st.Block)
+	mem.Register(memstats.TransactionPool, st.Transaction)
+	sys.UseMemoryAPI(mem)
+
+	res := &SystemMemoryUsageResponse{}
+	err = sys.MemoryUsage(nil, nil, res)
+	require.NoError(t, err)
+	require.Len(t, res.Subsystems, 2)
+	require.Equal(t, memstats.BlockTree, res.Subsystems[0].Subsystem)
+	require.NotZero(t, res.Subsystems[0].Bytes)
+	require.Equal(t, memstats.TransactionPool, res.Subsystems[1].Subsystem)
+	require.Equal(t, res.Subsystems[0].Bytes+res.Subsystems[1].Bytes, res.Total)
+}
+
+type mockBlockStatsAPI struct{}
+
+func (mockBlockStatsAPI) Stats() blockstats.Stats {
+	return blockstats.Stats{Blocks: 10, MissedSlots: 2}
+}
+
+func TestSystemModule_BlockStats(t *testing.T) {
+	sys := NewSystemModule(nil, nil, nil)
+	err := sys.BlockStats(nil, nil, &blockstats.Stats{})
+	require.Error(t, err)
+
+	sys.UseBlockStatsAPI(mockBlockStatsAPI{})
+	res := &blockstats.Stats{}
+	err = sys.BlockStats(nil, nil, res)
+	require.NoError(t, err)
+	require.Equal(t, blockstats.Stats{Blocks: 10, MissedSlots: 2}, *res)
 }
@@ -18,16 +18,19 @@ package rpc
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
 	"net/http"
-	"strings"
+	"strconv"
+	"sync"
 
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/gorilla/websocket"
 )
 
@@ -59,6 +62,21 @@ func newSubscriptionResponseJSON(subID int, reqID float64) SubscriptionResponseJ
 	}
 }
 
+// BooleanResponseJSON for json responses with a boolean result, such as unsubscription responses
+type BooleanResponseJSON struct {
+	Jsonrpc string  `json:"jsonrpc"`
+	Result  bool    `json:"result"`
+	ID      float64 `json:"id"`
+}
+
+func newBooleanResponseJSON(result bool, reqID float64) BooleanResponseJSON {
+	return BooleanResponseJSON{
+		Jsonrpc: "2.0",
+		Result:  result,
+		ID:      reqID,
+	}
+}
+
 // ErrorResponseJSON json for error responses
 type ErrorResponseJSON struct {
 	Jsonrpc string            `json:"jsonrpc"`
@@ -74,6 +92,15 @@ type ErrorMessageJSON struct {
 
 var rpcHost string
 
+// subscribeMethods are the methods that start a subscription handled by the websocket connection
+var subscribeMethods = map[string]bool{
+	"chain_subscribeNewHeads":       true,
+	"chain_subscribeNewHead":        true,
+	"chain_subscribeFinalizedHeads": true,
+	"state_subscribeStorage":        true,
+	"author_subscribePoolEvents":    true,
+}
+
 // ServeHTTP implemented to handle WebSocket connections
 func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var upg = websocket.Upgrader{
@@ -89,9 +116,16 @@ func (h *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	// create wsConn
 	wsc := NewWSConn(ws, h.serverConfig)
-	h.wsConns = append(h.wsConns, wsc)
+	// calls forwarded to the rpc server are authorised with the credentials of the upgrade request
+	wsc.authHeader = r.Header.Get("Authorization")
+	wsc.metrics = h.metrics
+	h.addWSConn(wsc)
 
-	go wsc.handleComm()
+	go func() {
+		wsc.handleComm()
+		// the connection is no longer being read from, release its subscriptions
+		h.removeWSConn(wsc)
+	}()
 }
 
 // NewWSConn to create new WebSocket Connection struct
@@ -102,12 +136,135 @@ func NewWSConn(conn *websocket.Conn, cfg *HTTPServerConfig) *WSConn {
 		subscriptions:      make(map[int]Listener),
 		blockSubChannels:   make(map[int]byte),
 		storageSubChannels: make(map[int]byte),
+		watches:            newExtrinsicWatches(),
+		maxSubscriptions:   cfg.WSMaxSubscriptions,
+		finalizedOnly:      cfg.FinalizedOnly,
 		storageAPI:         cfg.StorageAPI,
 		blockAPI:           cfg.BlockAPI,
+		txStateAPI:         cfg.TransactionQueueAPI,
 	}
 	return c
 }
 
+// SubscriptionCount returns the number of active subscriptions for the connection
+func (c *WSConn) SubscriptionCount() int {
+	c.subscriptionsLock.RLock()
+	defer c.subscriptionsLock.RUnlock()
+
+	return len(c.subscriptions)
+}
+
+// addSubscription stores the listener and returns its subscription ID
+func (c *WSConn) addSubscription(l Listener) int {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	c.qtyListeners++
+	c.subscriptions[c.qtyListeners] = l
+	return c.qtyListeners
+}
+
+// closeSubscriptions unregisters and closes the channels of all the connection's listeners
+func (c *WSConn) closeSubscriptions() {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	for id := range c.subscriptions {
+		c.removeSubscription(id)
+	}
+}
+
+// unsubscribe cancels the subscription with the given ID if it was made with the subscribe method matching the
+// unsubscribe method, it returns false if there is none
+func (c *WSConn) unsubscribe(method string, subID int) bool {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	var ok bool
+	switch l := c.subscriptions[subID].(type) {
+	case *StorageChangeListener, *FinalizedStorageChangeListener:
+		ok = method == "state_unsubscribeStorage"
+	case *BlockListener:
+		ok = method == "chain_unsubscribeNewHeads" || method == "chain_unsubscribeNewHead"
+	case *BlockFinalizedListener:
+		// in finalized-only mode, new heads subscriptions are sent finalized blocks
+		switch l.method {
+		case "chain_newHead":
+			ok = method == "chain_unsubscribeNewHeads" || method == "chain_unsubscribeNewHead"
+		case "chain_finalizedHead":
+			ok = method == "chain_unsubscribeFinalizedHeads"
+		}
+	}
+
+	if !ok {
+		return false
+	}
+
+	c.removeSubscription(subID)
+	return true
+}
+
+// removeSubscription unregisters and closes the channels of the listener with the given subscription ID and
+// frees its slot, subscriptionsLock must be held
+func (c *WSConn) removeSubscription(subID int) {
+	switch v := c.subscriptions[subID].(type) {
+	case *StorageChangeListener:
+		c.storageAPI.UnregisterStorageChangeChannel(v.chanID)
+		close(v.channel)
+	case *BlockListener:
+		c.blockAPI.UnregisterImportedChannel(v.chanID)
+		close(v.channel)
+	case *BlockFinalizedListener:
+		c.blockAPI.UnregisterFinalizedChannel(v.chanID)
+		close(v.channel)
+	case *FinalizedStorageChangeListener:
+		c.blockAPI.UnregisterFinalizedChannel(v.chanID)
+		close(v.channel)
+	case *ExtrinsicWatchListener:
+		v.stop()
+	case *PoolEventListener:
+		v.stop()
+	}
+
+	delete(c.subscriptions, subID)
+	delete(c.blockSubChannels, subID)
+	delete(c.storageSubChannels, subID)
+}
+
+// subscriptionLimitReached returns true and sends an error for the request if the connection has reached its
+// maximum number of subscriptions
+func (c *WSConn) subscriptionLimitReached(reqID float64) bool {
+	if c.maxSubscriptions == 0 || c.SubscriptionCount() < int(c.maxSubscriptions) {
+		return false
+	}
+
+	logger.Debug("websocket subscription limit reached", "limit", c.maxSubscriptions)
+	if c.metrics != nil {
+		c.metrics.rejectSubscription()
+	}
+
+	err := c.safeSendError(reqID, big.NewInt(-32000), "Too many subscriptions")
+	if err != nil {
+		logger.Warn("websocket failed write message", "error", err)
+	}
+	return true
+}
+
+// subscriptionRequestID returns the ID of a subscription request. Subscription responses are sent with numeric
+// IDs, so an error is sent and false is returned if the ID isn't a number.
+func (c *WSConn) subscriptionRequestID(msg map[string]interface{}) (float64, bool) {
+	reqID, ok := msg["id"].(float64)
+	if ok {
+		return reqID, true
+	}
+
+	err := c.safeSendError(0, big.NewInt(-32600), "Invalid request")
+	if err != nil {
+		logger.Warn("websocket failed write message", "error", err)
+	}
+	return 0, false
+}
+
 func (c *WSConn) safeSend(msg interface{}) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -150,12 +307,103 @@ func (c *WSConn) handleComm() {
 			continue
 		}
 		method := msg["method"]
-		// if method contains subscribe, then register subscription
-		if strings.Contains(fmt.Sprintf("%s", method), "subscribe") {
-			reqid := msg["id"].(float64)
+
+		// extrinsic watches are subscriptions, even though their methods don't contain subscribe
+		switch method {
+		case "author_submitAndWatchExtrinsic":
+			reqid, ok := c.subscriptionRequestID(msg)
+			if !ok || c.subscriptionLimitReached(reqid) {
+				continue
+			}
+
+			// the extrinsic isn't submitted if it can't be watched
+			if c.watches.count() >= maxExtrinsicWatches {
+				err = c.safeSendError(reqid, big.NewInt(-32000), "Too many extrinsic watches")
+				if err != nil {
+					logger.Warn("websocket failed write message", "error", err)
+				}
+				continue
+			}
+
+			el, err5 := c.initExtrinsicWatch(reqid, msg["params"])
+			if err5 != nil {
+				logger.Warn("failed to create extrinsic watch", "error", err5)
+				continue
+			}
+			c.startListener(el)
+			continue
+		case "author_unwatchExtrinsic":
+			reqid, ok := c.subscriptionRequestID(msg)
+			if !ok {
+				continue
+			}
+
+			removed := false
+			if subID, valid := parseSubscriptionID(msg["params"]); valid {
+				removed = c.unwatchExtrinsic(subID)
+			}
+
+			err = c.safeSend(newBooleanResponseJSON(removed, reqid))
+			if err != nil {
+				logger.Warn("websocket failed write message", "error", err)
+			}
+			continue
+		case "author_unsubscribePoolEvents":
+			reqid, ok := c.subscriptionRequestID(msg)
+			if !ok {
+				continue
+			}
+
+			removed := false
+			if subID, valid := parseSubscriptionID(msg["params"]); valid {
+				removed = c.unsubscribePoolEvents(subID)
+			}
+
+			err = c.safeSend(newBooleanResponseJSON(removed, reqid))
+			if err != nil {
+				logger.Warn("websocket failed write message", "error", err)
+			}
+			continue
+		case "state_unsubscribeStorage", "chain_unsubscribeNewHeads", "chain_unsubscribeNewHead",
+			"chain_unsubscribeFinalizedHeads":
+			reqid, ok := c.subscriptionRequestID(msg)
+			if !ok {
+				continue
+			}
+
+			removed := false
+			if subID, valid := parseSubscriptionID(msg["params"]); valid {
+				removed = c.unsubscribe(method.(string), subID)
+			}
+
+			err = c.safeSend(newBooleanResponseJSON(removed, reqid))
+			if err != nil {
+				logger.Warn("websocket failed write message", "error", err)
+			}
+			continue
+		}
+
+		// register subscriptions, other calls are forwarded to the rpc server
+		if subscribeMethods[fmt.Sprintf("%s", method)] {
+			reqid, ok := c.subscriptionRequestID(msg)
+			if !ok || c.subscriptionLimitReached(reqid) {
+				continue
+			}
 			params := msg["params"]
+
+			// in finalized-only mode, new heads and storage changes are only sent for finalized blocks
 			switch method {
 			case "chain_subscribeNewHeads", "chain_subscribeNewHead":
+				if c.finalizedOnly {
+					bfl, err3 := c.initBlockFinalizedListener(reqid, "chain_newHead")
+					if err3 != nil {
+						logger.Warn("failed to create block finalized", "error", err3)
+						continue
+					}
+					c.startListener(bfl)
+					continue
+				}
+
 				bl, err1 := c.initBlockListener(reqid)
 				if err1 != nil {
 					logger.Warn("failed to create block listener", "error", err)
@@ -163,6 +411,16 @@ func (c *WSConn) handleComm() {
 				}
 				c.startListener(bl)
 			case "state_subscribeStorage":
+				if c.finalizedOnly {
+					fscl, err4 := c.initFinalizedStorageChangeListener(reqid, params)
+					if err4 != nil {
+						logger.Warn("failed to create finalized state change listener", "error", err4)
+						continue
+					}
+					c.startListener(fscl)
+					continue
+				}
+
 				scl, err2 := c.initStorageChangeListener(reqid, params)
 				if err2 != nil {
 					logger.Warn("failed to create state change listener", "error", err)
@@ -170,66 +428,82 @@ func (c *WSConn) handleComm() {
 				}
 				c.startListener(scl)
 			case "chain_subscribeFinalizedHeads":
-				bfl, err3 := c.initBlockFinalizedListener(reqid)
+				bfl, err3 := c.initBlockFinalizedListener(reqid, "chain_finalizedHead")
 				if err3 != nil {
 					logger.Warn("failed to create block finalized", "error", err)
 					continue
 				}
 				c.startListener(bfl)
+			case "author_subscribePoolEvents":
+				pel, err6 := c.initPoolEventListener(reqid)
+				if err6 != nil {
+					logger.Warn("failed to create pool event listener", "error", err6)
+					continue
+				}
+				c.startListener(pel)
 			}
 			continue
 		}
 
 		// handle non-subscribe calls
-		client := &http.Client{}
-		buf := &bytes.Buffer{}
-		_, err = buf.Write(mbytes)
+		wsSend, err := c.forwardRequest(mbytes)
 		if err != nil {
-			logger.Warn("failed to write message to buffer", "error", err)
+			logger.Warn("websocket error calling rpc", "error", err)
 			return
 		}
 
-		req, err := http.NewRequest("POST", rpcHost, buf)
+		err = c.safeSend(wsSend)
 		if err != nil {
-			logger.Warn("failed request to rpc service", "error", err)
+			logger.Warn("error writing json response", "error", err)
 			return
 		}
+	}
+}
 
-		req.Header.Set("Content-Type", "application/json;")
+// forwardRequest sends the request to the http rpc server and returns the decoded response
+func (c *WSConn) forwardRequest(mbytes []byte) (interface{}, error) {
+	client := &http.Client{}
+	req, err := http.NewRequest("POST", rpcHost, bytes.NewReader(mbytes))
+	if err != nil {
+		return nil, err
+	}
 
-		res, err := client.Do(req)
-		if err != nil {
-			logger.Warn("websocket error calling rpc", "error", err)
-			return
-		}
+	req.Header.Set("Content-Type", "application/json;")
+	if c.authHeader != "" {
+		req.Header.Set("Authorization", c.authHeader)
+	}
 
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			logger.Warn("error reading response body", "error", err)
-			return
-		}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
 
-		err = res.Body.Close()
-		if err != nil {
-			logger.Warn("error closing response body", "error", err)
-			return
-		}
-		var wsSend interface{}
-		err = json.Unmarshal(body, &wsSend)
-		if err != nil {
-			logger.Warn("error unmarshal rpc response", "error", err)
-			return
-		}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
 
-		err = c.safeSend(wsSend)
-		if err != nil {
-			logger.Warn("error writing json response", "error", err)
-			return
-		}
+	err = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var wsSend interface{}
+	err = json.Unmarshal(body, &wsSend)
+	if err != nil {
+		return nil, err
 	}
+
+	return wsSend, nil
 }
 func (c *WSConn) startListener(lid int) {
-	go c.subscriptions[lid].Listen()
+	c.subscriptionsLock.RLock()
+	defer c.subscriptionsLock.RUnlock()
+
+	// the subscription may have already ended, such as an extrinsic watch for an extrinsic that was dropped
+	if l, has := c.subscriptions[lid]; has {
+		go l.Listen()
+	}
 }
 
 // Listener interface for functions that define Listener related functions
@@ -246,23 +520,34 @@ type StorageChangeListener struct {
 	subID   int
 }
 
-func (c *WSConn) initStorageChangeListener(reqID float64, params interface{}) (int, error) {
-	scl := &StorageChangeListener{
-		channel: make(chan *state.KeyValue),
-		filter:  make(map[string]bool),
-		wsconn:  c,
-	}
+// parseStorageFilter returns the set of hex-encoded storage keys in the parameters of a storage subscription
+func parseStorageFilter(params interface{}) (map[string]bool, error) {
+	filter := make(map[string]bool)
 	pA := params.([]interface{})
 	for _, param := range pA {
 		switch param.(type) {
 		case []interface{}:
 			for _, p := range param.([]interface{}) {
-				scl.filter[p.(string)] = true
+				filter[p.(string)] = true
 			}
 		default:
-			return 0, fmt.Errorf("unknow parameter type")
+			return nil, fmt.Errorf("unknow parameter type")
 		}
 	}
+	return filter, nil
+}
+
+func (c *WSConn) initStorageChangeListener(reqID float64, params interface{}) (int, error) {
+	filter, err := parseStorageFilter(params)
+	if err != nil {
+		return 0, err
+	}
+
+	scl := &StorageChangeListener{
+		channel: make(chan *state.KeyValue),
+		filter:  filter,
+		wsconn:  c,
+	}
 	if c.storageAPI == nil {
 		err := c.safeSendError(reqID, nil, "error StorageAPI not set")
 		if err != nil {
@@ -275,10 +560,7 @@ func (c *WSConn) initStorageChangeListener(reqID float64, params interface{}) (i
 		return 0, err
 	}
 	scl.chanID = chanID
-
-	c.qtyListeners++
-	scl.subID = c.qtyListeners
-	c.subscriptions[scl.subID] = scl
+	scl.subID = c.addSubscription(scl)
 	c.storageSubChannels[scl.subID] = chanID
 
 	initRes := newSubscriptionResponseJSON(scl.subID, reqID)
@@ -342,9 +624,7 @@ func (c *WSConn) initBlockListener(reqID float64) (int, error) {
 		return 0, err
 	}
 	bl.chanID = chanID
-	c.qtyListeners++
-	bl.subID = c.qtyListeners
-	c.subscriptions[bl.subID] = bl
+	bl.subID = c.addSubscription(bl)
 	c.blockSubChannels[bl.subID] = chanID
 	initRes := newSubscriptionResponseJSON(bl.subID, reqID)
 	err = c.safeSend(initRes)
@@ -360,14 +640,18 @@ func (l *BlockListener) Listen() {
 		if block == nil {
 			continue
 		}
-		head := modules.HeaderToJSON(*block.Header)
+		head, err := modules.HeaderToJSON(*block.Header)
+		if err != nil {
+			logger.Error("failed to convert header to JSON", "error", err)
+			continue
+		}
 		headM := make(map[string]interface{})
 		headM["result"] = head
 		headM["subscription"] = l.subID
 		res := newSubcriptionBaseResponseJSON()
 		res.Method = "chain_newHead"
 		res.Params = headM
-		err := l.wsconn.safeSend(res)
+		err = l.wsconn.safeSend(res)
 		if err != nil {
 			logger.Error("error sending websocket message", "error", err)
 		}
@@ -381,12 +665,14 @@ type BlockFinalizedListener struct {
 	wsconn  *WSConn
 	chanID  byte
 	subID   int
+	method  string // method of the notifications sent to the subscriber
 }
 
-func (c *WSConn) initBlockFinalizedListener(reqID float64) (int, error) {
+func (c *WSConn) initBlockFinalizedListener(reqID float64, method string) (int, error) {
 	bfl := &BlockFinalizedListener{
 		channel: make(chan *types.Header),
 		wsconn:  c,
+		method:  method,
 	}
 
 	if c.blockAPI == nil {
@@ -401,9 +687,7 @@ func (c *WSConn) initBlockFinalizedListener(reqID float64) (int, error) {
 		return 0, err
 	}
 	bfl.chanID = chanID
-	c.qtyListeners++
-	bfl.subID = c.qtyListeners
-	c.subscriptions[bfl.subID] = bfl
+	bfl.subID = c.addSubscription(bfl)
 	c.blockSubChannels[bfl.subID] = chanID
 	initRes := newSubscriptionResponseJSON(bfl.subID, reqID)
 	err = c.safeSend(initRes)
@@ -419,16 +703,796 @@ func (l *BlockFinalizedListener) Listen() {
 		if header == nil {
 			continue
 		}
-		head := modules.HeaderToJSON(*header)
+		head, err := modules.HeaderToJSON(*header)
+		if err != nil {
+			logger.Error("failed to convert header to JSON", "error", err)
+			continue
+		}
 		headM := make(map[string]interface{})
 		headM["result"] = head
 		headM["subscription"] = l.subID
 		res := newSubcriptionBaseResponseJSON()
-		res.Method = "chain_finalizedHead"
+		res.Method = l.method
 		res.Params = headM
-		err := l.wsconn.safeSend(res)
+		err = l.wsconn.safeSend(res)
 		if err != nil {
 			logger.Error("error sending websocket message", "error", err)
 		}
 	}
 }
+
+// FinalizedStorageChangeListener listens for finalized blocks and sends the storage values that changed since the
+// previously finalized block
+type FinalizedStorageChangeListener struct {
+	channel chan *types.Header
+	filter  map[string]bool
+	values  map[string]string // last values sent to the subscriber, by key
+	last    *common.Hash      // hash of the block the changes are diffed from, if the whole state is watched
+	wsconn  *WSConn
+	chanID  byte
+	subID   int
+}
+
+func (c *WSConn) initFinalizedStorageChangeListener(reqID float64, params interface{}) (int, error) {
+	filter, err := parseStorageFilter(params)
+	if err != nil {
+		return 0, err
+	}
+
+	fscl := &FinalizedStorageChangeListener{
+		channel: make(chan *types.Header),
+		filter:  filter,
+		values:  make(map[string]string),
+		wsconn:  c,
+	}
+
+	if c.storageAPI == nil || c.blockAPI == nil {
+		err = c.safeSendError(reqID, nil, "error StorageAPI or BlockAPI not set")
+		if err != nil {
+			logger.Warn("error sending error message", "error", err)
+		}
+		return 0, fmt.Errorf("error StorageAPI or BlockAPI not set")
+	}
+	// the changes to the whole state are diffed from the block finalized when subscribing
+	if len(filter) == 0 {
+		finalized, ferr := c.blockAPI.GetFinalizedHash(0, 0)
+		if ferr == nil {
+			fscl.last = &finalized
+		}
+	}
+
+	chanID, err := c.blockAPI.RegisterFinalizedChannel(fscl.channel)
+	if err != nil {
+		return 0, err
+	}
+	fscl.chanID = chanID
+	fscl.subID = c.addSubscription(fscl)
+	c.blockSubChannels[fscl.subID] = chanID
+
+	initRes := newSubscriptionResponseJSON(fscl.subID, reqID)
+	err = c.safeSend(initRes)
+	if err != nil {
+		return 0, err
+	}
+	return fscl.subID, nil
+}
+
+// Listen implementation of Listen interface to listen for channel changes
+func (l *FinalizedStorageChangeListener) Listen() {
+	for header := range l.channel {
+		if header == nil {
+			continue
+		}
+
+		changes, err := l.changes(header)
+		if err != nil {
+			logger.Warn("failed to get storage changes of finalized block", "block", header.Hash(), "error", err)
+			continue
+		}
+
+		for _, change := range changes {
+			changeM := make(map[string]interface{})
+			changeM["result"] = change
+			changeM["subscription"] = l.subID
+			res := newSubcriptionBaseResponseJSON()
+			res.Method = "state_storage"
+			res.Params = changeM
+			err = l.wsconn.safeSend(res)
+			if err != nil {
+				logger.Error("error sending websocket message", "error", err)
+			}
+		}
+	}
+}
+
+// changes returns the [key, value] pairs that changed between the previously seen block and the given block
+func (l *FinalizedStorageChangeListener) changes(header *types.Header) ([][]string, error) {
+	if len(l.filter) == 0 {
+		return l.stateChanges(header)
+	}
+
+	current := make(map[string]string)
+	for key := range l.filter {
+		k, err := common.HexToBytes(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := l.wsconn.storageAPI.GetStorage(&header.StateRoot, k)
+		if err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			current[key] = common.BytesToHex(v)
+		}
+	}
+
+	var changes [][]string
+	for k, v := range current {
+		if prev, has := l.values[k]; !has || prev != v {
+			changes = append(changes, []string{k, v})
+		}
+	}
+
+	// keys that were deleted are sent with an empty value
+	for k := range l.values {
+		if _, has := current[k]; !has {
+			changes = append(changes, []string{k, "0x"})
+		}
+	}
+
+	l.values = current
+	return changes, nil
+}
+
+// stateChanges returns the [key, value] pairs of the whole state that changed between the previously seen block and
+// the given block. The state isn't sent in full, if no block was seen yet the given block is only recorded and the
+// changes are sent from the next one.
+func (l *FinalizedStorageChangeListener) stateChanges(header *types.Header) ([][]string, error) {
+	hash := header.Hash()
+	if l.last == nil {
+		l.last = &hash
+		return nil, nil
+	}
+
+	diff, err := l.wsconn.storageAPI.StorageDiff(*l.last, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys that were deleted are sent with an empty value
+	var changes [][]string
+	for _, change := range diff {
+		changes = append(changes, []string{common.BytesToHex(change.Key), common.BytesToHex(change.New)})
+	}
+
+	l.last = &hash
+	return changes, nil
+}
+
+// watchBufferSize is the number of blocks buffered for extrinsic watches. The block state drops notifications
+// that a subscriber isn't ready to receive, so they are buffered while a watch is sending a status.
+const watchBufferSize = 64
+
+// maxExtrinsicWatches is the maximum number of extrinsic watches a connection may have at once
+const maxExtrinsicWatches = 128
+
+var errTooManyWatches = errors.New("too many extrinsic watches")
+
+// extrinsicWatches holds the extrinsic watches of a connection, which share one imported and one finalized block
+// channel, and one pool event channel. The channels are registered when the first watch is added and unregistered
+// when the last one ends, and their notifications are passed on to the watches.
+type extrinsicWatches struct {
+	lock        sync.Mutex
+	watches     map[*ExtrinsicWatchListener]struct{}
+	importedID  byte
+	finalizedID byte
+	eventsID    byte
+	flush       chan chan struct{}
+	done        chan struct{}
+}
+
+func newExtrinsicWatches() *extrinsicWatches {
+	return &extrinsicWatches{
+		watches: make(map[*ExtrinsicWatchListener]struct{}),
+	}
+}
+
+// count returns the number of extrinsic watches
+func (w *extrinsicWatches) count() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return len(w.watches)
+}
+
+// addWatch adds the extrinsic watch to the connection's watches, registering the shared channels if it's the first
+func (c *WSConn) addWatch(el *ExtrinsicWatchListener) error {
+	w := c.watches
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.watches) >= maxExtrinsicWatches {
+		return errTooManyWatches
+	}
+
+	if len(w.watches) == 0 {
+		imported := make(chan *types.Block, watchBufferSize)
+		importedID, err := c.blockAPI.RegisterImportedChannel(imported)
+		if err != nil {
+			return err
+		}
+
+		finalized := make(chan *types.Header, watchBufferSize)
+		finalizedID, err := c.blockAPI.RegisterFinalizedChannel(finalized)
+		if err != nil {
+			c.blockAPI.UnregisterImportedChannel(importedID)
+			return err
+		}
+
+		// events stays nil if the transaction state isn't set, so that the feed never receives from it
+		var events chan *transaction.PoolEvent
+		if c.txStateAPI != nil {
+			events = make(chan *transaction.PoolEvent, poolEventBufferSize)
+			w.eventsID, err = c.txStateAPI.RegisterPoolEventChannel(events)
+			if err != nil {
+				c.blockAPI.UnregisterImportedChannel(importedID)
+				c.blockAPI.UnregisterFinalizedChannel(finalizedID)
+				return err
+			}
+		}
+
+		w.importedID = importedID
+		w.finalizedID = finalizedID
+		w.flush = make(chan chan struct{})
+		w.done = make(chan struct{})
+		go w.feed(imported, finalized, events, w.flush, w.done)
+	}
+
+	w.watches[el] = struct{}{}
+	return nil
+}
+
+// removeWatch removes the extrinsic watch from the connection's watches, unregistering the shared channels if it
+// was the last
+func (c *WSConn) removeWatch(el *ExtrinsicWatchListener) {
+	w := c.watches
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, has := w.watches[el]; !has {
+		return
+	}
+
+	delete(w.watches, el)
+	if len(w.watches) > 0 {
+		return
+	}
+
+	c.blockAPI.UnregisterImportedChannel(w.importedID)
+	c.blockAPI.UnregisterFinalizedChannel(w.finalizedID)
+	if c.txStateAPI != nil {
+		c.txStateAPI.UnregisterPoolEventChannel(w.eventsID)
+	}
+	close(w.done)
+}
+
+// list returns the current extrinsic watches
+func (w *extrinsicWatches) list() []*ExtrinsicWatchListener {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	list := make([]*ExtrinsicWatchListener, 0, len(w.watches))
+	for el := range w.watches {
+		list = append(list, el)
+	}
+	return list
+}
+
+// sync waits until the pool events that were sent before it was called are passed on to the watches
+func (w *extrinsicWatches) sync() {
+	w.lock.Lock()
+	flush, done := w.flush, w.done
+	w.lock.Unlock()
+
+	if flush == nil {
+		return
+	}
+
+	reply := make(chan struct{})
+	select {
+	case flush <- reply:
+	case <-done:
+		return
+	}
+
+	select {
+	case <-reply:
+	case <-done:
+	}
+}
+
+// feed passes the notifications received on the shared channels on to the watches until done is closed. Pool
+// events are only passed on to the watches of the extrinsic they are for.
+func (w *extrinsicWatches) feed(imported <-chan *types.Block, finalized <-chan *types.Header,
+	events <-chan *transaction.PoolEvent, flush <-chan chan struct{}, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case block := <-imported:
+			for _, el := range w.list() {
+				select {
+				case el.importedChan <- block:
+				case <-el.done:
+				}
+			}
+		case header := <-finalized:
+			for _, el := range w.list() {
+				select {
+				case el.finalizedChan <- header:
+				case <-el.done:
+				}
+			}
+		case ev := <-events:
+			w.dispatchPoolEvent(ev)
+		case reply := <-flush:
+			for drained := false; !drained; {
+				select {
+				case ev := <-events:
+					w.dispatchPoolEvent(ev)
+				default:
+					drained = true
+				}
+			}
+			close(reply)
+		}
+	}
+}
+
+func (w *extrinsicWatches) dispatchPoolEvent(ev *transaction.PoolEvent) {
+	if ev == nil {
+		return
+	}
+
+	for _, el := range w.list() {
+		if el.hash != ev.Hash || el.eventsChan == nil {
+			continue
+		}
+
+		select {
+		case el.eventsChan <- ev:
+		case <-el.done:
+		}
+	}
+}
+
+// ExtrinsicWatchListener sends the status of a submitted extrinsic as it moves through the transaction pool, is
+// included in a block and finalized, until it reaches a final status, is unwatched or the connection is closed
+type ExtrinsicWatchListener struct {
+	importedChan  chan *types.Block
+	finalizedChan chan *types.Header
+	eventsChan    chan *transaction.PoolEvent // nil if the transaction state isn't set
+	ext           types.Extrinsic
+	hash          common.Hash
+	inBlock       *types.Header // block the extrinsic was included in, if any
+	status        string        // last pool status sent, so that it isn't sent repeatedly
+	wsconn        *WSConn
+	subID         int
+	done          chan struct{}
+}
+
+// initExtrinsicWatch submits the extrinsic in the request parameters and subscribes to its status
+func (c *WSConn) initExtrinsicWatch(reqID float64, params interface{}) (int, error) {
+	pA, ok := params.([]interface{})
+	if !ok || len(pA) == 0 {
+		return 0, c.sendInvalidParams(reqID, "expected extrinsic parameter")
+	}
+
+	extHex, ok := pA[0].(string)
+	if !ok {
+		return 0, c.sendInvalidParams(reqID, "expected hex-encoded extrinsic")
+	}
+
+	ext, err := common.HexToBytes(extHex)
+	if err != nil {
+		return 0, c.sendInvalidParams(reqID, err.Error())
+	}
+
+	if c.blockAPI == nil {
+		err = c.safeSendError(reqID, nil, "error BlockAPI not set")
+		if err != nil {
+			logger.Warn("error sending error message", "error", err)
+		}
+		return 0, fmt.Errorf("error BlockAPI not set")
+	}
+
+	// the listener is registered before the extrinsic is submitted, so that it doesn't miss the extrinsic
+	// entering the pool
+	el, err := c.newExtrinsicWatch(ext)
+	if err != nil {
+		return 0, err
+	}
+
+	// the extrinsic is submitted through the rpc server, so that it is validated like any other submission
+	submit, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "author_submitExtrinsic",
+		"params":  []string{extHex},
+		"id":      reqID,
+	})
+	if err != nil {
+		el.unregister()
+		return 0, err
+	}
+
+	res, err := c.forwardRequest(submit)
+	if err != nil {
+		el.unregister()
+		return 0, err
+	}
+
+	if resM, ok := res.(map[string]interface{}); !ok || resM["error"] != nil {
+		el.unregister()
+		err = c.safeSend(res)
+		if err != nil {
+			logger.Warn("error sending error message", "error", err)
+		}
+		return 0, fmt.Errorf("failed to submit extrinsic")
+	}
+
+	el.subID = c.addSubscription(el)
+	err = c.safeSend(newSubscriptionResponseJSON(el.subID, reqID))
+	if err != nil {
+		return 0, err
+	}
+
+	if el.sendSubmittedStatus() {
+		c.unwatchExtrinsic(el.subID)
+	}
+	return el.subID, nil
+}
+
+// addExtrinsicWatch registers a listener for the status of the given extrinsic
+func (c *WSConn) addExtrinsicWatch(ext types.Extrinsic) (*ExtrinsicWatchListener, error) {
+	el, err := c.newExtrinsicWatch(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	el.subID = c.addSubscription(el)
+	return el, nil
+}
+
+// newExtrinsicWatch returns a listener for the status of the given extrinsic with its channels registered, but
+// without adding it to the connection's subscriptions
+func (c *WSConn) newExtrinsicWatch(ext types.Extrinsic) (*ExtrinsicWatchListener, error) {
+	el := &ExtrinsicWatchListener{
+		importedChan:  make(chan *types.Block, watchBufferSize),
+		finalizedChan: make(chan *types.Header, watchBufferSize),
+		ext:           ext,
+		hash:          ext.Hash(),
+		wsconn:        c,
+		done:          make(chan struct{}),
+	}
+
+	if c.txStateAPI != nil {
+		el.eventsChan = make(chan *transaction.PoolEvent, poolEventBufferSize)
+	}
+
+	err := c.addWatch(el)
+	if err != nil {
+		return nil, err
+	}
+
+	return el, nil
+}
+
+// unwatchExtrinsic cancels the extrinsic watch with the given subscription ID, it returns false if there is none
+func (c *WSConn) unwatchExtrinsic(subID int) bool {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	el, ok := c.subscriptions[subID].(*ExtrinsicWatchListener)
+	if !ok {
+		return false
+	}
+
+	el.stop()
+	delete(c.subscriptions, subID)
+	return true
+}
+
+func (c *WSConn) sendInvalidParams(reqID float64, message string) error {
+	err := c.safeSendError(reqID, big.NewInt(-32602), message)
+	if err != nil {
+		logger.Warn("error sending error message", "error", err)
+	}
+	return errors.New(message)
+}
+
+// parseSubscriptionID returns the subscription ID in the parameters of an unsubscription request
+func parseSubscriptionID(params interface{}) (int, bool) {
+	pA, ok := params.([]interface{})
+	if !ok || len(pA) == 0 {
+		return 0, false
+	}
+
+	switch id := pA[0].(type) {
+	case float64:
+		return int(id), true
+	case string:
+		subID, err := strconv.Atoi(id)
+		return subID, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// stop unregisters the listener's channels and ends its Listen goroutine. The notification channels aren't
+// closed, since the block feed and transaction state may still be sending on them.
+func (l *ExtrinsicWatchListener) stop() {
+	l.unregister()
+	close(l.done)
+}
+
+func (l *ExtrinsicWatchListener) unregister() {
+	l.wsconn.removeWatch(l)
+}
+
+// sendSubmittedStatus sends the statuses of the pool events received while the extrinsic was submitted. A node
+// that doesn't produce blocks doesn't add submitted extrinsics to its pool, so they are ready once submitted. It
+// returns true if the extrinsic already reached a final status.
+func (l *ExtrinsicWatchListener) sendSubmittedStatus() bool {
+	l.wsconn.watches.sync()
+
+	for {
+		select {
+		case ev := <-l.eventsChan:
+			if l.handlePoolEvent(ev) {
+				return true
+			}
+		default:
+			if l.status == "" {
+				l.sendPoolStatus("ready")
+			}
+			return false
+		}
+	}
+}
+
+// handlePoolEvent sends the status of the extrinsic for a transaction pool event, it returns true if the status is
+// final. Inclusion in blocks is tracked with the imported blocks instead, so that blocks from peers are seen too.
+func (l *ExtrinsicWatchListener) handlePoolEvent(ev *transaction.PoolEvent) bool {
+	if ev == nil || ev.Hash != l.hash {
+		return false
+	}
+
+	switch ev.Type {
+	case transaction.PoolEventFuture:
+		l.sendPoolStatus("future")
+	case transaction.PoolEventImported, transaction.PoolEventReady, transaction.PoolEventFuturePromoted:
+		l.sendPoolStatus("ready")
+	case transaction.PoolEventBroadcast:
+		if l.status == "" {
+			l.sendPoolStatus("ready")
+		}
+		// the peers the extrinsic was sent to aren't known
+		l.sendStatus(map[string][]string{"broadcast": {}})
+	case transaction.PoolEventDropped:
+		l.sendPoolStatus("dropped")
+		return true
+	case transaction.PoolEventBanned:
+		l.sendPoolStatus("invalid")
+		return true
+	}
+
+	return false
+}
+
+// sendPoolStatus sends the given status unless it was the last pool status sent
+func (l *ExtrinsicWatchListener) sendPoolStatus(status string) {
+	if l.status == status {
+		return
+	}
+
+	l.status = status
+	l.sendStatus(status)
+}
+
+// Listen implementation of Listen interface to listen for channel changes
+func (l *ExtrinsicWatchListener) Listen() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case ev := <-l.eventsChan:
+			if l.handlePoolEvent(ev) {
+				l.wsconn.unwatchExtrinsic(l.subID)
+				return
+			}
+		case block := <-l.importedChan:
+			if block == nil {
+				continue
+			}
+
+			if l.included(block) {
+				l.inBlock = block.Header
+				l.sendStatus(map[string]string{"inBlock": block.Header.Hash().String()})
+				continue
+			}
+
+			if l.retracted() {
+				l.sendStatus(map[string]string{"retracted": l.inBlock.Hash().String()})
+				l.inBlock = nil
+			}
+		case header := <-l.finalizedChan:
+			if header == nil || l.inBlock == nil || header.Number.Cmp(l.inBlock.Number) < 0 {
+				continue
+			}
+
+			// the block is finalized if it is on the chain of the finalized block
+			hash, err := l.wsconn.blockAPI.GetBlockHash(l.inBlock.Number)
+			if err != nil || hash == nil || *hash != l.inBlock.Hash() {
+				continue
+			}
+
+			l.sendStatus(map[string]string{"finalized": hash.String()})
+			l.wsconn.unwatchExtrinsic(l.subID)
+			return
+		}
+	}
+}
+
+// retracted returns true if the extrinsic was included in a block that is no longer on the best chain
+func (l *ExtrinsicWatchListener) retracted() bool {
+	if l.inBlock == nil {
+		return false
+	}
+
+	hash := l.inBlock.Hash()
+	best := l.wsconn.blockAPI.BestBlockHash()
+	if best == hash {
+		return false
+	}
+
+	isDescendant, err := l.wsconn.blockAPI.IsDescendantOf(hash, best)
+	return err == nil && !isDescendant
+}
+
+func (l *ExtrinsicWatchListener) included(block *types.Block) bool {
+	if block.Body == nil {
+		return false
+	}
+
+	exts, err := block.Body.AsExtrinsics()
+	if err != nil {
+		return false
+	}
+
+	for _, ext := range exts {
+		if bytes.Equal(ext, l.ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *ExtrinsicWatchListener) sendStatus(status interface{}) {
+	statusM := make(map[string]interface{})
+	statusM["result"] = status
+	statusM["subscription"] = l.subID
+	res := newSubcriptionBaseResponseJSON()
+	res.Method = "author_extrinsicUpdate"
+	res.Params = statusM
+	err := l.wsconn.safeSend(res)
+	if err != nil {
+		logger.Error("error sending websocket message", "error", err)
+	}
+}
+
+// poolEventBufferSize is the number of pool events buffered for a subscriber, further events are dropped until
+// the subscriber catches up
+const poolEventBufferSize = 256
+
+// PoolEventJSON is a transaction pool event sent to pool event subscribers
+type PoolEventJSON struct {
+	Type  string `json:"type"`
+	Hash  string `json:"hash"`
+	Block string `json:"block,omitempty"`
+}
+
+// PoolEventListener sends the transaction pool events until it is unsubscribed or the connection is closed
+type PoolEventListener struct {
+	channel chan *transaction.PoolEvent
+	wsconn  *WSConn
+	chanID  byte
+	subID   int
+	done    chan struct{}
+}
+
+func (c *WSConn) initPoolEventListener(reqID float64) (int, error) {
+	if c.txStateAPI == nil {
+		err := c.safeSendError(reqID, nil, "error TransactionStateAPI not set")
+		if err != nil {
+			logger.Warn("error sending error message", "error", err)
+		}
+		return 0, fmt.Errorf("error TransactionStateAPI not set")
+	}
+
+	pel := &PoolEventListener{
+		channel: make(chan *transaction.PoolEvent, poolEventBufferSize),
+		wsconn:  c,
+		done:    make(chan struct{}),
+	}
+
+	chanID, err := c.txStateAPI.RegisterPoolEventChannel(pel.channel)
+	if err != nil {
+		return 0, err
+	}
+	pel.chanID = chanID
+	pel.subID = c.addSubscription(pel)
+
+	err = c.safeSend(newSubscriptionResponseJSON(pel.subID, reqID))
+	if err != nil {
+		return 0, err
+	}
+	return pel.subID, nil
+}
+
+// unsubscribePoolEvents cancels the pool event subscription with the given ID, it returns false if there is none
+func (c *WSConn) unsubscribePoolEvents(subID int) bool {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	pel, ok := c.subscriptions[subID].(*PoolEventListener)
+	if !ok {
+		return false
+	}
+
+	pel.stop()
+	delete(c.subscriptions, subID)
+	return true
+}
+
+// stop unregisters the listener's channel and ends its Listen goroutine. The channel isn't closed, since the
+// transaction state may still be sending on it.
+func (l *PoolEventListener) stop() {
+	l.wsconn.txStateAPI.UnregisterPoolEventChannel(l.chanID)
+	close(l.done)
+}
+
+// Listen implementation of Listen interface to listen for channel changes
+func (l *PoolEventListener) Listen() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case ev := <-l.channel:
+			if ev == nil {
+				continue
+			}
+
+			event := PoolEventJSON{
+				Type: string(ev.Type),
+				Hash: ev.Hash.String(),
+			}
+			if ev.Type == transaction.PoolEventInBlock {
+				event.Block = ev.Block.String()
+			}
+
+			eventM := make(map[string]interface{})
+			eventM["result"] = event
+			eventM["subscription"] = l.subID
+			res := newSubcriptionBaseResponseJSON()
+			res.Method = "author_poolEvent"
+			res.Params = eventM
+			err := l.wsconn.safeSend(res)
+			if err != nil {
+				logger.Error("error sending websocket message", "error", err)
+			}
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package rpc
 
 import (
+	"bytes"
 	"flag"
+	"fmt"
 	"log"
 	"math/big"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +18,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot/system"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +34,7 @@ var testCalls = []struct {
 	{[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":3}`), []byte(`{"jsonrpc":"2.0","result":1,"id":3}` + "\n")},
 	{[]byte(`{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":4}`), []byte(`{"jsonrpc":"2.0","result":2,"id":4}` + "\n")},
 	{[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeFinalizedHeads","params":[],"id":5}`), []byte(`{"jsonrpc":"2.0","result":3,"id":5}` + "\n")},
+	{[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":"6"}`), []byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":0}` + "\n")}, // non-numeric subscription id
 }
 
 func TestHTTPServer_ServeHTTP(t *testing.T) {
@@ -75,6 +82,66 @@ func TestHTTPServer_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestHTTPServer_ServeHTTP_MaxSubscriptions(t *testing.T) {
+	cfg := &HTTPServerConfig{
+		Modules:            []string{"system", "chain"},
+		RPCPort:            8555,
+		WSPort:             8556,
+		WSEnabled:          true,
+		WSMaxSubscriptions: 1,
+		RPCAPI:             NewService(),
+		BlockAPI:           new(MockBlockAPI),
+		StorageAPI:         new(MockStorageAPI),
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.Nil(t, err)
+
+	time.Sleep(time.Second) // give server a second to start
+
+	u := url.URL{Scheme: "ws", Host: "localhost:8556", Path: "/"}
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	require.NoError(t, err)
+
+	calls := []struct {
+		call     []byte
+		expected []byte
+	}{
+		{[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":3}`), []byte(`{"jsonrpc":"2.0","result":1,"id":3}` + "\n")},
+		{[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeFinalizedHeads","params":[],"id":4}`), []byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"Too many subscriptions"},"id":4}` + "\n")},
+	}
+
+	for _, item := range calls {
+		err = c.WriteMessage(websocket.TextMessage, item.call)
+		require.Nil(t, err)
+
+		_, message, err := c.ReadMessage()
+		require.Nil(t, err)
+		require.Equal(t, item.expected, message)
+	}
+
+	require.Equal(t, 1, s.ActiveWSConnections())
+	require.Equal(t, 1, s.ActiveSubscriptions())
+	require.Equal(t, uint64(wsConnSize+subscriptionSize), s.MemoryUsage())
+
+	rec := httptest.NewRecorder()
+	s.metricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, "gossamer_rpc_ws_connections 1\n"))
+	require.True(t, strings.Contains(body, "gossamer_rpc_ws_subscriptions 1\n"))
+	require.True(t, strings.Contains(body, "gossamer_rpc_ws_subscriptions_rejected_total 1\n"))
+
+	// subscriptions are released once the client disconnects
+	err = c.Close()
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond * 100)
+
+	require.Equal(t, 0, s.ActiveWSConnections())
+	require.Equal(t, 0, s.ActiveSubscriptions())
+	require.Equal(t, uint64(0), s.MemoryUsage())
+}
+
 type MockBlockAPI struct {
 }
 
@@ -90,9 +157,18 @@ func (m *MockBlockAPI) GetBlockByHash(hash common.Hash) (*types.Block, error) {
 func (m *MockBlockAPI) GetBlockHash(blockNumber *big.Int) (*common.Hash, error) {
 	return nil, nil
 }
+func (m *MockBlockAPI) IsDescendantOf(parent, child common.Hash) (bool, error) {
+	return false, nil
+}
 func (m *MockBlockAPI) GetFinalizedHash(uint64, uint64) (common.Hash, error) {
 	return common.Hash{}, nil
 }
+func (m *MockBlockAPI) HasJustification(hash common.Hash) (bool, error) {
+	return false, nil
+}
+func (m *MockBlockAPI) GetJustification(hash common.Hash) ([]byte, error) {
+	return nil, nil
+}
 func (m *MockBlockAPI) RegisterImportedChannel(ch chan<- *types.Block) (byte, error) {
 	return 0, nil
 }
@@ -102,6 +178,30 @@ func (m *MockBlockAPI) RegisterFinalizedChannel(ch chan<- *types.Header) (byte,
 	return 0, nil
 }
 func (m *MockBlockAPI) UnregisterFinalizedChannel(id byte) {}
+func (m *MockBlockAPI) MarkBadBlock(hash common.Hash) error {
+	return nil
+}
+func (m *MockBlockAPI) BadBlocks() []common.Hash {
+	return nil
+}
+func (m *MockBlockAPI) ForceBestBlock(hash common.Hash) error {
+	return nil
+}
+func (m *MockBlockAPI) ForcedBestBlock() common.Hash {
+	return common.Hash{}
+}
+func (m *MockBlockAPI) SetHaltAtBlock(num *big.Int) error {
+	return nil
+}
+func (m *MockBlockAPI) HaltAtBlock() *big.Int {
+	return nil
+}
+func (m *MockBlockAPI) GetExtrinsicInclusions(hash common.Hash) ([]*state.ExtrinsicInclusion, error) {
+	return nil, nil
+}
+func (m *MockBlockAPI) GetHeaderEncoding(hash common.Hash) ([]byte, error) {
+	return nil, nil
+}
 
 type MockStorageAPI struct{}
 
@@ -120,3 +220,501 @@ func (m *MockStorageAPI) RegisterStorageChangeChannel(ch chan<- *state.KeyValue)
 func (m *MockStorageAPI) UnregisterStorageChangeChannel(id byte) {
 
 }
+func (m *MockStorageAPI) InsertStorage(entries []*state.KeyValue) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+func (m *MockStorageAPI) GetKeysPaged(bhash *common.Hash, prefix, startKey []byte, count uint32) (common.Hash, [][]byte, error) {
+	return common.Hash{}, nil, nil
+}
+func (m *MockStorageAPI) StorageDiff(from, to common.Hash, prefix []byte) ([]*state.StorageChange, error) {
+	return nil, nil
+}
+
+type mockRootStorageAPI struct {
+	MockStorageAPI
+	entries map[common.Hash]map[string][]byte
+	roots   map[common.Hash]common.Hash // state roots by block hash
+}
+
+func (m *mockRootStorageAPI) GetStorage(root *common.Hash, key []byte) ([]byte, error) {
+	return m.entries[*root][string(key)], nil
+}
+func (m *mockRootStorageAPI) StorageDiff(from, to common.Hash, prefix []byte) ([]*state.StorageChange, error) {
+	fromEntries, toEntries := m.entries[m.roots[from]], m.entries[m.roots[to]]
+
+	var changes []*state.StorageChange
+	for k, v := range toEntries {
+		if old, has := fromEntries[k]; !has || !bytes.Equal(old, v) {
+			changes = append(changes, &state.StorageChange{Key: []byte(k), Old: old, New: v})
+		}
+	}
+	for k, v := range fromEntries {
+		if _, has := toEntries[k]; !has {
+			changes = append(changes, &state.StorageChange{Key: []byte(k), Old: v})
+		}
+	}
+	return changes, nil
+}
+
+func TestFinalizedStorageChangeListener_Changes(t *testing.T) {
+	headerA := &types.Header{Number: big.NewInt(1), StateRoot: common.Hash{0xa}}
+	headerB := &types.Header{Number: big.NewInt(2), StateRoot: common.Hash{0xb}}
+	sAPI := &mockRootStorageAPI{
+		entries: map[common.Hash]map[string][]byte{
+			headerA.StateRoot: {"a": {1}, "b": {2}},
+			headerB.StateRoot: {"a": {1}, "c": {3}},
+		},
+		roots: map[common.Hash]common.Hash{
+			headerA.Hash(): headerA.StateRoot,
+			headerB.Hash(): headerB.StateRoot,
+		},
+	}
+
+	l := &FinalizedStorageChangeListener{
+		filter: make(map[string]bool),
+		values: make(map[string]string),
+		wsconn: &WSConn{storageAPI: sAPI},
+	}
+
+	// the first block's state isn't sent, only the changes from it
+	changes, err := l.changes(headerA)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	changes, err = l.changes(headerB)
+	require.NoError(t, err)
+	require.ElementsMatch(t, [][]string{{"0x63", "0x03"}, {"0x62", "0x"}}, changes)
+
+	// a subscription diffs the first block from the block finalized when subscribing
+	hashA := headerA.Hash()
+	l = &FinalizedStorageChangeListener{
+		filter: make(map[string]bool),
+		values: make(map[string]string),
+		last:   &hashA,
+		wsconn: &WSConn{storageAPI: sAPI},
+	}
+
+	changes, err = l.changes(headerB)
+	require.NoError(t, err)
+	require.ElementsMatch(t, [][]string{{"0x63", "0x03"}, {"0x62", "0x"}}, changes)
+
+	l = &FinalizedStorageChangeListener{
+		filter: map[string]bool{"0x62": true},
+		values: make(map[string]string),
+		wsconn: &WSConn{storageAPI: sAPI},
+	}
+
+	changes, err = l.changes(headerA)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"0x62", "0x02"}}, changes)
+
+	changes, err = l.changes(headerB)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"0x62", "0x"}}, changes)
+}
+
+func TestWSConn_UnwatchExtrinsic(t *testing.T) {
+	c := NewWSConn(nil, &HTTPServerConfig{BlockAPI: new(MockBlockAPI)})
+
+	el, err := c.addExtrinsicWatch(types.Extrinsic{1, 2, 3})
+	require.NoError(t, err)
+
+	exited := make(chan struct{})
+	go func() {
+		el.Listen()
+		close(exited)
+	}()
+	require.Equal(t, 1, c.SubscriptionCount())
+
+	require.True(t, c.unwatchExtrinsic(el.subID))
+	require.Equal(t, 0, c.SubscriptionCount())
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("listener did not stop")
+	}
+
+	// the watch can only be cancelled once
+	require.False(t, c.unwatchExtrinsic(el.subID))
+}
+
+// mockWatchBlockAPI records the channels registered for block notifications
+type mockWatchBlockAPI struct {
+	MockBlockAPI
+	lock      sync.Mutex
+	nextID    byte
+	imported  map[byte]chan<- *types.Block
+	finalized map[byte]chan<- *types.Header
+	best      common.Hash
+}
+
+func newMockWatchBlockAPI() *mockWatchBlockAPI {
+	return &mockWatchBlockAPI{
+		imported:  make(map[byte]chan<- *types.Block),
+		finalized: make(map[byte]chan<- *types.Header),
+	}
+}
+
+func (m *mockWatchBlockAPI) RegisterImportedChannel(ch chan<- *types.Block) (byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.nextID++
+	m.imported[m.nextID] = ch
+	return m.nextID, nil
+}
+func (m *mockWatchBlockAPI) UnregisterImportedChannel(id byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.imported, id)
+}
+func (m *mockWatchBlockAPI) RegisterFinalizedChannel(ch chan<- *types.Header) (byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.nextID++
+	m.finalized[m.nextID] = ch
+	return m.nextID, nil
+}
+func (m *mockWatchBlockAPI) UnregisterFinalizedChannel(id byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.finalized, id)
+}
+
+func (m *mockWatchBlockAPI) BestBlockHash() common.Hash {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.best
+}
+
+func (m *mockWatchBlockAPI) importBlock(block *types.Block) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, ch := range m.imported {
+		ch <- block
+	}
+}
+
+func (m *mockWatchBlockAPI) channels() (int, int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.imported), len(m.finalized)
+}
+
+func TestWSConn_ExtrinsicWatchesShareBlockChannels(t *testing.T) {
+	bAPI := newMockWatchBlockAPI()
+	ts := state.NewTransactionState()
+	c := NewWSConn(nil, &HTTPServerConfig{BlockAPI: bAPI, TransactionQueueAPI: ts})
+
+	el1, err := c.addExtrinsicWatch(types.Extrinsic{1, 2, 3})
+	require.NoError(t, err)
+	el2, err := c.addExtrinsicWatch(types.Extrinsic{4, 5, 6})
+	require.NoError(t, err)
+
+	imported, finalized := bAPI.channels()
+	require.Equal(t, 1, imported)
+	require.Equal(t, 1, finalized)
+
+	// pool events are only passed on to the watch of their extrinsic
+	_, err = ts.AddToPool(transaction.NewValidTransaction(el1.ext, &transaction.Validity{Priority: 1}))
+	require.NoError(t, err)
+	c.watches.sync()
+	require.NotEmpty(t, el1.eventsChan)
+	require.Len(t, el2.eventsChan, 0)
+
+	// blocks sent on the shared channel are passed on to each watch
+	block := types.NewEmptyBlock()
+	bAPI.importBlock(block)
+
+	for _, el := range []*ExtrinsicWatchListener{el1, el2} {
+		select {
+		case b := <-el.importedChan:
+			require.Equal(t, block, b)
+		case <-time.After(time.Second):
+			t.Fatal("did not receive block")
+		}
+	}
+
+	// the channels are unregistered once the last watch ends
+	require.True(t, c.unwatchExtrinsic(el1.subID))
+	imported, _ = bAPI.channels()
+	require.Equal(t, 1, imported)
+
+	require.True(t, c.unwatchExtrinsic(el2.subID))
+	imported, finalized = bAPI.channels()
+	require.Equal(t, 0, imported)
+	require.Equal(t, 0, finalized)
+}
+
+func TestWSConn_MaxExtrinsicWatches(t *testing.T) {
+	c := NewWSConn(nil, &HTTPServerConfig{BlockAPI: newMockWatchBlockAPI()})
+
+	for i := 0; i < maxExtrinsicWatches; i++ {
+		_, err := c.addExtrinsicWatch(types.Extrinsic{byte(i), byte(i >> 8)})
+		require.NoError(t, err)
+	}
+
+	_, err := c.addExtrinsicWatch(types.Extrinsic{1, 2, 3})
+	require.Equal(t, errTooManyWatches, err)
+}
+
+func TestHTTPServer_ServeHTTP_ExtrinsicWatchRetracted(t *testing.T) {
+	bAPI := newMockWatchBlockAPI()
+	cfg := &HTTPServerConfig{
+		Modules:   []string{"system", "author"},
+		RPCPort:   8577,
+		WSPort:    8578,
+		WSEnabled: true,
+		RPCAPI:    NewService(),
+		BlockAPI:  bAPI,
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.Nil(t, err)
+
+	time.Sleep(time.Second) // give server a second to start
+
+	u := url.URL{Scheme: "ws", Host: "localhost:8578", Path: "/"}
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	s.wsConnsLock.Lock()
+	wsc := s.wsConns[0]
+	s.wsConnsLock.Unlock()
+
+	ext := types.Extrinsic{1, 2, 3}
+	el, err := wsc.addExtrinsicWatch(ext)
+	require.NoError(t, err)
+	go el.Listen()
+
+	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{ext})
+	require.NoError(t, err)
+	header, err := types.NewHeader(common.Hash{}, big.NewInt(1), common.Hash{}, common.Hash{}, types.Digest{})
+	require.NoError(t, err)
+
+	bAPI.lock.Lock()
+	bAPI.best = header.Hash()
+	bAPI.lock.Unlock()
+	bAPI.importBlock(types.NewBlock(header, body))
+
+	// a block on another fork becomes the best block
+	other, err := types.NewHeader(common.Hash{0x1}, big.NewInt(2), common.Hash{}, common.Hash{}, types.Digest{})
+	require.NoError(t, err)
+	bAPI.lock.Lock()
+	bAPI.best = other.Hash()
+	bAPI.lock.Unlock()
+	bAPI.importBlock(types.NewBlock(other, types.NewBody(nil)))
+
+	for _, status := range []string{`{"inBlock":"` + header.Hash().String() + `"}`,
+		`{"retracted":"` + header.Hash().String() + `"}`} {
+		_, message, err := c.ReadMessage()
+		require.NoError(t, err)
+		expected := `{"jsonrpc":"2.0","method":"author_extrinsicUpdate","params":{"result":` + status +
+			`,"subscription":` + fmt.Sprint(el.subID) + `}}` + "\n"
+		require.Equal(t, expected, string(message))
+	}
+}
+
+func TestParseSubscriptionID(t *testing.T) {
+	id, ok := parseSubscriptionID([]interface{}{float64(3)})
+	require.True(t, ok)
+	require.Equal(t, 3, id)
+
+	id, ok = parseSubscriptionID([]interface{}{"4"})
+	require.True(t, ok)
+	require.Equal(t, 4, id)
+
+	_, ok = parseSubscriptionID([]interface{}{})
+	require.False(t, ok)
+}
+
+func TestHTTPServer_ServeHTTP_PoolEvents(t *testing.T) {
+	ts := state.NewTransactionState()
+	cfg := &HTTPServerConfig{
+		Modules:             []string{"system", "author"},
+		RPCPort:             8565,
+		WSPort:              8566,
+		WSEnabled:           true,
+		RPCAPI:              NewService(),
+		TransactionQueueAPI: ts,
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.Nil(t, err)
+
+	time.Sleep(time.Second) // give server a second to start
+
+	u := url.URL{Scheme: "ws", Host: "localhost:8566", Path: "/"}
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	err = c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"author_subscribePoolEvents","params":[],"id":1}`))
+	require.NoError(t, err)
+
+	_, message, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","result":1,"id":1}`+"\n"), message)
+
+	ext := types.Extrinsic{1, 2, 3}
+	_, err = ts.AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{Priority: 1}))
+	require.NoError(t, err)
+
+	_, message, err = c.ReadMessage()
+	require.NoError(t, err)
+	expected := `{"jsonrpc":"2.0","method":"author_poolEvent","params":{"result":{"type":"imported","hash":"` + ext.Hash().String() + `"},"subscription":1}}` + "\n"
+	require.Equal(t, expected, string(message))
+
+	err = c.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"author_unsubscribePoolEvents","params":[1],"id":2}`))
+	require.NoError(t, err)
+
+	_, message, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","result":true,"id":2}`+"\n"), message)
+	require.Equal(t, 0, s.ActiveSubscriptions())
+}
+
+func TestHTTPServer_ServeHTTP_Unsubscribe(t *testing.T) {
+	bAPI := newMockWatchBlockAPI()
+	cfg := &HTTPServerConfig{
+		Modules:    []string{"system", "chain"},
+		RPCPort:    8585,
+		WSPort:     8586,
+		WSEnabled:  true,
+		RPCAPI:     NewService(),
+		BlockAPI:   bAPI,
+		StorageAPI: new(MockStorageAPI),
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.Nil(t, err)
+
+	time.Sleep(time.Second) // give server a second to start
+
+	u := url.URL{Scheme: "ws", Host: "localhost:8586", Path: "/"}
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	calls := []struct {
+		call     string
+		expected string
+	}{
+		{
+			`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":1}`,
+			`{"jsonrpc":"2.0","result":1,"id":1}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"chain_subscribeFinalizedHeads","params":[],"id":2}`,
+			`{"jsonrpc":"2.0","result":2,"id":2}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"state_subscribeStorage","params":[],"id":3}`,
+			`{"jsonrpc":"2.0","result":3,"id":3}`,
+		},
+		// a subscription can only be cancelled by the unsubscribe method of its subscribe method
+		{
+			`{"jsonrpc":"2.0","method":"state_unsubscribeStorage","params":[1],"id":4}`,
+			`{"jsonrpc":"2.0","result":false,"id":4}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"chain_unsubscribeNewHeads","params":[1],"id":5}`,
+			`{"jsonrpc":"2.0","result":true,"id":5}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"chain_unsubscribeNewHeads","params":[1],"id":6}`,
+			`{"jsonrpc":"2.0","result":false,"id":6}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"chain_unsubscribeFinalizedHeads","params":[2],"id":7}`,
+			`{"jsonrpc":"2.0","result":true,"id":7}`,
+		},
+		{
+			`{"jsonrpc":"2.0","method":"state_unsubscribeStorage","params":[3],"id":8}`,
+			`{"jsonrpc":"2.0","result":true,"id":8}`,
+		},
+	}
+
+	for _, item := range calls {
+		err = c.WriteMessage(websocket.TextMessage, []byte(item.call))
+		require.NoError(t, err)
+
+		_, message, err := c.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, item.expected+"\n", string(message))
+	}
+
+	// the listeners' channels and slots are released
+	require.Equal(t, 0, s.ActiveSubscriptions())
+	imported, finalized := bAPI.channels()
+	require.Equal(t, 0, imported)
+	require.Equal(t, 0, finalized)
+}
+
+func TestHTTPServer_ServeHTTP_ExtrinsicWatchPoolStatus(t *testing.T) {
+	ts := state.NewTransactionState()
+	cfg := &HTTPServerConfig{
+		Modules:             []string{"system", "author"},
+		RPCPort:             8575,
+		WSPort:              8576,
+		WSEnabled:           true,
+		RPCAPI:              NewService(),
+		BlockAPI:            new(MockBlockAPI),
+		TransactionQueueAPI: ts,
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.Nil(t, err)
+
+	time.Sleep(time.Second) // give server a second to start
+
+	u := url.URL{Scheme: "ws", Host: "localhost:8576", Path: "/"}
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 1, s.ActiveWSConnections())
+	s.wsConnsLock.Lock()
+	wsc := s.wsConns[0]
+	s.wsConnsLock.Unlock()
+
+	ext := types.Extrinsic{1, 2, 3}
+	el, err := wsc.addExtrinsicWatch(ext)
+	require.NoError(t, err)
+
+	// the extrinsic isn't valid yet when it's submitted
+	_, err = ts.AddFuture(ext)
+	require.NoError(t, err)
+	require.False(t, el.sendSubmittedStatus())
+	go el.Listen()
+
+	vt := transaction.NewValidTransaction(ext, &transaction.Validity{Priority: 1})
+	_, err = ts.PromoteFuture(vt)
+	require.NoError(t, err)
+	ts.NotifyBroadcast(ext)
+	_, err = ts.Push(vt)
+	require.NoError(t, err)
+	for i := 0; i < transaction.DefaultMaxValidationFailures; i++ {
+		ts.RecordValidationFailure(ext)
+	}
+
+	// the extrinsic becoming ready again isn't sent twice
+	for _, status := range []string{`"future"`, `"ready"`, `{"broadcast":[]}`, `"invalid"`} {
+		_, message, err := c.ReadMessage()
+		require.NoError(t, err)
+		expected := `{"jsonrpc":"2.0","method":"author_extrinsicUpdate","params":{"result":` + status +
+			`,"subscription":` + fmt.Sprint(el.subID) + `}}` + "\n"
+		require.Equal(t, expected, string(message))
+	}
+
+	// the watch ends once the extrinsic is invalid
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, wsc.SubscriptionCount())
+}
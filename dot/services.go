@@ -17,9 +17,13 @@
 package dot
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"path/filepath"
+	"time"
 
 	database "github.com/ChainSafe/chaindb"
 
@@ -31,14 +35,20 @@ import (
 	"github.com/ChainSafe/gossamer/dot/system"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/blockstats"
 	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/memstats"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmtime"
+	"github.com/ChainSafe/gossamer/lib/scale"
+	"github.com/ChainSafe/gossamer/lib/telemetry"
+	"github.com/ChainSafe/gossamer/lib/trace"
 )
 
 // State Service
@@ -48,6 +58,40 @@ func createStateService(cfg *Config) (*state.Service, error) {
 	logger.Info("creating state service...")
 	stateSrvc := state.NewService(cfg.Global.BasePath, cfg.Log.StateLvl)
 
+	if cfg.Global.BackupDB {
+		stateSrvc.EnableBackupBeforeMigration()
+	}
+
+	if cfg.Global.ExtrinsicIndex {
+		stateSrvc.EnableExtrinsicIndex()
+	}
+
+	// the database is started with the hash function it was initialized with, which must match the configured one
+	if cfg.Global.Hasher != "" {
+		err := stateSrvc.SetHasher(cfg.Global.Hasher)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Global.DBCache != "" {
+		sizes, err := state.ParseColumnCacheSizes(cfg.Global.DBCache)
+		if err != nil {
+			return nil, err
+		}
+		stateSrvc.SetColumnCacheSizes(sizes)
+	}
+
+	// an in-memory node doesn't persist its genesis state, so it's initialized every time it's created
+	if cfg.Global.InMemory {
+		stateSrvc.UseMemDB()
+
+		_, err := initState(cfg, stateSrvc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// start state service (initialize state database)
 	err := stateSrvc.Start()
 	if err != nil {
@@ -66,22 +110,39 @@ func createStateService(cfg *Config) (*state.Service, error) {
 		return nil, fmt.Errorf("failed to load latest state from database: %s", err)
 	}
 
+	if cfg.Core.TxBanDuration != 0 {
+		stateSrvc.Transaction.SetBanDuration(time.Duration(cfg.Core.TxBanDuration) * time.Second)
+	}
+
+	stateSrvc.Transaction.SetPreferLocal(cfg.Core.PreferLocalTxs)
+	stateSrvc.Transaction.SetLimits(int(cfg.Core.PoolLimit), int(cfg.Core.PoolKBytes)*1024)
+	stateSrvc.Block.SetHeadersOnly(cfg.Core.HeadersOnly)
+
+	if cfg.Core.HaltAtBlock != 0 {
+		err = stateSrvc.Block.SetHaltAtBlock(new(big.Int).SetUint64(cfg.Core.HaltAtBlock))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set halt block: %w", err)
+		}
+	}
+
 	return stateSrvc, nil
 }
 
-func createRuntime(cfg *Config, st *state.Service, ks *keystore.GenericKeystore, net *network.Service) (runtime.LegacyInstance, error) {
+// createRuntime creates the node's runtime, codeCache is used to load and store its compiled module if it's set
+func createRuntime(cfg *Config, st *state.Service, ks *keystore.GenericKeystore, net *network.Service,
+	codeCache *wasmer.CodeCache) (runtime.LegacyInstance, error) {
 	logger.Info(
 		"creating runtime...",
 		"interpreter", cfg.Core.WasmInterpreter,
 	)
 
-	// load runtime code from trie
-	code, err := st.Storage.GetStorage(nil, []byte(":code"))
+	// load runtime code from trie, or its substitute
+	code, err := runtimeCode(st)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve :code from trie: %s", err)
 	}
 
-	ts, err := st.Storage.TrieState(nil)
+	ts, err := st.Storage.OverlayState(nil)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +156,8 @@ func createRuntime(cfg *Config, st *state.Service, ks *keystore.GenericKeystore,
 	switch cfg.Core.WasmInterpreter {
 	case wasmer.Name:
 		rtCfg := &wasmer.Config{
-			Imports: wasmer.ImportsLegacyNodeRuntime,
+			Imports:   wasmer.ImportsLegacyNodeRuntime,
+			CodeCache: codeCache,
 		}
 		rtCfg.Storage = ts
 		rtCfg.Keystore = ks
@@ -103,6 +165,10 @@ func createRuntime(cfg *Config, st *state.Service, ks *keystore.GenericKeystore,
 		rtCfg.NodeStorage = ns
 		rtCfg.Network = net
 		rtCfg.Role = cfg.Core.Roles
+		rtCfg.HeapPages = cfg.Core.HeapPages
+		if cfg.Core.HostStats {
+			rtCfg.HostStats = runtime.DefaultHostStats
+		}
 
 		// create runtime executor
 		rt, err = wasmer.NewLegacyInstance(code, rtCfg)
@@ -119,6 +185,10 @@ func createRuntime(cfg *Config, st *state.Service, ks *keystore.GenericKeystore,
 		rtCfg.NodeStorage = ns
 		rtCfg.Network = net
 		rtCfg.Role = cfg.Core.Roles
+		rtCfg.HeapPages = cfg.Core.HeapPages
+		if cfg.Core.HostStats {
+			rtCfg.HostStats = runtime.DefaultHostStats
+		}
 
 		// create runtime executor
 		rt, err = wasmtime.NewLegacyInstance(code, rtCfg)
@@ -173,10 +243,12 @@ func createBABEService(cfg *Config, rt runtime.LegacyInstance, st *state.Service
 		Threshold:        cfg.Core.BabeThreshold,
 		SlotDuration:     cfg.Core.SlotDuration,
 		Authority:        cfg.Core.BabeAuthority,
+		SkipEmptyBlocks:  cfg.Core.SkipEmptyBlocks,
 	}
 
 	if cfg.Core.BabeAuthority {
 		bcfg.Keypair = kps[0].(*sr25519.Keypair)
+		bcfg.Keystore = ks
 	}
 
 	// create new BABE service
@@ -191,8 +263,32 @@ func createBABEService(cfg *Config, rt runtime.LegacyInstance, st *state.Service
 
 // Core Service
 
+// createBlockStats creates the collector of the block time, propagation delay and missed slot stats
+func createBlockStats(st *state.Service, bp BlockProducer) *blockstats.Collector {
+	var slotDuration time.Duration
+	if bp != nil && bp.Configuration() != nil {
+		slotDuration = time.Duration(bp.Configuration().SlotDuration) * time.Millisecond
+	}
+
+	return blockstats.NewCollector(st.Block, slotDuration, blockstats.DefaultWindow)
+}
+
+// createCodeCache returns the cache of compiled runtime modules in the node's base path
+func createCodeCache(cfg *Config) (*wasmer.CodeCache, error) {
+	if cfg.Global.BasePath == "" {
+		return nil, nil
+	}
+
+	cache, err := wasmer.NewCodeCache(filepath.Join(cfg.Global.BasePath, "wasm-cache"), wasmer.DefaultCodeCacheLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime code cache: %s", err)
+	}
+
+	return cache, nil
+}
+
 // createCoreService creates the core service from the provided core configuration
-func createCoreService(cfg *Config, bp BlockProducer, fg core.FinalityGadget, verifier *babe.VerificationManager, rt runtime.LegacyInstance, ks *keystore.GlobalKeystore, stateSrvc *state.Service, net *network.Service) (*core.Service, error) {
+func createCoreService(cfg *Config, bp BlockProducer, fg core.FinalityGadget, verifier *babe.VerificationManager, rt runtime.LegacyInstance, ks *keystore.GlobalKeystore, stateSrvc *state.Service, net *network.Service, codeCache *wasmer.CodeCache) (*core.Service, error) {
 	logger.Info(
 		"creating core service...",
 		"authority", cfg.Core.Roles == types.AuthorityRole,
@@ -200,6 +296,11 @@ func createCoreService(cfg *Config, bp BlockProducer, fg core.FinalityGadget, ve
 
 	handler := grandpa.NewMessageHandler(fg.(*grandpa.Service), stateSrvc.Block)
 
+	codeSubs, err := state.LoadCodeSubstitutes(stateSrvc.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load code substitutes: %s", err)
+	}
+
 	// set core configuration
 	coreConfig := &core.Config{
 		LogLvl:                  cfg.Log.CoreLvl,
@@ -215,6 +316,13 @@ func createCoreService(cfg *Config, bp BlockProducer, fg core.FinalityGadget, ve
 		IsFinalityAuthority:     cfg.Core.GrandpaAuthority,
 		Verifier:                verifier,
 		Network:                 net,
+		HeapPages:               cfg.Core.HeapPages,
+		RuntimeCallTimeout:      time.Duration(cfg.RPC.CallTimeout) * time.Second,
+		NoPropagate:             cfg.Core.NoPropagate,
+		LocalTxsOnly:            cfg.Core.LocalTxsOnly,
+		CodeSubstitutes:         codeSubs,
+		CodeCache:               codeCache,
+		BasePath:                cfg.Global.BasePath,
 	}
 
 	// create new core service
@@ -230,11 +338,12 @@ func createCoreService(cfg *Config, bp BlockProducer, fg core.FinalityGadget, ve
 // Network Service
 
 // createNetworkService creates a network service from the command configuration and genesis data
-func createNetworkService(cfg *Config, stateSrvc *state.Service, syncer *sync.Service) (*network.Service, error) {
+func createNetworkService(cfg *Config, stateSrvc *state.Service, syncer *sync.Service, ks keystore.Keystore) (*network.Service, error) {
 	logger.Info(
 		"creating network service...",
 		"roles", cfg.Core.Roles,
 		"port", cfg.Network.Port,
+		"listen addrs", cfg.Network.ListenAddrs,
 		"bootnodes", cfg.Network.Bootnodes,
 		"protocol", cfg.Network.ProtocolID,
 		"nobootstrap", cfg.Network.NoBootstrap,
@@ -249,11 +358,29 @@ func createNetworkService(cfg *Config, stateSrvc *state.Service, syncer *sync.Se
 		BasePath:     cfg.Global.BasePath,
 		Roles:        cfg.Core.Roles,
 		Port:         cfg.Network.Port,
+		ListenAddrs:  cfg.Network.ListenAddrs,
 		Bootnodes:    cfg.Network.Bootnodes,
 		ProtocolID:   cfg.Network.ProtocolID,
 		NoBootstrap:  cfg.Network.NoBootstrap,
 		NoMDNS:       cfg.Network.NoMDNS,
+		DisplayName:  cfg.Network.DisplayName,
+		Location:     cfg.Network.Location,
+		Contact:      cfg.Network.Contact,
 		Syncer:       syncer,
+
+		MaxSyncPeers:        cfg.Network.MaxSyncPeers,
+		MaxTransactionPeers: cfg.Network.MaxTransactionPeers,
+		MaxLightPeers:       cfg.Network.MaxLightPeers,
+	}
+
+	// authority discovery resolves the addresses of the authorities, and publishes our own record
+	// (signed with our `audi` key) if we are an authority
+	networkConfig.AuthorityState = &authorityDiscoveryState{storage: stateSrvc.Storage}
+
+	if cfg.Core.Roles == types.AuthorityRole && ks != nil && ks.Size() > 0 {
+		if kp, ok := ks.Keypairs()[0].(*sr25519.Keypair); ok {
+			networkConfig.AuthorityKey = kp
+		}
 	}
 
 	networkSrvc, err := network.NewService(&networkConfig)
@@ -265,10 +392,53 @@ func createNetworkService(cfg *Config, stateSrvc *state.Service, syncer *sync.Se
 	return networkSrvc, nil
 }
 
+// authorityDiscoveryState reads the authority discovery keys of the current authorities from the storage state
+// (implements network.AuthorityState)
+type authorityDiscoveryState struct {
+	storage *state.StorageState
+}
+
+// AuthorityDiscoveryKeys returns the keys stored by the runtime's authority discovery pallet at the best block
+func (s *authorityDiscoveryState) AuthorityDiscoveryKeys() ([]*sr25519.PublicKey, error) {
+	enc, err := s.storage.GetStorage(nil, runtime.AuthorityDiscoveryKeysKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(enc) == 0 {
+		return nil, nil
+	}
+
+	r := bytes.NewReader(enc)
+	sd := &scale.Decoder{Reader: r}
+	n, err := sd.DecodeInteger()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*sr25519.PublicKey{}
+	for i := int64(0); i < n; i++ {
+		buf := make([]byte, sr25519.PublicKeyLength)
+		_, err = io.ReadFull(r, buf)
+		if err != nil {
+			return nil, err
+		}
+
+		var key *sr25519.PublicKey
+		key, err = sr25519.NewPublicKey(buf)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
 // RPC Service
 
 // createRPCService creates the RPC service from the provided core configuration
-func createRPCService(cfg *Config, stateSrvc *state.Service, coreSrvc *core.Service, networkSrvc *network.Service, bp BlockProducer, rt runtime.LegacyInstance, sysSrvc *system.Service) *rpc.HTTPServer {
+func createRPCService(cfg *Config, stateSrvc *state.Service, coreSrvc *core.Service, networkSrvc *network.Service, bp BlockProducer, rt runtime.LegacyInstance, sysSrvc *system.Service, syncer *sync.Service, stats *blockstats.Collector) *rpc.HTTPServer {
 	logger.Info(
 		"creating rpc service...",
 		"host", cfg.RPC.Host,
@@ -276,6 +446,13 @@ func createRPCService(cfg *Config, stateSrvc *state.Service, coreSrvc *core.Serv
 		"mods", cfg.RPC.Modules,
 		"ws enabled", cfg.RPC.WSEnabled,
 		"ws port", cfg.RPC.WSPort,
+		"ws max subscriptions", cfg.RPC.WSMaxSubscriptions,
+		"finalized only", cfg.RPC.FinalizedOnly,
+		"log requests", cfg.RPC.LogRequests,
+		"auth enabled", cfg.RPC.AuthToken != "",
+		"tracing", cfg.RPC.Tracing,
+		"unsafe", cfg.RPC.Unsafe,
+		"slow query", cfg.RPC.SlowQuery,
 	)
 	rpcService := rpc.NewService()
 
@@ -294,10 +471,74 @@ func createRPCService(cfg *Config, stateSrvc *state.Service, coreSrvc *core.Serv
 		RPCPort:             cfg.RPC.Port,
 		WSEnabled:           cfg.RPC.WSEnabled,
 		WSPort:              cfg.RPC.WSPort,
+		WSMaxSubscriptions:  cfg.RPC.WSMaxSubscriptions,
+		FinalizedOnly:       cfg.RPC.FinalizedOnly,
+		LogRequests:         cfg.RPC.LogRequests,
+		AuthToken:           cfg.RPC.AuthToken,
+		UnsafeMethods:       cfg.RPC.UnsafeMethods,
+		Tracing:             cfg.RPC.Tracing || cfg.Global.TracingEndpoint != "",
+		Unsafe:              cfg.RPC.Unsafe,
+		SlowQueryThreshold:  time.Duration(cfg.RPC.SlowQuery) * time.Millisecond,
 		Modules:             cfg.RPC.Modules,
 	}
 
-	return rpc.NewHTTPServer(rpcConfig)
+	if syncer != nil {
+		rpcConfig.SyncAPI = syncer
+	}
+
+	rpcConfig.SyncStateAPI = &syncStateAPI{stateSrvc: stateSrvc}
+
+	if stats != nil {
+		rpcConfig.BlockStatsAPI = stats
+		rpcConfig.Metrics = append(rpcConfig.Metrics, stats)
+	}
+
+	if cfg.Core.HostStats {
+		rpcConfig.Metrics = append(rpcConfig.Metrics, runtime.DefaultHostStats)
+	}
+
+	rpcConfig.Metrics = append(rpcConfig.Metrics, stateSrvc.Transaction.ResubmitStats(), stateSrvc.Transaction.BanList())
+
+	if bp != nil {
+		rpcConfig.Metrics = append(rpcConfig.Metrics, bp.AuthorshipMetrics())
+	}
+
+	// report the memory held by the subsystems, the subscriptions are held by the rpc server itself
+	mem := memstats.NewRegistry()
+	mem.Register(memstats.TrieCache, stateSrvc.Storage)
+	mem.Register(memstats.BlockTree, stateSrvc.Block)
+	mem.Register(memstats.TransactionPool, stateSrvc.Transaction)
+	if syncer != nil {
+		mem.Register(memstats.PendingBlocks, syncer)
+	}
+	rpcConfig.MemoryAPI = mem
+	rpcConfig.Metrics = append(rpcConfig.Metrics, mem)
+
+	srv := rpc.NewHTTPServer(rpcConfig)
+	mem.Register(memstats.Subscriptions, srv)
+	return srv
+}
+
+// Tracing service
+
+// createTracer creates a tracer that exports the spans of the node to the configured collector
+func createTracer(cfg *Config) *trace.Tracer {
+	logger.Info("creating tracer...", "endpoint", cfg.Global.TracingEndpoint)
+	exporter := trace.NewOTLPExporter(cfg.Global.TracingEndpoint, cfg.Global.Name)
+
+	// operator labels identify the node on tracing dashboards
+	labels := []trace.Attribute{
+		{Key: "node.display_name", Value: cfg.Network.DisplayName},
+		{Key: "node.location", Value: cfg.Network.Location},
+		{Key: "node.contact", Value: cfg.Network.Contact},
+	}
+	for _, l := range labels {
+		if l.Value != "" {
+			exporter.AddResourceAttributes(l)
+		}
+	}
+
+	return trace.NewTracer(exporter)
 }
 
 // System service
@@ -306,6 +547,63 @@ func createSystemService(cfg *types.SystemInfo) *system.Service {
 	return system.NewService(cfg)
 }
 
+// createTelemetryService creates the service reporting to the configured telemetry servers, or to those of the
+// chain spec if none are configured. It returns nil if there are no servers to report to.
+func createTelemetryService(cfg *Config, st *state.Service, net *network.Service) (*telemetry.Service, error) {
+	if cfg.Global.NoTelemetry {
+		return nil, nil
+	}
+
+	var endpoints []*genesis.TelemetryEndpoint
+	for _, u := range cfg.Global.TelemetryURLs {
+		e, err := genesis.ParseTelemetryEndpoint(u)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+
+	if len(endpoints) == 0 {
+		var err error
+		endpoints, err = state.LoadTelemetryEndpoints(st.DB())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	genesisHash, err := st.Block.GetBlockHash(big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.Network.DisplayName
+	if name == "" {
+		name = cfg.Global.Name
+	}
+
+	info := &telemetry.Info{
+		Name:           name,
+		Chain:          cfg.Global.Name,
+		Implementation: cfg.System.SystemName,
+		Version:        cfg.System.SystemVersion,
+		GenesisHash:    genesisHash,
+		Authority:      cfg.Core.BabeAuthority || cfg.Core.GrandpaAuthority,
+	}
+
+	// avoid passing a typed nil if the network service is disabled
+	var tn telemetry.Network
+	if net != nil {
+		tn = net
+	}
+
+	logger.Info("creating telemetry service...", "endpoints", len(endpoints))
+	return telemetry.NewService(info, endpoints, st.Block, tn, telemetry.DefaultInterval), nil
+}
+
 // createGRANDPAService creates a new GRANDPA service
 func createGRANDPAService(cfg *Config, rt runtime.LegacyInstance, st *state.Service, dh *core.DigestHandler, ks keystore.Keystore) (*grandpa.Service, error) {
 	ad, err := rt.GrandpaAuthorities()
@@ -373,12 +671,18 @@ func createBlockVerifier(cfg *Config, st *state.Service, rt runtime.LegacyInstan
 	if err != nil {
 		return nil, err
 	}
+	ver.SetEpochLength(babeCfg.EpochLength)
 
 	logger.Info("verifier", "threshold", threshold)
 	return ver, nil
 }
 
 func createSyncService(cfg *Config, st *state.Service, bp BlockProducer, dh *core.DigestHandler, verifier *babe.VerificationManager, rt runtime.LegacyInstance) (*sync.Service, error) {
+	rules, err := state.LoadBlockRules(st.DB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block rules: %s", err)
+	}
+
 	syncCfg := &sync.Config{
 		LogLvl:           cfg.Log.SyncLvl,
 		BlockState:       st.Block,
@@ -388,6 +692,11 @@ func createSyncService(cfg *Config, st *state.Service, bp BlockProducer, dh *cor
 		Verifier:         verifier,
 		Runtime:          rt,
 		DigestHandler:    dh,
+		BlockRules:       rules,
+	}
+
+	if bp != nil && bp.Configuration() != nil {
+		syncCfg.SlotDuration = time.Duration(bp.Configuration().SlotDuration) * time.Millisecond
 	}
 
 	return sync.NewService(syncCfg)
@@ -82,7 +82,7 @@ func TestCreateCoreService(t *testing.T) {
 
 	networkSrvc := &network.Service{}
 
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc)
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc, nil)
 	require.NoError(t, err)
 
 	dh, err := createDigestHandler(stateSrvc, nil, nil)
@@ -91,7 +91,7 @@ func TestCreateCoreService(t *testing.T) {
 	gs, err := createGRANDPAService(cfg, rt, stateSrvc, dh, ks.Gran)
 	require.NoError(t, err)
 
-	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc)
+	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc, nil)
 	require.Nil(t, err)
 
 	// TODO: improve dot tests #687
@@ -117,7 +117,7 @@ func TestCreateBlockVerifier(t *testing.T) {
 
 	ks := keystore.NewGlobalKeystore()
 	require.NotNil(t, ks)
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{})
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{}, nil)
 	require.NoError(t, err)
 
 	cfg.Core.BabeThreshold = nil
@@ -144,7 +144,7 @@ func TestCreateSyncService(t *testing.T) {
 
 	ks := keystore.NewGlobalKeystore()
 	require.NotNil(t, ks)
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{})
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{}, nil)
 	require.NoError(t, err)
 
 	cfg.Core.BabeThreshold = nil
@@ -173,7 +173,7 @@ func TestCreateNetworkService(t *testing.T) {
 	stateSrvc, err := createStateService(cfg)
 	require.Nil(t, err)
 
-	networkSrvc, err := createNetworkService(cfg, stateSrvc, nil)
+	networkSrvc, err := createNetworkService(cfg, stateSrvc, nil, nil)
 	require.Nil(t, err)
 
 	// TODO: improve dot tests #687
@@ -208,7 +208,7 @@ func TestCreateRPCService(t *testing.T) {
 	ed25519Keyring, _ := keystore.NewEd25519Keyring()
 	ks.Gran.Insert(ed25519Keyring.Alice())
 
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc)
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc, nil)
 	require.NoError(t, err)
 
 	dh, err := createDigestHandler(stateSrvc, nil, nil)
@@ -217,12 +217,12 @@ func TestCreateRPCService(t *testing.T) {
 	gs, err := createGRANDPAService(cfg, rt, stateSrvc, dh, ks.Gran)
 	require.NoError(t, err)
 
-	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc)
+	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc, nil)
 	require.Nil(t, err)
 
 	sysSrvc := createSystemService(&cfg.System)
 
-	rpcSrvc := createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, nil, rt, sysSrvc)
+	rpcSrvc := createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, nil, rt, sysSrvc, nil, nil)
 	require.NotNil(t, rpcSrvc)
 }
 
@@ -250,7 +250,7 @@ func TestCreateBABEService(t *testing.T) {
 	require.Nil(t, err)
 	ks.Babe.Insert(kr.Alice())
 
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{})
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{}, nil)
 	require.NoError(t, err)
 
 	bs, err := createBABEService(cfg, rt, stateSrvc, ks.Babe)
@@ -282,7 +282,7 @@ func TestCreateGrandpaService(t *testing.T) {
 	require.NoError(t, err)
 	ks.Gran.Insert(kr.Alice())
 
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{})
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), &network.Service{}, nil)
 	require.NoError(t, err)
 
 	dh, err := createDigestHandler(stateSrvc, nil, nil)
@@ -332,7 +332,7 @@ func TestNewWebSocketServer(t *testing.T) {
 	ks := keystore.NewGlobalKeystore()
 	ed25519Keyring, _ := keystore.NewEd25519Keyring()
 	ks.Gran.Insert(ed25519Keyring.Alice())
-	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc)
+	rt, err := createRuntime(cfg, stateSrvc, ks.Acco.(*keystore.GenericKeystore), networkSrvc, nil)
 	require.NoError(t, err)
 
 	dh, err := createDigestHandler(stateSrvc, nil, nil)
@@ -341,12 +341,12 @@ func TestNewWebSocketServer(t *testing.T) {
 	gs, err := createGRANDPAService(cfg, rt, stateSrvc, dh, ks.Gran)
 	require.NoError(t, err)
 
-	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc)
+	coreSrvc, err := createCoreService(cfg, nil, gs, nil, rt, ks, stateSrvc, networkSrvc, nil)
 	require.Nil(t, err)
 
 	sysSrvc := createSystemService(&cfg.System)
 
-	rpcSrvc := createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, nil, rt, sysSrvc)
+	rpcSrvc := createRPCService(cfg, stateSrvc, coreSrvc, networkSrvc, nil, rt, sysSrvc, nil, nil)
 	err = rpcSrvc.Start()
 	require.Nil(t, err)
 
@@ -41,6 +41,9 @@ type BlockState struct {
 	bt                 *blocktree.BlockTree
 	baseDB             chaindb.Database
 	db                 chaindb.Database
+	headerDB           *columnTable
+	bodyDB             *columnTable
+	justificationDB    *columnTable
 	lock               sync.RWMutex
 	genesisHash        common.Hash
 	highestBlockHeader *types.Header
@@ -52,6 +55,58 @@ type BlockState struct {
 	finalizedLock sync.RWMutex
 
 	pruneKeyCh chan *types.Header
+
+	// blocks read from the database ahead of being requested
+	readAhead *readAheadCache
+	// decoded headers, see header_cache.go
+	headers *headerCache
+
+	// header-only mode, see block_body_fetch.go
+	headersOnly bool
+	bodyCache   *bodyCache
+	bodyFetcher BodyFetcher
+
+	// halt block, see block_halt.go
+	haltLock    sync.Mutex
+	haltAt      *big.Int
+	haltReached bool
+	halted      chan struct{}
+
+	// extrinsic index, see extrinsic_index.go
+	indexLock       sync.Mutex
+	indexExtrinsics bool
+	indexRunLock    sync.Mutex
+	indexCh         chan struct{}
+
+	// called when the best or finalized block changes, see StorageState.queueHeadStates
+	onHeadChange func()
+}
+
+// newBlockStateDB creates a BlockState without a block tree that stores its data in the given database, with the
+// headers, bodies and justifications in the tables of their column families
+func newBlockStateDB(db chaindb.Database) *BlockState {
+	return &BlockState{
+		baseDB:          db,
+		db:              chaindb.NewTable(db, blockPrefix),
+		headerDB:        newColumnTable(db, headerColumn),
+		bodyDB:          newColumnTable(db, bodyColumn),
+		justificationDB: newColumnTable(db, justificationColumn),
+		imported:        make(map[byte]chan<- *types.Block),
+		finalized:       make(map[byte]chan<- *types.Header),
+		pruneKeyCh:      make(chan *types.Header, pruneKeyBufferSize),
+		readAhead:       newReadAheadCache(readAheadCacheSize),
+		headers:         newHeaderCache(headerCacheSize),
+		indexCh:         make(chan struct{}, 1),
+	}
+}
+
+// setColumnCacheSizes changes the cache sizes in bytes of the given column families
+func (bs *BlockState) setColumnCacheSizes(sizes map[string]int) {
+	for _, t := range []*columnTable{bs.headerDB, bs.bodyDB, bs.justificationDB} {
+		if size, has := sizes[t.col.name]; has {
+			t.cache.resize(size)
+		}
+	}
 }
 
 // NewBlockState will create a new BlockState backed by the database located at basePath
@@ -60,17 +115,14 @@ func NewBlockState(db chaindb.Database, bt *blocktree.BlockTree) (*BlockState, e
 		return nil, fmt.Errorf("block tree is nil")
 	}
 
-	bs := &BlockState{
-		bt:         bt,
-		baseDB:     db,
-		db:         chaindb.NewTable(db, blockPrefix),
-		imported:   make(map[byte]chan<- *types.Block),
-		finalized:  make(map[byte]chan<- *types.Header),
-		pruneKeyCh: make(chan *types.Header, pruneKeyBufferSize),
-	}
-
+	bs := newBlockStateDB(db)
+	bs.bt = bt
 	bs.genesisHash = bt.GenesisHash()
-	var err error
+
+	err := bs.loadSteering()
+	if err != nil {
+		return nil, err
+	}
 
 	// set the current highest block
 	bs.highestBlockHeader, err = bs.BestBlockHeader()
@@ -83,14 +135,8 @@ func NewBlockState(db chaindb.Database, bt *blocktree.BlockTree) (*BlockState, e
 
 // NewBlockStateFromGenesis initializes a BlockState from a genesis header, saving it to the database located at basePath
 func NewBlockStateFromGenesis(db chaindb.Database, header *types.Header) (*BlockState, error) {
-	bs := &BlockState{
-		bt:         blocktree.NewBlockTreeFromGenesis(header, db),
-		baseDB:     db,
-		db:         chaindb.NewTable(db, blockPrefix),
-		imported:   make(map[byte]chan<- *types.Block),
-		finalized:  make(map[byte]chan<- *types.Header),
-		pruneKeyCh: make(chan *types.Header, pruneKeyBufferSize),
-	}
+	bs := newBlockStateDB(db)
+	bs.bt = blocktree.NewBlockTreeFromGenesis(header, db)
 
 	err := bs.setArrivalTime(header.Hash(), uint64(time.Now().Unix()))
 	if err != nil {
@@ -102,7 +148,7 @@ func NewBlockStateFromGenesis(db chaindb.Database, header *types.Header) (*Block
 		return nil, err
 	}
 
-	err = bs.db.Put(headerHashKey(header.Number.Uint64()), header.Hash().ToBytes())
+	err = bs.headerDB.Put(headerHashKey(header.Number.Uint64()), header.Hash().ToBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +184,12 @@ var (
 	receiptPrefix       = []byte("rcp") // receiptPrefix + hash -> receipt
 	messageQueuePrefix  = []byte("mqp") // messageQueuePrefix + hash -> message queue
 	justificationPrefix = []byte("jcp") // justificationPrefix + hash -> justification
+	prevotesPrefix      = []byte("jpv") // prevotesPrefix + hash -> pre-votes of the round that finalized the block
+
+	blockBodyHashesPrefix = []byte("blh") // blockBodyHashesPrefix + hash -> concatenated extrinsic hashes
+	extrinsicPrefix       = []byte("ext") // extrinsicPrefix + extrinsic hash -> extrinsic
+	extrinsicRefPrefix    = []byte("exr") // extrinsicRefPrefix + extrinsic hash -> reference count
+	extrinsicIndexPrefix  = []byte("exi") // extrinsicIndexPrefix + extrinsic hash -> finalized blocks that include it
 )
 
 // encodeBlockNumber encodes a block number as big endian uint64
@@ -183,43 +235,54 @@ func (bs *BlockState) GenesisHash() common.Hash {
 
 // DeleteBlock deletes all instances of the block and its related data in the database
 func (bs *BlockState) DeleteBlock(hash common.Hash) error {
+	bs.readAhead.remove(hash)
+	bs.headers.remove(hash)
+	bs.bodyCache.remove(hash)
+
 	if has, _ := bs.HasHeader(hash); has {
-		err := bs.db.Del(headerKey(hash))
+		err := bs.headerDB.Del(headerKey(hash))
 		if err != nil {
 			return err
 		}
 	}
 
 	if has, _ := bs.HasBlockBody(hash); has {
-		err := bs.db.Del(blockBodyKey(hash))
+		err := bs.deleteBlockBody(hash)
 		if err != nil {
 			return err
 		}
 	}
 
 	if has, _ := bs.HasArrivalTime(hash); has {
-		err := bs.db.Del(arrivalTimeKey(hash))
+		err := bs.headerDB.Del(arrivalTimeKey(hash))
 		if err != nil {
 			return err
 		}
 	}
 
 	if has, _ := bs.HasReceipt(hash); has {
-		err := bs.db.Del(prefixKey(hash, receiptPrefix))
+		err := bs.bodyDB.Del(prefixKey(hash, receiptPrefix))
 		if err != nil {
 			return err
 		}
 	}
 
 	if has, _ := bs.HasMessageQueue(hash); has {
-		err := bs.db.Del(prefixKey(hash, messageQueuePrefix))
+		err := bs.bodyDB.Del(prefixKey(hash, messageQueuePrefix))
 		if err != nil {
 			return err
 		}
 	}
 
 	if has, _ := bs.HasJustification(hash); has {
-		err := bs.db.Del(prefixKey(hash, justificationPrefix))
+		err := bs.justificationDB.Del(prefixKey(hash, justificationPrefix))
+		if err != nil {
+			return err
+		}
+	}
+
+	if has, _ := bs.justificationDB.Has(prefixKey(hash, prevotesPrefix)); has {
+		err := bs.justificationDB.Del(prefixKey(hash, prevotesPrefix))
 		if err != nil {
 			return err
 		}
@@ -230,22 +293,48 @@ func (bs *BlockState) DeleteBlock(hash common.Hash) error {
 
 // HasHeader returns if the db contains a header with the given hash
 func (bs *BlockState) HasHeader(hash common.Hash) (bool, error) {
-	return bs.db.Has(headerKey(hash))
+	return bs.headerDB.Has(headerKey(hash))
 }
 
 // GetHeader returns a BlockHeader for a given hash
 func (bs *BlockState) GetHeader(hash common.Hash) (*types.Header, error) {
-	result := new(types.Header)
-
 	if bs.db == nil {
 		return nil, fmt.Errorf("database is nil")
 	}
 
+	if header := bs.readAhead.header(hash); header != nil {
+		return header, nil
+	}
+
+	if header := bs.headers.get(hash); header != nil {
+		return header, nil
+	}
+
+	return bs.getHeaderFromDB(hash)
+}
+
+// GetHeaderEncoding returns the SCALE encoding of the header with the given hash
+func (bs *BlockState) GetHeaderEncoding(hash common.Hash) ([]byte, error) {
+	if enc := bs.headers.encoding(hash); enc != nil {
+		return enc, nil
+	}
+
+	header, err := bs.getHeaderFromDB(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return header.Encode()
+}
+
+func (bs *BlockState) getHeaderFromDB(hash common.Hash) (*types.Header, error) {
+	result := new(types.Header)
+
 	if has, _ := bs.HasHeader(hash); !has {
 		return nil, chaindb.ErrKeyNotFound
 	}
 
-	data, err := bs.db.Get(headerKey(hash))
+	data, err := bs.headerDB.Get(headerKey(hash))
 	if err != nil {
 		return nil, err
 	}
@@ -266,12 +355,13 @@ func (bs *BlockState) GetHeader(hash common.Hash) (*types.Header, error) {
 	}
 
 	result.Hash()
+	bs.headers.put(result, data)
 	return result, err
 }
 
 // GetHeaderByNumber returns a block header given a number
 func (bs *BlockState) GetHeaderByNumber(num *big.Int) (*types.Header, error) {
-	bh, err := bs.db.Get(headerHashKey(num.Uint64()))
+	bh, err := bs.headerDB.Get(headerHashKey(num.Uint64()))
 	if err != nil {
 		return nil, fmt.Errorf("cannot get block %d: %s", num, err)
 	}
@@ -288,6 +378,9 @@ func (bs *BlockState) GetBlockByHash(hash common.Hash) (*types.Block, error) {
 	}
 
 	blockBody, err := bs.GetBlockBody(hash)
+	if err == chaindb.ErrKeyNotFound && bs.HeadersOnly() {
+		blockBody, err = bs.fetchBlockBody(header)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +390,7 @@ func (bs *BlockState) GetBlockByHash(hash common.Hash) (*types.Block, error) {
 // GetBlockByNumber returns a block for a given blockNumber
 func (bs *BlockState) GetBlockByNumber(num *big.Int) (*types.Block, error) {
 	// First retrieve the block hash in a byte array based on the block number from the database
-	byteHash, err := bs.db.Get(headerHashKey(num.Uint64()))
+	byteHash, err := bs.headerDB.Get(headerHashKey(num.Uint64()))
 	if err != nil {
 		return nil, fmt.Errorf("cannot get block %d: %s", num, err)
 	}
@@ -315,7 +408,7 @@ func (bs *BlockState) GetBlockByNumber(num *big.Int) (*types.Block, error) {
 // GetBlockHash returns block hash for a given blockNumber
 func (bs *BlockState) GetBlockHash(blockNumber *big.Int) (*common.Hash, error) {
 	// First retrieve the block hash in a byte array based on the block number from the database
-	byteHash, err := bs.db.Get(headerHashKey(blockNumber.Uint64()))
+	byteHash, err := bs.headerDB.Get(headerHashKey(blockNumber.Uint64()))
 	if err != nil {
 		return nil, fmt.Errorf("cannot get block %d: %s", blockNumber, err)
 	}
@@ -329,6 +422,7 @@ func (bs *BlockState) SetHeader(header *types.Header) error {
 	defer bs.lock.Unlock()
 
 	hash := header.Hash()
+	bs.readAhead.remove(hash)
 
 	// if this is the highest block we've seen, save it
 	if bs.highestBlockHeader == nil {
@@ -343,22 +437,46 @@ func (bs *BlockState) SetHeader(header *types.Header) error {
 		return err
 	}
 
-	err = bs.db.Put(headerKey(hash), bh)
+	err = bs.headerDB.Put(headerKey(hash), bh)
 	if err != nil {
 		return err
 	}
 
+	bs.headers.put(header, bh)
 	return nil
 }
 
 // HasBlockBody returns true if the db contains the block body
 func (bs *BlockState) HasBlockBody(hash common.Hash) (bool, error) {
-	return bs.db.Has(blockBodyKey(hash))
+	if has, err := bs.bodyDB.Has(blockBodyHashesKey(hash)); has || err != nil {
+		return has, err
+	}
+
+	return bs.bodyDB.Has(blockBodyKey(hash))
 }
 
 // GetBlockBody will return Body for a given hash
 func (bs *BlockState) GetBlockBody(hash common.Hash) (*types.Body, error) {
-	data, err := bs.db.Get(blockBodyKey(hash))
+	if body := bs.readAhead.body(hash); body != nil {
+		return body, nil
+	}
+
+	if body := bs.bodyCache.get(hash); body != nil {
+		return body, nil
+	}
+
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+
+	return bs.getBlockBodyFromDB(hash)
+}
+
+func (bs *BlockState) getBlockBodyFromDB(hash common.Hash) (*types.Body, error) {
+	if has, _ := bs.bodyDB.Has(blockBodyHashesKey(hash)); has {
+		return bs.getDedupedBlockBody(hash)
+	}
+
+	data, err := bs.bodyDB.Get(blockBodyKey(hash))
 	if err != nil {
 		return nil, err
 	}
@@ -366,13 +484,46 @@ func (bs *BlockState) GetBlockBody(hash common.Hash) (*types.Body, error) {
 	return types.NewBody(data), nil
 }
 
-// SetBlockBody will add a block body to the db
+// SetBlockBody will add a block body to the db. Extrinsics in the body are stored once and shared
+// between all blocks that include them.
 func (bs *BlockState) SetBlockBody(hash common.Hash, body *types.Body) error {
 	bs.lock.Lock()
 	defer bs.lock.Unlock()
 
-	err := bs.db.Put(blockBodyKey(hash), body.AsOptional().Value)
-	return err
+	// release the extrinsics of any body previously stored for this block
+	err := bs.deleteBlockBodyUnlocked(hash)
+	if err != nil {
+		return err
+	}
+
+	ok, err := bs.setDedupedBlockBody(hash, body)
+	if err != nil || ok {
+		return err
+	}
+
+	return bs.bodyDB.Put(blockBodyKey(hash), body.AsOptional().Value)
+}
+
+// deleteBlockBody removes the body of the given block, along with any extrinsics that are no longer referenced
+func (bs *BlockState) deleteBlockBody(hash common.Hash) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	return bs.deleteBlockBodyUnlocked(hash)
+}
+
+func (bs *BlockState) deleteBlockBodyUnlocked(hash common.Hash) error {
+	bs.readAhead.remove(hash)
+
+	if has, _ := bs.bodyDB.Has(blockBodyHashesKey(hash)); has {
+		return bs.deleteDedupedBlockBody(hash)
+	}
+
+	if has, _ := bs.bodyDB.Has(blockBodyKey(hash)); has {
+		return bs.bodyDB.Del(blockBodyKey(hash))
+	}
+
+	return nil
 }
 
 // HasFinalizedBlock returns true if there is a finalized block for a given round and setID, false otherwise
@@ -452,7 +603,15 @@ func (bs *BlockState) SetFinalizedHash(hash common.Hash, round, setID uint64) er
 		bs.pruneKeyCh <- header
 	}
 
-	return bs.db.Put(finalizedHashKey(round, setID), hash[:])
+	err := bs.db.Put(finalizedHashKey(round, setID), hash[:])
+	if err != nil {
+		return err
+	}
+
+	bs.headChanged()
+
+	bs.queueExtrinsicIndex()
+	return nil
 }
 
 // SetRound sets the latest finalized GRANDPA round in the db
@@ -491,7 +650,7 @@ func (bs *BlockState) CompareAndSetBlockData(bd *types.BlockData) error {
 
 	if bd.Body != nil && (existingData.Body == nil || (!existingData.Body.Exists && bd.Body.Exists)) {
 		existingData.Body = bd.Body
-		err := bs.SetBlockBody(bd.Hash, types.NewBody(existingData.Body.Value))
+		err := bs.storeBlockBody(bd.Hash, types.NewBody(existingData.Body.Value))
 		if err != nil {
 			return err
 		}
@@ -531,7 +690,12 @@ func (bs *BlockState) AddBlock(block *types.Block) error {
 
 // AddBlockWithArrivalTime adds a block to the blocktree and the DB with the given arrival time
 func (bs *BlockState) AddBlockWithArrivalTime(block *types.Block, arrivalTime uint64) error {
-	err := bs.setArrivalTime(block.Header.Hash(), arrivalTime)
+	err := bs.checkHalt(block.Header)
+	if err != nil {
+		return err
+	}
+
+	err = bs.setArrivalTime(block.Header.Hash(), arrivalTime)
 	if err != nil {
 		return err
 	}
@@ -542,6 +706,14 @@ func (bs *BlockState) AddBlockWithArrivalTime(block *types.Block, arrivalTime ui
 		return err
 	}
 
+	// the forced best block follows blocks built on top of it
+	if forced := bs.bt.ForcedHead(); forced == block.Header.Hash() {
+		err = bs.db.Put(forcedHeadKey, forced[:])
+		if err != nil {
+			return err
+		}
+	}
+
 	// add the header to the DB
 	err = bs.SetHeader(block.Header)
 	if err != nil {
@@ -560,21 +732,30 @@ func (bs *BlockState) AddBlockWithArrivalTime(block *types.Block, arrivalTime ui
 	// only set number->hash mapping for our current chain
 	var onChain bool
 	if onChain, err = bs.isBlockOnCurrentChain(block.Header); onChain && err == nil {
-		err = bs.db.Put(headerHashKey(block.Header.Number.Uint64()), hash.ToBytes())
+		err = bs.headerDB.Put(headerHashKey(block.Header.Number.Uint64()), hash.ToBytes())
 		if err != nil {
 			return err
 		}
 	}
 
-	err = bs.SetBlockBody(block.Header.Hash(), types.NewBody(block.Body.AsOptional().Value))
+	err = bs.storeBlockBody(block.Header.Hash(), types.NewBody(block.Body.AsOptional().Value))
 	if err != nil {
 		return err
 	}
 
+	bs.headChanged()
 	go bs.notifyImported(block)
+	bs.handleHalt(block.Header)
 	return err
 }
 
+// headChanged is called once the best or finalized block may have changed
+func (bs *BlockState) headChanged() {
+	if bs.onHeadChange != nil {
+		bs.onHeadChange()
+	}
+}
+
 // GetAllBlocksAtDepth returns all hashes with the depth of the given hash plus one
 func (bs *BlockState) GetAllBlocksAtDepth(hash common.Hash) []common.Hash {
 	return bs.bt.GetAllBlocksAtDepth(hash)
@@ -626,6 +807,15 @@ func (bs *BlockState) BestBlockHash() common.Hash {
 	return bs.bt.DeepestBlockHash()
 }
 
+// MemoryUsage returns the approximate number of bytes held by the blocktree
+func (bs *BlockState) MemoryUsage() uint64 {
+	if bs.bt == nil {
+		return 0
+	}
+
+	return bs.bt.MemoryUsage()
+}
+
 // BestBlockHeader returns the block header of the current head of the chain
 func (bs *BlockState) BestBlockHeader() (*types.Header, error) {
 	return bs.GetHeader(bs.BestBlockHash())
@@ -663,29 +853,7 @@ func (bs *BlockState) GetSlotForBlock(hash common.Hash) (uint64, error) {
 		return 0, err
 	}
 
-	if len(header.Digest) == 0 {
-		return 0, fmt.Errorf("chain head missing digest")
-	}
-
-	preDigestBytes := header.Digest[0]
-
-	digestItem, err := types.DecodeDigestItem(preDigestBytes)
-	if err != nil {
-		return 0, err
-	}
-
-	preDigest, ok := digestItem.(*types.PreRuntimeDigest)
-	if !ok {
-		return 0, fmt.Errorf("first digest item is not pre-digest")
-	}
-
-	babeHeader := new(types.BabeHeader)
-	err = babeHeader.Decode(preDigest.Data)
-	if err != nil {
-		return 0, fmt.Errorf("cannot decode babe header from pre-digest: %s", err)
-	}
-
-	return babeHeader.SlotNumber, nil
+	return types.GetSlotFromHeader(header)
 }
 
 // SubChain returns the sub-blockchain between the starting hash and the ending hash using the block tree
@@ -728,7 +896,7 @@ func (bs *BlockState) setBestBlockHashKey(hash common.Hash) error {
 
 // HasArrivalTime returns true if the db contains the block's arrival time
 func (bs *BlockState) HasArrivalTime(hash common.Hash) (bool, error) {
-	return bs.db.Has(arrivalTimeKey(hash))
+	return bs.headerDB.Has(arrivalTimeKey(hash))
 }
 
 // GetArrivalTime returns the arrival time of a block given its hash
@@ -761,7 +929,7 @@ func babeHeaderKey(epoch uint64, slot uint64) []byte {
 func (bs *BlockState) GetBabeHeader(epoch uint64, slot uint64) (*types.BabeHeader, error) {
 	result := new(types.BabeHeader)
 
-	data, err := bs.db.Get(babeHeaderKey(epoch, slot))
+	data, err := bs.headerDB.Get(babeHeaderKey(epoch, slot))
 	if err != nil {
 		return nil, err
 	}
@@ -776,5 +944,5 @@ func (bs *BlockState) SetBabeHeader(epoch uint64, slot uint64, bh *types.BabeHea
 	// Write the encoded header
 	enc := bh.Encode()
 
-	return bs.db.Put(babeHeaderKey(epoch, slot), enc)
+	return bs.headerDB.Put(babeHeaderKey(epoch, slot), enc)
 }
@@ -0,0 +1,205 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// Block bodies that are a valid SCALE-encoded list of extrinsics are stored as a list of extrinsic hashes,
+// with each extrinsic stored once under its hash along with a reference count. This way an extrinsic that is
+// included in blocks on multiple forks only takes up space once. Bodies that can't be represented this way
+// are stored as-is under blockBodyPrefix.
+
+// blockBodyHashesKey = blockBodyHashesPrefix + hash
+func blockBodyHashesKey(hash common.Hash) []byte {
+	return append(blockBodyHashesPrefix, hash.ToBytes()...)
+}
+
+// extrinsicKey = extrinsicPrefix + extrinsic hash
+func extrinsicKey(hash common.Hash) []byte {
+	return append(extrinsicPrefix, hash.ToBytes()...)
+}
+
+// extrinsicRefKey = extrinsicRefPrefix + extrinsic hash
+func extrinsicRefKey(hash common.Hash) []byte {
+	return append(extrinsicRefPrefix, hash.ToBytes()...)
+}
+
+// decodeBodyExtrinsics splits a body into its extrinsics. It returns false if the body is not a
+// canonical SCALE encoding of a list of byte arrays, ie. if re-encoding the extrinsics would not
+// result in the same body.
+func decodeBodyExtrinsics(body []byte) ([]types.Extrinsic, bool) {
+	buf := bytes.NewBuffer(body)
+	sd := scale.Decoder{Reader: buf}
+
+	// each extrinsic is at least one byte (its length prefix), so the number of extrinsics
+	// can't exceed the remaining length of the body
+	num, err := sd.DecodeUnsignedInteger()
+	if err != nil || num > uint64(buf.Len()) {
+		return nil, false
+	}
+
+	exts := make([]types.Extrinsic, num)
+	for i := range exts {
+		var length uint64
+		length, err = sd.DecodeUnsignedInteger()
+		if err != nil || length > uint64(buf.Len()) {
+			return nil, false
+		}
+
+		exts[i] = types.NewExtrinsic(buf.Next(int(length)))
+	}
+
+	if buf.Len() != 0 {
+		return nil, false
+	}
+
+	enc, err := scale.Encode(types.ExtrinsicsArrayToBytesArray(exts))
+	if err != nil || !bytes.Equal(enc, body) {
+		return nil, false
+	}
+
+	return exts, true
+}
+
+// setDedupedBlockBody stores the body as a list of extrinsic hashes, storing each extrinsic that isn't already
+// in the database. It returns false if the body can't be represented as a list of extrinsics, in which
+// case nothing is written.
+func (bs *BlockState) setDedupedBlockBody(hash common.Hash, body *types.Body) (bool, error) {
+	exts, ok := decodeBodyExtrinsics(*body)
+	if !ok {
+		return false, nil
+	}
+
+	hashes := make([]byte, 0, len(exts)*32)
+	for _, ext := range exts {
+		extHash := ext.Hash()
+		err := bs.retainExtrinsic(extHash, ext)
+		if err != nil {
+			return false, err
+		}
+
+		hashes = append(hashes, extHash[:]...)
+	}
+
+	return true, bs.bodyDB.Put(blockBodyHashesKey(hash), hashes)
+}
+
+// getDedupedBlockBody reconstructs a body that was stored with setDedupedBlockBody
+func (bs *BlockState) getDedupedBlockBody(hash common.Hash) (*types.Body, error) {
+	hashes, err := bs.bodyDB.Get(blockBodyHashesKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hashes)%32 != 0 {
+		return nil, fmt.Errorf("invalid extrinsic hashes for block body %s", hash)
+	}
+
+	exts := make([]types.Extrinsic, len(hashes)/32)
+	for i := range exts {
+		extHash := common.BytesToHash(hashes[i*32 : (i+1)*32])
+		var ext []byte
+		ext, err = bs.bodyDB.Get(extrinsicKey(extHash))
+		if err != nil {
+			return nil, fmt.Errorf("cannot get extrinsic %s for block body %s: %w", extHash, hash, err)
+		}
+
+		exts[i] = types.NewExtrinsic(ext)
+	}
+
+	return types.NewBodyFromExtrinsics(exts)
+}
+
+// deleteDedupedBlockBody removes the extrinsic hashes of a block body and releases each of its extrinsics
+func (bs *BlockState) deleteDedupedBlockBody(hash common.Hash) error {
+	hashes, err := bs.bodyDB.Get(blockBodyHashesKey(hash))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i+32 <= len(hashes); i += 32 {
+		err = bs.releaseExtrinsic(common.BytesToHash(hashes[i : i+32]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return bs.bodyDB.Del(blockBodyHashesKey(hash))
+}
+
+func (bs *BlockState) getExtrinsicRefs(hash common.Hash) (uint64, error) {
+	if has, _ := bs.bodyDB.Has(extrinsicRefKey(hash)); !has {
+		return 0, nil
+	}
+
+	refs, err := bs.bodyDB.Get(extrinsicRefKey(hash))
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(refs), nil
+}
+
+func (bs *BlockState) setExtrinsicRefs(hash common.Hash, refs uint64) error {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, refs)
+	return bs.bodyDB.Put(extrinsicRefKey(hash), buf)
+}
+
+// retainExtrinsic stores the extrinsic if it isn't already stored and increments its reference count
+func (bs *BlockState) retainExtrinsic(hash common.Hash, ext types.Extrinsic) error {
+	refs, err := bs.getExtrinsicRefs(hash)
+	if err != nil {
+		return err
+	}
+
+	if refs == 0 {
+		err = bs.bodyDB.Put(extrinsicKey(hash), ext)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bs.setExtrinsicRefs(hash, refs+1)
+}
+
+// releaseExtrinsic decrements the reference count of the extrinsic, deleting it once it is no longer referenced
+func (bs *BlockState) releaseExtrinsic(hash common.Hash) error {
+	refs, err := bs.getExtrinsicRefs(hash)
+	if err != nil {
+		return err
+	}
+
+	if refs > 1 {
+		return bs.setExtrinsicRefs(hash, refs-1)
+	}
+
+	err = bs.bodyDB.Del(extrinsicKey(hash))
+	if err != nil {
+		return err
+	}
+
+	return bs.bodyDB.Del(extrinsicRefKey(hash))
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/ChainSafe/chaindb"
+)
+
+// In header-only mode the block state stores headers and justifications, but not block bodies. Bodies are
+// fetched from peers when a block is requested, verified against the extrinsics root of the header, and kept
+// in a least recently used cache.
+
+// bodyCacheSize is the maximum number of fetched block bodies held in memory
+const bodyCacheSize = 256
+
+// ErrBodyMismatch is returned when a fetched block body doesn't match the extrinsics root of the header
+var ErrBodyMismatch = errors.New("block body does not match extrinsics root")
+
+// BodyFetcher retrieves block bodies that aren't stored locally
+type BodyFetcher interface {
+	RequestBlockBody(hash common.Hash) (*types.Body, error)
+}
+
+// SetHeadersOnly sets whether only block headers are stored. It should be called before any blocks are added.
+func (bs *BlockState) SetHeadersOnly(headersOnly bool) {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	bs.headersOnly = headersOnly
+	if headersOnly && bs.bodyCache == nil {
+		bs.bodyCache = newBodyCache(bodyCacheSize)
+	}
+}
+
+// HeadersOnly returns true if block bodies aren't stored
+func (bs *BlockState) HeadersOnly() bool {
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+	return bs.headersOnly
+}
+
+// SetBodyFetcher sets the fetcher used to retrieve block bodies in header-only mode
+func (bs *BlockState) SetBodyFetcher(fetcher BodyFetcher) {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	bs.bodyFetcher = fetcher
+}
+
+// storeBlockBody stores the body of a block, unless only headers are stored
+func (bs *BlockState) storeBlockBody(hash common.Hash, body *types.Body) error {
+	if bs.HeadersOnly() {
+		return nil
+	}
+
+	return bs.SetBlockBody(hash, body)
+}
+
+// fetchBlockBody requests the body of a block using the body fetcher and caches it if it matches the header
+func (bs *BlockState) fetchBlockBody(header *types.Header) (*types.Body, error) {
+	bs.lock.RLock()
+	fetcher := bs.bodyFetcher
+	bs.lock.RUnlock()
+
+	if fetcher == nil {
+		return nil, chaindb.ErrKeyNotFound
+	}
+
+	hash := header.Hash()
+	body, err := fetcher.RequestBlockBody(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch body of block %s: %w", hash, err)
+	}
+
+	err = verifyBlockBody(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	bs.bodyCache.put(hash, body)
+	return body, nil
+}
+
+// AttachBlockBody stores the body of a block whose header is already stored, after checking that it matches the
+// extrinsics root of the header. It's used to fill in the bodies missing from a chain of headers, so the body is
+// stored even in header-only mode.
+func (bs *BlockState) AttachBlockBody(hash common.Hash, body *types.Body) error {
+	header, err := bs.GetHeader(hash)
+	if err != nil {
+		return err
+	}
+
+	err = verifyBlockBody(header, body)
+	if err != nil {
+		return err
+	}
+
+	return bs.SetBlockBody(hash, body)
+}
+
+// verifyBlockBody returns ErrBodyMismatch if the body doesn't match the extrinsics root of the header
+func verifyBlockBody(header *types.Header, body *types.Body) error {
+	exts, err := body.AsExtrinsics()
+	if err != nil {
+		return err
+	}
+
+	root, err := extrinsicsRoot(exts)
+	if err != nil {
+		return err
+	}
+
+	if root != header.ExtrinsicsRoot {
+		return fmt.Errorf("%w: block %s", ErrBodyMismatch, header.Hash())
+	}
+
+	return nil
+}
+
+// extrinsicsRoot returns the root of the trie mapping the compact encoded index of each extrinsic to its encoding
+func extrinsicsRoot(exts []types.Extrinsic) (common.Hash, error) {
+	t := trie.NewEmptyTrie()
+
+	for i, ext := range exts {
+		key, err := scale.Encode(big.NewInt(int64(i)))
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		value, err := scale.Encode([]byte(ext))
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		err = t.Put(key, value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	return t.Hash()
+}
+
+type bodyCacheEntry struct {
+	hash common.Hash
+	body *types.Body
+}
+
+// bodyCache is a least recently used cache of block bodies
+type bodyCache struct {
+	sync.Mutex
+	size    int
+	entries *list.List
+	items   map[common.Hash]*list.Element
+}
+
+func newBodyCache(size int) *bodyCache {
+	return &bodyCache{
+		size:    size,
+		entries: list.New(),
+		items:   make(map[common.Hash]*list.Element),
+	}
+}
+
+// put adds a body to the cache, evicting the least recently used body if the cache is full
+func (c *bodyCache) put(hash common.Hash, body *types.Body) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, has := c.items[hash]; has {
+		elem.Value.(*bodyCacheEntry).body = body
+		c.entries.MoveToFront(elem)
+		return
+	}
+
+	c.items[hash] = c.entries.PushFront(&bodyCacheEntry{hash: hash, body: body})
+
+	if c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.items, oldest.Value.(*bodyCacheEntry).hash)
+	}
+}
+
+// get returns a copy of the cached body of the given block, or nil if it isn't cached
+func (c *bodyCache) get(hash common.Hash) *types.Body {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	elem, has := c.items[hash]
+	if !has {
+		return nil
+	}
+
+	c.entries.MoveToFront(elem)
+	body := elem.Value.(*bodyCacheEntry).body
+	cp := make(types.Body, len(*body))
+	copy(cp, *body)
+	return &cp
+}
+
+// remove removes the body of the given block from the cache
+func (c *bodyCache) remove(hash common.Hash) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, has := c.items[hash]; has {
+		c.entries.Remove(elem)
+		delete(c.items, hash)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBodyFetcher struct {
+	bodies   map[common.Hash]*types.Body
+	requests int
+}
+
+func (f *mockBodyFetcher) RequestBlockBody(hash common.Hash) (*types.Body, error) {
+	f.requests++
+	body, has := f.bodies[hash]
+	if !has {
+		return nil, errors.New("not found")
+	}
+	return body, nil
+}
+
+func TestHeadersOnly_FetchBlockBody(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	bs.SetHeadersOnly(true)
+
+	exts := []types.Extrinsic{{1, 2, 3}, {4, 5, 6}}
+	body, err := types.NewBodyFromExtrinsics(exts)
+	require.NoError(t, err)
+	root, err := extrinsicsRoot(exts)
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash:     testGenesisHeader.Hash(),
+			Number:         big.NewInt(1),
+			ExtrinsicsRoot: root,
+			Digest:         types.NewEmptyDigest(),
+		},
+		Body: body,
+	}
+	hash := block.Header.Hash()
+
+	err = bs.AddBlock(block)
+	require.NoError(t, err)
+
+	has, err := bs.HasBlockBody(hash)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	// without a fetcher the body is unavailable
+	_, err = bs.GetBlockByHash(hash)
+	require.Equal(t, chaindb.ErrKeyNotFound, err)
+
+	fetcher := &mockBodyFetcher{
+		bodies: map[common.Hash]*types.Body{hash: body},
+	}
+	bs.SetBodyFetcher(fetcher)
+
+	res, err := bs.GetBlockByHash(hash)
+	require.NoError(t, err)
+	require.Equal(t, body, res.Body)
+
+	// the body is served from the cache afterwards
+	res, err = bs.GetBlockByHash(hash)
+	require.NoError(t, err)
+	require.Equal(t, body, res.Body)
+	require.Equal(t, 1, fetcher.requests)
+
+	// deleting the block evicts the fetched body
+	err = bs.DeleteBlock(hash)
+	require.NoError(t, err)
+	require.Nil(t, bs.bodyCache.get(hash))
+}
+
+func TestHeadersOnly_FetchBlockBody_Mismatch(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	bs.SetHeadersOnly(true)
+
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: testGenesisHeader.Hash(),
+			Number:     big.NewInt(1),
+			Digest:     types.NewEmptyDigest(),
+		},
+		Body: types.NewBody([]byte{}),
+	}
+	hash := block.Header.Hash()
+
+	err := bs.AddBlock(block)
+	require.NoError(t, err)
+
+	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{{1, 2, 3}})
+	require.NoError(t, err)
+	bs.SetBodyFetcher(&mockBodyFetcher{
+		bodies: map[common.Hash]*types.Body{hash: body},
+	})
+
+	_, err = bs.GetBlockByHash(hash)
+	require.True(t, errors.Is(err, ErrBodyMismatch))
+}
+
+func TestAttachBlockBody(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	exts := []types.Extrinsic{{1, 2, 3}, {4, 5, 6}}
+	body, err := types.NewBodyFromExtrinsics(exts)
+	require.NoError(t, err)
+	root, err := extrinsicsRoot(exts)
+	require.NoError(t, err)
+
+	header := &types.Header{
+		ParentHash:     testGenesisHeader.Hash(),
+		Number:         big.NewInt(1),
+		ExtrinsicsRoot: root,
+		Digest:         types.NewEmptyDigest(),
+	}
+	hash := header.Hash()
+
+	err = bs.SetHeader(header)
+	require.NoError(t, err)
+
+	other, err := types.NewBodyFromExtrinsics([]types.Extrinsic{{7, 8, 9}})
+	require.NoError(t, err)
+	err = bs.AttachBlockBody(hash, other)
+	require.True(t, errors.Is(err, ErrBodyMismatch))
+
+	has, err := bs.HasBlockBody(hash)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	err = bs.AttachBlockBody(hash, body)
+	require.NoError(t, err)
+
+	res, err := bs.GetBlockBody(hash)
+	require.NoError(t, err)
+	require.Equal(t, body, res)
+
+	// the header must be stored
+	err = bs.AttachBlockBody(common.Hash{1}, body)
+	require.Error(t, err)
+}
+
+func TestBodyCache_Evict(t *testing.T) {
+	c := newBodyCache(2)
+	hashes := []common.Hash{{1}, {2}, {3}}
+
+	c.put(hashes[0], types.NewBody([]byte{1}))
+	c.put(hashes[1], types.NewBody([]byte{2}))
+
+	// using the first body makes the second one the least recently used
+	require.NotNil(t, c.get(hashes[0]))
+	c.put(hashes[2], types.NewBody([]byte{3}))
+
+	require.NotNil(t, c.get(hashes[0]))
+	require.Nil(t, c.get(hashes[1]))
+	require.Equal(t, types.NewBody([]byte{3}), c.get(hashes[2]))
+}
+
+func TestBodyCache_Remove(t *testing.T) {
+	c := newBodyCache(2)
+	hash := common.Hash{1}
+
+	c.put(hash, types.NewBody([]byte{1}))
+	c.remove(hash)
+	require.Nil(t, c.get(hash))
+
+	// removing a missing entry is a no-op
+	c.remove(hash)
+	require.Equal(t, 0, c.entries.Len())
+}
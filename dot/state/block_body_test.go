@@ -0,0 +1,117 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBlockBody_DeduplicatesExtrinsics(t *testing.T) {
+	bs := newTestBlockState(t, nil)
+
+	shared := types.NewExtrinsic([]byte("shared extrinsic"))
+	bodyA, err := types.NewBodyFromExtrinsics([]types.Extrinsic{shared, types.NewExtrinsic([]byte("a"))})
+	require.NoError(t, err)
+	bodyB, err := types.NewBodyFromExtrinsics([]types.Extrinsic{types.NewExtrinsic([]byte("b")), shared})
+	require.NoError(t, err)
+
+	hashA := common.Hash{0xa}
+	hashB := common.Hash{0xb}
+
+	err = bs.SetBlockBody(hashA, bodyA)
+	require.NoError(t, err)
+	err = bs.SetBlockBody(hashB, bodyB)
+	require.NoError(t, err)
+
+	refs, err := bs.getExtrinsicRefs(shared.Hash())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), refs)
+
+	res, err := bs.GetBlockBody(hashA)
+	require.NoError(t, err)
+	require.Equal(t, bodyA, res)
+
+	res, err = bs.GetBlockBody(hashB)
+	require.NoError(t, err)
+	require.Equal(t, bodyB, res)
+
+	// re-setting a body doesn't count its extrinsics twice
+	err = bs.SetBlockBody(hashA, bodyA)
+	require.NoError(t, err)
+	refs, err = bs.getExtrinsicRefs(shared.Hash())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), refs)
+
+	err = bs.DeleteBlock(hashA)
+	require.NoError(t, err)
+
+	has, err := bs.HasBlockBody(hashA)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	res, err = bs.GetBlockBody(hashB)
+	require.NoError(t, err)
+	require.Equal(t, bodyB, res)
+
+	err = bs.DeleteBlock(hashB)
+	require.NoError(t, err)
+
+	has, err = bs.bodyDB.Has(extrinsicKey(shared.Hash()))
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestSetBlockBody_NonExtrinsicBody(t *testing.T) {
+	bs := newTestBlockState(t, nil)
+
+	hash := common.Hash{0xa}
+	body := types.NewBody([]byte{0xa, 0xb, 0xc, 0xd})
+
+	err := bs.SetBlockBody(hash, body)
+	require.NoError(t, err)
+
+	has, err := bs.bodyDB.Has(blockBodyHashesKey(hash))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	res, err := bs.GetBlockBody(hash)
+	require.NoError(t, err)
+	require.Equal(t, body, res)
+}
+
+func TestDecodeBodyExtrinsics(t *testing.T) {
+	exts := []types.Extrinsic{{1, 2, 3}, {4}}
+	body, err := types.NewBodyFromExtrinsics(exts)
+	require.NoError(t, err)
+
+	res, ok := decodeBodyExtrinsics(*body)
+	require.True(t, ok)
+	require.Equal(t, exts, res)
+
+	// trailing bytes
+	_, ok = decodeBodyExtrinsics(append(*body, 0))
+	require.False(t, ok)
+
+	// length prefix longer than the body
+	_, ok = decodeBodyExtrinsics([]byte{0xfc, 1})
+	require.False(t, ok)
+}
@@ -27,7 +27,7 @@ func prefixKey(hash common.Hash, prefix []byte) []byte {
 
 // HasReceipt returns if the db contains a receipt at the given hash
 func (bs *BlockState) HasReceipt(hash common.Hash) (bool, error) {
-	return bs.db.Has(prefixKey(hash, receiptPrefix))
+	return bs.bodyDB.Has(prefixKey(hash, receiptPrefix))
 }
 
 // SetReceipt sets a Receipt in the database
@@ -35,7 +35,7 @@ func (bs *BlockState) SetReceipt(hash common.Hash, data []byte) error {
 	bs.lock.Lock()
 	defer bs.lock.Unlock()
 
-	err := bs.db.Put(prefixKey(hash, receiptPrefix), data)
+	err := bs.bodyDB.Put(prefixKey(hash, receiptPrefix), data)
 	if err != nil {
 		return err
 	}
@@ -45,7 +45,7 @@ func (bs *BlockState) SetReceipt(hash common.Hash, data []byte) error {
 
 // GetReceipt retrieves a Receipt from the database
 func (bs *BlockState) GetReceipt(hash common.Hash) ([]byte, error) {
-	data, err := bs.db.Get(prefixKey(hash, receiptPrefix))
+	data, err := bs.bodyDB.Get(prefixKey(hash, receiptPrefix))
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +55,7 @@ func (bs *BlockState) GetReceipt(hash common.Hash) ([]byte, error) {
 
 // HasMessageQueue returns if the db contains a MessageQueue at the given hash
 func (bs *BlockState) HasMessageQueue(hash common.Hash) (bool, error) {
-	return bs.db.Has(prefixKey(hash, messageQueuePrefix))
+	return bs.bodyDB.Has(prefixKey(hash, messageQueuePrefix))
 }
 
 // SetMessageQueue sets a MessageQueue in the database
@@ -63,7 +63,7 @@ func (bs *BlockState) SetMessageQueue(hash common.Hash, data []byte) error {
 	bs.lock.Lock()
 	defer bs.lock.Unlock()
 
-	err := bs.db.Put(prefixKey(hash, messageQueuePrefix), data)
+	err := bs.bodyDB.Put(prefixKey(hash, messageQueuePrefix), data)
 	if err != nil {
 		return err
 	}
@@ -73,7 +73,7 @@ func (bs *BlockState) SetMessageQueue(hash common.Hash, data []byte) error {
 
 // GetMessageQueue retrieves a MessageQueue from the database
 func (bs *BlockState) GetMessageQueue(hash common.Hash) ([]byte, error) {
-	data, err := bs.db.Get(prefixKey(hash, messageQueuePrefix))
+	data, err := bs.bodyDB.Get(prefixKey(hash, messageQueuePrefix))
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +83,7 @@ func (bs *BlockState) GetMessageQueue(hash common.Hash) ([]byte, error) {
 
 // HasJustification returns if the db contains a Justification at the given hash
 func (bs *BlockState) HasJustification(hash common.Hash) (bool, error) {
-	return bs.db.Has(prefixKey(hash, justificationPrefix))
+	return bs.justificationDB.Has(prefixKey(hash, justificationPrefix))
 }
 
 // SetJustification sets a Justification in the database
@@ -91,7 +91,7 @@ func (bs *BlockState) SetJustification(hash common.Hash, data []byte) error {
 	bs.lock.Lock()
 	defer bs.lock.Unlock()
 
-	err := bs.db.Put(prefixKey(hash, justificationPrefix), data)
+	err := bs.justificationDB.Put(prefixKey(hash, justificationPrefix), data)
 	if err != nil {
 		return err
 	}
@@ -101,10 +101,24 @@ func (bs *BlockState) SetJustification(hash common.Hash, data []byte) error {
 
 // GetJustification retrieves a Justification from the database
 func (bs *BlockState) GetJustification(hash common.Hash) ([]byte, error) {
-	data, err := bs.db.Get(prefixKey(hash, justificationPrefix))
+	data, err := bs.justificationDB.Get(prefixKey(hash, justificationPrefix))
 	if err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
+
+// SetPrevotes sets the encoded pre-votes of the round that finalized the block with the given hash in the database
+func (bs *BlockState) SetPrevotes(hash common.Hash, data []byte) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	return bs.justificationDB.Put(prefixKey(hash, prevotesPrefix), data)
+}
+
+// GetPrevotes retrieves the encoded pre-votes of the round that finalized the block with the given hash from the
+// database
+func (bs *BlockState) GetPrevotes(hash common.Hash) ([]byte, error) {
+	return bs.justificationDB.Get(prefixKey(hash, prevotesPrefix))
+}
@@ -53,12 +53,15 @@ func TestGetSet_ReceiptMessageQueue_Justification(t *testing.T) {
 		Number:         big.NewInt(1),
 		StateRoot:      stateRoot,
 		ExtrinsicsRoot: extrinsicsRoot,
-		Digest:         [][]byte{},
+		Digest:         types.NewEmptyDigest(),
 	}
 
+	optHeader, err := header.AsOptional()
+	require.NoError(t, err)
+
 	bds := []*types.BlockData{{
 		Hash:          header.Hash(),
-		Header:        header.AsOptional(),
+		Header:        optHeader,
 		Body:          types.NewBody([]byte{}).AsOptional(),
 		Receipt:       optional.NewBytes(false, nil),
 		MessageQueue:  optional.NewBytes(false, nil),
@@ -0,0 +1,127 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+var (
+	// ErrHalted is returned when adding a block past the halt block
+	ErrHalted = errors.New("block is past the halt block")
+	// ErrHaltInPast is returned when setting a halt block that's below the best block
+	ErrHaltInPast = errors.New("halt block is below the best block")
+)
+
+// SetHaltAtBlock sets the number of the last block that's imported. Blocks past it are rejected by AddBlock,
+// and the channel returned by Halted is closed once a block with the number is imported. A nil number clears it.
+func (bs *BlockState) SetHaltAtBlock(num *big.Int) error {
+	bs.haltLock.Lock()
+	defer bs.haltLock.Unlock()
+
+	if bs.haltReached {
+		return fmt.Errorf("%w: already halted at block %s", ErrHalted, bs.haltAt)
+	}
+
+	if num == nil {
+		bs.haltAt = nil
+		logger.Info("cleared halt block")
+		return nil
+	}
+
+	best, err := bs.BestBlockNumber()
+	if err != nil {
+		return err
+	}
+
+	if num.Cmp(best) < 0 {
+		return fmt.Errorf("%w: halt block %s, best block %s", ErrHaltInPast, num, best)
+	}
+
+	bs.haltAt = new(big.Int).Set(num)
+	logger.Info("set halt block", "number", num, "best block", best)
+
+	if num.Cmp(best) == 0 {
+		bs.reachHalt()
+	}
+
+	return nil
+}
+
+// HaltAtBlock returns the number of the halt block, or nil if it isn't set
+func (bs *BlockState) HaltAtBlock() *big.Int {
+	bs.haltLock.Lock()
+	defer bs.haltLock.Unlock()
+
+	if bs.haltAt == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(bs.haltAt)
+}
+
+// Halted returns a channel that's closed once the halt block is imported
+func (bs *BlockState) Halted() <-chan struct{} {
+	bs.haltLock.Lock()
+	defer bs.haltLock.Unlock()
+
+	return bs.haltCh()
+}
+
+// checkHalt returns ErrHalted if the header is past the halt block
+func (bs *BlockState) checkHalt(header *types.Header) error {
+	bs.haltLock.Lock()
+	defer bs.haltLock.Unlock()
+
+	if bs.haltAt == nil || header.Number.Cmp(bs.haltAt) <= 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: block %s, halt block %s", ErrHalted, header.Number, bs.haltAt)
+}
+
+// handleHalt closes the halted channel if the header is the halt block
+func (bs *BlockState) handleHalt(header *types.Header) {
+	bs.haltLock.Lock()
+	defer bs.haltLock.Unlock()
+
+	if bs.haltAt == nil || bs.haltReached || header.Number.Cmp(bs.haltAt) != 0 {
+		return
+	}
+
+	logger.Info("imported halt block", "number", header.Number, "hash", header.Hash())
+	bs.reachHalt()
+}
+
+// reachHalt closes the halted channel, the halt lock must be held
+func (bs *BlockState) reachHalt() {
+	bs.haltReached = true
+	close(bs.haltCh())
+}
+
+// haltCh returns the halted channel, creating it if needed. The halt lock must be held.
+func (bs *BlockState) haltCh() chan struct{} {
+	if bs.halted == nil {
+		bs.halted = make(chan struct{})
+	}
+
+	return bs.halted
+}
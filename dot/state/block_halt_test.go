@@ -0,0 +1,90 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockState_HaltAtBlock(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	err := bs.SetHaltAtBlock(big.NewInt(3))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3), bs.HaltAtBlock())
+
+	hashes := addTestChain(t, bs, bs.GenesisHash(), 1, 2, 0)
+
+	select {
+	case <-bs.Halted():
+		t.Fatal("halted before the halt block was imported")
+	default:
+	}
+
+	// the halt block is imported, and closes the halted channel
+	addTestChain(t, bs, hashes[1], 3, 1, 0)
+	select {
+	case <-bs.Halted():
+	default:
+		t.Fatal("did not halt after the halt block was imported")
+	}
+
+	// blocks past the halt block are rejected
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: bs.BestBlockHash(),
+			Number:     big.NewInt(4),
+			StateRoot:  trie.EmptyHash,
+			Digest:     types.NewEmptyDigest(),
+		},
+		Body: types.NewBody([]byte{}),
+	}
+	err = bs.AddBlock(block)
+	require.True(t, errors.Is(err, ErrHalted))
+
+	// the halt block can't be changed once it's reached
+	err = bs.SetHaltAtBlock(nil)
+	require.True(t, errors.Is(err, ErrHalted))
+}
+
+func TestBlockState_HaltAtBlock_Past(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	addTestChain(t, bs, bs.GenesisHash(), 1, 3, 0)
+
+	err := bs.SetHaltAtBlock(big.NewInt(2))
+	require.True(t, errors.Is(err, ErrHaltInPast))
+	require.Nil(t, bs.HaltAtBlock())
+
+	err = bs.SetHaltAtBlock(big.NewInt(5))
+	require.NoError(t, err)
+
+	err = bs.SetHaltAtBlock(nil)
+	require.NoError(t, err)
+	require.Nil(t, bs.HaltAtBlock())
+
+	// halting at the best block halts immediately
+	err = bs.SetHaltAtBlock(big.NewInt(3))
+	require.NoError(t, err)
+	<-bs.Halted()
+}
@@ -42,14 +42,12 @@ func TestConcurrencySetHeader(t *testing.T) {
 		go func(index int) {
 			defer pend.Done()
 
-			bs := &BlockState{
-				db: dbs[index],
-			}
+			bs := newBlockStateDB(dbs[index])
 
 			header := &types.Header{
 				Number:    big.NewInt(0),
 				StateRoot: trie.EmptyHash,
-				Digest:    [][]byte{},
+				Digest:    types.NewEmptyDigest(),
 			}
 
 			err := bs.SetHeader(header)
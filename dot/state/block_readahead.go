@@ -0,0 +1,179 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// readAheadCacheSize is the maximum number of prefetched blocks held in memory
+const readAheadCacheSize = 512
+
+// readAheadCache holds block headers and bodies that were read from the database ahead of being requested,
+// so that sequential reads during sync don't stall on database latency. Once full, the oldest entries are evicted.
+type readAheadCache struct {
+	sync.Mutex
+	size    int
+	order   []common.Hash
+	headers map[common.Hash]*types.Header
+	bodies  map[common.Hash]*types.Body
+}
+
+func newReadAheadCache(size int) *readAheadCache {
+	return &readAheadCache{
+		size:    size,
+		headers: make(map[common.Hash]*types.Header),
+		bodies:  make(map[common.Hash]*types.Body),
+	}
+}
+
+// put adds the header and body of a block to the cache; either may be nil
+func (c *readAheadCache) put(hash common.Hash, header *types.Header, body *types.Body) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	_, hasHeader := c.headers[hash]
+	_, hasBody := c.bodies[hash]
+	if !hasHeader && !hasBody {
+		c.order = append(c.order, hash)
+	}
+
+	if header != nil {
+		c.headers[hash] = header
+	}
+
+	if body != nil {
+		c.bodies[hash] = body
+	}
+
+	for len(c.order) > c.size {
+		delete(c.headers, c.order[0])
+		delete(c.bodies, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// header returns a copy of the cached header of the given block, or nil if it isn't cached
+func (c *readAheadCache) header(hash common.Hash) *types.Header {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if header, has := c.headers[hash]; has {
+		return header.DeepCopy()
+	}
+
+	return nil
+}
+
+// body returns a copy of the cached body of the given block, or nil if it isn't cached
+func (c *readAheadCache) body(hash common.Hash) *types.Body {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if body, has := c.bodies[hash]; has {
+		cp := make(types.Body, len(*body))
+		copy(cp, *body)
+		return &cp
+	}
+
+	return nil
+}
+
+// has returns true if both the header and body of the given block are cached
+func (c *readAheadCache) has(hash common.Hash) bool {
+	if c == nil {
+		return false
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	_, hasHeader := c.headers[hash]
+	_, hasBody := c.bodies[hash]
+	return hasHeader && hasBody
+}
+
+// remove evicts the given block from the cache
+func (c *readAheadCache) remove(hash common.Hash) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.headers, hash)
+	delete(c.bodies, hash)
+	for i, h := range c.order {
+		if h == hash {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// PrefetchBlocks reads the headers and bodies of the given blocks from the database in the background, in order,
+// so that subsequent calls to GetHeader and GetBlockBody for them are served from memory.
+// Blocks that aren't in the database are skipped.
+func (bs *BlockState) PrefetchBlocks(hashes []common.Hash) {
+	if bs.readAhead == nil || len(hashes) == 0 {
+		return
+	}
+
+	go func() {
+		for _, hash := range hashes {
+			bs.prefetchBlock(hash)
+		}
+	}()
+}
+
+func (bs *BlockState) prefetchBlock(hash common.Hash) {
+	if bs.readAhead.has(hash) {
+		return
+	}
+
+	header, err := bs.getHeaderFromDB(hash)
+	if err != nil {
+		return
+	}
+
+	// hold the lock until the body is cached, so that it can't be replaced in between
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+
+	body, err := bs.getBlockBodyFromDB(hash)
+	if err != nil {
+		body = nil
+	}
+
+	bs.readAhead.put(hash, header, body)
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+// latencyDB simulates a database on a slow disk, where every read waits for latency
+type latencyDB struct {
+	chaindb.Database
+	latency time.Duration
+}
+
+func (db *latencyDB) Get(key []byte) ([]byte, error) {
+	time.Sleep(db.latency)
+	return db.Database.Get(key)
+}
+
+func (db *latencyDB) Has(key []byte) (bool, error) {
+	time.Sleep(db.latency)
+	return db.Database.Has(key)
+}
+
+func addBlocksWithBodies(t require.TestingT, bs *BlockState, num int) []common.Hash {
+	hashes := make([]common.Hash, num)
+	parent := bs.BestBlockHash()
+	for i := 0; i < num; i++ {
+		block := &types.Block{
+			Header: &types.Header{
+				ParentHash: parent,
+				Number:     big.NewInt(int64(i + 1)),
+				StateRoot:  trie.EmptyHash,
+				Digest:     types.NewEmptyDigest(),
+			},
+			Body: types.NewBody([]byte{4, 8, byte(i), byte(i >> 8)}),
+		}
+
+		err := bs.AddBlock(block)
+		require.NoError(t, err)
+
+		parent = block.Header.Hash()
+		hashes[i] = parent
+	}
+
+	return hashes
+}
+
+func TestPrefetchBlocks(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	hashes := addBlocksWithBodies(t, bs, 8)
+
+	// blocks that aren't in the database are skipped
+	bs.PrefetchBlocks(append(hashes, common.Hash{1, 2, 3}))
+	require.Eventually(t, func() bool {
+		for _, hash := range hashes {
+			if !bs.readAhead.has(hash) {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, bs.readAhead.has(common.Hash{1, 2, 3}))
+
+	for i, hash := range hashes {
+		header, err := bs.GetHeader(hash)
+		require.NoError(t, err)
+		require.Equal(t, hash, header.Hash())
+
+		body, err := bs.GetBlockBody(hash)
+		require.NoError(t, err)
+		require.Equal(t, types.NewBody([]byte{4, 8, byte(i), byte(i >> 8)}), body)
+
+		// the cached header is not shared with the caller
+		header.Number = big.NewInt(100)
+		header, err = bs.GetHeader(hash)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(int64(i+1)), header.Number)
+	}
+
+	// replacing a body evicts the prefetched one
+	err := bs.SetBlockBody(hashes[0], types.NewBody([]byte{4, 4, 1}))
+	require.NoError(t, err)
+	require.False(t, bs.readAhead.has(hashes[0]))
+
+	body, err := bs.GetBlockBody(hashes[0])
+	require.NoError(t, err)
+	require.Equal(t, types.NewBody([]byte{4, 4, 1}), body)
+}
+
+func TestReadAheadCache_Evicts(t *testing.T) {
+	c := newReadAheadCache(2)
+	header := &types.Header{Number: big.NewInt(1), Digest: types.NewEmptyDigest()}
+	body := types.NewBody([]byte{1})
+
+	c.put(common.Hash{1}, header, body)
+	c.put(common.Hash{2}, header, body)
+	c.put(common.Hash{3}, header, body)
+
+	require.False(t, c.has(common.Hash{1}))
+	require.True(t, c.has(common.Hash{2}))
+	require.True(t, c.has(common.Hash{3}))
+
+	c.remove(common.Hash{2})
+	require.Nil(t, c.header(common.Hash{2}))
+	require.Nil(t, c.body(common.Hash{2}))
+	require.Equal(t, []common.Hash{{3}}, c.order)
+}
+
+// benchmarkSequentialImport reads the header and body of sequential blocks from a database with the given latency,
+// spending execTime on each block as if it were being executed, and reports the resulting blocks per second.
+func benchmarkSequentialImport(b *testing.B, latency, execTime time.Duration, readAhead bool) {
+	const numBlocks = 64
+
+	db := &latencyDB{Database: chaindb.NewMemDatabase()}
+	bs, err := NewBlockStateFromGenesis(db, testGenesisHeader)
+	require.NoError(b, err)
+
+	// measure reads that miss the column caches
+	bs.setColumnCacheSizes(map[string]int{headerColumn.name: 0, bodyColumn.name: 0})
+
+	hashes := addBlocksWithBodies(b, bs, numBlocks)
+	db.latency = latency
+
+	var elapsed time.Duration
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for _, hash := range hashes {
+			bs.readAhead.remove(hash)
+		}
+		b.StartTimer()
+
+		start := time.Now()
+		if readAhead {
+			bs.PrefetchBlocks(hashes)
+		}
+
+		for _, hash := range hashes {
+			_, err = bs.GetHeader(hash)
+			require.NoError(b, err)
+
+			_, err = bs.GetBlockBody(hash)
+			require.NoError(b, err)
+
+			time.Sleep(execTime)
+		}
+		elapsed += time.Since(start)
+	}
+
+	b.ReportMetric(float64(numBlocks*b.N)/elapsed.Seconds(), "blocks/s")
+}
+
+// the latency is that of a seek on a spinning disk
+func BenchmarkSequentialImport(b *testing.B) {
+	benchmarkSequentialImport(b, 2*time.Millisecond, 4*time.Millisecond, false)
+}
+
+func BenchmarkSequentialImport_ReadAhead(b *testing.B) {
+	benchmarkSequentialImport(b, 2*time.Millisecond, 4*time.Millisecond, true)
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/ChainSafe/chaindb"
+)
+
+var (
+	badBlocksKey  = []byte("badblocks")  // badBlocksKey -> concatenated hashes of the blocks marked as bad
+	forcedHeadKey = []byte("forcedhead") // forcedHeadKey -> hash of the block forced as the best block
+)
+
+// MarkBadBlock marks the block with the given hash as bad, so that neither it nor its descendants are ever
+// part of the best chain. The mark is persisted across restarts.
+func (bs *BlockState) MarkBadBlock(hash common.Hash) error {
+	// called once the lock is released
+	defer bs.headChanged()
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	err := bs.bt.MarkBad(hash)
+	if err != nil {
+		return err
+	}
+
+	var enc []byte
+	for _, h := range bs.bt.BadBlocks() {
+		enc = append(enc, h[:]...)
+	}
+
+	err = bs.db.Put(badBlocksKey, enc)
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("marked block as bad", "hash", hash, "best block", bs.bt.DeepestBlockHash())
+	return bs.setBestChain()
+}
+
+// IsBadBlock returns true if the block with the given hash, or one of its ancestors, has been marked as bad
+func (bs *BlockState) IsBadBlock(hash common.Hash) bool {
+	return bs.bt.IsBad(hash)
+}
+
+// BadBlocks returns the hashes of the blocks marked as bad
+func (bs *BlockState) BadBlocks() []common.Hash {
+	return bs.bt.BadBlocks()
+}
+
+// ForceBestBlock sets the block with the given hash as the best block, until it is cleared by passing the
+// zero hash. Only blocks descending from it are considered for the best chain. It is persisted across restarts.
+func (bs *BlockState) ForceBestBlock(hash common.Hash) error {
+	// called once the lock is released
+	defer bs.headChanged()
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	err := bs.bt.ForceHead(hash)
+	if err != nil {
+		return err
+	}
+
+	if hash == (common.Hash{}) {
+		err = bs.db.Del(forcedHeadKey)
+	} else {
+		err = bs.db.Put(forcedHeadKey, hash[:])
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("forced best block", "hash", hash, "best block", bs.bt.DeepestBlockHash())
+	return bs.setBestChain()
+}
+
+// ForcedBestBlock returns the hash of the block forced as the best block, or the zero hash if there is none
+func (bs *BlockState) ForcedBestBlock() common.Hash {
+	return bs.bt.ForcedHead()
+}
+
+// loadSteering restores the bad blocks and forced best block from the database
+func (bs *BlockState) loadSteering() error {
+	enc, err := bs.db.Get(badBlocksKey)
+	if err != nil && err != chaindb.ErrKeyNotFound {
+		return err
+	}
+
+	for i := 0; i+32 <= len(enc); i += 32 {
+		hash := common.BytesToHash(enc[i : i+32])
+		if err = bs.bt.MarkBad(hash); err != nil {
+			logger.Warn("failed to restore bad block", "hash", hash, "error", err)
+		}
+	}
+
+	enc, err = bs.db.Get(forcedHeadKey)
+	if err == chaindb.ErrKeyNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the forced block may have been pruned by finalization, in which case it no longer applies
+	err = bs.bt.ForceHead(common.BytesToHash(enc))
+	if err != nil {
+		logger.Warn("failed to restore forced best block", "hash", common.BytesToHash(enc), "error", err)
+	}
+
+	return nil
+}
+
+// setBestChain stores the current best block and the number to hash mapping of the chain leading to it
+func (bs *BlockState) setBestChain() error {
+	best := bs.bt.DeepestBlockHash()
+	chain, err := bs.bt.SubBlockchain(bs.bt.GenesisHash(), best)
+	if err != nil {
+		return fmt.Errorf("failed to get best chain: %w", err)
+	}
+
+	for _, hash := range chain {
+		var header *types.Header
+		header, err = bs.GetHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		err = bs.headerDB.Put(headerHashKey(header.Number.Uint64()), hash.ToBytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return bs.setBestBlockHashKey(best)
+}
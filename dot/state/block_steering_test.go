@@ -0,0 +1,98 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+func addTestChain(t *testing.T, bs *BlockState, parent common.Hash, start, depth int, digest byte) []common.Hash {
+	hashes := []common.Hash{}
+	for i := start; i < start+depth; i++ {
+		block := &types.Block{
+			Header: &types.Header{
+				ParentHash: parent,
+				Number:     big.NewInt(int64(i)),
+				StateRoot:  trie.EmptyHash,
+				Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{digest}}),
+			},
+			Body: types.NewBody([]byte{}),
+		}
+
+		// blocks of forks arrive later than blocks of the main chain
+		err := bs.AddBlockWithArrivalTime(block, uint64(i)+uint64(digest)*10)
+		require.NoError(t, err)
+		parent = block.Header.Hash()
+		hashes = append(hashes, parent)
+	}
+
+	return hashes
+}
+
+func TestBlockState_Steering_Persisted(t *testing.T) {
+	db := chaindb.NewMemDatabase()
+	bs, err := NewBlockStateFromGenesis(db, testGenesisHeader)
+	require.NoError(t, err)
+
+	main := addTestChain(t, bs, bs.GenesisHash(), 1, 4, 0)
+	fork := addTestChain(t, bs, main[0], 2, 1, 1)
+	require.Equal(t, main[3], bs.BestBlockHash())
+
+	err = bs.MarkBadBlock(main[2])
+	require.NoError(t, err)
+	require.True(t, bs.IsBadBlock(main[3]))
+	require.Equal(t, main[1], bs.BestBlockHash())
+
+	err = bs.ForceBestBlock(fork[0])
+	require.NoError(t, err)
+	require.Equal(t, fork[0], bs.BestBlockHash())
+
+	hash, err := bs.GetBlockHash(big.NewInt(2))
+	require.NoError(t, err)
+	require.Equal(t, fork[0], *hash)
+
+	best, err := LoadBestBlockHash(db)
+	require.NoError(t, err)
+	require.Equal(t, fork[0], best)
+
+	// restart from the database
+	err = bs.bt.Store()
+	require.NoError(t, err)
+
+	bt := blocktree.NewEmptyBlockTree(db)
+	err = bt.Load()
+	require.NoError(t, err)
+
+	bs, err = NewBlockState(db, bt)
+	require.NoError(t, err)
+	require.Equal(t, []common.Hash{main[2]}, bs.BadBlocks())
+	require.Equal(t, fork[0], bs.ForcedBestBlock())
+	require.Equal(t, fork[0], bs.BestBlockHash())
+
+	err = bs.ForceBestBlock(common.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, main[1], bs.BestBlockHash())
+}
@@ -36,9 +36,7 @@ var testGenesisHeader = &types.Header{
 func newTestBlockState(t *testing.T, header *types.Header) *BlockState {
 	db := chaindb.NewMemDatabase()
 	if header == nil {
-		return &BlockState{
-			db: chaindb.NewTable(db, blockPrefix),
-		}
+		return newBlockStateDB(db)
 	}
 
 	bs, err := NewBlockStateFromGenesis(db, header)
@@ -52,7 +50,7 @@ func TestSetAndGetHeader(t *testing.T) {
 	header := &types.Header{
 		Number:    big.NewInt(0),
 		StateRoot: trie.EmptyHash,
-		Digest:    [][]byte{},
+		Digest:    types.NewEmptyDigest(),
 	}
 
 	err := bs.SetHeader(header)
@@ -69,7 +67,7 @@ func TestHasHeader(t *testing.T) {
 	header := &types.Header{
 		Number:    big.NewInt(0),
 		StateRoot: trie.EmptyHash,
-		Digest:    [][]byte{},
+		Digest:    types.NewEmptyDigest(),
 	}
 
 	err := bs.SetHeader(header)
@@ -86,7 +84,7 @@ func TestGetBlockByNumber(t *testing.T) {
 	blockHeader := &types.Header{
 		ParentHash: testGenesisHeader.Hash(),
 		Number:     big.NewInt(1),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	block := &types.Block{
@@ -109,7 +107,7 @@ func TestAddBlock(t *testing.T) {
 	// Create header
 	header0 := &types.Header{
 		Number:     big.NewInt(0),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 		ParentHash: testGenesisHeader.Hash(),
 	}
 	// Create blockHash
@@ -129,7 +127,7 @@ func TestAddBlock(t *testing.T) {
 	// Create header & blockData for block 1
 	header1 := &types.Header{
 		Number:     big.NewInt(1),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 		ParentHash: blockHash0,
 	}
 	blockHash1 := header1.Hash()
@@ -175,7 +173,10 @@ func TestAddBlock(t *testing.T) {
 func TestGetSlotForBlock(t *testing.T) {
 	bs := newTestBlockState(t, testGenesisHeader)
 
-	preDigest, err := common.HexToBytes("0x0642414245c138e93dcef2efc275b72b4fa748332dc4c9f13be1125909cf90c8e9109c45da16b04bc5fdf9fe06a4f35e4ae4ed7e251ff9ee3d0d840c8237c9fb9057442dbf00f210d697a7b4959f792a81b948ff88937e30bf9709a8ab1314f71284da89a40000000000000000001100000000000000")
+	enc, err := common.HexToBytes("0x0642414245c138e93dcef2efc275b72b4fa748332dc4c9f13be1125909cf90c8e9109c45da16b04bc5fdf9fe06a4f35e4ae4ed7e251ff9ee3d0d840c8237c9fb9057442dbf00f210d697a7b4959f792a81b948ff88937e30bf9709a8ab1314f71284da89a40000000000000000001100000000000000")
+	require.NoError(t, err)
+
+	preDigest, err := types.DecodeDigestItem(enc)
 	require.NoError(t, err)
 
 	expectedSlot := uint64(0)
@@ -184,7 +185,7 @@ func TestGetSlotForBlock(t *testing.T) {
 		Header: &types.Header{
 			ParentHash: testGenesisHeader.Hash(),
 			Number:     big.NewInt(int64(1)),
-			Digest:     [][]byte{preDigest},
+			Digest:     types.NewDigest(preDigest),
 		},
 		Body: &types.Body{},
 	}
@@ -0,0 +1,320 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ChainSafe/chaindb"
+)
+
+// columnFamily is a class of block data that is stored in a table of its own, so that it can be iterated, cached
+// and pruned without touching the other classes. The state tries are stored in the storage table.
+type columnFamily struct {
+	name      string
+	prefixes  [][]byte // prefixes of the keys that belong to the column
+	cacheSize int      // default number of bytes of values cached in memory, 0 disables the cache
+}
+
+var (
+	headerColumn = &columnFamily{
+		name:      "header",
+		prefixes:  [][]byte{headerPrefix, headerHashPrefix, babeHeaderPrefix, arrivalTimePrefix},
+		cacheSize: 8 << 20,
+	}
+	bodyColumn = &columnFamily{
+		name:      "body",
+		prefixes:  [][]byte{blockBodyPrefix, blockBodyHashesPrefix, extrinsicPrefix, extrinsicRefPrefix, extrinsicIndexPrefix, receiptPrefix, messageQueuePrefix},
+		cacheSize: 32 << 20,
+	}
+	justificationColumn = &columnFamily{
+		name:      "justification",
+		prefixes:  [][]byte{justificationPrefix, prevotesPrefix},
+		cacheSize: 4 << 20,
+	}
+
+	columnFamilies = []*columnFamily{headerColumn, bodyColumn, justificationColumn}
+)
+
+// columnOf returns the column family that the given block key belongs to, or nil if it isn't in a column family
+func columnOf(key []byte) *columnFamily {
+	for _, col := range columnFamilies {
+		for _, prefix := range col.prefixes {
+			if bytes.HasPrefix(key, prefix) {
+				return col
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParseColumnCacheSizes parses a comma separated list of column=size pairs, where size is in MiB,
+// eg. header=8,body=32,justification=4, into the cache sizes in bytes of each listed column family
+func ParseColumnCacheSizes(spec string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	if spec == "" {
+		return sizes, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		kv := strings.Split(strings.TrimSpace(pair), "=")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid column cache size %q, expected column=size", pair)
+		}
+
+		found := false
+		for _, col := range columnFamilies {
+			found = found || col.name == kv[0]
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown database column %q", kv[0])
+		}
+
+		mib, err := strconv.ParseUint(kv[1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache size for database column %s: %w", kv[0], err)
+		}
+
+		sizes[kv[0]] = int(mib) << 20
+	}
+
+	return sizes, nil
+}
+
+// columnTable is the table of a column family, with a least recently used cache of its values in front of it.
+// Writes made with batches bypass the cache, so the table must only be written to with Put and Del.
+type columnTable struct {
+	chaindb.Database
+	col   *columnFamily
+	cache *valueCache
+}
+
+func newColumnTable(db chaindb.Database, col *columnFamily) *columnTable {
+	return &columnTable{
+		Database: chaindb.NewTable(db, col.name),
+		col:      col,
+		cache:    newValueCache(col.cacheSize),
+	}
+}
+
+// Get returns the value of the given key, from the cache if it's cached. A value read from the table is only
+// cached if the table wasn't written to while it was read, so that a concurrent Put or Del isn't undone.
+func (t *columnTable) Get(key []byte) ([]byte, error) {
+	if value, has := t.cache.get(key); has {
+		return value, nil
+	}
+
+	gen := t.cache.generation()
+	value, err := t.Database.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.cache.fill(key, value, gen)
+	return value, nil
+}
+
+// Has returns true if the given key is in the table
+func (t *columnTable) Has(key []byte) (bool, error) {
+	if _, has := t.cache.get(key); has {
+		return true, nil
+	}
+
+	return t.Database.Has(key)
+}
+
+// Put writes the given key and value to the table and the cache
+func (t *columnTable) Put(key, value []byte) error {
+	err := t.Database.Put(key, value)
+	if err != nil {
+		return err
+	}
+
+	t.cache.put(key, value)
+	return nil
+}
+
+// Del removes the given key from the table and the cache. The key is removed from the cache after the table, so
+// that a concurrent Get can't cache the deleted value.
+func (t *columnTable) Del(key []byte) error {
+	err := t.Database.Del(key)
+	t.cache.remove(key)
+	return err
+}
+
+type valueCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// valueCache is a least recently used cache of database values, bounded by the total size of the cached values
+type valueCache struct {
+	sync.Mutex
+	budget  int
+	used    int
+	gen     uint64 // incremented by each write, see fill
+	entries *list.List
+	items   map[string]*list.Element
+}
+
+func newValueCache(budget int) *valueCache {
+	return &valueCache{
+		budget:  budget,
+		entries: list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached value of the given key
+func (c *valueCache) get(key []byte) ([]byte, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, has := c.items[string(key)]
+	if !has {
+		return nil, false
+	}
+
+	c.entries.MoveToFront(elem)
+	value := elem.Value.(*valueCacheEntry).value
+	return append([]byte{}, value...), true
+}
+
+// put caches a copy of the value written for the given key, evicting the least recently used values until the
+// cache is within budget. Values larger than the whole budget aren't cached.
+func (c *valueCache) put(key, value []byte) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.gen++
+	c.putLocked(key, value)
+}
+
+// generation returns the number of writes made to the cache, which is passed to fill
+func (c *valueCache) generation() uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.gen
+}
+
+// fill caches a copy of the value read for the given key, unless a value was written or removed since the given
+// generation, in which case the value may be stale
+func (c *valueCache) fill(key, value []byte, gen uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.gen != gen {
+		return
+	}
+
+	c.putLocked(key, value)
+}
+
+func (c *valueCache) putLocked(key, value []byte) {
+	c.removeLocked(string(key))
+	if len(key)+len(value) > c.budget {
+		return
+	}
+
+	entry := &valueCacheEntry{key: string(key), value: append([]byte{}, value...)}
+	c.items[entry.key] = c.entries.PushFront(entry)
+	c.used += len(entry.key) + len(entry.value)
+	c.evict()
+}
+
+// remove removes the given key from the cache
+func (c *valueCache) remove(key []byte) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.gen++
+	c.removeLocked(string(key))
+}
+
+// resize changes the budget of the cache, evicting values if it shrinks
+func (c *valueCache) resize(budget int) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.budget = budget
+	c.evict()
+}
+
+func (c *valueCache) removeLocked(key string) {
+	elem, has := c.items[key]
+	if !has {
+		return
+	}
+
+	entry := c.entries.Remove(elem).(*valueCacheEntry)
+	delete(c.items, key)
+	c.used -= len(entry.key) + len(entry.value)
+}
+
+func (c *valueCache) evict() {
+	for c.used > c.budget {
+		c.removeLocked(c.entries.Back().Value.(*valueCacheEntry).key)
+	}
+}
+
+// migrateColumnFamilies moves the headers, bodies and justifications out of the block table and into the tables
+// of their column families
+func migrateColumnFamilies(db chaindb.Database) error {
+	block := chaindb.NewTable(db, blockPrefix)
+
+	// collect the keys first, since the iterator can't be used while the table is modified
+	var keys [][]byte
+	iter := block.NewIterator()
+	for iter.Next() {
+		if columnOf(iter.Key()) != nil {
+			keys = append(keys, append([]byte{}, iter.Key()...))
+		}
+	}
+	iter.Release()
+
+	tables := make(map[*columnFamily]chaindb.Database)
+	for _, col := range columnFamilies {
+		tables[col] = chaindb.NewTable(db, col.name)
+	}
+
+	for _, key := range keys {
+		value, err := block.Get(key)
+		if err != nil {
+			return err
+		}
+
+		err = tables[columnOf(key)].Put(key, value)
+		if err != nil {
+			return err
+		}
+
+		err = block.Del(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Info("moved block data into column families", "keys", len(keys))
+	return nil
+}
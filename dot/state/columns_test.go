@@ -0,0 +1,174 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueCache(t *testing.T) {
+	c := newValueCache(10)
+
+	c.put([]byte("a"), []byte("1234"))
+	c.put([]byte("b"), []byte("1234"))
+
+	// reading a makes b the least recently used value
+	value, has := c.get([]byte("a"))
+	require.True(t, has)
+	require.Equal(t, []byte("1234"), value)
+
+	c.put([]byte("c"), []byte("1234"))
+	_, has = c.get([]byte("b"))
+	require.False(t, has)
+	_, has = c.get([]byte("a"))
+	require.True(t, has)
+
+	// values larger than the budget aren't cached
+	c.put([]byte("d"), make([]byte, 10))
+	_, has = c.get([]byte("d"))
+	require.False(t, has)
+
+	c.resize(0)
+	_, has = c.get([]byte("a"))
+	require.False(t, has)
+	require.Equal(t, 0, c.used)
+}
+
+func TestValueCache_FillAfterWrite(t *testing.T) {
+	c := newValueCache(10)
+
+	// a value read before a write isn't cached
+	gen := c.generation()
+	c.remove([]byte("a"))
+	c.fill([]byte("a"), []byte("1"), gen)
+	_, has := c.get([]byte("a"))
+	require.False(t, has)
+
+	gen = c.generation()
+	c.fill([]byte("a"), []byte("1"), gen)
+	value, has := c.get([]byte("a"))
+	require.True(t, has)
+	require.Equal(t, []byte("1"), value)
+}
+
+// pausedGetDB pauses reads of the database after the value is read, until resume is closed
+type pausedGetDB struct {
+	chaindb.Database
+	read   chan struct{}
+	resume chan struct{}
+}
+
+func (db *pausedGetDB) Get(key []byte) ([]byte, error) {
+	value, err := db.Database.Get(key)
+	close(db.read)
+	<-db.resume
+	return value, err
+}
+
+func TestColumnTable_ConcurrentGetDel(t *testing.T) {
+	db := &pausedGetDB{
+		Database: chaindb.NewMemDatabase(),
+		read:     make(chan struct{}),
+		resume:   make(chan struct{}),
+	}
+	table := newColumnTable(db, &columnFamily{name: "test", cacheSize: 1 << 20})
+	key := []byte("key")
+
+	err := db.Database.Put(append([]byte("test"), key...), []byte("value"))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = table.Get(key)
+	}()
+
+	// the key is deleted after the value was read but before it's cached
+	<-db.read
+	err = table.Del(key)
+	require.NoError(t, err)
+	close(db.resume)
+	wg.Wait()
+
+	_, has := table.cache.get(key)
+	require.False(t, has)
+}
+
+func TestParseColumnCacheSizes(t *testing.T) {
+	sizes, err := ParseColumnCacheSizes("header=16, body=64")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"header": 16 << 20, "body": 64 << 20}, sizes)
+
+	_, err = ParseColumnCacheSizes("state=16")
+	require.Error(t, err)
+
+	_, err = ParseColumnCacheSizes("header")
+	require.Error(t, err)
+
+	_, err = ParseColumnCacheSizes("header=-1")
+	require.Error(t, err)
+}
+
+func TestMigrateColumnFamilies(t *testing.T) {
+	db := chaindb.NewMemDatabase()
+	block := chaindb.NewTable(db, blockPrefix)
+	hash := common.Hash{0xa}
+
+	legacy := map[string][]byte{
+		string(headerKey(hash)):                      []byte("header"),
+		string(blockBodyKey(hash)):                   []byte("body"),
+		string(prefixKey(hash, justificationPrefix)): []byte("justification"),
+		string(finalizedHashKey(0, 0)):               hash[:],
+	}
+	for k, v := range legacy {
+		err := block.Put([]byte(k), v)
+		require.NoError(t, err)
+	}
+
+	err := migrateColumnFamilies(db)
+	require.NoError(t, err)
+
+	bs := newBlockStateDB(db)
+	for k, v := range legacy {
+		col := columnOf([]byte(k))
+		if col == nil {
+			res, err := bs.db.Get([]byte(k))
+			require.NoError(t, err)
+			require.Equal(t, v, res)
+			continue
+		}
+
+		has, err := bs.db.Has([]byte(k))
+		require.NoError(t, err)
+		require.False(t, has)
+
+		res, err := chaindb.NewTable(db, col.name).Get([]byte(k))
+		require.NoError(t, err)
+		require.Equal(t, v, res)
+	}
+
+	res, err := bs.GetJustification(hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte("justification"), res)
+}
@@ -17,6 +17,9 @@
 package state
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -42,6 +45,52 @@ func LoadBestBlockHash(db database.Database) (common.Hash, error) {
 	return common.NewHash(hash), nil
 }
 
+// StoreDBVersion stores the version of the database layout at DBVersionKey
+func StoreDBVersion(db database.Database, version uint32) error {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, version)
+	return db.Put(common.DBVersionKey, buf)
+}
+
+// LoadDBVersion loads the version of the database layout stored at DBVersionKey. Databases created before
+// the version was recorded don't have one, in which case 0 is returned.
+func LoadDBVersion(db database.Database) (uint32, error) {
+	if has, _ := db.Has(common.DBVersionKey); !has {
+		return 0, nil
+	}
+
+	version, err := db.Get(common.DBVersionKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(version) != 4 {
+		return 0, fmt.Errorf("invalid database version encoding: 0x%x", version)
+	}
+
+	return binary.LittleEndian.Uint32(version), nil
+}
+
+// StoreHasher stores the name of the hash function used for trie and header hashing at HasherKey
+func StoreHasher(db database.Database, name string) error {
+	return db.Put(common.HasherKey, []byte(name))
+}
+
+// LoadHasher loads the name of the hash function used for trie and header hashing stored at HasherKey. Databases
+// created before the hash function was recorded use blake2b.
+func LoadHasher(db database.Database) (string, error) {
+	if has, _ := db.Has(common.HasherKey); !has {
+		return common.Blake2bHasher, nil
+	}
+
+	name, err := db.Get(common.HasherKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(name), nil
+}
+
 // StoreGenesisData stores the given genesis data at the known GenesisDataKey.
 func StoreGenesisData(db database.Database, gen *genesis.Data) error {
 	enc, err := scale.Encode(gen)
@@ -67,6 +116,164 @@ func LoadGenesisData(db database.Database) (*genesis.Data, error) {
 	return data.(*genesis.Data), nil
 }
 
+// StoreGenesisProperties stores the JSON encoded chain spec properties at the known GenesisPropertiesKey.
+func StoreGenesisProperties(db database.Database, props map[string]interface{}) error {
+	enc, err := json.Marshal(props)
+	if err != nil {
+		return fmt.Errorf("cannot json encode genesis properties: %s", err)
+	}
+
+	return db.Put(common.GenesisPropertiesKey, enc)
+}
+
+// LoadGenesisProperties retrieves the chain spec properties stored at the known GenesisPropertiesKey. It returns
+// empty properties for databases initialized before the properties were stored.
+func LoadGenesisProperties(db database.Database) (map[string]interface{}, error) {
+	enc, err := db.Get(common.GenesisPropertiesKey)
+	if err == database.ErrKeyNotFound {
+		return make(map[string]interface{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	props := make(map[string]interface{})
+	err = json.Unmarshal(enc, &props)
+	if err != nil {
+		return nil, err
+	}
+
+	return props, nil
+}
+
+// StoreCodeSubstitutes stores the JSON encoded code substitutes of the chain spec at the known CodeSubstitutesKey.
+func StoreCodeSubstitutes(db database.Database, subs map[string]string) error {
+	enc, err := json.Marshal(subs)
+	if err != nil {
+		return fmt.Errorf("cannot json encode code substitutes: %s", err)
+	}
+
+	return db.Put(common.CodeSubstitutesKey, enc)
+}
+
+// LoadCodeSubstitutes retrieves the code substitutes stored at the known CodeSubstitutesKey, by block hash.
+func LoadCodeSubstitutes(db database.Database) (map[common.Hash][]byte, error) {
+	enc, err := db.Get(common.CodeSubstitutesKey)
+	if err == database.ErrKeyNotFound {
+		return make(map[common.Hash][]byte), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make(map[string]string)
+	err = json.Unmarshal(enc, &subs)
+	if err != nil {
+		return nil, err
+	}
+
+	return genesis.ParseCodeSubstitutes(subs)
+}
+
+// blockRules is the JSON encoding of the bad blocks and fork blocks of the chain spec
+type blockRules struct {
+	BadBlocks  []string            `json:"badBlocks"`
+	ForkBlocks []genesis.ForkBlock `json:"forkBlocks"`
+}
+
+// StoreBlockRules stores the JSON encoded bad blocks and fork blocks of the chain spec at the known BlockRulesKey.
+func StoreBlockRules(db database.Database, badBlocks []string, forkBlocks []genesis.ForkBlock) error {
+	enc, err := json.Marshal(&blockRules{
+		BadBlocks:  badBlocks,
+		ForkBlocks: forkBlocks,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot json encode block rules: %s", err)
+	}
+
+	return db.Put(common.BlockRulesKey, enc)
+}
+
+// LoadBlockRules retrieves the bad blocks and fork blocks stored at the known BlockRulesKey.
+func LoadBlockRules(db database.Database) (*genesis.BlockRules, error) {
+	enc, err := db.Get(common.BlockRulesKey)
+	if err == database.ErrKeyNotFound {
+		return genesis.NewBlockRules(nil, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := new(blockRules)
+	err = json.Unmarshal(enc, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return genesis.NewBlockRules(rules.BadBlocks, rules.ForkBlocks)
+}
+
+// StoreLightSyncState stores the JSON encoded light sync state of the chain spec at the known LightSyncStateKey.
+func StoreLightSyncState(db database.Database, state *genesis.LightSyncState) error {
+	enc, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("cannot json encode light sync state: %s", err)
+	}
+
+	return db.Put(common.LightSyncStateKey, enc)
+}
+
+// LoadLightSyncState retrieves the light sync state stored at the known LightSyncStateKey, it returns nil if the
+// chain spec had none.
+func LoadLightSyncState(db database.Database) (*genesis.LightSyncState, error) {
+	enc, err := db.Get(common.LightSyncStateKey)
+	if err == database.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := new(genesis.LightSyncState)
+	err = json.Unmarshal(enc, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// StoreTelemetryEndpoints stores the JSON encoded telemetry endpoints of the chain spec at the known
+// TelemetryEndpointsKey.
+func StoreTelemetryEndpoints(db database.Database, endpoints []*genesis.TelemetryEndpoint) error {
+	enc, err := json.Marshal(endpoints)
+	if err != nil {
+		return fmt.Errorf("cannot json encode telemetry endpoints: %s", err)
+	}
+
+	return db.Put(common.TelemetryEndpointsKey, enc)
+}
+
+// LoadTelemetryEndpoints retrieves the telemetry endpoints stored at the known TelemetryEndpointsKey, it returns
+// nil if the chain spec had none.
+func LoadTelemetryEndpoints(db database.Database) ([]*genesis.TelemetryEndpoint, error) {
+	enc, err := db.Get(common.TelemetryEndpointsKey)
+	if err == database.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*genesis.TelemetryEndpoint
+	err = json.Unmarshal(enc, &endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
 // StoreLatestStorageHash stores the current root hash in the database at LatestStorageHashKey
 func StoreLatestStorageHash(db database.Database, t *trie.Trie) error {
 	hash, err := t.Hash()
@@ -87,9 +294,15 @@ func LoadLatestStorageHash(db database.Database) (common.Hash, error) {
 	return common.NewHash(hashbytes), nil
 }
 
-// StoreTrie encodes the entire trie and writes it to the DB
-// The key to the DB entry is the root hash of the trie
+// StoreTrie encodes the entire trie and writes it to the DB. Its child tries are written under their own roots.
 func StoreTrie(db database.Database, t *trie.Trie) error {
+	// the children are stored first, so that a stored trie always has its children
+	for _, child := range t.ChildTries() {
+		if err := StoreTrie(db, child); err != nil {
+			return err
+		}
+	}
+
 	enc, err := t.Encode()
 	if err != nil {
 		return err
@@ -107,12 +320,39 @@ func StoreTrie(db database.Database, t *trie.Trie) error {
 	return db.Put(roothash[:], enc)
 }
 
-// LoadTrie loads an encoded trie from the DB where the key is `root`
+// LoadTrie loads an encoded trie from the DB where the key is `root`, along with its child tries
 func LoadTrie(db database.Database, t *trie.Trie, root common.Hash) error {
 	enctrie, err := db.Get(root[:])
 	if err != nil {
 		return err
 	}
 
-	return t.Decode(enctrie)
+	err = t.Decode(enctrie)
+	if err != nil {
+		return err
+	}
+
+	childRoots, err := t.ChildRoots()
+	if err != nil {
+		return err
+	}
+
+	for _, childRoot := range childRoots {
+		child := trie.NewEmptyTrieWithHasher(t.Hasher())
+		err = LoadTrie(db, child, childRoot)
+		if errors.Is(err, database.ErrKeyNotFound) {
+			// databases written before child tries were stored don't have them
+			logger.Warn("child trie is missing from database", "root", root, "child root", childRoot)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load child trie %s: %w", childRoot, err)
+		}
+
+		if err = t.SetChildTrie(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
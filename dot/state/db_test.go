@@ -9,6 +9,7 @@ import (
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/stretchr/testify/require"
 
 	database "github.com/ChainSafe/chaindb"
 )
@@ -137,6 +138,84 @@ func TestStoreAndLoadGenesisData(t *testing.T) {
 	}
 }
 
+func TestStoreAndLoadGenesisProperties(t *testing.T) {
+	db := database.NewMemDatabase()
+
+	// databases initialized before the properties were stored have none
+	props, err := LoadGenesisProperties(db)
+	require.NoError(t, err)
+	require.Empty(t, props)
+
+	expected := map[string]interface{}{
+		"ss58Format":    float64(2),
+		"tokenDecimals": float64(12),
+		"tokenSymbol":   "KSM",
+	}
+
+	err = StoreGenesisProperties(db, expected)
+	require.NoError(t, err)
+
+	props, err = LoadGenesisProperties(db)
+	require.NoError(t, err)
+	require.Equal(t, expected, props)
+}
+
+func TestStoreAndLoadCodeSubstitutes(t *testing.T) {
+	db := database.NewMemDatabase()
+
+	subs, err := LoadCodeSubstitutes(db)
+	require.NoError(t, err)
+	require.Empty(t, subs)
+
+	hash := common.Hash{0x01}
+	err = StoreCodeSubstitutes(db, map[string]string{hash.String(): "0x0102"})
+	require.NoError(t, err)
+
+	subs, err = LoadCodeSubstitutes(db)
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash][]byte{hash: {1, 2}}, subs)
+}
+
+func TestStoreAndLoadLightSyncState(t *testing.T) {
+	db := database.NewMemDatabase()
+
+	state, err := LoadLightSyncState(db)
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	expected := &genesis.LightSyncState{
+		FinalizedBlockHeader: "0x01",
+		BabeEpochChanges:     "0x02",
+		GrandpaAuthoritySet:  "0x03",
+	}
+
+	err = StoreLightSyncState(db, expected)
+	require.NoError(t, err)
+
+	state, err = LoadLightSyncState(db)
+	require.NoError(t, err)
+	require.Equal(t, expected, state)
+}
+
+func TestStoreAndLoadTelemetryEndpoints(t *testing.T) {
+	db := database.NewMemDatabase()
+
+	endpoints, err := LoadTelemetryEndpoints(db)
+	require.NoError(t, err)
+	require.Nil(t, endpoints)
+
+	expected := []*genesis.TelemetryEndpoint{
+		{Address: "/dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F", Verbosity: 0},
+	}
+
+	err = StoreTelemetryEndpoints(db, expected)
+	require.NoError(t, err)
+
+	endpoints, err = LoadTelemetryEndpoints(db)
+	require.NoError(t, err)
+	require.Equal(t, expected, endpoints)
+}
+
 func TestStoreAndLoadBestBlockHash(t *testing.T) {
 	db := database.NewMemDatabase()
 	hash, _ := common.HexToHash("0x3f5a19b9e9507e05276216f3877bb289e47885f8184010c65d0e41580d3663cc")
@@ -29,6 +29,7 @@ var (
 	epochPrefix     = "epoch"
 	currentEpochKey = []byte("current")
 	epochInfoPrefix = []byte("epochinfo")
+	prunedEpochKey  = []byte("prunedepoch")
 )
 
 func epochInfoKey(epoch uint64) []byte {
@@ -137,10 +138,17 @@ func (s *EpochState) GetStartSlotForEpoch(epoch uint64) (uint64, error) {
 		return 0, errors.New("epoch in future")
 	}
 
-	slot := uint64(0)
+	// start at the first epoch whose info hasn't been pruned
+	base, slot, err := s.getPrunedEpoch()
+	if err != nil {
+		return 0, err
+	}
+
+	if epoch < base {
+		return 0, errors.New("epoch has been pruned")
+	}
 
-	// start at epoch 1
-	for i := uint64(1); i < epoch; i++ {
+	for i := base; i < epoch; i++ {
 		info, err := s.GetEpochInfo(i)
 		if err != nil {
 			return 0, err
@@ -149,5 +157,59 @@ func (s *EpochState) GetStartSlotForEpoch(epoch uint64) (uint64, error) {
 		slot += info.Duration
 	}
 
-	return slot + 1, nil
+	return slot, nil
+}
+
+// PruneEpochInfo deletes the epoch info of all epochs before the given epoch.
+// The start slot of the given epoch is kept so that start slots of later epochs can still be calculated.
+func (s *EpochState) PruneEpochInfo(epoch uint64) error {
+	base, _, err := s.getPrunedEpoch()
+	if err != nil {
+		return err
+	}
+
+	if epoch <= base {
+		return nil
+	}
+
+	start, err := s.GetStartSlotForEpoch(epoch)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint64(buf[:8], epoch)
+	binary.LittleEndian.PutUint64(buf[8:], start)
+	err = s.db.Put(prunedEpochKey, buf)
+	if err != nil {
+		return err
+	}
+
+	for i := base; i < epoch; i++ {
+		err = s.db.Del(epochInfoKey(i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPrunedEpoch returns the first epoch whose info hasn't been pruned and its start slot
+func (s *EpochState) getPrunedEpoch() (uint64, uint64, error) {
+	has, err := s.db.Has(prunedEpochKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !has {
+		return 1, 1, nil
+	}
+
+	b, err := s.db.Get(prunedEpochKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return binary.LittleEndian.Uint64(b[:8]), binary.LittleEndian.Uint64(b[8:]), nil
 }
@@ -124,3 +124,57 @@ func TestEpochState_GetStartSlotForEpoch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(501), start)
 }
+
+func TestEpochState_PruneEpochInfo(t *testing.T) {
+	s := newEpochStateFromGenesis(t)
+
+	for i := uint64(2); i < 6; i++ {
+		info := &types.EpochInfo{
+			Duration:   100,
+			FirstBlock: 200 + (i-2)*100,
+			Randomness: [32]byte{77},
+		}
+
+		err := s.SetEpochInfo(i, info)
+		require.NoError(t, err)
+	}
+
+	err := s.SetCurrentEpoch(5)
+	require.NoError(t, err)
+
+	err = s.PruneEpochInfo(3)
+	require.NoError(t, err)
+
+	for i := uint64(1); i < 3; i++ {
+		has, err := s.HasEpochInfo(i) //nolint
+		require.NoError(t, err)
+		require.False(t, has)
+	}
+
+	has, err := s.HasEpochInfo(3)
+	require.NoError(t, err)
+	require.True(t, has)
+
+	// start slots are still calculated from the pruned epochs
+	start, err := s.GetStartSlotForEpoch(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(301), start)
+
+	start, err = s.GetStartSlotForEpoch(5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(501), start)
+
+	_, err = s.GetStartSlotForEpoch(2)
+	require.Error(t, err)
+
+	// pruning an already pruned epoch is a no-op
+	err = s.PruneEpochInfo(2)
+	require.NoError(t, err)
+
+	err = s.PruneEpochInfo(4)
+	require.NoError(t, err)
+
+	start, err = s.GetStartSlotForEpoch(5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(501), start)
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// The extrinsic index maps the hash of each extrinsic of the finalized chain to the blocks that include it, so that
+// an extrinsic of any age can be looked up by its hash. It's disabled by default since it grows with the chain.
+// Finalized blocks are indexed by a background worker, which persists the last indexed block as its cursor so that
+// it resumes where it stopped after a restart or a failure.
+
+// extrinsicIndexRetryInterval is the time the worker waits before retrying after failing to index a block
+var extrinsicIndexRetryInterval = 10 * time.Second
+
+// ErrExtrinsicIndexDisabled is returned when looking up an extrinsic while the extrinsic index is disabled
+var ErrExtrinsicIndexDisabled = errors.New("extrinsic index is disabled")
+
+// ExtrinsicInclusion is a finalized block that includes an extrinsic, with the index of the extrinsic in the block body
+type ExtrinsicInclusion struct {
+	BlockHash common.Hash
+	Index     uint32
+}
+
+// extrinsicIndexKey = extrinsicIndexPrefix + extrinsic hash
+func extrinsicIndexKey(hash common.Hash) []byte {
+	return append(extrinsicIndexPrefix, hash.ToBytes()...)
+}
+
+// EnableExtrinsicIndex enables the extrinsic index. The extrinsics of the blocks finalized before it was enabled are
+// indexed by the next IndexFinalizedExtrinsics call.
+func (bs *BlockState) EnableExtrinsicIndex() {
+	bs.indexLock.Lock()
+	defer bs.indexLock.Unlock()
+	bs.indexExtrinsics = true
+}
+
+// ExtrinsicIndexEnabled returns true if the extrinsic index is enabled
+func (bs *BlockState) ExtrinsicIndexEnabled() bool {
+	bs.indexLock.Lock()
+	defer bs.indexLock.Unlock()
+	return bs.indexExtrinsics
+}
+
+// GetExtrinsicInclusions returns the finalized blocks that include the extrinsic with the given hash, oldest first.
+// It returns nil if the extrinsic isn't in a finalized block.
+func (bs *BlockState) GetExtrinsicInclusions(hash common.Hash) ([]*ExtrinsicInclusion, error) {
+	if !bs.ExtrinsicIndexEnabled() {
+		return nil, ErrExtrinsicIndexDisabled
+	}
+
+	if has, err := bs.bodyDB.Has(extrinsicIndexKey(hash)); !has || err != nil {
+		return nil, err
+	}
+
+	enc, err := bs.bodyDB.Get(extrinsicIndexKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(enc)%36 != 0 {
+		return nil, fmt.Errorf("invalid index of extrinsic %s", hash)
+	}
+
+	incs := make([]*ExtrinsicInclusion, len(enc)/36)
+	for i := range incs {
+		entry := enc[i*36 : (i+1)*36]
+		incs[i] = &ExtrinsicInclusion{
+			BlockHash: common.BytesToHash(entry[:32]),
+			Index:     binary.LittleEndian.Uint32(entry[32:]),
+		}
+	}
+
+	return incs, nil
+}
+
+// queueExtrinsicIndex wakes up the extrinsic index worker, if it isn't already woken up
+func (bs *BlockState) queueExtrinsicIndex() {
+	select {
+	case bs.indexCh <- struct{}{}:
+	default:
+	}
+}
+
+// indexExtrinsicsWorker indexes the finalized blocks whenever the finalized block changes, until closeCh is closed.
+// If indexing fails, it's retried after extrinsicIndexRetryInterval.
+func (bs *BlockState) indexExtrinsicsWorker(closeCh chan interface{}) {
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-bs.indexCh:
+		case <-retry:
+		case <-closeCh:
+			return
+		}
+
+		retry = nil
+
+		finalized, err := bs.GetFinalizedHash(0, 0)
+		if err == nil {
+			err = bs.IndexFinalizedExtrinsics(finalized)
+		}
+
+		if err != nil {
+			logger.Error("failed to index extrinsics of finalized blocks, retrying", "error", err)
+			retry = time.After(extrinsicIndexRetryInterval)
+		}
+	}
+}
+
+// IndexFinalizedExtrinsics indexes the extrinsics of the finalized blocks up to the given block that aren't indexed
+// yet. It's called by the extrinsic index worker on every finalized block once the index is enabled.
+func (bs *BlockState) IndexFinalizedExtrinsics(finalized common.Hash) error {
+	if !bs.ExtrinsicIndexEnabled() {
+		return ErrExtrinsicIndexDisabled
+	}
+
+	bs.indexRunLock.Lock()
+	defer bs.indexRunLock.Unlock()
+
+	// the index head is the last block whose extrinsics are indexed
+	last := big.NewInt(-1)
+	if has, _ := bs.db.Has(common.ExtrinsicIndexHeadKey); has {
+		head, err := bs.db.Get(common.ExtrinsicIndexHeadKey)
+		if err != nil {
+			return err
+		}
+
+		header, err := bs.GetHeader(common.BytesToHash(head))
+		if err != nil {
+			return fmt.Errorf("cannot get extrinsic index head: %w", err)
+		}
+		last = header.Number
+	}
+
+	// the finalized chain is walked through the parent hashes, since the hash stored for a block number may be a fork's
+	header, err := bs.GetHeader(finalized)
+	if err != nil {
+		return err
+	}
+
+	var hashes []common.Hash
+	for header.Number.Cmp(last) > 0 {
+		hashes = append(hashes, header.Hash())
+		if header.Number.Sign() == 0 {
+			break
+		}
+
+		header, err = bs.GetHeader(header.ParentHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hashes) > 1 {
+		logger.Info("indexing extrinsics of finalized blocks", "blocks", len(hashes), "head", finalized)
+	}
+
+	for i := len(hashes) - 1; i >= 0; i-- {
+		err = bs.indexBlockExtrinsics(hashes[i])
+		if err != nil {
+			return err
+		}
+
+		err = bs.db.Put(common.ExtrinsicIndexHeadKey, hashes[i][:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexBlockExtrinsics adds the block to the index of each of its extrinsics
+func (bs *BlockState) indexBlockExtrinsics(hash common.Hash) error {
+	// blocks synced in header-only mode don't have their bodies
+	if has, err := bs.HasBlockBody(hash); !has || err != nil {
+		return err
+	}
+
+	body, err := bs.GetBlockBody(hash)
+	if err != nil {
+		return err
+	}
+
+	// a body that isn't a list of extrinsics can't be indexed, but mustn't hold back the blocks after it
+	exts, ok := decodeBodyExtrinsics(*body)
+	if !ok {
+		logger.Debug("cannot index extrinsics of block body", "hash", hash)
+		return nil
+	}
+
+	for i, ext := range exts {
+		key := extrinsicIndexKey(ext.Hash())
+
+		var enc []byte
+		if has, _ := bs.bodyDB.Has(key); has {
+			enc, err = bs.bodyDB.Get(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		entry := make([]byte, 36)
+		copy(entry, hash[:])
+		binary.LittleEndian.PutUint32(entry[32:], uint32(i))
+
+		err = bs.bodyDB.Put(key, append(enc, entry...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
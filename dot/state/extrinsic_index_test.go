@@ -0,0 +1,124 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func addTestBlockWithExtrinsics(t *testing.T, bs *BlockState, exts ...types.Extrinsic) *types.Header {
+	best, err := bs.BestBlockHeader()
+	require.NoError(t, err)
+
+	body, err := types.NewBodyFromExtrinsics(exts)
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: best.Hash(),
+			Number:     new(big.Int).Add(best.Number, big.NewInt(1)),
+			StateRoot:  trie.EmptyHash,
+			Digest:     types.NewEmptyDigest(),
+		},
+		Body: body,
+	}
+
+	err = bs.AddBlock(block)
+	require.NoError(t, err)
+	return block.Header
+}
+
+func TestExtrinsicIndex(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	extA := types.NewExtrinsic([]byte("transfer a"))
+	extB := types.NewExtrinsic([]byte("transfer b"))
+
+	_, err := bs.GetExtrinsicInclusions(extA.Hash())
+	require.True(t, errors.Is(err, ErrExtrinsicIndexDisabled))
+
+	// the block is finalized before the index is enabled
+	first := addTestBlockWithExtrinsics(t, bs, extA)
+	err = bs.SetFinalizedHash(first.Hash(), 0, 0)
+	require.NoError(t, err)
+
+	bs.EnableExtrinsicIndex()
+	err = bs.IndexFinalizedExtrinsics(first.Hash())
+	require.NoError(t, err)
+
+	second := addTestBlockWithExtrinsics(t, bs, extB, extA)
+	incs, err := bs.GetExtrinsicInclusions(extB.Hash())
+	require.NoError(t, err)
+	require.Nil(t, incs)
+
+	err = bs.SetFinalizedHash(second.Hash(), 0, 0)
+	require.NoError(t, err)
+
+	err = bs.IndexFinalizedExtrinsics(second.Hash())
+	require.NoError(t, err)
+
+	incs, err = bs.GetExtrinsicInclusions(extB.Hash())
+	require.NoError(t, err)
+	require.Equal(t, []*ExtrinsicInclusion{{BlockHash: second.Hash(), Index: 0}}, incs)
+
+	incs, err = bs.GetExtrinsicInclusions(extA.Hash())
+	require.NoError(t, err)
+	require.Equal(t, []*ExtrinsicInclusion{
+		{BlockHash: first.Hash(), Index: 0},
+		{BlockHash: second.Hash(), Index: 1},
+	}, incs)
+
+	// indexing an already indexed block is a no-op
+	err = bs.IndexFinalizedExtrinsics(second.Hash())
+	require.NoError(t, err)
+	incs, err = bs.GetExtrinsicInclusions(extA.Hash())
+	require.NoError(t, err)
+	require.Len(t, incs, 2)
+}
+
+func TestExtrinsicIndex_Worker(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+	bs.EnableExtrinsicIndex()
+
+	closeCh := make(chan interface{})
+	defer close(closeCh)
+	go bs.indexExtrinsicsWorker(closeCh)
+
+	ext := types.NewExtrinsic([]byte("transfer"))
+	header := addTestBlockWithExtrinsics(t, bs, ext)
+	err := bs.SetFinalizedHash(header.Hash(), 0, 0)
+	require.NoError(t, err)
+
+	// the worker indexes the finalized block in the background and moves its cursor
+	require.Eventually(t, func() bool {
+		head, getErr := bs.db.Get(common.ExtrinsicIndexHeadKey)
+		return getErr == nil && common.BytesToHash(head) == header.Hash()
+	}, time.Second*5, time.Millisecond*10)
+
+	incs, err := bs.GetExtrinsicInclusions(ext.Hash())
+	require.NoError(t, err)
+	require.Equal(t, []*ExtrinsicInclusion{{BlockHash: header.Hash(), Index: 0}}, incs)
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+)
+
+// HeadState is an immutable snapshot of the state of the best or finalized block. Reads from it don't take the
+// storage lock, so that storage queries at the chain head aren't blocked by block import.
+type HeadState struct {
+	hash      common.Hash
+	number    *big.Int
+	stateRoot common.Hash
+
+	// t is the trie of the storage state, a snapshot is taken again if it's replaced
+	t *trie.Trie
+}
+
+// Hash returns the hash of the block
+func (h *HeadState) Hash() common.Hash {
+	return h.hash
+}
+
+// Number returns the number of the block
+func (h *HeadState) Number() *big.Int {
+	return new(big.Int).Set(h.number)
+}
+
+// StateRoot returns the state root of the block
+func (h *HeadState) StateRoot() common.Hash {
+	return h.stateRoot
+}
+
+// Get returns the value at the given key in the state of the block
+func (h *HeadState) Get(key []byte) ([]byte, error) {
+	return h.t.Get(key)
+}
+
+// headStates holds the snapshots of the best and finalized block states. Blocks are built and imported on copies of
+// their parent's state, and the tries of the storage state aren't modified once they're stored, so a snapshot is
+// the stored trie of the block itself. The snapshots are published in the background after block import, and the
+// updates requested while one is running are coalesced into one. Reads at the best block fall back to the storage
+// state until the snapshot of the new best block is published.
+type headStates struct {
+	best      atomic.Value // *HeadState
+	finalized atomic.Value // *HeadState
+
+	queueLock sync.Mutex
+	queued    bool // an update is waiting for the running one, it takes the snapshots of the latest blocks
+}
+
+func loadHeadState(v *atomic.Value) *HeadState {
+	h, _ := v.Load().(*HeadState)
+	return h
+}
+
+// BestHeadState returns the snapshot of the best block state, or nil if its trie isn't in memory
+func (s *StorageState) BestHeadState() *HeadState {
+	return loadHeadState(&s.heads.best)
+}
+
+// FinalizedHeadState returns the snapshot of the finalized block state, or nil if its trie isn't in memory
+func (s *StorageState) FinalizedHeadState() *HeadState {
+	return loadHeadState(&s.heads.finalized)
+}
+
+// headStateAt returns the snapshot of the block with the given state root, or of the best block if no state root is
+// provided. It returns nil if there's no such snapshot.
+func (s *StorageState) headStateAt(root *common.Hash) *HeadState {
+	if root == nil {
+		if h := s.BestHeadState(); h != nil && h.hash == s.blockState.BestBlockHash() {
+			return h
+		}
+		return nil
+	}
+
+	for _, h := range []*HeadState{s.BestHeadState(), s.FinalizedHeadState()} {
+		if h != nil && h.stateRoot == *root {
+			return h
+		}
+	}
+	return nil
+}
+
+// headStateByHash returns the snapshot of the best or finalized block with the given hash, or nil if there's none
+func (s *StorageState) headStateByHash(hash common.Hash) *HeadState {
+	for _, h := range []*HeadState{s.BestHeadState(), s.FinalizedHeadState()} {
+		if h != nil && h.hash == hash {
+			return h
+		}
+	}
+	return nil
+}
+
+// queueHeadStates updates the snapshots in the background, unless an update that hasn't started yet is already
+// queued. It's called by the block state when a block is imported or finalized, or the best block is forced.
+func (s *StorageState) queueHeadStates() {
+	s.heads.queueLock.Lock()
+	defer s.heads.queueLock.Unlock()
+
+	if s.heads.queued {
+		return
+	}
+	s.heads.queued = true
+
+	go func() {
+		s.headsLock.Lock()
+		defer s.headsLock.Unlock()
+
+		s.heads.queueLock.Lock()
+		s.heads.queued = false
+		s.heads.queueLock.Unlock()
+
+		s.takeHeadStates()
+	}()
+}
+
+// updateHeadStates takes snapshots of the best and finalized block states if the blocks or their tries have
+// been replaced, and returns once they're taken
+func (s *StorageState) updateHeadStates() {
+	// serialise the updates so that a snapshot isn't replaced by an older one
+	s.headsLock.Lock()
+	defer s.headsLock.Unlock()
+
+	s.takeHeadStates()
+}
+
+// takeHeadStates takes the snapshots, the heads lock must be held
+func (s *StorageState) takeHeadStates() {
+	best, err := s.blockState.BestBlockHeader()
+	if err != nil {
+		logger.Debug("failed to get best block for head state", "error", err)
+	} else if h := s.snapshot(loadHeadState(&s.heads.best), best); h != nil {
+		s.heads.best.Store(h)
+	}
+
+	finalized, err := s.blockState.GetFinalizedHeader(0, 0)
+	if err != nil {
+		logger.Debug("failed to get finalized block for head state", "error", err)
+		return
+	}
+
+	if h := s.snapshot(loadHeadState(&s.heads.finalized), finalized); h != nil {
+		s.heads.finalized.Store(h)
+	}
+}
+
+// snapshot returns a snapshot of the state of the block, or nil if the current snapshot is still up to date or if
+// the block's trie isn't in memory
+func (s *StorageState) snapshot(current *HeadState, header *types.Header) *HeadState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	t := s.tries[header.StateRoot]
+	if t == nil {
+		return nil
+	}
+
+	hash := header.Hash()
+	if current != nil && current.hash == hash && current.t == t {
+		return nil
+	}
+
+	return &HeadState{
+		hash:      hash,
+		number:    new(big.Int).Set(header.Number),
+		stateRoot: header.StateRoot,
+		t:         t,
+	}
+}
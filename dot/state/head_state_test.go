@@ -0,0 +1,196 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+// addTestBlockWithStorage imports a child of the parent whose state has the key set to the value, and waits for
+// the snapshots of the new best block to be taken
+func addTestBlockWithStorage(t *testing.T, s *StorageState, parent *types.Header, key, value []byte) *types.Header {
+	header := addTestBlockWithStoragePending(t, s, parent, key, value)
+	waitForHeadStates(s)
+	return header
+}
+
+// addTestBlockWithStoragePending imports a child of the parent whose state has the key set to the value
+func addTestBlockWithStoragePending(t *testing.T, s *StorageState, parent *types.Header, key,
+	value []byte) *types.Header {
+	ts, err := s.TrieStateCopy(&parent.StateRoot)
+	require.NoError(t, err)
+	require.NoError(t, ts.Set(key, value))
+
+	root, err := ts.Root()
+	require.NoError(t, err)
+	require.NoError(t, s.StoreTrie(root, ts))
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+		StateRoot:  root,
+		Digest:     types.NewEmptyDigest(),
+	}
+	err = s.blockState.AddBlock(&types.Block{Header: header, Body: types.NewBody([]byte{})})
+	require.NoError(t, err)
+	return header
+}
+
+// waitForHeadStates waits until the snapshots queued by block import are taken
+func waitForHeadStates(s *StorageState) {
+	for {
+		s.heads.queueLock.Lock()
+		queued := s.heads.queued
+		s.heads.queueLock.Unlock()
+
+		if !queued {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the update that was dequeued holds the lock until it's done
+	s.headsLock.Lock()
+	s.headsLock.Unlock() //nolint:staticcheck
+}
+
+func TestHeadState_Best(t *testing.T) {
+	storage := newTestStorageState(t)
+	key := []byte("key")
+
+	require.NotNil(t, storage.BestHeadState())
+	require.Equal(t, testGenesisHeader.Hash(), storage.BestHeadState().Hash())
+
+	b1 := addTestBlockWithStorage(t, storage, testGenesisHeader, key, []byte("1"))
+	best := storage.BestHeadState()
+	require.Equal(t, b1.Hash(), best.Hash())
+	require.Equal(t, b1.StateRoot, best.StateRoot())
+	require.Equal(t, big.NewInt(1), best.Number())
+
+	// the snapshot is the stored trie of the block, not a copy of it
+	storage.lock.RLock()
+	require.Same(t, storage.tries[b1.StateRoot], best.t)
+	storage.lock.RUnlock()
+
+	// executing a child block on a copy of the state doesn't change the snapshot
+	ts, err := storage.TrieStateCopy(&b1.StateRoot)
+	require.NoError(t, err)
+	require.NoError(t, ts.Set(key, []byte("changed")))
+
+	val, err := storage.GetStorage(nil, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	val, err = storage.GetStorageByBlockHash(b1.Hash(), key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+}
+
+func TestHeadState_Finalized(t *testing.T) {
+	storage := newTestStorageState(t)
+	key := []byte("key")
+
+	b1 := addTestBlockWithStorage(t, storage, testGenesisHeader, key, []byte("1"))
+	require.NoError(t, storage.blockState.SetFinalizedHash(b1.Hash(), 0, 0))
+	b2 := addTestBlockWithStorage(t, storage, b1, key, []byte("2"))
+
+	require.Equal(t, b2.Hash(), storage.BestHeadState().Hash())
+	finalized := storage.FinalizedHeadState()
+	require.Equal(t, b1.Hash(), finalized.Hash())
+
+	val, err := finalized.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	val, err = storage.GetStorage(&b1.StateRoot, key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	// the finalized snapshot is the same one that was taken when the block was the best block
+	storage.lock.RLock()
+	require.Same(t, storage.tries[b1.StateRoot], finalized.t)
+	storage.lock.RUnlock()
+}
+
+func TestHeadState_QueuedUpdatesAreCoalesced(t *testing.T) {
+	storage := newTestStorageState(t)
+
+	// updates queued while one is waiting are dropped, the waiting one snapshots the latest blocks
+	storage.headsLock.Lock()
+	storage.queueHeadStates()
+	storage.queueHeadStates()
+	storage.heads.queueLock.Lock()
+	require.True(t, storage.heads.queued)
+	storage.heads.queueLock.Unlock()
+
+	b1 := addTestBlockWithStoragePending(t, storage, testGenesisHeader, []byte("key"), []byte("1"))
+
+	// the best block's snapshot is stale until the update runs, so reads fall back to the storage state
+	require.Equal(t, testGenesisHeader.Hash(), storage.BestHeadState().Hash())
+	val, err := storage.GetStorage(nil, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	storage.headsLock.Unlock()
+	waitForHeadStates(storage)
+	require.Equal(t, b1.Hash(), storage.BestHeadState().Hash())
+}
+
+func TestHeadState_ReadsDontWaitForStorageLock(t *testing.T) {
+	storage := newTestStorageState(t)
+	key := []byte("key")
+	addTestBlockWithStorage(t, storage, testGenesisHeader, key, []byte("1"))
+
+	// a block import holding the storage lock doesn't block reads at the head
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	done := make(chan []byte)
+	go func() {
+		val, _ := storage.GetStorage(nil, key)
+		done <- val
+	}()
+
+	select {
+	case val := <-done:
+		require.Equal(t, []byte("1"), val)
+	case <-time.After(time.Second):
+		t.Fatal("read at the best block waited for the storage lock")
+	}
+}
+
+func TestHeadState_InsertStorage(t *testing.T) {
+	storage := newTestStorageState(t)
+
+	_, err := storage.InsertStorage([]*KeyValue{{Key: []byte("key"), Value: []byte("value")}})
+	require.NoError(t, err)
+
+	// the snapshot is taken again once the best block's trie is replaced
+	val, err := storage.BestHeadState().Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), val)
+	require.Equal(t, testGenesisHeader.Hash(), storage.BestHeadState().Hash())
+
+	_, err = storage.GetStorage(&common.Hash{1}, []byte("key"))
+	require.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// headerCacheSize is the maximum number of decoded headers held in memory
+const headerCacheSize = 4096
+
+type headerCacheEntry struct {
+	hash   common.Hash
+	header *types.Header
+	enc    []byte
+}
+
+// headerCache is a least recently used cache of decoded block headers with their hash and SCALE encoding, so
+// that headers that are read repeatedly, eg. when serving block requests and RPC calls, aren't decoded and
+// hashed again each time.
+type headerCache struct {
+	sync.Mutex
+	size    int
+	entries *list.List
+	items   map[common.Hash]*list.Element
+}
+
+func newHeaderCache(size int) *headerCache {
+	return &headerCache{
+		size:    size,
+		entries: list.New(),
+		items:   make(map[common.Hash]*list.Element),
+	}
+}
+
+// get returns a copy of the cached header with the given hash, or nil if it isn't cached
+func (c *headerCache) get(hash common.Hash) *types.Header {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	elem, has := c.items[hash]
+	if !has {
+		return nil
+	}
+
+	c.entries.MoveToFront(elem)
+	return elem.Value.(*headerCacheEntry).header.DeepCopy()
+}
+
+// encoding returns the SCALE encoding of the cached header with the given hash, or nil if it isn't cached
+func (c *headerCache) encoding(hash common.Hash) []byte {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	elem, has := c.items[hash]
+	if !has {
+		return nil
+	}
+
+	c.entries.MoveToFront(elem)
+	return append([]byte{}, elem.Value.(*headerCacheEntry).enc...)
+}
+
+// put caches a copy of the given header and its SCALE encoding, evicting the least recently used header if the
+// cache is full
+func (c *headerCache) put(header *types.Header, enc []byte) {
+	if c == nil {
+		return
+	}
+
+	entry := &headerCacheEntry{
+		hash:   header.Hash(),
+		header: header.DeepCopy(),
+		enc:    append([]byte{}, enc...),
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, has := c.items[entry.hash]; has {
+		c.entries.Remove(elem)
+	}
+	c.items[entry.hash] = c.entries.PushFront(entry)
+
+	for c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.items, oldest.Value.(*headerCacheEntry).hash)
+	}
+}
+
+// remove evicts the header with the given hash from the cache
+func (c *headerCache) remove(hash common.Hash) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, has := c.items[hash]; has {
+		c.entries.Remove(elem)
+		delete(c.items, hash)
+	}
+}
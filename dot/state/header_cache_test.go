@@ -0,0 +1,137 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderCache_Evict(t *testing.T) {
+	c := newHeaderCache(2)
+
+	headers := make([]*types.Header, 3)
+	for i := range headers {
+		headers[i] = &types.Header{
+			Number: big.NewInt(int64(i)),
+			Digest: types.NewEmptyDigest(),
+		}
+		c.put(headers[i], headers[i].MustEncode())
+
+		// the first header is used, so the second one is evicted
+		if i == 1 {
+			require.NotNil(t, c.get(headers[0].Hash()))
+		}
+	}
+
+	require.NotNil(t, c.get(headers[0].Hash()))
+	require.Nil(t, c.get(headers[1].Hash()))
+	require.NotNil(t, c.get(headers[2].Hash()))
+	require.Equal(t, headers[2].MustEncode(), c.encoding(headers[2].Hash()))
+
+	c.remove(headers[2].Hash())
+	require.Nil(t, c.get(headers[2].Hash()))
+	require.Nil(t, c.encoding(headers[2].Hash()))
+}
+
+func TestBlockState_HeaderCache(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	header := &types.Header{
+		ParentHash: testGenesisHeader.Hash(),
+		Number:     big.NewInt(1),
+		StateRoot:  trie.EmptyHash,
+		Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{1, 2, 3}}),
+	}
+
+	err := bs.AddBlock(&types.Block{
+		Header: header,
+		Body:   &types.Body{},
+	})
+	require.NoError(t, err)
+	hash := header.Hash()
+
+	// changes to a returned header don't change the cached header
+	res, err := bs.GetHeader(hash)
+	require.NoError(t, err)
+	require.Equal(t, header, res)
+	res.Number = big.NewInt(2)
+
+	res, err = bs.GetHeader(hash)
+	require.NoError(t, err)
+	require.Equal(t, header, res)
+
+	enc, err := bs.GetHeaderEncoding(hash)
+	require.NoError(t, err)
+	require.Equal(t, header.MustEncode(), enc)
+
+	// headers are read from the database once they're evicted
+	bs.headers.remove(hash)
+	enc, err = bs.GetHeaderEncoding(hash)
+	require.NoError(t, err)
+	require.Equal(t, header.MustEncode(), enc)
+
+	err = bs.DeleteBlock(hash)
+	require.NoError(t, err)
+	require.Nil(t, bs.headers.get(hash))
+
+	_, err = bs.GetHeader(hash)
+	require.Error(t, err)
+
+	_, err = bs.GetHeaderEncoding(common.Hash{})
+	require.Error(t, err)
+}
+
+func TestBlockState_HeaderCache_Prune(t *testing.T) {
+	bs := newTestBlockState(t, testGenesisHeader)
+
+	headers := make([]*types.Header, 2)
+	for i := range headers {
+		headers[i] = &types.Header{
+			ParentHash: testGenesisHeader.Hash(),
+			Number:     big.NewInt(1),
+			StateRoot:  trie.EmptyHash,
+			Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{byte(i)}}),
+		}
+
+		err := bs.AddBlock(&types.Block{
+			Header: headers[i],
+			Body:   &types.Body{},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, bs.headers.encoding(headers[i].Hash()))
+	}
+
+	// the fork that is pruned once the other block is finalized is dropped from the cache
+	err := bs.SetFinalizedHash(headers[0].Hash(), 0, 0)
+	require.NoError(t, err)
+
+	pruned := headers[1].Hash()
+	require.Nil(t, bs.headers.encoding(pruned))
+	_, err = bs.GetHeaderEncoding(pruned)
+	require.Error(t, err)
+
+	enc, err := bs.GetHeaderEncoding(headers[0].Hash())
+	require.NoError(t, err)
+	require.Equal(t, headers[0].MustEncode(), enc)
+}
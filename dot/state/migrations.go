@@ -0,0 +1,192 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/ChainSafe/chaindb"
+)
+
+// CurrentDBVersion is the version of the database layout written by this version of gossamer.
+// Whenever the layout changes, a migration that upgrades the previous version must be added to migrations.
+var CurrentDBVersion = uint32(len(migrations))
+
+// migration upgrades a database from version `version-1` to `version`
+type migration struct {
+	version     uint32
+	description string
+	migrate     func(db chaindb.Database) error
+}
+
+// migrations contains all database migrations, ordered by version
+var migrations = []*migration{
+	{
+		version:     1,
+		description: "store block bodies as lists of deduplicated extrinsics",
+		migrate:     migrateBlockBodies,
+	},
+	{
+		version:     2,
+		description: "move headers, bodies and justifications into column families",
+		migrate:     migrateColumnFamilies,
+	},
+}
+
+// pendingMigrations returns the migrations that need to be applied to a database at the given version
+func pendingMigrations(version uint32) ([]*migration, error) {
+	if version > CurrentDBVersion {
+		return nil, fmt.Errorf("database version %d is newer than the latest supported version %d", version, CurrentDBVersion)
+	}
+
+	return migrations[version:], nil
+}
+
+// runMigrations upgrades the database to CurrentDBVersion, storing the new version after each migration so
+// that an interrupted upgrade resumes where it stopped.
+func runMigrations(db chaindb.Database) error {
+	version, err := LoadDBVersion(db)
+	if err != nil {
+		return fmt.Errorf("failed to load database version: %w", err)
+	}
+
+	pending, err := pendingMigrations(version)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		logger.Info("migrating database", "version", m.version, "description", m.description)
+
+		err = m.migrate(db)
+		if err != nil {
+			return fmt.Errorf("failed to migrate database to version %d: %w", m.version, err)
+		}
+
+		err = StoreDBVersion(db, m.version)
+		if err != nil {
+			return fmt.Errorf("failed to store database version: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// backupDB copies the database directory at basepath into a sibling directory named after the given version,
+// and returns the path of the backup. The database must be closed.
+func backupDB(basepath string, version uint32) (string, error) {
+	backup := fmt.Sprintf("%s-backup-v%d", basepath, version)
+	if _, err := os.Stat(backup); err == nil {
+		return "", fmt.Errorf("database backup %s already exists", backup)
+	}
+
+	err := filepath.Walk(basepath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(basepath, path)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(backup, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dst, info.Mode())
+		}
+
+		return copyFile(path, dst, info.Mode())
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return backup, nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// migrateBlockBodies converts block bodies that were stored as-is into lists of extrinsic hashes
+func migrateBlockBodies(db chaindb.Database) error {
+	// bodies are still stored in the block table at this version
+	block := chaindb.NewTable(db, blockPrefix)
+	bs := &BlockState{
+		db:     block,
+		bodyDB: &columnTable{Database: block, cache: newValueCache(0)},
+	}
+
+	// collect the keys first, since the iterator can't be used while the table is modified
+	var hashes []common.Hash
+	iter := bs.db.NewIterator()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) == len(blockBodyPrefix)+32 && bytes.HasPrefix(key, blockBodyPrefix) {
+			hashes = append(hashes, common.BytesToHash(key[len(blockBodyPrefix):]))
+		}
+	}
+	iter.Release()
+
+	for _, hash := range hashes {
+		body, err := bs.db.Get(blockBodyKey(hash))
+		if err != nil {
+			return err
+		}
+
+		ok, err := bs.setDedupedBlockBody(hash, types.NewBody(body))
+		if err != nil {
+			return err
+		}
+
+		// bodies that aren't lists of extrinsics stay as they are
+		if !ok {
+			continue
+		}
+
+		err = bs.db.Del(blockBodyKey(hash))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/trie"
+	"github.com/ChainSafe/gossamer/lib/utils"
+
+	"github.com/ChainSafe/chaindb"
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDBVersion_Legacy(t *testing.T) {
+	db := chaindb.NewMemDatabase()
+
+	version, err := LoadDBVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), version)
+
+	err = StoreDBVersion(db, 7)
+	require.NoError(t, err)
+
+	version, err = LoadDBVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), version)
+}
+
+func TestRunMigrations_BlockBodies(t *testing.T) {
+	db := chaindb.NewMemDatabase()
+	bs := newBlockStateDB(db)
+
+	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{types.NewExtrinsic([]byte("a")), types.NewExtrinsic([]byte("b"))})
+	require.NoError(t, err)
+	invalid := []byte{0xff, 0xff}
+
+	hashA := common.Hash{0xa}
+	hashB := common.Hash{0xb}
+
+	// bodies written before extrinsics were deduplicated
+	err = bs.db.Put(blockBodyKey(hashA), *body)
+	require.NoError(t, err)
+	err = bs.db.Put(blockBodyKey(hashB), invalid)
+	require.NoError(t, err)
+
+	err = runMigrations(db)
+	require.NoError(t, err)
+
+	version, err := LoadDBVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, CurrentDBVersion, version)
+
+	has, err := bs.db.Has(blockBodyKey(hashA))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	res, err := bs.GetBlockBody(hashA)
+	require.NoError(t, err)
+	require.Equal(t, body, res)
+
+	res, err = bs.GetBlockBody(hashB)
+	require.NoError(t, err)
+	require.Equal(t, types.NewBody(invalid), res)
+
+	// running the migrations again is a no-op
+	err = runMigrations(db)
+	require.NoError(t, err)
+}
+
+func TestRunMigrations_NewerVersion(t *testing.T) {
+	db := chaindb.NewMemDatabase()
+
+	err := StoreDBVersion(db, CurrentDBVersion+1)
+	require.NoError(t, err)
+
+	err = runMigrations(db)
+	require.Error(t, err)
+}
+
+func TestService_BackupBeforeMigration(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	state := NewService(testDir, log.LvlTrace)
+
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, types.NewEmptyDigest())
+	require.NoError(t, err)
+
+	err = state.Initialize(new(genesis.Data), genesisHeader, trie.NewEmptyTrie(), firstEpochInfo)
+	require.NoError(t, err)
+
+	// downgrade the database to the layout before versioning was introduced
+	basepath, err := filepath.Abs(testDir)
+	require.NoError(t, err)
+	db, err := chaindb.NewBadgerDB(basepath)
+	require.NoError(t, err)
+	err = StoreDBVersion(db, 0)
+	require.NoError(t, err)
+	err = db.Close()
+	require.NoError(t, err)
+
+	state = NewService(testDir, log.LvlTrace)
+	state.EnableBackupBeforeMigration()
+
+	err = state.Start()
+	require.NoError(t, err)
+
+	backup := basepath + "-backup-v0"
+	defer os.RemoveAll(backup)
+
+	_, err = os.Stat(backup)
+	require.NoError(t, err)
+
+	version, err := LoadDBVersion(state.DB())
+	require.NoError(t, err)
+	require.Equal(t, CurrentDBVersion, version)
+
+	err = state.Stop()
+	require.NoError(t, err)
+
+	bdb, err := chaindb.NewBadgerDB(backup)
+	require.NoError(t, err)
+	defer bdb.Close()
+
+	version, err = LoadDBVersion(bdb)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), version)
+}
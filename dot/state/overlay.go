@@ -0,0 +1,266 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/trie"
+)
+
+// OverlayState is a runtime storage that reads the state with a given root from the storage state and keeps the
+// changes made to it in memory, so that a runtime call can be executed on a block's state without copying it nor
+// changing it. The state is only copied if the call needs it as a whole, eg. to compute the storage root or to
+// iterate over keys.
+type OverlayState struct {
+	storage *StorageState
+	root    common.Hash
+	changes map[string][]byte // a nil value is a deleted key
+	copied  *TrieState        // the copy of the state with the changes applied, once it's been needed
+	lock    sync.Mutex
+}
+
+// OverlayState returns an OverlayState for the state with the given root, or the current chain head if none is
+// provided. The state is loaded from the database if it isn't in memory.
+func (s *StorageState) OverlayState(hash *common.Hash) (*OverlayState, error) {
+	if hash == nil {
+		sr, err := s.blockState.BestBlockStateRoot()
+		if err != nil {
+			return nil, err
+		}
+		hash = &sr
+	}
+
+	if _, err := s.loadTrie(*hash); err != nil {
+		return nil, err
+	}
+
+	return &OverlayState{
+		storage: s,
+		root:    *hash,
+		changes: make(map[string][]byte),
+	}, nil
+}
+
+// copy returns the copy of the state with the changes applied, it's created on the first call. The lock must be held.
+func (o *OverlayState) copy() (*TrieState, error) {
+	if o.copied != nil {
+		return o.copied, nil
+	}
+
+	ts, err := o.storage.TrieStateCopy(&o.root)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range o.changes {
+		if v == nil {
+			err = ts.Delete([]byte(k))
+		} else {
+			err = ts.Set([]byte(k), v)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	o.copied = ts
+	o.changes = nil
+	return ts, nil
+}
+
+// Set sets a key-value pair in the overlay
+func (o *OverlayState) Set(key []byte, value []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.copied != nil {
+		return o.copied.Set(key, value)
+	}
+
+	o.changes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+// Get returns the value at the given key, from the overlay if it was changed or from the state otherwise
+func (o *OverlayState) Get(key []byte) ([]byte, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.copied != nil {
+		return o.copied.Get(key)
+	}
+
+	if v, has := o.changes[string(key)]; has {
+		return v, nil
+	}
+
+	return o.storage.GetStorage(&o.root, key)
+}
+
+// Delete deletes the given key in the overlay
+func (o *OverlayState) Delete(key []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.copied != nil {
+		return o.copied.Delete(key)
+	}
+
+	o.changes[string(key)] = nil
+	return nil
+}
+
+// Root returns the storage root of the state with the changes applied
+func (o *OverlayState) Root() (common.Hash, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return ts.Root()
+}
+
+// SetChild sets the child trie at the given key
+func (o *OverlayState) SetChild(keyToChild []byte, child *trie.Trie) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		return err
+	}
+
+	return ts.SetChild(keyToChild, child)
+}
+
+// SetChildStorage sets a key-value pair in the child trie at the given key
+func (o *OverlayState) SetChildStorage(keyToChild, key, value []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		return err
+	}
+
+	return ts.SetChildStorage(keyToChild, key, value)
+}
+
+// GetChildStorage returns the value at the given key in the child trie at the given key. Child tries are only
+// changed in the copy of the state, so they're read from the state until it's copied.
+func (o *OverlayState) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.copied != nil {
+		return o.copied.GetChildStorage(keyToChild, key)
+	}
+
+	return o.storage.GetStorageFromChild(&o.root, keyToChild, key)
+}
+
+// DeleteChildStorage deletes the child trie at the given key
+func (o *OverlayState) DeleteChildStorage(key []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		return err
+	}
+
+	return ts.DeleteChildStorage(key)
+}
+
+// ClearChildStorage deletes the given key from the child trie at the given key
+func (o *OverlayState) ClearChildStorage(keyToChild, key []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		return err
+	}
+
+	return ts.ClearChildStorage(keyToChild, key)
+}
+
+// Entries returns all the key-value pairs of the state with the changes applied
+func (o *OverlayState) Entries() map[string][]byte {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		logger.Error("failed to copy state", "root", o.root, "error", err)
+		return nil
+	}
+
+	return ts.Entries()
+}
+
+// NextKey returns the key following the given key in the state with the changes applied
+func (o *OverlayState) NextKey(key []byte) []byte {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ts, err := o.copy()
+	if err != nil {
+		logger.Error("failed to copy state", "root", o.root, "error", err)
+		return nil
+	}
+
+	return ts.NextKey(key)
+}
+
+// SetBalance sets the balance for a given public key
+func (o *OverlayState) SetBalance(key [32]byte, balance uint64) error {
+	skey, err := common.BalanceKey(key)
+	if err != nil {
+		return err
+	}
+
+	bb := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bb, balance)
+
+	return o.Set(skey, bb)
+}
+
+// GetBalance returns the balance for a given public key
+func (o *OverlayState) GetBalance(key [32]byte) (uint64, error) {
+	skey, err := common.BalanceKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	bal, err := o.Get(skey)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(bal) != 8 {
+		return 0, nil
+	}
+
+	return binary.LittleEndian.Uint64(bal), nil
+}
@@ -17,12 +17,14 @@
 package state
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
@@ -32,11 +34,24 @@ import (
 
 var logger = log.New("pkg", "state")
 
+// ErrHasherMismatch is returned when starting a database initialized with another hash function than the configured one
+var ErrHasherMismatch = errors.New("hasher does not match the database")
+
 // Service is the struct that holds storage, block and network states
 type Service struct {
 	dbPath      string
 	db          chaindb.Database
 	isMemDB     bool // set to true if using an in-memory database; only used for testing.
+	backupDB    bool // set to true to back up the database before it's migrated
+	txIndex     bool // set to true to index the extrinsics of the finalized blocks
+	hasher      string
+	cacheSizes  map[string]int
+	properties  map[string]interface{}
+	codeSubs    map[string]string
+	badBlocks   []string
+	forkBlocks  []genesis.ForkBlock
+	syncState   *genesis.LightSyncState
+	telemetry   []*genesis.TelemetryEndpoint
 	Storage     *StorageState
 	Block       *BlockState
 	Network     *NetworkState
@@ -69,6 +84,69 @@ func (s *Service) UseMemDB() {
 	s.isMemDB = true
 }
 
+// EnableBackupBeforeMigration tells the service to copy the database into a backup directory before
+// upgrading it to a newer layout. This should be called after NewService, and before Start.
+func (s *Service) EnableBackupBeforeMigration() {
+	s.backupDB = true
+}
+
+// EnableExtrinsicIndex tells the service to index the extrinsics of all the finalized blocks by their hash, see
+// BlockState.GetExtrinsicInclusions. This should be called after NewService, and before Start.
+func (s *Service) EnableExtrinsicIndex() {
+	s.txIndex = true
+}
+
+// SetHasher sets the name of the hash function used for trie and header hashing. It's stored in the database when
+// it's initialized, and a database initialized with another hash function fails to start. If it isn't set, the
+// database is initialized with blake2b, and started with the hash function it was initialized with.
+// This should be called after NewService, and before Initialize or Start.
+func (s *Service) SetHasher(name string) error {
+	_, err := common.GetHasher(name)
+	if err != nil {
+		return err
+	}
+
+	s.hasher = name
+	return nil
+}
+
+// SetColumnCacheSizes sets the cache sizes in bytes of the database column families, see ParseColumnCacheSizes.
+// This should be called after NewService, and before Start.
+func (s *Service) SetColumnCacheSizes(sizes map[string]int) {
+	s.cacheSizes = sizes
+}
+
+// SetGenesisProperties sets the chain spec properties that are stored with the genesis data.
+// This should be called after NewService, and before Initialize.
+func (s *Service) SetGenesisProperties(props map[string]interface{}) {
+	s.properties = props
+}
+
+// SetCodeSubstitutes sets the code substitutes of the chain spec that are stored with the genesis data.
+// This should be called after NewService, and before Initialize.
+func (s *Service) SetCodeSubstitutes(subs map[string]string) {
+	s.codeSubs = subs
+}
+
+// SetBlockRules sets the bad blocks and fork blocks of the chain spec that are stored with the genesis data.
+// This should be called after NewService, and before Initialize.
+func (s *Service) SetBlockRules(badBlocks []string, forkBlocks []genesis.ForkBlock) {
+	s.badBlocks = badBlocks
+	s.forkBlocks = forkBlocks
+}
+
+// SetLightSyncState sets the light sync state of the chain spec that is stored with the genesis data.
+// This should be called after NewService, and before Initialize.
+func (s *Service) SetLightSyncState(state *genesis.LightSyncState) {
+	s.syncState = state
+}
+
+// SetTelemetryEndpoints sets the telemetry endpoints of the chain spec that are stored with the genesis data.
+// This should be called after NewService, and before Initialize.
+func (s *Service) SetTelemetryEndpoints(endpoints []*genesis.TelemetryEndpoint) {
+	s.telemetry = endpoints
+}
+
 // DB returns the Service's database
 func (s *Service) DB() chaindb.Database {
 	return s.db
@@ -76,7 +154,8 @@ func (s *Service) DB() chaindb.Database {
 
 // Initialize initializes the genesis state of the DB using the given storage trie. The trie should be loaded with the genesis storage state.
 // This only needs to be called during genesis initialization of the node; it doesn't need to be called during normal startup.
-func (s *Service) Initialize(data *genesis.Data, header *types.Header, t *trie.Trie, epochInfo *types.EpochInfo) error {
+func (s *Service) Initialize(data *genesis.Data, header *types.Header, t *trie.Trie,
+	epochInfo *types.EpochInfo) (err error) {
 	var db chaindb.Database
 
 	// check database type
@@ -88,7 +167,8 @@ func (s *Service) Initialize(data *genesis.Data, header *types.Header, t *trie.T
 	} else {
 
 		// get data directory from service
-		basepath, err := filepath.Abs(s.dbPath)
+		var basepath string
+		basepath, err = filepath.Abs(s.dbPath)
 		if err != nil {
 			return fmt.Errorf("failed to read basepath: %s", err)
 		}
@@ -98,10 +178,18 @@ func (s *Service) Initialize(data *genesis.Data, header *types.Header, t *trie.T
 		if err != nil {
 			return fmt.Errorf("failed to create database: %s", err)
 		}
+
+		// the database is only used to write the genesis state, it's closed whether that succeeds or not
+		defer func() {
+			closeErr := db.Close()
+			if closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to close database: %s", closeErr)
+			}
+		}()
 	}
 
 	// write initial genesis values to database
-	err := s.storeInitialValues(db, data, header, t)
+	err = s.storeInitialValues(db, data, header, t)
 	if err != nil {
 		return fmt.Errorf("failed to write genesis values to database: %s", err)
 	}
@@ -140,13 +228,6 @@ func (s *Service) Initialize(data *genesis.Data, header *types.Header, t *trie.T
 		s.Storage = storageState
 		s.Block = blockState
 		s.Epoch = epochState
-	} else {
-
-		// close database
-		err = db.Close()
-		if err != nil {
-			return fmt.Errorf("failed to close database: %s", err)
-		}
 	}
 
 	return nil
@@ -167,6 +248,16 @@ func (s *Service) storeInitialValues(db chaindb.Database, data *genesis.Data, he
 		return fmt.Errorf("failed to write storage hash to database: %s", err)
 	}
 
+	hasher := s.hasher
+	if hasher == "" {
+		hasher = common.Blake2bHasher
+	}
+
+	err = StoreHasher(db, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to write hasher to database: %s", err)
+	}
+
 	// write best block hash to state database
 	err = StoreBestBlockHash(db, header.Hash())
 	if err != nil {
@@ -179,6 +270,47 @@ func (s *Service) storeInitialValues(db chaindb.Database, data *genesis.Data, he
 		return fmt.Errorf("failed to write genesis data to database: %s", err)
 	}
 
+	if s.properties != nil {
+		err = StoreGenesisProperties(db, s.properties)
+		if err != nil {
+			return fmt.Errorf("failed to write genesis properties to database: %s", err)
+		}
+	}
+
+	if len(s.codeSubs) > 0 {
+		err = StoreCodeSubstitutes(db, s.codeSubs)
+		if err != nil {
+			return fmt.Errorf("failed to write code substitutes to database: %s", err)
+		}
+	}
+
+	if len(s.badBlocks) > 0 || len(s.forkBlocks) > 0 {
+		err = StoreBlockRules(db, s.badBlocks, s.forkBlocks)
+		if err != nil {
+			return fmt.Errorf("failed to write block rules to database: %s", err)
+		}
+	}
+
+	if s.syncState != nil {
+		err = StoreLightSyncState(db, s.syncState)
+		if err != nil {
+			return fmt.Errorf("failed to write light sync state to database: %s", err)
+		}
+	}
+
+	if len(s.telemetry) > 0 {
+		err = StoreTelemetryEndpoints(db, s.telemetry)
+		if err != nil {
+			return fmt.Errorf("failed to write telemetry endpoints to database: %s", err)
+		}
+	}
+
+	// a new database always uses the latest layout
+	err = StoreDBVersion(db, CurrentDBVersion)
+	if err != nil {
+		return fmt.Errorf("failed to write database version to database: %s", err)
+	}
+
 	return nil
 }
 
@@ -196,7 +328,7 @@ func (s *Service) Start() error {
 		}
 
 		// initialize database
-		db, err = chaindb.NewBadgerDB(basepath)
+		db, err = s.openDB(basepath)
 		if err != nil {
 			return err
 		}
@@ -204,6 +336,31 @@ func (s *Service) Start() error {
 		s.db = db
 	}
 
+	err := s.start(db)
+	if err != nil && !s.isMemDB {
+		// the database was opened above, it's closed so that its handle and lock aren't leaked
+		s.Block, s.Storage, s.db = nil, nil, nil
+		if closeErr := db.Close(); closeErr != nil {
+			logger.Error("failed to close database", "error", closeErr)
+		}
+	}
+
+	return err
+}
+
+// start loads the state services from the given database
+func (s *Service) start(db chaindb.Database) error {
+	// upgrade the database if it was written by an older version
+	err := runMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	hash, err := s.loadHasher(db)
+	if err != nil {
+		return err
+	}
+
 	// retrieve latest header
 	bestHash, err := LoadBestBlockHash(db)
 	if err != nil {
@@ -225,13 +382,15 @@ func (s *Service) Start() error {
 		return fmt.Errorf("failed to create block state: %s", err)
 	}
 
+	s.Block.setColumnCacheSizes(s.cacheSizes)
+
 	// create storage state
-	s.Storage, err = NewStorageState(db, s.Block, trie.NewEmptyTrie())
+	s.Storage, err = NewStorageState(db, s.Block, trie.NewEmptyTrieWithHasher(hash))
 	if err != nil {
 		return fmt.Errorf("failed to create storage state: %s", err)
 	}
 
-	stateRoot, err := LoadLatestStorageHash(s.db)
+	stateRoot, err := LoadLatestStorageHash(db)
 	if err != nil {
 		return fmt.Errorf("cannot load latest storage root: %s", err)
 	}
@@ -243,6 +402,7 @@ func (s *Service) Start() error {
 	if err != nil {
 		return fmt.Errorf("failed to get state root from database: %s", err)
 	}
+	s.Storage.updateHeadStates()
 
 	// create network state
 	s.Network = NewNetworkState()
@@ -253,11 +413,81 @@ func (s *Service) Start() error {
 	// create epoch state
 	s.Epoch = NewEpochState(db)
 
+	// the blocks finalized while the index was disabled are indexed in the background
+	if s.txIndex {
+		s.Block.EnableExtrinsicIndex()
+		go s.Block.indexExtrinsicsWorker(s.closeCh)
+		s.Block.queueExtrinsicIndex()
+	}
+
 	// Start background goroutine to GC pruned keys.
 	go s.Storage.pruneStorage(s.closeCh)
 	return nil
 }
 
+// loadHasher returns the hash function the database was initialized with, and makes it the default hash function
+// so that headers are hashed with it
+func (s *Service) loadHasher(db chaindb.Database) (common.HashFunc, error) {
+	name, err := LoadHasher(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hasher: %w", err)
+	}
+
+	if s.hasher != "" && s.hasher != name {
+		return nil, fmt.Errorf("%w: database uses %s, configured %s", ErrHasherMismatch, name, s.hasher)
+	}
+
+	err = common.SetDefaultHasher(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.GetHasher(name)
+}
+
+// openDB opens the database at basepath. If backups are enabled and the database needs to be migrated,
+// a copy of it is made before it's returned.
+func (s *Service) openDB(basepath string) (chaindb.Database, error) {
+	db, err := chaindb.NewBadgerDB(basepath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.backupDB {
+		return db, nil
+	}
+
+	version, err := LoadDBVersion(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load database version: %w", err)
+	}
+
+	pending, err := pendingMigrations(version)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return db, nil
+	}
+
+	// the database files can only be copied safely while the database is closed
+	err = db.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := backupDB(basepath, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	logger.Info("backed up database before migration", "path", backup, "version", version)
+	return chaindb.NewBadgerDB(basepath)
+}
+
 // Stop closes each state database
 func (s *Service) Stop() error {
 	head, err := s.Block.BestBlockStateRoot()
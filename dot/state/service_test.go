@@ -17,6 +17,7 @@
 package state
 
 import (
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
@@ -54,7 +55,7 @@ func TestService_Start(t *testing.T) {
 	state := newTestService(t)
 	defer utils.RemoveTestDir(t)
 
-	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, [][]byte{})
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, types.NewEmptyDigest())
 	require.NoError(t, err)
 
 	tr := trie.NewEmptyTrie()
@@ -71,10 +72,26 @@ func TestService_Start(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_Start_ClosesDBOnError(t *testing.T) {
+	state := newTestService(t)
+	defer utils.RemoveTestDir(t)
+
+	// the database was never initialized, so the state can't be loaded from it
+	err := state.Start()
+	require.Error(t, err)
+	require.Nil(t, state.Block)
+	require.Nil(t, state.Storage)
+
+	// the database lock was released, so a second attempt fails the same way
+	err2 := state.Start()
+	require.Error(t, err2)
+	require.Equal(t, err.Error(), err2.Error())
+}
+
 func TestMemDB_Start(t *testing.T) {
 	state := newTestMemDBService()
 
-	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, [][]byte{})
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, types.NewEmptyDigest())
 	require.NoError(t, err)
 
 	tr := trie.NewEmptyTrie()
@@ -91,6 +108,92 @@ func TestMemDB_Start(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_ChildTriesPersisted(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	child := trie.NewEmptyTrie()
+	err := child.Put([]byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	tr := trie.NewEmptyTrie()
+	err = tr.PutChild([]byte("default"), child)
+	require.NoError(t, err)
+
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), tr.MustHash(), trie.EmptyHash,
+		types.NewEmptyDigest())
+	require.NoError(t, err)
+
+	state := NewService(testDir, log.LvlTrace)
+	err = state.Initialize(new(genesis.Data), genesisHeader, tr, firstEpochInfo)
+	require.NoError(t, err)
+	err = state.Start()
+	require.NoError(t, err)
+	err = state.Stop()
+	require.NoError(t, err)
+
+	// the child trie is loaded along with the trie that holds it after a restart
+	state = NewService(testDir, log.LvlTrace)
+	err = state.Start()
+	require.NoError(t, err)
+	defer state.Stop()
+
+	loaded, err := state.Storage.LoadFromDB(genesisHeader.StateRoot)
+	require.NoError(t, err)
+
+	val, err := loaded.GetFromChild([]byte("default"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), val)
+}
+
+func TestService_Hasher(t *testing.T) {
+	testDir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	// headers are hashed with the hasher of the database once it's started
+	defer func() {
+		require.NoError(t, common.SetDefaultHasher(common.Blake2bHasher))
+	}()
+	require.NoError(t, common.SetDefaultHasher(common.KeccakHasher))
+
+	tr := trie.NewEmptyTrieWithHasher(common.Keccak256)
+	err := tr.Put([]byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), tr.MustHash(), trie.EmptyHash,
+		types.NewEmptyDigest())
+	require.NoError(t, err)
+
+	state := NewService(testDir, log.LvlTrace)
+	err = state.SetHasher(common.KeccakHasher)
+	require.NoError(t, err)
+	err = state.Initialize(new(genesis.Data), genesisHeader, tr, firstEpochInfo)
+	require.NoError(t, err)
+
+	// the database is started with the hasher it was initialized with
+	require.NoError(t, common.SetDefaultHasher(common.Blake2bHasher))
+	state = NewService(testDir, log.LvlTrace)
+	err = state.Start()
+	require.NoError(t, err)
+
+	loaded, err := state.Storage.LoadFromDB(genesisHeader.StateRoot)
+	require.NoError(t, err)
+	require.Equal(t, genesisHeader.StateRoot, loaded.MustHash())
+	require.Equal(t, genesisHeader.Hash(), state.Block.BestBlockHash())
+
+	err = state.Stop()
+	require.NoError(t, err)
+
+	state = NewService(testDir, log.LvlTrace)
+	err = state.SetHasher(common.Blake2bHasher)
+	require.NoError(t, err)
+	err = state.Start()
+	require.True(t, errors.Is(err, ErrHasherMismatch))
+
+	err = state.SetHasher("sha256")
+	require.True(t, errors.Is(err, common.ErrUnknownHasher))
+}
+
 func TestService_BlockTree(t *testing.T) {
 	testDir := utils.NewTestDir(t)
 
@@ -99,7 +202,7 @@ func TestService_BlockTree(t *testing.T) {
 
 	stateA := NewService(testDir, log.LvlTrace)
 
-	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, [][]byte{})
+	genesisHeader, err := types.NewHeader(common.NewHash([]byte{0}), big.NewInt(0), trie.EmptyHash, trie.EmptyHash, types.NewEmptyDigest())
 	require.NoError(t, err)
 
 	genesisData := new(genesis.Data)
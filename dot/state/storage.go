@@ -43,11 +43,22 @@ func errTrieDoesNotExist(hash common.Hash) error {
 type StorageState struct {
 	blockState *BlockState
 	tries      map[common.Hash]*trie.Trie
+	hash       common.HashFunc // the hash function of the tries
+
+	// approximate memory held by each trie, updated when the trie is stored so that it isn't walked when reported
+	trieSizes map[common.Hash]uint64
 
 	baseDB chaindb.Database
 	db     chaindb.Database
 	lock   sync.RWMutex
 
+	// held while a block is built or imported, see Lock
+	blockLock sync.Mutex
+
+	// snapshots of the best and finalized block states, see head_state.go
+	heads     headStates
+	headsLock sync.Mutex
+
 	// change notifiers
 	changed     map[byte]chan<- *KeyValue
 	changedLock sync.RWMutex
@@ -63,16 +74,23 @@ func NewStorageState(db chaindb.Database, blockState *BlockState, t *trie.Trie)
 		return nil, fmt.Errorf("cannot have nil trie")
 	}
 
-	tries := make(map[common.Hash]*trie.Trie)
-	tries[t.MustHash()] = t
-
-	return &StorageState{
+	s := &StorageState{
 		blockState: blockState,
-		tries:      tries,
+		tries:      make(map[common.Hash]*trie.Trie),
+		trieSizes:  make(map[common.Hash]uint64),
+		hash:       t.Hasher(),
 		baseDB:     db,
 		db:         chaindb.NewTable(db, storagePrefix),
 		changed:    make(map[byte]chan<- *KeyValue),
-	}, nil
+	}
+	s.setTrie(t.MustHash(), t)
+
+	if blockState != nil {
+		blockState.onHeadChange = s.queueHeadStates
+		s.updateHeadStates()
+	}
+
+	return s, nil
 }
 
 func (s *StorageState) pruneKey(keyHeader *types.Header) {
@@ -87,19 +105,40 @@ func (s *StorageState) pruneKey(keyHeader *types.Header) {
 	dbKey, _ := tr.Hash()
 	_ = s.baseDB.Del(dbKey[:])
 	delete(s.tries, keyHeader.StateRoot)
+	delete(s.trieSizes, keyHeader.StateRoot)
+}
+
+// setTrie sets the trie stored under the given root, s.lock must be held
+func (s *StorageState) setTrie(root common.Hash, t *trie.Trie) {
+	s.tries[root] = t
+	s.trieSizes[root] = t.MemoryUsage()
+}
+
+// Lock is held while a block is built or imported, from getting the state of its parent until its trie is stored and
+// the block is added, so that a block is always authored on top of the latest imported block. Blocks are built and
+// imported on a TrieStateCopy of their parent's state, so that the parent's state isn't changed. It doesn't block
+// reads of the storage.
+func (s *StorageState) Lock() {
+	s.blockLock.Lock()
+}
+
+// Unlock releases the lock taken by Lock
+func (s *StorageState) Unlock() {
+	s.blockLock.Unlock()
 }
 
 // StoreTrie stores the given trie in the StorageState and writes it to the database
 func (s *StorageState) StoreTrie(root common.Hash, ts *TrieState) error {
 	// TODO: commit and free TrieState
 
-	s.lock.Lock()
-	var err error
 	// make copy of trie since ts.Free will clear the TrieState
-	s.tries[root], err = ts.t.DeepCopy()
+	t, err := ts.t.DeepCopy()
 	if err != nil {
 		return err
 	}
+
+	s.lock.Lock()
+	s.setTrie(root, t)
 	s.lock.Unlock()
 
 	logger.Debug("stored trie in storage state", "root", root)
@@ -127,6 +166,32 @@ func (s *StorageState) TrieState(hash *common.Hash) (*TrieState, error) {
 	return NewTrieState(s.baseDB, s.tries[*hash])
 }
 
+// TrieStateCopy returns a TrieState for the given state root, or the current chain head if none is provided, that is
+// backed by a copy of the trie and an in-memory database, so that changes made to it are discarded
+func (s *StorageState) TrieStateCopy(hash *common.Hash) (*TrieState, error) {
+	if hash == nil {
+		sr, err := s.blockState.BestBlockStateRoot()
+		if err != nil {
+			return nil, err
+		}
+		hash = &sr
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.tries[*hash] == nil {
+		return nil, errTrieDoesNotExist(*hash)
+	}
+
+	t, err := s.tries[*hash].DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTrieState(chaindb.NewMemDatabase(), t)
+}
+
 // StoreInDB encodes the entire trie and writes it to the DB
 // The key to the DB entry is the root hash of the trie
 func (s *StorageState) StoreInDB(root common.Hash) error {
@@ -140,9 +205,14 @@ func (s *StorageState) StoreInDB(root common.Hash) error {
 	return StoreTrie(s.baseDB, s.tries[root])
 }
 
+// Hasher returns the hash function of the state tries
+func (s *StorageState) Hasher() common.HashFunc {
+	return s.hash
+}
+
 // LoadFromDB loads an encoded trie from the DB where the key is `root`
 func (s *StorageState) LoadFromDB(root common.Hash) (*trie.Trie, error) {
-	t := trie.NewEmptyTrie()
+	t := trie.NewEmptyTrieWithHasher(s.hash)
 	err := LoadTrie(s.baseDB, t, root)
 	if err != nil {
 		return nil, err
@@ -151,13 +221,35 @@ func (s *StorageState) LoadFromDB(root common.Hash) (*trie.Trie, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.tries[t.MustHash()] = t
+	s.setTrie(t.MustHash(), t)
 	return t, nil
 }
 
+// PrefetchTrie loads the trie with the given root from the DB in the background, if it isn't already in memory,
+// so that a following call to TrieState for it doesn't fail or stall on the DB.
+func (s *StorageState) PrefetchTrie(root common.Hash) {
+	s.lock.RLock()
+	_, has := s.tries[root]
+	s.lock.RUnlock()
+	if has {
+		return
+	}
+
+	go func() {
+		if _, err := s.LoadFromDB(root); err != nil {
+			logger.Debug("failed to prefetch trie", "root", root, "error", err)
+		}
+	}()
+}
+
 // ExistsStorage check if the key exists in the storage trie with the given storage hash
 // If no hash is provided, the current chain head is used
 func (s *StorageState) ExistsStorage(hash *common.Hash, key []byte) (bool, error) {
+	if h := s.headStateAt(hash); h != nil {
+		val, err := h.Get(key)
+		return val != nil, err
+	}
+
 	if hash == nil {
 		sr, err := s.blockState.BestBlockStateRoot()
 		if err != nil {
@@ -175,6 +267,10 @@ func (s *StorageState) ExistsStorage(hash *common.Hash, key []byte) (bool, error
 // GetStorage gets the object from the trie using the given key and storage hash
 // If no hash is provided, the current chain head is used
 func (s *StorageState) GetStorage(hash *common.Hash, key []byte) ([]byte, error) {
+	if h := s.headStateAt(hash); h != nil {
+		return h.Get(key)
+	}
+
 	if hash == nil {
 		sr, err := s.blockState.BestBlockStateRoot()
 		if err != nil {
@@ -195,6 +291,10 @@ func (s *StorageState) GetStorage(hash *common.Hash, key []byte) ([]byte, error)
 
 // GetStorageByBlockHash returns the value at the given key at the given block hash
 func (s *StorageState) GetStorageByBlockHash(bhash common.Hash, key []byte) ([]byte, error) {
+	if h := s.headStateByHash(bhash); h != nil {
+		return h.Get(key)
+	}
+
 	header, err := s.blockState.GetHeader(bhash)
 	if err != nil {
 		return nil, err
@@ -203,6 +303,86 @@ func (s *StorageState) GetStorageByBlockHash(bhash common.Hash, key []byte) ([]b
 	return s.GetStorage(&header.StateRoot, key)
 }
 
+// GetKeysPaged returns up to count keys with the given prefix that come strictly after startKey in lexicographic
+// order, at the state of the block with the given hash. If no hash is provided, the best block is used. The hash of
+// the block that the keys were read from is returned so that following pages can be read from the same state.
+func (s *StorageState) GetKeysPaged(bhash *common.Hash, prefix, startKey []byte, count uint32) (common.Hash, [][]byte, error) {
+	if bhash == nil {
+		best := s.blockState.BestBlockHash()
+		bhash = &best
+	}
+
+	header, err := s.blockState.GetHeader(*bhash)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.tries[header.StateRoot] == nil {
+		return common.Hash{}, nil, errTrieDoesNotExist(header.StateRoot)
+	}
+
+	return *bhash, s.tries[header.StateRoot].GetKeysPaged(prefix, startKey, int(count)), nil
+}
+
+// StorageChange is a storage entry that differs between two states. Old is nil if the entry was added and New is
+// nil if it was removed.
+type StorageChange = trie.Change
+
+// StorageDiff returns the entries with the given prefix that were added, changed or removed between the states of
+// the blocks with the given hashes, sorted by key
+func (s *StorageState) StorageDiff(from, to common.Hash, prefix []byte) ([]*StorageChange, error) {
+	fromHeader, err := s.blockState.GetHeader(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toHeader, err := s.blockState.GetHeader(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromHeader.StateRoot == toHeader.StateRoot {
+		return []*StorageChange{}, nil
+	}
+
+	fromTrie, err := s.loadTrie(fromHeader.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	toTrie, err := s.loadTrie(toHeader.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// the tries may be modified in place by block import. The walk doesn't copy them and skips the subtries that
+	// are the same in both states, so it only holds the lock for as long as it takes to visit the changes.
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return trie.Diff(fromTrie, toTrie, prefix)
+}
+
+// loadTrie returns the trie with the given root, loading it from the database if it isn't in memory
+func (s *StorageState) loadTrie(root common.Hash) (*trie.Trie, error) {
+	s.lock.RLock()
+	t := s.tries[root]
+	s.lock.RUnlock()
+
+	if t != nil {
+		return t, nil
+	}
+
+	t, err := s.LoadFromDB(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errTrieDoesNotExist(root), err)
+	}
+
+	return t, nil
+}
+
 // StorageRoot returns the root hash of the current storage trie
 func (s *StorageState) StorageRoot() (common.Hash, error) {
 	sr, err := s.blockState.BestBlockStateRoot()
@@ -220,6 +400,19 @@ func (s *StorageState) StorageRoot() (common.Hash, error) {
 	return s.tries[sr].Hash()
 }
 
+// MemoryUsage returns the approximate number of bytes held by the tries cached in memory. The size of each trie is
+// measured when it's stored, so changes made to a trie in place since then aren't accounted for.
+func (s *StorageState) MemoryUsage() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var size uint64
+	for _, trieSize := range s.trieSizes {
+		size += trieSize
+	}
+	return size
+}
+
 // EnumeratedTrieRoot not implemented
 func (s *StorageState) EnumeratedTrieRoot(values [][]byte) {
 	//TODO
@@ -319,6 +512,64 @@ func (s *StorageState) GetBalance(hash *common.Hash, key [32]byte) (uint64, erro
 	return binary.LittleEndian.Uint64(bal), nil
 }
 
+// InsertStorage writes the given key-value pairs into the state of the best block, deleting the keys with a nil
+// value. The modified trie is stored under its new root, and replaces the trie of the best block in memory so that
+// the next block is built on top of it. It returns the new state root. This is meant for setting up test scenarios
+// on dev chains; the replacement doesn't persist across restarts.
+func (s *StorageState) InsertStorage(entries []*KeyValue) (common.Hash, error) {
+	root, err := s.blockState.BestBlockStateRoot()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// the best block's trie is replaced, its snapshot is updated once the lock is released
+	defer s.updateHeadStates()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.tries[root] == nil {
+		return common.Hash{}, errTrieDoesNotExist(root)
+	}
+
+	t, err := s.tries[root].DeepCopy()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	for _, kv := range entries {
+		if kv.Value == nil {
+			err = t.Delete(kv.Key)
+		} else {
+			err = t.Put(kv.Key, kv.Value)
+		}
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	newRoot, err := t.Hash()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	err = StoreTrie(s.baseDB, t)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// stored tries aren't modified, so the best block shares the trie stored under the new root
+	s.setTrie(newRoot, t)
+	s.setTrie(root, t)
+
+	for _, kv := range entries {
+		s.notifyChanged(kv)
+	}
+
+	logger.Debug("inserted storage into best block state", "entries", len(entries), "root", root, "new root", newRoot)
+	return newRoot, nil
+}
+
 // setStorage set the storage value for a given key in the trie. only for testing
 func (s *StorageState) setStorage(hash *common.Hash, key []byte, value []byte) error {
 	if hash == nil {
@@ -329,6 +580,7 @@ func (s *StorageState) setStorage(hash *common.Hash, key []byte, value []byte) e
 		hash = &sr
 	}
 
+	// the trie is modified in place, which the head state snapshots sharing it see
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	kv := &KeyValue{
@@ -344,6 +596,7 @@ func (s *StorageState) setStorage(hash *common.Hash, key []byte, value []byte) e
 	if err != nil {
 		return err
 	}
+	s.trieSizes[*hash] = s.tries[*hash].MemoryUsage()
 	s.notifyChanged(kv) // TODO: what is this used for? needs to be updated to work with new StorageState/TrieState API
 	return nil
 }
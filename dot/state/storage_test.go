@@ -1,6 +1,7 @@
 package state
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -101,3 +102,238 @@ func TestStorage_GetStorageByBlockHash(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, value, res)
 }
+
+func TestStorage_InsertStorage(t *testing.T) {
+	storage := newTestStorageState(t)
+	err := storage.setStorage(nil, []byte("old"), []byte("value"))
+	require.NoError(t, err)
+
+	bestRoot, err := storage.blockState.BestBlockStateRoot()
+	require.NoError(t, err)
+
+	root, err := storage.InsertStorage([]*KeyValue{
+		{Key: []byte("new"), Value: []byte("value")},
+		{Key: []byte("old"), Value: nil},
+	})
+	require.NoError(t, err)
+
+	// the state of the best block is replaced
+	val, err := storage.GetStorage(nil, []byte("new"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), val)
+
+	val, err = storage.GetStorage(nil, []byte("old"))
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	// the modified trie is stored under its new root
+	tr, err := storage.LoadFromDB(root)
+	require.NoError(t, err)
+	require.Equal(t, root, tr.MustHash())
+	val, err = tr.Get([]byte("new"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), val)
+
+	// modifying the best block's trie in place doesn't modify the trie under the new root
+	err = storage.setStorage(&bestRoot, []byte("other"), []byte("value"))
+	require.NoError(t, err)
+	val, err = storage.GetStorage(&root, []byte("other"))
+	require.NoError(t, err)
+	require.Nil(t, val)
+}
+
+func TestStorage_MemoryUsage(t *testing.T) {
+	storage := newTestStorageState(t)
+	empty := storage.MemoryUsage()
+
+	ts, err := storage.TrieStateCopy(&trie.EmptyHash)
+	require.NoError(t, err)
+	err = ts.Set([]byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	root, err := ts.Root()
+	require.NoError(t, err)
+	err = storage.StoreTrie(root, ts)
+	require.NoError(t, err)
+
+	// the size of the trie is measured when it's stored
+	stored := storage.MemoryUsage()
+	require.Equal(t, empty+storage.tries[root].MemoryUsage(), stored)
+
+	// changing a trie state copy doesn't change the stored trie
+	err = ts.Set([]byte("other"), []byte("value"))
+	require.NoError(t, err)
+	require.Equal(t, stored, storage.MemoryUsage())
+
+	storage.pruneKey(&types.Header{StateRoot: root})
+	require.Equal(t, empty, storage.MemoryUsage())
+}
+
+func TestStorage_TrieStateCopy(t *testing.T) {
+	storage := newTestStorageState(t)
+	err := storage.setStorage(nil, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	ts, err := storage.TrieStateCopy(nil)
+	require.NoError(t, err)
+
+	err = ts.Set([]byte("key"), []byte("changed"))
+	require.NoError(t, err)
+
+	val, err := storage.GetStorage(nil, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), val)
+}
+
+func TestStorage_GetKeysPaged(t *testing.T) {
+	storage := newTestStorageState(t)
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+
+	for _, k := range []string{"abc", "abd", "abe", "abf", "xyz"} {
+		err = ts.Set([]byte(k), []byte("value"))
+		require.NoError(t, err)
+	}
+
+	root, err := ts.Root()
+	require.NoError(t, err)
+	err = storage.StoreTrie(root, ts)
+	require.NoError(t, err)
+
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: testGenesisHeader.Hash(),
+			Number:     big.NewInt(1),
+			StateRoot:  root,
+		},
+		Body: types.NewBody([]byte{}),
+	}
+	err = storage.blockState.AddBlock(block)
+	require.NoError(t, err)
+
+	bhash := block.Header.Hash()
+	hash, keys, err := storage.GetKeysPaged(&bhash, []byte("ab"), nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, bhash, hash)
+	require.Equal(t, [][]byte{[]byte("abc"), []byte("abd")}, keys)
+
+	_, keys, err = storage.GetKeysPaged(&bhash, []byte("ab"), keys[1], 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("abe"), []byte("abf")}, keys)
+
+	_, keys, err = storage.GetKeysPaged(&bhash, []byte("ab"), keys[1], 2)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	storage.pruneKey(block.Header)
+	_, _, err = storage.GetKeysPaged(&bhash, []byte("ab"), nil, 2)
+	require.True(t, errors.Is(err, ErrTrieDoesNotExist))
+}
+
+func TestStorage_StorageDiff(t *testing.T) {
+	storage := newTestStorageState(t)
+
+	addBlock := func(parent *types.Header, entries map[string]string) *types.Header {
+		ts, err := NewTrieState(storage.baseDB, trie.NewEmptyTrie())
+		require.NoError(t, err)
+
+		for k, v := range entries {
+			err = ts.Set([]byte(k), []byte(v))
+			require.NoError(t, err)
+		}
+
+		root, err := ts.Root()
+		require.NoError(t, err)
+		err = storage.StoreTrie(root, ts)
+		require.NoError(t, err)
+
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(0).Add(parent.Number, big.NewInt(1)),
+			StateRoot:  root,
+		}
+		err = storage.blockState.AddBlock(&types.Block{Header: header, Body: types.NewBody([]byte{})})
+		require.NoError(t, err)
+		return header
+	}
+
+	from := addBlock(testGenesisHeader, map[string]string{"aa": "1", "ab": "2", "ac": "3", "xy": "4"})
+	to := addBlock(from, map[string]string{"ab": "5", "ac": "3", "ad": "6", "xz": "7"})
+
+	changes, err := storage.StorageDiff(from.Hash(), to.Hash(), []byte("a"))
+	require.NoError(t, err)
+
+	expected := []*StorageChange{
+		{Key: []byte("aa"), Old: []byte("1")},
+		{Key: []byte("ab"), Old: []byte("2"), New: []byte("5")},
+		{Key: []byte("ad"), New: []byte("6")},
+	}
+	require.Equal(t, expected, changes)
+
+	changes, err = storage.StorageDiff(from.Hash(), to.Hash(), nil)
+	require.NoError(t, err)
+	require.Len(t, changes, 5)
+
+	changes, err = storage.StorageDiff(to.Hash(), to.Hash(), nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// a trie that isn't in memory is loaded from the database
+	storage.pruneKey(from)
+	changes, err = storage.StorageDiff(from.Hash(), to.Hash(), []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, expected, changes)
+
+	missing := &types.Header{
+		ParentHash: to.Hash(),
+		Number:     big.NewInt(3),
+		StateRoot:  common.Hash{0x1},
+	}
+	err = storage.blockState.AddBlock(&types.Block{Header: missing, Body: types.NewBody([]byte{})})
+	require.NoError(t, err)
+
+	_, err = storage.StorageDiff(to.Hash(), missing.Hash(), nil)
+	require.True(t, errors.Is(err, ErrTrieDoesNotExist))
+}
+
+func TestStorage_OverlayState(t *testing.T) {
+	storage := newTestStorageState(t)
+	require.NoError(t, storage.setStorage(nil, []byte("a"), []byte("1")))
+	require.NoError(t, storage.setStorage(nil, []byte("b"), []byte("2")))
+
+	o, err := storage.OverlayState(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, o.Set([]byte("a"), []byte("3")))
+	require.NoError(t, o.Delete([]byte("b")))
+
+	val, err := o.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), val)
+	val, err = o.Get([]byte("b"))
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	// the state isn't changed
+	val, err = storage.GetStorage(nil, []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+	val, err = storage.GetStorage(nil, []byte("b"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), val)
+
+	// the root is the one of the state with the changes applied
+	expected := trie.NewEmptyTrie()
+	require.NoError(t, expected.Put([]byte("a"), []byte("3")))
+	root, err := o.Root()
+	require.NoError(t, err)
+	require.Equal(t, expected.MustHash(), root)
+
+	val, err = o.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), val)
+
+	val, err = storage.GetStorage(nil, []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+}
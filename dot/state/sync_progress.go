@@ -0,0 +1,49 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+var syncProgressKey = []byte("syncprogress") // syncProgressKey -> encoded progress of an interrupted initial sync
+
+// SetSyncProgress persists the progress of the initial sync, so that it can be resumed after a restart
+func (bs *BlockState) SetSyncProgress(progress *types.SyncProgress) error {
+	return bs.db.Put(syncProgressKey, progress.Encode())
+}
+
+// GetSyncProgress returns the persisted progress of the initial sync, or chaindb.ErrKeyNotFound if there is none
+func (bs *BlockState) GetSyncProgress() (*types.SyncProgress, error) {
+	enc, err := bs.db.Get(syncProgressKey)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := new(types.SyncProgress)
+	err = progress.Decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// DeleteSyncProgress deletes the persisted progress of the initial sync, once the node is synced
+func (bs *BlockState) DeleteSyncProgress() error {
+	return bs.db.Del(syncProgressKey)
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockState_SyncProgress(t *testing.T) {
+	bs := newTestBlockState(t, nil)
+
+	_, err := bs.GetSyncProgress()
+	require.Equal(t, chaindb.ErrKeyNotFound, err)
+
+	progress := &types.SyncProgress{
+		Target:           big.NewInt(1000),
+		LastVerified:     big.NewInt(128),
+		LastVerifiedHash: common.Hash{0x1},
+	}
+
+	err = bs.SetSyncProgress(progress)
+	require.NoError(t, err)
+
+	res, err := bs.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, progress, res)
+
+	err = bs.DeleteSyncProgress()
+	require.NoError(t, err)
+
+	_, err = bs.GetSyncProgress()
+	require.Equal(t, chaindb.ErrKeyNotFound, err)
+}
@@ -91,7 +91,7 @@ func AddBlocksToState(t *testing.T, blockState *BlockState, depth int) ([]*types
 					ParentHash: previousHash,
 					Number:     big.NewInt(int64(i) + 1),
 					StateRoot:  trie.EmptyHash,
-					Digest:     [][]byte{{byte(i)}},
+					Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{byte(i)}}),
 				},
 				Body: &types.Body{},
 			}
@@ -162,7 +162,7 @@ func AddBlocksToStateWithFixedBranches(t *testing.T, blockState *BlockState, dep
 					ParentHash: previousHash,
 					Number:     big.NewInt(int64(i)),
 					StateRoot:  trie.EmptyHash,
-					Digest:     [][]byte{{byte(i), byte(j), r}},
+					Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{byte(i), byte(j), r}}),
 				},
 				Body: &types.Body{},
 			}
@@ -179,7 +179,7 @@ func AddBlocksToStateWithFixedBranches(t *testing.T, blockState *BlockState, dep
 }
 
 func generateBlockWithRandomTrie(t *testing.T, serv *Service) (*types.Block, *TrieState) {
-	trieState, err := serv.Storage.TrieState(&trie.EmptyHash)
+	trieState, err := serv.Storage.TrieStateCopy(&trie.EmptyHash)
 	require.NoError(t, err)
 
 	// Generate random data for trie state.
@@ -1,6 +1,9 @@
 package state
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/transaction"
@@ -8,21 +11,49 @@ import (
 
 // TransactionState represents the queue of transactions
 type TransactionState struct {
-	queue *transaction.PriorityQueue
-	pool  *transaction.Pool
+	queue  *transaction.PriorityQueue
+	pool   *transaction.Pool
+	bans   *transaction.BanList
+	locals *transaction.LocalList
+	future *transaction.FutureQueue
+
+	resubmits *transaction.ResubmitStats
+
+	readyLock sync.RWMutex
+	ready     map[byte]chan<- struct{}
+
+	eventsLock sync.RWMutex
+	events     map[byte]chan<- *transaction.PoolEvent
 }
 
 // NewTransactionState returns a new TransactionState
 func NewTransactionState() *TransactionState {
 	return &TransactionState{
-		queue: transaction.NewPriorityQueue(),
-		pool:  transaction.NewPool(),
+		queue:     transaction.NewPriorityQueue(),
+		pool:      transaction.NewPool(),
+		bans:      transaction.NewBanList(transaction.DefaultMaxValidationFailures, transaction.DefaultBanDuration),
+		locals:    transaction.NewLocalList(),
+		future:    transaction.NewFutureQueue(),
+		resubmits: transaction.NewResubmitStats(),
+		ready:     make(map[byte]chan<- struct{}),
+		events:    make(map[byte]chan<- *transaction.PoolEvent),
 	}
 }
 
 // Push pushes a transaction to the queue, ordered by priority
 func (s *TransactionState) Push(vt *transaction.ValidTransaction) (common.Hash, error) {
-	return s.queue.Push(vt)
+	hash, err := s.queue.Push(vt)
+	if err != nil {
+		return hash, err
+	}
+
+	if vt.Local {
+		s.locals.Add(hash)
+	}
+
+	s.notifyReady()
+	s.notifyPoolEvent(transaction.PoolEventReady, hash)
+	return hash, nil
 }
 
 // Pop removes and returns the head of the queue
@@ -45,10 +76,19 @@ func (s *TransactionState) PendingInPool() []*transaction.ValidTransaction {
 	return s.pool.Transactions()
 }
 
-// RemoveExtrinsic removes an extrinsic from the queue and pool
+// RemoveExtrinsic removes an extrinsic from the queue, pool and future queue
 func (s *TransactionState) RemoveExtrinsic(ext types.Extrinsic) {
-	s.pool.Remove(ext.Hash())
-	s.queue.RemoveExtrinsic(ext)
+	s.removeExtrinsic(ext)
+}
+
+// removeExtrinsic removes an extrinsic from the queue, pool and future queue, it returns true if it was removed
+// from any of them
+func (s *TransactionState) removeExtrinsic(ext types.Extrinsic) bool {
+	removed := s.pool.Remove(ext.Hash())
+	removed = s.queue.Remove(ext.Hash()) || removed
+	removed = s.future.Remove(ext.Hash()) || removed
+	s.notifyReady()
+	return removed
 }
 
 // RemoveExtrinsicFromPool removes an extrinsic from the pool
@@ -56,7 +96,161 @@ func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
 	s.pool.Remove(ext.Hash())
 }
 
-// AddToPool adds a transaction to the pool
-func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
-	return s.pool.Insert(vt)
+// AddToPool adds a transaction to the pool, returning an error if it's rejected because the pool is full
+func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) (common.Hash, error) {
+	return s.addToPool(vt, transaction.PoolEventImported)
+}
+
+// PromoteFuture adds a future transaction that has become valid to the pool
+func (s *TransactionState) PromoteFuture(vt *transaction.ValidTransaction) (common.Hash, error) {
+	return s.addToPool(vt, transaction.PoolEventFuturePromoted)
+}
+
+func (s *TransactionState) addToPool(vt *transaction.ValidTransaction, event transaction.PoolEventType) (common.Hash, error) {
+	hash, evicted, err := s.pool.InsertEvicting(vt)
+	if err != nil {
+		return hash, err
+	}
+
+	if vt.Local {
+		s.locals.Add(hash)
+	}
+
+	for _, h := range evicted {
+		s.notifyPoolEvent(transaction.PoolEventDropped, h)
+	}
+
+	s.notifyReady()
+	s.notifyPoolEvent(event, hash)
+	return hash, nil
+}
+
+// RemoveIncludedExtrinsic removes an extrinsic that was included in the given block from the queue, pool and future
+// queue
+func (s *TransactionState) RemoveIncludedExtrinsic(ext types.Extrinsic, block common.Hash) {
+	if s.removeExtrinsic(ext) {
+		s.notifyPoolEventInBlock(ext.Hash(), block)
+	}
+}
+
+// NotifyBroadcast notifies pool event subscribers that the extrinsic was gossiped to peers
+func (s *TransactionState) NotifyBroadcast(ext types.Extrinsic) {
+	s.notifyPoolEvent(transaction.PoolEventBroadcast, ext.Hash())
+}
+
+// SetPreferLocal sets whether transactions submitted to this node via RPC are preferred over transactions received
+// from peers. Preferred transactions are included in blocks before other transactions having the same priority, and
+// are evicted last when the pool is full.
+func (s *TransactionState) SetPreferLocal(preferLocal bool) {
+	s.queue.SetPreferLocal(preferLocal)
+	s.pool.SetPreferLocal(preferLocal)
+}
+
+// SetLimits sets the maximum number of transactions and their maximum total encoded size in bytes, for each of the
+// pool and the queue. A limit of 0 keeps the default limit.
+func (s *TransactionState) SetLimits(count, bytes int) {
+	s.pool.SetLimits(count, bytes)
+	s.queue.SetLimits(count, bytes)
+}
+
+// SetBanDuration sets how long transactions that repeatedly fail validation are banned for
+func (s *TransactionState) SetBanDuration(duration time.Duration) {
+	s.bans.SetDuration(duration)
+}
+
+// IsBanned returns true if the extrinsic is currently banned from the pool and queue
+func (s *TransactionState) IsBanned(ext types.Extrinsic) bool {
+	return s.bans.IsBanned(ext.Hash())
+}
+
+// RemoveAndBan removes the extrinsic with the given hash from the queue, pool and future queue, and bans it so that
+// it isn't re-imported when it's gossiped back to us. It returns true if the extrinsic was removed.
+func (s *TransactionState) RemoveAndBan(hash common.Hash) bool {
+	removed := s.pool.Remove(hash)
+	removed = s.queue.Remove(hash) || removed
+	removed = s.future.Remove(hash) || removed
+	s.bans.Ban(hash)
+
+	if !removed {
+		return false
+	}
+
+	s.notifyReady()
+	s.notifyPoolEvent(transaction.PoolEventBanned, hash)
+	return true
+}
+
+// RecordValidationFailure records that the extrinsic failed validation. If it has failed validation too many
+// times, it is banned and removed from the queue and pool, and true is returned.
+func (s *TransactionState) RecordValidationFailure(ext types.Extrinsic) bool {
+	if !s.bans.RecordFailure(ext.Hash()) {
+		return false
+	}
+
+	s.RemoveExtrinsic(ext)
+	s.notifyPoolEvent(transaction.PoolEventBanned, ext.Hash())
+	return true
+}
+
+// AddFuture adds a transaction that isn't valid yet to the future queue, to be revalidated once blocks are imported
+func (s *TransactionState) AddFuture(ext types.Extrinsic) (common.Hash, error) {
+	hash, err := s.future.Add(ext)
+	if err != nil {
+		return hash, err
+	}
+
+	s.notifyPoolEvent(transaction.PoolEventFuture, hash)
+	return hash, nil
+}
+
+// ReadyFuture removes and returns the future transactions that are due to be revalidated at the given block
+func (s *TransactionState) ReadyFuture(number uint64) []*transaction.FutureTransaction {
+	return s.future.Ready(number)
+}
+
+// RescheduleFuture adds back a future transaction that still isn't valid at the given block. It returns false if
+// the transaction was dropped because it has been held for too long.
+func (s *TransactionState) RescheduleFuture(ft *transaction.FutureTransaction, number uint64) bool {
+	if s.future.Reschedule(ft, number) {
+		return true
+	}
+
+	s.notifyPoolEvent(transaction.PoolEventDropped, ft.Extrinsic.Hash())
+	return false
+}
+
+// FutureCount returns the number of transactions in the future queue
+func (s *TransactionState) FutureCount() int {
+	return s.future.Len()
+}
+
+// MemoryUsage returns the approximate number of bytes held by the transaction queue, pool and future queue
+func (s *TransactionState) MemoryUsage() uint64 {
+	return uint64(s.queue.Size() + s.pool.Size() + s.future.Size())
+}
+
+// BanStats returns metrics about banned transactions
+func (s *TransactionState) BanStats() transaction.BanStats {
+	return s.bans.Stats()
+}
+
+// BanList returns the list of banned transactions
+func (s *TransactionState) BanList() *transaction.BanList {
+	return s.bans
+}
+
+// IsLocal returns true if the extrinsic was submitted to this node via RPC. It's remembered after the extrinsic leaves
+// the pool and queue, so that it's still preferred if it's resubmitted after the block including it is retracted.
+func (s *TransactionState) IsLocal(ext types.Extrinsic) bool {
+	return s.locals.Has(ext.Hash())
+}
+
+// RecordResubmission counts an extrinsic of a retracted block that was resubmitted with the given result
+func (s *TransactionState) RecordResubmission(result transaction.ResubmitResult) {
+	s.resubmits.Record(result)
+}
+
+// ResubmitStats returns the counts of resubmitted extrinsics
+func (s *TransactionState) ResubmitStats() *transaction.ResubmitStats {
+	return s.resubmits
 }
@@ -0,0 +1,127 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// RegisterReadyChannel registers a channel that is signalled whenever the set of ready transactions changes, including
+// when a transaction is added to the pool.
+// Signals are dropped if the channel is not ready to receive, so a buffered channel of size 1 is sufficient.
+// It returns the channel ID (used for unregistering the channel)
+func (s *TransactionState) RegisterReadyChannel(ch chan<- struct{}) (byte, error) {
+	s.readyLock.Lock()
+	defer s.readyLock.Unlock()
+
+	if len(s.ready) == 256 {
+		return 0, errors.New("channel limit reached")
+	}
+
+	var id byte
+	for {
+		id = generateID()
+		if s.ready[id] == nil {
+			break
+		}
+	}
+
+	s.ready[id] = ch
+	return id, nil
+}
+
+// UnregisterReadyChannel removes the ready set notification channel with the given ID.
+// A channel must be unregistered before closing it.
+func (s *TransactionState) UnregisterReadyChannel(id byte) {
+	s.readyLock.Lock()
+	defer s.readyLock.Unlock()
+
+	delete(s.ready, id)
+}
+
+func (s *TransactionState) notifyReady() {
+	s.readyLock.RLock()
+	defer s.readyLock.RUnlock()
+
+	for _, ch := range s.ready {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// RegisterPoolEventChannel registers a channel that receives the transaction pool events. Events are dropped if the
+// channel is not ready to receive. It returns the channel ID (used for unregistering the channel)
+func (s *TransactionState) RegisterPoolEventChannel(ch chan<- *transaction.PoolEvent) (byte, error) {
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	if len(s.events) == 256 {
+		return 0, errors.New("channel limit reached")
+	}
+
+	var id byte
+	for {
+		id = generateID()
+		if s.events[id] == nil {
+			break
+		}
+	}
+
+	s.events[id] = ch
+	return id, nil
+}
+
+// UnregisterPoolEventChannel removes the pool event channel with the given ID.
+// A channel must be unregistered before closing it.
+func (s *TransactionState) UnregisterPoolEventChannel(id byte) {
+	s.eventsLock.Lock()
+	defer s.eventsLock.Unlock()
+
+	delete(s.events, id)
+}
+
+func (s *TransactionState) notifyPoolEvent(typ transaction.PoolEventType, hash common.Hash) {
+	s.sendPoolEvent(&transaction.PoolEvent{
+		Type: typ,
+		Hash: hash,
+	})
+}
+
+func (s *TransactionState) notifyPoolEventInBlock(hash, block common.Hash) {
+	s.sendPoolEvent(&transaction.PoolEvent{
+		Type:  transaction.PoolEventInBlock,
+		Hash:  hash,
+		Block: block,
+	})
+}
+
+func (s *TransactionState) sendPoolEvent(ev *transaction.PoolEvent) {
+	s.eventsLock.RLock()
+	defer s.eventsLock.RUnlock()
+
+	for _, ch := range s.events {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyChannel(t *testing.T) {
+	ts := NewTransactionState()
+
+	ch := make(chan struct{}, 1)
+	id, err := ts.RegisterReadyChannel(ch)
+	require.NoError(t, err)
+
+	vt := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	_, err = ts.Push(vt)
+	require.NoError(t, err)
+
+	select {
+	case <-ch:
+	case <-time.After(testMessageTimeout):
+		t.Fatal("did not receive ready notification on push")
+	}
+
+	ts.RemoveExtrinsic(types.Extrinsic(vt.Extrinsic))
+
+	select {
+	case <-ch:
+	case <-time.After(testMessageTimeout):
+		t.Fatal("did not receive ready notification on removal")
+	}
+
+	_, err = ts.AddToPool(vt)
+	require.NoError(t, err)
+
+	select {
+	case <-ch:
+	case <-time.After(testMessageTimeout):
+		t.Fatal("did not receive ready notification on pool insert")
+	}
+
+	ts.RemoveExtrinsic(types.Extrinsic(vt.Extrinsic))
+	<-ch
+
+	ts.UnregisterReadyChannel(id)
+
+	_, err = ts.Push(vt)
+	require.NoError(t, err)
+
+	select {
+	case <-ch:
+		t.Fatal("received ready notification after unregistering")
+	default:
+	}
+}
+
+func TestPoolEventChannel(t *testing.T) {
+	ts := NewTransactionState()
+
+	ch := make(chan *transaction.PoolEvent, 16)
+	id, err := ts.RegisterPoolEventChannel(ch)
+	require.NoError(t, err)
+
+	next := func() *transaction.PoolEvent {
+		select {
+		case ev := <-ch:
+			return ev
+		case <-time.After(testMessageTimeout):
+			t.Fatal("did not receive pool event")
+			return nil
+		}
+	}
+
+	vt := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	hash := vt.Extrinsic.Hash()
+
+	_, err = ts.AddToPool(vt)
+	require.NoError(t, err)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventImported, Hash: hash}, next())
+
+	_, err = ts.Push(vt)
+	require.NoError(t, err)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventReady, Hash: hash}, next())
+
+	block := common.Hash{1}
+	ts.RemoveIncludedExtrinsic(vt.Extrinsic, block)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventInBlock, Hash: hash, Block: block}, next())
+
+	// the extrinsic isn't in the pool anymore, so it isn't included again
+	ts.RemoveIncludedExtrinsic(vt.Extrinsic, common.Hash{2})
+	require.Len(t, ch, 0)
+
+	future := types.Extrinsic("b")
+	_, err = ts.AddFuture(future)
+	require.NoError(t, err)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventFuture, Hash: future.Hash()}, next())
+
+	ready := ts.ReadyFuture(1)
+	require.Len(t, ready, 1)
+	require.False(t, ts.RescheduleFuture(ready[0], 1+transaction.DefaultFutureLifetime))
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventDropped, Hash: future.Hash()}, next())
+
+	_, err = ts.PromoteFuture(transaction.NewValidTransaction(future, &transaction.Validity{Priority: 1}))
+	require.NoError(t, err)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventFuturePromoted, Hash: future.Hash()}, next())
+
+	for i := 0; i < transaction.DefaultMaxValidationFailures; i++ {
+		ts.RecordValidationFailure(future)
+	}
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventBanned, Hash: future.Hash()}, next())
+
+	ts.NotifyBroadcast(vt.Extrinsic)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventBroadcast, Hash: hash}, next())
+
+	ts.UnregisterPoolEventChannel(id)
+
+	_, err = ts.AddToPool(vt)
+	require.NoError(t, err)
+	require.Len(t, ch, 0)
+}
+
+func TestPoolEventChannel_Evicted(t *testing.T) {
+	ts := NewTransactionState()
+	ts.SetLimits(1, 0)
+
+	ch := make(chan *transaction.PoolEvent, 16)
+	_, err := ts.RegisterPoolEventChannel(ch)
+	require.NoError(t, err)
+
+	low := transaction.NewValidTransaction(types.Extrinsic("a"), &transaction.Validity{Priority: 1})
+	high := transaction.NewValidTransaction(types.Extrinsic("b"), &transaction.Validity{Priority: 2})
+
+	_, err = ts.AddToPool(low)
+	require.NoError(t, err)
+	_, err = ts.AddToPool(high)
+	require.NoError(t, err)
+
+	require.Len(t, ch, 3)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventImported, Hash: low.Extrinsic.Hash()}, <-ch)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventDropped, Hash: low.Extrinsic.Hash()}, <-ch)
+	require.Equal(t, &transaction.PoolEvent{Type: transaction.PoolEventImported, Hash: high.Extrinsic.Hash()}, <-ch)
+}
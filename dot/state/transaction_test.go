@@ -38,7 +38,8 @@ func TestTransactionState_Pending(t *testing.T) {
 
 	hashes := make([]common.Hash, len(txs))
 	for i, tx := range txs {
-		h := ts.AddToPool(tx)
+		h, err := ts.AddToPool(tx)
+		require.NoError(t, err)
 		hashes[i] = h
 	}
 
@@ -59,3 +60,99 @@ func TestTransactionState_Pending(t *testing.T) {
 	head := ts.Peek()
 	require.Nil(t, head)
 }
+
+func TestTransactionState_RecordValidationFailure(t *testing.T) {
+	ts := NewTransactionState()
+
+	tx := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	ts.AddToPool(tx)
+
+	for i := 1; i < transaction.DefaultMaxValidationFailures; i++ {
+		require.False(t, ts.RecordValidationFailure(tx.Extrinsic))
+		require.False(t, ts.IsBanned(tx.Extrinsic))
+	}
+
+	require.True(t, ts.RecordValidationFailure(tx.Extrinsic))
+	require.True(t, ts.IsBanned(tx.Extrinsic))
+	require.Equal(t, 0, len(ts.PendingInPool()))
+
+	stats := ts.BanStats()
+	require.Equal(t, 1, stats.Banned)
+	require.Equal(t, uint64(1), stats.TotalBans)
+}
+
+func TestTransactionState_RemoveAndBan(t *testing.T) {
+	ts := NewTransactionState()
+
+	tx := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+	hash, err := ts.Push(tx)
+	require.NoError(t, err)
+
+	require.True(t, ts.RemoveAndBan(hash))
+	require.True(t, ts.IsBanned(tx.Extrinsic))
+	require.Nil(t, ts.Peek())
+	require.Empty(t, ts.PendingInPool())
+
+	// the extrinsic stays banned even though there's nothing left to remove
+	require.False(t, ts.RemoveAndBan(hash))
+	require.True(t, ts.IsBanned(tx.Extrinsic))
+}
+
+func TestTransactionState_IsLocal(t *testing.T) {
+	ts := NewTransactionState()
+
+	local := &transaction.ValidTransaction{
+		Extrinsic: []byte("a"),
+		Validity:  &transaction.Validity{Priority: 1},
+		Local:     true,
+	}
+	remote := &transaction.ValidTransaction{
+		Extrinsic: []byte("b"),
+		Validity:  &transaction.Validity{Priority: 1},
+	}
+
+	_, err := ts.Push(local)
+	require.NoError(t, err)
+	_, err = ts.AddToPool(remote)
+	require.NoError(t, err)
+
+	// the origin is remembered after the extrinsic leaves the queue
+	ts.RemoveIncludedExtrinsic(local.Extrinsic, common.Hash{1})
+	require.True(t, ts.IsLocal(local.Extrinsic))
+	require.False(t, ts.IsLocal(remote.Extrinsic))
+}
+
+func TestTransactionState_Future(t *testing.T) {
+	ts := NewTransactionState()
+	ext := []byte("future")
+
+	_, err := ts.AddFuture(ext)
+	require.NoError(t, err)
+	require.Equal(t, 1, ts.FutureCount())
+
+	// future transactions included in a block are removed
+	ts.RemoveExtrinsic(ext)
+	require.Equal(t, 0, ts.FutureCount())
+	require.Empty(t, ts.ReadyFuture(1))
+}
+
+func TestTransactionState_MemoryUsage(t *testing.T) {
+	ts := NewTransactionState()
+	require.Equal(t, uint64(0), ts.MemoryUsage())
+
+	_, err := ts.Push(&transaction.ValidTransaction{
+		Extrinsic: make([]byte, 100),
+		Validity:  &transaction.Validity{Priority: 1},
+	})
+	require.NoError(t, err)
+
+	_, err = ts.AddFuture(make([]byte, 10))
+	require.NoError(t, err)
+	require.Equal(t, uint64(110), ts.MemoryUsage())
+}
@@ -63,7 +63,7 @@ func (s *TrieState) Commit() error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.t = trie.NewEmptyTrie()
+	s.t = trie.NewEmptyTrieWithHasher(s.t.Hasher())
 	iter := s.db.NewIterator()
 
 	for iter.Next() {
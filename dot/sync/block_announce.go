@@ -0,0 +1,94 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+// maxFutureSlots is the number of slots an announced block may be ahead of our current slot, to allow for clock drift
+var maxFutureSlots = uint64(2)
+
+// ValidateBlockAnnounce checks that the announced block isn't rejected by the block rules of the chain spec, and
+// that its slot is not more than maxFutureSlots ahead of the current slot according to our clock, to prevent
+// peers from filling the blocktree with future blocks
+func (s *Service) ValidateBlockAnnounce(msg *network.BlockAnnounceMessage) error {
+	header, err := types.NewHeader(
+		msg.ParentHash,
+		msg.Number,
+		msg.StateRoot,
+		msg.ExtrinsicsRoot,
+		msg.Digest,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkBlockRules(header)
+	if err != nil {
+		return err
+	}
+
+	if s.slotDuration == 0 {
+		return nil
+	}
+
+	slot, err := types.GetSlotFromHeader(header)
+	if err != nil {
+		return err
+	}
+
+	current := s.currentSlot()
+	if slot > current+maxFutureSlots {
+		s.logger.Debug("rejecting announced block from future slot",
+			"number", header.Number,
+			"hash", header.Hash(),
+			"slot", slot,
+			"current slot", current,
+		)
+		return ErrFutureSlot
+	}
+
+	return nil
+}
+
+// currentSlot returns the current slot according to our clock. BABE slots are numbered by their start time since the
+// unix epoch, so the current slot doesn't depend on how far our chain is synced. If the genesis slot is known and is
+// ahead of our clock, it's used instead.
+func (s *Service) currentSlot() uint64 {
+	slot := uint64(s.now().UnixNano() / int64(s.slotDuration))
+
+	genesisSlot, err := s.genesisSlot()
+	if err == nil && genesisSlot > slot {
+		return genesisSlot
+	}
+
+	return slot
+}
+
+// genesisSlot returns the slot of the first block after genesis
+func (s *Service) genesisSlot() (uint64, error) {
+	hash, err := s.blockState.GetBlockHash(big.NewInt(1))
+	if err != nil {
+		return 0, err
+	}
+
+	return s.blockState.GetSlotForBlock(*hash)
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBabeDigest(slot uint64) *types.PreRuntimeDigest {
+	bh := &types.BabeHeader{
+		SlotNumber: slot,
+	}
+
+	return &types.PreRuntimeDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              bh.Encode(),
+	}
+}
+
+// addTestSlotBlock adds block 1 at the given slot, which arrives now
+func addTestSlotBlock(t *testing.T, s *Service, slot uint64) *types.Block {
+	block := &types.Block{
+		Header: &types.Header{
+			ParentHash: s.blockState.BestBlockHash(),
+			Number:     big.NewInt(1),
+			StateRoot:  trie.EmptyHash,
+			Digest:     types.NewDigest(newTestBabeDigest(slot)),
+		},
+		Body: &types.Body{},
+	}
+	err := s.blockState.AddBlock(block)
+	require.NoError(t, err)
+	return block
+}
+
+func newTestAnnounce(parent *types.Block, slot uint64) *network.BlockAnnounceMessage {
+	return &network.BlockAnnounceMessage{
+		ParentHash: parent.Header.Hash(),
+		Number:     big.NewInt(2),
+		StateRoot:  trie.EmptyHash,
+		Digest:     types.NewDigest(newTestBabeDigest(slot)),
+	}
+}
+
+func TestValidateBlockAnnounce_FutureSlot(t *testing.T) {
+	s := newTestSyncer(t)
+	s.slotDuration = time.Second
+	now := time.Unix(1600000000, 0)
+	s.now = func() time.Time { return now }
+	current := uint64(1600000000)
+
+	block := addTestSlotBlock(t, s, current-10)
+
+	err := s.ValidateBlockAnnounce(newTestAnnounce(block, current))
+	require.NoError(t, err)
+
+	err = s.ValidateBlockAnnounce(newTestAnnounce(block, current+maxFutureSlots))
+	require.NoError(t, err)
+
+	err = s.ValidateBlockAnnounce(newTestAnnounce(block, current+maxFutureSlots+1))
+	require.Equal(t, ErrFutureSlot, err)
+
+	// the current slot follows the clock
+	now = now.Add(10 * time.Second)
+	err = s.ValidateBlockAnnounce(newTestAnnounce(block, current+maxFutureSlots+1))
+	require.NoError(t, err)
+
+	// without a slot duration, slots are not checked
+	s.slotDuration = 0
+	err = s.ValidateBlockAnnounce(newTestAnnounce(block, current+maxFutureSlots+100))
+	require.NoError(t, err)
+}
+
+func TestValidateBlockAnnounce_Syncing(t *testing.T) {
+	s := newTestSyncer(t)
+	s.slotDuration = time.Second
+	s.now = func() time.Time { return time.Unix(1600000000, 0) }
+	current := uint64(1600000000)
+
+	// our best block was just synced, but is far behind the chain head
+	block := addTestSlotBlock(t, s, current-100000)
+
+	err := s.ValidateBlockAnnounce(newTestAnnounce(block, current))
+	require.NoError(t, err)
+}
+
+func TestValidateBlockAnnounce_GenesisSlotAheadOfClock(t *testing.T) {
+	s := newTestSyncer(t)
+	s.slotDuration = time.Second
+	s.now = func() time.Time { return time.Unix(1600000000, 0) }
+	genesisSlot := uint64(1600000100)
+
+	block := addTestSlotBlock(t, s, genesisSlot)
+
+	err := s.ValidateBlockAnnounce(newTestAnnounce(block, genesisSlot+maxFutureSlots))
+	require.NoError(t, err)
+
+	err = s.ValidateBlockAnnounce(newTestAnnounce(block, genesisSlot+maxFutureSlots+1))
+	require.Equal(t, ErrFutureSlot, err)
+}
+
+func TestValidateBlockAnnounce_BlockRules(t *testing.T) {
+	s := newTestSyncer(t)
+
+	announce := &network.BlockAnnounceMessage{
+		ParentHash: s.blockState.BestBlockHash(),
+		Number:     big.NewInt(1),
+		StateRoot:  trie.EmptyHash,
+		Digest:     types.NewEmptyDigest(),
+	}
+	header, err := types.NewHeader(announce.ParentHash, announce.Number, announce.StateRoot, announce.ExtrinsicsRoot, announce.Digest)
+	require.NoError(t, err)
+
+	rules, err := genesis.NewBlockRules([]string{header.Hash().String()}, nil)
+	require.NoError(t, err)
+	s.blockRules = rules
+
+	err = s.ValidateBlockAnnounce(announce)
+	require.True(t, errors.Is(err, genesis.ErrBadBlock))
+
+	// the block doesn't match the fork block at its number
+	rules, err = genesis.NewBlockRules(nil, []genesis.ForkBlock{{Number: 1, Hash: common.Hash{0x01}}})
+	require.NoError(t, err)
+	s.blockRules = rules
+
+	err = s.ValidateBlockAnnounce(announce)
+	require.True(t, errors.Is(err, genesis.ErrForkBlock))
+
+	err = s.handleHeader(header)
+	require.True(t, errors.Is(err, genesis.ErrForkBlock))
+
+	rules, err = genesis.NewBlockRules(nil, []genesis.ForkBlock{{Number: 1, Hash: header.Hash()}})
+	require.NoError(t, err)
+	s.blockRules = rules
+
+	err = s.ValidateBlockAnnounce(announce)
+	require.NoError(t, err)
+}
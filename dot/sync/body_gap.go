@@ -0,0 +1,211 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"math/big"
+	mrand "math/rand"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+	"github.com/ChainSafe/gossamer/lib/common/variadic"
+)
+
+// bodyGap is a range of the canonical chain whose headers are stored but whose bodies may be missing
+type bodyGap struct {
+	next *big.Int // lowest block of the range whose body hasn't been received yet
+	end  *big.Int
+}
+
+// FillBodyGaps schedules the download of the bodies missing between the given blocks of the canonical chain, eg.
+// after importing a chain of headers. Bodies are requested on their own and attached to the stored headers, so
+// the blocks aren't downloaded and verified again. It returns the first request to send, or nil if no body is
+// missing.
+func (s *Service) FillBodyGaps(start, end *big.Int) *network.BlockRequestMessage {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+
+	s.gap = &bodyGap{
+		next: new(big.Int).Set(start),
+		end:  new(big.Int).Set(end),
+	}
+
+	return s.nextBodyRequest()
+}
+
+// loadBodyGap schedules the download of the bodies missing from the head of the canonical chain, as left by a
+// previous run in headers-only mode. The range is found by walking back from the best block until a block with a
+// body, so it only costs a lookup when there is no gap. The first request is sent once the node is synced.
+func (s *Service) loadBodyGap() {
+	if s.blockState.HeadersOnly() {
+		return
+	}
+
+	best, err := s.blockState.BestBlockNumber()
+	if err != nil {
+		s.logger.Warn("failed to get best block number", "error", err)
+		return
+	}
+
+	start := new(big.Int).Set(best)
+	for ; start.Sign() > 0; start.Sub(start, big.NewInt(1)) {
+		var hash *common.Hash
+		hash, err = s.blockState.GetBlockHash(start)
+		if err != nil {
+			s.logger.Warn("failed to get hash of block", "number", start, "error", err)
+			return
+		}
+
+		var has bool
+		has, err = s.blockState.HasBlockBody(*hash)
+		if err != nil {
+			s.logger.Warn("failed to check for block body", "hash", hash, "error", err)
+			return
+		}
+
+		if has {
+			break
+		}
+	}
+
+	if start.Cmp(best) == 0 {
+		return
+	}
+
+	start.Add(start, big.NewInt(1))
+	s.logger.Info("found blocks with missing bodies", "start", start, "end", best)
+	s.FillBodyGaps(start, best)
+}
+
+// nextBodyRequest returns a request for the bodies following the first missing body of the gap being filled,
+// or nil if there is none. It must be called with gapLock held.
+func (s *Service) nextBodyRequest() *network.BlockRequestMessage {
+	if s.gap == nil {
+		return nil
+	}
+
+	var hash *common.Hash
+	for ; s.gap.next.Cmp(s.gap.end) <= 0; s.gap.next.Add(s.gap.next, big.NewInt(1)) {
+		var err error
+		hash, err = s.blockState.GetBlockHash(s.gap.next)
+		if err != nil {
+			s.logger.Warn("failed to get hash of block with missing body", "number", s.gap.next, "error", err)
+			s.gap = nil
+			return nil
+		}
+
+		has, err := s.blockState.HasBlockBody(*hash)
+		if err != nil {
+			s.logger.Warn("failed to check for block body", "hash", hash, "error", err)
+			s.gap = nil
+			return nil
+		}
+
+		if !has {
+			break
+		}
+	}
+
+	if s.gap.next.Cmp(s.gap.end) > 0 {
+		s.logger.Info("filled missing block bodies", "end", s.gap.end)
+		s.gap = nil
+		return nil
+	}
+
+	start, err := variadic.NewUint64OrHash(*hash)
+	if err != nil {
+		s.logger.Error("failed to create block body request start block", "error", err)
+		return nil
+	}
+
+	max := new(big.Int).Sub(s.gap.end, s.gap.next).Int64() + 1
+	if max > maxResponseSize {
+		max = maxResponseSize
+	}
+
+	s.logger.Debug("sending block body request", "start", s.gap.next, "max", max)
+
+	return &network.BlockRequestMessage{
+		ID:            mrand.Uint64(), //nolint
+		RequestedData: network.RequestedDataBody,
+		StartingBlock: start,
+		EndBlockHash:  optional.NewHash(false, common.Hash{}),
+		Direction:     1,
+		Max:           optional.NewUint32(true, uint32(max)),
+	}
+}
+
+// isBodyResponse returns true if the response only contains block bodies, as sent in response to a request
+// created by nextBodyRequest
+func isBodyResponse(msg *network.BlockResponseMessage) bool {
+	if msg == nil || len(msg.BlockData) == 0 {
+		return false
+	}
+
+	for _, bd := range msg.BlockData {
+		if bd.Header.Exists() || bd.Body == nil || !bd.Body.Exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// handleBodyResponse attaches the bodies of a response to a block body request to their headers, and returns the
+// request for the next missing bodies
+func (s *Service) handleBodyResponse(msg *network.BlockResponseMessage) *network.BlockRequestMessage {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+
+	for _, bd := range msg.BlockData {
+		// the peer may send bodies past the end of the gap
+		if has, _ := s.blockState.HasHeader(bd.Hash); !has {
+			continue
+		}
+
+		body, err := types.NewBodyFromOptional(bd.Body)
+		if err != nil {
+			s.logger.Warn("failed to decode block body", "hash", bd.Hash, "error", err)
+			return nil
+		}
+
+		// a body that doesn't match its header stops the request chain, the gap is resumed with the next peer
+		err = s.blockState.AttachBlockBody(bd.Hash, body)
+		if err != nil {
+			s.logger.Warn("failed to attach block body", "hash", bd.Hash, "error", err)
+			return nil
+		}
+
+		err = s.handleBody(body)
+		if err != nil {
+			return nil
+		}
+
+		s.logger.Trace("attached block body", "hash", bd.Hash)
+	}
+
+	return s.nextBodyRequest()
+}
+
+// resumeBodyGap returns the request for the next missing bodies of the gap being filled, if any
+func (s *Service) resumeBodyGap() *network.BlockRequestMessage {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+	return s.nextBodyRequest()
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network"
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+	"github.com/ChainSafe/gossamer/lib/scale"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBlockWithBody returns a block with a single extrinsic and a matching extrinsics root
+func newTestBlockWithBody(t *testing.T, parent *types.Header, ext types.Extrinsic) *types.Block {
+	tr := trie.NewEmptyTrie()
+	key, err := scale.Encode(big.NewInt(0))
+	require.NoError(t, err)
+	value, err := scale.Encode([]byte(ext))
+	require.NoError(t, err)
+	require.NoError(t, tr.Put(key, value))
+
+	root, err := tr.Hash()
+	require.NoError(t, err)
+
+	body, err := types.NewBodyFromExtrinsics([]types.Extrinsic{ext})
+	require.NoError(t, err)
+
+	return &types.Block{
+		Header: &types.Header{
+			ParentHash:     parent.Hash(),
+			Number:         new(big.Int).Add(parent.Number, big.NewInt(1)),
+			StateRoot:      trie.EmptyHash,
+			ExtrinsicsRoot: root,
+			Digest:         types.NewEmptyDigest(),
+		},
+		Body: body,
+	}
+}
+
+func TestFillBodyGaps(t *testing.T) {
+	syncer := newTestSyncer(t)
+	responder := newTestSyncer(t)
+
+	// the syncer only stores the headers of the blocks
+	bs := syncer.blockState.(*state.BlockState)
+	bs.SetHeadersOnly(true)
+
+	parent := testGenesisHeader
+	hashes := []common.Hash{}
+	for i := 0; i < 3; i++ {
+		block := newTestBlockWithBody(t, parent, types.Extrinsic{byte(i)})
+		require.NoError(t, bs.AddBlock(block))
+		require.NoError(t, responder.blockState.AddBlock(block))
+		parent = block.Header
+		hashes = append(hashes, block.Header.Hash())
+	}
+
+	bs.SetHeadersOnly(false)
+
+	req := syncer.FillBodyGaps(big.NewInt(1), big.NewInt(3))
+	require.NotNil(t, req)
+	require.Equal(t, hashes[0], req.StartingBlock.Value())
+	require.Equal(t, uint32(3), req.Max.Value())
+
+	resp, err := responder.CreateBlockResponse(req)
+	require.NoError(t, err)
+	require.Len(t, resp.BlockData, 3)
+	require.True(t, isBodyResponse(resp))
+
+	// all the bodies were attached, so there is nothing left to request
+	require.Nil(t, syncer.HandleBlockResponse(resp))
+	for _, hash := range hashes {
+		has, err := bs.HasBlockBody(hash)
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+
+	require.Nil(t, syncer.FillBodyGaps(big.NewInt(1), big.NewInt(3)))
+}
+
+func TestFillBodyGaps_Mismatch(t *testing.T) {
+	syncer := newTestSyncer(t)
+	bs := syncer.blockState.(*state.BlockState)
+	bs.SetHeadersOnly(true)
+
+	block := newTestBlockWithBody(t, testGenesisHeader, types.Extrinsic{1})
+	require.NoError(t, bs.AddBlock(block))
+	bs.SetHeadersOnly(false)
+
+	req := syncer.FillBodyGaps(big.NewInt(1), big.NewInt(1))
+	require.NotNil(t, req)
+
+	other, err := types.NewBodyFromExtrinsics([]types.Extrinsic{{2}})
+	require.NoError(t, err)
+	resp := &network.BlockResponseMessage{
+		ID: req.ID,
+		BlockData: []*types.BlockData{{
+			Hash:   block.Header.Hash(),
+			Header: optional.NewHeader(false, nil),
+			Body:   other.AsOptional(),
+		}},
+	}
+
+	require.Nil(t, syncer.HandleBlockResponse(resp))
+	has, err := bs.HasBlockBody(block.Header.Hash())
+	require.NoError(t, err)
+	require.False(t, has)
+
+	// the gap is requested again from the next peer
+	require.NotNil(t, syncer.HandleSeenBlocks(big.NewInt(0)))
+}
+
+func TestLoadBodyGap(t *testing.T) {
+	syncer := newTestSyncer(t)
+	bs := syncer.blockState.(*state.BlockState)
+
+	// the first block is imported with its body, the following ones in headers-only mode
+	block := newTestBlockWithBody(t, testGenesisHeader, types.Extrinsic{0})
+	require.NoError(t, bs.AddBlock(block))
+
+	bs.SetHeadersOnly(true)
+	parent := block.Header
+	hashes := []common.Hash{}
+	for i := 1; i < 3; i++ {
+		block = newTestBlockWithBody(t, parent, types.Extrinsic{byte(i)})
+		require.NoError(t, bs.AddBlock(block))
+		parent = block.Header
+		hashes = append(hashes, block.Header.Hash())
+	}
+
+	// the node is restarted with bodies enabled
+	bs.SetHeadersOnly(false)
+	restarted, err := NewService(&Config{
+		LogLvl:       log.LvlInfo,
+		BlockState:   bs,
+		StorageState: syncer.storageState,
+		Runtime:      syncer.runtime,
+		Verifier:     syncer.verifier,
+	})
+	require.NoError(t, err)
+
+	req := restarted.HandleSeenBlocks(big.NewInt(0))
+	require.NotNil(t, req)
+	require.Equal(t, network.RequestedDataBody, req.RequestedData)
+	require.Equal(t, hashes[0], req.StartingBlock.Value())
+	require.Equal(t, uint32(2), req.Max.Value())
+
+	// the bodies aren't fetched while the node stays in headers-only mode
+	bs.SetHeadersOnly(true)
+	restarted, err = NewService(&Config{
+		LogLvl:       log.LvlInfo,
+		BlockState:   bs,
+		StorageState: syncer.storageState,
+		Runtime:      syncer.runtime,
+		Verifier:     syncer.verifier,
+	})
+	require.NoError(t, err)
+	require.Nil(t, restarted.HandleSeenBlocks(big.NewInt(0)))
+}
@@ -39,6 +39,9 @@ var ErrServiceStopped = errors.New("service has been stopped")
 // ErrInvalidBlock is returned when a block cannot be verified
 var ErrInvalidBlock = errors.New("could not verify block")
 
+// ErrFutureSlot is returned when an announced block's slot is too far ahead of our current slot
+var ErrFutureSlot = errors.New("block slot is too far in the future")
+
 // ErrNilChannel is returned if a channel is nil
 func ErrNilChannel(s string) error {
 	return fmt.Errorf("cannot have nil channel %s", s)
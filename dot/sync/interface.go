@@ -31,20 +31,35 @@ type BlockState interface {
 	AddBlock(*types.Block) error
 	CompareAndSetBlockData(bd *types.BlockData) error
 	GetBlockByNumber(*big.Int) (*types.Block, error)
+	GetBlockHash(*big.Int) (*common.Hash, error)
 	GetBlockBody(common.Hash) (*types.Body, error)
+	HasBlockBody(common.Hash) (bool, error)
+	AttachBlockBody(common.Hash, *types.Body) error
+	HeadersOnly() bool
 	SetHeader(*types.Header) error
 	GetHeader(common.Hash) (*types.Header, error)
+	GetHeaderEncoding(common.Hash) ([]byte, error)
 	HasHeader(hash common.Hash) (bool, error)
 	SubChain(start, end common.Hash) ([]common.Hash, error)
 	GetReceipt(common.Hash) ([]byte, error)
 	GetMessageQueue(common.Hash) ([]byte, error)
 	GetJustification(common.Hash) ([]byte, error)
+	PrefetchBlocks(hashes []common.Hash)
+	GetSlotForBlock(common.Hash) (uint64, error)
+	GetArrivalTime(common.Hash) (uint64, error)
+	SetSyncProgress(*types.SyncProgress) error
+	GetSyncProgress() (*types.SyncProgress, error)
+	DeleteSyncProgress() error
 }
 
 // StorageState is the interface for the storage state
 type StorageState interface {
 	TrieState(root *common.Hash) (*state.TrieState, error)
+	TrieStateCopy(root *common.Hash) (*state.TrieState, error)
 	StoreTrie(root common.Hash, ts *state.TrieState) error
+	PrefetchTrie(root common.Hash)
+	Lock()
+	Unlock()
 }
 
 // TransactionState is the interface for transaction queue methods
@@ -69,3 +84,8 @@ type DigestHandler interface {
 type Verifier interface {
 	VerifyBlock(header *types.Header) (bool, error)
 }
+
+// BlockRules checks the bad blocks and fork blocks of the chain spec
+type BlockRules interface {
+	Check(header *types.Header) error
+}
@@ -37,12 +37,12 @@ func (s *Service) CreateBlockResponse(blockRequest *network.BlockRequestMessage)
 		if startBlock == 0 {
 			startBlock = 1
 		}
-		block, err := s.blockState.GetBlockByNumber(big.NewInt(0).SetUint64(startBlock))
+		hash, err := s.blockState.GetBlockHash(big.NewInt(0).SetUint64(startBlock))
 		if err != nil {
 			return nil, err
 		}
 
-		startHash = block.Header.Hash()
+		startHash = *hash
 	case common.Hash:
 		startHash = startBlock
 	}
@@ -67,6 +67,12 @@ func (s *Service) CreateBlockResponse(blockRequest *network.BlockRequestMessage)
 		subchain = subchain[:maxResponseSize]
 	}
 
+	if blockRequest.Max != nil && blockRequest.Max.Exists() {
+		if max := int(blockRequest.Max.Value()); max > 0 && len(subchain) > max {
+			subchain = subchain[:max]
+		}
+	}
+
 	s.logger.Trace("subchain", "start", subchain[0], "end", subchain[len(subchain)-1])
 
 	responseData := []*types.BlockData{}
@@ -87,7 +93,17 @@ func (s *Service) CreateBlockResponse(blockRequest *network.BlockRequestMessage)
 		if (blockRequest.RequestedData & network.RequestedDataHeader) == 1 {
 			retData, err := s.blockState.GetHeader(hash)
 			if err == nil && retData != nil {
-				blockData.Header = retData.AsOptional()
+				optHeader, err := retData.AsOptional()
+				if err != nil {
+					return nil, err
+				}
+				blockData.Header = optHeader
+
+				// the header's cached encoding is sent instead of encoding it again
+				blockData.HeaderEncoding, err = s.blockState.GetHeaderEncoding(hash)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 
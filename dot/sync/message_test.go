@@ -24,7 +24,7 @@ func addTestBlocksToState(t *testing.T, depth int, blockState BlockState) {
 				ParentHash: previousHash,
 				Number:     big.NewInt(int64(i)).Add(previousNum, big.NewInt(int64(i))),
 				StateRoot:  trie.EmptyHash,
-				Digest:     [][]byte{},
+				Digest:     types.NewEmptyDigest(),
 			},
 			Body: &types.Body{},
 		}
@@ -44,6 +44,8 @@ func TestService_CreateBlockResponse(t *testing.T) {
 	bestHash := s.blockState.BestBlockHash()
 	bestBlock, err := s.blockState.GetBlockByNumber(big.NewInt(1))
 	require.NoError(t, err)
+	bestHeader, err := bestBlock.Header.AsOptional()
+	require.NoError(t, err)
 
 	// set some nils and check no error is thrown
 	bds := &types.BlockData{
@@ -93,7 +95,7 @@ func TestService_CreateBlockResponse(t *testing.T) {
 				BlockData: []*types.BlockData{
 					{
 						Hash:   optional.NewHash(true, bestHash).Value(),
-						Header: bestBlock.Header.AsOptional(),
+						Header: bestHeader,
 						Body:   bestBlock.Body.AsOptional(),
 					},
 				},
@@ -115,7 +117,7 @@ func TestService_CreateBlockResponse(t *testing.T) {
 				BlockData: []*types.BlockData{
 					{
 						Hash:   optional.NewHash(true, bestHash).Value(),
-						Header: bestBlock.Header.AsOptional(),
+						Header: bestHeader,
 						Body:   optional.NewBody(false, nil),
 					},
 				},
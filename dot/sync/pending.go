@@ -0,0 +1,162 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	gosync "sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+)
+
+// maxPendingBlocks is the maximum number of blocks held while waiting for their parent
+var maxPendingBlocks = 512
+
+// pendingBlock is a block that arrived before its parent
+type pendingBlock struct {
+	parent common.Hash
+	data   *types.BlockData
+}
+
+// pendingBlocks holds blocks that arrived before their parent, keyed by the parent's hash, so that they can be
+// imported as soon as the parent is, rather than being discarded and downloaded again. When it's full, the
+// blocks that were added first are dropped.
+type pendingBlocks struct {
+	lock     gosync.Mutex
+	limit    int
+	blocks   map[common.Hash]*pendingBlock
+	byParent map[common.Hash][]common.Hash
+	order    []common.Hash // block hashes, in the order they were added
+}
+
+func newPendingBlocks(limit int) *pendingBlocks {
+	return &pendingBlocks{
+		limit:    limit,
+		blocks:   make(map[common.Hash]*pendingBlock),
+		byParent: make(map[common.Hash][]common.Hash),
+	}
+}
+
+// add holds the block with the given hash until its parent is imported
+func (p *pendingBlocks) add(hash, parent common.Hash, bd *types.BlockData) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, has := p.blocks[hash]; has {
+		return
+	}
+
+	for len(p.order) >= p.limit {
+		p.remove(p.order[0])
+	}
+
+	p.blocks[hash] = &pendingBlock{
+		parent: parent,
+		data:   bd,
+	}
+	p.byParent[parent] = append(p.byParent[parent], hash)
+	p.order = append(p.order, hash)
+}
+
+// take removes and returns the blocks waiting for the given parent
+func (p *pendingBlocks) take(parent common.Hash) []*types.BlockData {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	hashes := append([]common.Hash{}, p.byParent[parent]...)
+	children := make([]*types.BlockData, len(hashes))
+	for i, hash := range hashes {
+		children[i] = p.blocks[hash].data
+		p.remove(hash)
+	}
+
+	return children
+}
+
+// len returns the number of blocks held
+func (p *pendingBlocks) len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.order)
+}
+
+// size returns the approximate number of bytes held by the blocks
+func (p *pendingBlocks) size() uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var size uint64
+	for _, block := range p.blocks {
+		size += blockDataSize(block.data)
+	}
+	return size
+}
+
+// blockDataSize returns the approximate number of bytes held by the block data
+func blockDataSize(bd *types.BlockData) uint64 {
+	size := uint64(len(bd.Hash))
+
+	if header := bd.Header.Value(); bd.Header.Exists() && header != nil {
+		size += uint64(len(header.ParentHash) + len(header.StateRoot) + len(header.ExtrinsicsRoot))
+		for _, d := range header.Digest {
+			size += uint64(len(d))
+		}
+	}
+
+	if bd.Body != nil && bd.Body.Exists {
+		size += uint64(len(bd.Body.Value))
+	}
+
+	for _, opt := range []*optional.Bytes{bd.Receipt, bd.MessageQueue, bd.Justification} {
+		if opt != nil && opt.Exists() {
+			size += uint64(len(opt.Value()))
+		}
+	}
+
+	return size
+}
+
+// remove drops the block with the given hash. It must be called with the lock held.
+func (p *pendingBlocks) remove(hash common.Hash) {
+	block, has := p.blocks[hash]
+	if !has {
+		return
+	}
+	delete(p.blocks, hash)
+
+	siblings := p.byParent[block.parent]
+	for i, h := range siblings {
+		if h == hash {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+
+	if len(siblings) == 0 {
+		delete(p.byParent, block.parent)
+	} else {
+		p.byParent[block.parent] = siblings
+	}
+
+	for i, h := range p.order {
+		if h == hash {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingBlocks(t *testing.T) {
+	p := newPendingBlocks(3)
+
+	parent := common.Hash{0x1}
+	a := &types.BlockData{Hash: common.Hash{0xa}}
+	b := &types.BlockData{Hash: common.Hash{0xb}}
+	c := &types.BlockData{Hash: common.Hash{0xc}}
+
+	p.add(a.Hash, parent, a)
+	p.add(b.Hash, parent, b)
+	p.add(b.Hash, parent, b)
+	p.add(c.Hash, a.Hash, c)
+	require.Equal(t, 3, p.len())
+
+	require.Equal(t, []*types.BlockData{a, b}, p.take(parent))
+	require.Equal(t, 1, p.len())
+	require.Empty(t, p.take(parent))
+
+	require.Equal(t, []*types.BlockData{c}, p.take(a.Hash))
+	require.Equal(t, 0, p.len())
+}
+
+func TestPendingBlocks_Limit(t *testing.T) {
+	p := newPendingBlocks(2)
+
+	for i := byte(1); i <= 3; i++ {
+		bd := &types.BlockData{Hash: common.Hash{i}}
+		p.add(bd.Hash, common.Hash{0xff}, bd)
+	}
+
+	// the first block added is dropped
+	children := p.take(common.Hash{0xff})
+	require.Equal(t, 2, len(children))
+	require.Equal(t, common.Hash{2}, children[0].Hash)
+	require.Equal(t, common.Hash{3}, children[1].Hash)
+}
+
+func TestPendingBlocks_Size(t *testing.T) {
+	p := newPendingBlocks(2)
+	require.Equal(t, uint64(0), p.size())
+
+	bd := &types.BlockData{
+		Hash: common.Hash{0xa},
+		Body: optional.NewBody(true, make([]byte, 100)),
+	}
+	p.add(bd.Hash, common.Hash{0xff}, bd)
+	require.Equal(t, uint64(len(bd.Hash)+100), p.size())
+
+	p.take(common.Hash{0xff})
+	require.Equal(t, uint64(0), p.size())
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package sync
+
+import (
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// readAhead prefetches the database entries that importing the given blocks reads, so that importing them
+// doesn't stall on database latency: the headers and bodies of blocks that are already stored (eg. when
+// re-syncing after a restart), the parent of the first block, and the parent's state trie.
+func (s *Service) readAhead(blockData []*types.BlockData) {
+	if len(blockData) == 0 {
+		return
+	}
+
+	hashes := make([]common.Hash, 0, len(blockData)+1)
+
+	var first *types.Header
+	if blockData[0].Header.Exists() {
+		var err error
+		first, err = types.NewHeaderFromOptional(blockData[0].Header)
+		if err == nil {
+			hashes = append(hashes, first.ParentHash)
+		}
+	}
+
+	for _, bd := range blockData {
+		hashes = append(hashes, bd.Hash)
+	}
+
+	s.blockState.PrefetchBlocks(hashes)
+
+	if first == nil {
+		return
+	}
+
+	go func() {
+		parent, err := s.blockState.GetHeader(first.ParentHash)
+		if err != nil {
+			return
+		}
+
+		s.storageState.PrefetchTrie(parent.StateRoot)
+	}()
+}
@@ -17,10 +17,13 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	mrand "math/rand"
 	"os"
+	gosync "sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network"
@@ -30,6 +33,7 @@ import (
 	"github.com/ChainSafe/gossamer/lib/common/optional"
 	"github.com/ChainSafe/gossamer/lib/common/variadic"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/trace"
 
 	"github.com/ChainSafe/chaindb"
 	log "github.com/ChainSafe/log15"
@@ -56,11 +60,30 @@ type Service struct {
 	// BABE verification
 	verifier Verifier
 
+	// bad blocks and fork blocks of the chain spec, may be nil
+	blockRules BlockRules
+
 	// Consensus digest handling
 	digestHandler DigestHandler
 
 	// Benchmarker
 	benchmarker *benchmarker
+
+	// slot duration, used to reject announced blocks from future slots
+	slotDuration time.Duration
+	now          func() time.Time
+
+	// resuming is true until the first peer is seen after resuming a sync interrupted by a restart
+	resuming bool
+	// status is the *types.SyncState of the current sync, read by the RPC
+	status atomic.Value
+
+	// range of blocks whose bodies are being downloaded, see FillBodyGaps
+	gap     *bodyGap
+	gapLock gosync.Mutex
+
+	// blocks that arrived before their parent
+	pending *pendingBlocks
 }
 
 // Config is the configuration for the sync Service.
@@ -73,6 +96,8 @@ type Config struct {
 	Runtime          runtime.LegacyInstance
 	Verifier         Verifier
 	DigestHandler    DigestHandler
+	SlotDuration     time.Duration // if 0, the slots of announced blocks are not checked
+	BlockRules       BlockRules    // if nil, all blocks are accepted
 }
 
 // NewService returns a new *sync.Service
@@ -102,7 +127,7 @@ func NewService(cfg *Config) (*Service, error) {
 	handler = log.CallerFileHandler(handler)
 	logger.SetHandler(log.LvlFilterHandler(cfg.LogLvl, handler))
 
-	return &Service{
+	s := &Service{
 		logger:           logger,
 		blockState:       cfg.BlockState,
 		storageState:     cfg.StorageState,
@@ -112,14 +137,163 @@ func NewService(cfg *Config) (*Service, error) {
 		transactionState: cfg.TransactionState,
 		runtime:          cfg.Runtime,
 		verifier:         cfg.Verifier,
+		blockRules:       cfg.BlockRules,
 		digestHandler:    cfg.DigestHandler,
 		benchmarker:      newBenchmarker(logger),
-	}, nil
+		slotDuration:     cfg.SlotDuration,
+		now:              time.Now,
+		pending:          newPendingBlocks(maxPendingBlocks),
+	}
+
+	s.setStatus(big.NewInt(0), false)
+	s.loadSyncProgress()
+	s.loadBodyGap()
+	return s, nil
+}
+
+// loadSyncProgress resumes the sync that was in progress when the node was stopped, if any. The sync continues
+// from the best block with the first peer that is ahead of it, towards the target persisted before the restart.
+func (s *Service) loadSyncProgress() {
+	progress, err := s.blockState.GetSyncProgress()
+	if err != nil {
+		if err != chaindb.ErrKeyNotFound {
+			s.logger.Warn("failed to load sync progress", "error", err)
+		}
+		return
+	}
+
+	bestNum, err := s.blockState.BestBlockNumber()
+	if err != nil {
+		s.logger.Warn("failed to get best block number", "error", err)
+		return
+	}
+
+	if bestNum.Cmp(progress.Target) >= 0 {
+		s.deleteSyncProgress()
+		return
+	}
+
+	if has, _ := s.blockState.HasHeader(progress.LastVerifiedHash); !has {
+		s.logger.Warn("last verified block of interrupted sync not found", "hash", progress.LastVerifiedHash)
+	}
+
+	s.logger.Info("resuming interrupted sync",
+		"best", bestNum,
+		"last verified", progress.LastVerified,
+		"target", progress.Target,
+	)
+
+	s.synced = false
+	s.resuming = true
+	s.highestSeenBlock = progress.Target
+	s.setStatus(bestNum, true)
+}
+
+// resumeSync sends the first block request of a sync resumed after a restart, starting after the best block.
+// Peers that aren't ahead of the best block can't serve the request, so the sync waits for one that is.
+func (s *Service) resumeSync(blockNum *big.Int) *network.BlockRequestMessage {
+	bestNum, err := s.blockState.BestBlockNumber()
+	if err != nil {
+		s.logger.Error("failed to get best block number", "error", err)
+		return nil
+	}
+
+	if blockNum.Cmp(bestNum) <= 0 {
+		return nil
+	}
+
+	s.resuming = false
+
+	err = s.blockProducer.Pause()
+	if err != nil {
+		s.logger.Warn("failed to pause block production")
+	}
+
+	if blockNum.Cmp(s.highestSeenBlock) > 0 {
+		s.highestSeenBlock = blockNum
+		s.updateStatus()
+	}
+
+	s.saveSyncProgress()
+	return s.createBlockRequest(bestNum.Int64() + 1)
+}
+
+// saveSyncProgress persists the progress of the sync, so that it can be resumed after a restart
+func (s *Service) saveSyncProgress() {
+	best, err := s.blockState.GetHeader(s.blockState.BestBlockHash())
+	if err != nil {
+		s.logger.Warn("failed to get best block header", "error", err)
+		return
+	}
+
+	err = s.blockState.SetSyncProgress(&types.SyncProgress{
+		Target:           new(big.Int).Set(s.highestSeenBlock),
+		LastVerified:     best.Number,
+		LastVerifiedHash: best.Hash(),
+	})
+	if err != nil {
+		s.logger.Warn("failed to save sync progress", "error", err)
+	}
+}
+
+func (s *Service) deleteSyncProgress() {
+	err := s.blockState.DeleteSyncProgress()
+	if err != nil {
+		s.logger.Warn("failed to delete sync progress", "error", err)
+	}
+}
+
+// setStatus sets the status of the sync returned by SyncState
+func (s *Service) setStatus(startingBlock *big.Int, resumed bool) {
+	s.status.Store(&types.SyncState{
+		StartingBlock: new(big.Int).Set(startingBlock),
+		HighestBlock:  new(big.Int).Set(s.highestSeenBlock),
+		Resumed:       resumed,
+	})
+}
+
+// updateStatus updates the highest block of the status of the current sync
+func (s *Service) updateStatus() {
+	status := s.status.Load().(*types.SyncState)
+	s.setStatus(status.StartingBlock, status.Resumed)
+}
+
+// SyncState returns the status of the current or last sync
+func (s *Service) SyncState() *types.SyncState {
+	status := *s.status.Load().(*types.SyncState)
+
+	var err error
+	status.CurrentBlock, err = s.blockState.BestBlockNumber()
+	if err != nil {
+		status.CurrentBlock = big.NewInt(0)
+	}
+
+	if status.HighestBlock.Cmp(status.CurrentBlock) < 0 {
+		status.HighestBlock = status.CurrentBlock
+	}
+
+	return &status
+}
+
+// MemoryUsage returns the approximate number of bytes held by the blocks waiting for their parent to be imported
+func (s *Service) MemoryUsage() uint64 {
+	return s.pending.size()
 }
 
 // HandleSeenBlocks handles a block that is newly "seen" ie. a block that a peer claims to have through a StatusMessage
 func (s *Service) HandleSeenBlocks(blockNum *big.Int) *network.BlockRequestMessage {
-	if blockNum == nil || s.highestSeenBlock.Cmp(blockNum) != -1 {
+	if blockNum == nil {
+		return nil
+	}
+
+	if s.resuming {
+		return s.resumeSync(blockNum)
+	}
+
+	if s.highestSeenBlock.Cmp(blockNum) != -1 {
+		if s.synced {
+			return s.resumeBodyGap()
+		}
 		return nil
 	}
 
@@ -133,11 +307,19 @@ func (s *Service) HandleSeenBlocks(blockNum *big.Int) *network.BlockRequestMessa
 		if err != nil {
 			s.logger.Warn("failed to pause block production")
 		}
+
+		bestNum, err := s.blockState.BestBlockNumber()
+		if err != nil {
+			bestNum = big.NewInt(0)
+		}
+		s.setStatus(bestNum, false)
 	} else {
 		start = s.highestSeenBlock.Int64()
 	}
 
 	s.highestSeenBlock = blockNum
+	s.updateStatus()
+	s.saveSyncProgress()
 	return s.createBlockRequest(start)
 }
 
@@ -179,8 +361,9 @@ func (s *Service) HandleBlockAnnounce(msg *network.BlockAnnounceMessage) *networ
 		)
 	}
 
-	// check if block body is stored in block state (ie. if we have the full block already)
-	_, err = s.blockState.GetBlockBody(header.Hash())
+	// check if the block has been imported (ie. if we have the full block already). the arrival time is
+	// checked rather than the body, as bodies aren't stored in header-only mode
+	_, err = s.blockState.GetArrivalTime(header.Hash())
 	if err != nil && err == chaindb.ErrKeyNotFound {
 		s.synced = false
 
@@ -210,6 +393,10 @@ func (s *Service) HandleBlockAnnounce(msg *network.BlockAnnounceMessage) *networ
 // HandleBlockResponse handles a BlockResponseMessage by processing the blocks found in it and adding them to the BlockState if necessary.
 // If the node is still not synced after processing, it creates and returns the next BlockRequestMessage to send.
 func (s *Service) HandleBlockResponse(msg *network.BlockResponseMessage) *network.BlockRequestMessage {
+	if isBodyResponse(msg) {
+		return s.handleBodyResponse(msg)
+	}
+
 	// highestInResp will be the highest block in the response
 	// it's set to 0 if err != nil
 	var start int64
@@ -218,7 +405,7 @@ func (s *Service) HandleBlockResponse(msg *network.BlockResponseMessage) *networ
 
 	// if we cannot find the parent block in our blocktree, we are missing some blocks, and need to request
 	// blocks from farther back in the chain
-	if err == blocktree.ErrParentNotFound || err == chaindb.ErrKeyNotFound {
+	if errors.Is(err, blocktree.ErrParentNotFound) || errors.Is(err, chaindb.ErrKeyNotFound) {
 		s.logger.Debug("got ErrParentNotFound or ErrKeyNotFound; need to request earlier blocks")
 		bestNum, err := s.blockState.BestBlockNumber() //nolint
 		if err != nil {
@@ -253,11 +440,13 @@ func (s *Service) HandleBlockResponse(msg *network.BlockResponseMessage) *networ
 				s.logger.Warn("failed to resume block production")
 			}
 			s.synced = true
+			s.deleteSyncProgress()
 		}
 		return nil
 	}
 
-	// not yet synced, send another block request for the following blocks
+	// not yet synced, record the verified batch and send another block request for the following blocks
+	s.saveSyncProgress()
 	start = high + 1
 	return s.createBlockRequest(start)
 }
@@ -276,9 +465,15 @@ func (s *Service) createBlockRequest(startInt int64) *network.BlockRequestMessag
 
 	s.logger.Debug("sending block request", "start", start)
 
+	// block header + body + justification, bodies aren't synced in headers-only mode since they're fetched on demand
+	requestedData := network.RequestedDataHeader + network.RequestedDataBody + network.RequestedDataJustification
+	if s.blockState.HeadersOnly() {
+		requestedData = network.RequestedDataHeader + network.RequestedDataJustification
+	}
+
 	blockRequest := &network.BlockRequestMessage{
-		ID:            randomID,                                                                                     // random
-		RequestedData: network.RequestedDataHeader + network.RequestedDataBody + network.RequestedDataJustification, // block header + body + justification
+		ID:            randomID, // random
+		RequestedData: requestedData,
 		StartingBlock: start,
 		EndBlockHash:  optional.NewHash(false, common.Hash{}),
 		Direction:     1,
@@ -295,76 +490,144 @@ func (s *Service) processBlockResponseData(msg *network.BlockResponseMessage) (i
 		return 0, 0, errors.New("got nil BlockResponseMessage")
 	}
 
+	s.readAhead(msg.BlockData)
+
 	blockData := msg.BlockData
 	start := maxInt64
 	end := int64(0)
+	missingParent := false
 
+	headersOnly := s.blockState.HeadersOnly()
 	for _, bd := range blockData {
-		if bd.Header.Exists() {
+		if bd.Header.Exists() && (bd.Body.Exists || headersOnly) {
 			header, err := types.NewHeaderFromOptional(bd.Header)
 			if err != nil {
 				return 0, 0, err
 			}
 
-			err = s.handleHeader(header)
-			if err != nil {
+			// hold blocks whose parent hasn't been imported yet, they're imported along with the parent
+			_, err = s.blockState.GetArrivalTime(header.ParentHash)
+			if err == chaindb.ErrKeyNotFound && header.Number.Cmp(big.NewInt(0)) != 0 {
+				s.pending.add(header.Hash(), header.ParentHash, bd)
+				missingParent = true
+				continue
+			} else if err != nil && err != chaindb.ErrKeyNotFound {
 				return start, end, err
 			}
+		}
 
-			if header.Number.Int64() < start {
-				start = header.Number.Int64()
-			}
+		header, err := s.processBlockData(bd)
+		if err != nil {
+			return start, end, err
+		}
 
-			if header.Number.Int64() > end {
-				end = header.Number.Int64()
-			}
+		if header == nil {
+			continue
 		}
 
-		if bd.Body.Exists {
-			body, err := types.NewBodyFromOptional(bd.Body)
-			if err != nil {
-				return start, end, err
-			}
+		if header.Number.Int64() < start {
+			start = header.Number.Int64()
+		}
 
-			err = s.handleBody(body)
-			if err != nil {
-				return start, end, err
-			}
+		if header.Number.Int64() > end {
+			end = header.Number.Int64()
 		}
 
-		if bd.Header.Exists() && bd.Body.Exists {
-			header, err := types.NewHeaderFromOptional(bd.Header)
-			if err != nil {
-				return 0, 0, err
-			}
+		s.importPendingBlocks(header.Hash())
+	}
 
-			body, err := types.NewBodyFromOptional(bd.Body)
-			if err != nil {
-				return 0, 0, err
-			}
+	if missingParent {
+		return start, end, blocktree.ErrParentNotFound
+	}
+
+	return start, end, nil
+}
+
+// processBlockData verifies and imports the header, body or block in the given BlockData, and returns its
+// header if it has one
+func (s *Service) processBlockData(bd *types.BlockData) (*types.Header, error) {
+	var header *types.Header
+	if bd.Header.Exists() {
+		var err error
+		header, err = types.NewHeaderFromOptional(bd.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.handleHeader(header)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if bd.Body.Exists {
+		body, err := types.NewBodyFromOptional(bd.Body)
+		if err != nil {
+			return nil, err
+		}
 
-			block := &types.Block{
+		err = s.handleBody(body)
+		if err != nil {
+			return nil, err
+		}
+
+		if header != nil {
+			err = s.handleBlock(&types.Block{
 				Header: header,
 				Body:   body,
-			}
-
-			err = s.handleBlock(block)
+			})
 			if err != nil {
-				return start, end, err
+				return nil, err
 			}
 		}
-
-		err := s.blockState.CompareAndSetBlockData(bd)
+	} else if header != nil && s.blockState.HeadersOnly() {
+		// the body isn't stored in headers-only mode, so the block is imported from its header alone
+		err := s.handleBlock(&types.Block{
+			Header: header,
+			Body:   types.NewBody([]byte{}),
+		})
 		if err != nil {
-			return start, end, err
+			return nil, err
 		}
 	}
 
-	return start, end, nil
+	err := s.blockState.CompareAndSetBlockData(bd)
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// importPendingBlocks imports the blocks that were held waiting for the given block, and their own descendants
+func (s *Service) importPendingBlocks(parent common.Hash) {
+	queue := []common.Hash{parent}
+	for len(queue) > 0 {
+		children := s.pending.take(queue[0])
+		queue = queue[1:]
+
+		for _, bd := range children {
+			header, err := s.processBlockData(bd)
+			if err != nil {
+				s.logger.Debug("failed to import pending block", "hash", bd.Hash, "error", err)
+				continue
+			}
+
+			if header != nil {
+				s.logger.Debug("imported pending block", "number", header.Number, "hash", header.Hash())
+				queue = append(queue, header.Hash())
+			}
+		}
+	}
 }
 
 // handleHeader handles headers included in BlockResponses
 func (s *Service) handleHeader(header *types.Header) error {
+	err := s.checkBlockRules(header)
+	if err != nil {
+		return err
+	}
+
 	// get block header; if exists, return
 	has, err := s.blockState.HasHeader(header.Hash())
 	if err != nil {
@@ -392,6 +655,20 @@ func (s *Service) handleHeader(header *types.Header) error {
 	return nil
 }
 
+// checkBlockRules returns an error if the block is a bad block, or doesn't match the fork block at its number
+func (s *Service) checkBlockRules(header *types.Header) error {
+	if s.blockRules == nil {
+		return nil
+	}
+
+	err := s.blockRules.Check(header)
+	if err != nil {
+		s.logger.Debug("rejecting block", "number", header.Number, "hash", header.Hash(), "error", err)
+	}
+
+	return err
+}
+
 // handleHeader handles block bodies included in BlockResponses
 func (s *Service) handleBody(body *types.Body) error {
 	exts, err := body.AsExtrinsics()
@@ -408,17 +685,31 @@ func (s *Service) handleBody(body *types.Body) error {
 }
 
 // handleHeader handles blocks (header+body) included in BlockResponses
-func (s *Service) handleBlock(block *types.Block) error {
+func (s *Service) handleBlock(block *types.Block) (err error) {
 	if block == nil || block.Header == nil {
 		return errors.New("nil block or header")
 	}
 
+	ctx, span := trace.StartSpan(context.Background(), "sync.import_block",
+		trace.Attribute{Key: "number", Value: block.Header.Number.String()},
+		trace.Attribute{Key: "hash", Value: block.Header.Hash().String()},
+	)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
 	parent, err := s.blockState.GetHeader(block.Header.ParentHash)
 	if err != nil {
 		return err
 	}
 
-	ts, err := s.storageState.TrieState(&parent.StateRoot)
+	// don't interleave with a block being authored, until the block is added
+	s.storageState.Lock()
+	defer s.storageState.Unlock()
+
+	// the block is imported on a copy of the parent's state, since the parent may have other children
+	ts, err := s.storageState.TrieStateCopy(&parent.StateRoot)
 	if err != nil {
 		return err
 	}
@@ -431,16 +722,20 @@ func (s *Service) handleBlock(block *types.Block) error {
 	// 	return err
 	// }
 
-	err = s.storageState.StoreTrie(block.Header.StateRoot, ts)
+	err = trace.WithSpan(ctx, "state.store_trie", func(context.Context) error {
+		return s.storageState.StoreTrie(block.Header.StateRoot, ts)
+	})
 	if err != nil {
 		return err
 	}
 
-	err = s.blockState.AddBlock(block)
+	err = trace.WithSpan(ctx, "state.add_block", func(context.Context) error {
+		return s.blockState.AddBlock(block)
+	})
 	if err != nil {
-		if err == blocktree.ErrParentNotFound && block.Header.Number.Cmp(big.NewInt(0)) != 0 {
+		if errors.Is(err, blocktree.ErrParentNotFound) && block.Header.Number.Cmp(big.NewInt(0)) != 0 {
 			return err
-		} else if err == blocktree.ErrBlockExists || block.Header.Number.Cmp(big.NewInt(0)) == 0 {
+		} else if errors.Is(err, blocktree.ErrBlockExists) || block.Header.Number.Cmp(big.NewInt(0)) == 0 {
 			// this is fine
 		} else {
 			return err
@@ -454,7 +749,9 @@ func (s *Service) handleBlock(block *types.Block) error {
 
 	// handle consensus digest for authority changes
 	if s.digestHandler != nil {
-		err = s.handleDigests(block.Header)
+		err = trace.WithSpan(ctx, "sync.handle_digests", func(context.Context) error {
+			return s.handleDigests(block.Header)
+		})
 		if err != nil {
 			return err
 		}
@@ -472,21 +769,14 @@ func (s *Service) executeBlock(block *types.Block) ([]byte, error) {
 
 func (s *Service) handleDigests(header *types.Header) error {
 	for _, d := range header.Digest {
-		dg, err := types.DecodeDigestItem(d)
-		if err != nil {
-			return err
+		cd, ok := d.(*types.ConsensusDigest)
+		if !ok {
+			continue
 		}
 
-		if dg.Type() == types.ConsensusDigestType {
-			cd, ok := dg.(*types.ConsensusDigest)
-			if !ok {
-				return errors.New("cannot cast invalid consensus digest item")
-			}
-
-			err = s.digestHandler.HandleConsensusDigest(cd)
-			if err != nil {
-				return err
-			}
+		err := s.digestHandler.HandleConsensusDigest(cd)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -97,6 +97,46 @@ func TestHandleSeenBlocks(t *testing.T) {
 	require.Equal(t, number, syncer.highestSeenBlock)
 }
 
+func TestSyncProgress_Resume(t *testing.T) {
+	syncer := newTestSyncer(t)
+
+	number := big.NewInt(12)
+	req := syncer.HandleSeenBlocks(number)
+	require.NotNil(t, req)
+
+	progress, err := syncer.blockState.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, number, progress.Target)
+	require.Equal(t, big.NewInt(0), progress.LastVerified)
+
+	// restart the syncer, the interrupted sync is resumed
+	resumed, err := NewService(&Config{
+		LogLvl:       log.LvlDebug,
+		BlockState:   syncer.blockState,
+		StorageState: syncer.storageState,
+		Runtime:      syncer.runtime,
+		Verifier:     syncer.verifier,
+	})
+	require.NoError(t, err)
+	require.False(t, resumed.synced)
+	require.Equal(t, number, resumed.highestSeenBlock)
+
+	status := resumed.SyncState()
+	require.True(t, status.Resumed)
+	require.Equal(t, number, status.HighestBlock)
+	require.Equal(t, big.NewInt(0), status.CurrentBlock)
+
+	// a peer that isn't ahead of our best block can't continue the sync
+	require.Nil(t, resumed.HandleSeenBlocks(big.NewInt(0)))
+	require.True(t, resumed.resuming)
+
+	// a peer behind the target but ahead of us continues the sync from our best block
+	req = resumed.HandleSeenBlocks(big.NewInt(8))
+	require.NotNil(t, req)
+	require.Equal(t, uint64(1), req.StartingBlock.Value().(uint64))
+	require.Equal(t, number, resumed.highestSeenBlock)
+}
+
 func TestHandleSeenBlocks_NotHighestSeen(t *testing.T) {
 	syncer := newTestSyncer(t)
 
@@ -206,6 +246,76 @@ func TestHandleBlockResponse_MissingBlocks(t *testing.T) {
 	require.Equal(t, uint64(5), req2.StartingBlock.Value().(uint64))
 }
 
+func TestHandleBlockResponse_OutOfOrder(t *testing.T) {
+	syncer := newTestSyncer(t)
+	syncer.highestSeenBlock = big.NewInt(4)
+
+	responder := newTestSyncer(t)
+	addTestBlocksToState(t, 4, responder.blockState)
+
+	start, err := variadic.NewUint64OrHash(1)
+	require.NoError(t, err)
+
+	req := &network.BlockRequestMessage{
+		ID:            1,
+		RequestedData: 3,
+		StartingBlock: start,
+	}
+
+	resp, err := responder.CreateBlockResponse(req)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(resp.BlockData))
+
+	// blocks 3 and 4 arrive first, they're held and the missing blocks are requested
+	syncer.synced = false
+	req2 := syncer.HandleBlockResponse(&network.BlockResponseMessage{BlockData: resp.BlockData[2:]})
+	require.NotNil(t, req2)
+	require.Equal(t, uint64(1), req2.StartingBlock.Value().(uint64))
+	require.Equal(t, 2, syncer.pending.len())
+
+	// blocks 3 and 4 are imported along with their parent
+	syncer.HandleBlockResponse(&network.BlockResponseMessage{BlockData: resp.BlockData[:2]})
+	require.Equal(t, 0, syncer.pending.len())
+
+	bestNum, err := syncer.blockState.BestBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(4), bestNum)
+	require.True(t, syncer.synced)
+}
+
+func TestHandleBlockResponse_HeadersOnly(t *testing.T) {
+	syncer := newTestSyncer(t)
+	syncer.highestSeenBlock = big.NewInt(4)
+	bs := syncer.blockState.(*state.BlockState)
+	bs.SetHeadersOnly(true)
+
+	responder := newTestSyncer(t)
+	addTestBlocksToState(t, 4, responder.blockState)
+
+	req := syncer.createBlockRequest(1)
+	require.NotNil(t, req)
+	require.Equal(t, network.RequestedDataHeader+network.RequestedDataJustification, req.RequestedData)
+
+	resp, err := responder.CreateBlockResponse(req)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(resp.BlockData))
+	for _, bd := range resp.BlockData {
+		require.False(t, bd.Body.Exists)
+	}
+
+	// the blocks are imported from their headers, without storing bodies
+	syncer.synced = false
+	syncer.HandleBlockResponse(resp)
+
+	bestNum, err := syncer.blockState.BestBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(4), bestNum)
+
+	has, err := bs.HasBlockBody(syncer.blockState.BestBlockHash())
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
 func TestRemoveIncludedExtrinsics(t *testing.T) {
 	syncer := newTestSyncer(t)
 
@@ -281,7 +391,7 @@ func buildBlock(t *testing.T, instance runtime.LegacyInstance, parent *types.Hea
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     big.NewInt(0).Add(parent.Number, big.NewInt(1)),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	err := instance.InitializeBlock(header)
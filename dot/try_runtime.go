@@ -0,0 +1,272 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
+	"github.com/ChainSafe/gossamer/lib/runtime/wasmtime"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	database "github.com/ChainSafe/chaindb"
+	log "github.com/ChainSafe/log15"
+)
+
+// TryRuntimeUpgradeFunc is the runtime API function called to execute the runtime's migrations
+const TryRuntimeUpgradeFunc = "TryRuntime_on_runtime_upgrade"
+
+// TryRuntimeConfig configures a dry run of a runtime upgrade against existing state
+type TryRuntimeConfig struct {
+	// BasePath the data directory of a stopped node to load state from, used if Endpoint is empty
+	BasePath string
+	// Endpoint the HTTP RPC endpoint of a live node to load state from
+	Endpoint string
+	// Code the wasm runtime to test
+	Code []byte
+	// WasmInterpreter the interpreter used to execute the runtime, wasmer is used if empty
+	WasmInterpreter string
+	LogLvl          log.Lvl
+}
+
+// StorageDiff is a storage value changed by a runtime upgrade. Before is nil if the key was added and After
+// is nil if the key was removed.
+type StorageDiff struct {
+	Key    []byte
+	Before []byte
+	After  []byte
+}
+
+// TryRuntimeResult is the outcome of executing a runtime upgrade against existing state
+type TryRuntimeResult struct {
+	// Weight the weight reported by the runtime's migrations
+	Weight uint64
+	// Diffs the storage changes made by the migrations, sorted by key
+	Diffs []*StorageDiff
+}
+
+// TryRuntime loads state from a node, replaces the runtime code with the provided code, and executes the runtime
+// upgrade, reporting the resulting storage changes and weight. The loaded state is not modified.
+func TryRuntime(cfg *TryRuntimeConfig) (*TryRuntimeResult, error) {
+	if len(cfg.Code) == 0 {
+		return nil, errors.New("no runtime code provided")
+	}
+
+	var (
+		before map[string][]byte
+		err    error
+	)
+
+	if cfg.Endpoint != "" {
+		before, err = loadStateFromRPC(cfg.Endpoint)
+	} else {
+		before, err = loadStateFromDB(cfg.BasePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	t := trie.NewEmptyTrie()
+	for k, v := range before {
+		err = t.Put([]byte(k), v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ts, err := state.NewTrieState(database.NewMemDatabase(), t)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.Set(common.CodeKey, cfg.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := newTryRuntimeInstance(cfg, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runtime: %w", err)
+	}
+	defer rt.Stop()
+
+	ret, err := rt.Exec(TryRuntimeUpgradeFunc, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s: %w", TryRuntimeUpgradeFunc, err)
+	}
+
+	res := &TryRuntimeResult{
+		Diffs: diffStorage(before, ts.Entries()),
+	}
+
+	// the runtime returns the weight used by the upgrade, followed by the maximum block weight
+	if len(ret) >= 8 {
+		res.Weight = binary.LittleEndian.Uint64(ret[:8])
+	}
+
+	return res, nil
+}
+
+func newTryRuntimeInstance(cfg *TryRuntimeConfig, ts *state.TrieState) (runtime.LegacyInstance, error) {
+	ns := runtime.NodeStorage{
+		LocalStorage:      database.NewMemDatabase(),
+		PersistentStorage: database.NewMemDatabase(),
+	}
+
+	ks := keystore.NewGenericKeystore("try-runtime")
+
+	switch cfg.WasmInterpreter {
+	case wasmer.Name, "":
+		rtCfg := &wasmer.Config{
+			Imports: wasmer.ImportsLegacyNodeRuntime,
+		}
+		rtCfg.Storage = ts
+		rtCfg.Keystore = ks
+		rtCfg.LogLvl = cfg.LogLvl
+		rtCfg.NodeStorage = ns
+		return wasmer.NewLegacyInstance(cfg.Code, rtCfg)
+	case wasmtime.Name:
+		rtCfg := &wasmtime.Config{
+			Imports: wasmtime.ImportsLegacyNodeRuntime,
+		}
+		rtCfg.Storage = ts
+		rtCfg.Keystore = ks
+		rtCfg.LogLvl = cfg.LogLvl
+		rtCfg.NodeStorage = ns
+		return wasmtime.NewLegacyInstance(cfg.Code, rtCfg)
+	default:
+		return nil, fmt.Errorf("unknown wasm interpreter %s", cfg.WasmInterpreter)
+	}
+}
+
+// loadStateFromDB returns the storage entries at the best block of the node database at basepath
+func loadStateFromDB(basepath string) (map[string][]byte, error) {
+	stateSrvc := state.NewService(basepath, log.LvlCrit)
+
+	err := stateSrvc.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = stateSrvc.Stop()
+	}()
+
+	return stateSrvc.Storage.Entries(nil)
+}
+
+// loadStateFromRPC returns the storage entries at the best block of the node with the given HTTP RPC endpoint
+func loadStateFromRPC(endpoint string) (map[string][]byte, error) {
+	body := []byte(`{"jsonrpc":"2.0","method":"state_getPairs","params":["0x"],"id":1}`)
+
+	resp, err := http.Post(endpoint, "application/json", bytes.NewReader(body)) //nolint
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Result [][]string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Error != nil {
+		return nil, errors.New(res.Error.Message)
+	}
+
+	entries := make(map[string][]byte)
+	for _, pair := range res.Result {
+		if len(pair) != 2 {
+			return nil, errors.New("invalid key-value pair in state_getPairs response")
+		}
+
+		var k, v []byte
+		k, err = common.HexToBytes(pair[0])
+		if err != nil {
+			return nil, err
+		}
+
+		v, err = common.HexToBytes(pair[1])
+		if err != nil {
+			return nil, err
+		}
+
+		entries[string(k)] = v
+	}
+
+	return entries, nil
+}
+
+// diffStorage returns the changes between the before and after storage entries, ignoring the runtime code
+func diffStorage(before, after map[string][]byte) []*StorageDiff {
+	diffs := []*StorageDiff{}
+
+	for k, v := range before {
+		if k == string(common.CodeKey) {
+			continue
+		}
+
+		av, has := after[k]
+		if !has {
+			diffs = append(diffs, &StorageDiff{Key: []byte(k), Before: v})
+		} else if !bytes.Equal(v, av) {
+			diffs = append(diffs, &StorageDiff{Key: []byte(k), Before: v, After: av})
+		}
+	}
+
+	for k, v := range after {
+		if k == string(common.CodeKey) {
+			continue
+		}
+
+		if _, has := before[k]; !has {
+			diffs = append(diffs, &StorageDiff{Key: []byte(k), After: v})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return bytes.Compare(diffs[i].Key, diffs[j].Key) < 0
+	})
+
+	return diffs
+}
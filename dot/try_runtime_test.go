@@ -0,0 +1,88 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package dot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStorage(t *testing.T) {
+	before := map[string][]byte{
+		":code":   {1},
+		"changed": {1},
+		"removed": {2},
+		"same":    {3},
+	}
+
+	after := map[string][]byte{
+		":code":   {2},
+		"added":   {4},
+		"changed": {5},
+		"same":    {3},
+	}
+
+	expected := []*StorageDiff{
+		{Key: []byte("added"), After: []byte{4}},
+		{Key: []byte("changed"), Before: []byte{1}, After: []byte{5}},
+		{Key: []byte("removed"), Before: []byte{2}},
+	}
+
+	require.Equal(t, expected, diffStorage(before, after))
+}
+
+func TestLoadStateFromRPC(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[["0x3a636f6465","0x0102"],["0x6b6579","0x"]],"id":1}`)
+	}))
+	defer srv.Close()
+
+	entries, err := loadStateFromRPC(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		":code": {1, 2},
+		"key":   {},
+	}, entries)
+}
+
+func TestTryRuntime_NoUpgradeFunction(t *testing.T) {
+	cfg := NewTestConfig(t)
+	cfg.Init.GenesisRaw = "../chain/gssmr/genesis-raw.json"
+
+	err := InitNode(cfg)
+	require.NoError(t, err)
+
+	entries, err := loadStateFromDB(cfg.Global.BasePath)
+	require.NoError(t, err)
+
+	code := entries[string(common.CodeKey)]
+	require.NotEmpty(t, code)
+
+	// the gssmr runtime does not implement the try-runtime API
+	_, err = TryRuntime(&TryRuntimeConfig{
+		BasePath: cfg.Global.BasePath,
+		Code:     code,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), TryRuntimeUpgradeFunc)
+}
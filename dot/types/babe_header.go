@@ -19,6 +19,7 @@ package types
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 )
@@ -56,3 +57,23 @@ func (bh *BabeHeader) Decode(in []byte) error {
 	bh.SlotNumber = binary.LittleEndian.Uint64(in[sr25519.VrfOutputLength+sr25519.VrfProofLength+8 : sr25519.VrfOutputLength+sr25519.VrfProofLength+16])
 	return nil
 }
+
+// GetSlotFromHeader returns the BABE slot number of the block, decoded from the BABE header in its pre-runtime digest
+func GetSlotFromHeader(header *Header) (uint64, error) {
+	if len(header.Digest) == 0 {
+		return 0, fmt.Errorf("chain head missing digest")
+	}
+
+	preDigest, ok := header.Digest[0].(*PreRuntimeDigest)
+	if !ok {
+		return 0, fmt.Errorf("first digest item is not pre-digest")
+	}
+
+	babeHeader := new(BabeHeader)
+	err := babeHeader.Decode(preDigest.Data)
+	if err != nil {
+		return 0, fmt.Errorf("cannot decode babe header from pre-digest: %s", err)
+	}
+
+	return babeHeader.SlotNumber, nil
+}
@@ -17,6 +17,7 @@
 package types
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -32,68 +33,61 @@ type BlockData struct {
 	Receipt       *optional.Bytes
 	MessageQueue  *optional.Bytes
 	Justification *optional.Bytes
+
+	// HeaderEncoding is the SCALE encoding of the header, if it's known it's written instead of encoding the
+	// header again. It isn't set when BlockData is decoded.
+	HeaderEncoding []byte
 }
 
 // Encode performs SCALE encoding of the BlockData
 func (bd *BlockData) Encode() ([]byte, error) {
-	enc := bd.Hash[:]
+	buf := &bytes.Buffer{}
+	if err := bd.encode(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encode writes the SCALE encoding of the BlockData to the buffer, without intermediate allocations
+// for each field
+func (bd *BlockData) encode(buf *bytes.Buffer) error {
+	se := scale.Encoder{Writer: buf}
+	_, _ = buf.Write(bd.Hash[:])
 
 	if bd.Header.Exists() {
-		venc, err := scale.Encode(bd.Header.Value())
-		if err != nil {
-			return nil, err
+		_ = buf.WriteByte(1) // Some
+		if bd.HeaderEncoding != nil {
+			_, _ = buf.Write(bd.HeaderEncoding)
+		} else if _, err := se.Encode(bd.Header.Value()); err != nil {
+			return err
 		}
-		enc = append(enc, byte(1)) // Some
-		enc = append(enc, venc...)
 	} else {
-		enc = append(enc, byte(0)) // None
+		_ = buf.WriteByte(0) // None
 	}
 
 	if bd.Body.Exists {
-		venc, err := scale.Encode([]byte(bd.Body.Value))
-		if err != nil {
-			return nil, err
-		}
-		enc = append(enc, byte(1)) // Some
-		enc = append(enc, venc...)
-	} else {
-		enc = append(enc, byte(0)) // None
-	}
-
-	if bd.Receipt != nil && bd.Receipt.Exists() {
-		venc, err := scale.Encode(bd.Receipt.Value())
-		if err != nil {
-			return nil, err
+		_ = buf.WriteByte(1) // Some
+		if _, err := se.Encode([]byte(bd.Body.Value)); err != nil {
+			return err
 		}
-		enc = append(enc, byte(1)) // Some
-		enc = append(enc, venc...)
 	} else {
-		enc = append(enc, byte(0)) // None
+		_ = buf.WriteByte(0) // None
 	}
 
-	if bd.MessageQueue != nil && bd.MessageQueue.Exists() {
-		venc, err := scale.Encode(bd.MessageQueue.Value())
-		if err != nil {
-			return nil, err
+	for _, opt := range []*optional.Bytes{bd.Receipt, bd.MessageQueue, bd.Justification} {
+		if opt == nil || !opt.Exists() {
+			_ = buf.WriteByte(0) // None
+			continue
 		}
-		enc = append(enc, byte(1)) // Some
-		enc = append(enc, venc...)
-	} else {
-		enc = append(enc, byte(0)) // None
-	}
 
-	if bd.Justification != nil && bd.Justification.Exists() {
-		venc, err := scale.Encode(bd.Justification.Value())
-		if err != nil {
-			return nil, err
+		_ = buf.WriteByte(1) // Some
+		if _, err := se.Encode(opt.Value()); err != nil {
+			return err
 		}
-		enc = append(enc, byte(1)) // Some
-		enc = append(enc, venc...)
-	} else {
-		enc = append(enc, byte(0)) // None
 	}
 
-	return enc, nil
+	return nil
 }
 
 // Decode decodes the SCALE encoded input to BlockData
@@ -134,20 +128,19 @@ func (bd *BlockData) Decode(r io.Reader) error {
 
 // EncodeBlockDataArray encodes an array of BlockData using SCALE
 func EncodeBlockDataArray(bds []*BlockData) ([]byte, error) {
-	enc, err := scale.Encode(int32(len(bds)))
-	if err != nil {
+	buf := &bytes.Buffer{}
+	se := scale.Encoder{Writer: buf}
+	if _, err := se.Encode(int32(len(bds))); err != nil {
 		return nil, err
 	}
 
 	for _, bd := range bds {
-		benc, err := bd.Encode()
-		if err != nil {
+		if err := bd.encode(buf); err != nil {
 			return nil, err
 		}
-		enc = append(enc, benc...)
 	}
 
-	return enc, nil
+	return buf.Bytes(), nil
 }
 
 // DecodeBlockDataArray decodes a SCALE encoded BlockData array
@@ -87,6 +87,47 @@ func TestBlockDataEncodeHeader(t *testing.T) {
 	}
 }
 
+func TestBlockDataEncodeHeaderEncoding(t *testing.T) {
+	header := &Header{
+		ParentHash: common.Hash{1},
+		Number:     big.NewInt(1),
+		StateRoot:  common.Hash{2},
+		Digest: Digest{
+			&PreRuntimeDigest{ConsensusEngineID: BabeEngineID, Data: []byte{1, 2, 3}},
+		},
+	}
+
+	optHeader, err := header.AsOptional()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bd := &BlockData{
+		Hash:          header.Hash(),
+		Header:        optHeader,
+		Body:          optional.NewBody(false, nil),
+		Receipt:       optional.NewBytes(false, nil),
+		MessageQueue:  optional.NewBytes(false, nil),
+		Justification: optional.NewBytes(false, nil),
+	}
+
+	expected, err := bd.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the header's encoding is written as is
+	bd.HeaderEncoding = header.MustEncode()
+	enc, err := bd.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expected, enc) {
+		t.Fatalf("Fail: got %x expected %x", enc, expected)
+	}
+}
+
 func TestBlockDataEncodeBody(t *testing.T) {
 	hash := common.NewHash([]byte{0})
 	body := optional.CoreBody{0xa, 0xb, 0xc, 0xd}
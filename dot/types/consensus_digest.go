@@ -19,37 +19,17 @@ var PauseType = byte(4)
 // ResumeType identifies a Resume consensus digest
 var ResumeType = byte(5)
 
-// BABEScheduledChange represents a BABE scheduled authority change
-type BABEScheduledChange struct {
-	Auths []*AuthorityRaw
-	Delay uint32
-}
+// BABE consensus digests are Substrate's BABE ConsensusLog, whose types differ from the GRANDPA ones above.
+// Pause and Resume digests are shared by both engines.
 
-// Encode returns a SCALE encoded BABEScheduledChange with first type byte
-func (sc *BABEScheduledChange) Encode() ([]byte, error) {
-	d, err := scale.Encode(sc)
-	if err != nil {
-		return nil, err
-	}
-
-	return append([]byte{ScheduledChangeType}, d...), nil
-}
-
-// BABEForcedChange represents a BABE forced authority change
-type BABEForcedChange struct {
-	Auths []*AuthorityRaw
-	Delay uint32
-}
+// NextEpochDataType identifies a BABE NextEpochData consensus digest
+var NextEpochDataType = byte(1)
 
-// Encode returns a SCALE encoded BABEForcedChange with first type byte
-func (fc *BABEForcedChange) Encode() ([]byte, error) {
-	d, err := scale.Encode(fc)
-	if err != nil {
-		return nil, err
-	}
+// BABEOnDisabledType identifies a BABE OnDisabled consensus digest
+var BABEOnDisabledType = byte(2)
 
-	return append([]byte{ForcedChangeType}, d...), nil
-}
+// NextConfigDataType identifies a BABE NextConfigData consensus digest
+var NextConfigDataType = byte(3)
 
 // GrandpaScheduledChange represents a GRANDPA scheduled authority change
 type GrandpaScheduledChange struct {
@@ -127,3 +107,54 @@ func (r *Resume) Encode() ([]byte, error) {
 
 	return append([]byte{ResumeType}, d...), nil
 }
+
+// NextEpochData is the BABE authorities and randomness of the next epoch, announced in the first block of an epoch
+type NextEpochData struct {
+	Authorities []*AuthorityRaw
+	Randomness  [RandomnessLength]byte
+}
+
+// Encode returns a SCALE encoded NextEpochData with first type byte
+func (d *NextEpochData) Encode() ([]byte, error) {
+	enc, err := scale.Encode(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{NextEpochDataType}, enc...), nil
+}
+
+// BABEOnDisabled represents a BABE authority being disabled, by its index in the authority set
+type BABEOnDisabled struct {
+	ID uint32
+}
+
+// Encode returns a SCALE encoded BABEOnDisabled with first type byte
+func (od *BABEOnDisabled) Encode() ([]byte, error) {
+	d, err := scale.Encode(od)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{BABEOnDisabledType}, d...), nil
+}
+
+// nextConfigDataVersion is the version of the only NextConfigData format, NextConfigDescriptor::V1 in Substrate
+const nextConfigDataVersion = 1
+
+// NextConfigData is the BABE configuration of the next epoch, announced in the first block of an epoch
+type NextConfigData struct {
+	C1             uint64
+	C2             uint64
+	SecondarySlots byte
+}
+
+// Encode returns a SCALE encoded NextConfigData with first type byte and version
+func (d *NextConfigData) Encode() ([]byte, error) {
+	enc, err := scale.Encode(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{NextConfigDataType, nextConfigDataVersion}, enc...), nil
+}
@@ -18,6 +18,8 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/scale"
@@ -46,6 +48,9 @@ var BabeEngineID = ConsensusEngineID{'B', 'A', 'B', 'E'}
 // GrandpaEngineID is the hard-coded grandpa ID
 var GrandpaEngineID = ConsensusEngineID{'F', 'R', 'N', 'K'}
 
+// OtherDigestType is the byte representation of OtherDigest
+var OtherDigestType = byte(0)
+
 // ChangesTrieRootDigestType is the byte representation of ChangesTrieRootDigest
 var ChangesTrieRootDigestType = byte(2)
 
@@ -58,35 +63,44 @@ var ConsensusDigestType = byte(4)
 // SealDigestType is the byte representation of SealDigest
 var SealDigestType = byte(5)
 
-// DecodeDigestItem will decode byte array to DigestItem
+// RuntimeEnvironmentUpdatedDigestType is the byte representation of RuntimeEnvironmentUpdatedDigest
+var RuntimeEnvironmentUpdatedDigestType = byte(8)
+
+// DecodeDigestItem will decode byte array to DigestItem. Items of unknown types are decoded as an UnknownDigest.
 func DecodeDigestItem(in []byte) (DigestItem, error) {
-	if len(in) < 2 {
+	if len(in) == 0 {
 		return nil, errors.New("cannot decode invalid digest encoding")
 	}
 
+	var d DigestItem
 	switch in[0] {
+	case OtherDigestType:
+		d = new(OtherDigest)
 	case ChangesTrieRootDigestType:
-		d := new(ChangesTrieRootDigest)
-		err := d.Decode(in[1:])
-		return d, err
+		d = new(ChangesTrieRootDigest)
 	case PreRuntimeDigestType:
-		d := new(PreRuntimeDigest)
-		err := d.Decode(in[1:])
-		return d, err
+		d = new(PreRuntimeDigest)
 	case ConsensusDigestType:
-		d := new(ConsensusDigest)
-		err := d.Decode(in[1:])
-		return d, err
+		d = new(ConsensusDigest)
 	case SealDigestType:
-		d := new(SealDigest)
-		err := d.Decode(in[1:])
-		return d, err
+		d = new(SealDigest)
+	case RuntimeEnvironmentUpdatedDigestType:
+		d = new(RuntimeEnvironmentUpdatedDigest)
+	default:
+		d = &UnknownDigest{Kind: in[0]}
+	}
+
+	err := d.Decode(in[1:])
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.New("invalid digest item type")
+	return d, nil
 }
 
-// DigestItem can be of one of four types of digest: ChangesTrieRootDigest, PreRuntimeDigest, ConsensusDigest, or SealDigest.
+// DigestItem can be of one of six types of digest: OtherDigest, ChangesTrieRootDigest, PreRuntimeDigest,
+// ConsensusDigest, SealDigest, or RuntimeEnvironmentUpdatedDigest. Items of other types are kept as an
+// UnknownDigest.
 // see https://github.com/paritytech/substrate/blob/f548309478da3935f72567c2abc2eceec3978e9f/primitives/runtime/src/generic/digest.rs#L77
 type DigestItem interface {
 	Type() byte
@@ -94,6 +108,161 @@ type DigestItem interface {
 	Decode([]byte) error // Decode assumes the type byte (first byte) has been removed from the encoding.
 }
 
+// Digest is the list of digest items of a block header
+type Digest []DigestItem
+
+// NewEmptyDigest returns an empty digest
+func NewEmptyDigest() Digest {
+	return Digest{}
+}
+
+// NewDigest returns a digest containing the given items
+func NewDigest(items ...DigestItem) Digest {
+	return append(NewEmptyDigest(), items...)
+}
+
+// DecodeDigestItems decodes each of the given encoded digest items into a digest
+func DecodeDigestItems(in [][]byte) (Digest, error) {
+	d := make(Digest, len(in))
+	for i, enc := range in {
+		item, err := DecodeDigestItem(enc)
+		if err != nil {
+			return nil, err
+		}
+
+		d[i] = item
+	}
+
+	return d, nil
+}
+
+// DeepCopy returns a copy of the digest whose items don't share any memory with the items of this digest
+func (d Digest) DeepCopy() Digest {
+	if d == nil {
+		return nil
+	}
+
+	cp := make(Digest, len(d))
+	for i, item := range d {
+		cp[i] = copyDigestItem(item)
+	}
+
+	return cp
+}
+
+// copyDigestItem returns a copy of the given digest item
+func copyDigestItem(item DigestItem) DigestItem {
+	switch d := item.(type) {
+	case *OtherDigest:
+		return &OtherDigest{Data: copyBytes(d.Data)}
+	case *ChangesTrieRootDigest:
+		return &ChangesTrieRootDigest{Hash: d.Hash}
+	case *PreRuntimeDigest:
+		return &PreRuntimeDigest{ConsensusEngineID: d.ConsensusEngineID, Data: copyBytes(d.Data)}
+	case *ConsensusDigest:
+		return &ConsensusDigest{ConsensusEngineID: d.ConsensusEngineID, Data: copyBytes(d.Data)}
+	case *SealDigest:
+		return &SealDigest{ConsensusEngineID: d.ConsensusEngineID, Data: copyBytes(d.Data)}
+	case *RuntimeEnvironmentUpdatedDigest:
+		return new(RuntimeEnvironmentUpdatedDigest)
+	case *UnknownDigest:
+		return &UnknownDigest{Kind: d.Kind, Data: copyBytes(d.Data)}
+	}
+
+	return item
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
+
+// EncodeItems returns the encoding of each digest item
+func (d Digest) EncodeItems() ([][]byte, error) {
+	enc := make([][]byte, len(d))
+	for i, item := range d {
+		itemEnc, err := item.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		enc[i] = itemEnc
+	}
+
+	return enc, nil
+}
+
+// Encode returns the SCALE encoding of the digest, where each item is encoded as a byte array
+func (d Digest) Encode() ([]byte, error) {
+	enc, err := d.EncodeItems()
+	if err != nil {
+		return nil, err
+	}
+
+	return scale.Encode(enc)
+}
+
+// Decode decodes the SCALE encoded digest from the reader into this digest
+func (d *Digest) Decode(r io.Reader) (Digest, error) {
+	sd := scale.Decoder{Reader: r}
+	enc, err := sd.Decode([][]byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	*d, err = DecodeDigestItems(enc.([][]byte))
+	return *d, err
+}
+
+// String returns the hex encoding of each digest item
+func (d Digest) String() string {
+	enc, err := d.EncodeItems()
+	if err != nil {
+		return fmt.Sprintf("invalid digest: %s", err)
+	}
+
+	items := make([]string, len(enc))
+	for i, e := range enc {
+		items[i] = common.BytesToHex(e)
+	}
+
+	return fmt.Sprintf("%v", items)
+}
+
+// OtherDigest contains arbitrary data that isn't interpreted by the runtime or the consensus engines.
+type OtherDigest struct {
+	Data []byte
+}
+
+// Type returns the OtherDigest type
+func (d *OtherDigest) Type() byte {
+	return OtherDigestType
+}
+
+// Encode will encode the OtherDigest Data
+func (d *OtherDigest) Encode() ([]byte, error) {
+	output, err := scale.Encode(d.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{OtherDigestType}, output...), nil
+}
+
+// Decode will decode into OtherDigest Data
+func (d *OtherDigest) Decode(in []byte) error {
+	output, err := scale.Decode(in, []byte{})
+	if err != nil {
+		return err
+	}
+
+	d.Data = output.([]byte)
+	return nil
+}
+
 // ChangesTrieRootDigest contains the root of the changes trie at a given block, if the runtime supports it.
 type ChangesTrieRootDigest struct {
 	Hash common.Hash
@@ -242,3 +411,48 @@ func (d *SealDigest) Decode(in []byte) error {
 	d.Data = output.([]byte)
 	return nil
 }
+
+// RuntimeEnvironmentUpdatedDigest signals that the runtime code or heap pages were updated in the block.
+type RuntimeEnvironmentUpdatedDigest struct{}
+
+// Type returns the RuntimeEnvironmentUpdatedDigest type
+func (d *RuntimeEnvironmentUpdatedDigest) Type() byte {
+	return RuntimeEnvironmentUpdatedDigestType
+}
+
+// Encode will encode the RuntimeEnvironmentUpdatedDigest, which is only its type byte
+func (d *RuntimeEnvironmentUpdatedDigest) Encode() ([]byte, error) {
+	return []byte{RuntimeEnvironmentUpdatedDigestType}, nil
+}
+
+// Decode checks that there's no data, since the RuntimeEnvironmentUpdatedDigest has none
+func (d *RuntimeEnvironmentUpdatedDigest) Decode(in []byte) error {
+	if len(in) != 0 {
+		return errors.New("unexpected data after runtime environment updated digest")
+	}
+
+	return nil
+}
+
+// UnknownDigest is a digest item of a type that isn't known. Its data is kept as it is, so that the header it's in
+// encodes to the same hash.
+type UnknownDigest struct {
+	Kind byte
+	Data []byte
+}
+
+// Type returns the type of the UnknownDigest
+func (d *UnknownDigest) Type() byte {
+	return d.Kind
+}
+
+// Encode returns the type followed by the raw data of the UnknownDigest
+func (d *UnknownDigest) Encode() ([]byte, error) {
+	return append([]byte{d.Kind}, d.Data...), nil
+}
+
+// Decode keeps the raw data of the UnknownDigest
+func (d *UnknownDigest) Decode(in []byte) error {
+	d.Data = copyBytes(in)
+	return nil
+}
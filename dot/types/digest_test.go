@@ -17,13 +17,29 @@
 package types
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
 	"github.com/stretchr/testify/require"
 )
 
+func TestOtherDigest(t *testing.T) {
+	d := &OtherDigest{
+		Data: []byte{1, 3, 5, 7},
+	}
+
+	enc, err := d.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 16, 1, 3, 5, 7}, enc)
+
+	d2, err := DecodeDigestItem(enc)
+	require.NoError(t, err)
+	require.Equal(t, d, d2)
+}
+
 func TestChangesTrieRootDigest(t *testing.T) {
 	d := &ChangesTrieRootDigest{
 		Hash: common.Hash{0, 91, 50, 25, 214, 94, 119, 36, 71, 216, 33, 152, 85, 184, 34, 120, 61, 161, 164, 223, 76, 53, 40, 246, 76, 38, 235, 204, 43, 31, 179, 28},
@@ -90,3 +106,84 @@ func TestSealDigest(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", d2, d)
 	}
 }
+
+func TestRuntimeEnvironmentUpdatedDigest(t *testing.T) {
+	d := &RuntimeEnvironmentUpdatedDigest{}
+
+	enc, err := d.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{8}, enc)
+
+	d2, err := DecodeDigestItem(enc)
+	require.NoError(t, err)
+	require.Equal(t, d, d2)
+}
+
+func TestDecodeDigestItem_Invalid(t *testing.T) {
+	_, err := DecodeDigestItem([]byte{})
+	require.Error(t, err)
+
+	_, err = DecodeDigestItem([]byte{RuntimeEnvironmentUpdatedDigestType, 1})
+	require.Error(t, err)
+}
+
+func TestDecodeDigestItem_Unknown(t *testing.T) {
+	enc := []byte{0xe, 0xf, 0x10}
+
+	d, err := DecodeDigestItem(enc)
+	require.NoError(t, err)
+	require.Equal(t, &UnknownDigest{Kind: 0xe, Data: []byte{0xf, 0x10}}, d)
+	require.Equal(t, byte(0xe), d.Type())
+
+	// the item is encoded as it was received
+	enc2, err := d.Encode()
+	require.NoError(t, err)
+	require.Equal(t, enc, enc2)
+}
+
+func TestDigest_DeepCopy(t *testing.T) {
+	d := NewDigest(
+		&PreRuntimeDigest{ConsensusEngineID: BabeEngineID, Data: []byte{1, 3, 5, 7}},
+		&ChangesTrieRootDigest{Hash: common.Hash{1}},
+		&RuntimeEnvironmentUpdatedDigest{},
+		&UnknownDigest{Kind: 0xe, Data: []byte{0xf}},
+		&SealDigest{ConsensusEngineID: BabeEngineID, Data: []byte{2, 4}},
+	)
+
+	cp := d.DeepCopy()
+	require.Equal(t, d, cp)
+
+	// changing the copy doesn't change the original
+	cp[0].(*PreRuntimeDigest).Data[0] = 9
+	cp[1].(*ChangesTrieRootDigest).Hash[0] = 9
+	cp[3].(*UnknownDigest).Data[0] = 9
+	cp[4].(*SealDigest).Data[0] = 9
+	require.Equal(t, byte(1), d[0].(*PreRuntimeDigest).Data[0])
+	require.Equal(t, byte(1), d[1].(*ChangesTrieRootDigest).Hash[0])
+	require.Equal(t, byte(0xf), d[3].(*UnknownDigest).Data[0])
+	require.Equal(t, byte(2), d[4].(*SealDigest).Data[0])
+}
+
+func TestDigest_EncodeDecode(t *testing.T) {
+	d := NewDigest(
+		&PreRuntimeDigest{ConsensusEngineID: BabeEngineID, Data: []byte{1, 3, 5, 7}},
+		&SealDigest{ConsensusEngineID: BabeEngineID, Data: []byte{2, 4}},
+	)
+
+	enc, err := d.Encode()
+	require.NoError(t, err)
+
+	items, err := d.EncodeItems()
+	require.NoError(t, err)
+	expected, err := scale.Encode(items)
+	require.NoError(t, err)
+	require.Equal(t, expected, enc)
+
+	d2, err := new(Digest).Decode(bytes.NewReader(enc))
+	require.NoError(t, err)
+	require.Equal(t, d, d2)
+
+	d3, err := DecodeDigestItems(items)
+	require.NoError(t, err)
+	require.Equal(t, d, d3)
+}
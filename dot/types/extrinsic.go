@@ -28,9 +28,9 @@ func NewExtrinsic(e []byte) Extrinsic {
 	return Extrinsic(e)
 }
 
-// Hash returns the blake2b hash of the extrinsic
+// Hash returns the hash of the extrinsic using the default hash function
 func (e Extrinsic) Hash() common.Hash {
-	hash, err := common.Blake2bHash(e)
+	hash, err := common.DefaultHash(e)
 	if err != nil {
 		panic(err)
 	}
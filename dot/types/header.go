@@ -33,12 +33,12 @@ type Header struct {
 	Number         *big.Int    `json:"number"`
 	StateRoot      common.Hash `json:"stateRoot"`
 	ExtrinsicsRoot common.Hash `json:"extrinsicsRoot"`
-	Digest         [][]byte    `json:"digest"`
+	Digest         Digest      `json:"digest"`
 	hash           common.Hash
 }
 
 // NewHeader creates a new block header and sets its hash field
-func NewHeader(parentHash common.Hash, number *big.Int, stateRoot common.Hash, extrinsicsRoot common.Hash, digest [][]byte) (*Header, error) {
+func NewHeader(parentHash common.Hash, number *big.Int, stateRoot common.Hash, extrinsicsRoot common.Hash, digest Digest) (*Header, error) {
 	if number == nil {
 		// Hash() will panic if number is nil
 		return nil, errors.New("cannot have nil block number")
@@ -64,10 +64,9 @@ func (bh *Header) DeepCopy() *Header {
 	if bh.Number != nil {
 		safeCopyHeader.Number = new(big.Int).Set(bh.Number)
 	}
-	//copy digest byte array
+	//copy digest items
 	if len(bh.Digest) > 0 {
-		safeCopyHeader.Digest = make([][]byte, len(bh.Digest))
-		copy(safeCopyHeader.Digest, bh.Digest)
+		safeCopyHeader.Digest = bh.Digest.DeepCopy()
 	}
 
 	return &safeCopyHeader
@@ -89,7 +88,7 @@ func (bh *Header) Hash() common.Hash {
 			panic(err)
 		}
 
-		hash, err := common.Blake2bHash(enc)
+		hash, err := common.DefaultHash(enc)
 		if err != nil {
 			panic(err)
 		}
@@ -122,14 +121,19 @@ func (bh *Header) Decode(r io.Reader) (*Header, error) {
 }
 
 // AsOptional returns the Header as an optional.Header
-func (bh *Header) AsOptional() *optional.Header {
+func (bh *Header) AsOptional() (*optional.Header, error) {
+	digest, err := bh.Digest.EncodeItems()
+	if err != nil {
+		return nil, err
+	}
+
 	return optional.NewHeader(true, &optional.CoreHeader{
 		ParentHash:     bh.ParentHash,
 		Number:         bh.Number,
 		StateRoot:      bh.StateRoot,
 		ExtrinsicsRoot: bh.ExtrinsicsRoot,
-		Digest:         bh.Digest,
-	})
+		Digest:         digest,
+	}), nil
 }
 
 // NewHeaderFromOptional returns a Header given an optional.Header. If the optional.Header is None, an error is returned.
@@ -145,12 +149,17 @@ func NewHeaderFromOptional(oh *optional.Header) (*Header, error) {
 		return nil, errors.New("cannot have nil block number")
 	}
 
+	digest, err := DecodeDigestItems(h.Digest)
+	if err != nil {
+		return nil, err
+	}
+
 	bh := &Header{
 		ParentHash:     h.ParentHash,
 		Number:         h.Number,
 		StateRoot:      h.StateRoot,
 		ExtrinsicsRoot: h.ExtrinsicsRoot,
-		Digest:         h.Digest,
+		Digest:         digest,
 	}
 
 	bh.Hash()
@@ -167,7 +176,7 @@ func decodeOptionalHeader(r io.Reader) (*optional.Header, error) {
 	}
 
 	if exists == 1 {
-		header := &Header{
+		header := &optional.CoreHeader{
 			ParentHash:     common.Hash{},
 			Number:         big.NewInt(0),
 			StateRoot:      common.Hash{},
@@ -179,8 +188,7 @@ func decodeOptionalHeader(r io.Reader) (*optional.Header, error) {
 			return nil, err
 		}
 
-		header.Hash()
-		return header.AsOptional(), nil
+		return optional.NewHeader(true, header), nil
 	}
 
 	return optional.NewHeader(false, nil), nil
@@ -27,7 +27,16 @@ import (
 )
 
 func TestDecodeHeader(t *testing.T) {
-	header, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, [][]byte{{}})
+	digest := NewDigest(
+		&OtherDigest{Data: []byte{1, 2}},
+		&ChangesTrieRootDigest{Hash: common.Hash{0x1}},
+		&PreRuntimeDigest{ConsensusEngineID: BabeEngineID, Data: []byte{3}},
+		&ConsensusDigest{ConsensusEngineID: GrandpaEngineID, Data: []byte{4}},
+		&SealDigest{ConsensusEngineID: BabeEngineID, Data: []byte{5}},
+		&RuntimeEnvironmentUpdatedDigest{},
+	)
+
+	header, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, digest)
 	require.NoError(t, err)
 
 	enc, err := header.Encode()
@@ -42,12 +51,12 @@ func TestDecodeHeader(t *testing.T) {
 }
 
 func TestMustEncodeHeader(t *testing.T) {
-	bh1, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, [][]byte{{}})
+	bh1, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, NewEmptyDigest())
 	require.NoError(t, err)
 	enc, err := bh1.Encode()
 	require.NoError(t, err)
 
-	bh2, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, [][]byte{{0, 0}, {1, 2}, {2, 4}, {3, 6}, {4, 8}})
+	bh2, err := NewHeader(common.Hash{}, big.NewInt(0), common.Hash{}, common.Hash{}, NewDigest(&OtherDigest{Data: []byte{1, 2}}))
 	require.NoError(t, err)
 	enc2, err := bh2.Encode()
 	require.NoError(t, err)
@@ -0,0 +1,71 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// syncProgressLength is the length of an encoded SyncProgress
+const syncProgressLength = 8 + 8 + 32
+
+// SyncProgress is the progress of an initial sync, persisted so that an interrupted sync can be resumed
+type SyncProgress struct {
+	// Target is the highest block number seen from peers
+	Target *big.Int
+	// LastVerified is the number of the last block of the last verified and imported batch
+	LastVerified *big.Int
+	// LastVerifiedHash is the hash of the last block of the last verified and imported batch
+	LastVerifiedHash common.Hash
+}
+
+// Encode returns the encoding of the SyncProgress
+func (sp *SyncProgress) Encode() []byte {
+	enc := make([]byte, syncProgressLength)
+	binary.LittleEndian.PutUint64(enc[:8], sp.Target.Uint64())
+	binary.LittleEndian.PutUint64(enc[8:16], sp.LastVerified.Uint64())
+	copy(enc[16:], sp.LastVerifiedHash[:])
+	return enc
+}
+
+// Decode decodes an encoded SyncProgress
+func (sp *SyncProgress) Decode(in []byte) error {
+	if len(in) != syncProgressLength {
+		return errors.New("invalid sync progress encoding length")
+	}
+
+	sp.Target = new(big.Int).SetUint64(binary.LittleEndian.Uint64(in[:8]))
+	sp.LastVerified = new(big.Int).SetUint64(binary.LittleEndian.Uint64(in[8:16]))
+	copy(sp.LastVerifiedHash[:], in[16:])
+	return nil
+}
+
+// SyncState is the sync status of the node
+type SyncState struct {
+	// StartingBlock is the number of the best block when the sync started
+	StartingBlock *big.Int
+	// CurrentBlock is the number of the best block
+	CurrentBlock *big.Int
+	// HighestBlock is the highest block number seen from peers
+	HighestBlock *big.Int
+	// Resumed is true if the sync was resumed from the progress persisted before a restart
+	Resumed bool
+}
@@ -25,6 +25,8 @@ import (
 	"testing"
 
 	ctoml "github.com/ChainSafe/gossamer/dot/config/toml"
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/wasmer"
@@ -41,6 +43,28 @@ func setupLogger(cfg *Config) {
 	logger.SetHandler(log.LvlFilterHandler(cfg.Global.LogLvl, handler))
 }
 
+// setupHasher sets the hash function used for trie and header hashing of a new database, blake2b is used if none
+// is configured. It returns the hash function, so that the genesis trie is hashed with it.
+func setupHasher(cfg *Config, stateSrvc *state.Service) (common.HashFunc, error) {
+	name := cfg.Global.Hasher
+	if name == "" {
+		name = common.Blake2bHasher
+	}
+
+	err := stateSrvc.SetHasher(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the genesis header is hashed before the database is started
+	err = common.SetDefaultHasher(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.GetHasher(name)
+}
+
 // NewTestGenesis returns a test genesis instance using "gssmr" raw data
 func NewTestGenesis(t *testing.T) *genesis.Genesis {
 	fp := utils.GetGssmrGenesisRawPath()
@@ -41,4 +41,4 @@ require (
 	gopkg.in/yaml.v2 v2.2.7 // indirect
 )
 
-go 1.14
+go 1.16
@@ -0,0 +1,232 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// maxTrackedEpochs is the number of most recent epochs that authorship statistics are kept for
+const maxTrackedEpochs = 32
+
+// MissedSlotReason describes why a slot that we won the lottery for didn't result in a block
+type MissedSlotReason string
+
+// Reasons for missing a slot
+const (
+	// MissedSlotNoKey is used when our key was removed from the authority set after we won the slot's lottery
+	MissedSlotNoKey MissedSlotReason = "no key"
+	// MissedSlotBehindSync is used when the state of the best block isn't available to build on
+	MissedSlotBehindSync MissedSlotReason = "behind sync"
+	// MissedSlotBuildTimeout is used when the slot ended before the block could be built
+	MissedSlotBuildTimeout MissedSlotReason = "build timeout"
+	// MissedSlotBuildFailed is used when building or announcing the block failed for any other reason
+	MissedSlotBuildFailed MissedSlotReason = "build failed"
+)
+
+// missedSlotReasons are the reasons for missing a slot, in the order they're reported in
+var missedSlotReasons = []MissedSlotReason{
+	MissedSlotNoKey,
+	MissedSlotBehindSync,
+	MissedSlotBuildTimeout,
+	MissedSlotBuildFailed,
+}
+
+// errors returned by handleSlot that are tracked as missed slots
+var (
+	errSlotNoKey        = errors.New("key removed from BABE authority data after winning slot")
+	errSlotBehindSync   = errors.New("cannot get state of best block")
+	errSlotBuildTimeout = errors.New("slot ended before block was built")
+)
+
+// errors returned by handleSlot for slots that we don't know we won, which aren't tracked
+var (
+	// errSlotEmpty is returned when empty blocks are skipped and there are no ready transactions
+	errSlotEmpty = errors.New("no ready transactions")
+	// errSlotLottery is returned when the slot lottery couldn't be run
+	errSlotLottery = errors.New("failed to run lottery")
+)
+
+// AuthorshipStats contains the block authorship statistics of the node for an epoch
+type AuthorshipStats struct {
+	Epoch          uint64
+	ClaimedSlots   uint64 // number of slots we won the lottery for
+	AuthoredBlocks uint64
+	MissedSlots    map[MissedSlotReason]uint64
+}
+
+// TotalMissedSlots returns the number of missed slots for all reasons
+func (s *AuthorshipStats) TotalMissedSlots() uint64 {
+	var total uint64
+	for _, n := range s.MissedSlots {
+		total += n
+	}
+	return total
+}
+
+// record updates the stats with the result of handling a slot that we won the lottery for
+func (s *AuthorshipStats) record(err error) {
+	s.ClaimedSlots++
+
+	switch {
+	case err == nil:
+		s.AuthoredBlocks++
+	case errors.Is(err, errSlotNoKey):
+		s.MissedSlots[MissedSlotNoKey]++
+	case errors.Is(err, errSlotBehindSync):
+		s.MissedSlots[MissedSlotBehindSync]++
+	case errors.Is(err, errSlotBuildTimeout):
+		s.MissedSlots[MissedSlotBuildTimeout]++
+	default:
+		s.MissedSlots[MissedSlotBuildFailed]++
+	}
+}
+
+func (s *AuthorshipStats) copy() *AuthorshipStats {
+	cp := &AuthorshipStats{
+		Epoch:          s.Epoch,
+		ClaimedSlots:   s.ClaimedSlots,
+		AuthoredBlocks: s.AuthoredBlocks,
+		MissedSlots:    make(map[MissedSlotReason]uint64, len(s.MissedSlots)),
+	}
+
+	for reason, n := range s.MissedSlots {
+		cp.MissedSlots[reason] = n
+	}
+
+	return cp
+}
+
+// authorshipTracker records the outcome of each of our slots, per epoch
+type authorshipTracker struct {
+	sync.RWMutex
+	epochs map[uint64]*AuthorshipStats
+	total  *AuthorshipStats // stats of every epoch, including the ones that are no longer tracked
+}
+
+func newAuthorshipTracker() *authorshipTracker {
+	return &authorshipTracker{
+		epochs: make(map[uint64]*AuthorshipStats),
+		total: &AuthorshipStats{
+			MissedSlots: make(map[MissedSlotReason]uint64),
+		},
+	}
+}
+
+// epoch returns the stats for the given epoch, creating them if needed. The tracker must be locked.
+func (t *authorshipTracker) epoch(epoch uint64) *AuthorshipStats {
+	if s, has := t.epochs[epoch]; has {
+		return s
+	}
+
+	s := &AuthorshipStats{
+		Epoch:       epoch,
+		MissedSlots: make(map[MissedSlotReason]uint64),
+	}
+	t.epochs[epoch] = s
+
+	// forget the oldest epoch once too many are tracked
+	if len(t.epochs) > maxTrackedEpochs {
+		oldest := epoch
+		for e := range t.epochs {
+			if e < oldest {
+				oldest = e
+			}
+		}
+		delete(t.epochs, oldest)
+	}
+
+	return s
+}
+
+// record updates the stats of the given epoch with the result of handling a slot
+func (t *authorshipTracker) record(epoch uint64, err error) {
+	// only the slots we won the lottery for count towards the stats. The slots that aren't ours, that we aren't an
+	// authority for, that the lottery couldn't be run for, or that were skipped since there was nothing to include
+	// aren't counted.
+	if errors.Is(err, ErrNotAuthorized) || errors.Is(err, ErrNotAuthority) || errors.Is(err, errSlotLottery) ||
+		errors.Is(err, errSlotEmpty) {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.epoch(epoch).record(err)
+	t.total.record(err)
+}
+
+// get returns a copy of the stats for the given epoch, if they are tracked
+func (t *authorshipTracker) get(epoch uint64) (*AuthorshipStats, bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	s, has := t.epochs[epoch]
+	if !has {
+		return nil, false
+	}
+
+	return s.copy(), true
+}
+
+// stats returns a copy of the stats for the tracked epochs, ordered by epoch
+func (t *authorshipTracker) stats() []*AuthorshipStats {
+	t.RLock()
+	defer t.RUnlock()
+
+	stats := make([]*AuthorshipStats, 0, len(t.epochs))
+	for _, s := range t.epochs {
+		stats = append(stats, s.copy())
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Epoch < stats[j].Epoch
+	})
+	return stats
+}
+
+// ServeHTTP writes the slot counts of all epochs in the Prometheus text format
+func (t *authorshipTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	t.RLock()
+	total := t.total.copy()
+	t.RUnlock()
+
+	buf.WriteString("# HELP gossamer_babe_claimed_slots_total Number of slots we won the lottery for.\n")
+	buf.WriteString("# TYPE gossamer_babe_claimed_slots_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_babe_claimed_slots_total %d\n", total.ClaimedSlots)
+
+	buf.WriteString("# HELP gossamer_babe_authored_blocks_total Number of blocks we authored.\n")
+	buf.WriteString("# TYPE gossamer_babe_authored_blocks_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_babe_authored_blocks_total %d\n", total.AuthoredBlocks)
+
+	buf.WriteString("# HELP gossamer_babe_missed_slots_total Number of slots we won the lottery for but didn't " +
+		"author a block in.\n")
+	buf.WriteString("# TYPE gossamer_babe_missed_slots_total counter\n")
+	for _, reason := range missedSlotReasons {
+		fmt.Fprintf(&buf, "gossamer_babe_missed_slots_total{reason=%q} %d\n", reason, total.MissedSlots[reason])
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorshipTracker_Record(t *testing.T) {
+	tracker := newAuthorshipTracker()
+
+	tracker.record(1, nil)
+	tracker.record(1, nil)
+	tracker.record(1, ErrNotAuthorized)
+	tracker.record(1, ErrNotAuthority)
+	tracker.record(1, errSlotLottery)
+	tracker.record(1, errSlotEmpty)
+	tracker.record(1, errSlotNoKey)
+	tracker.record(1, fmt.Errorf("%w: no trie", errSlotBehindSync))
+	tracker.record(1, fmt.Errorf("%w: cannot finalize block", errSlotBuildTimeout))
+	tracker.record(1, errors.New("cannot send block"))
+	tracker.record(2, nil)
+
+	stats := tracker.stats()
+	require.Len(t, stats, 2)
+
+	expected := &AuthorshipStats{
+		Epoch:          1,
+		ClaimedSlots:   6,
+		AuthoredBlocks: 2,
+		MissedSlots: map[MissedSlotReason]uint64{
+			MissedSlotNoKey:        1,
+			MissedSlotBehindSync:   1,
+			MissedSlotBuildTimeout: 1,
+			MissedSlotBuildFailed:  1,
+		},
+	}
+	require.Equal(t, expected, stats[0])
+	require.Equal(t, uint64(4), stats[0].TotalMissedSlots())
+
+	require.Equal(t, uint64(2), stats[1].Epoch)
+	require.Equal(t, uint64(1), stats[1].AuthoredBlocks)
+	require.Equal(t, uint64(0), stats[1].TotalMissedSlots())
+
+	s, has := tracker.get(2)
+	require.True(t, has)
+	require.Equal(t, stats[1], s)
+
+	_, has = tracker.get(3)
+	require.False(t, has)
+}
+
+func TestAuthorshipTracker_ServeHTTP(t *testing.T) {
+	tracker := newAuthorshipTracker()
+	for i := uint64(0); i < maxTrackedEpochs+2; i++ {
+		tracker.record(i, nil)
+	}
+	tracker.record(maxTrackedEpochs+1, errSlotBehindSync)
+	tracker.record(maxTrackedEpochs+1, ErrNotAuthorized)
+
+	rec := httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	// the counts include the epochs that are no longer tracked
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, fmt.Sprintf("gossamer_babe_claimed_slots_total %d\n", maxTrackedEpochs+3)))
+	require.True(t, strings.Contains(body, fmt.Sprintf("gossamer_babe_authored_blocks_total %d\n", maxTrackedEpochs+2)))
+	require.True(t, strings.Contains(body, `gossamer_babe_missed_slots_total{reason="behind sync"} 1`))
+	require.True(t, strings.Contains(body, `gossamer_babe_missed_slots_total{reason="no key"} 0`))
+}
+
+func TestAuthorshipTracker_PrunesOldEpochs(t *testing.T) {
+	tracker := newAuthorshipTracker()
+
+	for i := uint64(0); i < maxTrackedEpochs+2; i++ {
+		tracker.record(i, nil)
+	}
+
+	stats := tracker.stats()
+	require.Len(t, stats, maxTrackedEpochs)
+	require.Equal(t, uint64(2), stats[0].Epoch)
+	require.Equal(t, uint64(maxTrackedEpochs+1), stats[maxTrackedEpochs-1].Epoch)
+}
+
+func TestHandleSlot_NoKey(t *testing.T) {
+	bs := createTestService(t, nil)
+	bs.authorityData = nil
+
+	// slots we didn't win aren't missed
+	delete(bs.slotToProof, 1)
+	err := bs.handleSlot(1)
+	require.Equal(t, ErrNotAuthority, err)
+
+	bs.slotToProof[1] = &VrfOutputAndProof{}
+	err = bs.handleSlot(1)
+	require.Equal(t, errSlotNoKey, err)
+}
+
+func TestHandleSlot_SkipEmpty(t *testing.T) {
+	cfg := &ServiceConfig{
+		Authority:       true,
+		SkipEmptyBlocks: true,
+	}
+	bs := createTestService(t, cfg)
+
+	err := bs.handleSlot(1)
+	require.Equal(t, errSlotEmpty, err)
+	require.NotNil(t, bs.slotToProof[1])
+}
+
+// applyRuntime is a runtime that successfully applies the given extrinsic without executing it
+type applyRuntime struct {
+	runtime.LegacyInstance
+	ext types.Extrinsic
+}
+
+func (rt *applyRuntime) ApplyExtrinsic(ext types.Extrinsic) ([]byte, error) {
+	if bytes.Equal(ext, rt.ext) {
+		return []byte{0, 0}, nil
+	}
+
+	return rt.LegacyInstance.ApplyExtrinsic(ext)
+}
+
+func TestHandleSlot_SkipEmpty_SubmittedTransaction(t *testing.T) {
+	cfg := &ServiceConfig{
+		Authority:       true,
+		SkipEmptyBlocks: true,
+	}
+	bs := createTestService(t, cfg)
+
+	ext := types.Extrinsic{1, 2, 3}
+	bs.rt = &applyRuntime{LegacyInstance: bs.rt, ext: ext}
+
+	ts := bs.transactionState.(*state.TransactionState)
+	id, err := ts.RegisterReadyChannel(bs.ready)
+	require.NoError(t, err)
+	defer ts.UnregisterReadyChannel(id)
+
+	// transactions submitted via RPC or received from peers are added to the pool, and wake a slot that was skipped
+	_, err = ts.AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{}))
+	require.NoError(t, err)
+
+	select {
+	case <-bs.ready:
+	default:
+		t.Fatal("did not signal the ready channel on pool insert")
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- bs.handleSlot(1)
+	}()
+
+	block := <-bs.GetBlockChannel()
+	require.NoError(t, <-errs)
+
+	exts, err := block.Body.AsExtrinsics()
+	require.NoError(t, err)
+	require.Contains(t, exts, ext)
+	require.Empty(t, ts.PendingInPool())
+	require.Nil(t, ts.Peek())
+}
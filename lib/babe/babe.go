@@ -23,12 +23,14 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	log "github.com/ChainSafe/log15"
 )
@@ -47,6 +49,8 @@ type Service struct {
 	cancel    context.CancelFunc
 	paused    bool
 	authority bool
+	skipEmpty bool // don't build blocks without ready transactions
+	clock     Clock
 
 	// Storage interfaces
 	blockState       BlockState
@@ -55,7 +59,8 @@ type Service struct {
 	epochState       EpochState
 
 	// BABE authority keypair
-	keypair *sr25519.Keypair // TODO: change to BABE keystore
+	keypair  *sr25519.Keypair
+	keystore keystore.Keystore // if set, the authority keypair is re-selected from it at the start of each epoch
 
 	// Current runtime
 	rt runtime.LegacyInstance
@@ -69,12 +74,18 @@ type Service struct {
 	startSlot      uint64
 	slotToProof    map[uint64]*VrfOutputAndProof // for slots where we are a producer, store the vrf output (bytes 0-32) + proof (bytes 32-96)
 
+	// Authorship statistics
+	authorship *authorshipTracker
+
 	// Channels for inter-process communication
 	blockChan chan types.Block // send blocks to core service
+	ready     chan struct{}    // signalled by the transaction state when the ready set changes
+	readyID   byte
 
 	// State variables
-	lock  sync.Mutex
-	pause chan struct{}
+	lock      sync.Mutex
+	pause     chan struct{}
+	authoring sync.WaitGroup // tracks the block authoring goroutine, so it can be waited for on restart
 }
 
 // ServiceConfig represents a BABE configuration
@@ -85,12 +96,26 @@ type ServiceConfig struct {
 	TransactionState TransactionState
 	EpochState       EpochState
 	Keypair          *sr25519.Keypair
+	Keystore         keystore.Keystore // keys inserted during an epoch are used from the next epoch
 	Runtime          runtime.LegacyInstance
 	AuthData         []*types.Authority
 	Threshold        *big.Int // for development purposes
 	SlotDuration     uint64   // for development purposes; in milliseconds
 	StartSlot        uint64   // slot to start at
 	Authority        bool
+	SkipEmptyBlocks  bool  // for development purposes; only author blocks when there are ready transactions
+	Clock            Clock // defaults to the system clock
+}
+
+// RestartConfig holds the settings applied when the service is restarted, nil fields are left unchanged
+type RestartConfig struct {
+	Keypair         *sr25519.Keypair
+	AuthData        []*types.Authority
+	Randomness      *[types.RandomnessLength]byte
+	Threshold       *big.Int // if nil and AuthData is set, it's recalculated for the new authorities
+	StartSlot       uint64   // slot to restart at; if 0, the current slot is calculated from the chain
+	Authority       *bool
+	SkipEmptyBlocks *bool
 }
 
 // NewService returns a new Babe Service using the provided VRF keys and runtime
@@ -126,6 +151,7 @@ func NewService(cfg *ServiceConfig) (*Service, error) {
 		storageState:     cfg.StorageState,
 		epochState:       cfg.EpochState,
 		keypair:          cfg.Keypair,
+		keystore:         cfg.Keystore,
 		rt:               cfg.Runtime,
 		transactionState: cfg.TransactionState,
 		slotToProof:      make(map[uint64]*VrfOutputAndProof),
@@ -135,6 +161,21 @@ func NewService(cfg *ServiceConfig) (*Service, error) {
 		startSlot:        cfg.StartSlot,
 		pause:            make(chan struct{}),
 		authority:        cfg.Authority,
+		skipEmpty:        cfg.SkipEmptyBlocks,
+		clock:            cfg.Clock,
+		authorship:       newAuthorshipTracker(),
+	}
+
+	if babeService.clock == nil {
+		babeService.clock = systemClock{}
+	}
+
+	if cfg.SkipEmptyBlocks {
+		if cfg.TransactionState == nil {
+			return nil, errors.New("cannot skip empty blocks; transactionState is nil")
+		}
+
+		babeService.ready = make(chan struct{}, 1)
 	}
 
 	var err error
@@ -191,14 +232,21 @@ func (b *Service) Start() error {
 		return err
 	}
 
-	go b.initiate()
+	if b.skipEmpty {
+		b.readyID, err = b.transactionState.RegisterReadyChannel(b.ready)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.startAuthoring()
 	return nil
 }
 
 // Pause pauses the service ie. halts block production
 func (b *Service) Pause() error {
 	if b.paused {
-		return errors.New("service already paused")
+		return ErrAlreadyPaused
 	}
 
 	select {
@@ -214,10 +262,10 @@ func (b *Service) Pause() error {
 // Resume resumes the service ie. resumes block production
 func (b *Service) Resume() error {
 	if !b.paused {
-		return errors.New("service not paused")
+		return ErrNotPaused
 	}
 
-	go b.initiate()
+	b.startAuthoring()
 	b.paused = false
 	b.logger.Info("service resumed")
 	return nil
@@ -229,14 +277,104 @@ func (b *Service) Stop() error {
 	defer b.lock.Unlock()
 
 	if b.ctx.Err() != nil {
-		return errors.New("service already stopped")
+		return ErrServiceStopped
 	}
 
 	b.cancel()
+	if b.skipEmpty {
+		b.transactionState.UnregisterReadyChannel(b.readyID)
+	}
 	close(b.blockChan)
 	return nil
 }
 
+// Restart halts block production, applies the given configuration and starts block production again.
+// Blocks are still sent on the same channel, so unlike restarting the node, the node's peers and transaction
+// pool are kept. If the configuration is invalid, block production stays halted until Restart succeeds.
+// It can't be used once the service has been stopped.
+func (b *Service) Restart(cfg *RestartConfig) error {
+	b.lock.Lock()
+	if b.ctx.Err() != nil {
+		b.lock.Unlock()
+		return ErrServiceStopped
+	}
+
+	b.cancel()
+	if b.skipEmpty {
+		b.transactionState.UnregisterReadyChannel(b.readyID)
+	}
+	b.lock.Unlock()
+
+	// the authoring goroutine reads the service's fields, wait for it to exit before changing them
+	b.authoring.Wait()
+
+	// the configuration is read by other goroutines, such as the RPC handlers and block import
+	b.lock.Lock()
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	err := b.reconfigure(cfg)
+	if err == nil {
+		b.paused = false
+	}
+	b.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	b.logger.Info("restarting service", "block producer", b.authority, "start slot", b.startSlot)
+	return b.Start()
+}
+
+// reconfigure applies the given configuration, b.lock must be held
+func (b *Service) reconfigure(cfg *RestartConfig) error {
+	if cfg.Authority != nil {
+		b.authority = *cfg.Authority
+	}
+
+	if cfg.Keypair != nil {
+		b.keypair = cfg.Keypair
+	}
+
+	if b.authority && b.keypair == nil {
+		return errors.New("cannot restart BABE service as authority; no keypair provided")
+	}
+
+	if cfg.SkipEmptyBlocks != nil {
+		if *cfg.SkipEmptyBlocks && b.transactionState == nil {
+			return errors.New("cannot skip empty blocks; transactionState is nil")
+		}
+
+		b.skipEmpty = *cfg.SkipEmptyBlocks
+		if b.skipEmpty && b.ready == nil {
+			b.ready = make(chan struct{}, 1)
+		}
+	}
+
+	if cfg.AuthData != nil {
+		b.authorityData = cfg.AuthData
+		b.threshold = nil
+	}
+
+	if cfg.Threshold != nil {
+		b.threshold = cfg.Threshold
+	}
+
+	if cfg.Randomness != nil {
+		b.randomness = *cfg.Randomness
+	}
+
+	// the slot lottery has to be re-run with the new keys and epoch data
+	b.startSlot = cfg.StartSlot
+	b.slotToProof = make(map[uint64]*VrfOutputAndProof)
+
+	if !b.authority {
+		return nil
+	}
+
+	b.updateKeypair()
+	return b.setAuthorityIndex()
+}
+
 // SetRuntime sets the service's runtime
 func (b *Service) SetRuntime(rt runtime.LegacyInstance) error {
 	b.rt = rt
@@ -274,6 +412,26 @@ func (b *Service) Descriptor() *Descriptor {
 	}
 }
 
+// Configuration returns the BABE configuration used by the service
+func (b *Service) Configuration() *types.BabeConfiguration {
+	return b.config
+}
+
+// CurrentEpoch returns the index of the current epoch
+func (b *Service) CurrentEpoch() (uint64, error) {
+	return b.epochState.GetCurrentEpoch()
+}
+
+// AuthorshipStats returns the block authorship statistics of the node for the most recent epochs
+func (b *Service) AuthorshipStats() []*AuthorshipStats {
+	return b.authorship.stats()
+}
+
+// AuthorshipMetrics returns the handler serving the slot counts of all epochs in the Prometheus text format
+func (b *Service) AuthorshipMetrics() http.Handler {
+	return b.authorship
+}
+
 // Authorities returns the current BABE authorities
 func (b *Service) Authorities() []*types.Authority {
 	return b.authorityData
@@ -290,7 +448,7 @@ func (b *Service) SetAuthorities(data []*types.Authority) error {
 		}
 	}
 	if !found {
-		return fmt.Errorf("key not in BABE authority data")
+		return ErrNotAuthority
 	}
 
 	b.authorityData = data
@@ -310,6 +468,8 @@ func (b *Service) SetRandomness(a [types.RandomnessLength]byte) {
 
 // IsStopped returns true if the service is stopped (ie not producing blocks)
 func (b *Service) IsStopped() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
 	return b.ctx.Err() != nil
 }
 
@@ -328,8 +488,8 @@ func (b *Service) safeSend(msg types.Block) error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	if b.IsStopped() {
-		return errors.New("Service has been stopped")
+	if b.ctx.Err() != nil {
+		return ErrServiceStopped
 	}
 
 	b.blockChan <- msg
@@ -348,13 +508,62 @@ func (b *Service) setAuthorityIndex() error {
 		}
 	}
 
-	return fmt.Errorf("key not in BABE authority data")
+	return ErrNotAuthority
+}
+
+// updateKeypair switches to a keystore key that is in the authority set if the current keypair isn't.
+// It's called at the start of each epoch, so keys inserted during an epoch are only used from the next epoch on.
+// b.lock must be held, since the keypair is read by block import, see ownKeys.
+func (b *Service) updateKeypair() {
+	if b.keystore == nil || b.hasAuthorityKey() {
+		return
+	}
+
+	for _, kp := range b.keystore.Keypairs() {
+		srkp, ok := kp.(*sr25519.Keypair)
+		if !ok {
+			continue
+		}
+
+		for i, auth := range b.authorityData {
+			if bytes.Equal(srkp.Public().Encode(), auth.Key.Encode()) {
+				b.logger.Info("using keystore key for block authoring", "key", srkp.Public().Hex())
+				b.keypair = srkp
+				b.authorityIndex = uint64(i)
+				return
+			}
+		}
+	}
+}
+
+// hasAuthorityKey returns true if our key is in the current authority set
+func (b *Service) hasAuthorityKey() bool {
+	if b.keypair == nil {
+		return false
+	}
+
+	pub := b.keypair.Public().Encode()
+	for _, auth := range b.authorityData {
+		if bytes.Equal(pub, auth.Key.Encode()) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (b *Service) slotDuration() time.Duration {
 	return time.Duration(b.config.SlotDuration * 1000000) // SlotDuration in ms, time.Duration in ns
 }
 
+func (b *Service) startAuthoring() {
+	b.authoring.Add(1)
+	go func() {
+		defer b.authoring.Done()
+		b.initiate()
+	}()
+}
+
 func (b *Service) initiate() {
 	if b.config == nil {
 		b.logger.Error("block authoring", "error", "config is nil")
@@ -424,22 +633,54 @@ func (b *Service) invokeBlockAuthoring(startSlot uint64) {
 
 	slotDone := make([]<-chan time.Time, b.config.EpochLength-intoEpoch)
 	for i := 0; i < int(b.config.EpochLength-intoEpoch); i++ {
-		slotDone[i] = time.After(b.slotDuration() * time.Duration(i))
+		slotDone[i] = b.clock.After(b.slotDuration() * time.Duration(i))
 	}
 
-	for i := 0; i < int(b.config.EpochLength-intoEpoch); i++ {
+	// when skipping empty blocks, a claimed slot without ready transactions is retried if transactions
+	// become ready before the slot ends
+	var (
+		skippedSlot uint64
+		skippedEnd  time.Time
+	)
+
+	for i := 0; i < int(b.config.EpochLength-intoEpoch); {
 		select {
 		case <-b.ctx.Done():
 			return
 		case <-b.pause:
 			return
+		case <-b.ready:
+			if skippedEnd.IsZero() || b.clock.Now().After(skippedEnd) {
+				continue
+			}
+
+			err = b.handleSlot(skippedSlot)
+			if errors.Is(err, errSlotEmpty) {
+				continue
+			}
+
+			skippedEnd = time.Time{}
+			b.authorship.record(currEpoch, err)
+			if err != nil {
+				b.logger.Warn("failed to handle slot", "slot", skippedSlot, "error", err)
+			}
 		case <-slotDone[i]:
+			slotNum := startSlot + uint64(i)
+			i++
+			skippedEnd = time.Time{}
+
 			if !b.authority {
 				continue
 			}
 
-			slotNum := startSlot + uint64(i)
 			err = b.handleSlot(slotNum)
+			b.authorship.record(currEpoch, err)
+			if errors.Is(err, errSlotEmpty) {
+				b.logger.Debug("no ready transactions, skipping slot", "slot", slotNum)
+				skippedSlot, skippedEnd = slotNum, b.clock.Now().Add(b.slotDuration())
+				continue
+			}
+
 			if err != nil {
 				b.logger.Warn("failed to handle slot", "slot", slotNum, "error", err)
 				continue
@@ -447,6 +688,10 @@ func (b *Service) invokeBlockAuthoring(startSlot uint64) {
 		}
 	}
 
+	if s, has := b.authorship.get(currEpoch); has {
+		b.logger.Info("epoch authorship", "epoch", currEpoch, "claimed slots", s.ClaimedSlots, "authored blocks", s.AuthoredBlocks, "missed slots", s.MissedSlots)
+	}
+
 	// setup next epoch, re-invoke block authoring
 	next, err := b.incrementEpoch()
 	if err != nil {
@@ -466,12 +711,20 @@ func (b *Service) invokeBlockAuthoring(startSlot uint64) {
 }
 
 func (b *Service) handleSlot(slotNum uint64) error {
+	if !b.hasAuthorityKey() {
+		// the slot is only missed if we won it before our key was removed from the authority set
+		if b.slotToProof[slotNum] != nil {
+			return errSlotNoKey
+		}
+		return ErrNotAuthority
+	}
+
 	if b.slotToProof[slotNum] == nil {
 		// if we don't have a proof already set, re-run lottery.
 		proof, err := b.runLottery(slotNum)
 		if err != nil {
 			b.logger.Warn("failed to run lottery", "slot", slotNum)
-			return errors.New("failed to run lottery")
+			return errSlotLottery
 		}
 
 		if proof == nil {
@@ -482,15 +735,46 @@ func (b *Service) handleSlot(slotNum uint64) error {
 		b.slotToProof[slotNum] = proof
 	}
 
+	if b.skipEmpty {
+		b.promotePooledTransactions()
+		if b.transactionState.Peek() == nil {
+			return errSlotEmpty
+		}
+	}
+
+	block, err := b.buildBlockOnBest(slotNum)
+	if err != nil {
+		return err
+	}
+
+	hash := block.Header.Hash()
+	b.logger.Info("built block", "hash", hash.String(), "number", block.Header.Number, "slot", slotNum)
+	b.logger.Debug("built block", "header", block.Header, "body", block.Body, "parent", block.Header.ParentHash)
+
+	err = b.safeSend(*block)
+	if err != nil {
+		b.logger.Error("failed to send block to core", "error", err)
+		return err
+	}
+	return nil
+}
+
+// buildBlockOnBest builds a block for the given slot on top of the best block, and stores its trie and the block
+func (b *Service) buildBlockOnBest(slotNum uint64) (*types.Block, error) {
+	// don't interleave with the import of a block, so that we build on top of the latest imported block and it's
+	// added before another block is imported
+	b.storageState.Lock()
+	defer b.storageState.Unlock()
+
 	parentHeader, err := b.blockState.BestBlockHeader()
 	if err != nil {
 		b.logger.Error("block authoring", "error", err)
-		return err
+		return nil, fmt.Errorf("%w: %s", errSlotBehindSync, err)
 	}
 
 	if parentHeader == nil {
 		b.logger.Error("block authoring", "error", "parent header is nil")
-		return err
+		return nil, errSlotBehindSync
 	}
 
 	// there is a chance that the best block header may change in the course of building the block,
@@ -498,19 +782,19 @@ func (b *Service) handleSlot(slotNum uint64) error {
 	parent := parentHeader.DeepCopy()
 
 	currentSlot := Slot{
-		start:    uint64(time.Now().Unix()),
+		start:    uint64(b.clock.Now().Unix()),
 		duration: b.config.SlotDuration,
 		number:   slotNum,
 	}
 
 	b.logger.Debug("going to build block", "parent", parent)
 
-	// set runtime trie before building block
+	// set runtime trie before building block, on a copy so that the parent's state isn't changed
 	// if block building is successful, store the resulting trie in the storage state
-	ts, err := b.storageState.TrieState(&parent.StateRoot)
+	ts, err := b.storageState.TrieStateCopy(&parent.StateRoot)
 	if err != nil || ts == nil {
 		b.logger.Error("failed to get parent trie", "parent state root", parent.StateRoot, "error", err)
-		return err
+		return nil, fmt.Errorf("%w: %v", errSlotBehindSync, err)
 	}
 
 	b.rt.SetContext(ts)
@@ -518,7 +802,10 @@ func (b *Service) handleSlot(slotNum uint64) error {
 	block, err := b.buildBlock(parent, currentSlot)
 	if err != nil {
 		b.logger.Error("block authoring", "error", err)
-		return nil
+		if b.hasSlotEnded(currentSlot) {
+			return nil, fmt.Errorf("%w: %s", errSlotBuildTimeout, err)
+		}
+		return nil, err
 	}
 
 	// block built successfully, store resulting trie in storage state
@@ -526,19 +813,16 @@ func (b *Service) handleSlot(slotNum uint64) error {
 	err = b.storageState.StoreTrie(block.Header.StateRoot, ts)
 	if err != nil {
 		b.logger.Error("failed to store trie in storage state", "error", err)
-		return err
+		return nil, err
 	}
 
-	hash := block.Header.Hash()
-	b.logger.Info("built block", "hash", hash.String(), "number", block.Header.Number, "slot", slotNum)
-	b.logger.Debug("built block", "header", block.Header, "body", block.Body, "parent", parent.Hash())
-
-	err = b.safeSend(*block)
+	err = b.blockState.AddBlock(block)
 	if err != nil {
-		b.logger.Error("failed to send block to core", "error", err)
-		return err
+		b.logger.Error("failed to add block", "error", err)
+		return nil, err
 	}
-	return nil
+
+	return block, nil
 }
 
 func (b *Service) vrfSign(input []byte) (out []byte, proof []byte, err error) {
@@ -566,7 +850,7 @@ func (b *Service) setThreshold() error {
 func CalculateThreshold(C1, C2 uint64, numAuths int) (*big.Int, error) {
 	c := float64(C1) / float64(C2)
 	if c > 1 {
-		return nil, errors.New("invalid C1/C2: greater than 1")
+		return nil, ErrInvalidThreshold
 	}
 
 	// 1 / len(authorities)
@@ -26,6 +26,7 @@ import (
 
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/keystore"
@@ -325,6 +326,75 @@ func TestStartAndStop(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_Restart(t *testing.T) {
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	bs := createTestService(t, &ServiceConfig{
+		Keypair:   kr.Alice().(*sr25519.Keypair),
+		Authority: true,
+		LogLvl:    log.LvlCrit,
+	})
+
+	err = bs.Start()
+	require.NoError(t, err)
+	ch := bs.GetBlockChannel()
+
+	auths := []*types.Authority{
+		{Key: kr.Alice().Public().(*sr25519.PublicKey), Weight: 1},
+		{Key: kr.Bob().Public().(*sr25519.PublicKey), Weight: 1},
+	}
+	rand := [types.RandomnessLength]byte{1, 2, 3}
+	skip := true
+
+	err = bs.Restart(&RestartConfig{
+		Keypair:         kr.Bob().(*sr25519.Keypair),
+		AuthData:        auths,
+		Randomness:      &rand,
+		StartSlot:       10,
+		SkipEmptyBlocks: &skip,
+	})
+	require.NoError(t, err)
+	require.False(t, bs.IsStopped())
+	require.Equal(t, kr.Bob(), bs.keypair)
+	require.Equal(t, uint64(1), bs.authorityIndex)
+	require.Equal(t, rand, bs.randomness)
+	require.True(t, bs.skipEmpty)
+	require.NotNil(t, bs.ready)
+
+	// the threshold is recalculated for the new authority set
+	expected, err := CalculateThreshold(bs.config.C1, bs.config.C2, 2)
+	require.NoError(t, err)
+	require.Equal(t, expected, bs.threshold)
+
+	// blocks are still sent on the same channel
+	require.Equal(t, ch, bs.GetBlockChannel())
+
+	err = bs.Stop()
+	require.NoError(t, err)
+
+	err = bs.Restart(&RestartConfig{})
+	require.Equal(t, ErrServiceStopped, err)
+}
+
+func TestService_Restart_NotAuthority(t *testing.T) {
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	bs := createTestService(t, &ServiceConfig{
+		Keypair:   kr.Alice().(*sr25519.Keypair),
+		Authority: true,
+		LogLvl:    log.LvlCrit,
+	})
+
+	err = bs.Start()
+	require.NoError(t, err)
+	defer bs.Stop()
+
+	err = bs.Restart(&RestartConfig{
+		Keypair: kr.Bob().(*sr25519.Keypair),
+	})
+	require.Equal(t, ErrNotAuthority, err)
+}
+
 func TestService_SetAuthorities(t *testing.T) {
 	kr, err := keystore.NewSr25519Keyring()
 	require.NoError(t, err)
@@ -369,12 +439,40 @@ func TestService_SetAuthorities_WrongKey(t *testing.T) {
 	auths = append(auths, bd1)
 
 	err = bs.SetAuthorities(auths)
-	require.EqualError(t, err, "key not in BABE authority data")
+	require.Equal(t, ErrNotAuthority, err)
 	aAfter := bs.authorityData
 	// auths before should equal auths after since there is an error with key, auths should not change
 	require.Equal(t, aBefore, aAfter)
 }
 
+func TestService_UpdateKeypair(t *testing.T) {
+	kr, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+	ks := keystore.NewBasicKeystore(keystore.BabeName, crypto.Sr25519Type)
+	bs := createTestService(t, &ServiceConfig{
+		Keypair:   kr.Alice().(*sr25519.Keypair),
+		Keystore:  ks,
+		Authority: true,
+	})
+
+	bs.authorityData = []*types.Authority{
+		{Key: kr.Alice().Public().(*sr25519.PublicKey), Weight: 1},
+		{Key: kr.Bob().Public().(*sr25519.PublicKey), Weight: 1},
+	}
+
+	// the current key is still an authority, the keystore isn't consulted
+	ks.Insert(kr.Bob())
+	bs.updateKeypair()
+	require.Equal(t, kr.Alice(), bs.keypair)
+
+	// once the current key leaves the authority set, the keystore key is used
+	bs.authorityData = bs.authorityData[1:]
+	bs.updateKeypair()
+	require.Equal(t, kr.Bob(), bs.keypair)
+	require.Equal(t, uint64(0), bs.authorityIndex)
+	require.True(t, bs.hasAuthorityKey())
+}
+
 func TestService_SetThreshold(t *testing.T) {
 	bs := createTestService(t, &ServiceConfig{})
 	etBefore := bs.threshold
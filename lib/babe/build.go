@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
@@ -51,7 +50,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 
 	// create new block header
 	number := big.NewInt(0).Add(parent.Number, big.NewInt(1))
-	header, err := types.NewHeader(parent.Hash(), number, common.Hash{}, common.Hash{}, [][]byte{})
+	header, err := types.NewHeader(parent.Hash(), number, common.Hash{}, common.Hash{}, types.NewEmptyDigest())
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +66,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 	// add block inherents
 	err = b.buildBlockInherents(slot)
 	if err != nil {
-		return nil, fmt.Errorf("cannot build inherents: %s", err)
+		return nil, fmt.Errorf("cannot build inherents: %w", err)
 	}
 
 	b.logger.Trace("built block inherents")
@@ -75,7 +74,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 	// add block extrinsics
 	included, err := b.buildBlockExtrinsics(slot)
 	if err != nil {
-		return nil, fmt.Errorf("cannot build extrinsics: %s", err)
+		return nil, fmt.Errorf("cannot build extrinsics: %w", err)
 	}
 
 	b.logger.Trace("built block extrinsics")
@@ -84,7 +83,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 	header, err = b.rt.FinalizeBlock()
 	if err != nil {
 		b.addToQueue(included)
-		return nil, fmt.Errorf("cannot finalize block: %s", err)
+		return nil, fmt.Errorf("cannot finalize block: %w", err)
 	}
 
 	b.logger.Trace("finalized block")
@@ -93,11 +92,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 	header.Number.Add(parent.Number, big.NewInt(1))
 
 	// add BABE header to digest
-	pdEnc, err := preDigest.Encode()
-	if err != nil {
-		return nil, err
-	}
-	header.Digest = append(header.Digest, pdEnc)
+	header.Digest = append(header.Digest, preDigest)
 
 	// create seal and add to digest
 	seal, err := b.buildBlockSeal(header)
@@ -105,11 +100,7 @@ func (b *Service) buildBlock(parent *types.Header, slot Slot) (*types.Block, err
 		return nil, err
 	}
 
-	sEnc, err := seal.Encode()
-	if err != nil {
-		return nil, err
-	}
-	header.Digest = append(header.Digest, sEnc)
+	header.Digest = append(header.Digest, seal)
 
 	b.logger.Trace("built block seal")
 
@@ -184,7 +175,7 @@ func (b *Service) buildBlockExtrinsics(slot Slot) ([]*transaction.ValidTransacti
 	next := b.nextReadyExtrinsic()
 	included := []*transaction.ValidTransaction{}
 
-	for !hasSlotEnded(slot) && next != nil {
+	for !b.hasSlotEnded(slot) && next != nil {
 		b.logger.Trace("build block", "applying extrinsic", next)
 		ret, err := b.rt.ApplyExtrinsic(next)
 		if err != nil {
@@ -203,7 +194,7 @@ func (b *Service) buildBlockExtrinsics(slot Slot) ([]*transaction.ValidTransacti
 			// re-add previously popped extrinsics back to queue
 			b.addToQueue(included)
 
-			return nil, errors.New("error applying extrinsic: " + errTxt)
+			return nil, fmt.Errorf("%w: %s", ErrApplyExtrinsic, errTxt)
 
 		}
 
@@ -222,7 +213,7 @@ func (b *Service) buildBlockExtrinsics(slot Slot) ([]*transaction.ValidTransacti
 func (b *Service) buildBlockInherents(slot Slot) error {
 	// Setup inherents: add timstap0
 	idata := types.NewInherentsData()
-	err := idata.SetInt64Inherent(types.Timstap0, uint64(time.Now().Unix()))
+	err := idata.SetInt64Inherent(types.Timstap0, uint64(b.clock.Now().Unix()))
 	if err != nil {
 		return err
 	}
@@ -279,7 +270,7 @@ func (b *Service) buildBlockInherents(slot Slot) error {
 				return err
 			}
 
-			return errors.New("error applying extrinsic: " + errTxt)
+			return fmt.Errorf("%w: %s", ErrApplyExtrinsic, errTxt)
 		}
 	}
 
@@ -297,6 +288,25 @@ func (b *Service) addToQueue(txs []*transaction.ValidTransaction) {
 	}
 }
 
+// promotePooledTransactions moves the transactions in the pool to the queue. The pool is otherwise only moved to the
+// queue when a block is imported, so a node that skips empty blocks would never include transactions submitted since
+// its last block.
+func (b *Service) promotePooledTransactions() {
+	for _, tx := range b.transactionState.PendingInPool() {
+		_, err := b.transactionState.Push(tx)
+		if errors.Is(err, transaction.ErrPoolCountLimit) || errors.Is(err, transaction.ErrPoolSizeLimit) {
+			// the queue is full, the remaining transactions are kept in the pool
+			return
+		}
+		if err != nil && !errors.Is(err, transaction.ErrTransactionExists) {
+			b.logger.Trace("failed to move transaction to queue", "error", err)
+			continue
+		}
+
+		b.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
+	}
+}
+
 // nextReadyExtrinsic peeks from the transaction queue. it does not remove any transactions from the queue
 func (b *Service) nextReadyExtrinsic() types.Extrinsic {
 	transaction := b.transactionState.Peek()
@@ -306,8 +316,8 @@ func (b *Service) nextReadyExtrinsic() types.Extrinsic {
 	return transaction.Extrinsic
 }
 
-func hasSlotEnded(slot Slot) bool {
-	return slot.start+slot.duration < uint64(time.Now().Unix())
+func (b *Service) hasSlotEnded(slot Slot) bool {
+	return slot.start+slot.duration < uint64(b.clock.Now().Unix())
 }
 
 func extrinsicsToBody(txs []*transaction.ValidTransaction) (*types.Body, error) {
@@ -18,7 +18,9 @@ package babe
 
 import (
 	"bytes"
+	"errors"
 	"math/big"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,7 +47,7 @@ func TestSeal(t *testing.T) {
 	zeroHash, err := common.HexToHash("0x00")
 	require.NoError(t, err)
 
-	header, err := types.NewHeader(zeroHash, big.NewInt(0), zeroHash, zeroHash, [][]byte{})
+	header, err := types.NewHeader(zeroHash, big.NewInt(0), zeroHash, zeroHash, types.NewEmptyDigest())
 	require.NoError(t, err)
 
 	encHeader, err := header.Encode()
@@ -117,14 +119,11 @@ func TestBuildBlock_ok(t *testing.T) {
 	preDigest, err := babeService.buildBlockPreDigest(slot)
 	require.NoError(t, err)
 
-	pdEnc, err := preDigest.Encode()
-	require.NoError(t, err)
-
 	expectedBlockHeader := &types.Header{
 		ParentHash: emptyHeader.Hash(),
 		Number:     big.NewInt(1),
 		StateRoot:  emptyHash,
-		Digest:     [][]byte{pdEnc},
+		Digest:     types.NewDigest(preDigest),
 	}
 
 	// remove seal from built block, since we can't predict the signature
@@ -201,9 +200,158 @@ func TestBuildBlock_failing(t *testing.T) {
 	}
 	require.Equal(t, "cannot build extrinsics: error applying extrinsic: Apply error, type: Payment",
 		err.Error(), "Did not receive expected error text")
+	require.True(t, errors.Is(err, ErrApplyExtrinsic))
 
 	txc := babeService.transactionState.Peek()
 	if !bytes.Equal(txc.Extrinsic, txa) {
 		t.Fatal("did not readd valid transaction to queue")
 	}
 }
+
+func TestBuildBlockOnBest_ConcurrentImport(t *testing.T) {
+	cfg := &ServiceConfig{
+		TransactionState: state.NewTransactionState(),
+		LogLvl:           log.LvlInfo,
+	}
+
+	babeService := createTestService(t, cfg)
+	babeService.threshold = maxThreshold
+	babeService.authorityIndex = 0
+
+	const numBlocks = 5
+	for i := uint64(1); i <= numBlocks; i++ {
+		addAuthorshipProof(t, babeService, i)
+	}
+
+	// import blocks the way the syncer does while blocks are being authored;
+	// run with -race to check that the storage state is never shared
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	errs := make(chan error, numBlocks*2)
+
+	go func() {
+		defer wg.Done()
+		for i := byte(0); i < numBlocks; i++ {
+			errs <- importTestBlockOnBest(babeService, []byte{'k', i}, []byte{i})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := uint64(1); i <= numBlocks; i++ {
+			_, err := babeService.buildBlockOnBest(i)
+			errs <- err
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	best, err := babeService.blockState.BestBlockHeader()
+	require.NoError(t, err)
+	require.True(t, best.Number.Cmp(big.NewInt(numBlocks)) >= 0)
+
+	_, err = babeService.storageState.TrieState(&best.StateRoot)
+	require.NoError(t, err)
+}
+
+func TestBuildBlockOnBest_CompetingImport(t *testing.T) {
+	cfg := &ServiceConfig{
+		TransactionState: state.NewTransactionState(),
+		LogLvl:           log.LvlInfo,
+	}
+
+	babeService := createTestService(t, cfg)
+	babeService.threshold = maxThreshold
+	babeService.authorityIndex = 0
+	addAuthorshipProof(t, babeService, 1)
+
+	parent, err := babeService.blockState.BestBlockHeader()
+	require.NoError(t, err)
+
+	// the state a competing block on the same parent is expected to have
+	expected, err := babeService.storageState.TrieStateCopy(&parent.StateRoot)
+	require.NoError(t, err)
+	err = expected.Set([]byte("key"), []byte("value"))
+	require.NoError(t, err)
+	expectedRoot, err := expected.Root()
+	require.NoError(t, err)
+
+	parentState, err := babeService.storageState.TrieState(&parent.StateRoot)
+	require.NoError(t, err)
+	parentRoot, err := parentState.Root()
+	require.NoError(t, err)
+
+	block, err := babeService.buildBlockOnBest(1)
+	require.NoError(t, err)
+	require.Equal(t, parent.Hash(), block.Header.ParentHash)
+
+	// authoring doesn't change the state of the parent
+	root, err := parentState.Root()
+	require.NoError(t, err)
+	require.Equal(t, parentRoot, root)
+
+	// the competing block is imported on the parent's state, without the changes of the authored block
+	err = importTestBlock(babeService, parent, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	_, err = babeService.storageState.TrieState(&expectedRoot)
+	require.NoError(t, err)
+}
+
+func importTestBlockOnBest(babeService *Service, key, value []byte) error {
+	babeService.storageState.Lock()
+	defer babeService.storageState.Unlock()
+
+	parent, err := babeService.blockState.BestBlockHeader()
+	if err != nil {
+		return err
+	}
+
+	return addTestBlock(babeService, parent, key, value)
+}
+
+// importTestBlock imports a block on the given parent that sets the given key, the way the syncer does
+func importTestBlock(babeService *Service, parent *types.Header, key, value []byte) error {
+	babeService.storageState.Lock()
+	defer babeService.storageState.Unlock()
+
+	return addTestBlock(babeService, parent, key, value)
+}
+
+func addTestBlock(babeService *Service, parent *types.Header, key, value []byte) error {
+	ts, err := babeService.storageState.TrieStateCopy(&parent.StateRoot)
+	if err != nil {
+		return err
+	}
+
+	err = ts.Set(key, value)
+	if err != nil {
+		return err
+	}
+
+	root, err := ts.Root()
+	if err != nil {
+		return err
+	}
+
+	err = babeService.storageState.StoreTrie(root, ts)
+	if err != nil {
+		return err
+	}
+
+	return babeService.blockState.AddBlock(&types.Block{
+		Header: &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(0).Add(parent.Number, big.NewInt(1)),
+			StateRoot:  root,
+			Digest:     types.NewDigest(&types.OtherDigest{Data: key}),
+		},
+		Body: types.NewBody([]byte{}),
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	clock := NewFakeClock(start)
+	require.Equal(t, start, clock.Now())
+
+	now := clock.After(0)
+	second := clock.After(time.Second)
+	minute := clock.After(time.Minute)
+
+	require.Equal(t, start, <-now)
+
+	clock.Advance(500 * time.Millisecond)
+	require.Equal(t, 0, len(second))
+
+	clock.Advance(500 * time.Millisecond)
+	require.Equal(t, start.Add(time.Second), <-second)
+	require.Equal(t, 0, len(minute))
+
+	// setting the clock backwards does nothing
+	clock.Set(start)
+	require.Equal(t, start.Add(time.Second), clock.Now())
+
+	clock.Set(start.Add(time.Hour))
+	require.Equal(t, start.Add(time.Hour), <-minute)
+}
+
+func TestHasSlotEnded(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1600000000, 0))
+	bs := &Service{
+		clock: clock,
+	}
+
+	slot := Slot{
+		start:    uint64(clock.Now().Unix()),
+		duration: 6,
+		number:   1,
+	}
+	require.False(t, bs.hasSlotEnded(slot))
+
+	clock.Advance(6 * time.Second)
+	require.False(t, bs.hasSlotEnded(slot))
+
+	clock.Advance(time.Second)
+	require.True(t, bs.hasSlotEnded(slot))
+}
@@ -62,11 +62,19 @@ func (b *Service) initiateEpoch(epoch, startSlot uint64) error {
 		}
 	}
 
+	err := b.pruneEpochData(epoch)
+	if err != nil {
+		b.logger.Warn("failed to prune epoch data", "epoch", epoch, "error", err)
+	}
+
 	if !b.authority {
 		return nil
 	}
 
-	var err error
+	b.lock.Lock()
+	b.updateKeypair()
+	b.lock.Unlock()
+
 	for i := startSlot; i < startSlot+b.config.EpochLength; i++ {
 		b.slotToProof[i], err = b.runLottery(i)
 		if err != nil {
@@ -77,6 +85,54 @@ func (b *Service) initiateEpoch(epoch, startSlot uint64) error {
 	return nil
 }
 
+// pruneEpochData deletes the slot lottery proofs for slots before the finalized head and the epoch info of
+// epochs that ended before the finalized head. The info of the two epochs before the given epoch is always
+// kept, as it's needed to calculate the randomness of the next epoch.
+func (b *Service) pruneEpochData(epoch uint64) error {
+	fin, err := b.blockState.GetFinalizedHeader(0, 0)
+	if err != nil {
+		return err
+	}
+
+	if fin.Number.Cmp(big.NewInt(0)) == 0 {
+		return nil
+	}
+
+	slot, err := b.blockState.GetSlotForBlock(fin.Hash())
+	if err != nil {
+		return err
+	}
+
+	for s := range b.slotToProof {
+		if s < slot {
+			delete(b.slotToProof, s)
+		}
+	}
+
+	if epoch < 4 {
+		return nil
+	}
+
+	// find the epoch containing the finalized head, all epochs before it are below the finalized head
+	for e := epoch - 2; e > 1; e-- {
+		has, err := b.epochState.HasEpochInfo(e)
+		if err != nil || !has {
+			return err
+		}
+
+		info, err := b.epochState.GetEpochInfo(e)
+		if err != nil {
+			return err
+		}
+
+		if info.FirstBlock <= fin.Number.Uint64() {
+			return b.epochState.PruneEpochInfo(e)
+		}
+	}
+
+	return nil
+}
+
 func (b *Service) epochRandomness(epoch uint64) ([types.RandomnessLength]byte, error) {
 	if epoch < 2 {
 		return b.randomness, nil
@@ -179,26 +235,19 @@ func getVRFOutput(header *types.Header) ([sr25519.VrfOutputLength]byte, error) {
 	var bh *types.BabeHeader
 
 	for _, d := range header.Digest {
-		digest, err := types.DecodeDigestItem(d)
-		if err != nil {
+		prd, ok := d.(*types.PreRuntimeDigest)
+		if !ok {
 			continue
 		}
 
-		if digest.Type() == types.PreRuntimeDigestType {
-			prd, ok := digest.(*types.PreRuntimeDigest)
-			if !ok {
-				continue
-			}
-
-			tbh := new(types.BabeHeader)
-			err = tbh.Decode(prd.Data)
-			if err != nil {
-				continue
-			}
-
-			bh = tbh
-			break
+		tbh := new(types.BabeHeader)
+		err := tbh.Decode(prd.Data)
+		if err != nil {
+			continue
 		}
+
+		bh = tbh
+		break
 	}
 
 	if bh == nil {
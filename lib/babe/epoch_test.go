@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 
 	"github.com/stretchr/testify/require"
@@ -110,3 +111,36 @@ func TestIncrementEpoch(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(3), epoch)
 }
+
+func TestPruneEpochData(t *testing.T) {
+	bs := createTestService(t, nil)
+	bs.config.EpochLength = testEpochLength
+
+	parent := genesisHeader
+	var finalized *types.Header
+	for i := 1; i < int(testEpochLength*2+1); i++ {
+		block, _ := createTestBlock(t, bs, parent, nil, uint64(i))
+		err := bs.blockState.AddBlock(block)
+		require.NoError(t, err)
+		parent = block.Header
+
+		if uint64(i) == testEpochLength+1 {
+			finalized = block.Header
+		}
+	}
+
+	for i := uint64(1); i <= testEpochLength*3; i++ {
+		bs.slotToProof[i] = &VrfOutputAndProof{}
+	}
+
+	err := bs.blockState.(*state.BlockState).SetFinalizedHash(finalized.Hash(), 0, 0)
+	require.NoError(t, err)
+
+	err = bs.pruneEpochData(2)
+	require.NoError(t, err)
+
+	// proofs for slots before the finalized head are pruned
+	require.Equal(t, int(testEpochLength*2), len(bs.slotToProof))
+	require.Nil(t, bs.slotToProof[testEpochLength])
+	require.NotNil(t, bs.slotToProof[testEpochLength+1])
+}
@@ -32,3 +32,36 @@ var ErrNotAuthorized = errors.New("not authorized to produce block")
 
 // ErrNoBABEHeader is returned when there is no BABE header found for a block, specifically when calculating randomness
 var ErrNoBABEHeader = errors.New("no BABE header found for block")
+
+// ErrNotAuthority is returned when the node's key is not in the BABE authority set
+var ErrNotAuthority = errors.New("key not in BABE authority data")
+
+// ErrUnknownAuthority is returned when a block's producer index has no entry in the authority set
+var ErrUnknownAuthority = errors.New("no authority data for block producer index")
+
+// ErrInvalidVRF is returned when a block's VRF output doesn't win the slot
+var ErrInvalidVRF = errors.New("invalid VRF output")
+
+// ErrMissingDigest is returned when a block header doesn't contain the BABE digest items
+var ErrMissingDigest = errors.New("block header is missing digest items")
+
+// ErrNoPreDigest is returned when the first digest item of a block header is not a pre-runtime digest
+var ErrNoPreDigest = errors.New("first digest item is not pre-digest")
+
+// ErrNoSeal is returned when the last digest item of a block header is not a seal
+var ErrNoSeal = errors.New("last digest item is not seal")
+
+// ErrApplyExtrinsic is returned when the runtime fails to apply an extrinsic while building a block
+var ErrApplyExtrinsic = errors.New("error applying extrinsic")
+
+// ErrInvalidThreshold is returned when the BABE constant c = C1/C2 is greater than 1
+var ErrInvalidThreshold = errors.New("invalid C1/C2: greater than 1")
+
+// ErrServiceStopped is returned when the service has been stopped
+var ErrServiceStopped = errors.New("service has been stopped")
+
+// ErrAlreadyPaused is returned when pausing a service that is already paused
+var ErrAlreadyPaused = errors.New("service already paused")
+
+// ErrNotPaused is returned when resuming a service that is not paused
+var ErrNotPaused = errors.New("service not paused")
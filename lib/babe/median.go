@@ -49,7 +49,7 @@ func (b *Service) estimateCurrentSlot() (uint64, error) {
 	for {
 		at := time.Unix(int64(arrivalTime), 0)
 
-		if time.Since(at) <= b.slotDuration() {
+		if b.clock.Now().Sub(at) <= b.slotDuration() {
 			return slot, nil
 		}
 
@@ -74,7 +74,7 @@ func (b *Service) getCurrentSlot() (uint64, error) {
 
 		st := time.Unix(int64(slotTime), 0)
 
-		if time.Since(st) <= b.slotDuration() {
+		if b.clock.Now().Sub(st) <= b.slotDuration() {
 			return estimate, nil
 		}
 
@@ -115,14 +115,11 @@ func addBlocksToState(t *testing.T, babeService *Service, depth int, blockState
 			t.Fatal(err)
 		}
 
-		pdEnc, err := predigest.Encode()
-		require.NoError(t, err)
-
 		block := &types.Block{
 			Header: &types.Header{
 				ParentHash: previousHash,
 				Number:     big.NewInt(int64(i)),
-				Digest:     [][]byte{pdEnc},
+				Digest:     types.NewDigest(predigest),
 			},
 			Body: &types.Body{},
 		}
@@ -155,7 +152,11 @@ func TestSlotTime(t *testing.T) {
 }
 
 func TestEstimateCurrentSlot(t *testing.T) {
-	babeService := createTestService(t, nil)
+	clock := NewFakeClock(time.Now())
+	babeService := createTestService(t, &ServiceConfig{
+		Authority: true,
+		Clock:     clock,
+	})
 	// create proof that we can authorize this block
 	babeService.threshold = maxThreshold
 	babeService.authorityIndex = 0
@@ -174,7 +175,7 @@ func TestEstimateCurrentSlot(t *testing.T) {
 
 	// create pre-digest
 	slot := Slot{
-		start:    uint64(time.Now().Unix()),
+		start:    uint64(clock.Now().Unix()),
 		duration: babeService.config.SlotDuration,
 		number:   slotNumber,
 	}
@@ -184,19 +185,16 @@ func TestEstimateCurrentSlot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	pdEnc, err := predigest.Encode()
-	require.NoError(t, err)
-
 	block := &types.Block{
 		Header: &types.Header{
 			ParentHash: genesisHeader.Hash(),
 			Number:     big.NewInt(int64(1)),
-			Digest:     [][]byte{pdEnc},
+			Digest:     types.NewDigest(predigest),
 		},
 		Body: &types.Body{},
 	}
 
-	arrivalTime := uint64(time.Now().Unix()) - slot.duration
+	arrivalTime := uint64(clock.Now().Unix()) - slot.duration
 
 	err = babeService.blockState.AddBlockWithArrivalTime(block, arrivalTime)
 	if err != nil {
@@ -214,19 +212,27 @@ func TestEstimateCurrentSlot(t *testing.T) {
 }
 
 func TestGetCurrentSlot(t *testing.T) {
-	babeService := createTestService(t, nil)
+	clock := NewFakeClock(time.Unix(1600000000, 0))
+	babeService := createTestService(t, &ServiceConfig{
+		Authority: true,
+		Clock:     clock,
+	})
 
 	// 100 blocks / 1000 ms/s
-	addBlocksToState(t, babeService, 100, babeService.blockState, uint64(time.Now().Unix())-(babeService.config.SlotDuration/10))
+	addBlocksToState(t, babeService, 100, babeService.blockState, uint64(clock.Now().Unix())-(babeService.config.SlotDuration/10))
 
 	res, err := babeService.getCurrentSlot()
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.NoError(t, err)
+	require.Equal(t, uint64(162), res)
 
-	expected := uint64(162)
+	// slot 162 started 2s ago, once it's more than a slot duration (3s) ago we're in slot 163
+	clock.Advance(time.Second)
+	res, err = babeService.getCurrentSlot()
+	require.NoError(t, err)
+	require.Equal(t, uint64(162), res)
 
-	if res != expected && res != expected+1 {
-		t.Fatalf("Fail: got %d expected %d", res, expected)
-	}
+	clock.Advance(time.Second)
+	res, err = babeService.getCurrentSlot()
+	require.NoError(t, err)
+	require.Equal(t, uint64(163), res)
 }
@@ -0,0 +1,112 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"bytes"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// AuthorshipProof is the proof of a block author's right to author the block in its slot, decoded from the
+// block's pre-digest
+type AuthorshipProof struct {
+	Slot           uint64
+	AuthorityIndex uint64
+	VrfOutput      [sr25519.VrfOutputLength]byte
+	VrfProof       [sr25519.VrfProofLength]byte
+	AuthoredByUs   bool // set if the block is sealed with one of our BABE keys
+}
+
+// AuthorshipProof returns the authorship proof of the block with the given header
+func (b *Service) AuthorshipProof(header *types.Header) (*AuthorshipProof, error) {
+	babeHeader, err := getBabeHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	ours, err := b.isSealedByUs(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorshipProof{
+		Slot:           babeHeader.SlotNumber,
+		AuthorityIndex: babeHeader.BlockProducerIndex,
+		VrfOutput:      babeHeader.VrfOutput,
+		VrfProof:       babeHeader.VrfProof,
+		AuthoredByUs:   ours,
+	}, nil
+}
+
+// isSealedByUs returns true if the seal of the header is signed by our keypair, or by a BABE key in the keystore
+func (b *Service) isSealedByUs(header *types.Header) (bool, error) {
+	if len(header.Digest) < 2 {
+		return false, ErrMissingDigest
+	}
+
+	seal, ok := header.Digest[len(header.Digest)-1].(*types.SealDigest)
+	if !ok {
+		return false, ErrNoSeal
+	}
+
+	// the seal signs the header without the seal
+	unsealed := header.DeepCopy()
+	unsealed.Digest = unsealed.Digest[:len(unsealed.Digest)-1]
+	enc, err := unsealed.Encode()
+	if err != nil {
+		return false, err
+	}
+
+	for _, pub := range b.ownKeys() {
+		ok, err := pub.Verify(enc, seal.Data)
+		if err == nil && ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ownKeys returns the public keys of our keypair and of the BABE keys in the keystore
+func (b *Service) ownKeys() []*sr25519.PublicKey {
+	// the keypair is switched by updateKeypair at the start of each epoch
+	b.lock.Lock()
+	keypair := b.keypair
+	b.lock.Unlock()
+
+	var keys []*sr25519.PublicKey
+	if keypair != nil {
+		keys = append(keys, keypair.Public().(*sr25519.PublicKey))
+	}
+
+	if b.keystore == nil {
+		return keys
+	}
+
+	for _, kp := range b.keystore.Keypairs() {
+		srkp, ok := kp.(*sr25519.Keypair)
+		if !ok || (keypair != nil && bytes.Equal(srkp.Public().Encode(), keypair.Public().Encode())) {
+			continue
+		}
+
+		keys = append(keys, srkp.Public().(*sr25519.PublicKey))
+	}
+
+	return keys
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorshipProof(t *testing.T) {
+	babeService := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+
+	block, slot := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1)
+
+	proof, err := babeService.AuthorshipProof(block.Header)
+	require.NoError(t, err)
+	require.Equal(t, slot.number, proof.Slot)
+	require.Equal(t, uint64(0), proof.AuthorityIndex)
+	require.True(t, proof.AuthoredByUs)
+
+	babeHeader, err := getBabeHeader(block.Header)
+	require.NoError(t, err)
+	require.Equal(t, babeHeader.VrfOutput, proof.VrfOutput)
+	require.Equal(t, babeHeader.VrfProof, proof.VrfProof)
+
+	other := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+
+	proof, err = other.AuthorshipProof(block.Header)
+	require.NoError(t, err)
+	require.Equal(t, slot.number, proof.Slot)
+	require.False(t, proof.AuthoredByUs)
+}
+
+func TestAuthorshipProof_NoSeal(t *testing.T) {
+	babeService := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+
+	block, _ := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1)
+	header := block.Header.DeepCopy()
+	header.Digest = header.Digest[:1]
+
+	_, err := babeService.AuthorshipProof(header)
+	require.Equal(t, ErrMissingDigest, err)
+}
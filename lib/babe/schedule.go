@@ -0,0 +1,136 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+)
+
+// EpochSchedule is the predicted start, authority set and randomness of an epoch
+type EpochSchedule struct {
+	Epoch       uint64
+	StartSlot   uint64
+	StartTime   time.Time // slots are numbered by their start time since the unix epoch
+	Authorities []*types.Authority
+	Randomness  [types.RandomnessLength]byte
+}
+
+// EpochSchedules returns the schedules of the current and next epochs. The current epoch is found from our clock and
+// the genesis slot, the slot of block 1 where the first epoch starts. The authorities and randomness of an epoch are
+// the ones announced by the NextEpochData digest of the first block of the epoch before it on our best chain, the
+// current ones are used for an epoch whose announcement isn't known.
+func (b *Service) EpochSchedules() ([]*EpochSchedule, error) {
+	genesisSlot, err := b.genesisSlot()
+	if err != nil {
+		return nil, err
+	}
+
+	curr := uint64(1)
+	if slot := uint64(b.clock.Now().UnixNano() / int64(b.slotDuration())); slot > genesisSlot {
+		curr = (slot-genesisSlot)/b.config.EpochLength + 1
+	}
+
+	announced, err := b.announcedEpochs(genesisSlot, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*EpochSchedule, 2)
+	for i := range schedules {
+		epoch := curr + uint64(i)
+		start := genesisSlot + (epoch-1)*b.config.EpochLength
+
+		schedules[i] = &EpochSchedule{
+			Epoch:       epoch,
+			StartSlot:   start,
+			StartTime:   time.Unix(0, int64(start)*int64(b.slotDuration())),
+			Authorities: b.authorityData,
+			Randomness:  b.randomness,
+		}
+
+		if data, has := announced[epoch]; has {
+			schedules[i].Authorities = data.AuthorityData
+			schedules[i].Randomness = data.Randomness
+		}
+	}
+
+	return schedules, nil
+}
+
+// genesisSlot returns the slot of block 1, where the first epoch starts
+func (b *Service) genesisSlot() (uint64, error) {
+	block1, err := b.blockState.GetBlockByNumber(big.NewInt(1))
+	if err != nil {
+		return 0, fmt.Errorf("cannot get genesis slot: %w", err)
+	}
+
+	return b.blockState.GetSlotForBlock(block1.Header.Hash())
+}
+
+// announcedEpochs returns the epoch data announced on our best chain for the given epoch and the one after it, by
+// epoch. The best chain is walked back to the start of the epoch before the given one, where the data of the given
+// epoch is announced.
+func (b *Service) announcedEpochs(genesisSlot, epoch uint64) (map[uint64]*Descriptor, error) {
+	announced := make(map[uint64]*Descriptor)
+
+	header, err := b.blockState.BestBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	for header.Number.Sign() > 0 {
+		var slot uint64
+		slot, err = b.blockState.GetSlotForBlock(header.Hash())
+		if err != nil {
+			return nil, err
+		}
+
+		blockEpoch := uint64(1)
+		if slot > genesisSlot {
+			blockEpoch = (slot-genesisSlot)/b.config.EpochLength + 1
+		}
+
+		if blockEpoch+1 < epoch {
+			break
+		}
+
+		// blocks in epochs after the given one, eg. on a chain ahead of our clock, don't announce the given epochs
+		if blockEpoch <= epoch {
+			var data *Descriptor
+			data, err = nextEpochDescriptor(header)
+			if err != nil {
+				return nil, err
+			}
+
+			// the first block of the epoch is reached last, its announcement is the one that counts
+			if data != nil {
+				announced[blockEpoch+1] = data
+			}
+		}
+
+		header, err = b.blockState.GetHeader(header.ParentHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return announced, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package babe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+
+	"github.com/stretchr/testify/require"
+)
+
+// addAnnouncingBlock builds a block in the given slot that announces the given epoch data, and adds it to the state
+func addAnnouncingBlock(t *testing.T, babeService *Service, parent *types.Header, slot uint64,
+	data *types.NextEpochData) *types.Header {
+	block, _ := createTestBlock(t, babeService, parent, [][]byte{}, slot)
+	header := withNextEpochData(t, babeService, block.Header, data)
+
+	err := babeService.blockState.AddBlock(&types.Block{
+		Header: header,
+		Body:   block.Body,
+	})
+	require.NoError(t, err)
+	return header
+}
+
+func TestEpochSchedules(t *testing.T) {
+	babeService := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+	length := babeService.config.EpochLength
+	slotDuration := int64(babeService.slotDuration())
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	auths2 := []*types.AuthorityRaw{babeService.authorityData[0].ToRaw()}
+	auths3 := []*types.AuthorityRaw{{Key: kp.Public().(*sr25519.PublicKey).AsBytes(), Weight: 1}}
+	randomness2 := [types.RandomnessLength]byte{0x22}
+	randomness3 := [types.RandomnessLength]byte{0x33}
+
+	// block 1 starts epoch 1 in slot 7 and announces epoch 2, the first block of epoch 2 announces epoch 3
+	genesisSlot := uint64(7)
+	header1 := addAnnouncingBlock(t, babeService, genesisHeader, genesisSlot, &types.NextEpochData{
+		Authorities: auths2,
+		Randomness:  randomness2,
+	})
+	addAnnouncingBlock(t, babeService, header1, genesisSlot+length, &types.NextEpochData{
+		Authorities: auths3,
+		Randomness:  randomness3,
+	})
+
+	// the epochs are found from the clock, not from the best block
+	for _, tc := range []struct {
+		slot        uint64
+		epoch       uint64
+		randomness  [2][types.RandomnessLength]byte
+		authorities [2][]*types.AuthorityRaw
+	}{
+		{
+			slot:        genesisSlot + 2,
+			epoch:       1,
+			randomness:  [2][types.RandomnessLength]byte{babeService.randomness, randomness2},
+			authorities: [2][]*types.AuthorityRaw{auths2, auths2},
+		},
+		{
+			slot:        genesisSlot + length + 3,
+			epoch:       2,
+			randomness:  [2][types.RandomnessLength]byte{randomness2, randomness3},
+			authorities: [2][]*types.AuthorityRaw{auths2, auths3},
+		},
+		{
+			slot:        genesisSlot + 2*length,
+			epoch:       3,
+			randomness:  [2][types.RandomnessLength]byte{randomness3, babeService.randomness},
+			authorities: [2][]*types.AuthorityRaw{auths3, auths2},
+		},
+	} {
+		babeService.clock = NewFakeClock(time.Unix(0, int64(tc.slot)*slotDuration))
+
+		schedules, err := babeService.EpochSchedules()
+		require.NoError(t, err)
+		require.Len(t, schedules, 2)
+
+		for i, s := range schedules {
+			start := genesisSlot + (tc.epoch+uint64(i)-1)*length
+			require.Equal(t, tc.epoch+uint64(i), s.Epoch)
+			require.Equal(t, start, s.StartSlot)
+			require.Equal(t, time.Unix(0, int64(start)*slotDuration), s.StartTime)
+			require.Equal(t, tc.randomness[i], s.Randomness)
+
+			auths := make([]*types.AuthorityRaw, len(s.Authorities))
+			for j, a := range s.Authorities {
+				auths[j] = a.ToRaw()
+			}
+			require.Equal(t, tc.authorities[i], auths)
+		}
+	}
+}
+
+func TestEpochSchedules_NoBlocks(t *testing.T) {
+	babeService := createTestService(t, nil)
+
+	// the genesis slot isn't known until block 1 is imported
+	_, err := babeService.EpochSchedules()
+	require.Error(t, err)
+}
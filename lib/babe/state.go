@@ -50,7 +50,10 @@ type BlockState interface {
 // StorageState interface for storage state methods
 type StorageState interface {
 	TrieState(hash *common.Hash) (*state.TrieState, error)
+	TrieStateCopy(hash *common.Hash) (*state.TrieState, error)
 	StoreTrie(root common.Hash, ts *state.TrieState) error
+	Lock()
+	Unlock()
 }
 
 // TransactionState is the interface for transaction queue methods
@@ -58,6 +61,10 @@ type TransactionState interface {
 	Push(vt *transaction.ValidTransaction) (common.Hash, error)
 	Pop() *transaction.ValidTransaction
 	Peek() *transaction.ValidTransaction
+	PendingInPool() []*transaction.ValidTransaction
+	RemoveExtrinsicFromPool(ext types.Extrinsic)
+	RegisterReadyChannel(ch chan<- struct{}) (byte, error)
+	UnregisterReadyChannel(id byte)
 }
 
 // EpochState is the interface for epoch methods
@@ -68,4 +75,5 @@ type EpochState interface {
 	GetEpochInfo(epoch uint64) (*types.EpochInfo, error)
 	HasEpochInfo(epoch uint64) (bool, error)
 	GetStartSlotForEpoch(epoch uint64) (uint64, error)
+	PruneEpochInfo(epoch uint64) error
 }
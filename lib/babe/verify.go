@@ -27,6 +27,7 @@ import (
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/scale"
 )
 
 // VerificationManager assists the syncer in keeping track of what epoch is it currently syncing and verifying,
@@ -42,6 +43,17 @@ type VerificationManager struct {
 
 	// current epoch information
 	verifier *verifier // current chain head verifier TODO: remove, or keep historical verifiers
+
+	// epoch data announced by NextEpochData digests, by the hash of the announcing block
+	epochLength uint64
+	genesisSlot uint64 // slot of block 1, where the first epoch starts, or 0 if it isn't known yet
+	nextEpochs  map[common.Hash]*announcedEpoch
+}
+
+// announcedEpoch is the authorities and randomness of an epoch, announced in the first block of the epoch before it
+type announcedEpoch struct {
+	epoch      uint64
+	descriptor *Descriptor
 }
 
 // NewVerificationManagerFromRuntime returns a new VerificationManager
@@ -51,7 +63,18 @@ func NewVerificationManagerFromRuntime(blockState BlockState, rt runtime.LegacyI
 		return nil, err
 	}
 
-	return NewVerificationManager(blockState, descriptor)
+	vm, err := NewVerificationManager(blockState, descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := rt.BabeConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	vm.SetEpochLength(cfg.EpochLength)
+	return vm, nil
 }
 
 // NewVerificationManager returns a new NewVerificationManager
@@ -78,9 +101,137 @@ func NewVerificationManager(blockState BlockState, descriptor *Descriptor) (*Ver
 		branchNums:  []int64{0},
 		branches:    branches,
 		verifier:    verifier,
+		nextEpochs:  make(map[common.Hash]*announcedEpoch),
 	}, nil
 }
 
+// SetEpochLength sets the number of slots in an epoch, which is needed to apply the epoch data announced by
+// NextEpochData digests. Epoch data announcements are ignored until it's set.
+func (v *VerificationManager) SetEpochLength(length uint64) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.epochLength = length
+}
+
+// epochOf returns the epoch that the slot of the given header is in. The first epoch starts at the genesis slot,
+// which is the slot of block 1.
+func (v *VerificationManager) epochOf(header *types.Header, slot uint64) (uint64, error) {
+	genesisSlot, err := v.getGenesisSlot(header, slot)
+	if err != nil {
+		return 0, err
+	}
+
+	if slot < genesisSlot {
+		return 1, nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return (slot-genesisSlot)/v.epochLength + 1, nil
+}
+
+// getGenesisSlot returns the slot of block 1, given a header and its slot in case it's block 1 itself
+func (v *VerificationManager) getGenesisSlot(header *types.Header, slot uint64) (uint64, error) {
+	if header.Number.Cmp(big.NewInt(1)) == 0 {
+		return slot, nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.genesisSlot != 0 {
+		return v.genesisSlot, nil
+	}
+
+	// block 1 is finalized long before the first epoch ends, so its slot doesn't change once it's known
+	block1, err := v.blockState.GetBlockByNumber(big.NewInt(1))
+	if err != nil {
+		return 0, fmt.Errorf("cannot get genesis slot: %w", err)
+	}
+
+	v.genesisSlot, err = v.blockState.GetSlotForBlock(block1.Header.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("cannot get genesis slot: %w", err)
+	}
+
+	return v.genesisSlot, nil
+}
+
+// announcedEpochFor returns the data of the given epoch that was announced on the chain of the given parent block,
+// or nil if none was announced
+func (v *VerificationManager) announcedEpochFor(parent common.Hash, epoch uint64) *Descriptor {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for hash, ann := range v.nextEpochs {
+		if ann.epoch != epoch {
+			continue
+		}
+
+		if is, _ := v.blockState.IsDescendantOf(hash, parent); hash == parent || is {
+			return ann.descriptor
+		}
+	}
+
+	return nil
+}
+
+// recordNextEpochData stores the epoch data announced by a NextEpochData digest of a verified block, if it has one.
+// The data applies to the blocks of the epoch after the block's epoch that descend from the block, so the block
+// itself is never verified with the data it announces.
+func (v *VerificationManager) recordNextEpochData(hash common.Hash, header *types.Header, epoch uint64) error {
+	descriptor, err := nextEpochDescriptor(header)
+	if err != nil || descriptor == nil {
+		return err
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.nextEpochs[hash] = &announcedEpoch{
+		epoch:      epoch + 1,
+		descriptor: descriptor,
+	}
+
+	// announcements for epochs before the current one aren't needed anymore
+	for h, ann := range v.nextEpochs {
+		if ann.epoch < epoch {
+			delete(v.nextEpochs, h)
+		}
+	}
+
+	return nil
+}
+
+// nextEpochDescriptor returns the authorities and randomness announced by the NextEpochData digest of the given
+// header, or nil if it doesn't have one
+func nextEpochDescriptor(header *types.Header) (*Descriptor, error) {
+	for _, d := range header.Digest {
+		cd, ok := d.(*types.ConsensusDigest)
+		if !ok || cd.ConsensusEngineID != types.BabeEngineID || cd.DataType() != types.NextEpochDataType {
+			continue
+		}
+
+		dec, err := scale.Decode(cd.Data[1:], new(types.NextEpochData))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode next epoch data: %w", err)
+		}
+		data := dec.(*types.NextEpochData)
+
+		auths, err := types.BABEAuthorityRawToAuthority(data.Authorities)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Descriptor{
+			AuthorityData: auths,
+			Randomness:    data.Randomness,
+		}, nil
+	}
+
+	return nil, nil
+}
+
 // SetRuntimeChangeAtBlock sets a runtime change at the given block
 // Blocks that are descendants of this block will be verified using the given runtime
 func (v *VerificationManager) SetRuntimeChangeAtBlock(header *types.Header, rt runtime.LegacyInstance) error {
@@ -195,7 +346,45 @@ func (v *VerificationManager) VerifyBlock(header *types.Header) (bool, error) {
 		}
 	}
 
-	return verifier.verifyAuthorshipRight(header)
+	v.lock.Lock()
+	epochLength := v.epochLength
+	v.lock.Unlock()
+
+	if epochLength == 0 {
+		return verifier.verifyAuthorshipRight(header)
+	}
+
+	babeHeader, err := getBabeHeader(header)
+	if err != nil {
+		return false, err
+	}
+
+	// blocks of an epoch, including its first block, are verified with the data announced for the epoch in the
+	// first block of the previous epoch, rather than the data of the previous epoch
+	epoch, err := v.epochOf(header, babeHeader.SlotNumber)
+	if err != nil {
+		return false, err
+	}
+
+	if ann := v.announcedEpochFor(header.ParentHash, epoch); ann != nil {
+		verifier, err = newVerifier(v.blockState, &Descriptor{
+			AuthorityData: ann.AuthorityData,
+			Randomness:    ann.Randomness,
+			Threshold:     verifier.threshold,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// the seal is removed from the header during verification, which changes its hash
+	hash := header.Hash()
+	ok, err := verifier.verifyAuthorshipRight(header)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, v.recordNextEpochData(hash, header, epoch)
 }
 
 func descriptorFromRuntime(rt runtime.LegacyInstance) (*Descriptor, error) {
@@ -246,14 +435,14 @@ func newVerifier(blockState BlockState, descriptor *Descriptor) (*verifier, erro
 // verifySlotWinner verifies the claim for a slot, given the BabeHeader for that slot.
 func (b *verifier) verifySlotWinner(slot uint64, header *types.BabeHeader) (bool, error) {
 	if len(b.authorityData) <= int(header.BlockProducerIndex) {
-		return false, fmt.Errorf("no authority data for index %d", header.BlockProducerIndex)
+		return false, fmt.Errorf("%w: %d", ErrUnknownAuthority, header.BlockProducerIndex)
 	}
 
 	// check that vrf output is under threshold
 	// if not, then return an error
 	output := big.NewInt(0).SetBytes(header.VrfOutput[:])
 	if output.Cmp(b.threshold) >= 0 {
-		return false, fmt.Errorf("vrf output over threshold")
+		return false, fmt.Errorf("%w: output over threshold", ErrInvalidVRF)
 	}
 
 	pub := b.authorityData[header.BlockProducerIndex].Key
@@ -275,41 +464,28 @@ func (b *verifier) verifyAuthorshipRight(header *types.Header) (bool, error) {
 	// header should have 2 digest items (possibly more in the future)
 	// first item should be pre-digest, second should be seal
 	if len(header.Digest) < 2 {
-		return false, fmt.Errorf("block header is missing digest items")
+		return false, ErrMissingDigest
 	}
 
 	// check for valid seal by verifying signature
-	preDigestBytes := header.Digest[0]
-	sealBytes := header.Digest[len(header.Digest)-1]
-
-	digestItem, err := types.DecodeDigestItem(preDigestBytes)
-	if err != nil {
-		return false, err
-	}
-
-	preDigest, ok := digestItem.(*types.PreRuntimeDigest)
+	preDigest, ok := header.Digest[0].(*types.PreRuntimeDigest)
 	if !ok {
-		return false, fmt.Errorf("first digest item is not pre-digest")
-	}
-
-	digestItem, err = types.DecodeDigestItem(sealBytes)
-	if err != nil {
-		return false, err
+		return false, ErrNoPreDigest
 	}
 
-	seal, ok := digestItem.(*types.SealDigest)
+	seal, ok := header.Digest[len(header.Digest)-1].(*types.SealDigest)
 	if !ok {
-		return false, fmt.Errorf("last digest item is not seal")
+		return false, ErrNoSeal
 	}
 
 	babeHeader := new(types.BabeHeader)
-	err = babeHeader.Decode(preDigest.Data)
+	err := babeHeader.Decode(preDigest.Data)
 	if err != nil {
-		return false, fmt.Errorf("cannot decode babe header from pre-digest: %s", err)
+		return false, fmt.Errorf("cannot decode babe header from pre-digest: %w", err)
 	}
 
 	if len(b.authorityData) <= int(babeHeader.BlockProducerIndex) {
-		return false, fmt.Errorf("no authority data for index %d", babeHeader.BlockProducerIndex)
+		return false, fmt.Errorf("%w: %d", ErrUnknownAuthority, babeHeader.BlockProducerIndex)
 	}
 
 	slot := babeHeader.SlotNumber
@@ -366,25 +542,38 @@ func (b *verifier) verifyAuthorshipRight(header *types.Header) (bool, error) {
 	return true, nil
 }
 
-func getBlockProducerIndex(header *types.Header) (uint64, error) {
+// getBabeHeader returns the BABE header in the pre-digest of the given block header
+func getBabeHeader(header *types.Header) (*types.BabeHeader, error) {
 	if len(header.Digest) == 0 {
-		return 0, fmt.Errorf("no digest provided")
+		return nil, ErrMissingDigest
 	}
 
-	preDigestBytes := header.Digest[0]
+	preDigest, ok := header.Digest[0].(*types.PreRuntimeDigest)
+	if !ok {
+		return nil, ErrNoPreDigest
+	}
 
-	digestItem, err := types.DecodeDigestItem(preDigestBytes)
+	babeHeader := new(types.BabeHeader)
+	err := babeHeader.Decode(preDigest.Data)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("cannot decode babe header from pre-digest: %w", err)
 	}
 
-	preDigest, ok := digestItem.(*types.PreRuntimeDigest)
+	return babeHeader, nil
+}
+
+func getBlockProducerIndex(header *types.Header) (uint64, error) {
+	if len(header.Digest) == 0 {
+		return 0, ErrMissingDigest
+	}
+
+	preDigest, ok := header.Digest[0].(*types.PreRuntimeDigest)
 	if !ok {
-		return 0, err
+		return 0, ErrNoPreDigest
 	}
 
 	babeHeader := new(types.BabeHeader)
-	err = babeHeader.Decode(preDigest.Data)
+	err := babeHeader.Decode(preDigest.Data)
 	if err != nil {
 		return 0, err
 	}
@@ -17,6 +17,7 @@
 package babe
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -204,6 +205,127 @@ func TestVerificationManager_VerifyBlock_Branches(t *testing.T) {
 	require.Equal(t, true, ok)
 }
 
+// withNextEpochData returns a copy of the given block header that carries the given NextEpochData digest,
+// sealed again by the given service
+func withNextEpochData(t *testing.T, babeService *Service, header *types.Header, data *types.NextEpochData) *types.Header {
+	enc, err := data.Encode()
+	require.NoError(t, err)
+
+	cd := &types.ConsensusDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              enc,
+	}
+
+	// replace the seal with the digest, then seal the header again
+	digest := append(append(types.NewEmptyDigest(), header.Digest[:len(header.Digest)-1]...), cd)
+	res, err := types.NewHeader(header.ParentHash, header.Number, header.StateRoot, header.ExtrinsicsRoot, digest)
+	require.NoError(t, err)
+
+	seal, err := babeService.buildBlockSeal(res)
+	require.NoError(t, err)
+
+	res.Digest = append(res.Digest, seal)
+	return res
+}
+
+func TestVerificationManager_VerifyBlock_EpochBoundary(t *testing.T) {
+	babeService := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+	descriptor := babeService.Descriptor()
+
+	vm := newTestVerificationManager(t, descriptor)
+	vm.SetEpochLength(testEpochLength)
+
+	auths := make([]*types.AuthorityRaw, len(descriptor.AuthorityData))
+	for i, a := range descriptor.AuthorityData {
+		auths[i] = a.ToRaw()
+	}
+
+	randomness2 := [types.RandomnessLength]byte{0x22}
+	randomness3 := [types.RandomnessLength]byte{0x33}
+
+	// the first block of epoch 1 announces the data of epoch 2
+	block1, _ := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1)
+	header1 := withNextEpochData(t, babeService, block1.Header, &types.NextEpochData{
+		Authorities: auths,
+		Randomness:  randomness2,
+	})
+
+	for _, bs := range []BlockState{babeService.blockState, vm.blockState} {
+		err := bs.AddBlock(&types.Block{
+			Header: header1,
+			Body:   block1.Body,
+		})
+		require.NoError(t, err)
+	}
+
+	ok, err := vm.VerifyBlock(header1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the first block of epoch 2 is verified with the randomness announced for epoch 2, not the randomness of
+	// epoch 1 nor the randomness it announces for epoch 3 itself
+	babeService.randomness = randomness2
+	block2, _ := createTestBlock(t, babeService, header1, [][]byte{}, testEpochLength+1)
+	header2 := withNextEpochData(t, babeService, block2.Header, &types.NextEpochData{
+		Authorities: auths,
+		Randomness:  randomness3,
+	})
+
+	ok, err = vm.VerifyBlock(header2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// a block of epoch 2 with a claim made with the randomness of epoch 1 is rejected
+	babeService.randomness = descriptor.Randomness
+	block2b, _ := createTestBlock(t, babeService, header1, [][]byte{}, testEpochLength+2)
+
+	_, err = vm.VerifyBlock(block2b.Header)
+	require.Equal(t, ErrBadSlotClaim, err)
+
+	// a block of epoch 2 on a chain where epoch 2 wasn't announced is verified with the data of its branch
+	block1b, _ := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 2)
+	err = vm.blockState.AddBlock(block1b)
+	require.NoError(t, err)
+	err = babeService.blockState.AddBlock(block1b)
+	require.NoError(t, err)
+
+	block2c, _ := createTestBlock(t, babeService, block1b.Header, [][]byte{}, testEpochLength+3)
+	ok, err = vm.VerifyBlock(block2c.Header)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestVerificationManager_EpochOf(t *testing.T) {
+	babeService := createTestService(t, &ServiceConfig{
+		Threshold: maxThreshold,
+	})
+
+	vm := newTestVerificationManager(t, babeService.Descriptor())
+	vm.SetEpochLength(10)
+
+	// the first epoch starts at the slot of block 1, not at slot 1
+	block1, _ := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 5)
+	err := vm.blockState.AddBlock(block1)
+	require.NoError(t, err)
+
+	epoch, err := vm.epochOf(block1.Header, 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), epoch)
+
+	header2 := &types.Header{
+		ParentHash: block1.Header.Hash(),
+		Number:     big.NewInt(2),
+	}
+
+	for slot, expected := range map[uint64]uint64{14: 1, 15: 2, 24: 2, 25: 3} {
+		epoch, err = vm.epochOf(header2, slot)
+		require.NoError(t, err)
+		require.Equal(t, expected, epoch, "slot %d", slot)
+	}
+}
+
 func TestVerifySlotWinner(t *testing.T) {
 	kp, err := sr25519.GenerateKeypair()
 	if err != nil {
@@ -275,6 +397,33 @@ func TestVerifyAuthorshipRight(t *testing.T) {
 	}
 }
 
+func TestVerifyAuthorshipRight_Errors(t *testing.T) {
+	babeService := createTestService(t, nil)
+	block, _ := createTestBlock(t, babeService, genesisHeader, [][]byte{}, 1)
+
+	verifier, err := newVerifier(babeService.blockState, babeService.Descriptor())
+	require.NoError(t, err)
+
+	header := block.Header.DeepCopy()
+	header.Digest = header.Digest[:1]
+	_, err = verifier.verifyAuthorshipRight(header)
+	require.Equal(t, ErrMissingDigest, err)
+
+	header = block.Header.DeepCopy()
+	header.Digest = types.NewDigest(header.Digest[1], header.Digest[0])
+	_, err = verifier.verifyAuthorshipRight(header)
+	require.Equal(t, ErrNoPreDigest, err)
+
+	header = block.Header.DeepCopy()
+	header.Digest = types.NewDigest(header.Digest[0], header.Digest[0])
+	_, err = verifier.verifyAuthorshipRight(header)
+	require.Equal(t, ErrNoSeal, err)
+
+	verifier.authorityData = nil
+	_, err = verifier.verifyAuthorshipRight(block.Header.DeepCopy())
+	require.True(t, errors.Is(err, ErrUnknownAuthority))
+}
+
 func TestVerifyAuthorshipRight_Equivocation(t *testing.T) {
 	kp, err := sr25519.GenerateKeypair()
 	if err != nil {
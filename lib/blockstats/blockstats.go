@@ -0,0 +1,360 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockstats
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DefaultWindow is the number of recent blocks the aggregates are computed over
+const DefaultWindow = 256
+
+// maxSlotLag is the number of slots an imported block that wasn't announced may be behind the current slot before
+// it's considered to be synced rather than propagated, and is ignored
+const maxSlotLag = 16
+
+// announceTTL is how long the arrival of an announced block is kept if the block isn't imported
+const announceTTL = 5 * time.Minute
+
+// BlockState is the interface the Collector receives imported blocks from
+type BlockState interface {
+	RegisterImportedChannel(ch chan<- *types.Block) (byte, error)
+	UnregisterImportedChannel(id byte)
+}
+
+// Summary holds the mean, median, 95th percentile and maximum of a duration in milliseconds
+type Summary struct {
+	Mean   uint64 `json:"mean"`
+	Median uint64 `json:"median"`
+	P95    uint64 `json:"p95"`
+	Max    uint64 `json:"max"`
+}
+
+// Stats are the block time, propagation delay and missed slot aggregates of the observed blocks. The block time
+// and propagation delay are computed over the collector's window of recent blocks.
+type Stats struct {
+	Blocks           uint64  `json:"blocks"`          // number of blocks observed
+	MissedSlots      uint64  `json:"missedSlots"`     // number of empty slots between observed blocks and their parents
+	MissedSlotRatio  float64 `json:"missedSlotRatio"` // fraction of the slots in the window that had no block
+	BlockTime        Summary `json:"blockTime"`
+	PropagationDelay Summary `json:"propagationDelay"`
+}
+
+// observation is an imported block with its arrival time
+type observation struct {
+	hash     common.Hash
+	slot     uint64
+	arrival  time.Time
+	interval time.Duration // time since the parent arrived, zero if the parent wasn't observed
+	slots    uint64        // number of slots since the parent, zero if the parent wasn't observed
+	offset   time.Duration // arrival time minus the slot number times the slot duration
+}
+
+// Collector observes the blocks imported by the node and aggregates the time between blocks, their propagation
+// delay and the slots without a block.
+//
+// The arrival time of a block is the time it was first announced to the node by a peer, see BlockAnnounced, so
+// that it doesn't include the time spent requesting, verifying and importing it. Blocks that weren't announced,
+// eg. the ones produced by the node, arrive when they're imported. Blocks imported during the initial sync are
+// ignored.
+//
+// Slot numbers don't map to wall clock time, so the propagation delay of a block is its arrival time relative to
+// the block of the window that arrived earliest into its slot. A rising delay across the network points to
+// degraded propagation, while a delay only seen by some nodes points to local issues.
+type Collector struct {
+	blockState   BlockState
+	slotDuration time.Duration
+	window       int
+
+	mu        sync.Mutex
+	blocks    map[common.Hash]*observation
+	recent    []*observation // oldest first
+	total     uint64
+	missed    uint64
+	announced map[common.Hash]time.Time // arrival time of the announced blocks that weren't imported yet
+	now       func() time.Time
+
+	in      chan *types.Block
+	chanID  byte
+	stopped chan struct{}
+}
+
+// NewCollector returns a Collector for the given slot duration that aggregates the last window blocks, or
+// DefaultWindow blocks if window is zero
+func NewCollector(bs BlockState, slotDuration time.Duration, window int) *Collector {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	return &Collector{
+		blockState:   bs,
+		slotDuration: slotDuration,
+		window:       window,
+		blocks:       make(map[common.Hash]*observation),
+		announced:    make(map[common.Hash]time.Time),
+		now:          time.Now,
+		stopped:      make(chan struct{}),
+	}
+}
+
+// Start starts observing imported blocks
+func (c *Collector) Start() error {
+	c.in = make(chan *types.Block, 16)
+	id, err := c.blockState.RegisterImportedChannel(c.in)
+	if err != nil {
+		return err
+	}
+
+	c.chanID = id
+	go c.handleBlocks()
+	return nil
+}
+
+// Stop stops observing imported blocks
+func (c *Collector) Stop() error {
+	close(c.stopped)
+	c.blockState.UnregisterImportedChannel(c.chanID)
+	return nil
+}
+
+func (c *Collector) handleBlocks() {
+	for {
+		select {
+		case block := <-c.in:
+			if block == nil || block.Header == nil {
+				continue
+			}
+
+			c.blockImported(block.Header)
+		case <-c.stopped:
+			return
+		}
+	}
+}
+
+// BlockAnnounced records the arrival of the announced block with the given header, it's observed once it's
+// imported. Only the first announcement of a block is recorded.
+func (c *Collector) BlockAnnounced(header *types.Header) {
+	hash := header.Hash()
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, has := c.announced[hash]; has {
+		return
+	}
+
+	// drop the blocks that were announced but never imported
+	if len(c.announced) >= c.window {
+		for h, arrival := range c.announced {
+			if now.Sub(arrival) > announceTTL {
+				delete(c.announced, h)
+			}
+		}
+
+		if len(c.announced) >= c.window {
+			return
+		}
+	}
+
+	c.announced[hash] = now
+}
+
+// blockImported observes the imported block with the given header, at the time it was announced if it was
+func (c *Collector) blockImported(header *types.Header) {
+	hash := header.Hash()
+
+	c.mu.Lock()
+	arrival, announced := c.announced[hash]
+	delete(c.announced, hash)
+	c.mu.Unlock()
+
+	if announced {
+		c.Observe(header, arrival)
+		return
+	}
+
+	now := c.now()
+	if c.syncing(header, now) {
+		return
+	}
+
+	c.Observe(header, now)
+}
+
+// syncing returns true if the block with the given header is too far behind the current slot to have been
+// propagated, ie. if it's being synced
+func (c *Collector) syncing(header *types.Header, now time.Time) bool {
+	if c.slotDuration == 0 {
+		return false
+	}
+
+	slot, err := types.GetSlotFromHeader(header)
+	if err != nil {
+		return false
+	}
+
+	current := uint64(now.UnixNano() / int64(c.slotDuration))
+	return slot+maxSlotLag < current
+}
+
+// Observe records the arrival of the block with the given header. Blocks without a BABE pre-digest are ignored.
+func (c *Collector) Observe(header *types.Header, arrival time.Time) {
+	slot, err := types.GetSlotFromHeader(header)
+	if err != nil {
+		return
+	}
+
+	o := &observation{
+		hash:    header.Hash(),
+		slot:    slot,
+		arrival: arrival,
+		offset:  arrival.Sub(time.Unix(0, 0)) - time.Duration(slot)*c.slotDuration,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, has := c.blocks[o.hash]; has {
+		return
+	}
+
+	if parent, has := c.blocks[header.ParentHash]; has && slot > parent.slot {
+		o.interval = arrival.Sub(parent.arrival)
+		o.slots = slot - parent.slot
+		c.missed += o.slots - 1
+	}
+
+	c.total++
+	c.blocks[o.hash] = o
+	c.recent = append(c.recent, o)
+	if len(c.recent) > c.window {
+		delete(c.blocks, c.recent[0].hash)
+		c.recent = c.recent[1:]
+	}
+}
+
+// Stats returns the aggregates of the observed blocks
+func (c *Collector) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := Stats{
+		Blocks:      c.total,
+		MissedSlots: c.missed,
+	}
+
+	if len(c.recent) == 0 {
+		return stats
+	}
+
+	minOffset := c.recent[0].offset
+	for _, o := range c.recent {
+		if o.offset < minOffset {
+			minOffset = o.offset
+		}
+	}
+
+	var (
+		intervals, delays []time.Duration
+		slots, missed     uint64
+	)
+
+	for _, o := range c.recent {
+		delays = append(delays, o.offset-minOffset)
+		if o.slots == 0 {
+			continue
+		}
+
+		intervals = append(intervals, o.interval)
+		slots += o.slots
+		missed += o.slots - 1
+	}
+
+	if slots > 0 {
+		stats.MissedSlotRatio = float64(missed) / float64(slots)
+	}
+
+	stats.BlockTime = summarize(intervals)
+	stats.PropagationDelay = summarize(delays)
+	return stats
+}
+
+func summarize(ds []time.Duration) Summary {
+	if len(ds) == 0 {
+		return Summary{}
+	}
+
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i] < ds[j]
+	})
+
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+
+	return Summary{
+		Mean:   uint64((sum / time.Duration(len(ds))).Milliseconds()),
+		Median: uint64(ds[len(ds)/2].Milliseconds()),
+		P95:    uint64(ds[(len(ds)*95-1)/100].Milliseconds()),
+		Max:    uint64(ds[len(ds)-1].Milliseconds()),
+	}
+}
+
+// ServeHTTP writes the block stats in the Prometheus text format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	stats := c.Stats()
+
+	buf.WriteString("# HELP gossamer_blocks_observed_total Number of imported blocks observed.\n")
+	buf.WriteString("# TYPE gossamer_blocks_observed_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_blocks_observed_total %d\n", stats.Blocks)
+
+	buf.WriteString("# HELP gossamer_missed_slots_total Number of slots without a block.\n")
+	buf.WriteString("# TYPE gossamer_missed_slots_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_missed_slots_total %d\n", stats.MissedSlots)
+
+	buf.WriteString("# HELP gossamer_missed_slot_ratio Fraction of recent slots without a block.\n")
+	buf.WriteString("# TYPE gossamer_missed_slot_ratio gauge\n")
+	fmt.Fprintf(&buf, "gossamer_missed_slot_ratio %g\n", stats.MissedSlotRatio)
+
+	writeSummary(&buf, "gossamer_block_time_seconds", "Time between recent blocks and their parents.", stats.BlockTime)
+	writeSummary(&buf, "gossamer_block_propagation_delay_seconds", "Arrival delay of recent blocks into their slot.", stats.PropagationDelay)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeSummary(buf *bytes.Buffer, name, help string, s Summary) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(buf, "%s{stat=\"mean\"} %g\n", name, float64(s.Mean)/1000)
+	fmt.Fprintf(buf, "%s{stat=\"median\"} %g\n", name, float64(s.Median)/1000)
+	fmt.Fprintf(buf, "%s{stat=\"p95\"} %g\n", name, float64(s.P95)/1000)
+	fmt.Fprintf(buf, "%s{stat=\"max\"} %g\n", name, float64(s.Max)/1000)
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockstats
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testSlotDuration = time.Second
+
+func newTestHeader(parent common.Hash, number int64, slot uint64) *types.Header {
+	bh := &types.BabeHeader{SlotNumber: slot}
+	d := &types.PreRuntimeDigest{
+		ConsensusEngineID: types.BabeEngineID,
+		Data:              bh.Encode(),
+	}
+
+	return &types.Header{
+		ParentHash: parent,
+		Number:     big.NewInt(number),
+		Digest:     types.NewDigest(d),
+	}
+}
+
+func TestCollector_Stats(t *testing.T) {
+	c := NewCollector(nil, testSlotDuration, 0)
+	start := time.Unix(1000, 0)
+
+	// blocks at slots 1, 2 and 4, the last one arriving 500ms into its slot
+	h1 := newTestHeader(common.Hash{}, 1, 1)
+	c.Observe(h1, start)
+	h2 := newTestHeader(h1.Hash(), 2, 2)
+	c.Observe(h2, start.Add(testSlotDuration))
+	h3 := newTestHeader(h2.Hash(), 3, 4)
+	c.Observe(h3, start.Add(3*testSlotDuration+500*time.Millisecond))
+
+	// duplicate blocks and blocks without a slot are ignored
+	c.Observe(h3, start.Add(4*testSlotDuration))
+	c.Observe(&types.Header{Number: big.NewInt(4), Digest: types.NewEmptyDigest()}, start)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(3), stats.Blocks)
+	require.Equal(t, uint64(1), stats.MissedSlots)
+	require.Equal(t, float64(1)/3, stats.MissedSlotRatio)
+	require.Equal(t, Summary{Mean: 1750, Median: 2500, P95: 2500, Max: 2500}, stats.BlockTime)
+	require.Equal(t, Summary{Mean: 166, Median: 0, P95: 500, Max: 500}, stats.PropagationDelay)
+}
+
+func TestCollector_Window(t *testing.T) {
+	c := NewCollector(nil, testSlotDuration, 2)
+	start := time.Unix(1000, 0)
+
+	parent := common.Hash{}
+	for i := uint64(1); i <= 4; i++ {
+		h := newTestHeader(parent, int64(i), i)
+		c.Observe(h, start.Add(time.Duration(i)*testSlotDuration))
+		parent = h.Hash()
+	}
+
+	require.Len(t, c.recent, 2)
+	require.Len(t, c.blocks, 2)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(4), stats.Blocks)
+	require.Equal(t, uint64(0), stats.MissedSlots)
+	require.Equal(t, uint64(1000), stats.BlockTime.Mean)
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	c := NewCollector(nil, testSlotDuration, 0)
+	c.Observe(newTestHeader(common.Hash{}, 1, 1), time.Unix(1000, 0))
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, "gossamer_blocks_observed_total 1\n"))
+	require.True(t, strings.Contains(body, "# TYPE gossamer_block_time_seconds gauge\n"))
+	require.True(t, strings.Contains(body, "gossamer_block_propagation_delay_seconds{stat=\"max\"} 0\n"))
+}
+
+func TestCollector_BlockAnnounced(t *testing.T) {
+	c := NewCollector(nil, testSlotDuration, 0)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time {
+		return now
+	}
+
+	// the block is observed at the time of its first announcement rather than its import
+	h1 := newTestHeader(common.Hash{}, 1, 1000)
+	c.BlockAnnounced(h1)
+	now = now.Add(100 * time.Millisecond)
+	c.BlockAnnounced(h1)
+	now = now.Add(time.Second)
+	c.blockImported(h1)
+
+	require.Len(t, c.announced, 0)
+	require.Len(t, c.recent, 1)
+	require.Equal(t, time.Unix(1000, 0), c.recent[0].arrival)
+
+	// blocks that weren't announced are observed when they're imported
+	h2 := newTestHeader(h1.Hash(), 2, 1001)
+	c.blockImported(h2)
+	require.Len(t, c.recent, 2)
+	require.Equal(t, now, c.recent[1].arrival)
+
+	// unless they're too far behind the current slot, ie. they're being synced
+	h3 := newTestHeader(common.Hash{}, 3, 1001-maxSlotLag-1)
+	c.blockImported(h3)
+	require.Len(t, c.recent, 2)
+	require.Equal(t, uint64(2), c.Stats().Blocks)
+}
@@ -33,17 +33,19 @@ type Hash = common.Hash
 
 // BlockTree represents the current state with all possible blocks
 type BlockTree struct {
-	head   *node // genesis node
-	leaves *leafMap
-	db     database.Database
+	head     *node // genesis node
+	leaves   *leafMap
+	db       database.Database
+	steering *steering
 }
 
 // NewEmptyBlockTree creates a BlockTree with a nil head
 func NewEmptyBlockTree(db database.Database) *BlockTree {
 	return &BlockTree{
-		head:   nil,
-		leaves: newEmptyLeafMap(),
-		db:     db,
+		head:     nil,
+		leaves:   newEmptyLeafMap(),
+		db:       db,
+		steering: newSteering(),
 	}
 }
 
@@ -59,17 +61,19 @@ func NewBlockTreeFromGenesis(genesis *types.Header, db database.Database) *Block
 	}
 
 	return &BlockTree{
-		head:   head,
-		leaves: newLeafMap(head),
-		db:     db,
+		head:     head,
+		leaves:   newLeafMap(head),
+		db:       db,
+		steering: newSteering(),
 	}
 }
 
 func newBlockTreeFromNode(head *node, db database.Database) *BlockTree {
 	return &BlockTree{
-		head:   head,
-		leaves: newLeafMap(head),
-		db:     db,
+		head:     head,
+		leaves:   newLeafMap(head),
+		db:       db,
+		steering: newSteering(),
 	}
 }
 
@@ -86,6 +90,10 @@ func (bt *BlockTree) AddBlock(block *types.Block, arrivalTime uint64) error {
 		return ErrParentNotFound
 	}
 
+	if bt.steering.isBadHash(block.Header.Hash()) || bt.steering.isBad(parent) {
+		return ErrBadBlock
+	}
+
 	// Check if it already exists
 	n := bt.getNode(block.Header.Hash())
 	if n != nil {
@@ -104,6 +112,7 @@ func (bt *BlockTree) AddBlock(block *types.Block, arrivalTime uint64) error {
 	}
 	parent.addChild(n)
 	bt.leaves.replace(parent, n)
+	bt.steering.advanceForcedHead(n)
 
 	return nil
 }
@@ -159,6 +168,7 @@ func (bt *BlockTree) Prune(newRoot Hash) (pruned []Hash) {
 
 	// set blocktree with new root node
 	next := newBlockTreeFromNode(n, bt.db)
+	next.steering = bt.steering
 	*bt = *next
 
 	return pruned
@@ -232,11 +242,19 @@ func (bt *BlockTree) deepestLeaf() *node { //nolint
 
 // DeepestBlockHash returns the hash of the deepest block in the blocktree
 // If there is multiple deepest blocks, it returns the one with the earliest arrival time.
+// Bad blocks are skipped, and if a head has been forced with ForceHead, it is returned instead.
 func (bt *BlockTree) DeepestBlockHash() Hash {
 	if bt.leaves == nil {
 		return Hash{}
 	}
 
+	if bt.steering.active() {
+		if n := bt.steeredDeepestBlock(); n != nil {
+			return n.hash
+		}
+		return Hash{}
+	}
+
 	if bt.leaves.deepestLeaf() == nil {
 		return Hash{}
 	}
@@ -259,7 +259,7 @@ func TestBlockTree_GetAllBlocksAtDepth(t *testing.T) {
 			Header: &types.Header{
 				ParentHash: previousHash,
 				Number:     big.NewInt(int64(i)),
-				Digest:     [][]byte{{9}},
+				Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{9}}),
 			},
 			Body: &types.Body{},
 		}
@@ -281,7 +281,7 @@ func TestBlockTree_GetAllBlocksAtDepth(t *testing.T) {
 			Header: &types.Header{
 				ParentHash: previousHash,
 				Number:     big.NewInt(int64(i)),
-				Digest:     [][]byte{{7}},
+				Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{7}}),
 			},
 			Body: &types.Body{},
 		}
@@ -379,3 +379,11 @@ func TestBlockTree_Prune(t *testing.T) {
 	require.ElementsMatch(t, expected, pruned)
 	require.Equal(t, bt.head, testNode)
 }
+
+func TestBlockTree_MemoryUsage(t *testing.T) {
+	small, _ := createFlatTree(t, 5)
+	bt, _ := createFlatTree(t, 10)
+
+	require.True(t, bt.MemoryUsage() >= 11*nodeSize+leafSize)
+	require.True(t, bt.MemoryUsage() > small.MemoryUsage())
+}
@@ -27,3 +27,9 @@ var ErrDescendantNotFound = errors.New("could not find descendant node")
 
 // ErrNodeNotFound is returned if a node with given hash doesn't exist
 var ErrNodeNotFound = errors.New("could not find node")
+
+// ErrBadBlock is returned if a block or one of its ancestors has been marked as bad
+var ErrBadBlock = errors.New("block has been marked as bad")
+
+// ErrBadRootBlock is returned when marking the root of the blocktree, which is finalized, as bad
+var ErrBadRootBlock = errors.New("cannot mark the finalized root block as bad")
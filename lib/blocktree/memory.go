@@ -0,0 +1,51 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package blocktree
+
+import (
+	"math/big"
+	"unsafe"
+)
+
+var (
+	nodeSize  = uint64(unsafe.Sizeof(node{}) + unsafe.Sizeof(big.Int{}))
+	leafSize  = uint64(unsafe.Sizeof(Hash{}) + unsafe.Sizeof(&node{}))
+	childSize = uint64(unsafe.Sizeof(&node{}))
+)
+
+// MemoryUsage returns the approximate number of bytes held by the nodes and leaves of the blocktree
+func (bt *BlockTree) MemoryUsage() uint64 {
+	return bt.head.memoryUsage() + uint64(len(bt.leaves.nodes()))*leafSize
+}
+
+// memoryUsage returns the approximate number of bytes held by the node and its descendants
+func (n *node) memoryUsage() uint64 {
+	if n == nil {
+		return 0
+	}
+
+	size := nodeSize + uint64(cap(n.children))*childSize
+	if n.depth != nil {
+		size += uint64(cap(n.depth.Bits())) * uint64(unsafe.Sizeof(big.Word(0)))
+	}
+
+	for _, child := range n.children {
+		size += child.memoryUsage()
+	}
+
+	return size
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package blocktree
+
+import (
+	"sync"
+)
+
+// steering holds the operator overrides of the fork choice rule: blocks that must never be part of
+// the best chain, and a block that the best chain must include
+type steering struct {
+	sync.RWMutex
+	bad  map[Hash]struct{}
+	head Hash
+}
+
+func newSteering() *steering {
+	return &steering{
+		bad: make(map[Hash]struct{}),
+	}
+}
+
+func (s *steering) active() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return len(s.bad) != 0 || s.head != (Hash{})
+}
+
+func (s *steering) isBadHash(hash Hash) bool {
+	s.RLock()
+	defer s.RUnlock()
+	_, has := s.bad[hash]
+	return has
+}
+
+// isBad returns true if the node or one of its ancestors has been marked as bad
+func (s *steering) isBad(n *node) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	if len(s.bad) == 0 {
+		return false
+	}
+
+	for curr := n; curr != nil; curr = curr.parent {
+		if _, has := s.bad[curr.hash]; has {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarkBad marks the block with the given hash as bad. Bad blocks and their descendants are never part
+// of the best chain, and are rejected by AddBlock. The block doesn't need to be in the blocktree.
+func (bt *BlockTree) MarkBad(hash Hash) error {
+	if hash == bt.head.hash {
+		return ErrBadRootBlock
+	}
+
+	bt.steering.Lock()
+	defer bt.steering.Unlock()
+	bt.steering.bad[hash] = struct{}{}
+	return nil
+}
+
+// IsBad returns true if the block with the given hash, or one of its ancestors, has been marked as bad
+func (bt *BlockTree) IsBad(hash Hash) bool {
+	if n := bt.getNode(hash); n != nil {
+		return bt.steering.isBad(n)
+	}
+
+	return bt.steering.isBadHash(hash)
+}
+
+// BadBlocks returns the hashes of the blocks that have been marked as bad
+func (bt *BlockTree) BadBlocks() []Hash {
+	bt.steering.RLock()
+	defer bt.steering.RUnlock()
+
+	hashes := make([]Hash, 0, len(bt.steering.bad))
+	for h := range bt.steering.bad {
+		hashes = append(hashes, h)
+	}
+
+	return hashes
+}
+
+// ForceHead sets the block with the given hash as the best block, until it is cleared by passing the zero hash.
+// The forced head moves to blocks that are added on top of it afterwards, existing descendants are ignored.
+func (bt *BlockTree) ForceHead(hash Hash) error {
+	if hash != (Hash{}) {
+		n := bt.getNode(hash)
+		if n == nil {
+			return ErrNodeNotFound
+		}
+
+		if bt.steering.isBad(n) {
+			return ErrBadBlock
+		}
+	}
+
+	bt.steering.Lock()
+	defer bt.steering.Unlock()
+	bt.steering.head = hash
+	return nil
+}
+
+// ForcedHead returns the hash of the block forced as the best block, or the zero hash if there is none
+func (bt *BlockTree) ForcedHead() Hash {
+	bt.steering.RLock()
+	defer bt.steering.RUnlock()
+	return bt.steering.head
+}
+
+// steeredDeepestBlock returns the forced head if there is one, otherwise the deepest block that isn't bad.
+// If there are multiple, it returns the one with the earliest arrival time.
+func (bt *BlockTree) steeredDeepestBlock() *node {
+	if head := bt.ForcedHead(); head != (Hash{}) {
+		// the forced head is ignored once it has been pruned or marked as bad
+		if forced := bt.getNode(head); forced != nil && !bt.steering.isBad(forced) {
+			return forced
+		}
+	}
+
+	var best *node
+	for _, leaf := range bt.leaves.nodes() {
+		// the best candidate on the leaf's branch is the parent of its earliest bad block
+		candidate := leaf
+		for curr := leaf; curr != nil; curr = curr.parent {
+			if bt.steering.isBadHash(curr.hash) {
+				candidate = curr.parent
+			}
+		}
+
+		if candidate == nil {
+			continue
+		}
+
+		if best == nil || best.depth.Cmp(candidate.depth) < 0 ||
+			(best.depth.Cmp(candidate.depth) == 0 && candidate.arrivalTime < best.arrivalTime) {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// advanceForcedHead moves the forced head to the given node if it is a child of the forced head, so that
+// the best chain follows blocks built on top of it
+func (s *steering) advanceForcedHead(n *node) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.head != (Hash{}) && n.parent != nil && n.parent.hash == s.head {
+		s.head = n.hash
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package blocktree
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createForkedTree returns a tree with a main chain of the given depth and a single block fork off
+// block 1 of it
+func createForkedTree(t *testing.T, depth int) (*BlockTree, []common.Hash, common.Hash) {
+	bt, hashes := createFlatTree(t, depth)
+
+	fork := &types.Block{
+		Header: &types.Header{
+			ParentHash: hashes[1],
+			Number:     big.NewInt(2),
+			Digest:     types.NewDigest(&types.OtherDigest{Data: []byte{1}}),
+		},
+		Body: &types.Body{},
+	}
+
+	err := bt.AddBlock(fork, 1)
+	require.NoError(t, err)
+	return bt, hashes, fork.Header.Hash()
+}
+
+func TestBlockTree_MarkBad(t *testing.T) {
+	bt, hashes, fork := createForkedTree(t, 4)
+	require.Equal(t, hashes[4], bt.DeepestBlockHash())
+
+	err := bt.MarkBad(hashes[0])
+	require.Equal(t, ErrBadRootBlock, err)
+
+	// the best chain falls back to the parent of the bad block
+	err = bt.MarkBad(hashes[3])
+	require.NoError(t, err)
+	require.Equal(t, hashes[2], bt.DeepestBlockHash())
+	require.True(t, bt.IsBad(hashes[4]))
+	require.False(t, bt.IsBad(hashes[2]))
+	require.Equal(t, []common.Hash{hashes[3]}, bt.BadBlocks())
+
+	// the fork is now deeper than the good part of the main chain
+	err = bt.MarkBad(hashes[2])
+	require.NoError(t, err)
+	require.Equal(t, fork, bt.DeepestBlockHash())
+
+	// descendants of bad blocks are rejected
+	err = bt.AddBlock(&types.Block{
+		Header: &types.Header{
+			ParentHash: hashes[4],
+			Number:     big.NewInt(5),
+		},
+		Body: &types.Body{},
+	}, 0)
+	require.Equal(t, ErrBadBlock, err)
+}
+
+func TestBlockTree_ForceHead(t *testing.T) {
+	bt, hashes, fork := createForkedTree(t, 4)
+
+	err := bt.ForceHead(common.Hash{1})
+	require.Equal(t, ErrNodeNotFound, err)
+
+	err = bt.ForceHead(fork)
+	require.NoError(t, err)
+	require.Equal(t, fork, bt.ForcedHead())
+	require.Equal(t, fork, bt.DeepestBlockHash())
+
+	// the chain keeps following the forced head as it grows
+	child := &types.Block{
+		Header: &types.Header{
+			ParentHash: fork,
+			Number:     big.NewInt(3),
+		},
+		Body: &types.Body{},
+	}
+	err = bt.AddBlock(child, 2)
+	require.NoError(t, err)
+	require.Equal(t, child.Header.Hash(), bt.DeepestBlockHash())
+	require.Equal(t, child.Header.Hash(), bt.ForcedHead())
+
+	err = bt.MarkBad(fork)
+	require.NoError(t, err)
+	require.Equal(t, hashes[4], bt.DeepestBlockHash())
+
+	err = bt.ForceHead(child.Header.Hash())
+	require.Equal(t, ErrBadBlock, err)
+
+	// existing descendants of a forced head are ignored
+	err = bt.ForceHead(hashes[2])
+	require.NoError(t, err)
+	require.Equal(t, hashes[2], bt.DeepestBlockHash())
+
+	err = bt.ForceHead(common.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{}, bt.ForcedHead())
+	require.Equal(t, hashes[4], bt.DeepestBlockHash())
+}
+
+func TestBlockTree_Prune_KeepsSteering(t *testing.T) {
+	bt, hashes, _ := createForkedTree(t, 4)
+
+	err := bt.MarkBad(hashes[4])
+	require.NoError(t, err)
+
+	bt.Prune(hashes[1])
+	require.Equal(t, hashes[3], bt.DeepestBlockHash())
+}
@@ -25,8 +25,24 @@ var (
 	FinalizedBlockHashKey = []byte("finalized_head")
 	// GenesisDataKey is the db location of the genesis data.
 	GenesisDataKey = []byte("genesis_data")
+	// GenesisPropertiesKey is the db location of the JSON encoded properties of the chain spec.
+	GenesisPropertiesKey = []byte("genesis_properties")
+	// CodeSubstitutesKey is the db location of the JSON encoded code substitutes of the chain spec.
+	CodeSubstitutesKey = []byte("code_substitutes")
+	// BlockRulesKey is the db location of the JSON encoded bad blocks and fork blocks of the chain spec.
+	BlockRulesKey = []byte("block_rules")
+	// LightSyncStateKey is the db location of the JSON encoded light sync state of the chain spec.
+	LightSyncStateKey = []byte("light_sync_state")
+	// TelemetryEndpointsKey is the db location of the JSON encoded telemetry endpoints of the chain spec.
+	TelemetryEndpointsKey = []byte("telemetry_endpoints")
 	// BlockTreeKey is the db location of the encoded block tree structure.
 	BlockTreeKey = []byte("block_tree")
 	// LatestFinalizedRoundKey is the key where the last finalized grandpa round is stored
 	LatestFinalizedRoundKey = []byte("latest_finalized_round")
+	// ExtrinsicIndexHeadKey is the db location of the hash of the last finalized block whose extrinsics are indexed.
+	ExtrinsicIndexHeadKey = []byte("extrinsic_index_head")
+	// DBVersionKey is the db location of the version of the database layout.
+	DBVersionKey = []byte("db_version")
+	// HasherKey is the db location of the name of the hash function used for trie and header hashing.
+	HasherKey = []byte("hasher")
 )
@@ -39,6 +39,21 @@ func Blake2b128(in []byte) ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
+// Blake2b8 returns the 64-bit blake2b hash of the input data
+func Blake2b8(in []byte) ([]byte, error) {
+	h, err := blake2b.New(8, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = h.Write(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
 // Blake2bHash returns the 256-bit blake2b hash of the input data
 func Blake2bHash(in []byte) (Hash, error) {
 	h, err := blake2b.New256(nil)
@@ -0,0 +1,98 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Names of the hash functions registered by default
+const (
+	Blake2bHasher = "blake2b"
+	KeccakHasher  = "keccak"
+	TwoxHasher    = "twox"
+)
+
+// ErrUnknownHasher is returned when a hash function is not registered
+var ErrUnknownHasher = errors.New("unknown hash function")
+
+// HashFunc is a function returning the 256-bit hash of the input data
+type HashFunc func(in []byte) (Hash, error)
+
+var (
+	hashers = map[string]HashFunc{
+		Blake2bHasher: Blake2bHash,
+		KeccakHasher:  Keccak256,
+		TwoxHasher:    Twox256,
+	}
+	defaultHasher = Blake2bHash
+	hashersLock   sync.RWMutex
+)
+
+// RegisterHasher registers a hash function under the given name, so that it can be selected by the chain configuration
+func RegisterHasher(name string, fn HashFunc) error {
+	hashersLock.Lock()
+	defer hashersLock.Unlock()
+
+	if _, has := hashers[name]; has {
+		return fmt.Errorf("hash function %s already registered", name)
+	}
+
+	hashers[name] = fn
+	return nil
+}
+
+// GetHasher returns the hash function registered under the given name
+func GetHasher(name string) (HashFunc, error) {
+	hashersLock.RLock()
+	defer hashersLock.RUnlock()
+
+	fn, has := hashers[name]
+	if !has {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHasher, name)
+	}
+
+	return fn, nil
+}
+
+// SetDefaultHasher sets the hash function used for header and extrinsic hashing. It defaults to blake2b. Tries are
+// given their hash function when they're created, see trie.NewEmptyTrieWithHasher.
+func SetDefaultHasher(name string) error {
+	fn, err := GetHasher(name)
+	if err != nil {
+		return err
+	}
+
+	hashersLock.Lock()
+	defer hashersLock.Unlock()
+	defaultHasher = fn
+	return nil
+}
+
+// DefaultHasher returns the hash function used for header and extrinsic hashing
+func DefaultHasher() HashFunc {
+	hashersLock.RLock()
+	defer hashersLock.RUnlock()
+	return defaultHasher
+}
+
+// DefaultHash returns the hash of the input data using the default hash function
+func DefaultHash(in []byte) (Hash, error) {
+	return DefaultHasher()(in)
+}
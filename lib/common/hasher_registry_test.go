@@ -0,0 +1,61 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package common_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasherRegistry(t *testing.T) {
+	in := []byte("noot")
+
+	fn, err := common.GetHasher(common.KeccakHasher)
+	require.NoError(t, err)
+	expected, err := common.Keccak256(in)
+	require.NoError(t, err)
+	res, err := fn(in)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	_, err = common.GetHasher("sha256")
+	require.True(t, errors.Is(err, common.ErrUnknownHasher))
+	require.Error(t, common.SetDefaultHasher("sha256"))
+
+	err = common.RegisterHasher(common.Blake2bHasher, common.Keccak256)
+	require.Error(t, err)
+
+	zero := func(in []byte) (common.Hash, error) {
+		return common.Hash{}, nil
+	}
+	err = common.RegisterHasher("zero", zero)
+	require.NoError(t, err)
+
+	err = common.SetDefaultHasher("zero")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, common.SetDefaultHasher(common.Blake2bHasher))
+	}()
+
+	res, err = common.DefaultHash(in)
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{}, res)
+}
@@ -39,3 +39,12 @@ type PeerInfo struct {
 	BestHash        Hash
 	BestNumber      uint64
 }
+
+// NodeIdentity is the operator supplied identity of a node, signed by the node's network key
+type NodeIdentity struct {
+	PeerID      string
+	DisplayName string
+	Location    string
+	Contact     string
+	Signature   []byte
+}
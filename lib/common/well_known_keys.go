@@ -3,6 +3,8 @@ package common
 var (
 	// CodeKey is the key where runtime code is stored in the trie
 	CodeKey = []byte(":code")
+	// HeapPagesKey is the key where the number of wasm heap pages for the runtime is stored in the trie
+	HeapPagesKey = []byte(":heappages")
 )
 
 // BalanceKey returns the storage trie key for the balance of the account with the given public key
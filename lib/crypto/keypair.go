@@ -17,6 +17,9 @@
 package crypto
 
 import (
+	"bytes"
+	"errors"
+
 	"github.com/ChainSafe/gossamer/lib/common"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -66,6 +69,12 @@ type PrivateKey interface {
 
 var ss58Prefix = []byte("SS58PRE")
 
+// ErrInvalidAddress is returned when an address isn't a valid ss58 address of a 32 byte public key
+var ErrInvalidAddress = errors.New("invalid ss58 address")
+
+// ErrInvalidAddressChecksum is returned when the checksum of an ss58 address doesn't match its contents
+var ErrInvalidAddressChecksum = errors.New("invalid ss58 address checksum")
+
 // PublicKeyToAddress returns an ss58 address given a PublicKey
 // see: https://github.com/paritytech/substrate/wiki/External-Address-Format-(SS58)
 // also see: https://github.com/paritytech/substrate/blob/master/primitives/core/src/crypto.rs#L275
@@ -83,6 +92,30 @@ func PublicKeyToAddress(pub PublicKey) common.Address {
 	return common.Address(base58.Encode(append(enc, checksum[:2]...)))
 }
 
+// ValidateAddress checks that the given address is an ss58 address of a 32 byte public key with a valid checksum
+func ValidateAddress(add common.Address) error {
+	k := base58.Decode(string(add))
+	if len(k) != 35 {
+		return ErrInvalidAddress
+	}
+
+	hasher, err := blake2b.New(64, nil)
+	if err != nil {
+		return err
+	}
+	_, err = hasher.Write(append(ss58Prefix, k[:33]...))
+	if err != nil {
+		return err
+	}
+
+	checksum := hasher.Sum(nil)
+	if !bytes.Equal(checksum[:2], k[33:]) {
+		return ErrInvalidAddressChecksum
+	}
+
+	return nil
+}
+
 // PublicAddressToByteArray returns []byte address for given PublicKey Address
 func PublicAddressToByteArray(add common.Address) []byte {
 	k := base58.Decode(string(add))
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 
 	"github.com/stretchr/testify/require"
@@ -19,3 +20,17 @@ func TestPubkeyToAddress(t *testing.T) {
 	a := pk.Address()
 	require.Equal(t, addr, string(a))
 }
+
+func TestValidateAddress(t *testing.T) {
+	err := crypto.ValidateAddress("5EZvvkH5RUjigUNT7pabMzMnHtmrYamsSe7yW6vVACBzTHFe")
+	require.NoError(t, err)
+
+	err = crypto.ValidateAddress("5EZvvkH5RUjigUNT7pabMzMnHtmrYamsSe7yW6vVACBzTHFf")
+	require.Equal(t, crypto.ErrInvalidAddressChecksum, err)
+
+	err = crypto.ValidateAddress("5EZvvkH5RUjigUNT7pab")
+	require.Equal(t, crypto.ErrInvalidAddress, err)
+
+	err = crypto.ValidateAddress("not an address")
+	require.Equal(t, crypto.ErrInvalidAddress, err)
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// ErrInvalidPublicKey is returned when an authority isn't a valid public key of the key type of its module
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// authorityKeyTypes are the key types of the authorities of each module
+var authorityKeyTypes = map[string]crypto.KeyType{
+	"babe":    crypto.Sr25519Type,
+	"grandpa": crypto.Ed25519Type,
+}
+
+// isHexKey returns true if the authority is a 0x prefixed hex encoded public key rather than an SS58 address
+func isHexKey(add common.Address) bool {
+	return strings.HasPrefix(string(add), "0x")
+}
+
+// authorityID returns the public key bytes of an authority, which is either an SS58 address or a 0x prefixed hex
+// encoded public key, as generated by other tooling
+func authorityID(add common.Address) ([]byte, error) {
+	if !isHexKey(add) {
+		return accountID(add)
+	}
+
+	id, err := common.HexToBytes(string(add))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", add, err)
+	}
+
+	if len(id) != 32 {
+		return nil, fmt.Errorf("%s: %w: expected 32 bytes, got %d", add, ErrInvalidPublicKey, len(id))
+	}
+
+	return id, nil
+}
+
+// authorityPublicKey returns the public key of an authority, checking that it's a valid key of the given type
+func authorityPublicKey(add common.Address, kt crypto.KeyType) (crypto.PublicKey, error) {
+	id, err := authorityID(add)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.PublicKey
+	switch kt {
+	case crypto.Sr25519Type:
+		key, err = sr25519.NewPublicKey(id)
+	case crypto.Ed25519Type:
+		key, err = ed25519.NewPublicKey(id)
+	default:
+		return nil, fmt.Errorf("%s: %w: unsupported key type %s", add, ErrInvalidPublicKey, kt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", add, ErrInvalidPublicKey, err)
+	}
+
+	// an sr25519 key that isn't a valid ristretto point doesn't encode back to the same bytes
+	if !bytes.Equal(key.Encode(), id) {
+		return nil, fmt.Errorf("%s: %w: not a valid %s key", add, ErrInvalidPublicKey, kt)
+	}
+
+	return key, nil
+}
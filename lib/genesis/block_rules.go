@@ -0,0 +1,130 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+var (
+	// ErrBadBlock is returned when importing a block listed in the badBlocks of the chain spec
+	ErrBadBlock = errors.New("block is a known bad block")
+	// ErrForkBlock is returned when importing a block at the number of a fork block of the chain spec, with a
+	// different hash
+	ErrForkBlock = errors.New("block doesn't match the fork block at its number")
+	// ErrInvalidBlockRule is returned when a bad block or fork block of the chain spec can't be parsed
+	ErrInvalidBlockRule = errors.New("bad blocks must be block hashes, fork blocks [number, hash] pairs")
+)
+
+// ForkBlock is a block the canonical chain must include, encoded as a [number, hash] pair in the chain spec
+type ForkBlock struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// MarshalJSON encodes the fork block as a [number, hash] pair
+func (f ForkBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{f.Number, f.Hash.String()})
+}
+
+// UnmarshalJSON decodes a [number, hash] pair
+func (f *ForkBlock) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	err := json.Unmarshal(data, &pair)
+	if err != nil || len(pair) != 2 {
+		return ErrInvalidBlockRule
+	}
+
+	var hash string
+	if err = json.Unmarshal(pair[0], &f.Number); err != nil {
+		return ErrInvalidBlockRule
+	}
+	if err = json.Unmarshal(pair[1], &hash); err != nil {
+		return ErrInvalidBlockRule
+	}
+
+	f.Hash, err = parseBlockHash(hash)
+	return err
+}
+
+// BlockRules holds the bad blocks and fork blocks of a chain spec, which are enforced when importing blocks
+type BlockRules struct {
+	badBlocks  map[common.Hash]struct{}
+	forkBlocks map[uint64]common.Hash
+}
+
+// NewBlockRules returns the BlockRules for the given hex encoded bad block hashes and fork blocks
+func NewBlockRules(badBlocks []string, forkBlocks []ForkBlock) (*BlockRules, error) {
+	r := &BlockRules{
+		badBlocks:  make(map[common.Hash]struct{}, len(badBlocks)),
+		forkBlocks: make(map[uint64]common.Hash, len(forkBlocks)),
+	}
+
+	for _, bb := range badBlocks {
+		hash, err := parseBlockHash(bb)
+		if err != nil {
+			return nil, err
+		}
+		r.badBlocks[hash] = struct{}{}
+	}
+
+	for _, fb := range forkBlocks {
+		r.forkBlocks[fb.Number] = fb.Hash
+	}
+
+	return r, nil
+}
+
+// BlockRules returns the rules for importing blocks set by the chain spec
+func (g *Genesis) BlockRules() (*BlockRules, error) {
+	return NewBlockRules(g.BadBlocks, g.ForkBlocks)
+}
+
+// Check returns an error if the block with the given header must not be imported. A nil BlockRules accepts all
+// blocks.
+func (r *BlockRules) Check(header *types.Header) error {
+	if r == nil {
+		return nil
+	}
+
+	hash := header.Hash()
+	if _, has := r.badBlocks[hash]; has {
+		return fmt.Errorf("%s: %w", hash, ErrBadBlock)
+	}
+
+	if header.Number.IsUint64() {
+		if expected, has := r.forkBlocks[header.Number.Uint64()]; has && expected != hash {
+			return fmt.Errorf("%s at block %s, expected %s: %w", hash, header.Number, expected, ErrForkBlock)
+		}
+	}
+
+	return nil
+}
+
+func parseBlockHash(in string) (common.Hash, error) {
+	h, err := common.HexToBytes(in)
+	if err != nil || len(h) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%s: %w", in, ErrInvalidBlockRule)
+	}
+
+	return common.NewHash(h), nil
+}
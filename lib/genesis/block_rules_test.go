@@ -0,0 +1,69 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesis_BlockRules(t *testing.T) {
+	bad := &types.Header{Number: big.NewInt(2), Digest: types.NewEmptyDigest()}
+	fork := &types.Header{Number: big.NewInt(3), Digest: types.NewEmptyDigest()}
+
+	data := []byte(`{"badBlocks":["` + bad.Hash().String() + `"],"forkBlocks":[[3,"` + fork.Hash().String() + `"]]}`)
+	gen := new(Genesis)
+	err := json.Unmarshal(data, gen)
+	require.NoError(t, err)
+	require.Equal(t, []ForkBlock{{Number: 3, Hash: fork.Hash()}}, gen.ForkBlocks)
+
+	rules, err := gen.BlockRules()
+	require.NoError(t, err)
+
+	require.True(t, errors.Is(rules.Check(bad), ErrBadBlock))
+	require.NoError(t, rules.Check(fork))
+
+	other := &types.Header{Number: big.NewInt(3), ParentHash: common.Hash{0x01}, Digest: types.NewEmptyDigest()}
+	require.True(t, errors.Is(rules.Check(other), ErrForkBlock))
+
+	// blocks at other numbers are accepted
+	require.NoError(t, rules.Check(&types.Header{Number: big.NewInt(4), Digest: types.NewEmptyDigest()}))
+
+	// a nil BlockRules accepts all blocks
+	require.NoError(t, (*BlockRules)(nil).Check(bad))
+
+	// fork blocks are exported as [number, hash] pairs
+	enc, err := json.Marshal(gen.ForkBlocks)
+	require.NoError(t, err)
+	require.Equal(t, `[[3,"`+fork.Hash().String()+`"]]`, string(enc))
+}
+
+func TestGenesis_BlockRules_Invalid(t *testing.T) {
+	gen := new(Genesis)
+	err := json.Unmarshal([]byte(`{"forkBlocks":[[3]]}`), gen)
+	require.True(t, errors.Is(err, ErrInvalidBlockRule))
+
+	gen = &Genesis{BadBlocks: []string{"0x01"}}
+	_, err = gen.BlockRules()
+	require.True(t, errors.Is(err, ErrInvalidBlockRule))
+}
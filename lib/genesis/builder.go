@@ -0,0 +1,171 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// Builder builds a raw genesis in code, without going through a JSON chain spec. The setters can be chained, eg.
+// NewBuilder().WithCode(code).WithBabeAuthorities(keys...).WithBalances(balances).Build()
+type Builder struct {
+	gen      *Genesis
+	code     []byte
+	babe     []*sr25519.PublicKey
+	grandpa  []*types.Authority
+	balances map[common.Address]*big.Int
+	sudo     *common.Address
+	storage  map[string][]byte
+}
+
+// NewBuilder returns a Builder for a genesis named "Development" with the id "dev"
+func NewBuilder() *Builder {
+	return &Builder{
+		gen: &Genesis{
+			Name: "Development",
+			ID:   "dev",
+		},
+		balances: make(map[common.Address]*big.Int),
+		storage:  make(map[string][]byte),
+	}
+}
+
+// WithName sets the name and id of the chain
+func (b *Builder) WithName(name, id string) *Builder {
+	b.gen.Name = name
+	b.gen.ID = id
+	return b
+}
+
+// WithProtocolID sets the network protocol id of the chain
+func (b *Builder) WithProtocolID(id string) *Builder {
+	b.gen.ProtocolID = id
+	return b
+}
+
+// WithBootnodes adds bootnode multiaddrs to the chain
+func (b *Builder) WithBootnodes(bootnodes ...string) *Builder {
+	b.gen.Bootnodes = append(b.gen.Bootnodes, bootnodes...)
+	return b
+}
+
+// WithProperties sets the chain properties, such as tokenSymbol, tokenDecimals and ss58Format
+func (b *Builder) WithProperties(properties map[string]interface{}) *Builder {
+	b.gen.Properties = properties
+	return b
+}
+
+// WithTelemetryEndpoints adds telemetry servers to the chain
+func (b *Builder) WithTelemetryEndpoints(endpoints ...*TelemetryEndpoint) *Builder {
+	b.gen.TelemetryEndpoints = append(b.gen.TelemetryEndpoints, endpoints...)
+	return b
+}
+
+// WithCode sets the runtime code
+func (b *Builder) WithCode(code []byte) *Builder {
+	b.code = code
+	return b
+}
+
+// WithBabeAuthorities sets the BABE authorities, each with a weight of 1
+func (b *Builder) WithBabeAuthorities(keys ...*sr25519.PublicKey) *Builder {
+	b.babe = keys
+	return b
+}
+
+// WithGrandpaAuthorities sets the GRANDPA authorities with their weights
+func (b *Builder) WithGrandpaAuthorities(auths ...*types.Authority) *Builder {
+	b.grandpa = auths
+	return b
+}
+
+// WithBalances adds free balances to the accounts, the total issuance is the sum of all the balances
+func (b *Builder) WithBalances(balances map[common.Address]*big.Int) *Builder {
+	for add, balance := range balances {
+		b.balances[add] = balance
+	}
+	return b
+}
+
+// WithSudo sets the sudo key
+func (b *Builder) WithSudo(add common.Address) *Builder {
+	b.sudo = &add
+	return b
+}
+
+// WithStorage sets a raw storage entry, it takes precedence over the entries set by the other setters
+func (b *Builder) WithStorage(key, value []byte) *Builder {
+	b.storage[common.BytesToHex(key)] = value
+	return b
+}
+
+// Build returns the raw genesis. It returns an error if a value can't be encoded, or if the genesis isn't valid,
+// see Genesis.Validate.
+func (b *Builder) Build() (*Genesis, error) {
+	gen := *b.gen
+	gen.Genesis = Fields{}
+
+	if b.code != nil {
+		gen.setRaw(common.BytesToHex(common.CodeKey), b.code)
+	}
+
+	if b.babe != nil {
+		err := gen.SetBabeAuthorities(b.babe)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if b.grandpa != nil {
+		err := gen.SetGrandpaAuthorities(b.grandpa)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(b.balances) > 0 {
+		err := gen.SetBalances(b.balances)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if b.sudo != nil {
+		err := gen.SetSudo(*b.sudo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range b.storage {
+		gen.setRaw(key, value)
+	}
+
+	// the human readable values written by the setters are dropped, the genesis only holds the raw storage
+	gen.Genesis.Runtime = nil
+
+	err := gen.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	babeKey, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	grandpaKey, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	code := []byte{0, 'a', 's', 'm'}
+	babe := []*sr25519.PublicKey{babeKey.Public().(*sr25519.PublicKey)}
+	grandpa := []*types.Authority{types.NewAuthority(grandpaKey.Public(), 1)}
+
+	gen, err := NewBuilder().
+		WithName("Test", "test").
+		WithProtocolID("/gossamer/test/0").
+		WithCode(code).
+		WithBabeAuthorities(babe...).
+		WithGrandpaAuthorities(grandpa...).
+		WithBalances(map[common.Address]*big.Int{alice: big.NewInt(1)}).
+		WithBalances(map[common.Address]*big.Int{alice: big.NewInt(2)}).
+		WithSudo(alice).
+		WithStorage([]byte("key"), []byte("value")).
+		Build()
+	require.NoError(t, err)
+
+	require.Equal(t, "Test", gen.Name)
+	require.Equal(t, "test", gen.ID)
+	require.Equal(t, "/gossamer/test/0", gen.ProtocolID)
+	require.Nil(t, gen.Genesis.Runtime)
+
+	// the builder writes the same storage as the genesis setters
+	expected := new(Genesis)
+	require.NoError(t, expected.SetBabeAuthorities(babe))
+	require.NoError(t, expected.SetGrandpaAuthorities(grandpa))
+	require.NoError(t, expected.SetBalances(map[common.Address]*big.Int{alice: big.NewInt(2)}))
+	require.NoError(t, expected.SetSudo(alice))
+	expected.setRaw(common.BytesToHex(common.CodeKey), code)
+	expected.setRaw(common.BytesToHex([]byte("key")), []byte("value"))
+	require.Equal(t, expected.Genesis.Raw[0], gen.Genesis.Raw[0])
+
+	// the genesis can be written as a raw chain spec and read back
+	data, err := gen.ToJSONRaw()
+	require.NoError(t, err)
+	decoded, err := NewGenesisFromJSONRawBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, gen.Genesis.Raw[0], decoded.Genesis.Raw[0])
+
+	_, err = NewTrieFromGenesis(gen)
+	require.NoError(t, err)
+}
+
+func TestBuilder_Reuse(t *testing.T) {
+	b := NewBuilder().WithCode([]byte{1})
+
+	first, err := b.Build()
+	require.NoError(t, err)
+
+	second, err := b.WithSudo(alice).Build()
+	require.NoError(t, err)
+
+	// building again doesn't change the genesis built before
+	require.Len(t, first.Genesis.Raw[0], 1)
+	require.Len(t, second.Genesis.Raw[0], 2)
+}
+
+func TestBuilder_Invalid(t *testing.T) {
+	// the runtime code is required
+	_, err := NewBuilder().Build()
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, []error{ErrMissingCode}, verr.Errors)
+
+	_, err = NewBuilder().
+		WithCode([]byte{1}).
+		WithBalances(map[common.Address]*big.Int{alice: big.NewInt(-1)}).
+		Build()
+	require.True(t, errors.Is(err, ErrInvalidBalance))
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// ErrInvalidCodeSubstitute is returned when a code substitute doesn't map a block hash to hex encoded code
+var ErrInvalidCodeSubstitute = errors.New("code substitute must map a block hash to hex encoded runtime code")
+
+// ParseCodeSubstitutes parses the codeSubstitutes of a chain spec, which map the hex encoded hash of a block to
+// the hex encoded runtime code that replaces the on-chain code from that block until the next runtime upgrade
+func ParseCodeSubstitutes(subs map[string]string) (map[common.Hash][]byte, error) {
+	res := make(map[common.Hash][]byte, len(subs))
+	for h, c := range subs {
+		hash, err := common.HexToBytes(h)
+		if err != nil || len(hash) != common.HashLength {
+			return nil, fmt.Errorf("block hash %s: %w", h, ErrInvalidCodeSubstitute)
+		}
+
+		code, err := common.HexToBytes(c)
+		if err != nil || len(code) == 0 {
+			return nil, fmt.Errorf("code of block %s: %w", h, ErrInvalidCodeSubstitute)
+		}
+
+		res[common.NewHash(hash)] = code
+	}
+
+	return res, nil
+}
+
+// CodeSubstitute returns the runtime code substituting the on-chain code at the block with the given hash, or nil
+// if the code of the block isn't substituted
+func (g *Genesis) CodeSubstitute(hash common.Hash) ([]byte, error) {
+	subs, err := ParseCodeSubstitutes(g.CodeSubstitutes)
+	if err != nil {
+		return nil, err
+	}
+
+	return subs[hash], nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeSubstitute(t *testing.T) {
+	hash := common.Hash{0x01}
+	gen := &Genesis{
+		CodeSubstitutes: map[string]string{
+			hash.String(): "0x0102",
+		},
+	}
+
+	code, err := gen.CodeSubstitute(hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, code)
+
+	code, err = gen.CodeSubstitute(common.Hash{0x02})
+	require.NoError(t, err)
+	require.Nil(t, code)
+}
+
+func TestParseCodeSubstitutes_Invalid(t *testing.T) {
+	for _, subs := range []map[string]string{
+		{"0x01": "0x0102"},
+		{"nothex": "0x0102"},
+		{common.Hash{0x01}.String(): "0x"},
+		{common.Hash{0x01}.String(): "0xzz"},
+	} {
+		_, err := ParseCodeSubstitutes(subs)
+		require.True(t, errors.Is(err, ErrInvalidCodeSubstitute))
+	}
+}
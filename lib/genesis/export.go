@@ -0,0 +1,107 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// rawStorage is the raw genesis storage in the format used by Substrate chain specs
+type rawStorage struct {
+	Top             map[string]string            `json:"top"`
+	ChildrenDefault map[string]map[string]string `json:"childrenDefault"`
+}
+
+// rawChainSpec is a raw chain spec in the format used by Substrate
+type rawChainSpec struct {
+	Name               string               `json:"name"`
+	ID                 string               `json:"id"`
+	Bootnodes          []string             `json:"bootNodes"`
+	TelemetryEndpoints []*TelemetryEndpoint `json:"telemetryEndpoints"`
+	ProtocolID         string               `json:"protocolId"`
+	Genesis            struct {
+		Raw rawStorage `json:"raw"`
+	} `json:"genesis"`
+	Properties      map[string]interface{} `json:"properties,omitempty"`
+	CodeSubstitutes map[string]string      `json:"codeSubstitutes,omitempty"`
+	BadBlocks       []string               `json:"badBlocks,omitempty"`
+	ForkBlocks      []ForkBlock            `json:"forkBlocks,omitempty"`
+	LightSyncState  *LightSyncState        `json:"lightSyncState,omitempty"`
+}
+
+// ToJSONRaw returns the genesis as a raw chain spec in the format used by Substrate, with the storage in raw.top.
+// The raw storage is built from the human-readable runtime fields if the genesis doesn't have it yet.
+func (g *Genesis) ToJSONRaw() ([]byte, error) {
+	top := g.Genesis.Raw[0]
+	if len(top) == 0 && len(g.Genesis.Runtime) > 0 {
+		var err error
+		top, err = buildRawMap(g.Genesis.Runtime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if top == nil {
+		top = make(map[string]string)
+	}
+
+	bootnodes := g.Bootnodes
+	if bootnodes == nil {
+		bootnodes = []string{}
+	}
+
+	spec := &rawChainSpec{
+		Name:               g.Name,
+		ID:                 g.ID,
+		Bootnodes:          bootnodes,
+		TelemetryEndpoints: g.TelemetryEndpoints,
+		ProtocolID:         g.ProtocolID,
+		Properties:         g.Properties,
+		CodeSubstitutes:    g.CodeSubstitutes,
+		BadBlocks:          g.BadBlocks,
+		ForkBlocks:         g.ForkBlocks,
+		LightSyncState:     g.LightSyncState,
+	}
+	children := g.Genesis.ChildrenDefault
+	if children == nil {
+		children = make(map[string]map[string]string)
+	}
+
+	spec.Genesis.Raw = rawStorage{
+		Top:             top,
+		ChildrenDefault: children,
+	}
+
+	return json.MarshalIndent(spec, "", "    ")
+}
+
+// ExportRawGenesis writes the genesis to the file at path as a raw chain spec that Substrate nodes can consume
+func ExportRawGenesis(g *Genesis, path string) error {
+	data, err := g.ToJSONRaw()
+	if err != nil {
+		return err
+	}
+
+	fp, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Clean(fp), data, 0600)
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRawGenesis(t *testing.T) {
+	file, err := CreateTestGenesisJSONFile(false)
+	require.NoError(t, err)
+	defer os.Remove(file)
+
+	gen, err := NewGenesisFromJSON(file, 0)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "gossamer-test-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "genesis-raw.json")
+	err = ExportRawGenesis(gen, path)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	// the raw storage is written in the substrate format
+	spec := new(rawChainSpec)
+	err = json.Unmarshal(data, spec)
+	require.NoError(t, err)
+	require.Equal(t, gen.Genesis.Raw[0], spec.Genesis.Raw.Top)
+	require.NotNil(t, spec.Genesis.Raw.ChildrenDefault)
+
+	res, err := NewGenesisFromJSONRaw(path)
+	require.NoError(t, err)
+	require.Equal(t, gen.Name, res.Name)
+	require.Equal(t, gen.ID, res.ID)
+	require.Equal(t, gen.Genesis.Raw[0], res.Genesis.Raw[0])
+}
+
+func TestFields_UnmarshalJSON(t *testing.T) {
+	expected := map[string]string{"0x3a636f6465": "0x0102"}
+
+	f := new(Fields)
+	err := json.Unmarshal([]byte(`{"raw":{"top":{"0x3a636f6465":"0x0102"},"childrenDefault":{}}}`), f)
+	require.NoError(t, err)
+	require.Equal(t, expected, f.Raw[0])
+
+	f = new(Fields)
+	err = json.Unmarshal([]byte(`{"raw":{"top":{},"childrenDefault":{"0x64656661756c74":{"0x6b6579":"0x76616c7565"}}}}`), f)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{}, f.Raw[0])
+	require.Equal(t, map[string]map[string]string{"0x64656661756c74": {"0x6b6579": "0x76616c7565"}}, f.ChildrenDefault)
+
+	f = new(Fields)
+	err = json.Unmarshal([]byte(`{"raw":[{"0x3a636f6465":"0x0102"},{}]}`), f)
+	require.NoError(t, err)
+	require.Equal(t, expected, f.Raw[0])
+
+	f = new(Fields)
+	err = json.Unmarshal([]byte(`{"runtime":{"system":{"code":"0x0102"}}}`), f)
+	require.NoError(t, err)
+	require.Nil(t, f.Raw[0])
+	require.Equal(t, "0x0102", f.Runtime["system"]["code"])
+}
+
+func TestExportRawGenesis_ChildrenDefault(t *testing.T) {
+	gen := &Genesis{
+		Name: "test",
+		Genesis: Fields{
+			Raw: [2]map[string]string{
+				0: {"0x3a636f6465": "0x0102"},
+			},
+			ChildrenDefault: map[string]map[string]string{
+				"0x64656661756c74": {"0x6b6579": "0x76616c7565"},
+			},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "gossamer-test-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "genesis-raw.json")
+	err = ExportRawGenesis(gen, path)
+	require.NoError(t, err)
+
+	res, err := NewGenesisFromJSONRaw(path)
+	require.NoError(t, err)
+	require.Equal(t, gen.Genesis.ChildrenDefault, res.Genesis.ChildrenDefault)
+
+	// the child trie is rooted into the main trie
+	tr, err := NewTrieFromGenesis(res)
+	require.NoError(t, err)
+
+	value, err := tr.GetFromChild([]byte("default"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+
+	child, err := tr.GetChild([]byte("default"))
+	require.NoError(t, err)
+	childHash, err := child.Hash()
+	require.NoError(t, err)
+
+	stored, err := tr.Get(append(append([]byte{}, trie.ChildStorageKeyPrefix...), []byte("default")...))
+	require.NoError(t, err)
+	require.Equal(t, childHash[:], stored)
+}
@@ -17,6 +17,9 @@
 package genesis
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"github.com/ChainSafe/gossamer/lib/common"
 )
 
@@ -27,6 +30,32 @@ type Genesis struct {
 	Bootnodes  []string `json:"bootNodes"`
 	ProtocolID string   `json:"protocolId"`
 	Genesis    Fields   `json:"genesis"`
+
+	// Properties holds the chain properties used to format values, such as tokenSymbol, tokenDecimals and ss58Format
+	Properties map[string]interface{} `json:"properties,omitempty"`
+
+	// CodeSubstitutes maps the hex encoded hashes of blocks to the hex encoded runtime code replacing their on-chain code
+	CodeSubstitutes map[string]string `json:"codeSubstitutes,omitempty"`
+
+	// BadBlocks are the hex encoded hashes of blocks that are never imported
+	BadBlocks []string `json:"badBlocks,omitempty"`
+	// ForkBlocks are the blocks the chain must include at their numbers
+	ForkBlocks []ForkBlock `json:"forkBlocks,omitempty"`
+
+	// LightSyncState is a checkpoint of the chain that nodes can start syncing from
+	LightSyncState *LightSyncState `json:"lightSyncState,omitempty"`
+
+	// TelemetryEndpoints are the telemetry servers the node reports to, unless configured otherwise
+	TelemetryEndpoints []*TelemetryEndpoint `json:"telemetryEndpoints,omitempty"`
+}
+
+// LightSyncState is a checkpoint of the chain at a finalized block, with the hex encoded finalized block header,
+// BABE epoch changes and GRANDPA authority set at that block, SCALE encoded as they're encoded by Substrate
+type LightSyncState struct {
+	FinalizedBlockHeader     string `json:"finalizedBlockHeader"`
+	BabeEpochChanges         string `json:"babeEpochChanges"`
+	BabeFinalizedBlockWeight uint32 `json:"babeFinalizedBlockWeight"`
+	GrandpaAuthoritySet      string `json:"grandpaAuthoritySet"`
 }
 
 // Data defines the genesis file data formatted for trie storage
@@ -41,6 +70,47 @@ type Data struct {
 type Fields struct {
 	Raw     [2]map[string]string              `json:"raw"`
 	Runtime map[string]map[string]interface{} `json:"runtime,omitempty"`
+
+	// ChildrenDefault holds the storage of the default child tries, keyed by the child trie key without the
+	// child storage prefix. It's only read from and written to the "childrenDefault" of the raw storage object.
+	ChildrenDefault map[string]map[string]string `json:"-"`
+}
+
+// UnmarshalJSON decodes the genesis fields. The raw storage may either be a [top, children] array, or an object
+// with the top level storage in "top" and the child storage in "childrenDefault" as written by Substrate.
+func (f *Fields) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Raw     json.RawMessage                   `json:"raw"`
+		Runtime map[string]map[string]interface{} `json:"runtime"`
+	}{}
+
+	err := json.Unmarshal(data, aux)
+	if err != nil {
+		return err
+	}
+
+	f.Runtime = aux.Runtime
+	f.Raw = [2]map[string]string{}
+	f.ChildrenDefault = nil
+
+	raw := bytes.TrimSpace(aux.Raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	if raw[0] != '{' {
+		return json.Unmarshal(raw, &f.Raw)
+	}
+
+	storage := new(rawStorage)
+	err = json.Unmarshal(raw, storage)
+	if err != nil {
+		return err
+	}
+
+	f.Raw[0] = storage.Top
+	f.ChildrenDefault = storage.ChildrenDefault
+	return nil
 }
 
 // GenesisData formats genesis for trie storage
@@ -37,13 +37,34 @@ import (
 	"github.com/ChainSafe/gossamer/lib/trie"
 )
 
-// NewGenesisFromJSONRaw parses a JSON formatted genesis-raw file
+// NewGenesisFromJSONRaw parses a JSON formatted genesis-raw file, use NewGenesisFromURL for remote files
 func NewGenesisFromJSONRaw(file string) (*Genesis, error) {
+	data, err := readGenesisFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenesisFromJSONRawBytes(data)
+}
+
+// readGenesisFile reads the genesis file at the given path. Remote files can't be verified without a checksum,
+// so URLs are rejected.
+func readGenesisFile(file string) ([]byte, error) {
+	if IsURL(file) {
+		return nil, ErrMissingChecksum
+	}
+
 	fp, err := filepath.Abs(file)
 	if err != nil {
 		return nil, err
 	}
-	data, err := ioutil.ReadFile(filepath.Clean(fp))
+
+	return ioutil.ReadFile(filepath.Clean(fp))
+}
+
+// NewGenesisFromJSONRawBytes parses JSON formatted genesis-raw data, which may be gzip compressed
+func NewGenesisFromJSONRawBytes(data []byte) (*Genesis, error) {
+	data, err := decompress(data)
 	if err != nil {
 		return nil, err
 	}
@@ -53,9 +74,15 @@ func NewGenesisFromJSONRaw(file string) (*Genesis, error) {
 	return g, err
 }
 
-// NewTrieFromGenesis creates a new trie from the raw genesis data
+// NewTrieFromGenesis creates a new trie from the raw genesis data, whose nodes are hashed with blake2b
 func NewTrieFromGenesis(g *Genesis) (*trie.Trie, error) {
-	t := trie.NewEmptyTrie()
+	return NewTrieFromGenesisWithHasher(g, common.Blake2bHash)
+}
+
+// NewTrieFromGenesisWithHasher creates a new trie from the raw genesis data, whose nodes are hashed with the given
+// hash function
+func NewTrieFromGenesisWithHasher(g *Genesis, hash common.HashFunc) (*trie.Trie, error) {
+	t := trie.NewEmptyTrieWithHasher(hash)
 
 	r := g.GenesisFields().Raw[0]
 
@@ -64,6 +91,11 @@ func NewTrieFromGenesis(g *Genesis) (*trie.Trie, error) {
 		return nil, fmt.Errorf("failed to create trie from genesis: %s", err)
 	}
 
+	err = t.LoadChildren(g.GenesisFields().ChildrenDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create child tries from genesis: %s", err)
+	}
+
 	return t, nil
 }
 
@@ -82,7 +114,7 @@ func NewGenesisBlockFromTrie(t *trie.Trie) (*types.Header, error) {
 		big.NewInt(0),             // number
 		stateRoot,                 // stateRoot
 		trie.EmptyHash,            // extrinsicsRoot
-		[][]byte{},                // digest
+		types.NewEmptyDigest(),    // digest
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create genesis block header: %s", err)
@@ -91,6 +123,22 @@ func NewGenesisBlockFromTrie(t *trie.Trie) (*types.Header, error) {
 	return header, nil
 }
 
+// GenesisHash returns the hash of the genesis block that is built from the raw storage of the genesis, using the
+// given hash function for the state trie. The header itself is hashed with the default hasher.
+func GenesisHash(g *Genesis, hash common.HashFunc) (common.Hash, error) {
+	t, err := NewTrieFromGenesisWithHasher(g, hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	header, err := NewGenesisBlockFromTrie(t)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return header.Hash(), nil
+}
+
 // NewLegacyRuntimeFromGenesis creates a runtime instance from the genesis data
 func NewLegacyRuntimeFromGenesis(g *Genesis, storage runtime.Storage) (runtime.LegacyInstance, error) {
 	codeStr := g.GenesisFields().Raw[0][common.BytesToHex(common.CodeKey)]
@@ -125,32 +173,53 @@ func trimGenesisAuthority(g *Genesis, authCount int) {
 	}
 }
 
-// NewGenesisFromJSON parses Human Readable JSON formatted genesis file.Name. If authCount > 0,
-// then it keeps only `authCount` number of authorities for babe and grandpa.
+// NewGenesisFromJSON parses Human Readable JSON formatted genesis file.Name
+// If authCount > 0, then it keeps only `authCount` number of authorities for babe and grandpa.
 func NewGenesisFromJSON(file string, authCount int) (*Genesis, error) {
-	fp, err := filepath.Abs(file)
+	data, err := readGenesisFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := ioutil.ReadFile(filepath.Clean(fp))
+	return NewGenesisFromJSONBytes(data, authCount)
+}
+
+// NewGenesisFromJSONBytes parses Human Readable JSON formatted genesis data, which may be gzip compressed. If
+// authCount > 0, then it keeps only `authCount` number of authorities for babe and grandpa.
+func NewGenesisFromJSONBytes(data []byte, authCount int) (*Genesis, error) {
+	data, err := decompress(data)
 	if err != nil {
 		return nil, err
 	}
 
 	g := new(Genesis)
-
 	err = json.Unmarshal(data, g)
 	if err != nil {
 		return nil, err
 	}
 
+	// the typed modules are decoded from the data directly so that balances keep their precision
+	var spec struct {
+		Genesis struct {
+			Runtime *RuntimeModules `json:"runtime"`
+		} `json:"genesis"`
+	}
+	err = json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := spec.Genesis.Runtime
+	if modules == nil {
+		modules = new(RuntimeModules)
+	}
+
 	if authCount > 0 {
 		trimGenesisAuthority(g, authCount)
+		modules.trimAuthorities(authCount)
 	}
 
-	grt := g.Genesis.Runtime
-	res, err := buildRawMap(grt)
+	res, err := buildRawStorage(g.Genesis.Runtime, modules)
 	if err != nil {
 		return nil, err
 	}
@@ -167,12 +236,31 @@ type keyValue struct {
 	valueLen *big.Int
 }
 
+// buildRawMap builds the raw storage of the human readable runtime modules
 func buildRawMap(m map[string]map[string]interface{}) (map[string]string, error) {
+	modules, err := decodeRuntimeModules(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildRawStorage(m, modules)
+}
+
+// buildRawStorage builds the raw storage of the runtime modules, the typed modules are encoded field by field and the
+// others are encoded from the untyped map
+func buildRawStorage(m map[string]map[string]interface{}, modules *RuntimeModules) (map[string]string, error) {
 	res := make(map[string]string)
 	for k, v := range m {
+		if typedModules[k] {
+			continue
+		}
+
 		kv := new(keyValue)
 		kv.key = append(kv.key, k)
-		buildRawMapInterface(v, kv)
+		err := buildRawMapInterface(v, kv)
+		if err != nil {
+			return nil, err
+		}
 
 		key, err := formatKey(kv.key)
 		if err != nil {
@@ -185,39 +273,59 @@ func buildRawMap(m map[string]map[string]interface{}) (map[string]string, error)
 		}
 		res[key] = value
 	}
+
+	typed, err := modules.Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range typed {
+		res[k] = v
+	}
+
 	return res, nil
 }
 
-func buildRawMapInterface(m map[string]interface{}, kv *keyValue) {
+func buildRawMapInterface(m map[string]interface{}, kv *keyValue) error {
 	for k, v := range m {
 		kv.key = append(kv.key, k)
 		switch v2 := v.(type) {
 		case []interface{}:
 			kv.valueLen = big.NewInt(int64(len(v2)))
-			buildRawArrayInterface(v2, kv)
+			err := buildRawArrayInterface(v2, kv)
+			if err != nil {
+				return fmt.Errorf("cannot format %s: %w", strings.Join(kv.key, " "), err)
+			}
 		case string:
 			kv.value = v2
 		}
 	}
+	return nil
 }
 
-func buildRawArrayInterface(a []interface{}, kv *keyValue) {
+func buildRawArrayInterface(a []interface{}, kv *keyValue) error {
 	for _, v := range a {
 		switch v2 := v.(type) {
 		case []interface{}:
-			buildRawArrayInterface(v2, kv)
+			err := buildRawArrayInterface(v2, kv)
+			if err != nil {
+				return err
+			}
 		case string:
-			// todo check to confirm it's an address
-			tba := crypto.PublicAddressToByteArray(common.Address(v2))
+			tba, err := authorityID(common.Address(v2))
+			if err != nil {
+				return err
+			}
 			kv.value = kv.value + fmt.Sprintf("%x", tba)
 		case float64:
 			encVal, err := scale.Encode(uint64(v2))
 			if err != nil {
-				//todo determine how to handle this error
+				return err
 			}
 			kv.value = kv.value + fmt.Sprintf("%x", encVal)
 		}
 	}
+	return nil
 }
 
 func formatKey(key []string) (string, error) {
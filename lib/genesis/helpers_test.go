@@ -75,8 +75,8 @@ func TestNewGenesisFromJSON(t *testing.T) {
 	expRaw := [2]map[string]string{}
 	expRaw[0] = make(map[string]string)
 	expRaw[0]["0x3a636f6465"] = "0xfoo"                                                                                                                // raw system code entry
-	expRaw[0]["0x3a6772616e6470615f617574686f726974696573"] = "0x010834602b88f60513f1c805d87ef52896934baf6a662bc37414dbdbf69356b1a6910000000000000000" // raw grandpa authorities
-	expRaw[0]["0x886726f904d8372fdabb7707870c2fad"] = "0x08d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d0100000000000000"           // raw babe authorities
+	expRaw[0]["0x3a6772616e6470615f617574686f726974696573"] = "0x010434602b88f60513f1c805d87ef52896934baf6a662bc37414dbdbf69356b1a6910000000000000000" // raw grandpa authorities
+	expRaw[0]["0x886726f904d8372fdabb7707870c2fad"] = "0x04d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d0100000000000000"           // raw babe authorities
 	expectedGenesis.Genesis = Fields{
 		Raw: expRaw,
 	}
@@ -113,3 +113,21 @@ func TestNewGenesisFromJSON(t *testing.T) {
 
 	require.Equal(t, expectedGenesis.Genesis.Raw, testGenesisProcessed.Genesis.Raw)
 }
+
+func TestNewGenesisFromJSONBytes_InvalidAuthority(t *testing.T) {
+	testGenesis := &Genesis{
+		Genesis: Fields{
+			Runtime: map[string]map[string]interface{}{
+				"babe": {
+					"authorities": []interface{}{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ", 1},
+				},
+			},
+		},
+	}
+
+	bz, err := json.Marshal(testGenesis)
+	require.NoError(t, err)
+
+	_, err = NewGenesisFromJSONBytes(bz, 0)
+	require.Error(t, err)
+}
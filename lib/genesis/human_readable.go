@@ -0,0 +1,150 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+)
+
+// ToHumanReadable returns a copy of the raw genesis with the runtime values that can be recovered from the known
+// module keys of its raw storage: the runtime code, the BABE and GRANDPA authorities, the free balances of the
+// System Account map and the sudo key. Other storage entries can't be reversed and are left out. The runtime values
+// are in the format of RuntimeModules, so the human readable genesis builds back the raw entries it was read from.
+func ToHumanReadable(raw *Genesis) (*Genesis, error) {
+	gen := *raw
+	gen.Genesis = Fields{
+		Runtime: make(map[string]map[string]interface{}),
+	}
+
+	entries, err := rawEntries(raw.Genesis.Raw[0])
+	if err != nil {
+		return nil, err
+	}
+
+	err = BuildFromMap(entries, &gen)
+	if err != nil {
+		return nil, err
+	}
+
+	// BuildFromMap also sets the raw values it recognises, only the runtime values are kept
+	gen.Genesis.Raw = [2]map[string]string{}
+
+	balances, err := readBalances(entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) > 0 {
+		gen.setRuntime("balances", "balances", balances)
+	}
+
+	sudo, err := storagePrefix("Sudo", "Key")
+	if err != nil {
+		return nil, err
+	}
+	if id, has := entries[string(sudo)]; has {
+		add, err := bytesToAddress(crypto.Sr25519Type, id)
+		if err != nil {
+			return nil, err
+		}
+		gen.setRuntime("sudo", "key", add)
+	}
+
+	return &gen, nil
+}
+
+// rawEntries returns the decoded keys and values of the hex encoded raw storage
+func rawEntries(raw map[string]string) (map[string][]byte, error) {
+	entries := make(map[string][]byte, len(raw))
+	for k, v := range raw {
+		key, err := common.HexToBytes(k)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := common.HexToBytes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[string(key)] = value
+	}
+
+	return entries, nil
+}
+
+// readBalances returns the address and free balance of every account in the System Account map, sorted by address
+func readBalances(entries map[string][]byte) ([]*AccountBalance, error) {
+	prefix, err := storagePrefix("System", "Account")
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []*AccountBalance
+	for k, v := range entries {
+		key := []byte(k)
+
+		// the key is the prefix, the blake2_128 hash of the account id, then the account id
+		if len(key) != len(prefix)+16+32 || !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+
+		id := key[len(prefix)+16:]
+		h, err := common.Blake2b128(id)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(h, key[len(prefix):len(prefix)+16]) {
+			continue
+		}
+
+		// the account data, with the free balance first, ends the account info whatever the runtime's nonce and
+		// reference count encodings are
+		if len(v) < 16*4 {
+			continue
+		}
+
+		add, err := bytesToAddress(crypto.Sr25519Type, id)
+		if err != nil {
+			return nil, err
+		}
+
+		free := v[len(v)-16*4 : len(v)-16*3]
+		balances = append(balances, &AccountBalance{
+			Address: add,
+			Balance: decodeU128(free),
+		})
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Address < balances[j].Address
+	})
+	return balances, nil
+}
+
+// decodeU128 returns the value of a little endian encoded 128 bit integer
+func decodeU128(enc []byte) *big.Int {
+	b := make([]byte, len(enc))
+	for i := range enc {
+		b[i] = enc[len(enc)-1-i]
+	}
+	return new(big.Int).SetBytes(b)
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHumanReadable(t *testing.T) {
+	expected, err := NewGenesisFromJSON("../../chain/gssmr/genesis.json", 0)
+	require.NoError(t, err)
+
+	hr, err := ToHumanReadable(expected)
+	require.NoError(t, err)
+	require.Equal(t, expected.Name, hr.Name)
+	require.Empty(t, hr.Genesis.Raw[0])
+
+	for _, module := range []string{"babe", "grandpa", "system"} {
+		exp, err := json.Marshal(expected.Genesis.Runtime[module])
+		require.NoError(t, err)
+		res, err := json.Marshal(hr.Genesis.Runtime[module])
+		require.NoError(t, err)
+		require.JSONEq(t, string(exp), string(res), module)
+	}
+
+	// the raw genesis isn't modified
+	require.NotEmpty(t, expected.Genesis.Raw[0])
+}
+
+func TestToHumanReadable_BalancesAndSudo(t *testing.T) {
+	gen := new(Genesis)
+	balance := new(big.Int).Lsh(big.NewInt(1), 100)
+	err := gen.SetBalances(map[common.Address]*big.Int{
+		alice: balance,
+		bob:   big.NewInt(1),
+	})
+	require.NoError(t, err)
+	err = gen.SetSudo(alice)
+	require.NoError(t, err)
+
+	hr, err := ToHumanReadable(gen)
+	require.NoError(t, err)
+
+	expected := []*AccountBalance{
+		{Address: bob, Balance: big.NewInt(1)},
+		{Address: alice, Balance: balance},
+	}
+	require.Equal(t, expected, hr.Genesis.Runtime["balances"]["balances"])
+	require.Equal(t, alice, hr.Genesis.Runtime["sudo"]["key"])
+}
+
+func TestToHumanReadable_RoundTrip(t *testing.T) {
+	babeKey, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	grandpaKey, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	raw := new(Genesis)
+	require.NoError(t, raw.SetBabeAuthorities([]*sr25519.PublicKey{babeKey.Public().(*sr25519.PublicKey)}))
+	require.NoError(t, raw.SetGrandpaAuthorities([]*types.Authority{types.NewAuthority(grandpaKey.Public(), 1)}))
+	require.NoError(t, raw.SetBalances(map[common.Address]*big.Int{
+		alice: new(big.Int).Lsh(big.NewInt(1), 100),
+		bob:   big.NewInt(1),
+	}))
+	require.NoError(t, raw.SetSudo(alice))
+	raw.setRaw(common.BytesToHex(common.CodeKey), []byte{0, 'a', 's', 'm'})
+
+	hr, err := ToHumanReadable(raw)
+	require.NoError(t, err)
+
+	// the human readable runtime values build back the raw storage, both directly and through the JSON chain spec
+	res, err := buildRawMap(hr.Genesis.Runtime)
+	require.NoError(t, err)
+	require.Equal(t, raw.Genesis.Raw[0], res)
+
+	data, err := json.Marshal(hr)
+	require.NoError(t, err)
+	decoded, err := NewGenesisFromJSONBytes(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, raw.Genesis.Raw[0], decoded.Genesis.Raw[0])
+}
@@ -0,0 +1,459 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// ErrInvalidLightSyncState is returned when the light sync state of a chain spec can't be decoded
+var ErrInvalidLightSyncState = errors.New("invalid light sync state")
+
+// maxLightSyncStateItems bounds the length of the collections decoded from a light sync state
+const maxLightSyncStateItems = 1 << 16
+
+// BabeEpoch is a BABE epoch as it's stored in the epoch changes of a light sync state
+type BabeEpoch struct {
+	Index       uint64
+	StartSlot   uint64
+	Duration    uint64
+	Authorities []*types.Authority
+	Randomness  [types.RandomnessLength]byte
+	Config      BabeEpochConfig
+}
+
+// BabeEpochConfig is the BABE configuration of an epoch, the probability of a slot being empty is 1-(C1/C2)
+type BabeEpochConfig struct {
+	C1           uint64
+	C2           uint64
+	AllowedSlots byte // 0 for primary slots only, 1 for secondary plain slots and 2 for secondary VRF slots
+}
+
+// Checkpoint is a decoded light sync state, it holds the finalized block header with the BABE epoch and the GRANDPA
+// authority set at that block
+type Checkpoint struct {
+	Header             *types.Header
+	Epoch              *BabeEpoch
+	GrandpaSetID       uint64
+	GrandpaAuthorities []*types.Authority
+}
+
+// NewLightSyncState returns the light sync state of the given checkpoint, encoded as it's encoded by Substrate.
+// The BABE epoch changes only hold the checkpoint's epoch, and the GRANDPA authority set has no pending changes.
+func NewLightSyncState(c *Checkpoint) (*LightSyncState, error) {
+	header, err := c.Header.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// the finalized block weight is only compared between descendants of the finalized block, so it's left at zero
+	return &LightSyncState{
+		FinalizedBlockHeader: common.BytesToHex(header),
+		BabeEpochChanges:     common.BytesToHex(encodeBabeEpochChanges(c.Header, c.Epoch)),
+		GrandpaAuthoritySet:  common.BytesToHex(encodeGrandpaAuthoritySet(c.GrandpaSetID, c.GrandpaAuthorities)),
+	}, nil
+}
+
+// Checkpoint decodes the light sync state
+func (s *LightSyncState) Checkpoint() (*Checkpoint, error) {
+	enc, err := common.HexToBytes(s.FinalizedBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: finalized block header: %s", ErrInvalidLightSyncState, err)
+	}
+
+	header, err := new(types.Header).Decode(bytes.NewReader(enc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: finalized block header: %s", ErrInvalidLightSyncState, err)
+	}
+
+	enc, err = common.HexToBytes(s.BabeEpochChanges)
+	if err != nil {
+		return nil, fmt.Errorf("%w: babe epoch changes: %s", ErrInvalidLightSyncState, err)
+	}
+
+	epochs, err := decodeBabeEpochChanges(bytes.NewReader(enc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: babe epoch changes: %s", ErrInvalidLightSyncState, err)
+	}
+
+	epoch, err := finalizedEpoch(header, epochs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: babe epoch changes: %s", ErrInvalidLightSyncState, err)
+	}
+
+	enc, err = common.HexToBytes(s.GrandpaAuthoritySet)
+	if err != nil {
+		return nil, fmt.Errorf("%w: grandpa authority set: %s", ErrInvalidLightSyncState, err)
+	}
+
+	setID, auths, err := decodeGrandpaAuthoritySet(bytes.NewReader(enc))
+	if err != nil {
+		return nil, fmt.Errorf("%w: grandpa authority set: %s", ErrInvalidLightSyncState, err)
+	}
+
+	return &Checkpoint{
+		Header:             header,
+		Epoch:              epoch,
+		GrandpaSetID:       setID,
+		GrandpaAuthorities: auths,
+	}, nil
+}
+
+// finalizedEpoch returns the epoch that contains the slot of the given header, or the earliest epoch if the header
+// has no slot, which is the case for the genesis block
+func finalizedEpoch(header *types.Header, epochs []*BabeEpoch) (*BabeEpoch, error) {
+	if len(epochs) == 0 {
+		return nil, errors.New("no epochs")
+	}
+
+	slot, err := types.GetSlotFromHeader(header)
+	if err != nil {
+		earliest := epochs[0]
+		for _, epoch := range epochs[1:] {
+			if epoch.Index < earliest.Index {
+				earliest = epoch
+			}
+		}
+		return earliest, nil
+	}
+
+	for _, epoch := range epochs {
+		if slot >= epoch.StartSlot && slot < epoch.StartSlot+epoch.Duration {
+			return epoch, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no epoch contains slot %d of the finalized block", slot)
+}
+
+// encodeBabeEpochChanges encodes the epoch changes of a Substrate node that only knows of the given epoch. The
+// epoch is announced by the given header in its fork tree, which is a single node.
+func encodeBabeEpochChanges(header *types.Header, epoch *BabeEpoch) []byte {
+	buf := new(bytes.Buffer)
+	hash := header.Hash()
+	number := uint32(header.Number.Uint64())
+
+	// the fork tree has one root and no best finalized number
+	writeCompact(buf, 1)
+	buf.Write(hash[:])
+	writeUint32(buf, number)
+	buf.WriteByte(1) // PersistedEpochHeader::Regular
+	writeUint64(buf, epoch.StartSlot)
+	writeUint64(buf, epoch.StartSlot+epoch.Duration)
+	writeCompact(buf, 0)
+	buf.WriteByte(0)
+
+	// the epochs are a map from the announcing block to the epoch
+	writeCompact(buf, 1)
+	buf.Write(hash[:])
+	writeUint32(buf, number)
+	buf.WriteByte(1) // PersistedEpoch::Regular
+	encodeBabeEpoch(buf, epoch)
+
+	return buf.Bytes()
+}
+
+// encodeBabeEpoch encodes the epoch as sc_consensus_babe::Epoch
+func encodeBabeEpoch(buf *bytes.Buffer, epoch *BabeEpoch) {
+	writeUint64(buf, epoch.Index)
+	writeUint64(buf, epoch.StartSlot)
+	writeUint64(buf, epoch.Duration)
+	writeAuthorities(buf, epoch.Authorities)
+	buf.Write(epoch.Randomness[:])
+	writeUint64(buf, epoch.Config.C1)
+	writeUint64(buf, epoch.Config.C2)
+	buf.WriteByte(epoch.Config.AllowedSlots)
+}
+
+// decodeBabeEpochChanges decodes sc_consensus_epochs::EpochChanges and returns all of its epochs. The fork tree
+// only holds the start and end slots of the epochs, so it's skipped.
+func decodeBabeEpochChanges(r io.Reader) ([]*BabeEpoch, error) {
+	roots, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < roots; i++ {
+		if err = skipEpochTreeNode(r, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// best finalized number
+	if err = skipOption(r, 4); err != nil {
+		return nil, err
+	}
+
+	count, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		epochs  []*BabeEpoch
+		epoch   *BabeEpoch
+		variant byte
+	)
+	for i := 0; i < count; i++ {
+		// the hash and number of the block that announced the epoch
+		if _, err = io.ReadFull(r, make([]byte, 36)); err != nil {
+			return nil, err
+		}
+
+		variant, err = common.ReadByte(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// genesis entries hold the first two epochs, regular ones hold one
+		n := 1
+		switch variant {
+		case 0:
+			n = 2
+		case 1:
+		default:
+			return nil, fmt.Errorf("invalid persisted epoch variant %d", variant)
+		}
+
+		for j := 0; j < n; j++ {
+			epoch, err = decodeBabeEpoch(r)
+			if err != nil {
+				return nil, err
+			}
+			epochs = append(epochs, epoch)
+		}
+	}
+
+	return epochs, nil
+}
+
+// maxEpochTreeDepth bounds the recursion when skipping the fork tree of the epoch changes
+const maxEpochTreeDepth = 1024
+
+// skipEpochTreeNode skips a node of the fork tree of the epoch changes and its children
+func skipEpochTreeNode(r io.Reader, depth int) error {
+	if depth > maxEpochTreeDepth {
+		return errors.New("epoch changes fork tree is too deep")
+	}
+
+	// hash and number
+	if _, err := io.ReadFull(r, make([]byte, 36)); err != nil {
+		return err
+	}
+
+	variant, err := common.ReadByte(r)
+	if err != nil {
+		return err
+	}
+
+	// genesis nodes hold the start and end slots of two epochs, regular ones of one
+	switch variant {
+	case 0:
+		_, err = io.ReadFull(r, make([]byte, 32))
+	case 1:
+		_, err = io.ReadFull(r, make([]byte, 16))
+	default:
+		return fmt.Errorf("invalid persisted epoch header variant %d", variant)
+	}
+	if err != nil {
+		return err
+	}
+
+	children, err := readLength(r)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < children; i++ {
+		if err = skipEpochTreeNode(r, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeBabeEpoch decodes sc_consensus_babe::Epoch
+func decodeBabeEpoch(r io.Reader) (*BabeEpoch, error) {
+	var (
+		epoch = new(BabeEpoch)
+		err   error
+	)
+
+	for _, field := range []*uint64{&epoch.Index, &epoch.StartSlot, &epoch.Duration} {
+		if *field, err = common.ReadUint64(r); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := readAuthorities(r)
+	if err != nil {
+		return nil, err
+	}
+
+	epoch.Authorities, err = types.BABEAuthorityRawToAuthority(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if epoch.Randomness, err = common.Read32Bytes(r); err != nil {
+		return nil, err
+	}
+
+	if epoch.Config.C1, err = common.ReadUint64(r); err != nil {
+		return nil, err
+	}
+	if epoch.Config.C2, err = common.ReadUint64(r); err != nil {
+		return nil, err
+	}
+	if epoch.Config.AllowedSlots, err = common.ReadByte(r); err != nil {
+		return nil, err
+	}
+
+	return epoch, nil
+}
+
+// encodeGrandpaAuthoritySet encodes sc_finality_grandpa::AuthoritySet without pending changes
+func encodeGrandpaAuthoritySet(setID uint64, auths []*types.Authority) []byte {
+	buf := new(bytes.Buffer)
+	writeAuthorities(buf, auths)
+	writeUint64(buf, setID)
+
+	// no pending standard changes, which are a fork tree with no roots and no best finalized number
+	writeCompact(buf, 0)
+	buf.WriteByte(0)
+	// no pending forced changes and no authority set changes
+	writeCompact(buf, 0)
+	writeCompact(buf, 0)
+
+	return buf.Bytes()
+}
+
+// decodeGrandpaAuthoritySet decodes the current authorities and set ID of sc_finality_grandpa::AuthoritySet, the
+// pending changes that follow them are ignored
+func decodeGrandpaAuthoritySet(r io.Reader) (uint64, []*types.Authority, error) {
+	raw, err := readAuthorities(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	grandpaRaw := make([]*types.GrandpaAuthorityDataRaw, len(raw))
+	for i, a := range raw {
+		grandpaRaw[i] = &types.GrandpaAuthorityDataRaw{
+			Key: a.Key,
+			ID:  a.Weight,
+		}
+	}
+
+	auths, err := types.GrandpaAuthorityDataRawToAuthorityData(grandpaRaw)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	setID, err := common.ReadUint64(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return setID, auths, nil
+}
+
+// readAuthorities reads a Vec<(AuthorityId, u64)>
+func readAuthorities(r io.Reader) ([]*types.AuthorityRaw, error) {
+	n, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	auths := make([]*types.AuthorityRaw, n)
+	for i := range auths {
+		auths[i], err = new(types.AuthorityRaw).Decode(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auths, nil
+}
+
+// writeAuthorities writes the authorities as a Vec<(AuthorityId, u64)>
+func writeAuthorities(buf *bytes.Buffer, auths []*types.Authority) {
+	writeCompact(buf, len(auths))
+	for _, a := range auths {
+		buf.Write(a.Key.Encode())
+		writeUint64(buf, a.Weight)
+	}
+}
+
+// readLength reads a compact encoded collection length
+func readLength(r io.Reader) (int, error) {
+	sd := scale.Decoder{Reader: r}
+	n, err := sd.DecodeInteger()
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 || n > maxLightSyncStateItems {
+		return 0, fmt.Errorf("invalid length %d", n)
+	}
+
+	return int(n), nil
+}
+
+// skipOption skips an Option of a value with the given size
+func skipOption(r io.Reader, size int) error {
+	exists, err := common.ReadByte(r)
+	if err != nil {
+		return err
+	}
+
+	switch exists {
+	case 0:
+		return nil
+	case 1:
+		_, err = io.ReadFull(r, make([]byte, size))
+		return err
+	default:
+		return fmt.Errorf("invalid option %d", exists)
+	}
+}
+
+func writeCompact(buf *bytes.Buffer, n int) {
+	enc, _ := scale.Encode(big.NewInt(int64(n)))
+	buf.Write(enc)
+}
+
+func writeUint32(buf *bytes.Buffer, n uint32) {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, n)
+	buf.Write(b)
+}
+
+func writeUint64(buf *bytes.Buffer, n uint64) {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, n)
+	buf.Write(b)
+}
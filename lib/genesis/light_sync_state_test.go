@@ -0,0 +1,146 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBabeEpoch(t *testing.T, index uint64) *BabeEpoch {
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	return &BabeEpoch{
+		Index:       index,
+		StartSlot:   1000 + (index-1)*200,
+		Duration:    200,
+		Authorities: []*types.Authority{types.NewAuthority(kp.Public(), 1)},
+		Randomness:  [types.RandomnessLength]byte{byte(index)},
+		Config: BabeEpochConfig{
+			C1:           1,
+			C2:           4,
+			AllowedSlots: 1,
+		},
+	}
+}
+
+func requireEqualEpochs(t *testing.T, expected, actual *BabeEpoch) {
+	require.Equal(t, expected.Index, actual.Index)
+	require.Equal(t, expected.StartSlot, actual.StartSlot)
+	require.Equal(t, expected.Duration, actual.Duration)
+	require.Equal(t, expected.Randomness, actual.Randomness)
+	require.Equal(t, expected.Config, actual.Config)
+	require.Len(t, actual.Authorities, len(expected.Authorities))
+	for i, auth := range expected.Authorities {
+		require.Equal(t, auth.Key.Encode(), actual.Authorities[i].Key.Encode())
+		require.Equal(t, auth.Weight, actual.Authorities[i].Weight)
+	}
+}
+
+func TestLightSyncState_Checkpoint(t *testing.T) {
+	kp, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	header := &types.Header{
+		ParentHash: common.Hash{0x01},
+		Number:     big.NewInt(100),
+		Digest:     types.NewEmptyDigest(),
+	}
+
+	expected := &Checkpoint{
+		Header:             header,
+		Epoch:              newTestBabeEpoch(t, 3),
+		GrandpaSetID:       2,
+		GrandpaAuthorities: []*types.Authority{types.NewAuthority(kp.Public(), 1)},
+	}
+
+	state, err := NewLightSyncState(expected)
+	require.NoError(t, err)
+
+	checkpoint, err := state.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, header.Hash(), checkpoint.Header.Hash())
+	requireEqualEpochs(t, expected.Epoch, checkpoint.Epoch)
+	require.Equal(t, uint64(2), checkpoint.GrandpaSetID)
+	require.Equal(t, expected.GrandpaAuthorities, checkpoint.GrandpaAuthorities)
+}
+
+func TestDecodeBabeEpochChanges_Genesis(t *testing.T) {
+	first, second := newTestBabeEpoch(t, 1), newTestBabeEpoch(t, 2)
+
+	// no fork tree and a single genesis entry, which holds the first two epochs
+	buf := new(bytes.Buffer)
+	writeCompact(buf, 0)
+	buf.WriteByte(0)
+	writeCompact(buf, 1)
+	buf.Write(make([]byte, 36))
+	buf.WriteByte(0)
+	encodeBabeEpoch(buf, second)
+	encodeBabeEpoch(buf, first)
+
+	epochs, err := decodeBabeEpochChanges(buf)
+	require.NoError(t, err)
+	require.Len(t, epochs, 2)
+
+	// the genesis block has no slot, so it's in the earliest epoch
+	genesisHeader := &types.Header{
+		Number: big.NewInt(0),
+		Digest: types.NewEmptyDigest(),
+	}
+	epoch, err := finalizedEpoch(genesisHeader, epochs)
+	require.NoError(t, err)
+	requireEqualEpochs(t, first, epoch)
+}
+
+func TestLightSyncState_Checkpoint_Invalid(t *testing.T) {
+	header := &types.Header{
+		Number: big.NewInt(1),
+		Digest: types.NewEmptyDigest(),
+	}
+
+	state, err := NewLightSyncState(&Checkpoint{
+		Header: header,
+		Epoch:  newTestBabeEpoch(t, 1),
+	})
+	require.NoError(t, err)
+
+	// a fork tree with one root that's missing
+	state.BabeEpochChanges = "0x04"
+	_, err = state.Checkpoint()
+	require.True(t, errors.Is(err, ErrInvalidLightSyncState))
+
+	gen := &Genesis{
+		LightSyncState: &LightSyncState{FinalizedBlockHeader: "0x01"},
+	}
+	verr, ok := gen.Validate().(*ValidationError)
+	require.True(t, ok)
+
+	var found bool
+	for _, err := range verr.Errors {
+		found = found || errors.Is(err, ErrInvalidLightSyncState)
+	}
+	require.True(t, found)
+}
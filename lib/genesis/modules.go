@@ -0,0 +1,681 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// ErrInvalidRuntimeModule is returned when a runtime module of the genesis can't be decoded into its typed config
+var ErrInvalidRuntimeModule = errors.New("invalid runtime module")
+
+// typedModules are the runtime modules decoded into RuntimeModules, the others are encoded from the untyped map
+var typedModules = map[string]bool{
+	"system":   true,
+	"balances": true,
+	"session":  true,
+	"grandpa":  true,
+	"babe":     true,
+	"staking":  true,
+	"sudo":     true,
+}
+
+// sessionKeyTypes are the session keys with their key type ids, in the order of the Substrate node runtime keys
+var sessionKeyTypes = []struct {
+	name string
+	id   string
+}{
+	{"grandpa", "gran"},
+	{"babe", "babe"},
+	{"im_online", "imon"},
+	{"authority_discovery", "audi"},
+}
+
+// forceEras are the variants of the staking Forcing enum
+var forceEras = []string{"NotForcing", "ForceNew", "ForceNone", "ForceAlways"}
+
+// RuntimeModules is the typed genesis config of the common runtime modules, modules missing from the genesis are nil
+type RuntimeModules struct {
+	System   *SystemModule   `json:"system,omitempty"`
+	Balances *BalancesModule `json:"balances,omitempty"`
+	Session  *SessionModule  `json:"session,omitempty"`
+	Grandpa  *GrandpaModule  `json:"grandpa,omitempty"`
+	Babe     *BabeModule     `json:"babe,omitempty"`
+	Staking  *StakingModule  `json:"staking,omitempty"`
+	Sudo     *SudoModule     `json:"sudo,omitempty"`
+}
+
+// SystemModule is the genesis config of the system module
+type SystemModule struct {
+	Code string `json:"code"`
+}
+
+// BalancesModule is the genesis config of the balances module
+type BalancesModule struct {
+	Balances []*AccountBalance `json:"balances"`
+}
+
+// SessionModule is the genesis config of the session module
+type SessionModule struct {
+	Keys []*SessionKeys `json:"keys"`
+}
+
+// GrandpaModule is the genesis config of the grandpa module
+type GrandpaModule struct {
+	Authorities AuthorityList `json:"authorities"`
+}
+
+// BabeModule is the genesis config of the babe module
+type BabeModule struct {
+	Authorities AuthorityList `json:"authorities"`
+}
+
+// StakingModule is the genesis config of the staking module
+type StakingModule struct {
+	ValidatorCount        uint32           `json:"validatorCount"`
+	MinimumValidatorCount uint32           `json:"minimumValidatorCount"`
+	Invulnerables         []common.Address `json:"invulnerables"`
+	ForceEra              string           `json:"forceEra"`
+	SlashRewardFraction   uint32           `json:"slashRewardFraction"`
+	CanceledPayout        *Balance         `json:"canceledPayout,omitempty"`
+	// Stakers aren't written to the raw storage, their bonds and exposures are set up by the runtime genesis build
+	Stakers []*Staker `json:"stakers"`
+}
+
+// SudoModule is the genesis config of the sudo module
+type SudoModule struct {
+	Key common.Address `json:"key"`
+}
+
+// Balance is a u128 balance, decoded from a JSON number or string without losing precision
+type Balance big.Int
+
+// AccountBalance is the free balance of an account, encoded in JSON as [address, balance]
+type AccountBalance struct {
+	Address common.Address
+	Balance *big.Int
+}
+
+// AuthorityWeight is an authority with its weight, encoded in JSON as [address, weight]. The address may also be a
+// 0x prefixed hex encoded public key.
+type AuthorityWeight struct {
+	Address common.Address
+	Weight  uint64
+}
+
+// AuthorityList is a list of authorities, decoded from either [[address, weight], ...] or a flat
+// [address, weight, ...] list
+type AuthorityList []*AuthorityWeight
+
+// SessionKeys are the session keys of a validator, encoded in JSON as [account, validator, {name: address}]
+type SessionKeys struct {
+	Account   common.Address
+	Validator common.Address
+	Keys      map[string]common.Address
+}
+
+// Staker is a genesis staker, encoded in JSON as [stash, controller, balance, status]
+type Staker struct {
+	Stash      common.Address
+	Controller common.Address
+	Balance    *big.Int
+	Status     interface{}
+}
+
+// RuntimeModules decodes the typed config of the common runtime modules of the genesis
+func (g *Genesis) RuntimeModules() (*RuntimeModules, error) {
+	return decodeRuntimeModules(g.Genesis.Runtime)
+}
+
+func decodeRuntimeModules(runtime map[string]map[string]interface{}) (*RuntimeModules, error) {
+	m := new(RuntimeModules)
+	if len(runtime) == 0 {
+		return m, nil
+	}
+
+	data, err := json.Marshal(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// trimAuthorities keeps only the first authCount babe and grandpa authorities
+func (m *RuntimeModules) trimAuthorities(authCount int) {
+	if m.Babe != nil && len(m.Babe.Authorities) > authCount {
+		m.Babe.Authorities = m.Babe.Authorities[:authCount]
+	}
+	if m.Grandpa != nil && len(m.Grandpa.Authorities) > authCount {
+		m.Grandpa.Authorities = m.Grandpa.Authorities[:authCount]
+	}
+}
+
+// Raw returns the raw storage entries of the modules
+func (m *RuntimeModules) Raw() (map[string]string, error) {
+	g := new(Genesis)
+	g.Genesis.Raw[0] = make(map[string]string)
+
+	if m.System != nil && m.System.Code != "" {
+		// the code is stored as is, it is already hex encoded
+		g.Genesis.Raw[0][common.BytesToHex(common.CodeKey)] = m.System.Code
+	}
+
+	if m.Babe != nil {
+		auths, err := m.Babe.Authorities.authorities(crypto.Sr25519Type)
+		if err != nil {
+			return nil, err
+		}
+
+		err = g.setAuthorities("babe", auths, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Grandpa != nil {
+		auths, err := m.Grandpa.Authorities.authorities(crypto.Ed25519Type)
+		if err != nil {
+			return nil, err
+		}
+
+		err = g.SetGrandpaAuthorities(auths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Balances != nil && len(m.Balances.Balances) > 0 {
+		balances := make(map[common.Address]*big.Int)
+		for _, b := range m.Balances.Balances {
+			if _, has := balances[b.Address]; has {
+				return nil, fmt.Errorf("%w: duplicate balance for %s", ErrInvalidRuntimeModule, b.Address)
+			}
+			balances[b.Address] = b.Balance
+		}
+
+		err := g.SetBalances(balances)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Session != nil {
+		err := g.setSession(m.Session)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Staking != nil {
+		err := g.setStaking(m.Staking)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Sudo != nil {
+		err := g.SetSudo(m.Sudo.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g.Genesis.Raw[0], nil
+}
+
+// setSession sets the validators, their next and queued keys and the owners of the keys
+func (g *Genesis) setSession(s *SessionModule) error {
+	validatorsKey, err := storagePrefix("Session", "Validators")
+	if err != nil {
+		return err
+	}
+
+	nextKeysPrefix, err := storagePrefix("Session", "NextKeys")
+	if err != nil {
+		return err
+	}
+
+	keyOwnerPrefix, err := storagePrefix("Session", "KeyOwner")
+	if err != nil {
+		return err
+	}
+
+	queuedKeysKey, err := storagePrefix("Session", "QueuedKeys")
+	if err != nil {
+		return err
+	}
+
+	validators, err := scale.Encode(big.NewInt(int64(len(s.Keys))))
+	if err != nil {
+		return err
+	}
+	queued := append([]byte{}, validators...)
+
+	for _, sk := range s.Keys {
+		validator, err := accountID(sk.Validator)
+		if err != nil {
+			return err
+		}
+
+		keys, err := sk.encodeKeys()
+		if err != nil {
+			return err
+		}
+
+		validators = append(validators, validator...)
+		queued = append(append(queued, validator...), keys...)
+
+		key, err := twox64Concat(nextKeysPrefix, validator)
+		if err != nil {
+			return err
+		}
+		g.setRaw(common.BytesToHex(key), keys)
+
+		for _, kt := range sessionKeyTypes {
+			add, has := sk.Keys[kt.name]
+			if !has {
+				continue
+			}
+
+			// the key owner map is keyed by the (key type id, public key) tuple
+			pub, err := scale.Encode(crypto.PublicAddressToByteArray(add))
+			if err != nil {
+				return err
+			}
+
+			key, err := twox64Concat(keyOwnerPrefix, append([]byte(kt.id), pub...))
+			if err != nil {
+				return err
+			}
+			g.setRaw(common.BytesToHex(key), validator)
+		}
+	}
+
+	g.setRaw(common.BytesToHex(validatorsKey), validators)
+	g.setRaw(common.BytesToHex(queuedKeysKey), queued)
+	return nil
+}
+
+// setStaking sets the staking parameters, the stakers are left to the runtime
+func (g *Genesis) setStaking(s *StakingModule) error {
+	forceEra := 0
+	if s.ForceEra != "" {
+		forceEra = -1
+		for i, v := range forceEras {
+			if v == s.ForceEra {
+				forceEra = i
+			}
+		}
+		if forceEra < 0 {
+			return fmt.Errorf("%w: unknown force era %s", ErrInvalidRuntimeModule, s.ForceEra)
+		}
+	}
+
+	invulnerables, err := scale.Encode(big.NewInt(int64(len(s.Invulnerables))))
+	if err != nil {
+		return err
+	}
+
+	for _, add := range s.Invulnerables {
+		id, err := accountID(add)
+		if err != nil {
+			return err
+		}
+		invulnerables = append(invulnerables, id...)
+	}
+
+	canceledPayout := new(big.Int)
+	if s.CanceledPayout != nil {
+		canceledPayout = s.CanceledPayout.Int()
+	}
+
+	if canceledPayout.Sign() < 0 || canceledPayout.Cmp(maxBalance) > 0 {
+		return fmt.Errorf("canceled payout: %w", ErrInvalidBalance)
+	}
+
+	items := []struct {
+		name  string
+		value interface{}
+	}{
+		{"ValidatorCount", s.ValidatorCount},
+		{"MinimumValidatorCount", s.MinimumValidatorCount},
+		{"Invulnerables", invulnerables},
+		{"ForceEra", []byte{byte(forceEra)}},
+		{"SlashRewardFraction", s.SlashRewardFraction},
+		{"CanceledSlashPayout", encodeU128(canceledPayout)},
+	}
+
+	for _, item := range items {
+		key, err := storagePrefix("Staking", item.name)
+		if err != nil {
+			return err
+		}
+
+		value, ok := item.value.([]byte)
+		if !ok {
+			value, err = scale.Encode(item.value)
+			if err != nil {
+				return err
+			}
+		}
+
+		g.setRaw(common.BytesToHex(key), value)
+	}
+
+	return nil
+}
+
+// encodeKeys returns the session keys encoded in the order of the runtime keys
+func (sk *SessionKeys) encodeKeys() ([]byte, error) {
+	var enc []byte
+	for _, kt := range sessionKeyTypes {
+		add, has := sk.Keys[kt.name]
+		if !has {
+			continue
+		}
+
+		id, err := accountID(add)
+		if err != nil {
+			return nil, err
+		}
+		enc = append(enc, id...)
+	}
+
+	return enc, nil
+}
+
+// authorities returns the authorities with public keys of the given type
+func (l AuthorityList) authorities(kt crypto.KeyType) ([]*types.Authority, error) {
+	auths := make([]*types.Authority, len(l))
+	for i, a := range l {
+		key, err := authorityPublicKey(a.Address, kt)
+		if err != nil {
+			return nil, err
+		}
+
+		auths[i] = types.NewAuthority(key, a.Weight)
+	}
+
+	return auths, nil
+}
+
+// UnmarshalJSON decodes the authorities from either a list of [address, weight] tuples or a flat list
+func (l *AuthorityList) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 || !isJSONString(items[0]) {
+		var auths []*AuthorityWeight
+		err = json.Unmarshal(data, &auths)
+		if err != nil {
+			return err
+		}
+
+		*l = auths
+		return nil
+	}
+
+	if len(items)%2 != 0 {
+		return fmt.Errorf("%w: authorities must be address and weight pairs", ErrInvalidRuntimeModule)
+	}
+
+	auths := make([]*AuthorityWeight, len(items)/2)
+	for i := range auths {
+		auths[i] = new(AuthorityWeight)
+		err = auths[i].decode(items[2*i], items[2*i+1])
+		if err != nil {
+			return err
+		}
+	}
+
+	*l = auths
+	return nil
+}
+
+// UnmarshalJSON decodes an [address, weight] tuple
+func (a *AuthorityWeight) UnmarshalJSON(data []byte) error {
+	tuple, err := decodeTuple(data, 2)
+	if err != nil {
+		return err
+	}
+
+	return a.decode(tuple[0], tuple[1])
+}
+
+func (a *AuthorityWeight) decode(add, weight json.RawMessage) error {
+	err := json.Unmarshal(add, &a.Address)
+	if err != nil {
+		return err
+	}
+
+	// the key type is checked once the authorities are encoded, see authorities
+	_, err = authorityID(a.Address)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(weight, &a.Weight)
+}
+
+// MarshalJSON encodes the authority as an [address, weight] tuple
+func (a *AuthorityWeight) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{a.Address, a.Weight})
+}
+
+// UnmarshalJSON decodes an [address, balance] tuple
+func (b *AccountBalance) UnmarshalJSON(data []byte) error {
+	tuple, err := decodeTuple(data, 2)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAddress(tuple[0], &b.Address)
+	if err != nil {
+		return err
+	}
+
+	b.Balance, err = decodeBalance(tuple[1])
+	return err
+}
+
+// MarshalJSON encodes the balance as an [address, balance] tuple
+func (b *AccountBalance) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{b.Address, b.Balance})
+}
+
+// UnmarshalJSON decodes an [account, validator, {name: address}] tuple
+func (sk *SessionKeys) UnmarshalJSON(data []byte) error {
+	tuple, err := decodeTuple(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAddress(tuple[0], &sk.Account)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAddress(tuple[1], &sk.Validator)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(tuple[2], &sk.Keys)
+	if err != nil {
+		return err
+	}
+
+	for name, add := range sk.Keys {
+		if !isSessionKeyType(name) {
+			return fmt.Errorf("%w: unknown session key %s", ErrInvalidRuntimeModule, name)
+		}
+
+		err = crypto.ValidateAddress(add)
+		if err != nil {
+			return fmt.Errorf("%s: %w", add, err)
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the session keys as an [account, validator, {name: address}] tuple
+func (sk *SessionKeys) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{sk.Account, sk.Validator, sk.Keys})
+}
+
+// UnmarshalJSON decodes a [stash, controller, balance, status] tuple
+func (s *Staker) UnmarshalJSON(data []byte) error {
+	tuple, err := decodeTuple(data, 4)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAddress(tuple[0], &s.Stash)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAddress(tuple[1], &s.Controller)
+	if err != nil {
+		return err
+	}
+
+	s.Balance, err = decodeBalance(tuple[2])
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(tuple[3], &s.Status)
+}
+
+// MarshalJSON encodes the staker as a [stash, controller, balance, status] tuple
+func (s *Staker) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{s.Stash, s.Controller, s.Balance, s.Status})
+}
+
+// Int returns the balance as a big.Int
+func (b *Balance) Int() *big.Int {
+	return (*big.Int)(b)
+}
+
+// UnmarshalJSON decodes the balance from a JSON number or string
+func (b *Balance) UnmarshalJSON(data []byte) error {
+	v, err := decodeBalance(data)
+	if err != nil {
+		return err
+	}
+
+	*b = Balance(*v)
+	return nil
+}
+
+// MarshalJSON encodes the balance as a JSON number
+func (b *Balance) MarshalJSON() ([]byte, error) {
+	return b.Int().MarshalJSON()
+}
+
+// decodeBalance decodes a positive integer from a JSON number or string, the number may be in exponent notation
+func decodeBalance(data json.RawMessage) (*big.Int, error) {
+	s := strings.Trim(string(data), `"`)
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+		if err != nil || !f.IsInt() {
+			return nil, fmt.Errorf("%s: %w", s, ErrInvalidBalance)
+		}
+		v, _ = f.Int(nil)
+	}
+
+	if v.Sign() < 0 || v.Cmp(maxBalance) > 0 {
+		return nil, fmt.Errorf("%s: %w", s, ErrInvalidBalance)
+	}
+
+	return v, nil
+}
+
+func decodeAddress(data json.RawMessage, add *common.Address) error {
+	err := json.Unmarshal(data, add)
+	if err != nil {
+		return err
+	}
+
+	err = crypto.ValidateAddress(*add)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *add, err)
+	}
+
+	return nil
+}
+
+// decodeTuple decodes a JSON array with the given number of elements
+func decodeTuple(data []byte, n int) ([]json.RawMessage, error) {
+	var tuple []json.RawMessage
+	err := json.Unmarshal(data, &tuple)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tuple) != n {
+		return nil, fmt.Errorf("%w: expected %d elements, got %d", ErrInvalidRuntimeModule, n, len(tuple))
+	}
+
+	return tuple, nil
+}
+
+func isJSONString(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '"'
+}
+
+func isSessionKeyType(name string) bool {
+	for _, kt := range sessionKeyTypes {
+		if kt.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// twox64Concat returns the key of a twox_64_concat hashed storage map entry
+func twox64Concat(prefix, key []byte) ([]byte, error) {
+	h, err := common.Twox64(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(append([]byte{}, prefix...), h...), key...), nil
+}
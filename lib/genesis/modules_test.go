@@ -0,0 +1,163 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const bob = common.Address("5FHneW46xGXgs5mUiveU4sbTyGBzmstUspZC92UhjJM694ty")
+
+func TestNewGenesisFromJSONBytes_BalancePrecision(t *testing.T) {
+	// 2^70 + 1 can't be represented by a float64
+	data := []byte(`{"genesis":{"runtime":{"balances":{"balances":[["` + string(alice) + `", 1180591620717411303425]]}}}}`)
+	gen, err := NewGenesisFromJSONBytes(data, 0)
+	require.NoError(t, err)
+
+	balance := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 70), big.NewInt(1))
+	expected := new(Genesis)
+	err = expected.SetBalances(map[common.Address]*big.Int{alice: balance})
+	require.NoError(t, err)
+	require.Equal(t, expected.Genesis.Raw[0], gen.Genesis.Raw[0])
+}
+
+func TestAuthorityList_UnmarshalJSON(t *testing.T) {
+	var nested, flat AuthorityList
+	err := json.Unmarshal([]byte(`[["`+string(alice)+`", 1], ["`+string(bob)+`", 2]]`), &nested)
+	require.NoError(t, err)
+	err = json.Unmarshal([]byte(`["`+string(alice)+`", 1, "`+string(bob)+`", 2]`), &flat)
+	require.NoError(t, err)
+
+	require.Equal(t, AuthorityList{{alice, 1}, {bob, 2}}, nested)
+	require.Equal(t, nested, flat)
+
+	err = json.Unmarshal([]byte(`["`+string(alice)+`", 1, "`+string(bob)+`"]`), &flat)
+	require.True(t, errors.Is(err, ErrInvalidRuntimeModule))
+}
+
+func TestRuntimeModules_Raw_Grandpa(t *testing.T) {
+	m := &RuntimeModules{
+		Grandpa: &GrandpaModule{Authorities: AuthorityList{{bob, 3}}},
+	}
+
+	raw, err := m.Raw()
+	require.NoError(t, err)
+
+	// version, compact length, then the key and its u64 weight
+	expected := "0x0104" + common.BytesToHex(crypto.PublicAddressToByteArray(bob))[2:] + "0300000000000000"
+	require.Equal(t, expected, raw[common.BytesToHex([]byte(":grandpa_authorities"))])
+}
+
+func TestRuntimeModules_Raw_Staking(t *testing.T) {
+	m := &RuntimeModules{
+		Staking: &StakingModule{
+			ValidatorCount:      2,
+			Invulnerables:       []common.Address{alice},
+			ForceEra:            "ForceNone",
+			SlashRewardFraction: 100000000,
+		},
+	}
+
+	raw, err := m.Raw()
+	require.NoError(t, err)
+
+	value := func(item string) string {
+		key, err := storagePrefix("Staking", item)
+		require.NoError(t, err)
+		return raw[common.BytesToHex(key)]
+	}
+
+	require.Equal(t, "0x02000000", value("ValidatorCount"))
+	require.Equal(t, "0x00000000", value("MinimumValidatorCount"))
+	require.Equal(t, "0x04"+common.BytesToHex(crypto.PublicAddressToByteArray(alice))[2:], value("Invulnerables"))
+	require.Equal(t, "0x02", value("ForceEra"))
+	require.Equal(t, "0x00e1f505", value("SlashRewardFraction"))
+
+	m.Staking.ForceEra = "Sometimes"
+	_, err = m.Raw()
+	require.True(t, errors.Is(err, ErrInvalidRuntimeModule))
+}
+
+func TestRuntimeModules_Raw_Session(t *testing.T) {
+	data := []byte(`{"session":{"keys":[["` + string(alice) + `", "` + string(alice) + `", {"babe": "` + string(alice) + `", "grandpa": "` + string(bob) + `"}]]}}`)
+	m := new(RuntimeModules)
+	err := json.Unmarshal(data, m)
+	require.NoError(t, err)
+
+	raw, err := m.Raw()
+	require.NoError(t, err)
+
+	// the keys are encoded in the order of the runtime session keys, grandpa first
+	prefix, err := storagePrefix("Session", "NextKeys")
+	require.NoError(t, err)
+	key, err := twox64Concat(prefix, crypto.PublicAddressToByteArray(alice))
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHex(append(crypto.PublicAddressToByteArray(bob), crypto.PublicAddressToByteArray(alice)...)),
+		raw[common.BytesToHex(key)])
+
+	// validators, queued keys and an owner per key
+	require.Len(t, raw, 5)
+
+	data = []byte(`{"session":{"keys":[["` + string(alice) + `", "` + string(alice) + `", {"unknown": "` + string(alice) + `"}]]}}`)
+	err = json.Unmarshal(data, new(RuntimeModules))
+	require.True(t, errors.Is(err, ErrInvalidRuntimeModule))
+}
+
+func TestGenesis_RuntimeModules(t *testing.T) {
+	gen, err := NewGenesisFromJSON("../../chain/gssmr/genesis.json", 0)
+	require.NoError(t, err)
+
+	m, err := gen.RuntimeModules()
+	require.NoError(t, err)
+	require.NotNil(t, m.System)
+	require.Len(t, m.Babe.Authorities, len(gen.Genesis.Runtime["babe"]["authorities"].([]interface{})))
+	require.Len(t, m.Grandpa.Authorities, len(gen.Genesis.Runtime["grandpa"]["authorities"].([]interface{})))
+	require.Nil(t, m.Staking)
+}
+
+func TestRuntimeModules_Raw_HexAuthorities(t *testing.T) {
+	ss58 := &RuntimeModules{
+		Babe:    &BabeModule{Authorities: AuthorityList{{alice, 1}}},
+		Grandpa: &GrandpaModule{Authorities: AuthorityList{{bob, 1}}},
+	}
+
+	hex := &RuntimeModules{
+		Babe: &BabeModule{Authorities: AuthorityList{
+			{common.Address(common.BytesToHex(crypto.PublicAddressToByteArray(alice))), 1},
+		}},
+		Grandpa: &GrandpaModule{Authorities: AuthorityList{
+			{common.Address(common.BytesToHex(crypto.PublicAddressToByteArray(bob))), 1},
+		}},
+	}
+
+	expected, err := ss58.Raw()
+	require.NoError(t, err)
+	raw, err := hex.Raw()
+	require.NoError(t, err)
+	require.Equal(t, expected, raw)
+
+	var auths AuthorityList
+	err = json.Unmarshal([]byte(`[["0x1234", 1]]`), &auths)
+	require.True(t, errors.Is(err, ErrInvalidPublicKey))
+}
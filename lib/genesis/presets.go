@@ -0,0 +1,75 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ChainSafe/gossamer/chain/gssmr"
+	"github.com/ChainSafe/gossamer/chain/ksmcc"
+)
+
+// presets are the raw genesis of the well-known chains embedded in the binary, by chain id
+var presets = map[string][]byte{
+	"gssmr": gssmr.GenesisRaw,
+	"ksmcc": ksmcc.GenesisRaw,
+}
+
+// presetAliases are the alternative names of the presets
+var presetAliases = map[string]string{
+	"kusama": "ksmcc",
+}
+
+// PresetName returns the chain id of the preset with the given name or alias. If there's no such preset, the name
+// is returned unchanged.
+func PresetName(name string) string {
+	if id, has := presetAliases[name]; has {
+		return id
+	}
+	return name
+}
+
+// PresetNames returns the sorted chain ids of the embedded presets
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetPresetData returns the raw genesis JSON of the preset with the given name or alias
+func GetPresetData(name string) ([]byte, error) {
+	data, has := presets[PresetName(name)]
+	if !has {
+		return nil, fmt.Errorf("no genesis preset for chain %s, available presets are %v", name, PresetNames())
+	}
+
+	return data, nil
+}
+
+// GetPreset returns the raw genesis of the preset with the given name or alias, eg. "gssmr" or "kusama"
+func GetPreset(name string) (*Genesis, error) {
+	data, err := GetPresetData(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenesisFromJSONRawBytes(data)
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPreset(t *testing.T) {
+	require.Equal(t, []string{"gssmr", "ksmcc"}, PresetNames())
+
+	gen, err := GetPreset("gssmr")
+	require.NoError(t, err)
+	require.Equal(t, "gssmr", gen.ID)
+	require.NotEmpty(t, gen.Genesis.Raw[0])
+
+	gen, err = GetPreset("kusama")
+	require.NoError(t, err)
+	require.Equal(t, "ksmcc", gen.ID)
+	require.Equal(t, "KSM", gen.Properties["tokenSymbol"])
+	require.Equal(t, float64(2), gen.Properties["ss58Format"])
+
+	_, err = GetPreset("unknown")
+	require.Error(t, err)
+}
+
+func TestPresetName(t *testing.T) {
+	require.Equal(t, "ksmcc", PresetName("kusama"))
+	require.Equal(t, "ksmcc", PresetName("ksmcc"))
+	require.Equal(t, "unknown", PresetName("unknown"))
+}
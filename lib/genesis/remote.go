@@ -0,0 +1,145 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// fetchTimeout is the timeout of the request fetching a remote genesis
+var fetchTimeout = time.Minute
+
+// maxGenesisSize is the maximum size of a genesis, both as fetched and once decompressed
+var maxGenesisSize int64 = 256 << 20
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+var (
+	// ErrChecksumMismatch is returned when the checksum of a fetched genesis doesn't match the expected checksum
+	ErrChecksumMismatch = errors.New("genesis checksum mismatch")
+	// ErrMissingChecksum is returned when a genesis is fetched without an expected checksum
+	ErrMissingChecksum = errors.New("genesis checksum is required to fetch a genesis from a URL")
+	// ErrGenesisTooLarge is returned when a genesis exceeds the maximum genesis size
+	ErrGenesisTooLarge = errors.New("genesis is too large")
+)
+
+// IsURL returns true if the given genesis path is an http or https URL
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// FetchGenesis fetches the genesis at the given URL. The checksum is the hex encoded sha256 checksum the fetched
+// file must have, before decompression. Gzip compressed files are decompressed.
+func FetchGenesis(url, checksum string) ([]byte, error) {
+	if checksum == "" {
+		return nil, ErrMissingChecksum
+	}
+
+	client := &http.Client{
+		Timeout: fetchTimeout,
+	}
+
+	resp, err := client.Get(url) //nolint
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis: %w", err)
+	}
+	defer resp.Body.Close() //nolint
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch genesis: %s", resp.Status)
+	}
+
+	data, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis: %w", err)
+	}
+
+	err = verifyChecksum(data, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	return decompress(data)
+}
+
+// NewGenesisFromURL fetches and parses the JSON formatted genesis-raw file at the given URL, see FetchGenesis
+func NewGenesisFromURL(url, checksum string) (*Genesis, error) {
+	data, err := FetchGenesis(url, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenesisFromJSONRawBytes(data)
+}
+
+func verifyChecksum(data []byte, checksum string) error {
+	expected, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(checksum), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid genesis checksum: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], expected) {
+		return fmt.Errorf("%w: expected %x, got %x", ErrChecksumMismatch, expected, sum)
+	}
+
+	return nil
+}
+
+// decompress returns the decompressed data if it's gzip compressed, otherwise it returns the data unchanged
+func decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress genesis: %w", err)
+	}
+	defer r.Close() //nolint
+
+	out, err := readLimited(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress genesis: %w", err)
+	}
+
+	return out, nil
+}
+
+// readLimited reads all of the reader's data, returning ErrGenesisTooLarge if it exceeds maxGenesisSize
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxGenesisSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxGenesisSize {
+		return nil, ErrGenesisTooLarge
+	}
+
+	return data, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGenesisRawJSON(t *testing.T) []byte {
+	gen := &Genesis{
+		Name:    TestGenesis.Name,
+		ID:      TestGenesis.ID,
+		Genesis: TestFieldsRaw,
+	}
+
+	data, err := json.Marshal(gen)
+	require.NoError(t, err)
+	return data
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestNewGenesisFromURL(t *testing.T) {
+	data := newTestGenesisRawJSON(t)
+	compressed := gzipData(t, data)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genesis.json":
+			_, _ = w.Write(data)
+		case "/genesis.json.gz":
+			_, _ = w.Write(compressed)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256(data)
+	gen, err := NewGenesisFromURL(srv.URL+"/genesis.json", hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	require.Equal(t, TestGenesis.Name, gen.Name)
+	require.Equal(t, TestFieldsRaw.Raw, gen.Genesis.Raw)
+
+	// the checksum is of the compressed file
+	sum = sha256.Sum256(compressed)
+	gen, err = NewGenesisFromURL(srv.URL+"/genesis.json.gz", "0x"+hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	require.Equal(t, TestFieldsRaw.Raw, gen.Genesis.Raw)
+
+	_, err = NewGenesisFromURL(srv.URL+"/genesis.json", hex.EncodeToString(sum[:]))
+	require.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	_, err = NewGenesisFromURL(srv.URL+"/missing.json", hex.EncodeToString(sum[:]))
+	require.Error(t, err)
+
+	// remote files are only loaded with a checksum
+	_, err = NewGenesisFromURL(srv.URL+"/genesis.json", "")
+	require.True(t, errors.Is(err, ErrMissingChecksum))
+
+	_, err = NewGenesisFromJSONRaw(srv.URL + "/genesis.json.gz")
+	require.True(t, errors.Is(err, ErrMissingChecksum))
+
+	_, err = NewGenesisFromJSON(srv.URL+"/genesis.json", 0)
+	require.True(t, errors.Is(err, ErrMissingChecksum))
+}
+
+func TestFetchGenesis_TooLarge(t *testing.T) {
+	// the test genesis is padded so that it's larger than once compressed
+	data := append(newTestGenesisRawJSON(t), bytes.Repeat([]byte(" "), 1024)...)
+	compressed := gzipData(t, data)
+	require.Less(t, len(compressed), len(data))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(compressed)
+	}))
+	defer srv.Close()
+
+	defaultMaxGenesisSize := maxGenesisSize
+	defer func() {
+		maxGenesisSize = defaultMaxGenesisSize
+	}()
+
+	sum := sha256.Sum256(compressed)
+	checksum := hex.EncodeToString(sum[:])
+
+	// the fetched file is too large
+	maxGenesisSize = int64(len(compressed) - 1)
+	_, err := FetchGenesis(srv.URL, checksum)
+	require.True(t, errors.Is(err, ErrGenesisTooLarge))
+
+	// the decompressed file is too large
+	maxGenesisSize = int64(len(compressed))
+	_, err = FetchGenesis(srv.URL, checksum)
+	require.True(t, errors.Is(err, ErrGenesisTooLarge))
+
+	maxGenesisSize = int64(len(data))
+	res, err := FetchGenesis(srv.URL, checksum)
+	require.NoError(t, err)
+	require.Equal(t, data, res)
+}
+
+func TestNewGenesisFromJSONRaw_Gzip(t *testing.T) {
+	file, err := ioutil.TempFile("", "genesis-test")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(gzipData(t, newTestGenesisRawJSON(t)))
+	require.NoError(t, err)
+
+	gen, err := NewGenesisFromJSONRaw(file.Name())
+	require.NoError(t, err)
+	require.Equal(t, TestGenesis.ID, gen.ID)
+	require.Equal(t, TestFieldsRaw.Raw, gen.Genesis.Raw)
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTelemetryEndpoint is returned when a telemetry endpoint can't be parsed
+var ErrInvalidTelemetryEndpoint = errors.New("invalid telemetry endpoint")
+
+// TelemetryEndpoint is a telemetry server along with the maximum verbosity of the messages sent to it. The address
+// is either a websocket URL or a multiaddress, eg. /dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F
+type TelemetryEndpoint struct {
+	Address   string
+	Verbosity uint8
+}
+
+// ParseTelemetryEndpoint parses an endpoint in the "address verbosity" format, eg. "wss://example.com/submit 0"
+func ParseTelemetryEndpoint(in string) (*TelemetryEndpoint, error) {
+	fields := strings.Fields(in)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("%w: expected address and verbosity, got %q", ErrInvalidTelemetryEndpoint, in)
+	}
+
+	verbosity, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid verbosity %q", ErrInvalidTelemetryEndpoint, fields[1])
+	}
+
+	e := &TelemetryEndpoint{
+		Address:   fields[0],
+		Verbosity: uint8(verbosity),
+	}
+
+	_, err = e.URL()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// String returns the endpoint in the "address verbosity" format
+func (e *TelemetryEndpoint) String() string {
+	return fmt.Sprintf("%s %d", e.Address, e.Verbosity)
+}
+
+// UnmarshalJSON decodes an [address, verbosity] tuple
+func (e *TelemetryEndpoint) UnmarshalJSON(data []byte) error {
+	var tuple []json.RawMessage
+	err := json.Unmarshal(data, &tuple)
+	if err != nil {
+		return err
+	}
+
+	if len(tuple) != 2 {
+		return fmt.Errorf("%w: expected 2 elements, got %d", ErrInvalidTelemetryEndpoint, len(tuple))
+	}
+
+	err = json.Unmarshal(tuple[0], &e.Address)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(tuple[1], &e.Verbosity)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.URL()
+	return err
+}
+
+// MarshalJSON encodes the endpoint as an [address, verbosity] tuple
+func (e *TelemetryEndpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Address, e.Verbosity})
+}
+
+// URL returns the websocket URL of the endpoint
+func (e *TelemetryEndpoint) URL() (string, error) {
+	if strings.HasPrefix(e.Address, "ws://") || strings.HasPrefix(e.Address, "wss://") {
+		u, err := url.Parse(e.Address)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidTelemetryEndpoint, err)
+		}
+		return u.String(), nil
+	}
+
+	// the multiaddress starts with a "/", so the first part is empty
+	parts := strings.Split(e.Address, "/")
+	if len(parts) < 6 || parts[0] != "" || parts[3] != "tcp" {
+		return "", fmt.Errorf("%w: unsupported address %s", ErrInvalidTelemetryEndpoint, e.Address)
+	}
+
+	host := parts[2]
+	switch parts[1] {
+	case "dns", "dns4", "dns6", "ip4":
+	case "ip6":
+		host = "[" + host + "]"
+	default:
+		return "", fmt.Errorf("%w: unsupported address %s", ErrInvalidTelemetryEndpoint, e.Address)
+	}
+
+	_, err := strconv.ParseUint(parts[4], 10, 16)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid port %q", ErrInvalidTelemetryEndpoint, parts[4])
+	}
+
+	// the x-parity-ws and x-parity-wss protocols are followed by the URL encoded path
+	path := "/"
+	scheme := strings.TrimPrefix(parts[5], "x-parity-")
+	switch {
+	case scheme != "ws" && scheme != "wss":
+		return "", fmt.Errorf("%w: unsupported address %s", ErrInvalidTelemetryEndpoint, e.Address)
+	case scheme != parts[5] && len(parts) == 7:
+		path, err = url.PathUnescape(parts[6])
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidTelemetryEndpoint, err)
+		}
+	case len(parts) != 6:
+		return "", fmt.Errorf("%w: unsupported address %s", ErrInvalidTelemetryEndpoint, e.Address)
+	}
+
+	return fmt.Sprintf("%s://%s:%s%s", scheme, host, parts[4], path), nil
+}
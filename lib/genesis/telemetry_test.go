@@ -0,0 +1,94 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryEndpoint_URL(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"/dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F", "wss://telemetry.polkadot.io:443/submit/"},
+		{"/ip4/127.0.0.1/tcp/8000/x-parity-ws/%2Fsubmit", "ws://127.0.0.1:8000/submit"},
+		{"/ip6/::1/tcp/8000/ws", "ws://[::1]:8000/"},
+		{"wss://telemetry.example.com/submit/", "wss://telemetry.example.com/submit/"},
+	}
+
+	for _, test := range tests {
+		e := &TelemetryEndpoint{Address: test.address}
+		res, err := e.URL()
+		require.NoError(t, err, test.address)
+		require.Equal(t, test.expected, res)
+	}
+
+	invalid := []string{
+		"https://telemetry.example.com",
+		"/dns/telemetry.polkadot.io/udp/443/x-parity-wss/%2Fsubmit%2F",
+		"/dns/telemetry.polkadot.io/tcp/443/quic",
+		"/dns/telemetry.polkadot.io/tcp/port/ws",
+		"/dns/telemetry.polkadot.io/tcp/443/ws/submit",
+	}
+
+	for _, address := range invalid {
+		_, err := (&TelemetryEndpoint{Address: address}).URL()
+		require.True(t, errors.Is(err, ErrInvalidTelemetryEndpoint), address)
+	}
+}
+
+func TestTelemetryEndpoint_JSON(t *testing.T) {
+	data := []byte(`{"telemetryEndpoints":[["/dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F",0]]}`)
+	gen := new(Genesis)
+	err := json.Unmarshal(data, gen)
+	require.NoError(t, err)
+
+	expected := []*TelemetryEndpoint{
+		{Address: "/dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F", Verbosity: 0},
+	}
+	require.Equal(t, expected, gen.TelemetryEndpoints)
+
+	enc, err := json.Marshal(gen.TelemetryEndpoints)
+	require.NoError(t, err)
+	require.Equal(t, `[["/dns/telemetry.polkadot.io/tcp/443/x-parity-wss/%2Fsubmit%2F",0]]`, string(enc))
+
+	// Substrate writes null if the chain has no telemetry endpoints
+	gen = new(Genesis)
+	err = json.Unmarshal([]byte(`{"telemetryEndpoints":null}`), gen)
+	require.NoError(t, err)
+	require.Nil(t, gen.TelemetryEndpoints)
+
+	err = json.Unmarshal([]byte(`{"telemetryEndpoints":[["/dns/telemetry.polkadot.io"]]}`), gen)
+	require.True(t, errors.Is(err, ErrInvalidTelemetryEndpoint))
+}
+
+func TestParseTelemetryEndpoint(t *testing.T) {
+	e, err := ParseTelemetryEndpoint("wss://telemetry.example.com/submit 5")
+	require.NoError(t, err)
+	require.Equal(t, &TelemetryEndpoint{Address: "wss://telemetry.example.com/submit", Verbosity: 5}, e)
+	require.Equal(t, "wss://telemetry.example.com/submit 5", e.String())
+
+	for _, in := range []string{"wss://telemetry.example.com/submit", "wss://telemetry.example.com/submit 256"} {
+		_, err = ParseTelemetryEndpoint(in)
+		require.True(t, errors.Is(err, ErrInvalidTelemetryEndpoint), in)
+	}
+}
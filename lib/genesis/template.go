@@ -0,0 +1,244 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// ErrInvalidBalance is returned when setting a negative balance or one that doesn't fit in a u128
+var ErrInvalidBalance = errors.New("balance must be positive and fit in 128 bits")
+
+var maxBalance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// grandpaAuthoritySetVersion prefixes the encoded GRANDPA authority set
+const grandpaAuthoritySetVersion = 1
+
+// SetBabeAuthorities sets the BABE authorities of the genesis, each with a weight of 1
+func (g *Genesis) SetBabeAuthorities(keys []*sr25519.PublicKey) error {
+	auths := make([]*types.Authority, len(keys))
+	for i, key := range keys {
+		auths[i] = types.NewAuthority(key, 1)
+	}
+
+	return g.setAuthorities("babe", auths, nil)
+}
+
+// SetGrandpaAuthorities sets the GRANDPA authorities of the genesis with their weights
+func (g *Genesis) SetGrandpaAuthorities(auths []*types.Authority) error {
+	// the grandpa authorities are prefixed with the version of their encoding
+	return g.setAuthorities("grandpa", auths, []byte{grandpaAuthoritySetVersion})
+}
+
+// setAuthorities sets the raw storage and the human readable runtime value of the authorities of the given module
+func (g *Genesis) setAuthorities(module string, auths []*types.Authority, prefix []byte) error {
+	key, err := formatKey([]string{module, "authorities"})
+	if err != nil {
+		return err
+	}
+
+	enc, err := scale.Encode(big.NewInt(int64(len(auths))))
+	if err != nil {
+		return err
+	}
+	enc = append(prefix, enc...)
+
+	hr := make([][]interface{}, len(auths))
+	for i, auth := range auths {
+		enc = append(enc, auth.Encode()...)
+		hr[i] = []interface{}{crypto.PublicKeyToAddress(auth.Key), auth.Weight}
+	}
+
+	g.setRaw(key, enc)
+	g.setRuntime(module, "authorities", hr)
+	return nil
+}
+
+// SetBalances sets the free balances of the given accounts in the System Account storage map, replacing their
+// account info, and adds them to the total issuance, less the balances of the replaced accounts. The human readable
+// balances are updated to the balances of every account of the map. The account info is encoded with a u32 nonce and
+// a u32 reference count.
+func (g *Genesis) SetBalances(balances map[common.Address]*big.Int) error {
+	prefix, err := storagePrefix("System", "Account")
+	if err != nil {
+		return err
+	}
+
+	issuanceKey, err := storagePrefix("Balances", "TotalIssuance")
+	if err != nil {
+		return err
+	}
+
+	total, err := g.rawBalance(common.BytesToHex(issuanceKey), 16)
+	if err != nil {
+		return err
+	}
+
+	accounts := make(map[string][]byte, len(balances))
+	for add, balance := range balances {
+		if balance.Sign() < 0 || balance.Cmp(maxBalance) > 0 {
+			return fmt.Errorf("%s: %w", add, ErrInvalidBalance)
+		}
+
+		id, err := accountID(add)
+		if err != nil {
+			return err
+		}
+
+		// the System Account map uses the blake2_128_concat hasher
+		h, err := common.Blake2b128(id)
+		if err != nil {
+			return err
+		}
+
+		key := common.BytesToHex(append(append(append([]byte{}, prefix...), h...), id...))
+
+		// the free and reserved balances of the replaced account info are no longer issued
+		for _, offset := range []int{16 * 4, 16 * 3} {
+			old, err := g.rawBalance(key, offset)
+			if err != nil {
+				return err
+			}
+			total.Sub(total, old)
+		}
+
+		// nonce, reference count, then the free, reserved, misc frozen and fee frozen balances
+		info := make([]byte, 8+16*4)
+		copy(info[8:24], encodeU128(balance))
+		accounts[key] = info
+
+		total.Add(total, balance)
+	}
+
+	if total.Sign() < 0 || total.Cmp(maxBalance) > 0 {
+		return fmt.Errorf("total issuance: %w", ErrInvalidBalance)
+	}
+
+	for key, info := range accounts {
+		g.setRaw(key, info)
+	}
+	g.setRaw(common.BytesToHex(issuanceKey), encodeU128(total))
+
+	entries, err := rawEntries(g.Genesis.Raw[0])
+	if err != nil {
+		return err
+	}
+
+	hr, err := readBalances(entries)
+	if err != nil {
+		return err
+	}
+
+	g.setRuntime("balances", "balances", hr)
+	return nil
+}
+
+// rawBalance returns the u128 balance that starts the given number of bytes before the end of the raw value of the
+// key, or zero if the key isn't set or its value is too short
+func (g *Genesis) rawBalance(key string, fromEnd int) (*big.Int, error) {
+	v, has := g.Genesis.Raw[0][key]
+	if !has {
+		return new(big.Int), nil
+	}
+
+	value, err := common.HexToBytes(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) < fromEnd {
+		return new(big.Int), nil
+	}
+
+	return decodeU128(value[len(value)-fromEnd : len(value)-fromEnd+16]), nil
+}
+
+// SetSudo sets the sudo key of the genesis
+func (g *Genesis) SetSudo(add common.Address) error {
+	id, err := accountID(add)
+	if err != nil {
+		return err
+	}
+
+	key, err := storagePrefix("Sudo", "Key")
+	if err != nil {
+		return err
+	}
+
+	g.setRaw(common.BytesToHex(key), id)
+	return nil
+}
+
+func (g *Genesis) setRaw(key string, value []byte) {
+	if g.Genesis.Raw[0] == nil {
+		g.Genesis.Raw[0] = make(map[string]string)
+	}
+	g.Genesis.Raw[0][key] = common.BytesToHex(value)
+}
+
+func (g *Genesis) setRuntime(module, key string, value interface{}) {
+	if g.Genesis.Runtime == nil {
+		g.Genesis.Runtime = make(map[string]map[string]interface{})
+	}
+	if g.Genesis.Runtime[module] == nil {
+		g.Genesis.Runtime[module] = make(map[string]interface{})
+	}
+	g.Genesis.Runtime[module][key] = value
+}
+
+// storagePrefix returns the twox128 hashes of the module and the storage item names
+func storagePrefix(module, item string) ([]byte, error) {
+	m, err := common.Twox128Hash([]byte(module))
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := common.Twox128Hash([]byte(item))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(m, i...), nil
+}
+
+// accountID returns the public key of the given SS58 address
+func accountID(add common.Address) ([]byte, error) {
+	err := crypto.ValidateAddress(add)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", add, err)
+	}
+
+	return crypto.PublicAddressToByteArray(add), nil
+}
+
+// encodeU128 returns the little endian encoding of a balance that fits in 128 bits
+func encodeU128(v *big.Int) []byte {
+	b := v.Bytes()
+	enc := make([]byte, 16)
+	for i := range b {
+		enc[i] = b[len(b)-1-i]
+	}
+	return enc
+}
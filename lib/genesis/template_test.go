@@ -0,0 +1,120 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/stretchr/testify/require"
+)
+
+const alice = common.Address("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY")
+
+func TestSetAuthorities(t *testing.T) {
+	expected, err := NewGenesisFromJSON("../../chain/gssmr/genesis.json", 0)
+	require.NoError(t, err)
+
+	var babeKeys []*sr25519.PublicKey
+	for _, auth := range expected.Genesis.Runtime["babe"]["authorities"].([]interface{}) {
+		key, err := sr25519.NewPublicKey(crypto.PublicAddressToByteArray(common.Address(auth.([]interface{})[0].(string))))
+		require.NoError(t, err)
+		babeKeys = append(babeKeys, key)
+	}
+
+	var grandpaAuths []*types.Authority
+	for _, auth := range expected.Genesis.Runtime["grandpa"]["authorities"].([]interface{}) {
+		key, err := ed25519.NewPublicKey(crypto.PublicAddressToByteArray(common.Address(auth.([]interface{})[0].(string))))
+		require.NoError(t, err)
+		grandpaAuths = append(grandpaAuths, types.NewAuthority(key, uint64(auth.([]interface{})[1].(float64))))
+	}
+
+	gen := new(Genesis)
+	err = gen.SetBabeAuthorities(babeKeys)
+	require.NoError(t, err)
+	err = gen.SetGrandpaAuthorities(grandpaAuths)
+	require.NoError(t, err)
+
+	require.Len(t, gen.Genesis.Raw[0], 2)
+	for key, value := range gen.Genesis.Raw[0] {
+		require.Equal(t, expected.Genesis.Raw[0][key], value)
+	}
+
+	// the human readable authorities are set as well
+	require.Len(t, gen.Genesis.Runtime["babe"]["authorities"], len(babeKeys))
+	require.Len(t, gen.Genesis.Runtime["grandpa"]["authorities"], len(grandpaAuths))
+}
+
+func TestSetBalances(t *testing.T) {
+	gen := new(Genesis)
+	balance := new(big.Int).Lsh(big.NewInt(1), 70)
+	err := gen.SetBalances(map[common.Address]*big.Int{alice: balance})
+	require.NoError(t, err)
+
+	// System Account key of alice
+	key := "0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9de1e86a9a8c739864cf3cc5ec2bea59fd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"
+	require.Equal(t, "0x"+
+		"0000000000000000"+ // nonce and reference count
+		"00000000000000004000000000000000"+ // free
+		"00000000000000000000000000000000"+ // reserved
+		"00000000000000000000000000000000"+ // misc frozen
+		"00000000000000000000000000000000", // fee frozen
+		gen.Genesis.Raw[0][key])
+
+	// Balances TotalIssuance
+	issuanceKey := "0xc2261276cc9d1f8598ea4b6a74b15c2f57c875e4cff74148e4628f264b974c80"
+	require.Equal(t, "0x00000000000000004000000000000000", gen.Genesis.Raw[0][issuanceKey])
+	require.Equal(t, []*AccountBalance{{Address: alice, Balance: balance}}, gen.Genesis.Runtime["balances"]["balances"])
+
+	// replacing alice's balance and adding bob's keeps the issuance of the other accounts
+	gen.setRaw(issuanceKey, encodeU128(new(big.Int).Add(balance, big.NewInt(5))))
+	err = gen.SetBalances(map[common.Address]*big.Int{
+		alice: big.NewInt(2),
+		bob:   big.NewInt(3),
+	})
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHex(encodeU128(big.NewInt(10))), gen.Genesis.Raw[0][issuanceKey])
+	expected := []*AccountBalance{
+		{Address: bob, Balance: big.NewInt(3)},
+		{Address: alice, Balance: big.NewInt(2)},
+	}
+	require.Equal(t, expected, gen.Genesis.Runtime["balances"]["balances"])
+
+	err = gen.SetBalances(map[common.Address]*big.Int{alice: big.NewInt(-1)})
+	require.True(t, errors.Is(err, ErrInvalidBalance))
+
+	// the genesis isn't modified when the total issuance overflows
+	err = gen.SetBalances(map[common.Address]*big.Int{alice: maxBalance})
+	require.True(t, errors.Is(err, ErrInvalidBalance))
+	require.Equal(t, common.BytesToHex(encodeU128(big.NewInt(10))), gen.Genesis.Raw[0][issuanceKey])
+
+	err = gen.SetBalances(map[common.Address]*big.Int{"invalid": big.NewInt(1)})
+	require.True(t, errors.Is(err, crypto.ErrInvalidAddress))
+}
+
+func TestSetSudo(t *testing.T) {
+	gen := new(Genesis)
+	err := gen.SetSudo(alice)
+	require.NoError(t, err)
+	require.Equal(t, "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d", gen.Genesis.Raw[0]["0x5c0d1176a568c1f92944340dbfed9e9c530ebca703c85910e7164cb7d1c9e47b"])
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+var (
+	// ErrMissingName is returned when the genesis has no name
+	ErrMissingName = errors.New("genesis name is missing")
+	// ErrMissingID is returned when the genesis has no id
+	ErrMissingID = errors.New("genesis id is missing")
+	// ErrMissingCode is returned when the genesis has no runtime code
+	ErrMissingCode = errors.New("genesis runtime code is missing")
+	// ErrMissingPeerID is returned when a bootnode multiaddr has no /p2p component
+	ErrMissingPeerID = errors.New("multiaddr has no /p2p peer ID")
+	// ErrInvalidAuthorities is returned when the authorities of a runtime module aren't a list
+	ErrInvalidAuthorities = errors.New("authorities must be a list of [address, weight] pairs")
+)
+
+// BootnodeError is the error for an invalid bootnode multiaddr
+type BootnodeError struct {
+	Index    int
+	Bootnode string
+	Err      error
+}
+
+func (e *BootnodeError) Error() string {
+	return fmt.Sprintf("bootnode %d (%s): %s", e.Index, e.Bootnode, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *BootnodeError) Unwrap() error {
+	return e.Err
+}
+
+// AuthorityError is the error for an invalid authority of a runtime module
+type AuthorityError struct {
+	Module  string
+	Index   int
+	Address string
+	Err     error
+}
+
+func (e *AuthorityError) Error() string {
+	if e.Address == "" {
+		return fmt.Sprintf("%s authority %d: %s", e.Module, e.Index, e.Err)
+	}
+	return fmt.Sprintf("%s authority %d (%s): %s", e.Module, e.Index, e.Address, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *AuthorityError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError holds all the errors found when validating a genesis
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid genesis: %s", strings.Join(msgs, "; "))
+}
+
+// Validate checks that the genesis has a name, id and runtime code, that its bootnodes are valid multiaddrs and that
+// the babe and grandpa authorities have valid addresses. If any check fails, a *ValidationError holding every
+// failure is returned.
+func (g *Genesis) Validate() error {
+	var errs []error
+
+	if g.Name == "" {
+		errs = append(errs, ErrMissingName)
+	}
+
+	if g.ID == "" {
+		errs = append(errs, ErrMissingID)
+	}
+
+	for i, bn := range g.Bootnodes {
+		err := validateBootnode(bn)
+		if err != nil {
+			errs = append(errs, &BootnodeError{Index: i, Bootnode: bn, Err: err})
+		}
+	}
+
+	if !g.hasCode() {
+		errs = append(errs, ErrMissingCode)
+	}
+
+	if _, err := ParseCodeSubstitutes(g.CodeSubstitutes); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := g.BlockRules(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if g.LightSyncState != nil {
+		if _, err := g.LightSyncState.Checkpoint(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, module := range []string{"babe", "grandpa"} {
+		errs = append(errs, validateAuthorities(module, g.Genesis.Runtime[module])...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: errs}
+}
+
+func (g *Genesis) hasCode() bool {
+	if g.Genesis.Raw[0][common.BytesToHex(common.CodeKey)] != "" {
+		return true
+	}
+
+	code, _ := g.Genesis.Runtime["system"]["code"].(string)
+	return code != ""
+}
+
+func validateBootnode(bn string) error {
+	addr, err := ma.NewMultiaddr(bn)
+	if err != nil {
+		return err
+	}
+
+	_, err = addr.ValueForProtocol(ma.P_P2P)
+	if err != nil {
+		return ErrMissingPeerID
+	}
+
+	return nil
+}
+
+func validateAuthorities(module string, m map[string]interface{}) []error {
+	v, has := m["authorities"]
+	if !has {
+		return nil
+	}
+
+	authorities, ok := v.([]interface{})
+	if !ok {
+		return []error{&AuthorityError{Module: module, Err: ErrInvalidAuthorities}}
+	}
+
+	kt := authorityKeyTypes[module]
+
+	var errs []error
+	for i, auth := range authorities {
+		// authorities are usually [address, weight] pairs, but may also be flattened into a single list
+		fields, ok := auth.([]interface{})
+		if !ok {
+			fields = []interface{}{auth}
+		}
+
+		for _, f := range fields {
+			addr, ok := f.(string)
+			if !ok {
+				continue
+			}
+
+			_, err := authorityPublicKey(common.Address(addr), kt)
+			if err != nil {
+				errs = append(errs, &AuthorityError{Module: module, Index: i, Address: addr, Err: err})
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/crypto"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesis_Validate(t *testing.T) {
+	g := &Genesis{
+		Name:      "gossamer",
+		ID:        "gossamer",
+		Bootnodes: testBootnodes,
+		Genesis: Fields{
+			Runtime: map[string]map[string]interface{}{
+				"system": {"code": "0xfoo"},
+				"babe": {
+					"authorities": []interface{}{
+						[]interface{}{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", float64(1)},
+					},
+				},
+				"grandpa": {
+					"authorities": []interface{}{"5DFNv4Txc4b88qHqQ6GG4D646QcT4fN3jjS2G3r1PyZkfDut", float64(0)},
+				},
+			},
+		},
+	}
+	require.NoError(t, g.Validate())
+
+	raw := &Genesis{
+		Name:    "gossamer",
+		ID:      "gossamer",
+		Genesis: TestFieldsRaw,
+	}
+	require.NoError(t, raw.Validate())
+}
+
+func TestGenesis_Validate_Errors(t *testing.T) {
+	g := &Genesis{
+		Bootnodes: []string{
+			testBootnodes[0],
+			"not a multiaddr",
+			"/ip4/127.0.0.1/tcp/7001",
+		},
+		Genesis: Fields{
+			Runtime: map[string]map[string]interface{}{
+				"babe": {
+					"authorities": []interface{}{
+						[]interface{}{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", float64(1)},
+						[]interface{}{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ", float64(1)},
+					},
+				},
+				"grandpa": {
+					"authorities": "5DFNv4Txc4b88qHqQ6GG4D646QcT4fN3jjS2G3r1PyZkfDut",
+				},
+			},
+		},
+	}
+
+	err := g.Validate()
+	require.Error(t, err)
+
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok)
+	require.Len(t, verr.Errors, 7)
+
+	require.Equal(t, ErrMissingName, verr.Errors[0])
+	require.Equal(t, ErrMissingID, verr.Errors[1])
+
+	bnErr, ok := verr.Errors[2].(*BootnodeError)
+	require.True(t, ok)
+	require.Equal(t, 1, bnErr.Index)
+
+	bnErr, ok = verr.Errors[3].(*BootnodeError)
+	require.True(t, ok)
+	require.Equal(t, 2, bnErr.Index)
+	require.True(t, errors.Is(bnErr, ErrMissingPeerID))
+
+	require.Equal(t, ErrMissingCode, verr.Errors[4])
+
+	authErr, ok := verr.Errors[5].(*AuthorityError)
+	require.True(t, ok)
+	require.Equal(t, "babe", authErr.Module)
+	require.Equal(t, 1, authErr.Index)
+	require.True(t, errors.Is(authErr, crypto.ErrInvalidAddressChecksum))
+
+	authErr, ok = verr.Errors[6].(*AuthorityError)
+	require.True(t, ok)
+	require.Equal(t, "grandpa", authErr.Module)
+	require.True(t, errors.Is(authErr, ErrInvalidAuthorities))
+}
+
+func TestGenesis_Validate_HexAuthorities(t *testing.T) {
+	// 0xff..ff is a valid ed25519 key, but not a valid ristretto point
+	invalidPoint := "0x" + strings.Repeat("ff", 32)
+
+	g := &Genesis{
+		Name: "gossamer",
+		ID:   "gossamer",
+		Genesis: Fields{
+			Runtime: map[string]map[string]interface{}{
+				"system": {"code": "0xfoo"},
+				"babe": {
+					"authorities": []interface{}{
+						[]interface{}{"0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d", float64(1)},
+					},
+				},
+				"grandpa": {
+					"authorities": []interface{}{
+						[]interface{}{"0x34602b88f60513f1c805d87ef52896934baf6a662bc37414dbdbf69356b1a691", float64(1)},
+						[]interface{}{invalidPoint, float64(1)},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, g.Validate())
+
+	g.Genesis.Runtime["babe"]["authorities"] = []interface{}{[]interface{}{invalidPoint, float64(1)}}
+	err := g.Validate()
+	require.Error(t, err)
+
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok)
+	require.Len(t, verr.Errors, 1)
+	require.True(t, errors.Is(verr.Errors[0], ErrInvalidPublicKey))
+}
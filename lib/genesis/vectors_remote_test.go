@@ -0,0 +1,96 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build integration
+// +build integration
+
+package genesis
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// remoteGenesisVectors are the vectors of the chains whose raw specs are too large to keep in the repo, the specs
+// are fetched from url, so these tests need network access and only run with the integration build tag
+var remoteGenesisVectors = []struct {
+	genesisVector
+	url string
+}{
+	{
+		genesisVector: genesisVector{
+			name: "polkadot",
+			hash: "0x91b171bb158e2d3848fa23a9f1c25182fb8e20313b2c1eb49219da7a70ce90c3",
+		},
+		url: "https://raw.githubusercontent.com/paritytech/polkadot/v0.8.30/node/service/res/polkadot.json",
+	},
+	{
+		genesisVector: genesisVector{
+			name: "westend",
+			hash: "0xe143f23803ac50e8f6f8e62695d1ce9e4e1d68aa36c1cd2cfd15340213f3423e",
+		},
+		url: "https://raw.githubusercontent.com/paritytech/polkadot/v0.8.30/node/service/res/westend.json",
+	},
+}
+
+// fetchChainSpec returns the path of the raw chain spec of the given chain, downloading it from url if it isn't
+// cached yet
+func fetchChainSpec(t *testing.T, name, url string) string {
+	dir := filepath.Join(os.TempDir(), "gossamer-chain-specs")
+	fp := filepath.Join(dir, name+"-raw.json")
+	if _, err := os.Stat(fp); err == nil {
+		return fp
+	}
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	require.NoError(t, err)
+
+	/* #nosec */
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "failed to fetch %s", url)
+
+	// the spec is only moved to the cache once it's complete, so that an interrupted download isn't used
+	tmp, err := ioutil.TempFile(dir, name)
+	require.NoError(t, err)
+
+	_, err = io.Copy(tmp, resp.Body)
+	require.NoError(t, err)
+	err = tmp.Close()
+	require.NoError(t, err)
+
+	err = os.Rename(tmp.Name(), fp)
+	require.NoError(t, err)
+	return fp
+}
+
+func TestGenesisVectors_Remote(t *testing.T) {
+	for _, v := range remoteGenesisVectors {
+		v := v
+		t.Run(v.name, func(t *testing.T) {
+			checkGenesisVector(t, v.genesisVector, fetchChainSpec(t, v.name, v.url))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+// genesisVector is the genesis state root and block hash of a chain as computed by the reference
+// implementation. The header hash commits to the state root, so the state root is optional. The vectors of the
+// chains whose raw specs aren't in the repo are in vectors_remote_test.go.
+type genesisVector struct {
+	name      string
+	file      string
+	stateRoot string
+	hash      string
+}
+
+var genesisVectors = []genesisVector{
+	{
+		name:      "kusama",
+		file:      "../../chain/ksmcc/genesis-raw.json",
+		stateRoot: "0xb0006203c3a6e6bd2c6a17b1d4ae8ca49a31da0f4579da950b127774b44aef6b",
+		hash:      "0xb0a8d493285c2df73290dfb7e61f870f17b41801197a149ca93654499ea3dafe",
+	},
+}
+
+// checkGenesisVector checks the genesis block built from the raw chain spec at the path against the vector
+func checkGenesisVector(t *testing.T, v genesisVector, file string) {
+	gen, err := NewGenesisFromJSONRaw(file)
+	require.NoError(t, err)
+
+	tr, err := NewTrieFromGenesis(gen)
+	require.NoError(t, err)
+
+	header, err := NewGenesisBlockFromTrie(tr)
+	require.NoError(t, err)
+
+	if v.stateRoot != "" {
+		require.Equal(t, common.MustHexToHash(v.stateRoot), header.StateRoot)
+	}
+	require.Equal(t, common.MustHexToHash(v.hash), header.Hash())
+
+	hash, err := GenesisHash(gen, common.Blake2bHash)
+	require.NoError(t, err)
+	require.Equal(t, common.MustHexToHash(v.hash), hash)
+}
+
+func TestGenesisVectors(t *testing.T) {
+	for _, v := range genesisVectors {
+		v := v
+		t.Run(v.name, func(t *testing.T) {
+			checkGenesisVector(t, v, v.file)
+		})
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"math/big"
 	"os"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -49,8 +48,10 @@ type Service struct {
 	chanLock      sync.Mutex
 	roundLock     sync.Mutex
 	authority     bool          // run the service as an authority (ie participate in voting)
-	paused        atomic.Value  // the service will be paused if it is waiting for catch up responses
-	resumed       chan struct{} // this channel will be closed when the service resumes
+	pauseLock     sync.Mutex    // protects paused, votingPaused and resumed
+	paused        bool          // the service will be paused if it is waiting for catch up responses
+	votingPaused  bool          // voting is paused by a Pause digest until a Resume digest is enacted
+	resumed       chan struct{} // this channel is closed and replaced whenever either pause ends
 
 	// current state information
 	state            *State                             // current state
@@ -146,7 +147,6 @@ func NewService(cfg *Config) (*Service, error) {
 		resumed:            make(chan struct{}),
 	}
 
-	s.paused.Store(false)
 	return s, nil
 }
 
@@ -186,7 +186,7 @@ func (s *Service) Authorities() []*types.Authority {
 	for i, v := range s.state.voters {
 		ad[i] = &types.Authority{
 			Key:    v.key,
-			Weight: v.id,
+			Weight: v.weight,
 		}
 	}
 
@@ -195,17 +195,7 @@ func (s *Service) Authorities() []*types.Authority {
 
 // UpdateAuthorities schedules an update to the grandpa voter set and increments the setID at the end of the current round
 func (s *Service) UpdateAuthorities(ad []*types.Authority) {
-	v := make([]*Voter, len(ad))
-	for i, a := range ad {
-		if pk, ok := a.Key.(*ed25519.PublicKey); ok {
-			v[i] = &Voter{
-				key: pk,
-				id:  a.Weight,
-			}
-		}
-	}
-
-	s.nextAuthorities = v
+	s.nextAuthorities = NewVotersFromAuthorityData(ad)
 }
 
 // updateAuthorities updates the grandpa voter set, increments the setID, and resets the round numbers
@@ -218,6 +208,99 @@ func (s *Service) updateAuthorities() {
 	}
 }
 
+// PauseVoting stops the service from casting votes until ResumeVoting is called. The authority set is unchanged,
+// and blocks continue to be imported while voting is paused.
+func (s *Service) PauseVoting() {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+
+	if s.votingPaused {
+		return
+	}
+
+	s.logger.Info("pausing voting", "round", s.state.round, "setID", s.state.setID)
+	s.votingPaused = true
+}
+
+// ResumeVoting resumes voting with the same authority set after PauseVoting
+func (s *Service) ResumeVoting() {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+
+	if !s.votingPaused {
+		return
+	}
+
+	s.logger.Info("resuming voting", "setID", s.state.setID)
+	s.votingPaused = false
+	s.signalResumed()
+}
+
+// pauseForCatchUp pauses the service while it waits for a catch up response. It returns false if the service
+// was already waiting for one.
+func (s *Service) pauseForCatchUp() bool {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+
+	if s.paused {
+		return false
+	}
+
+	s.paused = true
+	return true
+}
+
+// resumeAfterCatchUp resumes the service once it's caught up
+func (s *Service) resumeAfterCatchUp() {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+
+	s.paused = false
+	s.signalResumed()
+}
+
+// signalResumed wakes up waitForResume, s.pauseLock must be held
+func (s *Service) signalResumed() {
+	close(s.resumed)
+	s.resumed = make(chan struct{})
+}
+
+// isCatchingUp returns true if the service is waiting for a catch up response
+func (s *Service) isCatchingUp() bool {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+	return s.paused
+}
+
+// isPaused returns true if the service is waiting for catch up responses or voting is paused
+func (s *Service) isPaused() bool {
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+	return s.paused || s.votingPaused
+}
+
+// waitForResume blocks until the service is neither waiting for catch up responses nor paused by a Pause digest,
+// or until the service is stopped
+func (s *Service) waitForResume() {
+	for {
+		s.pauseLock.Lock()
+		if !s.paused && !s.votingPaused {
+			s.pauseLock.Unlock()
+			return
+		}
+
+		// the channel is read under the lock, so a resume can't be missed between the check and the wait
+		resumed := s.resumed
+		s.pauseLock.Unlock()
+
+		select {
+		case <-resumed:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
 func (s *Service) publicKeyBytes() ed25519.PublicKeyBytes {
 	return s.keypair.Public().(*ed25519.PublicKey).AsBytes()
 }
@@ -280,7 +363,7 @@ func (s *Service) initiate() error {
 			err = s.playGrandpaRound()
 			if err == ErrServicePaused {
 				// wait for service to un-pause
-				<-s.resumed
+				s.waitForResume()
 				err = s.initiate()
 			}
 
@@ -386,7 +469,7 @@ func (s *Service) playGrandpaRound() error {
 	s.logger.Debug("receiving pre-vote messages...")
 
 	go s.receiveMessages(func() bool {
-		if s.paused.Load().(bool) {
+		if s.isPaused() {
 			return true
 		}
 
@@ -406,7 +489,7 @@ func (s *Service) playGrandpaRound() error {
 
 	time.Sleep(interval * 2)
 
-	if s.paused.Load().(bool) {
+	if s.isPaused() {
 		return ErrServicePaused
 	}
 
@@ -426,7 +509,7 @@ func (s *Service) playGrandpaRound() error {
 	// continue to send prevote messages until round is done
 	go func(finalized *bool) {
 		for {
-			if s.paused.Load().(bool) {
+			if s.isPaused() {
 				return
 			}
 
@@ -463,7 +546,7 @@ func (s *Service) playGrandpaRound() error {
 
 	time.Sleep(interval * 2)
 
-	if s.paused.Load().(bool) {
+	if s.isPaused() {
 		return ErrServicePaused
 	}
 
@@ -481,7 +564,7 @@ func (s *Service) playGrandpaRound() error {
 	// continue to send precommit messages until round is done
 	go func(finalized *bool) {
 		for {
-			if s.paused.Load().(bool) {
+			if s.isPaused() {
 				return
 			}
 
@@ -504,7 +587,7 @@ func (s *Service) playGrandpaRound() error {
 		// and the last finalized block is greater than the best final candidate from the previous round
 		s.receiveMessages(func() bool {
 			//return false
-			if s.paused.Load().(bool) {
+			if s.isPaused() {
 				return true
 			}
 
@@ -548,7 +631,7 @@ func (s *Service) playGrandpaRound() error {
 
 // attemptToFinalize loops until the round is finalizable
 func (s *Service) attemptToFinalize() error {
-	if s.paused.Load().(bool) {
+	if s.isPaused() {
 		return ErrServicePaused
 	}
 
@@ -714,17 +797,23 @@ func (s *Service) finalize() error {
 	// set justification
 	s.justification[s.state.round] = s.pcJustifications[bfc.hash]
 
-	pvj, err := newFullJustification(s.pvJustifications[bfc.hash]).Encode()
+	just, err := newGrandpaJustification(s.state.round, bfc, s.pcJustifications[bfc.hash]).Encode()
+	if err != nil {
+		return err
+	}
+
+	err = s.blockState.SetJustification(bfc.hash, just)
 	if err != nil {
 		return err
 	}
 
-	pcj, err := newFullJustification(s.pcJustifications[bfc.hash]).Encode()
+	// the pre-votes aren't part of the justification, but are needed to respond to catch up requests
+	pvj, err := newFullJustification(s.pvJustifications[bfc.hash]).Encode()
 	if err != nil {
 		return err
 	}
 
-	err = s.blockState.SetJustification(bfc.hash, append(pvj, pcj...))
+	err = s.blockState.SetPrevotes(bfc.hash, pvj)
 	if err != nil {
 		return err
 	}
@@ -848,7 +937,7 @@ func (s *Service) isCompletable() (bool, error) {
 			return false, err
 		}
 
-		if c > s.state.threshold() {
+		if c >= s.state.threshold() {
 			// round isn't completable
 			return false, nil
 		}
@@ -1024,8 +1113,8 @@ func (s *Service) getPossibleSelectedAncestors(votes []Vote, curr common.Hash, s
 	return selected, nil
 }
 
-// getTotalVotesForBlock returns the total number of observed votes for a block B in a subround, which is equal
-// to the direct votes for B and B's descendants plus the total number of equivocating voters
+// getTotalVotesForBlock returns the total weight of observed votes for a block B in a subround, which is equal
+// to the direct votes for B and B's descendants plus the weight of the equivocating voters
 func (s *Service) getTotalVotesForBlock(hash common.Hash, stage subround) (uint64, error) {
 	// observed votes for block
 	dv, err := s.getVotesForBlock(hash, stage)
@@ -1034,14 +1123,22 @@ func (s *Service) getTotalVotesForBlock(hash common.Hash, stage subround) (uint6
 	}
 
 	// equivocatory votes
-	var ev int
+	s.mapLock.Lock()
+	defer s.mapLock.Unlock()
+
+	var eqv map[ed25519.PublicKeyBytes][]*Vote
 	if stage == prevote {
-		ev = len(s.pvEquivocations)
+		eqv = s.pvEquivocations
 	} else {
-		ev = len(s.pcEquivocations)
+		eqv = s.pcEquivocations
+	}
+
+	var ev uint64
+	for voter := range eqv {
+		ev += s.state.voterWeight(voter)
 	}
 
-	return dv + uint64(ev), nil
+	return dv + ev, nil
 }
 
 // getVotesForBlock returns the number of observed votes for a block B.
@@ -1073,7 +1170,7 @@ func (s *Service) getVotesForBlock(hash common.Hash, stage subround) (uint64, er
 	return votesForBlock, nil
 }
 
-// getDirectVotes returns a map of Votes to direct vote counts
+// getDirectVotes returns a map of Votes to the total weight of the voters that voted for them directly
 func (s *Service) getDirectVotes(stage subround) map[Vote]uint64 {
 	votes := make(map[Vote]uint64)
 
@@ -1087,8 +1184,8 @@ func (s *Service) getDirectVotes(stage subround) map[Vote]uint64 {
 	s.mapLock.Lock()
 	defer s.mapLock.Unlock()
 
-	for _, v := range src {
-		votes[*v]++
+	for voter, v := range src {
+		votes[*v] += s.state.voterWeight(voter)
 	}
 
 	return votes
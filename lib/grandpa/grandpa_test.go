@@ -19,7 +19,7 @@ package grandpa
 import (
 	"math/big"
 	"math/rand"
-	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -73,8 +73,9 @@ func newTestVoters() []*Voter {
 	voters := []*Voter{}
 	for i, k := range kr.Keys {
 		voters = append(voters, &Voter{
-			key: k.Public().(*ed25519.PublicKey),
-			id:  uint64(i),
+			key:    k.Public().(*ed25519.PublicKey),
+			id:     uint64(i),
+			weight: 1,
 		})
 	}
 
@@ -103,6 +104,7 @@ func TestUpdateAuthorities(t *testing.T) {
 	gs, _ := newTestService(t)
 	gs.UpdateAuthorities([]*types.Authority{
 		{Key: kr.Alice().Public().(*ed25519.PublicKey), Weight: 0},
+		{Key: kr.Bob().Public().(*ed25519.PublicKey), Weight: 3},
 	})
 
 	err := gs.Start()
@@ -111,8 +113,11 @@ func TestUpdateAuthorities(t *testing.T) {
 	time.Sleep(time.Second)
 	require.Equal(t, uint64(1), gs.state.setID)
 	require.Equal(t, []*Voter{
-		{key: kr.Alice().Public().(*ed25519.PublicKey), id: 0},
+		{key: kr.Alice().Public().(*ed25519.PublicKey), id: 0, weight: 1},
+		{key: kr.Bob().Public().(*ed25519.PublicKey), id: 1, weight: 3},
 	}, gs.state.voters)
+	require.Equal(t, uint64(4), gs.state.totalWeight())
+	require.Equal(t, uint64(3), gs.Authorities()[1].Weight)
 
 	gs.UpdateAuthorities([]*types.Authority{
 		{Key: kr.Alice().Public().(*ed25519.PublicKey), Weight: 0},
@@ -122,6 +127,71 @@ func TestUpdateAuthorities(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPauseAndResumeVoting(t *testing.T) {
+	gs, _ := newTestService(t)
+	setID := gs.state.setID
+	voters := gs.state.voters
+
+	gs.PauseVoting()
+	require.True(t, gs.isPaused())
+	resumed := gs.resumed
+
+	gs.ResumeVoting()
+	require.False(t, gs.isPaused())
+	require.Equal(t, setID, gs.state.setID)
+	require.Equal(t, voters, gs.state.voters)
+
+	select {
+	case <-resumed:
+	default:
+		t.Fatal("resume channel was not closed")
+	}
+}
+
+func TestWaitForResume_ConcurrentPauseAndResume(t *testing.T) {
+	gs, _ := newTestService(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			gs.waitForResume()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				gs.PauseVoting()
+				gs.ResumeVoting()
+			}
+		}()
+	}
+
+	// pausing for a catch up at the same time mustn't lose either resume
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			if gs.pauseForCatchUp() {
+				gs.resumeAfterCatchUp()
+			}
+		}
+	}()
+
+	wg.Wait()
+	require.False(t, gs.isPaused())
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("waitForResume didn't return once the service was resumed")
+	}
+}
+
 func TestGetDirectVotes(t *testing.T) {
 	gs, _ := newTestService(t)
 
@@ -151,6 +221,42 @@ func TestGetDirectVotes(t *testing.T) {
 	require.Equal(t, uint64(4), directVotes[*voteB])
 }
 
+func TestGetDirectVotes_Weighted(t *testing.T) {
+	gs, _ := newTestService(t)
+	gs.state.voters[0].weight = 10
+
+	voteA := &Vote{
+		hash:   common.Hash{0xa},
+		number: 1,
+	}
+
+	voteB := &Vote{
+		hash:   common.Hash{0xb},
+		number: 1,
+	}
+
+	for i, k := range kr.Keys {
+		voter := k.Public().(*ed25519.PublicKey).AsBytes()
+
+		if i < 2 {
+			gs.prevotes[voter] = voteA
+		} else {
+			gs.prevotes[voter] = voteB
+		}
+	}
+
+	directVotes := gs.getDirectVotes(prevote)
+	require.Equal(t, uint64(11), directVotes[*voteA])
+	require.Equal(t, uint64(7), directVotes[*voteB])
+
+	// the heavier voter equivocating counts with its full weight
+	gs.pvEquivocations[kr.Keys[0].Public().(*ed25519.PublicKey).AsBytes()] = []*Vote{voteA, voteB}
+	delete(gs.prevotes, kr.Keys[0].Public().(*ed25519.PublicKey).AsBytes())
+	total, err := gs.getTotalVotesForBlock(voteB.hash, prevote)
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), total)
+}
+
 func TestGetVotesForBlock_NoDescendantVotes(t *testing.T) {
 	gs, st := newTestService(t)
 
@@ -310,12 +416,14 @@ func TestGetPossibleSelectedAncestors_VaryingAncestor(t *testing.T) {
 		}
 	}
 
+	// select the blocks that 2/3 of the voters voted for, which is less than the supermajority threshold
+	threshold := 2 * gs.state.totalWeight() / 3
 	votes := gs.getVotes(prevote)
 	prevoted := make(map[common.Hash]uint64)
 	var blocks map[common.Hash]uint64
 
 	for _, curr := range leaves {
-		blocks, err = gs.getPossibleSelectedAncestors(votes, curr, prevoted, prevote, gs.state.threshold())
+		blocks, err = gs.getPossibleSelectedAncestors(votes, curr, prevoted, prevote, threshold)
 		require.NoError(t, err)
 	}
 
@@ -370,12 +478,14 @@ func TestGetPossibleSelectedAncestors_VaryingAncestor_MoreBranches(t *testing.T)
 		}
 	}
 
+	// select the blocks that 2/3 of the voters voted for, which is less than the supermajority threshold
+	threshold := 2 * gs.state.totalWeight() / 3
 	votes := gs.getVotes(prevote)
 	prevoted := make(map[common.Hash]uint64)
 	var blocks map[common.Hash]uint64
 
 	for _, curr := range leaves {
-		blocks, err = gs.getPossibleSelectedAncestors(votes, curr, prevoted, prevote, gs.state.threshold())
+		blocks, err = gs.getPossibleSelectedAncestors(votes, curr, prevoted, prevote, threshold)
 		require.NoError(t, err)
 	}
 
@@ -408,7 +518,7 @@ func TestGetPossibleSelectedBlocks_OneBlock(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 		} else {
 			gs.prevotes[voter] = voteB
@@ -495,7 +605,9 @@ func TestGetPossibleSelectedBlocks_EqualVotes_VaryingAncestor(t *testing.T) {
 		}
 	}
 
-	blocks, err := gs.getPossibleSelectedBlocks(prevote, gs.state.threshold())
+	// select the blocks that 2/3 of the voters voted for, which is less than the supermajority threshold
+	threshold := 2 * gs.state.totalWeight() / 3
+	blocks, err := gs.getPossibleSelectedBlocks(prevote, threshold)
 	require.NoError(t, err)
 
 	expectedAt6, err := common.HexToHash("0x32ed981734053dc565a1e224137d751f24917a1cb2aeea56fd44a06629550a23")
@@ -537,7 +649,9 @@ func TestGetPossibleSelectedBlocks_OneThirdEquivocating(t *testing.T) {
 		}
 	}
 
-	blocks, err := gs.getPossibleSelectedBlocks(prevote, gs.state.threshold())
+	// select the blocks that 2/3 of the voters voted for, which is less than the supermajority threshold
+	threshold := 2 * gs.state.totalWeight() / 3
+	blocks, err := gs.getPossibleSelectedBlocks(prevote, threshold)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(blocks))
 }
@@ -577,7 +691,9 @@ func TestGetPossibleSelectedBlocks_MoreThanOneThirdEquivocating(t *testing.T) {
 		}
 	}
 
-	blocks, err := gs.getPossibleSelectedBlocks(prevote, gs.state.threshold())
+	// select the blocks that 2/3 of the voters voted for, which is less than the supermajority threshold
+	threshold := 2 * gs.state.totalWeight() / 3
+	blocks, err := gs.getPossibleSelectedBlocks(prevote, threshold)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(blocks))
 }
@@ -598,7 +714,7 @@ func TestGetPreVotedBlock_OneBlock(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 		} else {
 			gs.prevotes[voter] = voteB
@@ -622,30 +738,33 @@ func TestGetPreVotedBlock_MultipleCandidates(t *testing.T) {
 	leaves := gs.blockState.Leaves()
 	require.Equal(t, 3, len(leaves))
 
-	// 1/3 voters each vote for a block on a different chain
-	voteA, err := NewVoteFromHash(leaves[0], st.Block)
+	// expected block is that with the highest number ie. at depth 7
+	expected, err := common.HexToHash("0x57508d4d2c5b01e6bd50dacee5d14979a6f23e41d4b4eb6464a8a29015549847")
 	require.NoError(t, err)
-	voteB, err := NewVoteFromHash(leaves[1], st.Block)
+
+	descendants, others := partitionLeaves(t, st.Block, expected, leaves)
+	require.Equal(t, 2, len(descendants))
+
+	// voters vote for a block on a different chain, with more than 2/3 of them on the chains through block 7
+	voteA, err := NewVoteFromHash(descendants[0], st.Block)
 	require.NoError(t, err)
-	voteC, err := NewVoteFromHash(leaves[2], st.Block)
+	voteB, err := NewVoteFromHash(descendants[1], st.Block)
+	require.NoError(t, err)
+	voteC, err := NewVoteFromHash(others[0], st.Block)
 	require.NoError(t, err)
 
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 3 {
+		if i < 4 {
 			gs.prevotes[voter] = voteA
-		} else if i < 6 {
+		} else if i < 7 {
 			gs.prevotes[voter] = voteB
 		} else {
 			gs.prevotes[voter] = voteC
 		}
 	}
 
-	// expected block is that with the highest number ie. at depth 7
-	expected, err := common.HexToHash("0x57508d4d2c5b01e6bd50dacee5d14979a6f23e41d4b4eb6464a8a29015549847")
-	require.NoError(t, err)
-
 	block, err := gs.getPreVotedBlock()
 	require.NoError(t, err)
 	require.Equal(t, expected, block.hash)
@@ -667,22 +786,26 @@ func TestGetPreVotedBlock_EvenMoreCandidates(t *testing.T) {
 	leaves := gs.blockState.Leaves()
 	require.Equal(t, 6, len(leaves))
 
-	sort.Slice(leaves, func(i, j int) bool {
-		return leaves[i][0] < leaves[j][0]
-	})
+	// expected block is at depth 5
+	expected, err := common.HexToHash("0xd01a209a130af98b3375cf9a571e92b7c0fc8b61dee7917f852a673fcb57ac19")
+	require.NoError(t, err)
 
-	// voters vote for a blocks on a different chains
-	voteA, err := NewVoteFromHash(leaves[0], st.Block)
+	descendants, others := partitionLeaves(t, st.Block, expected, leaves)
+	require.Equal(t, 4, len(descendants))
+
+	// voters vote for a blocks on a different chains, with more than 2/3 of them on the chains through block 5
+	// and no more than 2/3 of them on the chains through any block above it
+	voteA, err := NewVoteFromHash(descendants[0], st.Block)
 	require.NoError(t, err)
-	voteB, err := NewVoteFromHash(leaves[1], st.Block)
+	voteB, err := NewVoteFromHash(descendants[1], st.Block)
 	require.NoError(t, err)
-	voteC, err := NewVoteFromHash(leaves[2], st.Block)
+	voteC, err := NewVoteFromHash(descendants[2], st.Block)
 	require.NoError(t, err)
-	voteD, err := NewVoteFromHash(leaves[3], st.Block)
+	voteD, err := NewVoteFromHash(descendants[3], st.Block)
 	require.NoError(t, err)
-	voteE, err := NewVoteFromHash(leaves[4], st.Block)
+	voteE, err := NewVoteFromHash(others[0], st.Block)
 	require.NoError(t, err)
-	voteF, err := NewVoteFromHash(leaves[5], st.Block)
+	voteF, err := NewVoteFromHash(others[1], st.Block)
 	require.NoError(t, err)
 
 	for i, k := range kr.Keys {
@@ -705,16 +828,29 @@ func TestGetPreVotedBlock_EvenMoreCandidates(t *testing.T) {
 
 	t.Log(st.Block.BlocktreeAsString())
 
-	// expected block is at depth 5
-	expected, err := common.HexToHash("0xd01a209a130af98b3375cf9a571e92b7c0fc8b61dee7917f852a673fcb57ac19")
-	require.NoError(t, err)
-
 	block, err := gs.getPreVotedBlock()
 	require.NoError(t, err)
 	require.Equal(t, expected, block.hash)
 	require.Equal(t, uint64(5), block.number)
 }
 
+// partitionLeaves splits the leaves into those that descend from the given block and the others
+func partitionLeaves(t *testing.T, bs *state.BlockState, ancestor common.Hash,
+	leaves []common.Hash) (descendants, others []common.Hash) {
+	for _, leaf := range leaves {
+		isDescendant, err := bs.IsDescendantOf(ancestor, leaf)
+		require.NoError(t, err)
+
+		if isDescendant {
+			descendants = append(descendants, leaf)
+		} else {
+			others = append(others, leaf)
+		}
+	}
+
+	return descendants, others
+}
+
 func TestIsCompletable(t *testing.T) {
 	gs, st := newTestService(t)
 
@@ -731,7 +867,7 @@ func TestIsCompletable(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 		} else {
 			gs.prevotes[voter] = voteB
@@ -781,7 +917,7 @@ func TestGetBestFinalCandidate_OneBlock(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 			gs.precommits[voter] = voteA
 		} else {
@@ -809,14 +945,14 @@ func TestGetBestFinalCandidate_PrecommitAncestor(t *testing.T) {
 	voteB, err := NewVoteFromHash(leaves[1], st.Block)
 	require.NoError(t, err)
 
-	// in precommit round, 2/3 voters will vote for ancestor of A
+	// in precommit round, more than 2/3 voters will vote for ancestor of A
 	voteC, err := gs.findParentWithNumber(voteA, 6)
 	require.NoError(t, err)
 
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 			gs.precommits[voter] = voteC
 		} else {
@@ -848,7 +984,7 @@ func TestGetBestFinalCandidate_NoPrecommit(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 		} else {
 			gs.prevotes[voter] = voteB
@@ -879,7 +1015,7 @@ func TestGetBestFinalCandidate_PrecommitOnAnotherChain(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 			gs.precommits[voter] = voteB
 		} else {
@@ -959,7 +1095,7 @@ func TestIsFinalizable_True(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 			gs.precommits[voter] = voteA
 		} else {
@@ -989,7 +1125,7 @@ func TestIsFinalizable_False(t *testing.T) {
 	for i, k := range kr.Keys {
 		voter := k.Public().(*ed25519.PublicKey).AsBytes()
 
-		if i < 6 {
+		if i < 7 {
 			gs.prevotes[voter] = voteA
 			gs.precommits[voter] = voteA
 		} else {
@@ -1010,6 +1146,46 @@ func TestIsFinalizable_False(t *testing.T) {
 	require.False(t, finalizable)
 }
 
+func TestIsFinalizable_Weighted(t *testing.T) {
+	gs, st := newTestService(t)
+
+	// the first voter has 10 of the 18 total weight, so it takes 13 to finalize a block
+	gs.state.voters[0].weight = 10
+	require.Equal(t, uint64(13), gs.state.threshold())
+
+	branches := make(map[int]int)
+	branches[6] = 1
+	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches, byte(rand.Intn(256)))
+	leaves := gs.blockState.Leaves()
+
+	voteA, err := NewVoteFromHash(leaves[0], st.Block)
+	require.NoError(t, err)
+
+	for i, k := range kr.Keys {
+		voter := k.Public().(*ed25519.PublicKey).AsBytes()
+		gs.prevotes[voter] = voteA
+
+		// exactly 2/3 of the weight pre-commits for A
+		if i < 3 {
+			gs.precommits[voter] = voteA
+		}
+	}
+
+	gs.state.round = 1
+	gs.bestFinalCandidate[0] = &Vote{}
+	gs.preVotedBlock[gs.state.round] = voteA
+
+	finalizable, err := gs.isFinalizable(gs.state.round)
+	require.NoError(t, err)
+	require.False(t, finalizable)
+
+	gs.precommits[kr.Keys[3].Public().(*ed25519.PublicKey).AsBytes()] = voteA
+
+	finalizable, err = gs.isFinalizable(gs.state.round)
+	require.NoError(t, err)
+	require.True(t, finalizable)
+}
+
 func TestGetGrandpaGHOST_CommonAncestor(t *testing.T) {
 	gs, st := newTestService(t)
 
@@ -0,0 +1,241 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package grandpa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// ErrInvalidJustification is returned when a stored justification is malformed or doesn't justify its block
+var ErrInvalidJustification = errors.New("invalid justification")
+
+// Commit is the commit of a GRANDPA round: the block it finalized and the signed pre-commits that justify it
+type Commit struct {
+	Hash       common.Hash
+	Number     uint32
+	Precommits []*Justification
+}
+
+// GrandpaJustification is the justification stored with a finalized block and served to peers and RPC clients.
+// It has the layout of Substrate's GrandpaJustification, which uses 32-bit block numbers, so that it can be used
+// as a finality proof by other implementations.
+type GrandpaJustification struct {
+	Round           uint64
+	Commit          *Commit
+	VotesAncestries []*types.Header
+}
+
+// newGrandpaJustification returns the justification of the given round finalizing the given vote
+func newGrandpaJustification(round uint64, vote *Vote, precommits []*Justification) *GrandpaJustification {
+	return &GrandpaJustification{
+		Round: round,
+		Commit: &Commit{
+			Hash:       vote.hash,
+			Number:     uint32(vote.number),
+			Precommits: precommits,
+		},
+		VotesAncestries: []*types.Header{},
+	}
+}
+
+// Encode returns the SCALE encoding of the GrandpaJustification
+func (j *GrandpaJustification) Encode() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, j.Round)
+	_, _ = buf.Write(j.Commit.Hash[:])
+	_ = binary.Write(buf, binary.LittleEndian, j.Commit.Number)
+
+	if err := writeLength(buf, len(j.Commit.Precommits)); err != nil {
+		return nil, err
+	}
+
+	for _, pc := range j.Commit.Precommits {
+		_, _ = buf.Write(pc.Vote.hash[:])
+		_ = binary.Write(buf, binary.LittleEndian, uint32(pc.Vote.number))
+		_, _ = buf.Write(pc.Signature[:])
+		_, _ = buf.Write(pc.AuthorityID[:])
+	}
+
+	if err := writeLength(buf, len(j.VotesAncestries)); err != nil {
+		return nil, err
+	}
+
+	for _, header := range j.VotesAncestries {
+		enc, err := header.Encode()
+		if err != nil {
+			return nil, err
+		}
+		_, _ = buf.Write(enc)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode returns the SCALE decoded GrandpaJustification
+func (j *GrandpaJustification) Decode(r io.Reader) (*GrandpaJustification, error) {
+	if j == nil {
+		j = new(GrandpaJustification)
+	}
+
+	var err error
+	j.Round, err = common.ReadUint64(r)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Commit = new(Commit)
+	j.Commit.Hash, err = common.ReadHash(r)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Commit.Number, err = common.ReadUint32(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sd := &scale.Decoder{Reader: r}
+	n, err := sd.DecodeInteger()
+	if err != nil {
+		return nil, err
+	}
+
+	j.Commit.Precommits = []*Justification{}
+	for i := int64(0); i < n; i++ {
+		pc := &Justification{
+			Vote: new(Vote),
+		}
+
+		pc.Vote.hash, err = common.ReadHash(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var number uint32
+		number, err = common.ReadUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		pc.Vote.number = uint64(number)
+
+		if _, err = io.ReadFull(r, pc.Signature[:]); err != nil {
+			return nil, err
+		}
+
+		if _, err = io.ReadFull(r, pc.AuthorityID[:]); err != nil {
+			return nil, err
+		}
+
+		j.Commit.Precommits = append(j.Commit.Precommits, pc)
+	}
+
+	n, err = sd.DecodeInteger()
+	if err != nil {
+		return nil, err
+	}
+
+	j.VotesAncestries = []*types.Header{}
+	for i := int64(0); i < n; i++ {
+		var header *types.Header
+		header, err = new(types.Header).Decode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		j.VotesAncestries = append(j.VotesAncestries, header)
+	}
+
+	return j, nil
+}
+
+// writeLength writes the SCALE compact encoding of a sequence length
+func writeLength(buf *bytes.Buffer, n int) error {
+	enc, err := scale.Encode(big.NewInt(int64(n)))
+	if err != nil {
+		return err
+	}
+
+	_, _ = buf.Write(enc)
+	return nil
+}
+
+// decodeStoredJustification decodes a justification as stored in the block state, making sure that all of the
+// input is consumed
+func decodeStoredJustification(data []byte) (just *GrandpaJustification, err error) {
+	// the scale decoder panics on some truncated input, which is expected when checking a corrupted database
+	defer func() {
+		if r := recover(); r != nil {
+			just, err = nil, fmt.Errorf("failed to decode justification: %v", r)
+		}
+	}()
+
+	r := bytes.NewReader(data)
+	just, err = new(GrandpaJustification).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after justification", r.Len())
+	}
+
+	return just, nil
+}
+
+// VerifyStoredJustification checks that the justification stored for the block with the given hash and number is
+// well formed, that its commit is for the block, and that each of its pre-commits is for the block or one of its
+// descendants and is cast by a distinct authority. The set ID of the votes isn't stored with the justification, so
+// signatures aren't checked.
+func VerifyStoredJustification(data []byte, hash common.Hash, number uint64) error {
+	just, err := decodeStoredJustification(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidJustification, err)
+	}
+
+	if just.Commit.Hash != hash || uint64(just.Commit.Number) != number {
+		return fmt.Errorf("%w: commit for block %s with number %d", ErrInvalidJustification, just.Commit.Hash,
+			just.Commit.Number)
+	}
+
+	if len(just.Commit.Precommits) == 0 {
+		return fmt.Errorf("%w: no votes", ErrInvalidJustification)
+	}
+
+	voters := make(map[string]struct{}, len(just.Commit.Precommits))
+	for _, pc := range just.Commit.Precommits {
+		if pc.Vote.number < number || (pc.Vote.number == number && pc.Vote.hash != hash) {
+			return fmt.Errorf("%w: vote for %s is not for the block or a descendant", ErrInvalidJustification, pc.Vote)
+		}
+
+		voter := string(pc.AuthorityID[:])
+		if _, has := voters[voter]; has {
+			return fmt.Errorf("%w: duplicate vote from authority 0x%x", ErrInvalidJustification, pc.AuthorityID)
+		}
+		voters[voter] = struct{}{}
+	}
+
+	return nil
+}
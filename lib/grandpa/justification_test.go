@@ -0,0 +1,106 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package grandpa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrandpaJustification_Encode(t *testing.T) {
+	header, err := types.NewHeader(common.Hash{0x1}, big.NewInt(1), common.Hash{0x2}, common.Hash{0x3}, types.Digest{})
+	require.NoError(t, err)
+
+	just := newGrandpaJustification(77, testVote, []*Justification{
+		{Vote: testVote, Signature: testSignature, AuthorityID: testAuthorityID},
+		{Vote: testVote2, Signature: testSignature, AuthorityID: [32]byte{9}},
+	})
+	just.VotesAncestries = []*types.Header{header}
+
+	enc, err := just.Encode()
+	require.NoError(t, err)
+
+	// round, commit target and the compact length of the pre-commits, in Substrate's layout
+	expected := append([]byte{77, 0, 0, 0, 0, 0, 0, 0}, testVote.hash[:]...)
+	number := make([]byte, 4)
+	binary.LittleEndian.PutUint32(number, uint32(testVote.number))
+	expected = append(append(expected, number...), 2<<2)
+	require.Equal(t, expected, enc[:len(expected)])
+
+	dec, err := new(GrandpaJustification).Decode(bytes.NewReader(enc))
+	require.NoError(t, err)
+	require.Equal(t, just.Round, dec.Round)
+	require.Equal(t, just.Commit, dec.Commit)
+	require.Len(t, dec.VotesAncestries, 1)
+	require.Equal(t, header.Hash(), dec.VotesAncestries[0].Hash())
+
+	again, err := dec.Encode()
+	require.NoError(t, err)
+	require.Equal(t, enc, again)
+}
+
+func TestVerifyStoredJustification(t *testing.T) {
+	descendant := &Vote{hash: common.Hash{0x1}, number: testVote.number + 1}
+
+	just, err := newGrandpaJustification(1, testVote, []*Justification{
+		{Vote: testVote, Signature: testSignature, AuthorityID: testAuthorityID},
+		{Vote: descendant, Signature: testSignature, AuthorityID: [32]byte{9}},
+	}).Encode()
+	require.NoError(t, err)
+	require.NoError(t, VerifyStoredJustification(just, testVote.hash, testVote.number))
+
+	err = VerifyStoredJustification(just, common.Hash{0x2}, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	err = VerifyStoredJustification(just, testVote.hash, testVote.number+1)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	err = VerifyStoredJustification(just[:len(just)-1], testVote.hash, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	err = VerifyStoredJustification(append(just, 0), testVote.hash, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	ancestor, err := newGrandpaJustification(1, testVote, []*Justification{
+		{Vote: &Vote{hash: common.Hash{0x1}, number: testVote.number - 1}, Signature: testSignature,
+			AuthorityID: testAuthorityID},
+	}).Encode()
+	require.NoError(t, err)
+	err = VerifyStoredJustification(ancestor, testVote.hash, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	dup, err := newGrandpaJustification(1, testVote, []*Justification{
+		{Vote: testVote, Signature: testSignature, AuthorityID: testAuthorityID},
+		{Vote: testVote, Signature: testSignature, AuthorityID: testAuthorityID},
+	}).Encode()
+	require.NoError(t, err)
+	err = VerifyStoredJustification(dup, testVote.hash, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+
+	empty, err := newGrandpaJustification(1, testVote, []*Justification{}).Encode()
+	require.NoError(t, err)
+	err = VerifyStoredJustification(empty, testVote.hash, testVote.number)
+	require.True(t, errors.Is(err, ErrInvalidJustification))
+}
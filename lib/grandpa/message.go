@@ -178,23 +178,23 @@ func (s *Service) newCatchUpResponse(round, setID uint64) (*catchUpResponse, err
 		return nil, ErrNoJustification
 	}
 
-	just, err := s.blockState.GetJustification(header.Hash())
+	enc, err := s.blockState.GetJustification(header.Hash())
 	if err != nil {
 		return nil, err
 	}
 
-	r := &bytes.Buffer{}
-	_, err = r.Write(just)
+	just, err := new(GrandpaJustification).Decode(bytes.NewReader(enc))
 	if err != nil {
 		return nil, err
 	}
 
-	pvj, err := FullJustification{}.Decode(r)
+	// the pre-votes are only stored if the block was finalized locally
+	prevotes, err := s.blockState.GetPrevotes(header.Hash())
 	if err != nil {
-		return nil, err
+		return nil, ErrNoJustification
 	}
 
-	pcj, err := FullJustification{}.Decode(r)
+	pvj, err := FullJustification{}.Decode(bytes.NewReader(prevotes))
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +203,7 @@ func (s *Service) newCatchUpResponse(round, setID uint64) (*catchUpResponse, err
 		Round:                  round,
 		SetID:                  setID,
 		PreVoteJustification:   pvj,
-		PreCommitJustification: pcj,
+		PreCommitJustification: just.Commit.Precommits,
 		Hash:                   header.Hash(),
 		Number:                 header.Number.Uint64(),
 	}, nil
@@ -86,6 +86,17 @@ func (h *MessageHandler) handleFinalizationMessage(msg *FinalizationMessage) (*C
 		return nil, err
 	}
 
+	// store the justification, so that it can be served as a finality proof
+	just, err := newGrandpaJustification(msg.Round, msg.Vote, msg.Justification).Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.blockState.SetJustification(msg.Vote.hash, just)
+	if err != nil {
+		return nil, err
+	}
+
 	// set finalized head for round in db
 	err = h.blockState.SetFinalizedHash(msg.Vote.hash, msg.Round, h.grandpa.state.setID)
 	if err != nil {
@@ -99,8 +110,8 @@ func (h *MessageHandler) handleFinalizationMessage(msg *FinalizationMessage) (*C
 	}
 
 	// check if msg has same setID but is 2 or more rounds ahead of us, if so, return catch-up request to send
-	if msg.Round > h.grandpa.state.round+1 && !h.grandpa.paused.Load().(bool) { // TODO: FinalizationMessage does not have setID, confirm this is correct
-		h.grandpa.paused.Store(true)
+	// TODO: FinalizationMessage does not have setID, confirm this is correct
+	if msg.Round > h.grandpa.state.round+1 && h.grandpa.pauseForCatchUp() {
 		h.grandpa.state.round = msg.Round + 1
 		req := newCatchUpRequest(msg.Round, h.grandpa.state.setID)
 		h.grandpa.logger.Debug("sending catch-up request; paused service", "round", msg.Round)
@@ -133,7 +144,7 @@ func (h *MessageHandler) handleCatchUpResponse(msg *catchUpResponse) error {
 	h.grandpa.logger.Debug("received catch up response", "round", msg.Round, "setID", msg.SetID, "hash", msg.Hash)
 
 	// if we aren't currently expecting a catch up response, return
-	if !h.grandpa.paused.Load().(bool) {
+	if !h.grandpa.isCatchingUp() {
 		h.grandpa.logger.Debug("not currently paused, ignoring catch up response")
 		return nil
 	}
@@ -171,9 +182,7 @@ func (h *MessageHandler) handleCatchUpResponse(msg *catchUpResponse) error {
 
 	h.grandpa.head = head
 	h.grandpa.state.round = msg.Round
-	close(h.grandpa.resumed)
-	h.grandpa.resumed = make(chan struct{})
-	h.grandpa.paused.Store(false)
+	h.grandpa.resumeAfterCatchUp()
 	h.grandpa.logger.Debug("caught up to round; unpaused service", "round", h.grandpa.state.round)
 	return nil
 }
@@ -237,21 +246,22 @@ func decodeMessage(msg *ConsensusMessage) (m FinalityMessage, err error) {
 }
 
 func (h *MessageHandler) verifyFinalizationMessageJustification(fm *FinalizationMessage) error {
-	// verify justifications
-	count := 0
+	// verify justifications, every pre-commit must be signed by a voter of the current set
+	var count uint64
+	seen := make(map[ed25519.PublicKeyBytes]struct{})
 	for _, just := range fm.Justification {
 		err := h.verifyJustification(just, just.Vote, fm.Round, h.grandpa.state.setID, precommit)
 		if err != nil {
-			continue
+			return err
 		}
 
 		if just.Vote.hash == fm.Vote.hash && just.Vote.number == fm.Vote.number {
-			count++
+			count += h.justificationWeight(just, seen)
 		}
 	}
 
-	// confirm total # signatures >= grandpa threshold
-	if uint64(count) < h.grandpa.state.threshold() {
+	// confirm total weight of signatures >= grandpa threshold
+	if count < h.grandpa.state.threshold() {
 		return ErrMinVotesNotMet
 	}
 	return nil
@@ -260,6 +270,7 @@ func (h *MessageHandler) verifyFinalizationMessageJustification(fm *Finalization
 func (h *MessageHandler) verifyPreVoteJustification(msg *catchUpResponse) (common.Hash, error) {
 	// verify pre-vote justification, returning the pre-voted block if there is one
 	votes := make(map[common.Hash]uint64)
+	seen := make(map[ed25519.PublicKeyBytes]struct{})
 
 	for _, just := range msg.PreVoteJustification {
 		err := h.verifyJustification(just, just.Vote, msg.Round, msg.SetID, prevote)
@@ -267,7 +278,7 @@ func (h *MessageHandler) verifyPreVoteJustification(msg *catchUpResponse) (commo
 			continue
 		}
 
-		votes[just.Vote.hash]++
+		votes[just.Vote.hash] += h.justificationWeight(just, seen)
 	}
 
 	var prevote common.Hash
@@ -287,25 +298,36 @@ func (h *MessageHandler) verifyPreVoteJustification(msg *catchUpResponse) (commo
 
 func (h *MessageHandler) verifyPreCommitJustification(msg *catchUpResponse) error {
 	// verify pre-commit justification
-	count := 0
+	var count uint64
+	seen := make(map[ed25519.PublicKeyBytes]struct{})
 	for _, just := range msg.PreCommitJustification {
 		err := h.verifyJustification(just, just.Vote, msg.Round, msg.SetID, precommit)
 		if err != nil {
-			continue
+			return err
 		}
 
 		if just.Vote.hash == msg.Hash && just.Vote.number == msg.Number {
-			count++
+			count += h.justificationWeight(just, seen)
 		}
 	}
 
-	if uint64(count) < h.grandpa.state.threshold() {
+	if count < h.grandpa.state.threshold() {
 		return ErrMinVotesNotMet
 	}
 
 	return nil
 }
 
+// justificationWeight returns the weight of the voter that signed the justification, counting each voter only once
+func (h *MessageHandler) justificationWeight(just *Justification, seen map[ed25519.PublicKeyBytes]struct{}) uint64 {
+	if _, has := seen[just.AuthorityID]; has {
+		return 0
+	}
+
+	seen[just.AuthorityID] = struct{}{}
+	return h.grandpa.state.voterWeight(just.AuthorityID)
+}
+
 func (h *MessageHandler) verifyJustification(just *Justification, vote *Vote, round, setID uint64, stage subround) error {
 	// verify signature
 	msg, err := scale.Encode(&FullVote{
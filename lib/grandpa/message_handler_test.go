@@ -208,6 +208,12 @@ func TestMessageHandler_FinalizationMessage_NoCatchUpRequest_ValidSig(t *testing
 	hash, err = st.Block.GetFinalizedHash(fm.Round, gs.state.setID)
 	require.NoError(t, err)
 	require.Equal(t, fm.Vote.hash, hash)
+
+	just, err := st.Block.GetJustification(fm.Vote.hash)
+	require.NoError(t, err)
+	expected, err := newGrandpaJustification(fm.Round, fm.Vote, fm.Justification).Encode()
+	require.NoError(t, err)
+	require.Equal(t, expected, just)
 }
 
 func TestMessageHandler_FinalizationMessage_NoCatchUpRequest_MinVoteError(t *testing.T) {
@@ -228,13 +234,67 @@ func TestMessageHandler_FinalizationMessage_NoCatchUpRequest_MinVoteError(t *tes
 	require.Nil(t, out)
 }
 
+func TestMessageHandler_FinalizationMessage_TwoThirdsVoteError(t *testing.T) {
+	gs, st := newTestService(t)
+
+	round := uint64(77)
+	gs.state.round = round
+
+	// exactly 2/3 of the voter weight doesn't finalize the block
+	gs.justification[round] = buildTestJustifications(t, int(gs.state.threshold())-1, round, gs.state.setID, kr, precommit)
+
+	fm := gs.newFinalizationMessage(gs.head, round)
+	fm.Vote = NewVote(testHash, round)
+	cm, err := fm.ToConsensusMessage()
+	require.NoError(t, err)
+
+	h := NewMessageHandler(gs, st.Block)
+	out, err := h.HandleMessage(cm)
+	require.EqualError(t, err, ErrMinVotesNotMet.Error())
+	require.Nil(t, out)
+
+	has, err := st.Block.HasJustification(fm.Vote.hash)
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestMessageHandler_FinalizationMessage_InvalidSig(t *testing.T) {
+	gs, st := newTestService(t)
+
+	round := uint64(77)
+	gs.state.round = round
+
+	// a supermajority of valid pre-commits doesn't make up for an invalid one
+	gs.justification[round] = buildTestJustifications(t, int(gs.state.threshold()), round, gs.state.setID, kr, precommit)
+	gs.justification[round] = append(gs.justification[round], &Justification{
+		Vote:        NewVote(testHash, round),
+		Signature:   [64]byte{0x1},
+		AuthorityID: kr.Keys[len(kr.Keys)-1].Public().(*ed25519.PublicKey).AsBytes(),
+	})
+
+	fm := gs.newFinalizationMessage(gs.head, round)
+	fm.Vote = NewVote(testHash, round)
+	cm, err := fm.ToConsensusMessage()
+	require.NoError(t, err)
+
+	h := NewMessageHandler(gs, st.Block)
+	out, err := h.HandleMessage(cm)
+	require.Equal(t, ErrInvalidSignature, err)
+	require.Nil(t, out)
+}
+
 func TestMessageHandler_FinalizationMessage_WithCatchUpRequest(t *testing.T) {
 	gs, st := newTestService(t)
 
+	gs.state.round = 77
+	vm, err := gs.createVoteMessage(NewVoteFromHeader(gs.head), precommit, gs.keypair)
+	require.NoError(t, err)
+	gs.state.round = 0
+
 	gs.justification[77] = []*Justification{
 		{
-			Vote:        testVote,
-			Signature:   testSignature,
+			Vote:        NewVoteFromHeader(gs.head),
+			Signature:   vm.Message.Signature,
 			AuthorityID: gs.publicKeyBytes(),
 		},
 	}
@@ -316,10 +376,13 @@ func TestMessageHandler_CatchUpRequest_WithResponse(t *testing.T) {
 		},
 	}
 
-	pcjEnc, err := scale.Encode(pcj)
+	just, err := newGrandpaJustification(round, v, pcj).Encode()
 	require.NoError(t, err)
 
-	err = gs.blockState.SetJustification(v.hash, append(pvjEnc, pcjEnc...))
+	err = gs.blockState.SetJustification(v.hash, just)
+	require.NoError(t, err)
+
+	err = gs.blockState.SetPrevotes(v.hash, pvjEnc)
 	require.NoError(t, err)
 
 	resp, err := gs.newCatchUpResponse(round, setID)
@@ -422,6 +485,36 @@ func TestMessageHandler_VerifyPreCommitJustification(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMessageHandler_VerifyPreCommitJustification_Weighted(t *testing.T) {
+	gs, st := newTestService(t)
+	h := NewMessageHandler(gs, st.Block)
+	gs.state.voters[0].weight = 10
+
+	round := uint64(1)
+	just := buildTestJustifications(t, 4, round, gs.state.setID, kr, precommit)
+
+	for _, tc := range []struct {
+		just []*Justification
+		err  error
+	}{
+		{just: just, err: nil},
+		{just: just[:3], err: ErrMinVotesNotMet},
+		{just: just[1:], err: ErrMinVotesNotMet},
+		{just: []*Justification{just[0], just[0], just[1]}, err: ErrMinVotesNotMet},
+	} {
+		msg := &catchUpResponse{
+			Round:                  round,
+			SetID:                  gs.state.setID,
+			PreCommitJustification: tc.just,
+			Hash:                   testHash,
+			Number:                 round,
+		}
+
+		err := h.verifyPreCommitJustification(msg)
+		require.Equal(t, tc.err, err)
+	}
+}
+
 func TestMessageHandler_HandleCatchUpResponse(t *testing.T) {
 	gs, st := newTestService(t)
 
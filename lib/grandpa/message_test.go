@@ -125,10 +125,13 @@ func TestNewCatchUpResponse(t *testing.T) {
 		},
 	}
 
-	pcjEnc, err := scale.Encode(pcj)
+	just, err := newGrandpaJustification(round, v, pcj).Encode()
 	require.NoError(t, err)
 
-	err = gs.blockState.SetJustification(v.hash, append(pvjEnc, pcjEnc...))
+	err = gs.blockState.SetJustification(v.hash, just)
+	require.NoError(t, err)
+
+	err = gs.blockState.SetPrevotes(v.hash, pvjEnc)
 	require.NoError(t, err)
 
 	resp, err := gs.newCatchUpResponse(round, setID)
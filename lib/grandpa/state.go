@@ -45,6 +45,8 @@ type BlockState interface {
 	SetJustification(hash common.Hash, data []byte) error
 	HasJustification(hash common.Hash) (bool, error)
 	GetJustification(hash common.Hash) ([]byte, error)
+	SetPrevotes(hash common.Hash, data []byte) error
+	GetPrevotes(hash common.Hash) ([]byte, error)
 }
 
 // DigestHandler is the interface required by GRANDPA for the digest handler
@@ -64,8 +64,9 @@ func (s subround) String() string {
 
 // Voter represents a GRANDPA voter
 type Voter struct {
-	key *ed25519.PublicKey
-	id  uint64 //nolint:unused
+	key    *ed25519.PublicKey
+	id     uint64 //nolint:unused
+	weight uint64
 }
 
 // PublicKeyBytes returns the voter key as PublicKeyBytes
@@ -75,18 +76,25 @@ func (v *Voter) PublicKeyBytes() ed25519.PublicKeyBytes {
 
 // String returns a formatted Voter string
 func (v *Voter) String() string {
-	return fmt.Sprintf("[key=0x%x id=%d]", v.PublicKeyBytes(), v.id)
+	return fmt.Sprintf("[key=0x%x id=%d weight=%d]", v.PublicKeyBytes(), v.id, v.weight)
 }
 
-// NewVotersFromAuthorityData returns an array of Voters given an array of GrandpaAuthorityData
+// NewVotersFromAuthorityData returns an array of Voters given an array of GrandpaAuthorityData. An authority
+// without a weight counts as a single vote.
 func NewVotersFromAuthorityData(ad []*types.Authority) []*Voter {
 	v := make([]*Voter, len(ad))
 
 	for i, d := range ad {
 		if pk, ok := d.Key.(*ed25519.PublicKey); ok {
+			weight := d.Weight
+			if weight == 0 {
+				weight = 1
+			}
+
 			v[i] = &Voter{
-				key: pk,
-				id:  d.Weight,
+				key:    pk,
+				id:     uint64(i),
+				weight: weight,
 			}
 		}
 	}
@@ -139,15 +147,42 @@ func (s *State) pubkeyToVoter(pk *ed25519.PublicKey) (*Voter, error) {
 	}
 
 	return &Voter{
-		key: pk,
-		id:  id,
+		key:    pk,
+		id:     id,
+		weight: s.voters[id].weight,
 	}, nil
 }
 
-// threshold returns the 2/3 |voters| threshold value
-// TODO: determine rounding, is currently set to floor
+// voterWeight returns the weight of the voter with the given key, or 0 if it isn't in the voter set
+func (s *State) voterWeight(key ed25519.PublicKeyBytes) uint64 {
+	for _, v := range s.voters {
+		if v.PublicKeyBytes() == key {
+			return v.weight
+		}
+	}
+
+	return 0
+}
+
+// totalWeight returns the sum of the weights of all voters
+func (s *State) totalWeight() uint64 {
+	var total uint64
+	for _, v := range s.voters {
+		total += v.weight
+	}
+
+	return total
+}
+
+// threshold returns the smallest voter weight that is more than 2/3 of the total voter weight, ie. the weight
+// remaining once the most faulty weight that can be tolerated is taken away
 func (s *State) threshold() uint64 {
-	return uint64(2 * len(s.voters) / 3)
+	total := s.totalWeight()
+	if total == 0 {
+		return 0
+	}
+
+	return total - (total-1)/3
 }
 
 // Vote represents a vote for a block with the given hash and number
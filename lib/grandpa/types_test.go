@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/scale"
@@ -38,6 +39,50 @@ func TestPubkeyToVoter(t *testing.T) {
 	require.Equal(t, voters[0], voter)
 }
 
+func TestNewVotersFromAuthorityData(t *testing.T) {
+	kr, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+
+	voters := NewVotersFromAuthorityData([]*types.Authority{
+		{Key: kr.Alice().Public(), Weight: 5},
+		{Key: kr.Bob().Public(), Weight: 0},
+		{Key: kr.Charlie().Public(), Weight: 2},
+	})
+
+	require.Len(t, voters, 3)
+	for i, w := range []uint64{5, 1, 2} {
+		require.Equal(t, uint64(i), voters[i].id)
+		require.Equal(t, w, voters[i].weight)
+	}
+}
+
+func TestState_Threshold_Weighted(t *testing.T) {
+	voters := newTestVoters()
+	state := NewState(voters, 0, 0)
+	require.Equal(t, uint64(9), state.totalWeight())
+	require.Equal(t, uint64(7), state.threshold())
+
+	voters[0].weight = 10
+	require.Equal(t, uint64(18), state.totalWeight())
+	require.Equal(t, uint64(13), state.threshold())
+	require.Equal(t, uint64(10), state.voterWeight(voters[0].PublicKeyBytes()))
+	require.Equal(t, uint64(0), state.voterWeight(ed25519.PublicKeyBytes{}))
+}
+
+func TestState_Threshold_MoreThanTwoThirds(t *testing.T) {
+	voters := newTestVoters()
+	for i, expected := range []uint64{0, 1, 2, 3, 3, 4, 5, 5, 6, 7} {
+		state := NewState(voters[:i], 0, 0)
+		require.Equal(t, expected, state.threshold(), i)
+	}
+
+	// exactly 2/3 of the weight isn't enough, so a voter with twice the weight of the other can't finalize alone
+	voters = newTestVoters()[:2]
+	voters[0].weight = 2
+	state := NewState(voters, 0, 0)
+	require.Equal(t, uint64(3), state.threshold())
+}
+
 func TestJustificationEncoding(t *testing.T) {
 	just := &Justification{
 		Vote:        testVote,
@@ -53,7 +53,9 @@ func (ks *BasicKeystore) Type() crypto.KeyType {
 
 // Size returns the number of keys in the keystore
 func (ks *BasicKeystore) Size() int {
-	return len(ks.Keypairs())
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	return len(ks.keys)
 }
 
 // Insert adds a keypair to the keystore
@@ -72,6 +74,9 @@ func (ks *BasicKeystore) Insert(kp crypto.Keypair) {
 
 // GetKeypair returns a keypair corresponding to the given public key, or nil if it doesn't exist
 func (ks *BasicKeystore) GetKeypair(pub crypto.PublicKey) crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	for _, key := range ks.keys {
 		if bytes.Equal(key.Public().Encode(), pub.Encode()) {
 			return key
@@ -89,6 +94,9 @@ func (ks *BasicKeystore) GetKeypairFromAddress(pub common.Address) crypto.Keypai
 
 // PublicKeys returns all public keys in the keystore
 func (ks *BasicKeystore) PublicKeys() []crypto.PublicKey {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.PublicKey{}
 	if ks.keys == nil {
 		return srkeys
@@ -103,6 +111,9 @@ func (ks *BasicKeystore) PublicKeys() []crypto.PublicKey {
 
 // Keypairs returns all keypairs in the keystore
 func (ks *BasicKeystore) Keypairs() []crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.Keypair{}
 	if ks.keys == nil {
 		return srkeys
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -72,3 +73,44 @@ func TestBasicKeystore_PublicKeys(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", pubkeys, expectedPubkeys)
 	}
 }
+
+func TestBasicKeystore_Concurrent(t *testing.T) {
+	ks := NewBasicKeystore("test", crypto.Sr25519Type)
+
+	numKps := 16
+	kps := make([]crypto.Keypair, numKps)
+	for i := range kps {
+		kp, err := sr25519.GenerateKeypair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		kps[i] = kp
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2 * numKps)
+
+	for _, kp := range kps {
+		go func(kp crypto.Keypair) {
+			defer wg.Done()
+			ks.Insert(kp)
+		}(kp)
+
+		go func(kp crypto.Keypair) {
+			defer wg.Done()
+			before := ks.Keypairs()
+			_ = ks.GetKeypair(kp.Public())
+			_ = ks.PublicKeys()
+			_ = ks.Size()
+			if len(ks.Keypairs()) < len(before) {
+				t.Error("keystore lost keys")
+			}
+		}(kp)
+	}
+
+	wg.Wait()
+
+	if ks.Size() != numKps {
+		t.Fatalf("Fail: got %d keys expected %d", ks.Size(), numKps)
+	}
+}
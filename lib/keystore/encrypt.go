@@ -36,11 +36,12 @@ import (
 	"golang.org/x/crypto/blake2b"
 )
 
-// EncryptedKeystore holds Type PublicKey and Ciphertext
+// EncryptedKeystore holds Type PublicKey and Ciphertext, and the KeyType of keys that are only used as one key type
 type EncryptedKeystore struct {
 	Type       string
 	PublicKey  string
 	Ciphertext []byte
+	KeyType    Name `json:",omitempty"` // if empty, the key is used as every key type of its scheme
 }
 
 // gcmFromPassphrase creates a symmetric AES key given a password
@@ -112,6 +113,12 @@ func DecryptPrivateKey(data, password []byte, keytype string) (crypto.PrivateKey
 
 // EncryptAndWriteToFile encrypts the `crypto.PrivateKey` using the password and saves it to the specified file
 func EncryptAndWriteToFile(file *os.File, pk crypto.PrivateKey, password []byte) error {
+	return encryptAndWriteToFile(file, pk, "", password)
+}
+
+// encryptAndWriteToFile encrypts the `crypto.PrivateKey` using the password and saves it to the specified file as
+// a key of the given key type
+func encryptAndWriteToFile(file *os.File, pk crypto.PrivateKey, name Name, password []byte) error {
 	ciphertext, err := EncryptPrivateKey(pk, password)
 	if err != nil {
 		return err
@@ -143,6 +150,7 @@ func EncryptAndWriteToFile(file *os.File, pk crypto.PrivateKey, password []byte)
 		Type:       keytype,
 		PublicKey:  pub.Hex(),
 		Ciphertext: ciphertext,
+		KeyType:    name,
 	}
 
 	data, err := json.MarshalIndent(keydata, "", "\t")
@@ -156,6 +164,16 @@ func EncryptAndWriteToFile(file *os.File, pk crypto.PrivateKey, password []byte)
 
 // ReadFromFileAndDecrypt reads ciphertext from a file and decrypts it using the password into a `crypto.PrivateKey`
 func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey, error) {
+	keydata, err := readEncryptedKeystore(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type)
+}
+
+// readEncryptedKeystore reads the EncryptedKeystore of a key file
+func readEncryptedKeystore(filename string) (*EncryptedKeystore, error) {
 	fp, err := filepath.Abs(filename)
 	if err != nil {
 		return nil, err
@@ -172,5 +190,5 @@ func ReadFromFileAndDecrypt(filename string, password []byte) (crypto.PrivateKey
 		return nil, err
 	}
 
-	return DecryptPrivateKey(keydata.Ciphertext, password, keydata.Type)
+	return keydata, nil
 }
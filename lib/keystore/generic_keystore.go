@@ -54,7 +54,9 @@ func (ks *GenericKeystore) Type() crypto.KeyType {
 
 // Size returns the number of keys in the keystore
 func (ks *GenericKeystore) Size() int {
-	return len(ks.Keypairs())
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	return len(ks.keys)
 }
 
 // Insert adds a keypair to the keystore
@@ -69,6 +71,9 @@ func (ks *GenericKeystore) Insert(kp crypto.Keypair) {
 
 // GetKeypair returns a keypair corresponding to the given public key, or nil if it doesn't exist
 func (ks *GenericKeystore) GetKeypair(pub crypto.PublicKey) crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	for _, key := range ks.keys {
 		if bytes.Equal(key.Public().Encode(), pub.Encode()) {
 			return key
@@ -86,6 +91,9 @@ func (ks *GenericKeystore) GetKeypairFromAddress(pub common.Address) crypto.Keyp
 
 // PublicKeys returns all public keys in the keystore
 func (ks *GenericKeystore) PublicKeys() []crypto.PublicKey {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.PublicKey{}
 	if ks.keys == nil {
 		return srkeys
@@ -100,6 +108,9 @@ func (ks *GenericKeystore) PublicKeys() []crypto.PublicKey {
 
 // Keypairs returns all keypairs in the keystore
 func (ks *GenericKeystore) Keypairs() []crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.Keypair{}
 	if ks.keys == nil {
 		return srkeys
@@ -123,6 +134,9 @@ func (ks *GenericKeystore) NumEd25519Keys() int {
 
 // Ed25519PublicKeys keys
 func (ks *GenericKeystore) Ed25519PublicKeys() []crypto.PublicKey {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	edkeys := []crypto.PublicKey{}
 	if ks.keys == nil {
 		return edkeys
@@ -138,6 +152,9 @@ func (ks *GenericKeystore) Ed25519PublicKeys() []crypto.PublicKey {
 
 // Ed25519Keypairs Keypair
 func (ks *GenericKeystore) Ed25519Keypairs() []crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	edkeys := []crypto.Keypair{}
 	if ks.keys == nil {
 		return edkeys
@@ -153,6 +170,9 @@ func (ks *GenericKeystore) Ed25519Keypairs() []crypto.Keypair {
 
 // Sr25519PublicKeys PublicKey
 func (ks *GenericKeystore) Sr25519PublicKeys() []crypto.PublicKey {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.PublicKey{}
 	if ks.keys == nil {
 		return srkeys
@@ -168,6 +188,9 @@ func (ks *GenericKeystore) Sr25519PublicKeys() []crypto.PublicKey {
 
 // Sr25519Keypairs Keypair
 func (ks *GenericKeystore) Sr25519Keypairs() []crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	srkeys := []crypto.Keypair{}
 	if ks.keys == nil {
 		return srkeys
@@ -183,6 +206,9 @@ func (ks *GenericKeystore) Sr25519Keypairs() []crypto.Keypair {
 
 // Secp256k1PublicKeys PublicKey
 func (ks *GenericKeystore) Secp256k1PublicKeys() []crypto.PublicKey {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	sckeys := []crypto.PublicKey{}
 	if ks.keys == nil {
 		return sckeys
@@ -198,6 +224,9 @@ func (ks *GenericKeystore) Secp256k1PublicKeys() []crypto.PublicKey {
 
 // Secp256k1Keypairs Keypair
 func (ks *GenericKeystore) Secp256k1Keypairs() []crypto.Keypair {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+
 	sckeys := []crypto.Keypair{}
 	if ks.keys == nil {
 		return sckeys
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -135,3 +136,47 @@ func TestGetSecp256k1PublicKeys(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", pubkeys, expectedPubkeys)
 	}
 }
+
+func TestGenericKeystore_Concurrent(t *testing.T) {
+	ks := NewGenericKeystore("test")
+
+	numKps := 8
+	var wg sync.WaitGroup
+	wg.Add(3 * numKps)
+
+	for i := 0; i < numKps; i++ {
+		go func() {
+			defer wg.Done()
+			kp, err := sr25519.GenerateKeypair()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ks.Insert(kp)
+		}()
+
+		go func() {
+			defer wg.Done()
+			kp, err := ed25519.GenerateKeypair()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ks.Insert(kp)
+		}()
+
+		go func() {
+			defer wg.Done()
+			_ = ks.Sr25519Keypairs()
+			_ = ks.Ed25519PublicKeys()
+			_ = ks.NumSr25519Keys()
+			_ = ks.Keypairs()
+		}()
+	}
+
+	wg.Wait()
+
+	if ks.NumSr25519Keys() != numKps || ks.NumEd25519Keys() != numKps {
+		t.Fatalf("Fail: got %d sr25519 and %d ed25519 keys expected %d", ks.NumSr25519Keys(), ks.NumEd25519Keys(), numKps)
+	}
+}
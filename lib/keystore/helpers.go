@@ -68,6 +68,16 @@ func DecodePrivateKey(in []byte, keytype crypto.KeyType) (priv crypto.PrivateKey
 // it to basepath/keystore/[public key].key in json format encrypted using the
 // specified password and returns the resulting filepath of the new key
 func GenerateKeypair(keytype string, kp crypto.Keypair, basepath string, password []byte) (string, error) {
+	return generateKeypair(keytype, "", kp, basepath, password)
+}
+
+// InsertKeypair saves the keypair to basepath/keystore/[public key].key like GenerateKeypair, as a key of the
+// given key type. When it's unlocked, the key is only inserted into the keystore of that key type.
+func InsertKeypair(name Name, kp crypto.Keypair, basepath string, password []byte) (string, error) {
+	return generateKeypair(kp.Type(), name, kp, basepath, password)
+}
+
+func generateKeypair(keytype string, name Name, kp crypto.Keypair, basepath string, password []byte) (string, error) {
 	if keytype == "" {
 		keytype = crypto.Sr25519Type
 	}
@@ -108,8 +118,47 @@ func GenerateKeypair(keytype string, kp crypto.Keypair, basepath string, passwor
 		return "", err
 	}
 
-	err = EncryptAndWriteToFile(file, kp.Private(), password)
+	err = encryptAndWriteToFile(file, kp.Private(), name, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to write key to file: %s", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to close file: %s", err)
+	}
+
+	return fp, nil
+}
+
+// sessionKeysDir is the subdirectory of the keystore directory that session keys generated by the node are written
+// to. They're kept apart from the other key files, so the key indices used by --unlock don't change.
+const sessionKeysDir = "session"
+
+// WriteSessionKey writes the given keypair into the session keys directory of the given base path, in a file named
+// after the key type and public key. Session keys are loaded without a password prompt when the node starts, so
+// they're encrypted with an empty password.
+func WriteSessionKey(basepath string, name Name, kp crypto.Keypair) (string, error) {
+	keyPath, err := utils.KeystoreDir(basepath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get keystore directory: %s", err)
+	}
+
+	dir := filepath.Join(keyPath, sessionKeysDir)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session keys directory: %s", err)
+	}
+
+	fp := filepath.Join(dir, fmt.Sprintf("%s-%s.key", name, hex.EncodeToString(kp.Public().Encode())))
+	file, err := os.OpenFile(filepath.Clean(fp), os.O_EXCL|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	err = EncryptAndWriteToFile(file, kp.Private(), nil)
 	if err != nil {
+		_ = file.Close()
 		return "", fmt.Errorf("failed to write key to file: %s", err)
 	}
 
@@ -121,6 +170,49 @@ func GenerateKeypair(keytype string, kp crypto.Keypair, basepath string, passwor
 	return fp, nil
 }
 
+// LoadSessionKeys inserts the session keys written by WriteSessionKey to the given base path into their keystores
+func LoadSessionKeys(ks *GlobalKeystore, basepath string) error {
+	keyPath, err := utils.KeystoreDir(basepath)
+	if err != nil {
+		return fmt.Errorf("failed to get keystore directory: %s", err)
+	}
+
+	dir := filepath.Join(keyPath, sessionKeysDir)
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read session keys directory: %s", err)
+	}
+
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != ".key" {
+			continue
+		}
+
+		name := strings.SplitN(f.Name(), "-", 2)[0]
+		typed := ks.Get(Name(name))
+		if typed == nil {
+			return fmt.Errorf("invalid key type %q of session key file %s", name, f.Name())
+		}
+
+		priv, err := ReadFromFileAndDecrypt(filepath.Join(dir, f.Name()), nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt session key file %s: %s", f.Name(), err)
+		}
+
+		kp, err := PrivateKeyToKeypair(priv)
+		if err != nil {
+			return fmt.Errorf("failed to create keypair from session key file %s: %s", f.Name(), err)
+		}
+
+		typed.Insert(kp)
+	}
+
+	return nil
+}
+
 // LoadKeystore loads a new keystore and inserts the test key into the keystore
 func LoadKeystore(key string, ks Keystore) error {
 	if key != "" {
@@ -231,7 +323,7 @@ func ImportRawPrivateKey(key, keytype, basepath string, password []byte) (string
 }
 
 // UnlockKeys unlocks keys specified by the --unlock flag with the passwords given by --password
-// and places them into the keystore
+// and places them into the keystore. Keys written as a key of another key type are skipped.
 func UnlockKeys(ks Keystore, dir string, unlock string, password string) error {
 	var indices []int
 	var passwords []string
@@ -276,7 +368,16 @@ func UnlockKeys(ks Keystore, dir string, unlock string, password string) error {
 		}
 
 		keyFile := keyFiles[idx]
-		priv, err := ReadFromFileAndDecrypt(keyDir+"/"+keyFile, []byte(passwords[i]))
+		keydata, err := readEncryptedKeystore(keyDir + "/" + keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read key file %s: %s", keyFile, err)
+		}
+
+		if keydata.KeyType != "" && keydata.KeyType != ks.Name() {
+			continue
+		}
+
+		priv, err := DecryptPrivateKey(keydata.Ciphertext, []byte(passwords[i]), keydata.Type)
 		if err != nil {
 			return fmt.Errorf("failed to decrypt key file %s: %s", keyFile, err)
 		}
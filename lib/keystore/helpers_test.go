@@ -27,6 +27,8 @@ import (
 	"testing"
 
 	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/utils"
 
 	"github.com/stretchr/testify/require"
@@ -302,6 +304,38 @@ func TestUnlockKeys(t *testing.T) {
 	}
 }
 
+func TestInsertKeypair_UnlockKeys(t *testing.T) {
+	testdir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	kp, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	keyfile, err := InsertKeypair(BabeName, kp, testdir, testPassword)
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(keyfile)
+	require.NoError(t, err)
+
+	kscontents := new(EncryptedKeystore)
+	err = json.Unmarshal(contents, kscontents)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Sr25519Type, kscontents.Type)
+	require.Equal(t, BabeName, kscontents.KeyType)
+
+	// the key isn't unlocked into the keystores of other key types
+	acco := NewBasicKeystore(AccoName, crypto.Sr25519Type)
+	err = UnlockKeys(acco, testdir, "0", string(testPassword))
+	require.NoError(t, err)
+	require.Equal(t, 0, acco.Size())
+
+	babe := NewBasicKeystore(BabeName, crypto.Sr25519Type)
+	err = UnlockKeys(babe, testdir, "0", string(testPassword))
+	require.NoError(t, err)
+	require.Equal(t, 1, babe.Size())
+	require.NotNil(t, babe.GetKeypair(kp.Public()))
+}
+
 func TestImportRawPrivateKey_NoType(t *testing.T) {
 	testdir := utils.NewTestDir(t)
 	defer utils.RemoveTestDir(t)
@@ -369,3 +403,42 @@ func TestImportRawPrivateKey_Secp256k1(t *testing.T) {
 	require.Equal(t, "secp256k1", kscontents.Type)
 	require.Equal(t, "0x03409094a319b2961660c3ebcc7d206266182c1b3e60d341b5fb17e6851865825c", kscontents.PublicKey)
 }
+
+func TestWriteSessionKey_LoadSessionKeys(t *testing.T) {
+	testdir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	babeKey, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	granKey, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	_, err = WriteSessionKey(testdir, BabeName, babeKey)
+	require.NoError(t, err)
+	_, err = WriteSessionKey(testdir, GranName, granKey)
+	require.NoError(t, err)
+
+	// session keys don't change the indices of the keys that can be unlocked
+	files, err := utils.KeystoreFiles(testdir)
+	require.NoError(t, err)
+	require.Len(t, files, 0)
+
+	ks := NewGlobalKeystore()
+	err = LoadSessionKeys(ks, testdir)
+	require.NoError(t, err)
+	require.Equal(t, 1, ks.Babe.Size())
+	require.Equal(t, 1, ks.Gran.Size())
+	require.Equal(t, 0, ks.Acco.Size())
+	require.Equal(t, babeKey.Public(), ks.Babe.GetKeypair(babeKey.Public()).Public())
+	require.Equal(t, granKey.Public(), ks.Gran.GetKeypair(granKey.Public()).Public())
+}
+
+func TestLoadSessionKeys_NoSessionKeys(t *testing.T) {
+	testdir := utils.NewTestDir(t)
+	defer utils.RemoveTestDir(t)
+
+	ks := NewGlobalKeystore()
+	err := LoadSessionKeys(ks, testdir)
+	require.NoError(t, err)
+	require.Equal(t, 0, ks.Babe.Size())
+}
@@ -35,7 +35,8 @@ var (
 	DumyName Name = "dumy"
 )
 
-// Keystore provides key management functionality
+// Keystore provides key management functionality. Implementations are safe for concurrent use, and the slices
+// returned by PublicKeys and Keypairs are copies that are unaffected by later insertions.
 type Keystore interface {
 	Name() Name
 	Type() crypto.KeyType
@@ -70,3 +71,25 @@ func NewGlobalKeystore() *GlobalKeystore {
 		Dumy: NewGenericKeystore(DumyName),
 	}
 }
+
+// Get returns the keystore with the given name, or nil if there is none
+func (k *GlobalKeystore) Get(name Name) Keystore {
+	switch name {
+	case BabeName:
+		return k.Babe
+	case GranName:
+		return k.Gran
+	case AccoName:
+		return k.Acco
+	case AuraName:
+		return k.Aura
+	case ImonName:
+		return k.Imon
+	case AudiName:
+		return k.Audi
+	case DumyName:
+		return k.Dumy
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package memstats
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Names of the subsystems that report their memory usage
+const (
+	TrieCache       = "trie_cache"
+	BlockTree       = "blocktree"
+	TransactionPool = "transaction_pool"
+	PendingBlocks   = "pending_blocks"
+	Subscriptions   = "subscriptions"
+)
+
+// Reporter is implemented by subsystems that report the approximate memory they hold
+type Reporter interface {
+	// MemoryUsage returns the approximate number of bytes held by the subsystem
+	MemoryUsage() uint64
+}
+
+// Usage holds the approximate memory held by a subsystem
+type Usage struct {
+	Subsystem string `json:"subsystem"`
+	Bytes     uint64 `json:"bytes"`
+}
+
+// Registry holds the memory reporters of the subsystems of a node
+type Registry struct {
+	mu        sync.RWMutex
+	reporters map[string]Reporter
+}
+
+// NewRegistry returns a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		reporters: make(map[string]Reporter),
+	}
+}
+
+// Register sets the reporter of the given subsystem, replacing any previous one
+func (r *Registry) Register(subsystem string, rep Reporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reporters[subsystem] = rep
+}
+
+// Usage returns the memory usage of every registered subsystem, sorted by subsystem
+func (r *Registry) Usage() []Usage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	usage := make([]Usage, 0, len(r.reporters))
+	for subsystem, rep := range r.reporters {
+		usage = append(usage, Usage{
+			Subsystem: subsystem,
+			Bytes:     rep.MemoryUsage(),
+		})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Subsystem < usage[j].Subsystem
+	})
+	return usage
+}
+
+// ServeHTTP writes the memory usage of the subsystems in the Prometheus text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var buf bytes.Buffer
+
+	buf.WriteString("# HELP gossamer_memory_bytes Approximate memory held by a subsystem.\n")
+	buf.WriteString("# TYPE gossamer_memory_bytes gauge\n")
+	for _, u := range r.Usage() {
+		fmt.Fprintf(&buf, "gossamer_memory_bytes{subsystem=%q} %d\n", u.Subsystem, u.Bytes)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package memstats
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockReporter uint64
+
+func (m mockReporter) MemoryUsage() uint64 {
+	return uint64(m)
+}
+
+func TestRegistry_Usage(t *testing.T) {
+	r := NewRegistry()
+	r.Register(TrieCache, mockReporter(100))
+	r.Register(BlockTree, mockReporter(10))
+	r.Register(TrieCache, mockReporter(200))
+
+	expected := []Usage{
+		{Subsystem: BlockTree, Bytes: 10},
+		{Subsystem: TrieCache, Bytes: 200},
+	}
+	require.Equal(t, expected, r.Usage())
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.Register(PendingBlocks, mockReporter(42))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, "# TYPE gossamer_memory_bytes gauge\n"))
+	require.True(t, strings.Contains(body, "gossamer_memory_bytes{subsystem=\"pending_blocks\"} 42\n"))
+}
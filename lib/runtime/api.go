@@ -0,0 +1,77 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// Names of the runtime APIs used by the node. A runtime lists the APIs it implements in its version.
+const (
+	CoreAPIName                   = "Core"
+	MetadataAPIName               = "Metadata"
+	BlockBuilderAPIName           = "BlockBuilder"
+	TaggedTransactionQueueAPIName = "TaggedTransactionQueue"
+	BabeAPIName                   = "BabeApi"
+	GrandpaAPIName                = "GrandpaApi"
+	SessionKeysAPIName            = "SessionKeys"
+	TransactionPaymentAPIName     = "TransactionPaymentApi"
+	OffchainWorkerAPIName         = "OffchainWorkerApi"
+)
+
+// APIID returns the identifier of the runtime API with the given name, which is the 64-bit blake2b hash of the name
+func APIID(name string) []byte {
+	id, _ := common.Blake2b8([]byte(name))
+	return id
+}
+
+// APIVersion returns the version of the runtime API with the given name, and false if the runtime doesn't implement it
+func (v *VersionAPI) APIVersion(name string) (int32, bool) {
+	id := APIID(name)
+	for _, item := range v.API {
+		if bytes.Equal(item.Name, id) {
+			return item.Ver, true
+		}
+	}
+
+	return 0, false
+}
+
+// HasAPI returns true if the runtime implements the runtime API with the given name
+func (v *VersionAPI) HasAPI(name string) bool {
+	_, ok := v.APIVersion(name)
+	return ok
+}
+
+// CheckCall returns ErrMethodUnavailable if the runtime doesn't implement the API of the given runtime call,
+// eg. BabeApi for BabeApi_configuration
+func (v *VersionAPI) CheckCall(function string) error {
+	i := strings.Index(function, "_")
+	if i <= 0 {
+		return nil
+	}
+
+	if !v.HasAPI(function[:i]) {
+		return fmt.Errorf("%w: %s", ErrMethodUnavailable, function)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIID(t *testing.T) {
+	// identifiers as listed in the version of substrate runtimes
+	require.Equal(t, common.MustHexToBytes("0xdf6acb689907609b"), APIID(CoreAPIName))
+	require.Equal(t, common.MustHexToBytes("0xcbca25e39f142387"), APIID(BabeAPIName))
+	require.Equal(t, common.MustHexToBytes("0xab3c0572291feb8b"), APIID(SessionKeysAPIName))
+	require.Equal(t, common.MustHexToBytes("0x37c8bb1350a9a2a8"), APIID(TransactionPaymentAPIName))
+}
+
+func TestVersionAPI_CheckCall(t *testing.T) {
+	v := &VersionAPI{
+		RuntimeVersion: &Version{},
+		API: []*API_Item{
+			{Name: APIID(CoreAPIName), Ver: 2},
+			{Name: APIID(BabeAPIName), Ver: 1},
+		},
+	}
+
+	ver, ok := v.APIVersion(CoreAPIName)
+	require.True(t, ok)
+	require.Equal(t, int32(2), ver)
+	require.True(t, v.HasAPI(BabeAPIName))
+	require.False(t, v.HasAPI(SessionKeysAPIName))
+
+	require.NoError(t, v.CheckCall(BabeAPIConfiguration))
+	require.NoError(t, v.CheckCall("validate"))
+
+	err := v.CheckCall("TransactionPaymentApi_query_info")
+	require.True(t, errors.Is(err, ErrMethodUnavailable))
+}
@@ -62,11 +62,30 @@ var (
 	BlockBuilderApplyExtrinsic = "BlockBuilder_apply_extrinsic"
 	// BlockBuilderFinalizeBlock is the runtime API call BlockBuilder_finalize_block
 	BlockBuilderFinalizeBlock = "BlockBuilder_finalize_block"
+	// SessionKeysDecodeSessionKeys is the runtime API call SessionKeys_decode_session_keys
+	SessionKeysDecodeSessionKeys = "SessionKeys_decode_session_keys"
+	// OffchainWorkerAPIOffchainWorker is the runtime API call OffchainWorkerApi_offchain_worker
+	OffchainWorkerAPIOffchainWorker = "OffchainWorkerApi_offchain_worker"
 )
 
 // GrandpaAuthorityDataKey is the location of GRANDPA authority data in the storage trie for LEGACY_NODE_RUNTIME and NODE_RUNTIME
 var GrandpaAuthorityDataKey, _ = common.HexToBytes("0x3a6772616e6470615f617574686f726974696573")
 
+// GrandpaCurrentSetIDKey is the location of the current GRANDPA set ID in the storage trie for NODE_RUNTIME
+func GrandpaCurrentSetIDKey() []byte {
+	prefix, _ := common.Twox128Hash([]byte("Grandpa"))
+	key, _ := common.Twox128Hash([]byte("CurrentSetId"))
+	return append(append([]byte{}, prefix...), key...)
+}
+
+// AuthorityDiscoveryKeysKey is the location of the authority discovery keys of the current authorities in the
+// storage trie for NODE_RUNTIME
+func AuthorityDiscoveryKeysKey() []byte {
+	prefix, _ := common.Twox128Hash([]byte("AuthorityDiscovery"))
+	key, _ := common.Twox128Hash([]byte("Keys"))
+	return append(append([]byte{}, prefix...), key...)
+}
+
 // BABEPrefix is the prefix for all BABE related storage values
 var BABEPrefix, _ = common.Twox128Hash([]byte("Babe"))
 
@@ -81,3 +100,10 @@ func BABERandomnessKey() []byte {
 	key, _ := common.Twox128Hash([]byte("Randomness"))
 	return append(BABEPrefix, key...)
 }
+
+// SystemEventsKey is the location of the events deposited in the current block in the storage trie for NODE_RUNTIME
+func SystemEventsKey() []byte {
+	prefix, _ := common.Twox128Hash([]byte("System"))
+	key, _ := common.Twox128Hash([]byte("Events"))
+	return append(append([]byte{}, prefix...), key...)
+}
@@ -29,9 +29,25 @@ var ErrCannotValidateTx = errors.New("could not validate transaction")
 //  value of [1, 0, x]
 var ErrInvalidTransaction = &json2.Error{Code: 1010, Message: "Invalid Transaction"}
 
+// ErrFutureTransaction is returned if the call to runtime function TaggedTransactionQueueValidateTransaction fails with
+// value of [1, 0, 2], ie. the transaction isn't valid yet but may be in a future block
+var ErrFutureTransaction = &json2.Error{Code: 1010, Message: "Invalid Transaction", Data: "Future"}
+
 // ErrUnknownTransaction is returned if the call to runtime function TaggedTransactionQueueValidateTransaction fails with
 //  value of [1, 1, x]
 var ErrUnknownTransaction = &json2.Error{Code: 1011, Message: "Unknown Transaction Validity"}
 
 // ErrNilStorage is returned when the runtime context storage isn't set
 var ErrNilStorage = errors.New("runtime context storage is nil")
+
+// ErrExecutionTimeout is returned when a runtime call doesn't complete within its deadline
+var ErrExecutionTimeout = errors.New("runtime call timed out")
+
+// ErrHeapPagesExceedLimit is returned when the number of heap pages for a runtime exceeds the memory ceiling
+var ErrHeapPagesExceedLimit = errors.New("heap pages exceed maximum memory pages")
+
+// ErrMethodUnavailable is returned when calling a runtime API that the runtime doesn't implement
+var ErrMethodUnavailable = errors.New("method unavailable for this runtime")
+
+// ErrInvalidSessionKeys is returned when the runtime cannot decode a session keys blob
+var ErrInvalidSessionKeys = errors.New("invalid session keys")
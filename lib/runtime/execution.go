@@ -0,0 +1,168 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExecutionStrategy determines which runtime implementation is used to execute runtime calls
+type ExecutionStrategy string
+
+const (
+	// ExecutionWasm executes with the wasm runtime stored on-chain
+	ExecutionWasm ExecutionStrategy = "wasm"
+	// ExecutionNative executes with the native runtime, failing if it is not available
+	ExecutionNative ExecutionStrategy = "native"
+	// ExecutionNativeElseWasm executes with the native runtime if it is available, otherwise with the wasm runtime
+	ExecutionNativeElseWasm ExecutionStrategy = "native-else-wasm"
+)
+
+// DefaultExecutionStrategy is the strategy used for any context that isn't configured
+const DefaultExecutionStrategy = ExecutionWasm
+
+// ErrNativeRuntimeUnavailable is returned when an execution strategy requires a native runtime, but none is available
+var ErrNativeRuntimeUnavailable = errors.New("native runtime is not available")
+
+// ParseExecutionStrategy returns the execution strategy with the given name, which is case-insensitive.
+// An empty name returns DefaultExecutionStrategy.
+func ParseExecutionStrategy(name string) (ExecutionStrategy, error) {
+	switch s := ExecutionStrategy(strings.ToLower(name)); s {
+	case "":
+		return DefaultExecutionStrategy, nil
+	case ExecutionWasm, ExecutionNative, ExecutionNativeElseWasm:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid execution strategy %q", name)
+	}
+}
+
+// requiresNative returns true if the strategy can't be executed without a native runtime
+func (s ExecutionStrategy) requiresNative() bool {
+	return s == ExecutionNative
+}
+
+// ExecutionContext is the context in which a runtime call is made
+type ExecutionContext byte
+
+const (
+	// ContextSyncing is used when importing blocks during initial sync
+	ContextSyncing ExecutionContext = iota
+	// ContextImportBlock is used when importing blocks received from peers after sync
+	ContextImportBlock
+	// ContextBlockConstruction is used when authoring blocks
+	ContextBlockConstruction
+	// ContextOther is used for every other call, such as RPC queries and transaction validation
+	ContextOther
+)
+
+func (c ExecutionContext) String() string {
+	switch c {
+	case ContextSyncing:
+		return "syncing"
+	case ContextImportBlock:
+		return "import-block"
+	case ContextBlockConstruction:
+		return "block-construction"
+	case ContextOther:
+		return "other"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// ExecutionStrategies holds the execution strategy for each execution context
+type ExecutionStrategies struct {
+	Syncing           ExecutionStrategy
+	ImportBlock       ExecutionStrategy
+	BlockConstruction ExecutionStrategy
+	Other             ExecutionStrategy
+}
+
+// Strategy returns the execution strategy for the given context. Contexts that aren't set use
+// DefaultExecutionStrategy.
+func (s *ExecutionStrategies) Strategy(ctx ExecutionContext) ExecutionStrategy {
+	var strategy ExecutionStrategy
+	switch ctx {
+	case ContextSyncing:
+		strategy = s.Syncing
+	case ContextImportBlock:
+		strategy = s.ImportBlock
+	case ContextBlockConstruction:
+		strategy = s.BlockConstruction
+	case ContextOther:
+		strategy = s.Other
+	}
+
+	if strategy == "" {
+		return DefaultExecutionStrategy
+	}
+
+	return strategy
+}
+
+// executionContexts is the list of all execution contexts
+var executionContexts = []ExecutionContext{
+	ContextSyncing,
+	ContextImportBlock,
+	ContextBlockConstruction,
+	ContextOther,
+}
+
+// Executor selects the runtime instance used in each execution context according to the configured strategies
+type Executor struct {
+	strategies ExecutionStrategies
+	wasm       LegacyInstance
+	native     LegacyInstance
+}
+
+// NewExecutor returns a new Executor for the given wasm and native instances. The native instance may be nil,
+// in which case an error is returned if any context's strategy requires it.
+func NewExecutor(strategies ExecutionStrategies, wasm, native LegacyInstance) (*Executor, error) {
+	if wasm == nil {
+		return nil, errors.New("wasm runtime instance is nil")
+	}
+
+	if native == nil {
+		for _, ctx := range executionContexts {
+			if strategies.Strategy(ctx).requiresNative() {
+				return nil, fmt.Errorf("cannot use execution strategy %s for %s: %w",
+					strategies.Strategy(ctx), ctx, ErrNativeRuntimeUnavailable)
+			}
+		}
+	}
+
+	return &Executor{
+		strategies: strategies,
+		wasm:       wasm,
+		native:     native,
+	}, nil
+}
+
+// Instance returns the runtime instance to use in the given context
+func (e *Executor) Instance(ctx ExecutionContext) LegacyInstance {
+	switch e.strategies.Strategy(ctx) {
+	case ExecutionNative, ExecutionNativeElseWasm:
+		if e.native != nil {
+			return e.native
+		}
+	}
+
+	return e.wasm
+}
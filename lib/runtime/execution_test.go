@@ -0,0 +1,89 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockInstance struct {
+	LegacyInstance
+	name string
+}
+
+func TestParseExecutionStrategy(t *testing.T) {
+	s, err := ParseExecutionStrategy("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultExecutionStrategy, s)
+
+	s, err = ParseExecutionStrategy("Native-Else-Wasm")
+	require.NoError(t, err)
+	require.Equal(t, ExecutionNativeElseWasm, s)
+
+	_, err = ParseExecutionStrategy("both")
+	require.Error(t, err)
+
+	_, err = ParseExecutionStrategy("interpreted")
+	require.Error(t, err)
+}
+
+func TestExecutionStrategies_Strategy(t *testing.T) {
+	strategies := ExecutionStrategies{
+		Syncing: ExecutionNativeElseWasm,
+	}
+
+	require.Equal(t, ExecutionNativeElseWasm, strategies.Strategy(ContextSyncing))
+	for _, ctx := range executionContexts[1:] {
+		require.Equal(t, DefaultExecutionStrategy, strategies.Strategy(ctx), ctx.String())
+	}
+}
+
+func TestNewExecutor_NativeUnavailable(t *testing.T) {
+	wasm := &mockInstance{name: "wasm"}
+
+	_, err := NewExecutor(ExecutionStrategies{ImportBlock: ExecutionNative}, wasm, nil)
+	require.True(t, errors.Is(err, ErrNativeRuntimeUnavailable))
+
+	_, err = NewExecutor(ExecutionStrategies{}, nil, nil)
+	require.Error(t, err)
+}
+
+func TestExecutor_Instance(t *testing.T) {
+	wasm := &mockInstance{name: "wasm"}
+	native := &mockInstance{name: "native"}
+
+	strategies := ExecutionStrategies{
+		Syncing:           ExecutionNativeElseWasm,
+		ImportBlock:       ExecutionNative,
+		BlockConstruction: ExecutionWasm,
+	}
+
+	e, err := NewExecutor(strategies, wasm, native)
+	require.NoError(t, err)
+	require.Equal(t, native, e.Instance(ContextSyncing))
+	require.Equal(t, native, e.Instance(ContextImportBlock))
+	require.Equal(t, wasm, e.Instance(ContextBlockConstruction))
+	require.Equal(t, wasm, e.Instance(ContextOther))
+
+	// native-else-wasm falls back to wasm when there is no native runtime
+	e, err = NewExecutor(ExecutionStrategies{Syncing: ExecutionNativeElseWasm}, wasm, nil)
+	require.NoError(t, err)
+	require.Equal(t, wasm, e.Instance(ContextSyncing))
+}
@@ -0,0 +1,447 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/chaindb"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/trie"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+// wasm value types, see https://webassembly.github.io/spec/core/binary/types.html
+const (
+	WasmVoid byte = 0x00
+	WasmI32  byte = 0x7f
+	WasmI64  byte = 0x7e
+)
+
+// HostAPISignature is the wasm signature of a host function
+type HostAPISignature struct {
+	Result byte
+	Params []byte
+}
+
+// HostAPISignatures are the signatures of the host functions of the v0.8 runtime
+var HostAPISignatures = map[string]HostAPISignature{
+	"ext_allocator_free_version_1":                            {WasmVoid, []byte{WasmI32}},
+	"ext_allocator_malloc_version_1":                          {WasmI32, []byte{WasmI32}},
+	"ext_crypto_ed25519_generate_version_1":                   {WasmI32, []byte{WasmI32, WasmI64}},
+	"ext_crypto_ed25519_public_keys_version_1":                {WasmI64, []byte{WasmI32}},
+	"ext_crypto_ed25519_sign_version_1":                       {WasmI64, []byte{WasmI32, WasmI32, WasmI64}},
+	"ext_crypto_ed25519_verify_version_1":                     {WasmI32, []byte{WasmI32, WasmI64, WasmI32}},
+	"ext_crypto_finish_batch_verify_version_1":                {WasmI32, nil},
+	"ext_crypto_secp256k1_ecdsa_recover_compressed_version_1": {WasmI64, []byte{WasmI32, WasmI32}},
+	"ext_crypto_secp256k1_ecdsa_recover_version_1":            {WasmI64, []byte{WasmI32, WasmI32}},
+	"ext_crypto_sr25519_generate_version_1":                   {WasmI32, []byte{WasmI32, WasmI64}},
+	"ext_crypto_sr25519_public_keys_version_1":                {WasmI64, []byte{WasmI32}},
+	"ext_crypto_sr25519_sign_version_1":                       {WasmI64, []byte{WasmI32, WasmI32, WasmI64}},
+	"ext_crypto_sr25519_verify_version_1":                     {WasmI32, []byte{WasmI32, WasmI64, WasmI32}},
+	"ext_crypto_sr25519_verify_version_2":                     {WasmI32, []byte{WasmI32, WasmI64, WasmI32}},
+	"ext_crypto_start_batch_verify_version_1":                 {WasmVoid, nil},
+	"ext_default_child_storage_clear_prefix_version_1":        {WasmVoid, []byte{WasmI64, WasmI64}},
+	"ext_default_child_storage_clear_version_1":               {WasmVoid, []byte{WasmI64, WasmI64}},
+	"ext_default_child_storage_exists_version_1":              {WasmI32, []byte{WasmI64, WasmI64}},
+	"ext_default_child_storage_get_version_1":                 {WasmI64, []byte{WasmI64, WasmI64}},
+	"ext_default_child_storage_next_key_version_1":            {WasmI64, []byte{WasmI64, WasmI64}},
+	"ext_default_child_storage_read_version_1":                {WasmI64, []byte{WasmI64, WasmI64, WasmI64, WasmI32}},
+	"ext_default_child_storage_root_version_1":                {WasmI64, []byte{WasmI64}},
+	"ext_default_child_storage_set_version_1":                 {WasmVoid, []byte{WasmI64, WasmI64, WasmI64}},
+	"ext_default_child_storage_storage_kill_version_1":        {WasmVoid, []byte{WasmI64}},
+	"ext_hashing_blake2_128_version_1":                        {WasmI32, []byte{WasmI64}},
+	"ext_hashing_blake2_256_version_1":                        {WasmI32, []byte{WasmI64}},
+	"ext_hashing_keccak_256_version_1":                        {WasmI32, []byte{WasmI64}},
+	"ext_hashing_sha2_256_version_1":                          {WasmI32, []byte{WasmI64}},
+	"ext_hashing_twox_128_version_1":                          {WasmI32, []byte{WasmI64}},
+	"ext_hashing_twox_256_version_1":                          {WasmI32, []byte{WasmI64}},
+	"ext_hashing_twox_64_version_1":                           {WasmI32, []byte{WasmI64}},
+	"ext_logging_log_version_1":                               {WasmVoid, []byte{WasmI32, WasmI64, WasmI64}},
+	"ext_misc_print_hex_version_1":                            {WasmVoid, []byte{WasmI64}},
+	"ext_misc_print_num_version_1":                            {WasmVoid, []byte{WasmI64}},
+	"ext_misc_print_utf8_version_1":                           {WasmVoid, []byte{WasmI64}},
+	"ext_misc_runtime_version_version_1":                      {WasmI64, []byte{WasmI64}},
+	"ext_offchain_index_set_version_1":                        {WasmVoid, []byte{WasmI64, WasmI64}},
+	"ext_offchain_is_validator_version_1":                     {WasmI32, nil},
+	"ext_offchain_local_storage_compare_and_set_version_1":    {WasmI32, []byte{WasmI32, WasmI64, WasmI64, WasmI64}},
+	"ext_offchain_local_storage_get_version_1":                {WasmI64, []byte{WasmI32, WasmI64}},
+	"ext_offchain_local_storage_set_version_1":                {WasmVoid, []byte{WasmI32, WasmI64, WasmI64}},
+	"ext_offchain_network_state_version_1":                    {WasmI64, nil},
+	"ext_offchain_random_seed_version_1":                      {WasmI32, nil},
+	"ext_offchain_submit_transaction_version_1":               {WasmI64, []byte{WasmI64}},
+	"ext_offchain_timestamp_version_1":                        {WasmI64, nil},
+	"ext_sandbox_instance_teardown_version_1":                 {WasmVoid, []byte{WasmI32}},
+	"ext_sandbox_instantiate_version_1":                       {WasmI32, []byte{WasmI32, WasmI64, WasmI64, WasmI32}},
+	"ext_sandbox_invoke_version_1":                            {WasmI32, []byte{WasmI32, WasmI64, WasmI64, WasmI32, WasmI32, WasmI32}},
+	"ext_sandbox_memory_get_version_1":                        {WasmI32, []byte{WasmI32, WasmI32, WasmI32, WasmI32}},
+	"ext_sandbox_memory_new_version_1":                        {WasmI32, []byte{WasmI32, WasmI32}},
+	"ext_sandbox_memory_set_version_1":                        {WasmI32, []byte{WasmI32, WasmI32, WasmI32, WasmI32}},
+	"ext_sandbox_memory_teardown_version_1":                   {WasmVoid, []byte{WasmI32}},
+	"ext_storage_append_version_1":                            {WasmVoid, []byte{WasmI64, WasmI64}},
+	"ext_storage_changes_root_version_1":                      {WasmI64, []byte{WasmI64}},
+	"ext_storage_clear_prefix_version_1":                      {WasmVoid, []byte{WasmI64}},
+	"ext_storage_clear_version_1":                             {WasmVoid, []byte{WasmI64}},
+	"ext_storage_commit_transaction_version_1":                {WasmVoid, nil},
+	"ext_storage_exists_version_1":                            {WasmI32, []byte{WasmI64}},
+	"ext_storage_get_version_1":                               {WasmI64, []byte{WasmI64}},
+	"ext_storage_next_key_version_1":                          {WasmI64, []byte{WasmI64}},
+	"ext_storage_read_version_1":                              {WasmI64, []byte{WasmI64, WasmI64, WasmI32}},
+	"ext_storage_rollback_transaction_version_1":              {WasmVoid, nil},
+	"ext_storage_root_version_1":                              {WasmI64, nil},
+	"ext_storage_set_version_1":                               {WasmVoid, []byte{WasmI64, WasmI64}},
+	"ext_storage_start_transaction_version_1":                 {WasmVoid, nil},
+	"ext_trie_blake2_256_ordered_root_version_1":              {WasmI32, []byte{WasmI64}},
+	"ext_trie_blake2_256_root_version_1":                      {WasmI32, []byte{WasmI64}},
+}
+
+// HostAPICall is a call to a host function. Arguments starting with 0x are written to memory and passed as a
+// pointer or a span depending on the parameter type, any other argument is passed as an integer.
+type HostAPICall struct {
+	Function string   `json:"function"`
+	Args     []string `json:"args"`
+}
+
+// HostAPICase is a host function call along with the output produced by Substrate's host API for the same call,
+// any difference from gossamer's output is a semantic drift in the host API. The storage is set and the calls in
+// Before are made before the call. Output is the data the returned pointer or span refers to, Result is the
+// returned value of host functions that don't return a pointer, ArgsOut is the data the host function wrote to
+// the memory of the arguments with the given index and the expected storage is checked after the call.
+// OffchainStorage is the persistent offchain storage and an empty expected value means that the key must not be set.
+type HostAPICase struct {
+	HostAPICall
+	Name                    string                       `json:"name"`
+	Storage                 map[string]string            `json:"storage"`
+	ChildStorage            map[string]map[string]string `json:"childStorage"`
+	OffchainStorage         map[string]string            `json:"offchainStorage"`
+	Before                  []HostAPICall                `json:"before"`
+	Output                  string                       `json:"output"`
+	Result                  *int64                       `json:"result"`
+	ArgsOut                 map[int]string               `json:"argsOut"`
+	ExpectedStorage         map[string]string            `json:"expectedStorage"`
+	ExpectedChildStorage    map[string]map[string]string `json:"expectedChildStorage"`
+	ExpectedOffchainStorage map[string]string            `json:"expectedOffchainStorage"`
+}
+
+// HostAPIInstance is a runtime instance of a module built by HostAPIModule
+type HostAPIInstance interface {
+	// Call calls the exported function that passes its parameters through to the given host function
+	Call(function string, args []interface{}) (int64, error)
+	Memory() []byte
+	Allocate(size uint32) (uint32, error)
+	Clear()
+}
+
+// LoadHostAPICases returns the host API conformance cases in the given file
+func LoadHostAPICases(t testing.TB, fp string) []*HostAPICase {
+	data, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+
+	var cases []*HostAPICase
+	err = json.Unmarshal(data, &cases)
+	require.NoError(t, err)
+	return cases
+}
+
+// Functions returns the host functions called by the case
+func (c *HostAPICase) Functions() []string {
+	functions := []string{c.Function}
+	for _, call := range c.Before {
+		if !containsString(functions, call.Function) {
+			functions = append(functions, call.Function)
+		}
+	}
+	return functions
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NewConfig returns an instance configuration with the case's storage and offchain storage
+func (c *HostAPICase) NewConfig(t testing.TB) InstanceConfig {
+	storage := NewTestRuntimeStorage(t, nil)
+	for k, v := range c.Storage {
+		err := storage.Set(mustHexToBytes(t, k), mustHexToBytes(t, v))
+		require.NoError(t, err)
+	}
+
+	for keyToChild, entries := range c.ChildStorage {
+		err := storage.SetChild(mustHexToBytes(t, keyToChild), trie.NewEmptyTrie())
+		require.NoError(t, err)
+
+		for k, v := range entries {
+			err = storage.SetChildStorage(mustHexToBytes(t, keyToChild), mustHexToBytes(t, k), mustHexToBytes(t, v))
+			require.NoError(t, err)
+		}
+	}
+
+	ns := NodeStorage{
+		LocalStorage:      chaindb.NewMemDatabase(),
+		PersistentStorage: chaindb.NewMemDatabase(),
+	}
+	for k, v := range c.OffchainStorage {
+		err := ns.PersistentStorage.Put(mustHexToBytes(t, k), mustHexToBytes(t, v))
+		require.NoError(t, err)
+	}
+
+	return InstanceConfig{
+		Storage:     storage,
+		Keystore:    keystore.NewGenericKeystore("test"),
+		LogLvl:      log.LvlError,
+		NodeStorage: ns,
+	}
+}
+
+// RunHostAPICase makes the case's calls on the given instance, which must have been created with the case's
+// configuration, and checks the outputs and storage against the expected ones
+func RunHostAPICase(t testing.TB, in HostAPIInstance, c *HostAPICase, cfg InstanceConfig) {
+	for i := range c.Before {
+		CallHostAPI(t, in, &c.Before[i])
+	}
+
+	var expected []byte
+	if c.Output != "" {
+		expected = mustHexToBytes(t, c.Output)
+	}
+
+	res, out, argsOut := callHostAPI(t, in, &c.HostAPICall, c.Output != "", len(expected), c.ArgsOut)
+	if c.Output != "" {
+		require.Equal(t, expected, out, "output of %s", c.Function)
+	}
+
+	if c.Result != nil {
+		require.Equal(t, *c.Result, res, "result of %s", c.Function)
+	}
+
+	for i, v := range c.ArgsOut {
+		require.Equal(t, mustHexToBytes(t, v), argsOut[i], "argument %d of %s", i, c.Function)
+	}
+
+	for k, v := range c.ExpectedStorage {
+		value, err := cfg.Storage.Get(mustHexToBytes(t, k))
+		requireStored(t, v, value, err, "storage key %s", k)
+	}
+
+	for keyToChild, entries := range c.ExpectedChildStorage {
+		for k, v := range entries {
+			value, err := cfg.Storage.GetChildStorage(mustHexToBytes(t, keyToChild), mustHexToBytes(t, k))
+			requireStored(t, v, value, err, "child storage %s key %s", keyToChild, k)
+		}
+	}
+
+	for k, v := range c.ExpectedOffchainStorage {
+		value, err := cfg.NodeStorage.PersistentStorage.Get(mustHexToBytes(t, k))
+		requireStored(t, v, value, err, "offchain storage key %s", k)
+	}
+}
+
+// requireStored checks a storage value, a missing key may be returned as an error or an empty value
+func requireStored(t testing.TB, expected string, value []byte, err error, msgAndArgs ...interface{}) {
+	if expected == "" {
+		require.Empty(t, value, msgAndArgs...)
+		return
+	}
+
+	require.NoError(t, err, msgAndArgs...)
+	require.Equal(t, mustHexToBytes(t, expected), value, msgAndArgs...)
+}
+
+// CallHostAPI writes the call's arguments to memory and calls the host function
+func CallHostAPI(t testing.TB, in HostAPIInstance, call *HostAPICall) {
+	callHostAPI(t, in, call, false, 0, nil)
+}
+
+// callHostAPI calls the host function and returns its result, the output the result refers to if output is set
+// and the data of the arguments in argsOut after the call. Host functions returning a pointer rather than a span
+// write outputs of a fixed size, which must be given.
+func callHostAPI(t testing.TB, in HostAPIInstance, call *HostAPICall, output bool, size int,
+	argsOut map[int]string) (int64, []byte, map[int][]byte) {
+	sig, ok := HostAPISignatures[call.Function]
+	require.True(t, ok, "no signature for host function %s", call.Function)
+	require.Len(t, call.Args, len(sig.Params), "arguments of %s", call.Function)
+
+	defer in.Clear()
+
+	args := make([]interface{}, len(call.Args))
+	spans := make(map[int][2]uint32)
+	for i, arg := range call.Args {
+		if !strings.HasPrefix(arg, "0x") {
+			n, err := strconv.ParseInt(arg, 10, 64)
+			require.NoError(t, err, "argument %d of %s", i, call.Function)
+			args[i] = wasmValue(sig.Params[i], n)
+			continue
+		}
+
+		data := mustHexToBytes(t, arg)
+		if len(data) == 0 {
+			args[i] = wasmValue(sig.Params[i], 0)
+			continue
+		}
+
+		ptr, err := in.Allocate(uint32(len(data)))
+		require.NoError(t, err)
+		copy(in.Memory()[ptr:ptr+uint32(len(data))], data)
+		spans[i] = [2]uint32{ptr, uint32(len(data))}
+
+		if sig.Params[i] == WasmI32 {
+			args[i] = int32(ptr)
+		} else {
+			args[i] = int64(ptr) | int64(len(data))<<32
+		}
+	}
+
+	res, err := in.Call(call.Function, args)
+	require.NoError(t, err, "call to %s", call.Function)
+
+	// the allocator is cleared on return, so the outputs must not alias instance memory
+	memory := in.Memory()
+	var out []byte
+	if output {
+		ptr, length := uint64(uint32(res)), uint64(size)
+		if sig.Result == WasmI64 {
+			length = uint64(res) >> 32
+		}
+
+		require.LessOrEqual(t, ptr+length, uint64(len(memory)), "output of %s is out of bounds", call.Function)
+		out = append([]byte{}, memory[ptr:ptr+length]...)
+	}
+
+	written := make(map[int][]byte)
+	for i := range argsOut {
+		span, ok := spans[i]
+		require.True(t, ok, "argument %d of %s isn't in memory", i, call.Function)
+		written[i] = append([]byte{}, memory[span[0]:span[0]+span[1]]...)
+	}
+
+	return res, out, written
+}
+
+func wasmValue(typ byte, n int64) interface{} {
+	if typ == WasmI32 {
+		return int32(n)
+	}
+	return n
+}
+
+func mustHexToBytes(t testing.TB, in string) []byte {
+	out, err := common.HexToBytes(in)
+	require.NoError(t, err, in)
+	return out
+}
+
+// wasmUint encodes n as an unsigned LEB128 integer
+func wasmUint(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func wasmName(name string) []byte {
+	return append(wasmUint(len(name)), name...)
+}
+
+func wasmSection(id byte, content []byte) []byte {
+	return append(append([]byte{id}, wasmUint(len(content))...), content...)
+}
+
+func wasmVec(count int, items []byte) []byte {
+	return append(wasmUint(count), items...)
+}
+
+// HostAPIModule returns a wasm module that imports memory and the given host functions in order, and exports
+// a function for each of them under the host function's name that passes its parameters through to the host
+// function and returns its result. It also exports a table holding the first of these functions, which sandboxed
+// modules can be given as their dispatch thunk.
+func HostAPIModule(functions []string) ([]byte, error) {
+	n := len(functions)
+
+	var types, funcs, exports, code []byte
+	imports := append(wasmName("env"), wasmName("memory")...)
+	imports = append(imports, 0x02, 0x00, 0x00)
+
+	for i, function := range functions {
+		sig, ok := HostAPISignatures[function]
+		if !ok {
+			return nil, fmt.Errorf("no signature for host function %s", function)
+		}
+
+		types = append(types, 0x60)
+		types = append(types, wasmVec(len(sig.Params), sig.Params)...)
+		if sig.Result == WasmVoid {
+			types = append(types, 0)
+		} else {
+			types = append(types, 1, sig.Result)
+		}
+
+		imports = append(imports, wasmName("env")...)
+		imports = append(imports, wasmName(function)...)
+		imports = append(imports, 0x00)
+		imports = append(imports, wasmUint(i)...)
+
+		funcs = append(funcs, wasmUint(i)...)
+
+		exports = append(exports, wasmName(function)...)
+		exports = append(exports, 0x00)
+		exports = append(exports, wasmUint(n+i)...)
+
+		body := []byte{0}
+		for p := range sig.Params {
+			body = append(body, 0x20)
+			body = append(body, wasmUint(p)...)
+		}
+		body = append(body, 0x10)
+		body = append(body, wasmUint(i)...)
+		body = append(body, 0x0b)
+		code = append(code, wasmVec(len(body), body)...)
+	}
+
+	exports = append(exports, wasmName("__indirect_function_table")...)
+	exports = append(exports, 0x01, 0x00)
+
+	table := []byte{0x70, 0x00, 0x01}
+	elements := append([]byte{0x00, 0x41, 0x00, 0x0b}, wasmVec(1, wasmUint(n))...)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, wasmSection(1, wasmVec(n, types))...)
+	module = append(module, wasmSection(2, wasmVec(n+1, imports))...)
+	module = append(module, wasmSection(3, wasmVec(n, funcs))...)
+	module = append(module, wasmSection(4, wasmVec(1, table))...)
+	module = append(module, wasmSection(7, wasmVec(n+1, exports))...)
+	module = append(module, wasmSection(9, wasmVec(1, elements))...)
+	module = append(module, wasmSection(10, wasmVec(n, code))...)
+	return module, nil
+}
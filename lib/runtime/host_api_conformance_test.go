@@ -0,0 +1,61 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const hostAPIFixtures = "testdata/host_api_conformance.json"
+
+func TestHostAPIConformance_Coverage(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, c := range LoadHostAPICases(t, hostAPIFixtures) {
+		calls := append([]HostAPICall{c.HostAPICall}, c.Before...)
+		for _, call := range calls {
+			sig, ok := HostAPISignatures[call.Function]
+			require.True(t, ok, "%s: no signature for host function %s", c.Name, call.Function)
+			require.Len(t, call.Args, len(sig.Params), "%s: arguments of %s", c.Name, call.Function)
+		}
+
+		covered[c.Function] = true
+	}
+
+	for function := range HostAPISignatures {
+		require.True(t, covered[function], "no fixtures for host function %s", function)
+	}
+}
+
+func TestHostAPIModule(t *testing.T) {
+	functions := make([]string, 0, len(HostAPISignatures))
+	for function := range HostAPISignatures {
+		functions = append(functions, function)
+	}
+
+	module, err := HostAPIModule(functions)
+	require.NoError(t, err)
+
+	// the module imports its memory, which doesn't declare any initial pages
+	pages, err := InitialMemoryPages(module)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), pages)
+
+	_, err = HostAPIModule([]string{"ext_unknown_version_1"})
+	require.Error(t, err)
+}
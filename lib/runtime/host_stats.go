@@ -0,0 +1,133 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHostStats is the HostStats that a node records the host function calls of its runtime instances in, if
+// host function stats are enabled
+var DefaultHostStats = NewHostStats()
+
+// HostFuncStats holds the number of calls to a host function and the time spent in them
+type HostFuncStats struct {
+	Name  string
+	Calls uint64
+	Time  time.Duration
+}
+
+// HostStats counts and times the calls to the host functions of the runtime instances it's configured for,
+// both since it was created and since the last block, see TakeBlock.
+type HostStats struct {
+	mu    sync.Mutex
+	total map[string]*HostFuncStats
+	block map[string]*HostFuncStats
+}
+
+// NewHostStats returns a new HostStats
+func NewHostStats() *HostStats {
+	return &HostStats{
+		total: make(map[string]*HostFuncStats),
+		block: make(map[string]*HostFuncStats),
+	}
+}
+
+// Observe records a call to the given host function that took the given time
+func (s *HostStats) Observe(name string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observeHostFunc(s.total, name, d)
+	observeHostFunc(s.block, name, d)
+}
+
+func observeHostFunc(stats map[string]*HostFuncStats, name string, d time.Duration) {
+	fs, has := stats[name]
+	if !has {
+		fs = &HostFuncStats{Name: name}
+		stats[name] = fs
+	}
+
+	fs.Calls++
+	fs.Time += d
+}
+
+// Stats returns the stats of every host function called since the HostStats was created, sorted by decreasing
+// time spent in the function
+func (s *HostStats) Stats() []HostFuncStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedHostStats(s.total)
+}
+
+// TakeBlock returns the stats of every host function called since the last call to TakeBlock, sorted by
+// decreasing time spent in the function, and resets them. It's called once a block has been executed; calls
+// made by other runtime calls in between, eg. to validate transactions, are included.
+func (s *HostStats) TakeBlock() []HostFuncStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := sortedHostStats(s.block)
+	s.block = make(map[string]*HostFuncStats)
+	return stats
+}
+
+func sortedHostStats(m map[string]*HostFuncStats) []HostFuncStats {
+	stats := make([]HostFuncStats, 0, len(m))
+	for _, fs := range m {
+		stats = append(stats, *fs)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Time != stats[j].Time {
+			return stats[i].Time > stats[j].Time
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// ServeHTTP writes the host function stats in the Prometheus text format
+func (s *HostStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	stats := s.Stats()
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	buf.WriteString("# HELP gossamer_runtime_host_calls_total Number of calls to runtime host functions.\n")
+	buf.WriteString("# TYPE gossamer_runtime_host_calls_total counter\n")
+	for _, fs := range stats {
+		fmt.Fprintf(&buf, "gossamer_runtime_host_calls_total{function=%q} %d\n", fs.Name, fs.Calls)
+	}
+
+	buf.WriteString("# HELP gossamer_runtime_host_seconds_total Time spent in runtime host functions.\n")
+	buf.WriteString("# TYPE gossamer_runtime_host_seconds_total counter\n")
+	for _, fs := range stats {
+		fmt.Fprintf(&buf, "gossamer_runtime_host_seconds_total{function=%q} %g\n", fs.Name, fs.Time.Seconds())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostStats(t *testing.T) {
+	s := NewHostStats()
+	s.Observe("ext_storage_get_version_1", time.Millisecond)
+	s.Observe("ext_storage_get_version_1", time.Millisecond)
+	s.Observe("ext_hashing_blake2_256_version_1", 5*time.Millisecond)
+
+	expected := []HostFuncStats{
+		{Name: "ext_hashing_blake2_256_version_1", Calls: 1, Time: 5 * time.Millisecond},
+		{Name: "ext_storage_get_version_1", Calls: 2, Time: 2 * time.Millisecond},
+	}
+	require.Equal(t, expected, s.TakeBlock())
+	require.Empty(t, s.TakeBlock())
+
+	// the totals aren't reset with the block stats
+	s.Observe("ext_storage_set_version_1", time.Millisecond)
+	require.Equal(t, []HostFuncStats{{Name: "ext_storage_set_version_1", Calls: 1, Time: time.Millisecond}}, s.TakeBlock())
+	require.Equal(t, 3, len(s.Stats()))
+	require.Equal(t, expected[0], s.Stats()[0])
+}
+
+func TestHostStats_ServeHTTP(t *testing.T) {
+	s := NewHostStats()
+	s.Observe("ext_storage_get_version_1", 1500*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, `gossamer_runtime_host_calls_total{function="ext_storage_get_version_1"} 1`))
+	require.True(t, strings.Contains(body, `gossamer_runtime_host_seconds_total{function="ext_storage_get_version_1"} 1.5`))
+}
@@ -95,5 +95,5 @@ type BasicStorage interface {
 
 // TransactionState interface for adding transactions to pool
 type TransactionState interface {
-	AddToPool(vt *transaction.ValidTransaction) common.Hash
+	AddToPool(vt *transaction.ValidTransaction) (common.Hash, error)
 }
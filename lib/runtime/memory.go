@@ -16,8 +16,242 @@
 
 package runtime
 
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// PageSize is the size of a wasm memory page in bytes
+const PageSize = 65536
+
+// DefaultHeapPages is the number of wasm memory pages given to a runtime instance on top of the memory the runtime
+// declares, when no value is configured and there is no :heappages value in storage
+const DefaultHeapPages = uint32(20)
+
+// DefaultMaxMemoryPages is the maximum number of wasm memory pages a runtime instance may use (256 MiB)
+const DefaultMaxMemoryPages = uint32(4096)
+
 // Memory is a raw memory interface
 type Memory interface {
 	Data() []byte
 	Length() uint32
 }
+
+// GetHeapPages returns the number of wasm memory pages to give the runtime on top of the memory it declares. A value
+// set in the configuration overrides the :heappages value in storage, otherwise DefaultHeapPages is used. An error
+// is returned if the number of pages exceeds the configured memory ceiling.
+func (cfg *InstanceConfig) GetHeapPages() (uint32, error) {
+	heapPages := cfg.HeapPages
+
+	if heapPages == 0 && cfg.Storage != nil {
+		enc, err := cfg.Storage.Get(common.HeapPagesKey)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(enc) != 0 {
+			if len(enc) != 8 {
+				return 0, fmt.Errorf("invalid %s value: %x", common.HeapPagesKey, enc)
+			}
+
+			pages := binary.LittleEndian.Uint64(enc)
+			if pages > uint64(^uint32(0)) {
+				return 0, ErrHeapPagesExceedLimit
+			}
+			heapPages = uint32(pages)
+		}
+	}
+
+	if heapPages == 0 {
+		heapPages = DefaultHeapPages
+	}
+
+	if heapPages > cfg.GetMaxMemoryPages() {
+		return 0, ErrHeapPagesExceedLimit
+	}
+
+	return heapPages, nil
+}
+
+// GetMaxMemoryPages returns the maximum number of wasm memory pages the runtime may use
+func (cfg *InstanceConfig) GetMaxMemoryPages() uint32 {
+	if cfg.MaxMemoryPages == 0 {
+		return DefaultMaxMemoryPages
+	}
+
+	return cfg.MaxMemoryPages
+}
+
+// GetMemoryPages returns the number of wasm memory pages to instantiate the runtime with the given code with, which
+// are the initial pages of the memory declared by the code plus the heap pages, as Substrate does
+func (cfg *InstanceConfig) GetMemoryPages(code []byte) (uint32, error) {
+	heapPages, err := cfg.GetHeapPages()
+	if err != nil {
+		return 0, err
+	}
+
+	initial, err := InitialMemoryPages(code)
+	if err != nil {
+		return 0, err
+	}
+
+	if uint64(initial)+uint64(heapPages) > uint64(cfg.GetMaxMemoryPages()) {
+		return 0, ErrHeapPagesExceedLimit
+	}
+
+	return initial + heapPages, nil
+}
+
+// wasm module section IDs and import kinds, see https://webassembly.github.io/spec/core/binary/modules.html
+const (
+	wasmImportSection = 2
+	wasmMemorySection = 5
+
+	wasmImportFunc   = 0
+	wasmImportTable  = 1
+	wasmImportMemory = 2
+	wasmImportGlobal = 3
+)
+
+var errInvalidWasm = errors.New("invalid wasm module")
+
+// InitialMemoryPages returns the initial number of pages of the memory that the given wasm code imports or defines,
+// or 0 if it has no memory
+func InitialMemoryPages(code []byte) (uint32, error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], []byte("\x00asm")) {
+		return 0, errInvalidWasm
+	}
+
+	r := bytes.NewReader(code[8:])
+	for r.Len() > 0 {
+		id, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		size, err := readULEB128(r)
+		if err != nil {
+			return 0, err
+		}
+
+		if uint64(r.Len()) < size {
+			return 0, errInvalidWasm
+		}
+
+		section := make([]byte, size)
+		if _, err = io.ReadFull(r, section); err != nil {
+			return 0, err
+		}
+
+		switch id {
+		case wasmImportSection:
+			pages, has, err := importedMemoryPages(bytes.NewReader(section))
+			if err != nil || has {
+				return pages, err
+			}
+		case wasmMemorySection:
+			sr := bytes.NewReader(section)
+			count, err := readULEB128(sr)
+			if err != nil || count == 0 {
+				return 0, err
+			}
+			return readLimitsMin(sr)
+		}
+	}
+
+	return 0, nil
+}
+
+// importedMemoryPages returns the initial number of pages of the memory imported in the given import section
+func importedMemoryPages(r *bytes.Reader) (uint32, bool, error) {
+	count, err := readULEB128(r)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for i := uint64(0); i < count; i++ {
+		// module and field names
+		for j := 0; j < 2; j++ {
+			n, err := readULEB128(r)
+			if err != nil {
+				return 0, false, err
+			}
+			if _, err = r.Seek(int64(n), io.SeekCurrent); err != nil {
+				return 0, false, err
+			}
+		}
+
+		kind, err := r.ReadByte()
+		if err != nil {
+			return 0, false, err
+		}
+
+		switch kind {
+		case wasmImportFunc:
+			_, err = readULEB128(r)
+		case wasmImportTable:
+			if _, err = r.ReadByte(); err == nil {
+				_, err = readLimitsMin(r)
+			}
+		case wasmImportMemory:
+			pages, err := readLimitsMin(r)
+			return pages, err == nil, err
+		case wasmImportGlobal:
+			_, err = r.Seek(2, io.SeekCurrent)
+		default:
+			err = errInvalidWasm
+		}
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	return 0, false, nil
+}
+
+// readLimitsMin reads wasm limits and returns their minimum
+func readLimitsMin(r *bytes.Reader) (uint32, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	min, err := readULEB128(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if flags&1 != 0 {
+		if _, err = readULEB128(r); err != nil {
+			return 0, err
+		}
+	}
+
+	if min > uint64(^uint32(0)) {
+		return 0, errInvalidWasm
+	}
+	return uint32(min), nil
+}
+
+// readULEB128 reads an unsigned LEB128 integer of at most 64 bits
+func readULEB128(r io.ByteReader) (uint64, error) {
+	var res uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		res |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return res, nil
+		}
+	}
+
+	return 0, errInvalidWasm
+}
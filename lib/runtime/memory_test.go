@@ -0,0 +1,129 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHeapPages_Default(t *testing.T) {
+	cfg := &InstanceConfig{
+		Storage: NewTestRuntimeStorage(t, nil),
+	}
+
+	pages, err := cfg.GetHeapPages()
+	require.NoError(t, err)
+	require.Equal(t, DefaultHeapPages, pages)
+}
+
+func TestGetHeapPages_FromStorage(t *testing.T) {
+	storage := NewTestRuntimeStorage(t, nil)
+	enc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(enc, 64)
+	err := storage.Set(common.HeapPagesKey, enc)
+	require.NoError(t, err)
+
+	cfg := &InstanceConfig{
+		Storage: storage,
+	}
+
+	pages, err := cfg.GetHeapPages()
+	require.NoError(t, err)
+	require.Equal(t, uint32(64), pages)
+
+	// a configured value overrides the value in storage
+	cfg.HeapPages = 128
+	pages, err = cfg.GetHeapPages()
+	require.NoError(t, err)
+	require.Equal(t, uint32(128), pages)
+}
+
+func TestGetHeapPages_InvalidStorageValue(t *testing.T) {
+	storage := NewTestRuntimeStorage(t, nil)
+	err := storage.Set(common.HeapPagesKey, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	cfg := &InstanceConfig{
+		Storage: storage,
+	}
+
+	_, err = cfg.GetHeapPages()
+	require.Error(t, err)
+}
+
+func TestGetHeapPages_ExceedsLimit(t *testing.T) {
+	cfg := &InstanceConfig{
+		HeapPages:      65,
+		MaxMemoryPages: 64,
+	}
+
+	_, err := cfg.GetHeapPages()
+	require.Equal(t, ErrHeapPagesExceedLimit, err)
+
+	cfg.HeapPages = DefaultMaxMemoryPages + 1
+	cfg.MaxMemoryPages = 0
+	_, err = cfg.GetHeapPages()
+	require.Equal(t, ErrHeapPagesExceedLimit, err)
+}
+
+func TestInitialMemoryPages(t *testing.T) {
+	header := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	custom := []byte{0x00, 0x02, 0x01, 'x'}
+
+	// memory section declaring one memory of 3 pages
+	defined := append(append(append([]byte{}, header...), custom...), 0x05, 0x03, 0x01, 0x00, 0x03)
+
+	// import section importing a function, then a memory of 17 pages with a maximum of 256 pages
+	imported := append(append([]byte{}, header...), 0x02, 0x19, 0x02,
+		0x03, 'e', 'n', 'v', 0x01, 'f', 0x00, 0x00,
+		0x03, 'e', 'n', 'v', 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x01, 0x11, 0x80, 0x02,
+	)
+
+	pages, err := InitialMemoryPages(defined)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), pages)
+
+	pages, err = InitialMemoryPages(imported)
+	require.NoError(t, err)
+	require.Equal(t, uint32(17), pages)
+
+	pages, err = InitialMemoryPages(header)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), pages)
+
+	_, err = InitialMemoryPages([]byte{0x01, 0x02})
+	require.Error(t, err)
+
+	cfg := &InstanceConfig{
+		Storage:   NewTestRuntimeStorage(t, nil),
+		HeapPages: 20,
+	}
+
+	pages, err = cfg.GetMemoryPages(imported)
+	require.NoError(t, err)
+	require.Equal(t, uint32(37), pages)
+
+	cfg.MaxMemoryPages = 30
+	_, err = cfg.GetMemoryPages(imported)
+	require.True(t, errors.Is(err, ErrHeapPagesExceedLimit))
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"strings"
+	"sync"
+)
+
+// Priority is the scheduling priority of a call into a runtime instance
+type Priority int
+
+const (
+	// PriorityLow is the priority of calls that aren't consensus critical, eg. calls made on behalf of RPC clients
+	PriorityLow Priority = iota
+	// PriorityHigh is the priority of calls made to author and import blocks
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// consensusCalls are the runtime functions (or prefixes of them) that are called to author and import blocks
+var consensusCalls = []string{
+	"Core_execute_block",
+	"Core_initialize_block",
+	BlockBuilderAPIName + "_",
+	BabeAPIName + "_",
+	GrandpaAPIName + "_",
+}
+
+// CallPriority returns the scheduling priority of the given runtime function
+func CallPriority(function string) Priority {
+	for _, c := range consensusCalls {
+		if strings.HasPrefix(function, c) {
+			return PriorityHigh
+		}
+	}
+
+	return PriorityLow
+}
+
+// CallScheduler serialises the calls into a runtime instance. When the instance is busy, waiting calls are
+// run in order of priority, so consensus critical calls bypass any queued RPC calls. Calls with the same
+// priority run in the order they were made. The zero value is ready to use.
+type CallScheduler struct {
+	mu      sync.Mutex
+	busy    bool
+	waiting [numPriorities][]chan struct{}
+}
+
+// Lock blocks until the instance is free and no call with a higher priority is waiting
+func (s *CallScheduler) Lock(p Priority) {
+	s.mu.Lock()
+	if !s.busy {
+		s.busy = true
+		s.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	s.waiting[p] = append(s.waiting[p], ready)
+	s.mu.Unlock()
+	<-ready
+}
+
+// Unlock hands the instance over to the waiting call with the highest priority
+func (s *CallScheduler) Unlock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for p := numPriorities - 1; p >= 0; p-- {
+		if len(s.waiting[p]) == 0 {
+			continue
+		}
+
+		next := s.waiting[p][0]
+		s.waiting[p] = s.waiting[p][1:]
+		close(next)
+		return
+	}
+
+	s.busy = false
+}
+
+// Waiting returns the number of calls with the given priority that are waiting for the instance
+func (s *CallScheduler) Waiting(p Priority) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiting[p])
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallPriority(t *testing.T) {
+	require.Equal(t, PriorityHigh, CallPriority("Core_execute_block"))
+	require.Equal(t, PriorityHigh, CallPriority("BlockBuilder_apply_extrinsic"))
+	require.Equal(t, PriorityHigh, CallPriority("BabeApi_configuration"))
+	require.Equal(t, PriorityLow, CallPriority("Core_version"))
+	require.Equal(t, PriorityLow, CallPriority("Metadata_metadata"))
+	require.Equal(t, PriorityLow, CallPriority("TaggedTransactionQueue_validate_transaction"))
+}
+
+func waitForWaiting(t *testing.T, s *CallScheduler, p Priority, n int) {
+	for i := 0; i < 100; i++ {
+		if s.Waiting(p) == n {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("expected %d waiting calls", n)
+}
+
+func TestCallScheduler_HighPriorityBypassesQueue(t *testing.T) {
+	s := new(CallScheduler)
+	s.Lock(PriorityLow)
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+
+	call := func(name string, p Priority) {
+		defer wg.Done()
+		s.Lock(p)
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+		s.Unlock()
+	}
+
+	wg.Add(3)
+	go call("rpc1", PriorityLow)
+	waitForWaiting(t, s, PriorityLow, 1)
+	go call("rpc2", PriorityLow)
+	waitForWaiting(t, s, PriorityLow, 2)
+	go call("import", PriorityHigh)
+	waitForWaiting(t, s, PriorityHigh, 1)
+
+	s.Unlock()
+	wg.Wait()
+
+	require.Equal(t, []string{"import", "rpc1", "rpc2"}, order)
+
+	// the scheduler is free once every call is done
+	s.Lock(PriorityLow)
+	s.Unlock()
+}
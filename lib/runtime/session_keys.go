@@ -0,0 +1,80 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ChainSafe/gossamer/lib/scale"
+)
+
+// KeyTypeID identifies the type of a session key, eg. "babe" or "gran"
+type KeyTypeID [4]byte
+
+// String returns the key type as a string
+func (id KeyTypeID) String() string {
+	return string(id[:])
+}
+
+// SessionKey is a session public key along with its key type
+type SessionKey struct {
+	Type   KeyTypeID
+	Public []byte
+}
+
+// DecodeSessionKeysResult decodes the return value of SessionKeys_decode_session_keys, which is an
+// Option<Vec<(Vec<u8>, KeyTypeId)>>. It returns ErrInvalidSessionKeys if the runtime couldn't decode the keys.
+func DecodeSessionKeysResult(in []byte) ([]*SessionKey, error) {
+	r := bytes.NewReader(in)
+	sd := &scale.Decoder{Reader: r}
+
+	opt, err := sd.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if opt == 0 {
+		return nil, ErrInvalidSessionKeys
+	}
+
+	n, err := sd.DecodeInteger()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*SessionKey{}
+	for i := int64(0); i < n; i++ {
+		pub, err := sd.DecodeByteArray()
+		if err != nil {
+			return nil, err
+		}
+
+		key := &SessionKey{
+			Public: pub,
+		}
+
+		_, err = io.ReadFull(r, key.Type[:])
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSessionKeysResult(t *testing.T) {
+	babe := make([]byte, 32)
+	babe[0] = 0xb
+	gran := make([]byte, 32)
+	gran[0] = 0x6
+
+	// Some(vec![(babe, "babe"), (gran, "gran")])
+	in := []byte{1, 2 << 2}
+	in = append(in, 32<<2)
+	in = append(in, babe...)
+	in = append(in, []byte("babe")...)
+	in = append(in, 32<<2)
+	in = append(in, gran...)
+	in = append(in, []byte("gran")...)
+
+	keys, err := DecodeSessionKeysResult(in)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, "babe", keys[0].Type.String())
+	require.Equal(t, babe, keys[0].Public)
+	require.Equal(t, "gran", keys[1].Type.String())
+	require.Equal(t, gran, keys[1].Public)
+
+	_, err = DecodeSessionKeysResult([]byte{0})
+	require.Equal(t, ErrInvalidSessionKeys, err)
+
+	_, err = DecodeSessionKeysResult(in[:len(in)-2])
+	require.Error(t, err)
+}
@@ -95,7 +95,7 @@ type TestRuntimeStorage struct {
 }
 
 // NewTestRuntimeStorage returns an empty, initialized TestRuntimeStorage
-func NewTestRuntimeStorage(t *testing.T, tr *trie.Trie) *TestRuntimeStorage {
+func NewTestRuntimeStorage(t testing.TB, tr *trie.Trie) *TestRuntimeStorage {
 	if tr == nil {
 		tr = trie.NewEmptyTrie()
 	}
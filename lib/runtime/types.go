@@ -18,6 +18,7 @@ package runtime
 
 import (
 	"bytes"
+	"sync/atomic"
 
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/scale"
@@ -49,6 +50,13 @@ type InstanceConfig struct {
 	NodeStorage NodeStorage
 	Network     BasicNetwork
 	Transaction TransactionState
+
+	// HeapPages overrides the :heappages storage value if non-zero
+	HeapPages uint32
+	// MaxMemoryPages is the hard ceiling on the instance's memory, DefaultMaxMemoryPages is used if zero
+	MaxMemoryPages uint32
+	// HostStats records the host function calls of the instance if it's set
+	HostStats *HostStats
 }
 
 // Context is the context for the wasm interpreter's imported functions
@@ -60,6 +68,20 @@ type Context struct {
 	NodeStorage NodeStorage
 	Network     BasicNetwork
 	Transaction TransactionState
+	HostStats   *HostStats
+
+	interrupted uint32
+}
+
+// Interrupt marks the context's instance as interrupted, the instance's host functions then make the current call
+// trap at the next allocation
+func (c *Context) Interrupt() {
+	atomic.StoreUint32(&c.interrupted, 1)
+}
+
+// Interrupted returns true if the context's instance has been interrupted
+func (c *Context) Interrupted() bool {
+	return atomic.LoadUint32(&c.interrupted) == 1
 }
 
 // Version struct
@@ -125,7 +147,10 @@ func NewValidateTransactionError(res []byte) error {
 	}
 
 	if res[1] == 0 {
-		// transaction is invalid
+		// transaction is invalid, or will be valid in the future
+		if len(res) > 2 && res[2] == 2 {
+			return ErrFutureTransaction
+		}
 		return ErrInvalidTransaction
 	}
 
@@ -0,0 +1,30 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidateTransactionError(t *testing.T) {
+	require.Nil(t, NewValidateTransactionError([]byte{0, 1}))
+	require.Equal(t, ErrInvalidTransaction, NewValidateTransactionError([]byte{1, 0, 4}))
+	require.Equal(t, ErrFutureTransaction, NewValidateTransactionError([]byte{1, 0, 2}))
+	require.Equal(t, ErrUnknownTransaction, NewValidateTransactionError([]byte{1, 1, 0}))
+}
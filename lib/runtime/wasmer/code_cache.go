@@ -0,0 +1,229 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
+)
+
+// cacheMagic prefixes every cached module, it's followed by the blake2b hash of the serialized module
+var cacheMagic = []byte("gssmr-wasmer-1")
+
+// moduleExt is the file extension of cached modules
+const moduleExt = ".module"
+
+// DefaultCodeCacheLimit is the number of modules a CodeCache keeps if no limit is given
+const DefaultCodeCacheLimit = 8
+
+// errCorruptModule is returned when a cached module fails its integrity check
+var errCorruptModule = errors.New("cached module failed integrity check")
+
+// CodeCache caches compiled runtime modules on disk, keyed by the hash of their code, so that restarts and
+// runtime upgrades don't compile the same code again. A node should share one CodeCache between all of its
+// instances, since the cache only serializes access to its directory through its own lock.
+type CodeCache struct {
+	dir   string
+	limit int
+	lock  sync.Mutex
+}
+
+// NewCodeCache returns a CodeCache that stores at most limit modules in the given directory, which is created
+// if needed. The least recently used modules are evicted once the limit is exceeded; DefaultCodeCacheLimit is
+// used if the limit is 0.
+func NewCodeCache(dir string, limit int) (*CodeCache, error) {
+	if limit <= 0 {
+		limit = DefaultCodeCacheLimit
+	}
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CodeCache{
+		dir:   dir,
+		limit: limit,
+	}, nil
+}
+
+// Module returns the compiled module of the given code. It's loaded from the cache if there is a valid entry
+// for the code, otherwise the code is compiled and the result is cached.
+func (c *CodeCache) Module(code []byte) (wasm.Module, error) {
+	hash, err := common.Blake2bHash(code)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	fp := c.path(hash)
+	module, err := loadModule(fp)
+	if err == nil {
+		logger.Debug("loaded cached runtime module", "code hash", hash)
+
+		// mark the module as recently used, so that it's evicted last
+		now := time.Now()
+		_ = os.Chtimes(fp, now, now)
+		return module, nil
+	}
+
+	if !os.IsNotExist(err) {
+		logger.Warn("discarding cached runtime module", "code hash", hash, "error", err)
+		_ = os.Remove(fp)
+	}
+
+	module, err = wasm.Compile(code)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	err = c.storeModule(fp, module)
+	if err != nil {
+		logger.Warn("failed to cache runtime module", "code hash", hash, "error", err)
+		return module, nil
+	}
+
+	err = c.evict()
+	if err != nil {
+		logger.Warn("failed to evict cached runtime modules", "error", err)
+	}
+
+	return module, nil
+}
+
+func (c *CodeCache) path(hash common.Hash) string {
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+moduleExt)
+}
+
+// evict removes the least recently used modules until the cache holds at most c.limit modules
+func (c *CodeCache) evict() error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	modules := files[:0]
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), moduleExt) {
+			modules = append(modules, f)
+		}
+	}
+
+	if len(modules) <= c.limit {
+		return nil
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].ModTime().Before(modules[j].ModTime())
+	})
+
+	for _, f := range modules[:len(modules)-c.limit] {
+		err = os.Remove(filepath.Join(c.dir, f.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		logger.Debug("evicted cached runtime module", "file", f.Name())
+	}
+
+	return nil
+}
+
+// storeModule writes the serialized module to a temporary file first, so that the cache never holds a
+// partially written module
+func (c *CodeCache) storeModule(fp string, module wasm.Module) error {
+	artifact, err := module.Serialize()
+	if err != nil {
+		return err
+	}
+
+	checksum, err := common.Blake2bHash(artifact)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(c.dir, "module-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint
+
+	for _, b := range [][]byte{cacheMagic, checksum[:], artifact} {
+		if _, err = f.Write(b); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+
+	err = f.Sync()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), fp)
+}
+
+// loadModule maps the cached module into memory and verifies its checksum before deserializing it
+func loadModule(fp string) (wasm.Module, error) {
+	data, release, err := mapModuleFile(fp)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+	defer release()
+
+	headerLen := len(cacheMagic) + len(common.Hash{})
+	if len(data) <= headerLen {
+		return wasm.Module{}, errCorruptModule
+	}
+
+	if !bytes.Equal(data[:len(cacheMagic)], cacheMagic) {
+		return wasm.Module{}, errCorruptModule
+	}
+
+	artifact := data[headerLen:]
+	checksum, err := common.Blake2bHash(artifact)
+	if err != nil {
+		return wasm.Module{}, err
+	}
+
+	if !bytes.Equal(checksum[:], data[len(cacheMagic):headerLen]) {
+		return wasm.Module{}, errCorruptModule
+	}
+
+	// the serialized module is copied by wasmer, so it's safe to unmap it afterwards
+	return wasm.DeserializeModule(artifact)
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package wasmer
+
+import (
+	"io/ioutil"
+)
+
+// mapModuleFile reads the given file into memory on platforms without mmap support
+func mapModuleFile(fp string) ([]byte, func(), error) {
+	data, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() {}, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	log "github.com/ChainSafe/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCodeCache(t *testing.T) (*CodeCache, string) {
+	dir, err := ioutil.TempDir("", "gossamer-wasm-cache")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	cache, err := NewCodeCache(dir, 0)
+	require.NoError(t, err)
+	return cache, dir
+}
+
+func newCachedTestInstance(t *testing.T, cache *CodeCache) (*LegacyInstance, []byte) {
+	fp, cfg := setupConfig(t, runtime.LEGACY_NODE_RUNTIME, nil, log.LvlInfo, 0)
+	cfg.CodeCache = cache
+
+	code, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+
+	inst, err := NewLegacyInstance(code, cfg)
+	require.NoError(t, err)
+	t.Cleanup(inst.Stop)
+
+	_, err = inst.Version()
+	require.NoError(t, err)
+	return inst, code
+}
+
+func TestCodeCache(t *testing.T) {
+	cache, dir := newTestCodeCache(t)
+	_, code := newCachedTestInstance(t, cache)
+
+	hash, err := common.Blake2bHash(code)
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, cache.path(hash), filepath.Join(dir, files[0].Name()))
+
+	_, err = loadModule(cache.path(hash))
+	require.NoError(t, err)
+
+	// the second instance is created from the cached module
+	newCachedTestInstance(t, cache)
+}
+
+func TestCodeCache_Corrupt(t *testing.T) {
+	cache, _ := newTestCodeCache(t)
+	_, code := newCachedTestInstance(t, cache)
+
+	hash, err := common.Blake2bHash(code)
+	require.NoError(t, err)
+	fp := cache.path(hash)
+
+	data, err := ioutil.ReadFile(fp)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	err = ioutil.WriteFile(fp, data, 0600)
+	require.NoError(t, err)
+
+	_, err = loadModule(fp)
+	require.Equal(t, errCorruptModule, err)
+
+	// the corrupt module is replaced with a freshly compiled one
+	newCachedTestInstance(t, cache)
+	_, err = loadModule(fp)
+	require.NoError(t, err)
+}
+
+func TestCodeCache_Evict(t *testing.T) {
+	cache, dir := newTestCodeCache(t)
+	cache.limit = 2
+
+	now := time.Now()
+	names := []string{"a", "b", "c"}
+	for i, name := range names {
+		fp := filepath.Join(dir, name+moduleExt)
+		err := ioutil.WriteFile(fp, []byte(name), 0600)
+		require.NoError(t, err)
+
+		mtime := now.Add(time.Duration(i-len(names)) * time.Minute)
+		err = os.Chtimes(fp, mtime, mtime)
+		require.NoError(t, err)
+	}
+
+	// files that aren't modules are ignored
+	err := ioutil.WriteFile(filepath.Join(dir, "module-1.tmp"), nil, 0600)
+	require.NoError(t, err)
+
+	err = cache.evict()
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	var remaining []string
+	for _, f := range files {
+		remaining = append(remaining, f.Name())
+	}
+	require.Equal(t, []string{"b" + moduleExt, "c" + moduleExt, "module-1.tmp"}, remaining)
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+// hostAPIFixtures contains host function calls along with the outputs produced by Substrate's host API
+// for the same inputs, any difference from gossamer's output is a semantic drift in the host API
+const hostAPIFixtures = "../testdata/host_api_conformance.json"
+
+// unimplementedHostAPI are the host functions that are stubbed out, the cases calling them are skipped
+var unimplementedHostAPI = map[string]bool{
+	"ext_sandbox_instance_teardown_version_1": true,
+	"ext_sandbox_instantiate_version_1":       true,
+	"ext_sandbox_invoke_version_1":            true,
+	"ext_sandbox_memory_get_version_1":        true,
+	"ext_sandbox_memory_new_version_1":        true,
+	"ext_sandbox_memory_set_version_1":        true,
+	"ext_sandbox_memory_teardown_version_1":   true,
+
+	"ext_crypto_ed25519_generate_version_1":                   true,
+	"ext_crypto_ed25519_public_keys_version_1":                true,
+	"ext_crypto_ed25519_sign_version_1":                       true,
+	"ext_crypto_ed25519_verify_version_1":                     true,
+	"ext_crypto_finish_batch_verify_version_1":                true,
+	"ext_crypto_secp256k1_ecdsa_recover_version_1":            true,
+	"ext_crypto_secp256k1_ecdsa_recover_compressed_version_1": true,
+	"ext_crypto_sr25519_generate_version_1":                   true,
+	"ext_crypto_sr25519_public_keys_version_1":                true,
+	"ext_crypto_sr25519_sign_version_1":                       true,
+	"ext_crypto_sr25519_verify_version_1":                     true,
+	"ext_crypto_sr25519_verify_version_2":                     true,
+	"ext_crypto_start_batch_verify_version_1":                 true,
+
+	// the ordered root is always the root of an empty trie
+	"ext_trie_blake2_256_root_version_1":         true,
+	"ext_trie_blake2_256_ordered_root_version_1": true,
+
+	"ext_misc_print_hex_version_1":       true,
+	"ext_misc_print_num_version_1":       true,
+	"ext_misc_runtime_version_version_1": true,
+
+	"ext_default_child_storage_read_version_1":         true,
+	"ext_default_child_storage_clear_version_1":        true,
+	"ext_default_child_storage_clear_prefix_version_1": true,
+	"ext_default_child_storage_exists_version_1":       true,
+	"ext_default_child_storage_get_version_1":          true,
+	"ext_default_child_storage_next_key_version_1":     true,
+	"ext_default_child_storage_root_version_1":         true,
+	"ext_default_child_storage_set_version_1":          true,
+	"ext_default_child_storage_storage_kill_version_1": true,
+
+	"ext_hashing_sha2_256_version_1": true,
+
+	"ext_offchain_index_set_version_1":     true,
+	"ext_offchain_network_state_version_1": true,
+	"ext_offchain_random_seed_version_1":   true,
+
+	"ext_storage_changes_root_version_1":         true,
+	"ext_storage_clear_version_1":                true,
+	"ext_storage_clear_prefix_version_1":         true,
+	"ext_storage_commit_transaction_version_1":   true,
+	"ext_storage_exists_version_1":               true,
+	"ext_storage_read_version_1":                 true,
+	"ext_storage_rollback_transaction_version_1": true,
+	"ext_storage_root_version_1":                 true,
+	"ext_storage_start_transaction_version_1":    true,
+}
+
+// hostAPIInstance runs the host API conformance cases on a wasmer instance
+type hostAPIInstance struct {
+	inst *LegacyInstance
+}
+
+func (in *hostAPIInstance) Call(function string, args []interface{}) (int64, error) {
+	export, ok := in.inst.vm.Exports[function]
+	if !ok {
+		return 0, fmt.Errorf("no export for host function %s", function)
+	}
+
+	res, err := export(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	switch runtime.HostAPISignatures[function].Result {
+	case runtime.WasmI32:
+		return int64(res.ToI32()), nil
+	case runtime.WasmI64:
+		return res.ToI64(), nil
+	default:
+		return 0, nil
+	}
+}
+
+func (in *hostAPIInstance) Memory() []byte {
+	return in.inst.vm.Memory.Data()
+}
+
+func (in *hostAPIInstance) Allocate(size uint32) (uint32, error) {
+	return in.inst.malloc(size)
+}
+
+func (in *hostAPIInstance) Clear() {
+	in.inst.clear()
+}
+
+// newHostAPIInstance instantiates a module calling the case's host functions with the case's storage, it skips
+// the case if any of them isn't implemented
+func newHostAPIInstance(t testing.TB, c *runtime.HostAPICase) (*hostAPIInstance, runtime.InstanceConfig) {
+	for _, function := range c.Functions() {
+		if unimplementedHostAPI[function] {
+			t.Skipf("%s isn't implemented", function)
+		}
+	}
+
+	module, err := runtime.HostAPIModule(c.Functions())
+	require.NoError(t, err)
+
+	cfg := &Config{
+		InstanceConfig: c.NewConfig(t),
+		Imports:        ImportsNodeRuntime,
+	}
+
+	instance, err := NewInstance(module, cfg)
+	require.NoError(t, err)
+	t.Cleanup(instance.Stop)
+	return &hostAPIInstance{inst: instance.inst}, cfg.InstanceConfig
+}
+
+func TestHostAPIConformance(t *testing.T) {
+	for _, c := range runtime.LoadHostAPICases(t, hostAPIFixtures) {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			instance, cfg := newHostAPIInstance(t, c)
+			runtime.RunHostAPICase(t, instance, c, cfg)
+		})
+	}
+}
+
+func TestHostAPIConformance_Unimplemented(t *testing.T) {
+	for function := range unimplementedHostAPI {
+		_, ok := runtime.HostAPISignatures[function]
+		require.True(t, ok, "unknown host function %s", function)
+	}
+}
+
+func BenchmarkHostAPI(b *testing.B) {
+	for _, c := range runtime.LoadHostAPICases(b, hostAPIFixtures) {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			instance, _ := newHostAPIInstance(b, c)
+			for i := range c.Before {
+				runtime.CallHostAPI(b, instance, &c.Before[i])
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				runtime.CallHostAPI(b, instance, &c.HostAPICall)
+			}
+		})
+	}
+}
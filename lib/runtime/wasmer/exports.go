@@ -62,13 +62,29 @@ func (in *LegacyInstance) Version() (*runtime.VersionAPI, error) {
 	return version, nil
 }
 
+// checkCall returns runtime.ErrMethodUnavailable if the runtime doesn't implement the API of the given runtime call
+func (in *LegacyInstance) checkCall(function string) error {
+	ver, err := in.Version()
+	if err != nil {
+		return err
+	}
+
+	return ver.CheckCall(function)
+}
+
 // Metadata calls runtime function Metadata_metadata
 func (in *LegacyInstance) Metadata() ([]byte, error) {
 	return in.exec(runtime.Metadata, []byte{})
 }
 
-// BabeConfiguration gets the configuration data for BABE from the runtime
+// BabeConfiguration gets the configuration data for BABE from the runtime, it returns runtime.ErrMethodUnavailable
+// if the runtime doesn't implement BabeApi
 func (in *LegacyInstance) BabeConfiguration() (*types.BabeConfiguration, error) {
+	err := in.checkCall(runtime.BabeAPIConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := in.exec(runtime.BabeAPIConfiguration, []byte{})
 	if err != nil {
 		return nil, err
@@ -133,6 +149,7 @@ func (in *LegacyInstance) FinalizeBlock() (*types.Header, error) {
 		return nil, err
 	}
 
+	in.logHostStats(bh.Number)
 	return bh, nil
 }
 
@@ -141,13 +158,19 @@ func (in *LegacyInstance) ExecuteBlock(block *types.Block) ([]byte, error) {
 	// copy block since we're going to modify it
 	b := block.DeepCopy()
 
-	b.Header.Digest = [][]byte{} // TODO: remove only seal digest
+	b.Header.Digest = types.NewEmptyDigest() // TODO: remove only seal digest
 	bdEnc, err := b.Encode()
 	if err != nil {
 		return nil, err
 	}
 
-	return in.exec(runtime.CoreExecuteBlock, bdEnc)
+	res, err := in.exec(runtime.CoreExecuteBlock, bdEnc)
+	if err != nil {
+		return nil, err
+	}
+
+	in.logHostStats(block.Header.Number)
+	return res, nil
 }
 
 // ValidateTransaction runs the extrinsic through runtime function TaggedTransactionQueue_validate_transaction and returns *Validity
@@ -278,7 +278,7 @@ func TestInstance_FinalizeBlock_LegacyNodeRuntime(t *testing.T) {
 	header := &types.Header{
 		ParentHash: trie.EmptyHash,
 		Number:     big.NewInt(77),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	err := instance.InitializeBlock(header)
@@ -323,7 +323,7 @@ func TestInstance_FinalizeBlock_LegacyNodeRuntime(t *testing.T) {
 	expected := &types.Header{
 		ParentHash: header.ParentHash,
 		Number:     big.NewInt(77),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	require.Equal(t, expected.ParentHash, res.ParentHash)
@@ -146,7 +146,7 @@ func TestInstance_ExecuteBlock_NodeRuntime(t *testing.T) {
 		Number:         big.NewInt(1),
 		StateRoot:      trie.EmptyHash,
 		ExtrinsicsRoot: trie.EmptyHash,
-		Digest:         [][]byte{},
+		Digest:         types.NewEmptyDigest(),
 	}
 
 	_, err := rt.ExecuteBlock(&types.Block{
@@ -163,7 +163,7 @@ func TestInstance_InitializeBlock_NodeRuntime(t *testing.T) {
 
 	header := &types.Header{
 		Number: big.NewInt(1),
-		Digest: [][]byte{},
+		Digest: types.NewEmptyDigest(),
 	}
 
 	err := rt.InitializeBlock(header)
@@ -0,0 +1,52 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmer
+
+import (
+	"math/big"
+	"time"
+	"unsafe"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+
+	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
+)
+
+// observeHostCall starts timing a call to the named host function if the instance records host function stats,
+// the returned func must be deferred until the host function returns
+func observeHostCall(ctx unsafe.Pointer, name string) func() {
+	stats := wasm.IntoInstanceContext(ctx).Data().(*runtime.Context).HostStats
+	if stats == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		stats.Observe(name, time.Since(start))
+	}
+}
+
+// logHostStats logs the host function calls made while executing or building the given block
+func (in *LegacyInstance) logHostStats(number *big.Int) {
+	if in.ctx.HostStats == nil {
+		return
+	}
+
+	for _, fs := range in.ctx.HostStats.TakeBlock() {
+		logger.Debug("host function calls", "block", number, "function", fs.Name, "calls", fs.Calls, "time", fs.Time)
+	}
+}
@@ -79,6 +79,7 @@ package wasmer
 // extern int64_t ext_offchain_network_state_version_1(void *context);
 // extern int32_t ext_offchain_random_seed_version_1(void *context);
 // extern int64_t ext_offchain_submit_transaction_version_1(void *context, int64_t a);
+// extern int64_t ext_offchain_timestamp_version_1(void *context);
 //
 // extern void ext_storage_append_version_1(void *context, int64_t a, int64_t b);
 // extern int64_t ext_storage_changes_root_version_1(void *context, int64_t a);
@@ -96,13 +97,19 @@ package wasmer
 import "C"
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
+	"time"
 	"unsafe"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/common/optional"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/scale"
+	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/lib/trie"
 
 	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
@@ -111,6 +118,7 @@ import (
 //export ext_logging_log_version_1
 func ext_logging_log_version_1(context unsafe.Pointer, level C.int32_t, targetData, msgData C.int64_t) {
 	logger.Trace("[ext_logging_log_version_1] executing...")
+	defer observeHostCall(context, "ext_logging_log_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -136,129 +144,151 @@ func ext_logging_log_version_1(context unsafe.Pointer, level C.int32_t, targetDa
 //export ext_sandbox_instance_teardown_version_1
 func ext_sandbox_instance_teardown_version_1(context unsafe.Pointer, a C.int32_t) {
 	logger.Trace("[ext_sandbox_instance_teardown_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_instance_teardown_version_1")()
 }
 
 //export ext_sandbox_instantiate_version_1
 func ext_sandbox_instantiate_version_1(context unsafe.Pointer, a C.int32_t, x, y C.int64_t, z C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_instantiate_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_instantiate_version_1")()
 	return 0
 }
 
 //export ext_sandbox_invoke_version_1
 func ext_sandbox_invoke_version_1(context unsafe.Pointer, a C.int32_t, x, y C.int64_t, z, d, e C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_invoke_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_invoke_version_1")()
 	return 0
 }
 
 //export ext_sandbox_memory_get_version_1
 func ext_sandbox_memory_get_version_1(context unsafe.Pointer, a, z, d, e C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_get_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_get_version_1")()
 	return 0
 }
 
 //export ext_sandbox_memory_new_version_1
 func ext_sandbox_memory_new_version_1(context unsafe.Pointer, a, z C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_new_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_new_version_1")()
 	return 0
 }
 
 //export ext_sandbox_memory_set_version_1
 func ext_sandbox_memory_set_version_1(context unsafe.Pointer, a, z, d, e C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_set_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_set_version_1")()
 	return 0
 }
 
 //export ext_sandbox_memory_teardown_version_1
 func ext_sandbox_memory_teardown_version_1(context unsafe.Pointer, a C.int32_t) {
 	logger.Trace("[ext_sandbox_memory_teardown_version_1] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_teardown_version_1")()
 }
 
 //export ext_crypto_ed25519_generate_version_1
 func ext_crypto_ed25519_generate_version_1(context unsafe.Pointer, a C.int32_t, z C.int64_t) C.int32_t {
 	logger.Trace("[ext_crypto_ed25519_generate_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_ed25519_generate_version_1")()
 	return 0
 }
 
 //export ext_crypto_ed25519_public_keys_version_1
 func ext_crypto_ed25519_public_keys_version_1(context unsafe.Pointer, a C.int32_t) C.int64_t {
 	logger.Trace("[ext_crypto_ed25519_public_keys_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_ed25519_public_keys_version_1")()
 	return 0
 }
 
 //export ext_crypto_ed25519_sign_version_1
 func ext_crypto_ed25519_sign_version_1(context unsafe.Pointer, a, z C.int32_t, y C.int64_t) C.int64_t {
 	logger.Trace("[ext_crypto_ed25519_sign_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_ed25519_sign_version_1")()
 	return 0
 }
 
 //export ext_crypto_ed25519_verify_version_1
 func ext_crypto_ed25519_verify_version_1(context unsafe.Pointer, a C.int32_t, z C.int64_t, y C.int32_t) C.int32_t {
 	logger.Trace("[ext_crypto_ed25519_verify_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_ed25519_verify_version_1")()
 	return 0
 }
 
 //export ext_crypto_finish_batch_verify_version_1
 func ext_crypto_finish_batch_verify_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Trace("[ext_crypto_finish_batch_verify_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_finish_batch_verify_version_1")()
 	return 0
 }
 
 //export ext_crypto_secp256k1_ecdsa_recover_version_1
 func ext_crypto_secp256k1_ecdsa_recover_version_1(context unsafe.Pointer, a, z C.int32_t) C.int64_t {
 	logger.Trace("[ext_crypto_secp256k1_ecdsa_recover_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_version_1")()
 	return 0
 }
 
 //export ext_crypto_secp256k1_ecdsa_recover_compressed_version_1
 func ext_crypto_secp256k1_ecdsa_recover_compressed_version_1(context unsafe.Pointer, a, z C.int32_t) C.int64_t {
 	logger.Trace("[ext_crypto_secp256k1_ecdsa_recover_compressed_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_secp256k1_ecdsa_recover_compressed_version_1")()
 	return 0
 }
 
 //export ext_crypto_sr25519_generate_version_1
 func ext_crypto_sr25519_generate_version_1(context unsafe.Pointer, a C.int32_t, z C.int64_t) C.int32_t {
 	logger.Trace("[ext_crypto_sr25519_generate_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_sr25519_generate_version_1")()
 	return 0
 }
 
 //export ext_crypto_sr25519_public_keys_version_1
 func ext_crypto_sr25519_public_keys_version_1(context unsafe.Pointer, a C.int32_t) C.int64_t {
 	logger.Trace("[ext_crypto_sr25519_public_keys_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_sr25519_public_keys_version_1")()
 	return 0
 }
 
 //export ext_crypto_sr25519_sign_version_1
 func ext_crypto_sr25519_sign_version_1(context unsafe.Pointer, a, z C.int32_t, y C.int64_t) C.int64_t {
 	logger.Trace("[ext_crypto_sr25519_sign_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_sr25519_sign_version_1")()
 	return 0
 }
 
 //export ext_crypto_sr25519_verify_version_1
 func ext_crypto_sr25519_verify_version_1(context unsafe.Pointer, a C.int32_t, z C.int64_t, y C.int32_t) C.int32_t {
 	logger.Trace("[ext_crypto_sr25519_verify_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_sr25519_verify_version_1")()
 	return 0
 }
 
 //export ext_crypto_sr25519_verify_version_2
 func ext_crypto_sr25519_verify_version_2(context unsafe.Pointer, a C.int32_t, z C.int64_t, y C.int32_t) C.int32_t {
 	logger.Trace("[ext_crypto_sr25519_verify_version_2] executing...")
+	defer observeHostCall(context, "ext_crypto_sr25519_verify_version_2")()
 	return 0
 }
 
 //export ext_crypto_start_batch_verify_version_1
 func ext_crypto_start_batch_verify_version_1(context unsafe.Pointer) {
 	logger.Trace("[ext_crypto_start_batch_verify_version_1] executing...")
+	defer observeHostCall(context, "ext_crypto_start_batch_verify_version_1")()
 }
 
 //export ext_trie_blake2_256_root_version_1
 func ext_trie_blake2_256_root_version_1(context unsafe.Pointer, data C.int64_t) C.int32_t {
 	logger.Trace("[ext_trie_blake2_256_root_version_1] executing...")
+	defer observeHostCall(context, "ext_trie_blake2_256_root_version_1")()
 	return 0
 }
 
 //export ext_trie_blake2_256_ordered_root_version_1
 func ext_trie_blake2_256_ordered_root_version_1(context unsafe.Pointer, data C.int64_t) C.int32_t {
 	logger.Trace("[ext_trie_blake2_256_ordered_root_version_1] executing...")
+	defer observeHostCall(context, "ext_trie_blake2_256_ordered_root_version_1")()
 	//dataPtr, dataSize := int64ToPointerAndSize(int64(data))
 
 	instanceContext := wasm.IntoInstanceContext(context)
@@ -279,91 +309,107 @@ func ext_trie_blake2_256_ordered_root_version_1(context unsafe.Pointer, data C.i
 //export ext_misc_print_hex_version_1
 func ext_misc_print_hex_version_1(context unsafe.Pointer, a C.int64_t) {
 	logger.Trace("[ext_misc_print_hex_version_1] executing...")
+	defer observeHostCall(context, "ext_misc_print_hex_version_1")()
 }
 
 //export ext_misc_print_num_version_1
 func ext_misc_print_num_version_1(context unsafe.Pointer, a C.int64_t) {
 	logger.Trace("[ext_misc_print_num_version_1] executing...")
+	defer observeHostCall(context, "ext_misc_print_num_version_1")()
 }
 
 //export ext_misc_print_utf8_version_1
 func ext_misc_print_utf8_version_1(context unsafe.Pointer, data C.int64_t) {
 	logger.Trace("[ext_misc_print_utf8_version_1] executing...")
+	defer observeHostCall(context, "ext_misc_print_utf8_version_1")()
 	ptr, size := int64ToPointerAndSize(int64(data))
-	ext_print_utf8(context, C.int32_t(ptr), C.int32_t(size))
+	printUTF8(context, C.int32_t(ptr), C.int32_t(size))
 }
 
 //export ext_misc_runtime_version_version_1
 func ext_misc_runtime_version_version_1(context unsafe.Pointer, z C.int64_t) C.int64_t {
 	logger.Trace("[ext_misc_runtime_version_version_1] executing...")
+	defer observeHostCall(context, "ext_misc_runtime_version_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_read_version_1
 func ext_default_child_storage_read_version_1(context unsafe.Pointer, a C.int64_t, b C.int64_t, c C.int64_t, d C.int32_t) C.int64_t {
 	logger.Trace("[ext_default_child_storage_read_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_read_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_clear_version_1
 func ext_default_child_storage_clear_version_1(context unsafe.Pointer, a, b C.int64_t) {
 	logger.Trace("[ext_default_child_storage_clear_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_clear_version_1")()
 }
 
 //export ext_default_child_storage_clear_prefix_version_1
 func ext_default_child_storage_clear_prefix_version_1(context unsafe.Pointer, a C.int64_t, b C.int64_t) {
 	logger.Trace("[ext_default_child_storage_clear_prefix_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_clear_prefix_version_1")()
 }
 
 //export ext_default_child_storage_exists_version_1
 func ext_default_child_storage_exists_version_1(context unsafe.Pointer, a C.int64_t, b C.int64_t) C.int32_t {
 	logger.Trace("[ext_default_child_storage_exists_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_exists_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_get_version_1
 func ext_default_child_storage_get_version_1(context unsafe.Pointer, a, b C.int64_t) C.int64_t {
 	logger.Trace("[ext_default_child_storage_get_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_get_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_next_key_version_1
 func ext_default_child_storage_next_key_version_1(context unsafe.Pointer, a C.int64_t, b C.int64_t) C.int64_t {
 	logger.Trace("[ext_default_child_storage_next_key_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_next_key_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_root_version_1
 func ext_default_child_storage_root_version_1(context unsafe.Pointer, z C.int64_t) C.int64_t {
 	logger.Trace("[ext_default_child_storage_root_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_root_version_1")()
 	return 0
 }
 
 //export ext_default_child_storage_set_version_1
 func ext_default_child_storage_set_version_1(context unsafe.Pointer, a, b, z C.int64_t) {
 	logger.Trace("[ext_default_child_storage_set_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_set_version_1")()
 }
 
 //export ext_default_child_storage_storage_kill_version_1
 func ext_default_child_storage_storage_kill_version_1(context unsafe.Pointer, a C.int64_t) {
 	logger.Trace("[ext_default_child_storage_storage_kill_version_1] executing...")
+	defer observeHostCall(context, "ext_default_child_storage_storage_kill_version_1")()
 }
 
 //export ext_allocator_free_version_1
 func ext_allocator_free_version_1(context unsafe.Pointer, addr C.int32_t) {
 	logger.Trace("[ext_allocator_free_version_1] executing...")
-	ext_free(context, addr)
+	defer observeHostCall(context, "ext_allocator_free_version_1")()
+	free(context, addr)
 }
 
 //export ext_allocator_malloc_version_1
 func ext_allocator_malloc_version_1(context unsafe.Pointer, size C.int32_t) C.int32_t {
 	logger.Trace("[ext_allocator_malloc_version_1] executing...", "size", size)
-	return ext_malloc(context, size)
+	defer observeHostCall(context, "ext_allocator_malloc_version_1")()
+	return malloc(context, size)
 }
 
 //export ext_hashing_blake2_128_version_1
 func ext_hashing_blake2_128_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_blake2_128_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_blake2_128_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -386,6 +432,7 @@ func ext_hashing_blake2_128_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_blake2_256_version_1
 func ext_hashing_blake2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_blake2_256_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_blake2_256_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -408,6 +455,7 @@ func ext_hashing_blake2_256_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_keccak_256_version_1
 func ext_hashing_keccak_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_keccak_256_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_keccak_256_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -430,12 +478,14 @@ func ext_hashing_keccak_256_version_1(context unsafe.Pointer, dataSpan C.int64_t
 //export ext_hashing_sha2_256_version_1
 func ext_hashing_sha2_256_version_1(context unsafe.Pointer, z C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_sha2_256_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_sha2_256_version_1")()
 	return 0
 }
 
 //export ext_hashing_twox_256_version_1
 func ext_hashing_twox_256_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_twox_256_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_twox_256_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -458,6 +508,7 @@ func ext_hashing_twox_256_version_1(context unsafe.Pointer, dataSpan C.int64_t)
 //export ext_hashing_twox_128_version_1
 func ext_hashing_twox_128_version_1(context unsafe.Pointer, data C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_twox_128_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_twox_128_version_1")()
 	ptr, size := int64ToPointerAndSize(int64(data))
 
 	instanceContext := wasm.IntoInstanceContext(context)
@@ -467,13 +518,14 @@ func ext_hashing_twox_128_version_1(context unsafe.Pointer, data C.int64_t) C.in
 		logger.Error("[ext_hashing_twox_128_version_1] failed to allocate", "error", err)
 		panic(err)
 	}
-	ext_twox_128(context, C.int32_t(ptr), C.int32_t(size), C.int32_t(out))
+	twox128(context, C.int32_t(ptr), C.int32_t(size), C.int32_t(out))
 	return C.int32_t(out)
 }
 
 //export ext_hashing_twox_64_version_1
 func ext_hashing_twox_64_version_1(context unsafe.Pointer, dataSpan C.int64_t) C.int32_t {
 	logger.Trace("[ext_hashing_twox_64_version_1] executing...")
+	defer observeHostCall(context, "ext_hashing_twox_64_version_1")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	data := asMemorySlice(instanceContext, dataSpan)
@@ -496,84 +548,236 @@ func ext_hashing_twox_64_version_1(context unsafe.Pointer, dataSpan C.int64_t) C
 //export ext_offchain_index_set_version_1
 func ext_offchain_index_set_version_1(context unsafe.Pointer, a, b C.int64_t) {
 	logger.Trace("[ext_offchain_index_set_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_index_set_version_1")()
 }
 
 //export ext_offchain_is_validator_version_1
 func ext_offchain_is_validator_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Trace("[ext_offchain_is_validator_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_is_validator_version_1")()
+	instanceContext := wasm.IntoInstanceContext(context)
+
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+	if runtimeCtx.Validator {
+		return 1
+	}
 	return 0
 }
 
+// offchainStorage returns the node storage of the given kind, or nil if the kind is unknown
+func offchainStorage(runtimeCtx *runtime.Context, kind C.int32_t) runtime.BasicStorage {
+	switch runtime.NodeStorageType(kind) {
+	case runtime.NodeStorageTypePersistent:
+		return runtimeCtx.NodeStorage.PersistentStorage
+	case runtime.NodeStorageTypeLocal:
+		return runtimeCtx.NodeStorage.LocalStorage
+	default:
+		return nil
+	}
+}
+
 //export ext_offchain_local_storage_compare_and_set_version_1
-func ext_offchain_local_storage_compare_and_set_version_1(context unsafe.Pointer, a C.int32_t, x, y, z C.int64_t) C.int32_t {
+func ext_offchain_local_storage_compare_and_set_version_1(context unsafe.Pointer, kind C.int32_t, key, oldValue, newValue C.int64_t) C.int32_t {
 	logger.Trace("[ext_offchain_local_storage_compare_and_set_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_local_storage_compare_and_set_version_1")()
+	instanceContext := wasm.IntoInstanceContext(context)
+
+	storage := offchainStorage(instanceContext.Data().(*runtime.Context), kind)
+	if storage == nil {
+		logger.Error("[ext_offchain_local_storage_compare_and_set_version_1] invalid storage kind", "kind", kind)
+		return 0
+	}
+
+	// the key and value are copied since the memory they're read from is reused by the runtime
+	k := append([]byte{}, asMemorySlice(instanceContext, key)...)
+	v := append([]byte{}, asMemorySlice(instanceContext, newValue)...)
+	set, err := localStorageCompareAndSet(storage, k, asMemorySlice(instanceContext, oldValue), v)
+	if err != nil {
+		logger.Error("[ext_offchain_local_storage_compare_and_set_version_1]", "error", err)
+		return 0
+	}
+
+	if set {
+		return 1
+	}
 	return 0
 }
 
+// localStorageCompareAndSet sets the key to the new value if its value is the old value, which is an encoded
+// Option<Vec<u8>> where None expects the key to be unset. It returns true if the value was set.
+func localStorageCompareAndSet(storage runtime.BasicStorage, key, oldValue, newValue []byte) (bool, error) {
+	expected, err := new(optional.Bytes).Decode(bytes.NewReader(oldValue))
+	if err != nil {
+		return false, fmt.Errorf("failed to decode old value: %w", err)
+	}
+
+	stored, err := storage.Get(key)
+	if (err == nil) != expected.Exists() || !bytes.Equal(stored, expected.Value()) {
+		return false, nil
+	}
+
+	err = storage.Put(key, newValue)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 //export ext_offchain_local_storage_get_version_1
-func ext_offchain_local_storage_get_version_1(context unsafe.Pointer, a C.int32_t, x C.int64_t) C.int64_t {
+func ext_offchain_local_storage_get_version_1(context unsafe.Pointer, kind C.int32_t, key C.int64_t) C.int64_t {
 	logger.Trace("[ext_offchain_local_storage_get_version_1] executing...")
-	return 0
+	defer observeHostCall(context, "ext_offchain_local_storage_get_version_1")()
+	instanceContext := wasm.IntoInstanceContext(context)
+
+	var value []byte
+	storage := offchainStorage(instanceContext.Data().(*runtime.Context), kind)
+	if storage != nil {
+		// a missing key is returned as None
+		value, _ = storage.Get(asMemorySlice(instanceContext, key))
+	} else {
+		logger.Error("[ext_offchain_local_storage_get_version_1] invalid storage kind", "kind", kind)
+	}
+
+	ret, err := toWasmMemoryOptional(instanceContext, value)
+	if err != nil {
+		logger.Error("[ext_offchain_local_storage_get_version_1] failed to allocate", "error", err)
+		return 0
+	}
+
+	return C.int64_t(ret)
 }
 
 //export ext_offchain_local_storage_set_version_1
-func ext_offchain_local_storage_set_version_1(context unsafe.Pointer, a C.int32_t, x, y C.int64_t) {
+func ext_offchain_local_storage_set_version_1(context unsafe.Pointer, kind C.int32_t, key, value C.int64_t) {
 	logger.Trace("[ext_offchain_local_storage_set_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_local_storage_set_version_1")()
+	instanceContext := wasm.IntoInstanceContext(context)
+
+	storage := offchainStorage(instanceContext.Data().(*runtime.Context), kind)
+	if storage == nil {
+		logger.Error("[ext_offchain_local_storage_set_version_1] invalid storage kind", "kind", kind)
+		return
+	}
+
+	k := append([]byte{}, asMemorySlice(instanceContext, key)...)
+	v := append([]byte{}, asMemorySlice(instanceContext, value)...)
+	err := storage.Put(k, v)
+	if err != nil {
+		logger.Error("[ext_offchain_local_storage_set_version_1]", "error", err)
+	}
 }
 
 //export ext_offchain_network_state_version_1
 func ext_offchain_network_state_version_1(context unsafe.Pointer) C.int64_t {
 	logger.Trace("[ext_offchain_network_state_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_network_state_version_1")()
 	return 0
 }
 
 //export ext_offchain_random_seed_version_1
 func ext_offchain_random_seed_version_1(context unsafe.Pointer) C.int32_t {
 	logger.Trace("[ext_offchain_random_seed_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_random_seed_version_1")()
 	return 0
 }
 
 //export ext_offchain_submit_transaction_version_1
-func ext_offchain_submit_transaction_version_1(context unsafe.Pointer, z C.int64_t) C.int64_t {
+func ext_offchain_submit_transaction_version_1(context unsafe.Pointer, data C.int64_t) C.int64_t {
 	logger.Trace("[ext_offchain_submit_transaction_version_1] executing...")
-	return 0
+	defer observeHostCall(context, "ext_offchain_submit_transaction_version_1")()
+	instanceContext := wasm.IntoInstanceContext(context)
+	runtimeCtx := instanceContext.Data().(*runtime.Context)
+
+	// the result is an encoded Result<(), ()>
+	res := []byte{1}
+	if runtimeCtx.Transaction != nil {
+		ext := types.Extrinsic(append([]byte{}, asMemorySlice(instanceContext, data)...))
+
+		// the transaction is validated again before it's included in a block
+		txv := transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false)
+		_, err := runtimeCtx.Transaction.AddToPool(transaction.NewValidTransaction(ext, txv))
+		if err != nil {
+			logger.Debug("[ext_offchain_submit_transaction_version_1] failed to add transaction to pool", "error", err)
+		} else {
+			res = []byte{0}
+		}
+	} else {
+		logger.Error("[ext_offchain_submit_transaction_version_1] no transaction pool to submit to")
+	}
+
+	ret, err := toWasmMemory(instanceContext, res)
+	if err != nil {
+		logger.Error("[ext_offchain_submit_transaction_version_1] failed to allocate", "error", err)
+		return 0
+	}
+
+	return C.int64_t(ret)
+}
+
+//export ext_offchain_timestamp_version_1
+func ext_offchain_timestamp_version_1(context unsafe.Pointer) C.int64_t {
+	logger.Trace("[ext_offchain_timestamp_version_1] executing...")
+	defer observeHostCall(context, "ext_offchain_timestamp_version_1")()
+
+	// milliseconds since the unix epoch
+	return C.int64_t(time.Now().UnixNano() / int64(time.Millisecond))
 }
 
 //export ext_storage_append_version_1
-func ext_storage_append_version_1(context unsafe.Pointer, a, b C.int64_t) {
+func ext_storage_append_version_1(context unsafe.Pointer, keySpan, valueSpan C.int64_t) {
 	logger.Trace("[ext_storage_append_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_append_version_1")()
+
+	instanceContext := wasm.IntoInstanceContext(context)
+	storage := instanceContext.Data().(*runtime.Context).Storage
+
+	key := asMemorySlice(instanceContext, keySpan)
+	valueAppend := asMemorySlice(instanceContext, valueSpan)
+
+	logger.Trace("[ext_storage_append_version_1]", "key", fmt.Sprintf("0x%x", key), "val", valueAppend)
+	err := storageAppend(storage, key, valueAppend)
+	if err != nil {
+		logger.Error("[ext_storage_append_version_1]", "error", err)
+	}
 }
 
 //export ext_storage_changes_root_version_1
 func ext_storage_changes_root_version_1(context unsafe.Pointer, z C.int64_t) C.int64_t {
 	logger.Trace("[ext_storage_changes_root_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_changes_root_version_1")()
 	return 0
 }
 
 //export ext_storage_clear_version_1
 func ext_storage_clear_version_1(context unsafe.Pointer, a C.int64_t) {
 	logger.Trace("[ext_storage_clear_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_clear_version_1")()
 }
 
 //export ext_storage_clear_prefix_version_1
 func ext_storage_clear_prefix_version_1(context unsafe.Pointer, a C.int64_t) {
 	logger.Trace("[ext_storage_clear_prefix_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_clear_prefix_version_1")()
 }
 
 //export ext_storage_commit_transaction_version_1
 func ext_storage_commit_transaction_version_1(context unsafe.Pointer) {
 	logger.Trace("[ext_storage_commit_transaction_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_commit_transaction_version_1")()
 }
 
 //export ext_storage_exists_version_1
 func ext_storage_exists_version_1(context unsafe.Pointer, a C.int64_t) C.int32_t {
 	logger.Trace("[ext_storage_exists_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_exists_version_1")()
 	return 0
 }
 
 //export ext_storage_get_version_1
 func ext_storage_get_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int64_t {
 	logger.Trace("[ext_storage_get_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_get_version_1")()
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -601,6 +805,7 @@ func ext_storage_get_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int6
 //export ext_storage_next_key_version_1
 func ext_storage_next_key_version_1(context unsafe.Pointer, keySpan C.int64_t) C.int64_t {
 	logger.Trace("[ext_storage_next_key_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_next_key_version_1")()
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -622,23 +827,27 @@ func ext_storage_next_key_version_1(context unsafe.Pointer, keySpan C.int64_t) C
 //export ext_storage_read_version_1
 func ext_storage_read_version_1(context unsafe.Pointer, a, b C.int64_t, x C.int32_t) C.int64_t {
 	logger.Trace("[ext_storage_read_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_read_version_1")()
 	return 0
 }
 
 //export ext_storage_rollback_transaction_version_1
 func ext_storage_rollback_transaction_version_1(context unsafe.Pointer) {
 	logger.Trace("[ext_storage_rollback_transaction_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_rollback_transaction_version_1")()
 }
 
 //export ext_storage_root_version_1
 func ext_storage_root_version_1(context unsafe.Pointer) C.int64_t {
 	logger.Trace("[ext_storage_root_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_root_version_1")()
 	return 0
 }
 
 //export ext_storage_set_version_1
 func ext_storage_set_version_1(context unsafe.Pointer, keySpan C.int64_t, valueSpan C.int64_t) {
 	logger.Trace("[ext_storage_set_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_set_version_1")()
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	storage := instanceContext.Data().(*runtime.Context).Storage
@@ -657,6 +866,46 @@ func ext_storage_set_version_1(context unsafe.Pointer, keySpan C.int64_t, valueS
 //export ext_storage_start_transaction_version_1
 func ext_storage_start_transaction_version_1(context unsafe.Pointer) {
 	logger.Trace("[ext_storage_start_transaction_version_1] executing...")
+	defer observeHostCall(context, "ext_storage_start_transaction_version_1")()
+}
+
+// storageAppend appends the SCALE-encoded item valueToAppend to the SCALE-encoded Vec stored at key.
+// Only the compact length prefix of the stored value is re-encoded, the existing items are copied as-is.
+// If there is no value at key, or it does not begin with a valid length prefix, the value is replaced
+// with a Vec containing only the new item.
+func storageAppend(storage runtime.Storage, key, valueToAppend []byte) error {
+	current, err := storage.Get(key)
+	if err != nil {
+		return err
+	}
+
+	nextLength := big.NewInt(1)
+	var items []byte
+
+	if len(current) != 0 {
+		buf := bytes.NewBuffer(current)
+		sd := scale.Decoder{Reader: buf}
+
+		length, decErr := sd.DecodeBigInt()
+		if decErr != nil {
+			logger.Trace("[ext_storage_append_version_1] stored value is not a valid Vec, overwriting", "error", decErr)
+		} else {
+			nextLength.Add(length, big.NewInt(1))
+			items = buf.Bytes()
+		}
+	}
+
+	lengthEnc, err := scale.Encode(nextLength)
+	if err != nil {
+		return err
+	}
+
+	value := make([]byte, 0, len(lengthEnc)+len(items)+len(valueToAppend))
+	value = append(value, lengthEnc...)
+	value = append(value, items...)
+	value = append(value, valueToAppend...)
+
+	return storage.Set(key, value)
 }
 
 // Convert 64bit wasm span descriptor to Go memory slice
@@ -911,6 +1160,10 @@ func ImportsNodeRuntime() (*wasm.Imports, error) { //nolint
 	if err != nil {
 		return nil, err
 	}
+	_, err = imports.Append("ext_offchain_timestamp_version_1", ext_offchain_timestamp_version_1, C.ext_offchain_timestamp_version_1)
+	if err != nil {
+		return nil, err
+	}
 
 	_, err = imports.Append("ext_sandbox_instance_teardown_version_1", ext_sandbox_instance_teardown_version_1, C.ext_sandbox_instance_teardown_version_1)
 	if err != nil {
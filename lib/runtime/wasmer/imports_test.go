@@ -0,0 +1,142 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmer
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/scale"
+
+	database "github.com/ChainSafe/chaindb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageAppend(t *testing.T) {
+	storage := runtime.NewTestRuntimeStorage(t, nil)
+	key := []byte("events")
+
+	// appending to a missing key creates a Vec with one item
+	err := storageAppend(storage, key, []byte{1, 2})
+	require.NoError(t, err)
+
+	res, err := storage.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 1, 2}, res)
+
+	err = storageAppend(storage, key, []byte{3, 4})
+	require.NoError(t, err)
+
+	res, err = storage.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{8, 1, 2, 3, 4}, res)
+}
+
+func TestStorageAppend_LengthPrefixGrows(t *testing.T) {
+	storage := runtime.NewTestRuntimeStorage(t, nil)
+	key := []byte("events")
+
+	// 63 is the largest length that fits in a single byte compact prefix
+	items := make([][]byte, 63)
+	for i := range items {
+		items[i] = []byte{byte(i)}
+	}
+
+	enc, err := scale.Encode(items)
+	require.NoError(t, err)
+	err = storage.Set(key, enc)
+	require.NoError(t, err)
+
+	err = storageAppend(storage, key, []byte{4, 0xff})
+	require.NoError(t, err)
+
+	res, err := storage.Get(key)
+	require.NoError(t, err)
+
+	expected, err := scale.Encode(append(items, []byte{0xff}))
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+	require.Equal(t, []byte{0x01, 0x01}, res[:2])
+}
+
+func TestStorageAppend_InvalidValue(t *testing.T) {
+	storage := runtime.NewTestRuntimeStorage(t, nil)
+	key := []byte("events")
+
+	// big integer mode prefix with no following bytes
+	err := storage.Set(key, []byte{0xff})
+	require.NoError(t, err)
+
+	err = storageAppend(storage, key, []byte{9})
+	require.NoError(t, err)
+
+	res, err := storage.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 9}, res)
+}
+
+func TestLocalStorageCompareAndSet(t *testing.T) {
+	storage := database.NewMemDatabase()
+	key := []byte("heartbeat")
+
+	none, err := optional.NewBytes(false, nil).Encode()
+	require.NoError(t, err)
+	some, err := optional.NewBytes(true, []byte{1}).Encode()
+	require.NoError(t, err)
+
+	// the key is unset, so only None matches
+	set, err := localStorageCompareAndSet(storage, key, some, []byte{2})
+	require.NoError(t, err)
+	require.False(t, set)
+
+	set, err = localStorageCompareAndSet(storage, key, none, []byte{1})
+	require.NoError(t, err)
+	require.True(t, set)
+
+	set, err = localStorageCompareAndSet(storage, key, none, []byte{2})
+	require.NoError(t, err)
+	require.False(t, set)
+
+	set, err = localStorageCompareAndSet(storage, key, some, []byte{2})
+	require.NoError(t, err)
+	require.True(t, set)
+
+	res, err := storage.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, res)
+
+	_, err = localStorageCompareAndSet(storage, key, []byte{2}, []byte{3})
+	require.Error(t, err)
+}
+
+func TestAllocatorHostCallsObservedOnce(t *testing.T) {
+	instance := NewTestLegacyInstance(t, runtime.NODE_RUNTIME)
+	instance.ctx.HostStats = runtime.NewHostStats()
+
+	_, err := instance.Version()
+	require.NoError(t, err)
+
+	// the versioned allocator host functions don't also record the legacy ones they share the allocator with
+	names := make(map[string]bool)
+	for _, fs := range instance.ctx.HostStats.Stats() {
+		names[fs.Name] = true
+	}
+	require.True(t, names["ext_allocator_malloc_version_1"])
+	require.False(t, names["ext_malloc"])
+	require.False(t, names["ext_free"])
+}
@@ -17,11 +17,12 @@
 package wasmer
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"sync"
 
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/trace"
 	log "github.com/ChainSafe/log15"
 	wasm "github.com/wasmerio/go-ext-wasm/wasmer"
 )
@@ -39,13 +40,15 @@ var logger = log.New("pkg", "runtime", "module", "go-wasmer")
 type Config struct {
 	runtime.InstanceConfig
 	Imports func() (*wasm.Imports, error)
+	// CodeCache is used to load and store compiled modules if it's set
+	CodeCache *CodeCache
 }
 
 // LegacyInstance represents a v0.6 runtime go-wasmer instance
 type LegacyInstance struct {
 	vm    wasm.Instance
 	ctx   *runtime.Context
-	mutex sync.Mutex
+	calls runtime.CallScheduler
 }
 
 // Instance represents a v0.8 runtime go-wasmer instance
@@ -141,8 +144,15 @@ func newLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
 		return nil, err
 	}
 
+	// the runtime gets the heap pages on top of the initial pages of the memory it declares
+	pages, err := cfg.GetMemoryPages(code)
+	if err != nil {
+		return nil, err
+	}
+	maxPages := cfg.GetMaxMemoryPages()
+
 	// Provide importable memory for newer runtimes
-	memory, err := wasm.NewMemory(20, 0)
+	memory, err := wasm.NewMemory(pages, maxPages)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +163,7 @@ func newLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
 	}
 
 	// Instantiates the WebAssembly module.
-	instance, err := wasm.NewInstanceWithImports(code, imports)
+	instance, err := instantiate(code, imports, cfg.CodeCache)
 	if err != nil {
 		return nil, err
 	}
@@ -161,6 +171,12 @@ func newLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
 	// Assume imported memory is used if runtime does not export any
 	if !instance.HasMemory() {
 		instance.Memory = memory
+	} else {
+		err = growMemory(instance.Memory, pages, maxPages)
+		if err != nil {
+			instance.Close()
+			return nil, err
+		}
 	}
 
 	allocator := runtime.NewAllocator(instance.Memory, 0)
@@ -173,6 +189,7 @@ func newLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
 		NodeStorage: cfg.NodeStorage,
 		Network:     cfg.Network,
 		Transaction: cfg.Transaction,
+		HostStats:   cfg.HostStats,
 	}
 
 	logger.Debug("NewInstance", "runtimeCtx", runtimeCtx)
@@ -184,6 +201,22 @@ func newLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
 	}, nil
 }
 
+// growMemory grows the runtime's own memory to at least the given number of pages, returning an
+// error if the memory is already larger than the maximum number of pages
+func growMemory(memory *wasm.Memory, target, maxPages uint32) error {
+	pages := memory.Length() / runtime.PageSize
+	if pages > maxPages {
+		return runtime.ErrHeapPagesExceedLimit
+	}
+
+	if pages >= target {
+		return nil
+	}
+
+	logger.Debug("growing runtime memory", "pages", pages, "target", target)
+	return memory.Grow(target - pages)
+}
+
 // SetContext sets the runtime's storage. It should be set before calls to the below functions.
 func (in *LegacyInstance) SetContext(s runtime.Storage) {
 	in.ctx.Storage = s
@@ -195,6 +228,15 @@ func (in *LegacyInstance) Stop() {
 	in.vm.Close()
 }
 
+// Interrupt stops the instance's current call at the next memory allocation made by the runtime, which then
+// traps. The wasmer bindings don't provide a way to trap from outside of a call, nor from a host function
+// without aborting the process, so the allocation returns an out of bounds pointer that the runtime traps on.
+// Calls that don't allocate, such as a tight loop in the runtime, still run to completion. An interrupted
+// instance must not be used for further calls.
+func (in *LegacyInstance) Interrupt() {
+	in.ctx.Interrupt()
+}
+
 // Store func
 func (in *LegacyInstance) store(data []byte, location int32) {
 	mem := in.vm.Memory.Data()
@@ -214,6 +256,9 @@ func (in *LegacyInstance) Exec(function string, data []byte) ([]byte, error) {
 
 // Exec func
 func (in *LegacyInstance) exec(function string, data []byte) ([]byte, error) {
+	_, span := trace.StartSpan(context.Background(), "runtime.exec", trace.Attribute{Key: "function", Value: function})
+	defer span.End()
+
 	if in.ctx.Storage == nil {
 		return nil, runtime.ErrNilStorage
 	}
@@ -225,8 +270,8 @@ func (in *LegacyInstance) exec(function string, data []byte) ([]byte, error) {
 
 	defer in.clear()
 
-	in.mutex.Lock()
-	defer in.mutex.Unlock()
+	in.calls.Lock(runtime.CallPriority(function))
+	defer in.calls.Unlock()
 
 	// Store the data into memory
 	in.store(data, int32(ptr))
@@ -234,7 +279,7 @@ func (in *LegacyInstance) exec(function string, data []byte) ([]byte, error) {
 
 	runtimeFunc, ok := in.vm.Exports[function]
 	if !ok {
-		return nil, fmt.Errorf("could not find exported function %s", function)
+		return nil, fmt.Errorf("%w: could not find exported function %s", runtime.ErrMethodUnavailable, function)
 	}
 
 	res, err := runtimeFunc(int32(ptr), datalen)
@@ -273,3 +318,17 @@ func int64ToPointerAndSize(in int64) (ptr int32, length int32) {
 func pointerAndSizeToInt64(ptr, size int32) int64 {
 	return int64(ptr) + (int64(size) << 32)
 }
+
+func instantiate(code []byte, imports *wasm.Imports, cache *CodeCache) (wasm.Instance, error) {
+	if cache == nil {
+		return wasm.NewInstanceWithImports(code, imports)
+	}
+
+	module, err := cache.Module(code)
+	if err != nil {
+		return wasm.Instance{}, err
+	}
+	defer module.Close()
+
+	return module.InstantiateWithImports(imports)
+}
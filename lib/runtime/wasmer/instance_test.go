@@ -44,3 +44,14 @@ func TestPointerSize(t *testing.T) {
 	res := pointerAndSizeToInt64(ptr, length)
 	require.Equal(t, in, res)
 }
+
+func TestLegacyInstance_Interrupt(t *testing.T) {
+	instance := NewTestLegacyInstance(t, runtime.LEGACY_NODE_RUNTIME)
+
+	_, err := instance.exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	instance.Interrupt()
+	_, err = instance.exec(runtime.CoreVersion, []byte{})
+	require.Error(t, err)
+}
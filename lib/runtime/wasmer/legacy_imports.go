@@ -96,12 +96,19 @@ import (
 //export ext_print_num
 func ext_print_num(context unsafe.Pointer, data C.int64_t) {
 	logger.Trace("[ext_print_num] executing...")
+	defer observeHostCall(context, "ext_print_num")()
 	logger.Debug("[ext_print_num]", "message", fmt.Sprintf("%d", data))
 }
 
 //export ext_malloc
 func ext_malloc(context unsafe.Pointer, size C.int32_t) C.int32_t {
 	logger.Trace("[ext_malloc] executing...", "size", size)
+	defer observeHostCall(context, "ext_malloc")()
+	return malloc(context, size)
+}
+
+// malloc allocates memory for the runtime, the host functions that call it record their own stats
+func malloc(context unsafe.Pointer, size C.int32_t) C.int32_t {
 	instanceContext := wasm.IntoInstanceContext(context)
 	data := instanceContext.Data()
 	runtimeCtx, ok := data.(*runtime.Context)
@@ -109,6 +116,11 @@ func ext_malloc(context unsafe.Pointer, size C.int32_t) C.int32_t {
 		panic(fmt.Sprintf("%#v", data))
 	}
 
+	if runtimeCtx.Interrupted() {
+		// the runtime traps as soon as it accesses the out of bounds pointer
+		return C.int32_t(-1)
+	}
+
 	// Allocate memory
 	res, err := runtimeCtx.Allocator.Allocate(uint32(size))
 	if err != nil {
@@ -122,6 +134,12 @@ func ext_malloc(context unsafe.Pointer, size C.int32_t) C.int32_t {
 //export ext_free
 func ext_free(context unsafe.Pointer, addr C.int32_t) {
 	logger.Trace("[ext_free] executing...", "addr", addr)
+	defer observeHostCall(context, "ext_free")()
+	free(context, addr)
+}
+
+// free deallocates the runtime's memory at addr, the host functions that call it record their own stats
+func free(context unsafe.Pointer, addr C.int32_t) {
 	instanceContext := wasm.IntoInstanceContext(context)
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
 
@@ -137,6 +155,12 @@ func ext_free(context unsafe.Pointer, addr C.int32_t) {
 //export ext_print_utf8
 func ext_print_utf8(context unsafe.Pointer, utf8_data, utf8_len C.int32_t) {
 	logger.Trace("[ext_print_utf8] executing...")
+	defer observeHostCall(context, "ext_print_utf8")()
+	printUTF8(context, utf8_data, utf8_len)
+}
+
+// printUTF8 logs the string in memory at utf8_data, the host functions that call it record their own stats
+func printUTF8(context unsafe.Pointer, utf8_data, utf8_len C.int32_t) {
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	logger.Debug("[ext_print_utf8]", "message", fmt.Sprintf("%s", memory[utf8_data:utf8_data+utf8_len]))
@@ -146,6 +170,7 @@ func ext_print_utf8(context unsafe.Pointer, utf8_data, utf8_len C.int32_t) {
 //export ext_print_hex
 func ext_print_hex(context unsafe.Pointer, offset, size C.int32_t) {
 	logger.Trace("[ext_print_hex] executing...")
+	defer observeHostCall(context, "ext_print_hex")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	logger.Debug("[ext_print_hex]", "message", fmt.Sprintf("%x", memory[offset:offset+size]))
@@ -156,6 +181,7 @@ func ext_print_hex(context unsafe.Pointer, offset, size C.int32_t) {
 //export ext_get_storage_into
 func ext_get_storage_into(context unsafe.Pointer, keyData, keyLen, valueData, valueLen, valueOffset C.int32_t) C.int32_t {
 	logger.Trace("[ext_get_storage_into] executing...")
+	defer observeHostCall(context, "ext_get_storage_into")()
 
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
@@ -189,6 +215,7 @@ func ext_get_storage_into(context unsafe.Pointer, keyData, keyLen, valueData, va
 //export ext_set_storage
 func ext_set_storage(context unsafe.Pointer, keyData, keyLen, valueData, valueLen C.int32_t) {
 	logger.Trace("[ext_set_storage] executing...")
+	defer observeHostCall(context, "ext_set_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -208,6 +235,7 @@ func ext_set_storage(context unsafe.Pointer, keyData, keyLen, valueData, valueLe
 //export ext_set_child_storage
 func ext_set_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLen, keyData, keyLen, valueData, valueLen C.int32_t) {
 	logger.Trace("[ext_set_child_storage] executing...")
+	defer observeHostCall(context, "ext_set_child_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -227,6 +255,7 @@ func ext_set_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLen
 //export ext_get_child_storage_into
 func ext_get_child_storage_into(context unsafe.Pointer, storageKeyData, storageKeyLen, keyData, keyLen, valueData, valueLen, valueOffset C.int32_t) C.int32_t {
 	logger.Trace("[ext_get_child_storage_into] executing...")
+	defer observeHostCall(context, "ext_get_child_storage_into")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -250,6 +279,7 @@ func ext_get_child_storage_into(context unsafe.Pointer, storageKeyData, storageK
 //export ext_storage_root
 func ext_storage_root(context unsafe.Pointer, resultPtr C.int32_t) {
 	logger.Trace("[ext_storage_root] executing...")
+	defer observeHostCall(context, "ext_storage_root")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -268,6 +298,7 @@ func ext_storage_root(context unsafe.Pointer, resultPtr C.int32_t) {
 //export ext_storage_changes_root
 func ext_storage_changes_root(context unsafe.Pointer, a, b, c C.int32_t) C.int32_t {
 	logger.Trace("[ext_storage_changes_root] executing...")
+	defer observeHostCall(context, "ext_storage_changes_root")()
 	logger.Debug("[ext_storage_changes_root] Not yet implemented.")
 	return 0
 }
@@ -277,6 +308,7 @@ func ext_storage_changes_root(context unsafe.Pointer, a, b, c C.int32_t) C.int32
 //export ext_get_allocated_storage
 func ext_get_allocated_storage(context unsafe.Pointer, keyData, keyLen, writtenOut C.int32_t) C.int32_t {
 	logger.Trace("[ext_get_allocated_storage] executing...")
+	defer observeHostCall(context, "ext_get_allocated_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -330,6 +362,7 @@ func ext_get_allocated_storage(context unsafe.Pointer, keyData, keyLen, writtenO
 //export ext_clear_storage
 func ext_clear_storage(context unsafe.Pointer, keyData, keyLen C.int32_t) {
 	logger.Trace("[ext_clear_storage] executing...")
+	defer observeHostCall(context, "ext_clear_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -347,6 +380,7 @@ func ext_clear_storage(context unsafe.Pointer, keyData, keyLen C.int32_t) {
 //export ext_clear_prefix
 func ext_clear_prefix(context unsafe.Pointer, prefixData, prefixLen C.int32_t) {
 	logger.Trace("[ext_clear_prefix] executing...")
+	defer observeHostCall(context, "ext_clear_prefix")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -370,6 +404,7 @@ func ext_clear_prefix(context unsafe.Pointer, prefixData, prefixLen C.int32_t) {
 //export ext_blake2_256_enumerated_trie_root
 func ext_blake2_256_enumerated_trie_root(context unsafe.Pointer, valuesData, lensData, lensLen, result C.int32_t) {
 	logger.Trace("[ext_blake2_256_enumerated_trie_root] executing...")
+	defer observeHostCall(context, "ext_blake2_256_enumerated_trie_root")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -411,6 +446,7 @@ func ext_blake2_256_enumerated_trie_root(context unsafe.Pointer, valuesData, len
 //export ext_blake2_256
 func ext_blake2_256(context unsafe.Pointer, data, length, out C.int32_t) {
 	logger.Trace("[ext_blake2_256] executing...")
+	defer observeHostCall(context, "ext_blake2_256")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	hash, err := common.Blake2bHash(memory[data : data+length])
@@ -425,6 +461,7 @@ func ext_blake2_256(context unsafe.Pointer, data, length, out C.int32_t) {
 //export ext_blake2_128
 func ext_blake2_128(context unsafe.Pointer, data, length, out C.int32_t) {
 	logger.Trace("[ext_blake2_128] executing...")
+	defer observeHostCall(context, "ext_blake2_128")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	hash, err := common.Blake2b128(memory[data : data+length])
@@ -440,6 +477,7 @@ func ext_blake2_128(context unsafe.Pointer, data, length, out C.int32_t) {
 //export ext_keccak_256
 func ext_keccak_256(context unsafe.Pointer, data, length, out C.int32_t) {
 	logger.Trace("[ext_keccak_256] executing...")
+	defer observeHostCall(context, "ext_keccak_256")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	hash, err := common.Keccak256(memory[data : data+length])
@@ -455,6 +493,7 @@ func ext_keccak_256(context unsafe.Pointer, data, length, out C.int32_t) {
 //export ext_twox_64
 func ext_twox_64(context unsafe.Pointer, data, len, out C.int32_t) {
 	logger.Trace("[ext_twox_64] executing...")
+	defer observeHostCall(context, "ext_twox_64")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -471,6 +510,13 @@ func ext_twox_64(context unsafe.Pointer, data, len, out C.int32_t) {
 //export ext_twox_128
 func ext_twox_128(context unsafe.Pointer, data, len, out C.int32_t) {
 	logger.Trace("[ext_twox_128] executing...")
+	defer observeHostCall(context, "ext_twox_128")()
+	twox128(context, data, len, out)
+}
+
+// twox128 writes the twox128 hash of the data in memory to out, the host functions that call it record their
+// own stats
+func twox128(context unsafe.Pointer, data, len, out C.int32_t) {
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -487,6 +533,7 @@ func ext_twox_128(context unsafe.Pointer, data, len, out C.int32_t) {
 //export ext_sr25519_generate
 func ext_sr25519_generate(context unsafe.Pointer, idData, seed, seedLen, out C.int32_t) {
 	logger.Trace("[ext_sr25519_generate] executing...")
+	defer observeHostCall(context, "ext_sr25519_generate")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -512,6 +559,7 @@ func ext_sr25519_generate(context unsafe.Pointer, idData, seed, seedLen, out C.i
 //export ext_ed25519_public_keys
 func ext_ed25519_public_keys(context unsafe.Pointer, idData, resultLen C.int32_t) C.int32_t {
 	logger.Trace("[ext_ed25519_public_keys] executing...")
+	defer observeHostCall(context, "ext_ed25519_public_keys")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -538,6 +586,7 @@ func ext_ed25519_public_keys(context unsafe.Pointer, idData, resultLen C.int32_t
 //export ext_sr25519_public_keys
 func ext_sr25519_public_keys(context unsafe.Pointer, idData, resultLen C.int32_t) C.int32_t {
 	logger.Trace("[ext_sr25519_public_keys] executing...")
+	defer observeHostCall(context, "ext_sr25519_public_keys")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -564,6 +613,7 @@ func ext_sr25519_public_keys(context unsafe.Pointer, idData, resultLen C.int32_t
 //export ext_ed25519_sign
 func ext_ed25519_sign(context unsafe.Pointer, idData, pubkeyData, msgData, msgLen, out C.int32_t) C.int32_t {
 	logger.Trace("[ext_ed25519_sign] executing...")
+	defer observeHostCall(context, "ext_ed25519_sign")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -598,6 +648,7 @@ func ext_ed25519_sign(context unsafe.Pointer, idData, pubkeyData, msgData, msgLe
 //export ext_sr25519_sign
 func ext_sr25519_sign(context unsafe.Pointer, idData, pubkeyData, msgData, msgLen, out C.int32_t) C.int32_t {
 	logger.Trace("[ext_sr25519_sign] executing...")
+	defer observeHostCall(context, "ext_sr25519_sign")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -633,6 +684,7 @@ func ext_sr25519_sign(context unsafe.Pointer, idData, pubkeyData, msgData, msgLe
 //export ext_sr25519_verify
 func ext_sr25519_verify(context unsafe.Pointer, msgData, msgLen, sigData, pubkeyData C.int32_t) C.int32_t {
 	logger.Trace("[ext_sr25519_verify] executing...")
+	defer observeHostCall(context, "ext_sr25519_verify")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -654,6 +706,7 @@ func ext_sr25519_verify(context unsafe.Pointer, msgData, msgLen, sigData, pubkey
 //export ext_ed25519_generate
 func ext_ed25519_generate(context unsafe.Pointer, idData, seed, seedLen, out C.int32_t) {
 	logger.Trace("[ext_ed25519_generate] executing...")
+	defer observeHostCall(context, "ext_ed25519_generate")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -679,6 +732,7 @@ func ext_ed25519_generate(context unsafe.Pointer, idData, seed, seedLen, out C.i
 //export ext_ed25519_verify
 func ext_ed25519_verify(context unsafe.Pointer, msgData, msgLen, sigData, pubkeyData C.int32_t) C.int32_t {
 	logger.Trace("[ext_ed25519_verify] executing...")
+	defer observeHostCall(context, "ext_ed25519_verify")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -699,6 +753,7 @@ func ext_ed25519_verify(context unsafe.Pointer, msgData, msgLen, sigData, pubkey
 //export ext_secp256k1_ecdsa_recover
 func ext_secp256k1_ecdsa_recover(context unsafe.Pointer, msgData, sigData, pubkeyData C.int32_t) C.int32_t {
 	logger.Trace("[ext_secp256k1_ecdsa_recover] executing...")
+	defer observeHostCall(context, "ext_secp256k1_ecdsa_recover")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -720,6 +775,7 @@ func ext_secp256k1_ecdsa_recover(context unsafe.Pointer, msgData, sigData, pubke
 //export ext_is_validator
 func ext_is_validator(context unsafe.Pointer) C.int32_t {
 	logger.Trace("[ext_is_validator] executing...")
+	defer observeHostCall(context, "ext_is_validator")()
 	instanceContext := wasm.IntoInstanceContext(context)
 
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -732,6 +788,7 @@ func ext_is_validator(context unsafe.Pointer) C.int32_t {
 //export ext_local_storage_get
 func ext_local_storage_get(context unsafe.Pointer, kind, key, keyLen, valueLen C.int32_t) C.int32_t {
 	logger.Trace("[ext_local_storage_get] executing...")
+	defer observeHostCall(context, "ext_local_storage_get")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -763,6 +820,7 @@ func ext_local_storage_get(context unsafe.Pointer, kind, key, keyLen, valueLen C
 //export ext_local_storage_compare_and_set
 func ext_local_storage_compare_and_set(context unsafe.Pointer, kind, keyPtr, keyLen, oldValuePtr, oldValueLen, newValuePtr, newValueLen C.int32_t) C.int32_t {
 	logger.Trace("[ext_local_storage_compare_and_set] executing...")
+	defer observeHostCall(context, "ext_local_storage_compare_and_set")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -803,6 +861,7 @@ func ext_local_storage_compare_and_set(context unsafe.Pointer, kind, keyPtr, key
 //export ext_network_state
 func ext_network_state(context unsafe.Pointer, writtenOut C.int32_t) C.int32_t {
 	logger.Trace("[ext_network_state] executing...")
+	defer observeHostCall(context, "ext_network_state")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -835,6 +894,7 @@ func ext_network_state(context unsafe.Pointer, writtenOut C.int32_t) C.int32_t {
 //export ext_submit_transaction
 func ext_submit_transaction(context unsafe.Pointer, data, len C.int32_t) C.int32_t {
 	logger.Trace("[ext_submit_transaction] executing...")
+	defer observeHostCall(context, "ext_submit_transaction")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	runtimeCtx := instanceContext.Data().(*runtime.Context)
@@ -847,13 +907,19 @@ func ext_submit_transaction(context unsafe.Pointer, data, len C.int32_t) C.int32
 	txv := transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false)
 	vtx := transaction.NewValidTransaction(ext, txv)
 
-	runtimeCtx.Transaction.AddToPool(vtx)
+	_, err := runtimeCtx.Transaction.AddToPool(vtx)
+	if err != nil {
+		logger.Debug("[ext_submit_transaction] failed to add transaction to pool", "error", err)
+		return 1
+	}
+
 	return 0
 }
 
 //export ext_local_storage_set
 func ext_local_storage_set(context unsafe.Pointer, kind, key, keyLen, value, valueLen C.int32_t) {
 	logger.Trace("[ext_local_storage_set] executing...")
+	defer observeHostCall(context, "ext_local_storage_set")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -877,6 +943,7 @@ func ext_local_storage_set(context unsafe.Pointer, kind, key, keyLen, value, val
 //export ext_kill_child_storage
 func ext_kill_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLen C.int32_t) {
 	logger.Trace("[ext_kill_child_storage] executing...")
+	defer observeHostCall(context, "ext_kill_child_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -894,6 +961,7 @@ func ext_kill_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLe
 //export ext_sandbox_memory_new
 func ext_sandbox_memory_new(context unsafe.Pointer, a, b C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_new] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_new")()
 	logger.Warn("[ext_sandbox_memory_new] not yet implemented")
 	return 0
 }
@@ -901,12 +969,14 @@ func ext_sandbox_memory_new(context unsafe.Pointer, a, b C.int32_t) C.int32_t {
 //export ext_sandbox_memory_teardown
 func ext_sandbox_memory_teardown(context unsafe.Pointer, a C.int32_t) {
 	logger.Trace("[ext_sandbox_memory_teardown] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_teardown")()
 	logger.Warn("[ext_sandbox_memory_teardown] not yet implemented")
 }
 
 //export ext_sandbox_instantiate
 func ext_sandbox_instantiate(context unsafe.Pointer, a, b, c, d, e, f C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_instantiate] executing...")
+	defer observeHostCall(context, "ext_sandbox_instantiate")()
 	logger.Warn("[ext_sandbox_instantiate] not yet implemented")
 	return 0
 }
@@ -914,6 +984,7 @@ func ext_sandbox_instantiate(context unsafe.Pointer, a, b, c, d, e, f C.int32_t)
 //export ext_sandbox_invoke
 func ext_sandbox_invoke(context unsafe.Pointer, a, b, c, d, e, f, g, h C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_invoke] executing...")
+	defer observeHostCall(context, "ext_sandbox_invoke")()
 	logger.Warn("[ext_sandbox_invoke] not yet implemented")
 	return 0
 }
@@ -921,12 +992,14 @@ func ext_sandbox_invoke(context unsafe.Pointer, a, b, c, d, e, f, g, h C.int32_t
 //export ext_sandbox_instance_teardown
 func ext_sandbox_instance_teardown(context unsafe.Pointer, a C.int32_t) {
 	logger.Trace("[ext_sandbox_instance_teardown] executing...")
+	defer observeHostCall(context, "ext_sandbox_instance_teardown")()
 	logger.Warn("[ext_sandbox_instance_teardown] not yet implemented")
 }
 
 //export ext_get_allocated_child_storage
 func ext_get_allocated_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLen, keyData, keyLen, writtenOut C.int32_t) C.int32_t {
 	logger.Trace("[ext_get_allocated_child_storage] executing...")
+	defer observeHostCall(context, "ext_get_allocated_child_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -964,6 +1037,7 @@ func ext_get_allocated_child_storage(context unsafe.Pointer, storageKeyData, sto
 //export ext_child_storage_root
 func ext_child_storage_root(context unsafe.Pointer, a, b, c C.int32_t) C.int32_t {
 	logger.Trace("[ext_child_storage_root] executing...")
+	defer observeHostCall(context, "ext_child_storage_root")()
 	logger.Warn("[ext_child_storage_root] not yet implemented")
 	return 0
 }
@@ -971,6 +1045,7 @@ func ext_child_storage_root(context unsafe.Pointer, a, b, c C.int32_t) C.int32_t
 //export ext_clear_child_storage
 func ext_clear_child_storage(context unsafe.Pointer, storageKeyData, storageKeyLen, keyData, keyLen C.int32_t) {
 	logger.Trace("[ext_clear_child_storage] executing...")
+	defer observeHostCall(context, "ext_clear_child_storage")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 
@@ -988,6 +1063,7 @@ func ext_clear_child_storage(context unsafe.Pointer, storageKeyData, storageKeyL
 //export ext_secp256k1_ecdsa_recover_compressed
 func ext_secp256k1_ecdsa_recover_compressed(context unsafe.Pointer, a, b, c C.int32_t) C.int32_t {
 	logger.Trace("[ext_secp256k1_ecdsa_recover_compressed] executing...")
+	defer observeHostCall(context, "ext_secp256k1_ecdsa_recover_compressed")()
 	logger.Warn("[ext_secp256k1_ecdsa_recover_compressed] not yet implemented")
 	return 0
 }
@@ -995,6 +1071,7 @@ func ext_secp256k1_ecdsa_recover_compressed(context unsafe.Pointer, a, b, c C.in
 //export ext_sandbox_memory_get
 func ext_sandbox_memory_get(context unsafe.Pointer, a, b, c, d C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_get] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_get")()
 	logger.Warn("[ext_sandbox_memory_get] not yet implemented")
 	return 0
 }
@@ -1002,6 +1079,7 @@ func ext_sandbox_memory_get(context unsafe.Pointer, a, b, c, d C.int32_t) C.int3
 //export ext_sandbox_memory_set
 func ext_sandbox_memory_set(context unsafe.Pointer, a, b, c, d C.int32_t) C.int32_t {
 	logger.Trace("[ext_sandbox_memory_set] executing...")
+	defer observeHostCall(context, "ext_sandbox_memory_set")()
 	logger.Warn("[ext_sandbox_memory_set] not yet implemented")
 	return 0
 }
@@ -1009,12 +1087,14 @@ func ext_sandbox_memory_set(context unsafe.Pointer, a, b, c, d C.int32_t) C.int3
 //export ext_log
 func ext_log(context unsafe.Pointer, a, b, c, d, e C.int32_t) {
 	logger.Trace("[ext_log] executing...")
+	defer observeHostCall(context, "ext_log")()
 	logger.Warn("[ext_log] not yet implemented")
 }
 
 //export ext_twox_256
 func ext_twox_256(context unsafe.Pointer, data, len, out C.int32_t) {
 	logger.Trace("[ext_twox_256] executing...")
+	defer observeHostCall(context, "ext_twox_256")()
 	instanceContext := wasm.IntoInstanceContext(context)
 	memory := instanceContext.Memory().Data()
 	logger.Trace("[ext_twox_256] hashing...", "value", fmt.Sprintf("%s", memory[data:data+len]))
@@ -1031,6 +1111,7 @@ func ext_twox_256(context unsafe.Pointer, data, len, out C.int32_t) {
 //export ext_exists_storage
 func ext_exists_storage(context unsafe.Pointer, a, b C.int32_t) C.int32_t {
 	logger.Trace("[ext_exists_storage] executing...")
+	defer observeHostCall(context, "ext_exists_storage")()
 	logger.Warn("[ext_exists_storage] not yet implemented")
 	return 0
 }
@@ -1038,6 +1119,7 @@ func ext_exists_storage(context unsafe.Pointer, a, b C.int32_t) C.int32_t {
 //export ext_exists_child_storage
 func ext_exists_child_storage(context unsafe.Pointer, a, b, c, d C.int32_t) C.int32_t {
 	logger.Trace("[ext_exists_child_storage] executing...")
+	defer observeHostCall(context, "ext_exists_child_storage")()
 	logger.Warn("[ext_exists_child_storage] not yet implemented")
 	return 0
 }
@@ -1045,6 +1127,7 @@ func ext_exists_child_storage(context unsafe.Pointer, a, b, c, d C.int32_t) C.in
 //export ext_clear_child_prefix
 func ext_clear_child_prefix(context unsafe.Pointer, a, b, c, d C.int32_t) {
 	logger.Trace("[ext_clear_child_prefix] executing...")
+	defer observeHostCall(context, "ext_clear_child_prefix")()
 	logger.Warn("[ext_clear_child_prefix] not yet implemented")
 }
 
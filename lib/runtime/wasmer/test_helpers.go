@@ -132,6 +132,6 @@ type mockTransactionState struct {
 }
 
 // AddToPool adds a transaction to the pool
-func (mt *mockTransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
-	return common.BytesToHash([]byte("test"))
+func (mt *mockTransactionState) AddToPool(vt *transaction.ValidTransaction) (common.Hash, error) {
+	return common.BytesToHash([]byte("test")), nil
 }
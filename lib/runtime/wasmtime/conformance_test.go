@@ -0,0 +1,142 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasmtime
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+// hostAPIFixtures contains host function calls along with the outputs produced by Substrate's host API
+// for the same inputs, any difference from gossamer's output is a semantic drift in the host API
+const hostAPIFixtures = "../testdata/host_api_conformance.json"
+
+// implementedHostAPI are the host functions that aren't stubbed out, the cases calling any other host function
+// are skipped
+var implementedHostAPI = map[string]bool{
+	"ext_allocator_free_version_1":   true,
+	"ext_allocator_malloc_version_1": true,
+}
+
+// hostAPIInstance runs the host API conformance cases on a wasmtime instance
+type hostAPIInstance struct {
+	inst *LegacyInstance
+}
+
+func (in *hostAPIInstance) Call(function string, args []interface{}) (int64, error) {
+	export := in.inst.vm.GetExport(function)
+	if export == nil || export.Func() == nil {
+		return 0, fmt.Errorf("no export for host function %s", function)
+	}
+
+	res, err := export.Func().Call(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := res.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (in *hostAPIInstance) Memory() []byte {
+	return in.inst.mem.UnsafeData()
+}
+
+func (in *hostAPIInstance) Allocate(size uint32) (uint32, error) {
+	return ctx.Allocator.Allocate(size)
+}
+
+func (in *hostAPIInstance) Clear() {
+	ctx.Allocator.Clear()
+}
+
+// newHostAPIInstance instantiates a module calling the v0.8 host functions with the case's storage, it skips the
+// case if any of the host functions it calls isn't implemented
+func newHostAPIInstance(t testing.TB, c *runtime.HostAPICase) (*hostAPIInstance, runtime.InstanceConfig) {
+	for _, function := range c.Functions() {
+		if !implementedHostAPI[function] {
+			t.Skipf("%s isn't implemented", function)
+		}
+	}
+
+	// the imports are matched by position, so the module must import all of them in order
+	functions := make([]string, len(nodeRuntimeImports))
+	for i, imp := range nodeRuntimeImports {
+		functions[i] = imp.name
+	}
+
+	module, err := runtime.HostAPIModule(functions)
+	require.NoError(t, err)
+
+	cfg := &Config{
+		InstanceConfig: c.NewConfig(t),
+		Imports:        ImportsNodeRuntime,
+	}
+
+	instance, err := NewInstance(module, cfg)
+	require.NoError(t, err)
+	t.Cleanup(instance.Stop)
+	return &hostAPIInstance{inst: instance.inst}, cfg.InstanceConfig
+}
+
+func TestHostAPIConformance(t *testing.T) {
+	for _, c := range runtime.LoadHostAPICases(t, hostAPIFixtures) {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			instance, cfg := newHostAPIInstance(t, c)
+			runtime.RunHostAPICase(t, instance, c, cfg)
+		})
+	}
+}
+
+func TestHostAPIConformance_Imports(t *testing.T) {
+	for _, imp := range nodeRuntimeImports {
+		_, ok := runtime.HostAPISignatures[imp.name]
+		require.True(t, ok, "unknown host function %s", imp.name)
+	}
+
+	for function := range implementedHostAPI {
+		_, ok := runtime.HostAPISignatures[function]
+		require.True(t, ok, "unknown host function %s", function)
+	}
+}
+
+func BenchmarkHostAPI(b *testing.B) {
+	for _, c := range runtime.LoadHostAPICases(b, hostAPIFixtures) {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			instance, _ := newHostAPIInstance(b, c)
+			for i := range c.Before {
+				runtime.CallHostAPI(b, instance, &c.Before[i])
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				runtime.CallHostAPI(b, instance, &c.HostAPICall)
+			}
+		})
+	}
+}
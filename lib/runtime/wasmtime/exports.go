@@ -50,8 +50,24 @@ func (in *LegacyInstance) Version() (*runtime.VersionAPI, error) {
 	return version, nil
 }
 
-// BabeConfiguration gets the configuration data for BABE from the runtime
+// checkCall returns runtime.ErrMethodUnavailable if the runtime doesn't implement the API of the given runtime call
+func (in *LegacyInstance) checkCall(function string) error {
+	ver, err := in.Version()
+	if err != nil {
+		return err
+	}
+
+	return ver.CheckCall(function)
+}
+
+// BabeConfiguration gets the configuration data for BABE from the runtime, it returns runtime.ErrMethodUnavailable
+// if the runtime doesn't implement BabeApi
 func (in *LegacyInstance) BabeConfiguration() (*types.BabeConfiguration, error) {
+	err := in.checkCall(runtime.BabeAPIConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
 	ret, err := in.exec(runtime.BabeAPIConfiguration, []byte{})
 	if err != nil {
 		return nil, err
@@ -131,6 +147,7 @@ func (in *LegacyInstance) FinalizeBlock() (*types.Header, error) {
 		return nil, err
 	}
 
+	in.logHostStats(bh.Number)
 	return bh, nil
 }
 
@@ -138,13 +155,19 @@ func (in *LegacyInstance) FinalizeBlock() (*types.Header, error) {
 func (in *LegacyInstance) ExecuteBlock(block *types.Block) ([]byte, error) {
 	b := block.DeepCopy()
 
-	b.Header.Digest = [][]byte{}
+	b.Header.Digest = types.NewEmptyDigest()
 	bdEnc, err := b.Encode()
 	if err != nil {
 		return nil, err
 	}
 
-	return in.exec(runtime.CoreExecuteBlock, bdEnc)
+	res, err := in.exec(runtime.CoreExecuteBlock, bdEnc)
+	if err != nil {
+		return nil, err
+	}
+
+	in.logHostStats(block.Header.Number)
+	return res, nil
 }
 
 // ValidateTransaction runs the extrinsic through runtime function TaggedTransactionQueue_validate_transaction and returns *Validity
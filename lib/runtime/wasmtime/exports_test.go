@@ -94,7 +94,7 @@ func TestInstance_InitializeBlock_LegacyNodeRuntime(t *testing.T) {
 	header := &types.Header{
 		ParentHash: trie.EmptyHash,
 		Number:     big.NewInt(1),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	instance := NewTestInstance(t, runtime.LEGACY_NODE_RUNTIME)
@@ -106,7 +106,7 @@ func TestInstance_InherentExtrinsics_LegacyNodeRuntime(t *testing.T) {
 	header := &types.Header{
 		ParentHash: trie.EmptyHash,
 		Number:     big.NewInt(1),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	instance := NewTestInstance(t, runtime.LEGACY_NODE_RUNTIME)
@@ -151,7 +151,7 @@ func TestInstance_FinalizeBlock_LegacyNodeRuntime(t *testing.T) {
 	header := &types.Header{
 		ParentHash: trie.EmptyHash,
 		Number:     big.NewInt(77),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	err := instance.InitializeBlock(header)
@@ -196,7 +196,7 @@ func TestInstance_FinalizeBlock_LegacyNodeRuntime(t *testing.T) {
 	expected := &types.Header{
 		ParentHash: header.ParentHash,
 		Number:     big.NewInt(77),
-		Digest:     [][]byte{},
+		Digest:     types.NewEmptyDigest(),
 	}
 
 	require.Equal(t, expected.ParentHash, res.ParentHash)
@@ -22,131 +22,159 @@ import (
 
 func ext_logging_log_version_1(c *wasmtime.Caller, level int32, target, msg int64) {
 	logger.Trace("[ext_logging_log_version_1] executing...")
+	defer observeHostCall("ext_logging_log_version_1")()
 }
 
 func ext_sandbox_instance_teardown_version_1(c *wasmtime.Caller, a int32) {
 	logger.Trace("[ext_sandbox_instance_teardown_version_1] executing...")
+	defer observeHostCall("ext_sandbox_instance_teardown_version_1")()
 }
 
 func ext_sandbox_instantiate_version_1(c *wasmtime.Caller, a int32, x, y int64, z int32) int32 {
 	logger.Trace("[ext_sandbox_instantiate_version_1] executing...")
+	defer observeHostCall("ext_sandbox_instantiate_version_1")()
 	return 0
 }
 
 func ext_sandbox_invoke_version_1(c *wasmtime.Caller, a int32, x, y int64, z, d, e int32) int32 {
 	logger.Trace("[ext_sandbox_invoke_version_1] executing...")
+	defer observeHostCall("ext_sandbox_invoke_version_1")()
 	return 0
 }
 
 func ext_sandbox_memory_get_version_1(c *wasmtime.Caller, a, z, d, e int32) int32 {
 	logger.Trace("[ext_sandbox_memory_get_version_1] executing...")
+	defer observeHostCall("ext_sandbox_memory_get_version_1")()
 	return 0
 }
 
 func ext_sandbox_memory_new_version_1(c *wasmtime.Caller, a, z int32) int32 {
 	logger.Trace("[ext_sandbox_memory_new_version_1] executing...")
+	defer observeHostCall("ext_sandbox_memory_new_version_1")()
 	return 0
 }
 
 func ext_sandbox_memory_set_version_1(c *wasmtime.Caller, a, z, d, e int32) int32 {
 	logger.Trace("[ext_sandbox_memory_set_version_1] executing...")
+	defer observeHostCall("ext_sandbox_memory_set_version_1")()
 	return 0
 }
 
 func ext_sandbox_memory_teardown_version_1(c *wasmtime.Caller, a int32) {
 	logger.Trace("[ext_sandbox_memory_teardown_version_1] executing...")
+	defer observeHostCall("ext_sandbox_memory_teardown_version_1")()
 }
 
 func ext_crypto_ed25519_generate_version_1(c *wasmtime.Caller, a int32, z int64) int32 {
 	logger.Trace("[ext_crypto_ed25519_generate_version_1] executing...")
+	defer observeHostCall("ext_crypto_ed25519_generate_version_1")()
 	return 0
 }
 
 func ext_crypto_ed25519_verify_version_1(c *wasmtime.Caller, a int32, z int64, y int32) int32 {
 	logger.Trace("[ext_crypto_ed25519_verify_version_1] executing...")
+	defer observeHostCall("ext_crypto_ed25519_verify_version_1")()
 	return 0
 }
 
 func ext_crypto_finish_batch_verify_version_1(c *wasmtime.Caller) int32 {
 	logger.Trace("[ext_crypto_finish_batch_verify_version_1] executing...")
+	defer observeHostCall("ext_crypto_finish_batch_verify_version_1")()
 	return 0
 }
 
 func ext_crypto_secp256k1_ecdsa_recover_compressed_version_1(c *wasmtime.Caller, a, z int32) int64 {
 	logger.Trace("[ext_crypto_secp256k1_ecdsa_recover_compressed_version_1] executing...")
+	defer observeHostCall("ext_crypto_secp256k1_ecdsa_recover_compressed_version_1")()
 	return 0
 }
 
 func ext_crypto_sr25519_generate_version_1(c *wasmtime.Caller, a int32, z int64) int32 {
 	logger.Trace("[ext_crypto_sr25519_generate_version_1] executing...")
+	defer observeHostCall("ext_crypto_sr25519_generate_version_1")()
 	return 0
 }
 
 func ext_crypto_sr25519_public_keys_version_1(c *wasmtime.Caller, a int32) int64 {
 	logger.Trace("[ext_crypto_sr25519_public_keys_version_1] executing...")
+	defer observeHostCall("ext_crypto_sr25519_public_keys_version_1")()
 	return 0
 }
 
 func ext_crypto_sr25519_sign_version_1(c *wasmtime.Caller, a, z int32, y int64) int64 {
 	logger.Trace("[ext_crypto_sr25519_sign_version_1] executing...")
+	defer observeHostCall("ext_crypto_sr25519_sign_version_1")()
 	return 0
 }
 
 func ext_crypto_sr25519_verify_version_2(c *wasmtime.Caller, a int32, z int64, y int32) int32 {
 	logger.Trace("[ext_crypto_sr25519_verify_version_2] executing...")
+	defer observeHostCall("ext_crypto_sr25519_verify_version_2")()
 	return 0
 }
 
 func ext_crypto_start_batch_verify_version_1(c *wasmtime.Caller) {
 	logger.Trace("[ext_crypto_start_batch_verify_version_1] executing...")
+	defer observeHostCall("ext_crypto_start_batch_verify_version_1")()
 }
 
 func ext_trie_blake2_256_ordered_root_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_trie_blake2_256_ordered_root_version_1] executing...")
+	defer observeHostCall("ext_trie_blake2_256_ordered_root_version_1")()
 	return 0
 }
 
 func ext_misc_print_hex_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_misc_print_hex_version_1] executing...")
+	defer observeHostCall("ext_misc_print_hex_version_1")()
 }
 
 func ext_misc_print_num_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_misc_print_num_version_1] executing...")
+	defer observeHostCall("ext_misc_print_num_version_1")()
 }
 
 func ext_misc_print_utf8_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_misc_print_utf8_version_1] executing...")
+	defer observeHostCall("ext_misc_print_utf8_version_1")()
 }
 
 func ext_misc_runtime_version_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_misc_runtime_version_version_1] executing...")
+	defer observeHostCall("ext_misc_runtime_version_version_1")()
 	return 0
 }
 
 func ext_default_child_storage_clear_version_1(c *wasmtime.Caller, a, b int64) {
 	logger.Trace("[ext_default_child_storage_clear_version_1] executing...")
+	defer observeHostCall("ext_default_child_storage_clear_version_1")()
 }
 
 func ext_default_child_storage_get_version_1(c *wasmtime.Caller, a, b int64) int64 {
 	logger.Trace("[ext_default_child_storage_get_version_1] executing...")
+	defer observeHostCall("ext_default_child_storage_get_version_1")()
 	return 0
 }
 
 func ext_default_child_storage_root_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_default_child_storage_root_version_1] executing...")
+	defer observeHostCall("ext_default_child_storage_root_version_1")()
 	return 0
 }
 
 func ext_default_child_storage_set_version_1(c *wasmtime.Caller, a, b, z int64) {
 	logger.Trace("[ext_default_child_storage_set_version_1] executing...")
+	defer observeHostCall("ext_default_child_storage_set_version_1")()
 }
 
 func ext_default_child_storage_storage_kill_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_default_child_storage_storage_kill_version_1] executing...")
+	defer observeHostCall("ext_default_child_storage_storage_kill_version_1")()
 }
 
 func ext_allocator_free_version_1(c *wasmtime.Caller, addr int32) {
 	logger.Trace("[ext_allocator_free_version_1] executing...")
+	defer observeHostCall("ext_allocator_free_version_1")()
 	err := ctx.Allocator.Deallocate(uint32(addr))
 	if err != nil {
 		logger.Error("[ext_free]", "error", err)
@@ -155,6 +183,7 @@ func ext_allocator_free_version_1(c *wasmtime.Caller, addr int32) {
 
 func ext_allocator_malloc_version_1(c *wasmtime.Caller, size int32) int32 {
 	logger.Trace("[ext_allocator_malloc_version_1] executing...")
+	defer observeHostCall("ext_allocator_malloc_version_1")()
 	res, err := ctx.Allocator.Allocate(uint32(size))
 	if err != nil {
 		logger.Error("[ext_malloc]", "Error:", err)
@@ -164,123 +193,212 @@ func ext_allocator_malloc_version_1(c *wasmtime.Caller, size int32) int32 {
 
 func ext_hashing_blake2_128_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_blake2_128_version_1] executing...")
+	defer observeHostCall("ext_hashing_blake2_128_version_1")()
 	return 0
 }
 
 func ext_hashing_blake2_256_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_blake2_256_version_1] executing...")
+	defer observeHostCall("ext_hashing_blake2_256_version_1")()
 	return 0
 }
 
 func ext_hashing_keccak_256_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_keccak_256_version_1] executing...")
+	defer observeHostCall("ext_hashing_keccak_256_version_1")()
 	return 0
 }
 
 func ext_hashing_sha2_256_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_sha2_256_version_1] executing...")
+	defer observeHostCall("ext_hashing_sha2_256_version_1")()
 	return 0
 }
 
 func ext_hashing_twox_128_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_twox_128_version_1] executing...")
+	defer observeHostCall("ext_hashing_twox_128_version_1")()
 	return 0
 }
 
 func ext_hashing_twox_64_version_1(c *wasmtime.Caller, z int64) int32 {
 	logger.Trace("[ext_hashing_twox_64_version_1] executing...")
+	defer observeHostCall("ext_hashing_twox_64_version_1")()
 	return 0
 }
 
 func ext_offchain_is_validator_version_1(c *wasmtime.Caller) int32 {
 	logger.Trace("[ext_offchain_is_validator_version_1] executing...")
+	defer observeHostCall("ext_offchain_is_validator_version_1")()
 	return 0
 }
 
 func ext_offchain_local_storage_compare_and_set_version_1(c *wasmtime.Caller, a int32, x, y, z int64) int32 {
 	logger.Trace("[ext_offchain_local_storage_compare_and_set_version_1] executing...")
+	defer observeHostCall("ext_offchain_local_storage_compare_and_set_version_1")()
 	return 0
 }
 
 func ext_offchain_local_storage_get_version_1(c *wasmtime.Caller, a int32, x int64) int64 {
 	logger.Trace("[ext_offchain_local_storage_get_version_1] executing...")
+	defer observeHostCall("ext_offchain_local_storage_get_version_1")()
 	return 0
 }
 
 func ext_offchain_local_storage_set_version_1(c *wasmtime.Caller, a int32, x, y int64) {
 	logger.Trace("[ext_offchain_local_storage_set_version_1] executing...")
+	defer observeHostCall("ext_offchain_local_storage_set_version_1")()
 }
 
 func ext_offchain_network_state_version_1(c *wasmtime.Caller) int64 {
 	logger.Trace("[ext_offchain_network_state_version_1] executing...")
+	defer observeHostCall("ext_offchain_network_state_version_1")()
 	return 0
 }
 
 func ext_offchain_random_seed_version_1(c *wasmtime.Caller) int32 {
 	logger.Trace("[ext_offchain_random_seed_version_1] executing...")
+	defer observeHostCall("ext_offchain_random_seed_version_1")()
 	return 0
 }
 
 func ext_offchain_submit_transaction_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_offchain_submit_transaction_version_1] executing...")
+	defer observeHostCall("ext_offchain_submit_transaction_version_1")()
 	return 0
 }
 
 func ext_storage_append_version_1(c *wasmtime.Caller, a, b int64) {
 	logger.Trace("[ext_storage_append_version_1] executing...")
+	defer observeHostCall("ext_storage_append_version_1")()
 }
 
 func ext_storage_changes_root_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_storage_changes_root_version_1] executing...")
+	defer observeHostCall("ext_storage_changes_root_version_1")()
 	return 0
 }
 
 func ext_storage_clear_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_storage_clear_version_1] executing...")
+	defer observeHostCall("ext_storage_clear_version_1")()
 }
 
 func ext_storage_clear_prefix_version_1(c *wasmtime.Caller, a int64) {
 	logger.Trace("[ext_storage_clear_prefix_version_1] executing...")
+	defer observeHostCall("ext_storage_clear_prefix_version_1")()
 }
 
 func ext_storage_commit_transaction_version_1(c *wasmtime.Caller) {
 	logger.Trace("[ext_storage_commit_transaction_version_1] executing...")
+	defer observeHostCall("ext_storage_commit_transaction_version_1")()
 }
 
 func ext_storage_get_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_storage_get_version_1] executing...")
+	defer observeHostCall("ext_storage_get_version_1")()
 	return 0
 }
 
 func ext_storage_next_key_version_1(c *wasmtime.Caller, z int64) int64 {
 	logger.Trace("[ext_storage_next_key_version_1] executing...")
+	defer observeHostCall("ext_storage_next_key_version_1")()
 	return 0
 }
 
 func ext_storage_read_version_1(c *wasmtime.Caller, a, b int64, x int32) int64 {
 	logger.Trace("[ext_storage_read_version_1] executing...")
+	defer observeHostCall("ext_storage_read_version_1")()
 	return 0
 }
 
 func ext_storage_rollback_transaction_version_1(c *wasmtime.Caller) {
 	logger.Trace("[ext_storage_rollback_transaction_version_1] executing...")
+	defer observeHostCall("ext_storage_rollback_transaction_version_1")()
 }
 
 func ext_storage_root_version_1(c *wasmtime.Caller) int64 {
 	logger.Trace("[ext_storage_root_version_1] executing...")
+	defer observeHostCall("ext_storage_root_version_1")()
 	return 0
 }
 
 func ext_storage_set_version_1(c *wasmtime.Caller, a, b int64) {
 	logger.Trace("[ext_storage_set_version_1] executing...")
+	defer observeHostCall("ext_storage_set_version_1")()
 }
 
 func ext_storage_start_transaction_version_1(c *wasmtime.Caller) {
 	logger.Trace("[ext_storage_start_transaction_version_1] executing...")
+	defer observeHostCall("ext_storage_start_transaction_version_1")()
 }
 
 func ext_offchain_index_set_version_1(c *wasmtime.Caller, a, b int64) {
 	logger.Trace("[ext_offchain_index_set_version_1] executing...")
+	defer observeHostCall("ext_offchain_index_set_version_1")()
+}
+
+// nodeRuntimeImports are the host functions of the v0.8 runtime, in the order ImportsNodeRuntime returns them after
+// the memory
+var nodeRuntimeImports = []struct {
+	name string
+	fn   interface{}
+}{
+	{"ext_logging_log_version_1", ext_logging_log_version_1},
+	{"ext_sandbox_instance_teardown_version_1", ext_sandbox_instance_teardown_version_1},
+	{"ext_sandbox_instantiate_version_1", ext_sandbox_instantiate_version_1},
+	{"ext_sandbox_invoke_version_1", ext_sandbox_invoke_version_1},
+	{"ext_sandbox_memory_get_version_1", ext_sandbox_memory_get_version_1},
+	{"ext_sandbox_memory_new_version_1", ext_sandbox_memory_new_version_1},
+	{"ext_sandbox_memory_set_version_1", ext_sandbox_memory_set_version_1},
+	{"ext_sandbox_memory_teardown_version_1", ext_sandbox_memory_teardown_version_1},
+	{"ext_crypto_ed25519_generate_version_1", ext_crypto_ed25519_generate_version_1},
+	{"ext_crypto_ed25519_verify_version_1", ext_crypto_ed25519_verify_version_1},
+	{"ext_crypto_finish_batch_verify_version_1", ext_crypto_finish_batch_verify_version_1},
+	{"ext_crypto_secp256k1_ecdsa_recover_compressed_version_1", ext_crypto_secp256k1_ecdsa_recover_compressed_version_1},
+	{"ext_crypto_sr25519_generate_version_1", ext_crypto_sr25519_generate_version_1},
+	{"ext_crypto_sr25519_public_keys_version_1", ext_crypto_sr25519_public_keys_version_1},
+	{"ext_crypto_sr25519_sign_version_1", ext_crypto_sr25519_sign_version_1},
+	{"ext_crypto_sr25519_verify_version_2", ext_crypto_sr25519_verify_version_2},
+	{"ext_crypto_start_batch_verify_version_1", ext_crypto_start_batch_verify_version_1},
+	{"ext_trie_blake2_256_ordered_root_version_1", ext_trie_blake2_256_ordered_root_version_1},
+	{"ext_misc_print_hex_version_1", ext_misc_print_hex_version_1},
+	{"ext_misc_print_num_version_1", ext_misc_print_num_version_1},
+	{"ext_misc_print_utf8_version_1", ext_misc_print_utf8_version_1},
+	{"ext_misc_runtime_version_version_1", ext_misc_runtime_version_version_1},
+	{"ext_default_child_storage_clear_version_1", ext_default_child_storage_clear_version_1},
+	{"ext_default_child_storage_get_version_1", ext_default_child_storage_get_version_1},
+	{"ext_default_child_storage_root_version_1", ext_default_child_storage_root_version_1},
+	{"ext_default_child_storage_set_version_1", ext_default_child_storage_set_version_1},
+	{"ext_default_child_storage_storage_kill_version_1", ext_default_child_storage_storage_kill_version_1},
+	{"ext_allocator_free_version_1", ext_allocator_free_version_1},
+	{"ext_allocator_malloc_version_1", ext_allocator_malloc_version_1},
+	{"ext_hashing_blake2_128_version_1", ext_hashing_blake2_128_version_1},
+	{"ext_hashing_blake2_256_version_1", ext_hashing_blake2_256_version_1},
+	{"ext_hashing_keccak_256_version_1", ext_hashing_keccak_256_version_1},
+	{"ext_hashing_sha2_256_version_1", ext_hashing_sha2_256_version_1},
+	{"ext_hashing_twox_128_version_1", ext_hashing_twox_128_version_1},
+	{"ext_hashing_twox_64_version_1", ext_hashing_twox_64_version_1},
+	{"ext_offchain_is_validator_version_1", ext_offchain_is_validator_version_1},
+	{"ext_offchain_local_storage_compare_and_set_version_1", ext_offchain_local_storage_compare_and_set_version_1},
+	{"ext_offchain_local_storage_get_version_1", ext_offchain_local_storage_get_version_1},
+	{"ext_offchain_local_storage_set_version_1", ext_offchain_local_storage_set_version_1},
+	{"ext_offchain_network_state_version_1", ext_offchain_network_state_version_1},
+	{"ext_offchain_random_seed_version_1", ext_offchain_random_seed_version_1},
+	{"ext_offchain_submit_transaction_version_1", ext_offchain_submit_transaction_version_1},
+	{"ext_storage_append_version_1", ext_storage_append_version_1},
+	{"ext_storage_changes_root_version_1", ext_storage_changes_root_version_1},
+	{"ext_storage_clear_version_1", ext_storage_clear_version_1},
+	{"ext_storage_clear_prefix_version_1", ext_storage_clear_prefix_version_1},
+	{"ext_storage_commit_transaction_version_1", ext_storage_commit_transaction_version_1},
+	{"ext_storage_get_version_1", ext_storage_get_version_1},
+	{"ext_storage_next_key_version_1", ext_storage_next_key_version_1},
+	{"ext_storage_read_version_1", ext_storage_read_version_1},
+	{"ext_storage_rollback_transaction_version_1", ext_storage_rollback_transaction_version_1},
+	{"ext_storage_root_version_1", ext_storage_root_version_1},
+	{"ext_storage_set_version_1", ext_storage_set_version_1},
+	{"ext_storage_start_transaction_version_1", ext_storage_start_transaction_version_1},
+	{"ext_offchain_index_set_version_1", ext_offchain_index_set_version_1},
 }
 
 // ImportsNodeRuntime returns the imports for the v0.8 runtime
@@ -291,118 +409,9 @@ func ImportsNodeRuntime(store *wasmtime.Store) []*wasmtime.Extern {
 	}
 	mem := wasmtime.NewMemory(store, wasmtime.NewMemoryType(lim))
 
-	ext_logging_log_version_1 := wasmtime.WrapFunc(store, ext_logging_log_version_1)
-	ext_sandbox_instance_teardown_version_1 := wasmtime.WrapFunc(store, ext_sandbox_instance_teardown_version_1)
-	ext_sandbox_instantiate_version_1 := wasmtime.WrapFunc(store, ext_sandbox_instantiate_version_1)
-	ext_sandbox_invoke_version_1 := wasmtime.WrapFunc(store, ext_sandbox_invoke_version_1)
-	ext_sandbox_memory_get_version_1 := wasmtime.WrapFunc(store, ext_sandbox_memory_get_version_1)
-	ext_sandbox_memory_new_version_1 := wasmtime.WrapFunc(store, ext_sandbox_memory_new_version_1)
-	ext_sandbox_memory_set_version_1 := wasmtime.WrapFunc(store, ext_sandbox_memory_set_version_1)
-	ext_sandbox_memory_teardown_version_1 := wasmtime.WrapFunc(store, ext_sandbox_memory_teardown_version_1)
-	ext_crypto_ed25519_generate_version_1 := wasmtime.WrapFunc(store, ext_crypto_ed25519_generate_version_1)
-	ext_crypto_ed25519_verify_version_1 := wasmtime.WrapFunc(store, ext_crypto_ed25519_verify_version_1)
-	ext_crypto_finish_batch_verify_version_1 := wasmtime.WrapFunc(store, ext_crypto_finish_batch_verify_version_1)
-	ext_crypto_secp256k1_ecdsa_recover_compressed_version_1 := wasmtime.WrapFunc(store, ext_crypto_secp256k1_ecdsa_recover_compressed_version_1)
-	ext_crypto_sr25519_generate_version_1 := wasmtime.WrapFunc(store, ext_crypto_sr25519_generate_version_1)
-	ext_crypto_sr25519_public_keys_version_1 := wasmtime.WrapFunc(store, ext_crypto_sr25519_public_keys_version_1)
-	ext_crypto_sr25519_sign_version_1 := wasmtime.WrapFunc(store, ext_crypto_sr25519_sign_version_1)
-	ext_crypto_sr25519_verify_version_2 := wasmtime.WrapFunc(store, ext_crypto_sr25519_verify_version_2)
-	ext_crypto_start_batch_verify_version_1 := wasmtime.WrapFunc(store, ext_crypto_start_batch_verify_version_1)
-	ext_trie_blake2_256_ordered_root_version_1 := wasmtime.WrapFunc(store, ext_trie_blake2_256_ordered_root_version_1)
-	ext_misc_print_hex_version_1 := wasmtime.WrapFunc(store, ext_misc_print_hex_version_1)
-	ext_misc_print_num_version_1 := wasmtime.WrapFunc(store, ext_misc_print_num_version_1)
-	ext_misc_print_utf8_version_1 := wasmtime.WrapFunc(store, ext_misc_print_utf8_version_1)
-	ext_misc_runtime_version_version_1 := wasmtime.WrapFunc(store, ext_misc_runtime_version_version_1)
-	ext_default_child_storage_clear_version_1 := wasmtime.WrapFunc(store, ext_default_child_storage_clear_version_1)
-	ext_default_child_storage_get_version_1 := wasmtime.WrapFunc(store, ext_default_child_storage_get_version_1)
-	ext_default_child_storage_root_version_1 := wasmtime.WrapFunc(store, ext_default_child_storage_root_version_1)
-	ext_default_child_storage_set_version_1 := wasmtime.WrapFunc(store, ext_default_child_storage_set_version_1)
-	ext_default_child_storage_storage_kill_version_1 := wasmtime.WrapFunc(store, ext_default_child_storage_storage_kill_version_1)
-	ext_allocator_free_version_1 := wasmtime.WrapFunc(store, ext_allocator_free_version_1)
-	ext_allocator_malloc_version_1 := wasmtime.WrapFunc(store, ext_allocator_malloc_version_1)
-	ext_hashing_blake2_128_version_1 := wasmtime.WrapFunc(store, ext_hashing_blake2_128_version_1)
-	ext_hashing_blake2_256_version_1 := wasmtime.WrapFunc(store, ext_hashing_blake2_256_version_1)
-	ext_hashing_keccak_256_version_1 := wasmtime.WrapFunc(store, ext_hashing_keccak_256_version_1)
-	ext_hashing_sha2_256_version_1 := wasmtime.WrapFunc(store, ext_hashing_sha2_256_version_1)
-	ext_hashing_twox_128_version_1 := wasmtime.WrapFunc(store, ext_hashing_twox_128_version_1)
-	ext_hashing_twox_64_version_1 := wasmtime.WrapFunc(store, ext_hashing_twox_64_version_1)
-	ext_offchain_is_validator_version_1 := wasmtime.WrapFunc(store, ext_offchain_is_validator_version_1)
-	ext_offchain_local_storage_compare_and_set_version_1 := wasmtime.WrapFunc(store, ext_offchain_local_storage_compare_and_set_version_1)
-	ext_offchain_local_storage_get_version_1 := wasmtime.WrapFunc(store, ext_offchain_local_storage_get_version_1)
-	ext_offchain_local_storage_set_version_1 := wasmtime.WrapFunc(store, ext_offchain_local_storage_set_version_1)
-	ext_offchain_network_state_version_1 := wasmtime.WrapFunc(store, ext_offchain_network_state_version_1)
-	ext_offchain_random_seed_version_1 := wasmtime.WrapFunc(store, ext_offchain_random_seed_version_1)
-	ext_offchain_submit_transaction_version_1 := wasmtime.WrapFunc(store, ext_offchain_submit_transaction_version_1)
-	ext_storage_append_version_1 := wasmtime.WrapFunc(store, ext_storage_append_version_1)
-	ext_storage_changes_root_version_1 := wasmtime.WrapFunc(store, ext_storage_changes_root_version_1)
-	ext_storage_clear_version_1 := wasmtime.WrapFunc(store, ext_storage_clear_version_1)
-	ext_storage_clear_prefix_version_1 := wasmtime.WrapFunc(store, ext_storage_clear_prefix_version_1)
-	ext_storage_commit_transaction_version_1 := wasmtime.WrapFunc(store, ext_storage_commit_transaction_version_1)
-	ext_storage_get_version_1 := wasmtime.WrapFunc(store, ext_storage_get_version_1)
-	ext_storage_next_key_version_1 := wasmtime.WrapFunc(store, ext_storage_next_key_version_1)
-	ext_storage_read_version_1 := wasmtime.WrapFunc(store, ext_storage_read_version_1)
-	ext_storage_rollback_transaction_version_1 := wasmtime.WrapFunc(store, ext_storage_rollback_transaction_version_1)
-	ext_storage_root_version_1 := wasmtime.WrapFunc(store, ext_storage_root_version_1)
-	ext_storage_set_version_1 := wasmtime.WrapFunc(store, ext_storage_set_version_1)
-	ext_storage_start_transaction_version_1 := wasmtime.WrapFunc(store, ext_storage_start_transaction_version_1)
-	ext_offchain_index_set_version_1 := wasmtime.WrapFunc(store, ext_offchain_index_set_version_1)
-
-	return []*wasmtime.Extern{
-		mem.AsExtern(),
-		ext_logging_log_version_1.AsExtern(),
-		ext_sandbox_instance_teardown_version_1.AsExtern(),
-		ext_sandbox_instantiate_version_1.AsExtern(),
-		ext_sandbox_invoke_version_1.AsExtern(),
-		ext_sandbox_memory_get_version_1.AsExtern(),
-		ext_sandbox_memory_new_version_1.AsExtern(),
-		ext_sandbox_memory_set_version_1.AsExtern(),
-		ext_sandbox_memory_teardown_version_1.AsExtern(),
-		ext_crypto_ed25519_generate_version_1.AsExtern(),
-		ext_crypto_ed25519_verify_version_1.AsExtern(),
-		ext_crypto_finish_batch_verify_version_1.AsExtern(),
-		ext_crypto_secp256k1_ecdsa_recover_compressed_version_1.AsExtern(),
-		ext_crypto_sr25519_generate_version_1.AsExtern(),
-		ext_crypto_sr25519_public_keys_version_1.AsExtern(),
-		ext_crypto_sr25519_sign_version_1.AsExtern(),
-		ext_crypto_sr25519_verify_version_2.AsExtern(),
-		ext_crypto_start_batch_verify_version_1.AsExtern(),
-		ext_trie_blake2_256_ordered_root_version_1.AsExtern(),
-		ext_misc_print_hex_version_1.AsExtern(),
-		ext_misc_print_num_version_1.AsExtern(),
-		ext_misc_print_utf8_version_1.AsExtern(),
-		ext_misc_runtime_version_version_1.AsExtern(),
-		ext_default_child_storage_clear_version_1.AsExtern(),
-		ext_default_child_storage_get_version_1.AsExtern(),
-		ext_default_child_storage_root_version_1.AsExtern(),
-		ext_default_child_storage_set_version_1.AsExtern(),
-		ext_default_child_storage_storage_kill_version_1.AsExtern(),
-		ext_allocator_free_version_1.AsExtern(),
-		ext_allocator_malloc_version_1.AsExtern(),
-		ext_hashing_blake2_128_version_1.AsExtern(),
-		ext_hashing_blake2_256_version_1.AsExtern(),
-		ext_hashing_keccak_256_version_1.AsExtern(),
-		ext_hashing_sha2_256_version_1.AsExtern(),
-		ext_hashing_twox_128_version_1.AsExtern(),
-		ext_hashing_twox_64_version_1.AsExtern(),
-		ext_offchain_is_validator_version_1.AsExtern(),
-		ext_offchain_local_storage_compare_and_set_version_1.AsExtern(),
-		ext_offchain_local_storage_get_version_1.AsExtern(),
-		ext_offchain_local_storage_set_version_1.AsExtern(),
-		ext_offchain_network_state_version_1.AsExtern(),
-		ext_offchain_random_seed_version_1.AsExtern(),
-		ext_offchain_submit_transaction_version_1.AsExtern(),
-		ext_storage_append_version_1.AsExtern(),
-		ext_storage_changes_root_version_1.AsExtern(),
-		ext_storage_clear_version_1.AsExtern(),
-		ext_storage_clear_prefix_version_1.AsExtern(),
-		ext_storage_commit_transaction_version_1.AsExtern(),
-		ext_storage_get_version_1.AsExtern(),
-		ext_storage_next_key_version_1.AsExtern(),
-		ext_storage_read_version_1.AsExtern(),
-		ext_storage_rollback_transaction_version_1.AsExtern(),
-		ext_storage_root_version_1.AsExtern(),
-		ext_storage_set_version_1.AsExtern(),
-		ext_storage_start_transaction_version_1.AsExtern(),
-		ext_offchain_index_set_version_1.AsExtern(),
+	imports := []*wasmtime.Extern{mem.AsExtern()}
+	for _, imp := range nodeRuntimeImports {
+		imports = append(imports, wasmtime.WrapFunc(store, imp.fn).AsExtern())
 	}
+	return imports
 }
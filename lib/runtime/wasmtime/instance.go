@@ -17,11 +17,14 @@
 package wasmtime
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"runtime"
-	"sync"
 
 	gssmrruntime "github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/trace"
 
 	log "github.com/ChainSafe/log15"
 	"github.com/bytecodealliance/wasmtime-go"
@@ -43,9 +46,9 @@ type Config struct {
 
 // LegacyInstance represents a v0.6 runtime go-wasmtime instance
 type LegacyInstance struct {
-	vm  *wasmtime.Instance
-	mu  sync.Mutex
-	mem *wasmtime.Memory
+	vm    *wasmtime.Instance
+	calls gssmrruntime.CallScheduler
+	mem   *wasmtime.Memory
 }
 
 // Instance represents a v0.8 runtime go-wasmtime instance
@@ -55,24 +58,41 @@ type Instance struct {
 
 // NewLegacyInstance instantiates a runtime from the given wasm bytecode
 func NewLegacyInstance(code []byte, cfg *Config) (*LegacyInstance, error) {
+	// the runtime gets the heap pages on top of the initial pages of the memory it declares
+	pages, err := cfg.GetMemoryPages(code)
+	if err != nil {
+		return nil, err
+	}
+
 	engine := wasmtime.NewEngine()
 	module, err := wasmtime.NewModule(engine, code)
 	if err != nil {
 		return nil, err
 	}
 
-	return newLegacyInstanceFromModule(module, engine, cfg)
+	return newLegacyInstanceFromModule(module, engine, pages, cfg)
 }
 
 // NewLegacyInstanceFromFile instantiates a runtime from a .wasm file
 func NewLegacyInstanceFromFile(fp string, cfg *Config) (*LegacyInstance, error) {
-	engine := wasmtime.NewEngine()
-	module, err := wasmtime.NewModuleFromFile(engine, fp)
+	code, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLegacyInstance(code, cfg)
+}
+
+// NewInstance instantiates a runtime from the given wasm bytecode
+func NewInstance(code []byte, cfg *Config) (*Instance, error) {
+	inst, err := NewLegacyInstance(code, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return newLegacyInstanceFromModule(module, engine, cfg)
+	return &Instance{
+		inst: inst,
+	}, nil
 }
 
 // NewInstanceFromFile instantiates a runtime from a .wasm file
@@ -87,7 +107,8 @@ func NewInstanceFromFile(fp string, cfg *Config) (*Instance, error) {
 	}, nil
 }
 
-func newLegacyInstanceFromModule(module *wasmtime.Module, engine *wasmtime.Engine, cfg *Config) (*LegacyInstance, error) {
+func newLegacyInstanceFromModule(module *wasmtime.Module, engine *wasmtime.Engine, pages uint32,
+	cfg *Config) (*LegacyInstance, error) {
 	// if cfg.LogLvl set to < 0, then don't change package log level
 	if cfg.LogLvl >= 0 {
 		h := log.StreamHandler(os.Stdout, log.TerminalFormat())
@@ -96,16 +117,24 @@ func newLegacyInstanceFromModule(module *wasmtime.Module, engine *wasmtime.Engin
 	}
 
 	store := wasmtime.NewStore(engine)
-	instance, err := wasmtime.NewInstance(store, module, cfg.Imports(store))
+	imports := cfg.Imports(store)
+	instance, err := wasmtime.NewInstance(store, module, imports)
 	if err != nil {
 		return nil, err
 	}
 
+	// use the runtime's own memory if it exports one, otherwise the imported memory
 	var mem *wasmtime.Memory
-	if m := cfg.Imports(store)[0].Memory(); m != nil {
-		mem = m
+	if exp := instance.GetExport("memory"); exp != nil && exp.Memory() != nil {
+		mem = exp.Memory()
+	} else if len(imports) > 0 && imports[0].Memory() != nil {
+		mem = imports[0].Memory()
 	} else {
-		mem = instance.GetExport("memory").Memory()
+		return nil, fmt.Errorf("runtime has no memory")
+	}
+
+	if err = growMemory(mem, pages, cfg.GetMaxMemoryPages()); err != nil {
+		return nil, err
 	}
 
 	allocator := gssmrruntime.NewAllocator(Memory{mem}, 0)
@@ -117,6 +146,7 @@ func newLegacyInstanceFromModule(module *wasmtime.Module, engine *wasmtime.Engin
 		Validator:   cfg.Role == byte(4),
 		NodeStorage: cfg.NodeStorage,
 		Network:     cfg.Network,
+		HostStats:   cfg.HostStats,
 	}
 
 	return &LegacyInstance{
@@ -125,6 +155,26 @@ func newLegacyInstanceFromModule(module *wasmtime.Module, engine *wasmtime.Engin
 	}, nil
 }
 
+// growMemory grows the runtime's memory to at least the given number of pages, returning an
+// error if the memory is already larger than the maximum number of pages
+func growMemory(mem *wasmtime.Memory, target, maxPages uint32) error {
+	pages := uint32(mem.DataSize() / gssmrruntime.PageSize)
+	if pages > maxPages {
+		return gssmrruntime.ErrHeapPagesExceedLimit
+	}
+
+	if pages >= target {
+		return nil
+	}
+
+	logger.Debug("growing runtime memory", "pages", pages, "target", target)
+	if !mem.Grow(uint(target - pages)) {
+		return fmt.Errorf("failed to grow runtime memory from %d to %d pages", pages, target)
+	}
+
+	return nil
+}
+
 // Legacy returns the instance as a LegacyInstance
 func (in *Instance) Legacy() *LegacyInstance {
 	return in.inst
@@ -184,8 +234,11 @@ func (in *LegacyInstance) Exec(function string, data []byte) ([]byte, error) {
 }
 
 func (in *LegacyInstance) exec(function string, data []byte) ([]byte, error) {
-	in.mu.Lock()
-	defer in.mu.Unlock()
+	_, span := trace.StartSpan(context.Background(), "runtime.exec", trace.Attribute{Key: "function", Value: function})
+	defer span.End()
+
+	in.calls.Lock(gssmrruntime.CallPriority(function))
+	defer in.calls.Unlock()
 
 	ptr, err := ctx.Allocator.Allocate(uint32(len(data)))
 	if err != nil {
@@ -196,7 +249,12 @@ func (in *LegacyInstance) exec(function string, data []byte) ([]byte, error) {
 	memdata := in.mem.UnsafeData()
 	copy(memdata[ptr:ptr+uint32(len(data))], data)
 
-	run := in.vm.GetExport(function).Func()
+	export := in.vm.GetExport(function)
+	if export == nil {
+		return nil, fmt.Errorf("%w: could not find exported function %s", gssmrruntime.ErrMethodUnavailable, function)
+	}
+
+	run := export.Func()
 	resi, err := run.Call(int32(ptr), int32(len(data)))
 	if err != nil {
 		return nil, err
@@ -33,11 +33,13 @@ import (
 
 func ext_print_num(data int64) {
 	logger.Trace("[ext_print_num] executing...")
+	defer observeHostCall("ext_print_num")()
 	logger.Info("[ext_print_num]", "message", fmt.Sprintf("%d", data))
 }
 
 func ext_print_utf8(c *wasmtime.Caller, data, len int32) {
 	logger.Trace("[ext_print_utf8] executing...")
+	defer observeHostCall("ext_print_utf8")()
 	m := c.GetExport("memory").Memory()
 	mem := m.UnsafeData()
 	logger.Info("[ext_print_utf8]", "message", fmt.Sprintf("%s", mem[data:data+len]))
@@ -46,6 +48,7 @@ func ext_print_utf8(c *wasmtime.Caller, data, len int32) {
 
 func ext_malloc(c *wasmtime.Caller, size int32) int32 {
 	logger.Trace("[ext_malloc] executing...")
+	defer observeHostCall("ext_malloc")()
 	res, err := ctx.Allocator.Allocate(uint32(size))
 	if err != nil {
 		logger.Error("[ext_malloc]", "Error:", err)
@@ -55,6 +58,7 @@ func ext_malloc(c *wasmtime.Caller, size int32) int32 {
 
 func ext_free(c *wasmtime.Caller, addr int32) {
 	logger.Trace("[ext_free] executing...")
+	defer observeHostCall("ext_free")()
 	err := ctx.Allocator.Deallocate(uint32(addr))
 	if err != nil {
 		logger.Error("[ext_free]", "error", err)
@@ -63,6 +67,7 @@ func ext_free(c *wasmtime.Caller, addr int32) {
 
 func ext_twox_128(c *wasmtime.Caller, data, len, out int32) {
 	logger.Trace("[ext_twox_128] executing...")
+	defer observeHostCall("ext_twox_128")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 	logger.Trace("[ext_twox_128]", "hashing", fmt.Sprintf("%s", memory[data:data+len]))
@@ -78,6 +83,7 @@ func ext_twox_128(c *wasmtime.Caller, data, len, out int32) {
 
 func ext_get_storage_into(c *wasmtime.Caller, keyData, keyLen, valueData, valueLen, valueOffset int32) int32 {
 	logger.Trace("[ext_get_storage_into] executing...")
+	defer observeHostCall("ext_get_storage_into")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -105,6 +111,7 @@ func ext_get_storage_into(c *wasmtime.Caller, keyData, keyLen, valueData, valueL
 
 func ext_set_storage(c *wasmtime.Caller, keyData, keyLen, valueData, valueLen int32) {
 	logger.Trace("[ext_set_storage] executing...")
+	defer observeHostCall("ext_set_storage")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -120,6 +127,7 @@ func ext_set_storage(c *wasmtime.Caller, keyData, keyLen, valueData, valueLen in
 
 func ext_storage_root(c *wasmtime.Caller, resultPtr int32) {
 	logger.Trace("[ext_storage_root] executing...")
+	defer observeHostCall("ext_storage_root")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -135,6 +143,7 @@ func ext_storage_root(c *wasmtime.Caller, resultPtr int32) {
 
 func ext_get_allocated_storage(c *wasmtime.Caller, keyData, keyLen, writtenOut int32) int32 {
 	logger.Trace("[ext_get_allocated_storage] executing...")
+	defer observeHostCall("ext_get_allocated_storage")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -183,6 +192,7 @@ func ext_get_allocated_storage(c *wasmtime.Caller, keyData, keyLen, writtenOut i
 
 func ext_clear_storage(c *wasmtime.Caller, keyData, keyLen int32) {
 	logger.Trace("[ext_clear_storage] executing...")
+	defer observeHostCall("ext_clear_storage")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -197,6 +207,7 @@ func ext_clear_storage(c *wasmtime.Caller, keyData, keyLen int32) {
 
 func ext_clear_prefix(c *wasmtime.Caller, prefixData, prefixLen int32) {
 	logger.Trace("[ext_clear_prefix] executing...")
+	defer observeHostCall("ext_clear_prefix")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -216,6 +227,7 @@ func ext_clear_prefix(c *wasmtime.Caller, prefixData, prefixLen int32) {
 
 func ext_blake2_256(c *wasmtime.Caller, data, length, out int32) {
 	logger.Trace("[ext_blake2_256] executing...")
+	defer observeHostCall("ext_blake2_256")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -231,6 +243,7 @@ func ext_blake2_256(c *wasmtime.Caller, data, length, out int32) {
 
 func ext_blake2_256_enumerated_trie_root(c *wasmtime.Caller, valuesData, lensData, lensLen, result int32) {
 	logger.Trace("[ext_blake2_256_enumerated_trie_root] executing...")
+	defer observeHostCall("ext_blake2_256_enumerated_trie_root")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -269,140 +282,170 @@ func ext_blake2_256_enumerated_trie_root(c *wasmtime.Caller, valuesData, lensDat
 
 func ext_print_hex(c *wasmtime.Caller, offset, size int32) {
 	logger.Trace("[ext_print_hex] executing...")
+	defer observeHostCall("ext_print_hex")()
 }
 
 func ext_storage_changes_root(c *wasmtime.Caller, a, b, d int32) int32 {
 	logger.Trace("[ext_storage_changes_root] executing...")
+	defer observeHostCall("ext_storage_changes_root")()
 	return 0
 }
 
 func ext_set_child_storage(c *wasmtime.Caller, storageKeyData, storageKeyLen, keyData, keyLen, valueData, valueLen int32) {
 	logger.Trace("[ext_set_child_storage] executing...")
+	defer observeHostCall("ext_set_child_storage")()
 }
 
 func ext_twox_64(c *wasmtime.Caller, data, length, out int32) {
 	logger.Trace("[ext_twox_64] executing...")
+	defer observeHostCall("ext_twox_64")()
 }
 
 func ext_sr25519_generate(c *wasmtime.Caller, idData, seed, seedLen, out int32) {
 	logger.Trace("[ext_sr25519_generate] executing...")
+	defer observeHostCall("ext_sr25519_generate")()
 }
 
 func ext_sr25519_public_keys(c *wasmtime.Caller, idData, resultLen int32) int32 {
 	logger.Trace("[ext_sr25519_public_keys] executing...")
+	defer observeHostCall("ext_sr25519_public_keys")()
 	return 0
 }
 
 func ext_sr25519_sign(c *wasmtime.Caller, idData, pubkeyData, msgData, msgLen, out int32) int32 {
 	logger.Trace("[ext_sr25519_sign] executing...")
+	defer observeHostCall("ext_sr25519_sign")()
 	return 0
 }
 
 func ext_sr25519_verify(c *wasmtime.Caller, msgData, msgLen, sigData, pubkeyData int32) int32 {
 	logger.Trace("[ext_sr25519_verify] executing...")
+	defer observeHostCall("ext_sr25519_verify")()
 	return 0
 }
 
 func ext_ed25519_generate(c *wasmtime.Caller, idData, seed, seedLen, out int32) {
 	logger.Trace("[ext_ed25519_generate] executing...")
+	defer observeHostCall("ext_ed25519_generate")()
 }
 
 func ext_ed25519_verify(c *wasmtime.Caller, msgData, msgLen, sigData, pubkeyData int32) int32 {
 	logger.Trace("[ext_ed25519_verify] executing...")
+	defer observeHostCall("ext_ed25519_verify")()
 	return 0
 }
 
 func ext_is_validator(c *wasmtime.Caller) int32 {
 	logger.Trace("[ext_is_validator] executing...")
+	defer observeHostCall("ext_is_validator")()
 	return 0
 }
 
 func ext_local_storage_get(c *wasmtime.Caller, kind, key, keyLen, valueLen int32) int32 {
 	logger.Trace("[ext_local_storage_get] executing...")
+	defer observeHostCall("ext_local_storage_get")()
 	return 0
 }
 
 func ext_local_storage_compare_and_set(c *wasmtime.Caller, kind, key, keyLen, oldValue, oldValueLen, newValue, newValueLen int32) int32 {
 	logger.Trace("[ext_local_storage_compare_and_set] executing...")
+	defer observeHostCall("ext_local_storage_compare_and_set")()
 	return 0
 }
 
 func ext_network_state(c *wasmtime.Caller, writtenOut int32) int32 {
 	logger.Trace("[ext_network_state] executing...")
+	defer observeHostCall("ext_network_state")()
 	return 0
 }
 
 func ext_submit_transaction(c *wasmtime.Caller, data, len int32) int32 {
 	logger.Trace("[ext_submit_transaction] executing...")
+	defer observeHostCall("ext_submit_transaction")()
 	return 0
 }
 
 func ext_local_storage_set(c *wasmtime.Caller, kind, key, keyLen, value, valueLen int32) {
 	logger.Trace("[ext_local_storage_set] executing...")
+	defer observeHostCall("ext_local_storage_set")()
 }
 
 func ext_kill_child_storage(c *wasmtime.Caller, a, b int32) {
 	logger.Trace("[ext_kill_child_storage] executing...")
+	defer observeHostCall("ext_kill_child_storage")()
 }
 
 func ext_sandbox_memory_new(c *wasmtime.Caller, a, b int32) int32 {
 	logger.Trace("[ext_sandbox_memory_new] executing...")
+	defer observeHostCall("ext_sandbox_memory_new")()
 	return 0
 }
 
 func ext_sandbox_memory_teardown(c *wasmtime.Caller, a int32) {
 	logger.Trace("[ext_sandbox_memory_teardown] executing...")
+	defer observeHostCall("ext_sandbox_memory_teardown")()
 }
 
 func ext_sandbox_instantiate(c *wasmtime.Caller, a, b, g, d, e, f int32) int32 {
 	logger.Trace("[ext_sandbox_instantiate] executing...")
+	defer observeHostCall("ext_sandbox_instantiate")()
 	return 0
 }
 
 func ext_sandbox_invoke(c *wasmtime.Caller, a, b, i, d, e, f, g, h int32) int32 {
 	logger.Trace("[ext_sandbox_invoke] executing...")
+	defer observeHostCall("ext_sandbox_invoke")()
 	return 0
 }
 
 func ext_sandbox_instance_teardown(c *wasmtime.Caller, a int32) {
 	logger.Trace("[ext_sandbox_instance_teardown] executing...")
+	defer observeHostCall("ext_sandbox_instance_teardown")()
 }
 
 func ext_get_allocated_child_storage(c *wasmtime.Caller, a, b, i, d, e int32) int32 {
 	logger.Trace("[ext_get_allocated_child_storage] executing...")
+	defer observeHostCall("ext_get_allocated_child_storage")()
 	return 0
 }
 
 func ext_child_storage_root(c *wasmtime.Caller, a, b, i int32) int32 {
 	logger.Trace("[ext_child_storage_root] executing...")
+	defer observeHostCall("ext_child_storage_root")()
 	return 0
 }
 
 func ext_clear_child_storage(c *wasmtime.Caller, a, b, d, z int32) {
 	logger.Trace("[ext_clear_child_storage] executing...")
+	defer observeHostCall("ext_clear_child_storage")()
 }
 
 func ext_secp256k1_ecdsa_recover_compressed(c *wasmtime.Caller, a, b, i int32) int32 {
 	logger.Trace("[ext_secp256k1_ecdsa_recover_compressed] executing...")
+	defer observeHostCall("ext_secp256k1_ecdsa_recover_compressed")()
 	return 0
 }
 
 func ext_sandbox_memory_get(c *wasmtime.Caller, a, b, d, z int32) int32 {
 	logger.Trace("[ext_sandbox_memory_get] executing...")
+	defer observeHostCall("ext_sandbox_memory_get")()
 	return 0
 }
 
 func ext_sandbox_memory_set(c *wasmtime.Caller, a, b, d, z int32) int32 {
 	logger.Trace("[ext_sandbox_memory_set] executing...")
+	defer observeHostCall("ext_sandbox_memory_set")()
 	return 0
 }
 
 func ext_log(c *wasmtime.Caller, a, b, d, e, z int32) {
 	logger.Trace("[ext_log] executing...")
+	defer observeHostCall("ext_log")()
 }
 
 func ext_twox_256(c *wasmtime.Caller, data, len, out int32) {
 	logger.Trace("[ext_twox_256] executing...")
+	defer observeHostCall("ext_twox_256")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 	logger.Trace("[ext_twox_256] hashing...", "value", fmt.Sprintf("%s", memory[data:data+len]))
@@ -419,6 +462,7 @@ func ext_twox_256(c *wasmtime.Caller, data, len, out int32) {
 
 func ext_blake2_128(c *wasmtime.Caller, data, length, out int32) {
 	logger.Trace("[ext_blake2_128] executing...")
+	defer observeHostCall("ext_blake2_128")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -435,6 +479,7 @@ func ext_blake2_128(c *wasmtime.Caller, data, length, out int32) {
 
 func ext_keccak_256(c *wasmtime.Caller, data, length, out int32) {
 	logger.Trace("[ext_keccak_256] executing...")
+	defer observeHostCall("ext_keccak_256")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -451,23 +496,27 @@ func ext_keccak_256(c *wasmtime.Caller, data, length, out int32) {
 
 func ext_exists_storage(c *wasmtime.Caller, a, b int32) int32 {
 	logger.Trace("[ext_exists_storage] executing...")
+	defer observeHostCall("ext_exists_storage")()
 	logger.Warn("[ext_exists_storage] not yet implemented")
 	return 0
 }
 
 func ext_exists_child_storage(c *wasmtime.Caller, a, b, e, d int32) int32 {
 	logger.Trace("[ext_exists_child_storage] executing...")
+	defer observeHostCall("ext_exists_child_storage")()
 	logger.Warn("[ext_exists_child_storage] not yet implemented")
 	return 0
 }
 
 func ext_clear_child_prefix(c *wasmtime.Caller, a, b, e, d int32) {
 	logger.Trace("[ext_clear_child_prefix] executing...")
+	defer observeHostCall("ext_clear_child_prefix")()
 	logger.Warn("[ext_clear_child_prefix] not yet implemented")
 }
 
 func ext_get_child_storage_into(c *wasmtime.Caller, storageKeyData, storageKeyLen, keyData, keyLen, valueData, valueLen, valueOffset int32) int32 {
 	logger.Trace("[ext_get_child_storage_into] executing...")
+	defer observeHostCall("ext_get_child_storage_into")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -487,6 +536,7 @@ func ext_get_child_storage_into(c *wasmtime.Caller, storageKeyData, storageKeyLe
 
 func ext_ed25519_public_keys(c *wasmtime.Caller, idData, resultLen int32) int32 {
 	logger.Trace("[ext_ed25519_public_keys] executing...")
+	defer observeHostCall("ext_ed25519_public_keys")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
@@ -511,6 +561,7 @@ func ext_ed25519_public_keys(c *wasmtime.Caller, idData, resultLen int32) int32
 
 func ext_ed25519_sign(c *wasmtime.Caller, idData, pubkeyData, msgData, msgLen, out int32) int32 {
 	logger.Trace("[ext_ed25519_sign] executing...")
+	defer observeHostCall("ext_ed25519_sign")()
 	m := c.GetExport("memory").Memory()
 	memory := m.UnsafeData()
 
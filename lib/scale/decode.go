@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
 	"runtime"
@@ -30,6 +31,12 @@ import (
 	"github.com/ChainSafe/gossamer/lib/common"
 )
 
+// errInvalidLength is returned when a length prefix is larger than the input that remains to be decoded
+var errInvalidLength = errors.New("could not decode invalid length: exceeds remaining input")
+
+// maxLength is the largest length prefix accepted when the size of the remaining input is unknown
+const maxLength = math.MaxInt32
+
 // withCustom is true is custom encoding is supported. set to true by default.
 // only should be set to false for testing.
 var withCustom = true
@@ -244,6 +251,25 @@ func (sd *Decoder) DecodeUnsignedInteger() (o uint64, err error) {
 	return o, err
 }
 
+// decodeLength decodes the compact length prefix of a byte array or sequence. Every element takes at least one byte,
+// so a length larger than the remaining input is rejected before anything is allocated for it.
+func (sd *Decoder) decodeLength() (int, error) {
+	length, err := sd.DecodeUnsignedInteger()
+	if err != nil {
+		return 0, err
+	}
+
+	if length > maxLength {
+		return 0, errInvalidLength
+	}
+
+	if r, ok := sd.Reader.(interface{ Len() int }); ok && length > uint64(r.Len()) {
+		return 0, errInvalidLength
+	}
+
+	return int(length), nil
+}
+
 // DecodeBigInt decodes a SCALE encoded byte array into a *big.Int
 // Works for all integers, including ints > 2**64
 func (sd *Decoder) DecodeBigInt() (output *big.Int, err error) {
@@ -284,7 +310,7 @@ func (sd *Decoder) DecodeBigInt() (output *big.Int, err error) {
 // if the encoding is valid, it then returns the decoded byte array, the total number of input bytes decoded, and nil
 // otherwise, it returns nil, 0, and error
 func (sd *Decoder) DecodeByteArray() (o []byte, err error) {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
@@ -386,7 +412,7 @@ func (sd *Decoder) DecodeSlice(t interface{}) (interface{}, error) {
 	var err error
 	var o interface{}
 
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
@@ -395,9 +421,9 @@ func (sd *Decoder) DecodeSlice(t interface{}) (interface{}, error) {
 		return t, nil
 	}
 
-	sl := reflect.MakeSlice(v.Type(), int(length), int(length))
+	sl := reflect.MakeSlice(v.Type(), length, length)
 
-	for i := 0; i < int(length); i++ {
+	for i := 0; i < length; i++ {
 		arrayValue := sl.Index(i)
 
 		switch ptr := arrayValue.Addr().Interface().(type) {
@@ -574,7 +600,7 @@ func (sd *Decoder) DecodeTuple(t interface{}) (interface{}, error) { //nolint
 
 // DecodeIntArray decodes a byte array to an array of ints
 func (sd *Decoder) DecodeIntArray() ([]int, error) {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
@@ -583,28 +609,26 @@ func (sd *Decoder) DecodeIntArray() ([]int, error) {
 	for i := range o {
 		var t int64
 		t, err = sd.DecodeInteger()
-		o[i] = int(t)
 		if err != nil {
-			break
+			return nil, err
 		}
+		o[i] = int(t)
 	}
 	return o, nil
 }
 
 // DecodeBigIntArray decodes a byte array to an array of *big.Ints
 func (sd *Decoder) DecodeBigIntArray() ([]*big.Int, error) {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
 
 	o := make([]*big.Int, length)
 	for i := range o {
-		var t *big.Int
-		t, err = sd.DecodeBigInt()
-		o[i] = t
+		o[i], err = sd.DecodeBigInt()
 		if err != nil {
-			break
+			return nil, err
 		}
 	}
 	return o, nil
@@ -612,7 +636,7 @@ func (sd *Decoder) DecodeBigIntArray() ([]*big.Int, error) {
 
 // DecodeBoolArray decodes a byte array to an array of bools
 func (sd *Decoder) DecodeBoolArray() ([]bool, error) {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
@@ -621,7 +645,7 @@ func (sd *Decoder) DecodeBoolArray() ([]bool, error) {
 	for i := range o {
 		o[i], err = sd.DecodeBool()
 		if err != nil {
-			break
+			return nil, err
 		}
 	}
 	return o, nil
@@ -629,13 +653,13 @@ func (sd *Decoder) DecodeBoolArray() ([]bool, error) {
 
 // DecodeStringArray will decode to string array
 func (sd *Decoder) DecodeStringArray() ([]string, error) {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return nil, err
 	}
 	s := make([]string, length)
 
-	for i := 0; i < int(length); i++ {
+	for i := 0; i < length; i++ {
 		o, err := sd.DecodeByteArray()
 		if err != nil {
 			return nil, err
@@ -259,7 +259,7 @@ func (sd *Decoder) DecodePtrBool(output interface{}) error {
 
 // DecodePtrIntArray decodes a byte array to an array of ints
 func (sd *Decoder) DecodePtrIntArray(t interface{}) error {
-	length, err := sd.DecodeInteger()
+	length, err := sd.decodeLength()
 	if err != nil {
 		return err
 	}
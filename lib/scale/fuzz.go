@@ -0,0 +1,104 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package scale
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// fuzzTuple is a struct type used to fuzz tuple decoding
+type fuzzTuple struct {
+	Foo []byte
+	Bar uint32
+	Baz bool
+	Qux int64
+}
+
+// fuzzTypes returns a new instance of every type supported by the codec, which fuzzed inputs are decoded into
+var fuzzTypes = []func() interface{}{
+	func() interface{} { return int8(0) },
+	func() interface{} { return uint8(0) },
+	func() interface{} { return int16(0) },
+	func() interface{} { return uint16(0) },
+	func() interface{} { return int32(0) },
+	func() interface{} { return uint32(0) },
+	func() interface{} { return int64(0) },
+	func() interface{} { return uint64(0) },
+	func() interface{} { return int(0) },
+	func() interface{} { return uint(0) },
+	func() interface{} { return big.NewInt(0) },
+	func() interface{} { return []byte{} },
+	func() interface{} { return "" },
+	func() interface{} { return false },
+	func() interface{} { return []int{} },
+	func() interface{} { return []bool{} },
+	func() interface{} { return []*big.Int{} },
+	func() interface{} { return [][]byte{} },
+	func() interface{} { return [][32]byte{} },
+	func() interface{} { return common.Hash{} },
+	func() interface{} { return &fuzzTuple{} },
+}
+
+// fuzzDecode decodes the input into every supported type. It returns the number of types the input was decoded
+// into successfully; malformed input must result in an error, never a panic.
+func fuzzDecode(data []byte) int {
+	decoded := 0
+	for _, newType := range fuzzTypes {
+		if _, err := Decode(data, newType()); err == nil {
+			decoded++
+		}
+	}
+
+	return decoded
+}
+
+// fuzzRoundTrip checks that for every type the input decodes into, re-encoding the decoded value and decoding it
+// again results in the same value, ie. encode(decode(encode(decode(x)))) == encode(decode(x)).
+// The encodings are compared rather than the values, since inputs may use non-canonical encodings.
+func fuzzRoundTrip(data []byte) error {
+	for _, newType := range fuzzTypes {
+		v, err := Decode(data, newType())
+		if err != nil {
+			continue
+		}
+
+		enc, err := Encode(v)
+		if err != nil {
+			return fmt.Errorf("could not encode decoded %T: %w", v, err)
+		}
+
+		res, err := Decode(enc, newType())
+		if err != nil {
+			return fmt.Errorf("could not decode re-encoded %T 0x%x: %w", v, enc, err)
+		}
+
+		resEnc, err := Encode(res)
+		if err != nil {
+			return fmt.Errorf("could not encode re-decoded %T: %w", res, err)
+		}
+
+		if !bytes.Equal(enc, resEnc) {
+			return fmt.Errorf("round trip of %T failed: 0x%x != 0x%x", v, enc, resEnc)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package scale
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzCorpus(t *testing.T) {
+	files, err := ioutil.ReadDir(filepath.Join("testdata", "fuzz", "corpus"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		var data []byte
+		data, err = ioutil.ReadFile(filepath.Join("testdata", "fuzz", "corpus", file.Name()))
+		require.NoError(t, err)
+
+		require.NotPanics(t, func() { fuzzDecode(data) }, file.Name())
+		require.NoError(t, fuzzRoundTrip(data), file.Name())
+	}
+}
+
+func TestFuzzRandomInput(t *testing.T) {
+	r := rand.New(rand.NewSource(1)) //nolint
+
+	for i := 0; i < 10000; i++ {
+		data := make([]byte, r.Intn(128))
+		_, _ = r.Read(data)
+
+		require.NotPanics(t, func() { fuzzDecode(data) }, "0x%x", data)
+		require.NoError(t, fuzzRoundTrip(data), "0x%x", data)
+	}
+}
+
+func TestDecode_InvalidLength(t *testing.T) {
+	// length prefix of 2^22 with only one byte following
+	_, err := Decode([]byte{0x02, 0x00, 0x00, 0x01, 0xff}, []byte{})
+	require.Equal(t, errInvalidLength, err)
+
+	// length prefix larger than math.MaxInt32
+	_, err = Decode([]byte{0x07, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, []int{})
+	require.Equal(t, errInvalidLength, err)
+
+	_, err = Decode([]byte{0x08, 0x01}, []bool{})
+	require.Error(t, err)
+}
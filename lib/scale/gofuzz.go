@@ -0,0 +1,44 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build gofuzz
+// +build gofuzz
+
+package scale
+
+// The fuzz targets are built with go-fuzz (https://github.com/dvyukov/go-fuzz), using the corpus in
+// testdata/fuzz/corpus, which is seeded with encoded network messages:
+//
+//	go-fuzz-build -func FuzzDecode github.com/ChainSafe/gossamer/lib/scale
+//	go-fuzz -bin scale-fuzz.zip -workdir lib/scale/testdata/fuzz
+
+// FuzzDecode checks that decoding arbitrary input into any supported type never panics
+func FuzzDecode(data []byte) int {
+	if fuzzDecode(data) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// FuzzRoundTrip checks that every value decoded from arbitrary input survives an encode and decode round trip
+func FuzzRoundTrip(data []byte) int {
+	if err := fuzzRoundTrip(data); err != nil {
+		panic(err)
+	}
+
+	return 0
+}
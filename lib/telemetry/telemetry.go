@@ -0,0 +1,244 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package telemetry
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+	log "github.com/ChainSafe/log15"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultInterval is the time between the status messages sent to the telemetry servers, and between attempts to
+// reconnect to a server
+const DefaultInterval = 5 * time.Second
+
+// levelInfo is the verbosity of the connection and status messages, a message is only sent to the servers whose
+// verbosity is at least the message's
+const levelInfo = 0
+
+var logger = log.New("pkg", "telemetry")
+
+// BlockState is the interface used to report the best and finalized blocks
+type BlockState interface {
+	BestBlockHeader() (*types.Header, error)
+	GetFinalizedHeader(round, setID uint64) (*types.Header, error)
+}
+
+// Network is the interface used to report the node's peer ID and number of peers
+type Network interface {
+	Health() common.Health
+	NetworkState() common.NetworkState
+}
+
+// Info identifies the node to the telemetry servers
+type Info struct {
+	Name           string
+	Chain          string
+	Implementation string
+	Version        string
+	GenesisHash    common.Hash
+	Authority      bool
+}
+
+// Service connects to the telemetry servers and periodically sends them the status of the node
+type Service struct {
+	info       *Info
+	endpoints  []*genesis.TelemetryEndpoint
+	blockState BlockState
+	network    Network // nil if the network service is disabled
+	interval   time.Duration
+	startup    time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService returns a Service that reports to the given endpoints every interval, or every DefaultInterval if
+// interval is zero
+func NewService(info *Info, endpoints []*genesis.TelemetryEndpoint, bs BlockState, net Network,
+	interval time.Duration) *Service {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		info:       info,
+		endpoints:  endpoints,
+		blockState: bs,
+		network:    net,
+		interval:   interval,
+		startup:    time.Now(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start starts reporting to the telemetry servers
+func (s *Service) Start() error {
+	urls := make([]string, len(s.endpoints))
+	for i, e := range s.endpoints {
+		url, err := e.URL()
+		if err != nil {
+			return err
+		}
+		urls[i] = url
+	}
+
+	for i, e := range s.endpoints {
+		logger.Info("reporting to telemetry server", "url", urls[i], "verbosity", e.Verbosity)
+
+		s.wg.Add(1)
+		go s.run(urls[i], e.Verbosity)
+	}
+
+	return nil
+}
+
+// Stop closes the connections to the telemetry servers
+func (s *Service) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// run reports to the server at url until the service is stopped, reconnecting if the connection is lost
+func (s *Service) run(url string, verbosity uint8) {
+	defer s.wg.Done()
+
+	for {
+		err := s.report(url, verbosity)
+		if err != nil {
+			logger.Debug("lost connection to telemetry server", "url", url, "error", err)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *Service) report(url string, verbosity uint8) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(s.ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint
+
+	// unblock writes once the service is stopped
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	err = send(conn, verbosity, levelInfo, s.connectedMessage())
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		msg, err := s.intervalMessage()
+		if err != nil {
+			logger.Debug("failed to get node status", "error", err)
+		} else {
+			err = send(conn, verbosity, levelInfo, msg)
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func send(conn *websocket.Conn, verbosity, level uint8, msg map[string]interface{}) error {
+	if level > verbosity {
+		return nil
+	}
+
+	msg["level"] = "INFO"
+	msg["ts"] = time.Now().Format(time.RFC3339Nano)
+	return conn.WriteJSON(msg)
+}
+
+func (s *Service) connectedMessage() map[string]interface{} {
+	msg := map[string]interface{}{
+		"msg":            "system.connected",
+		"name":           s.info.Name,
+		"chain":          s.info.Chain,
+		"implementation": s.info.Implementation,
+		"version":        s.info.Version,
+		"config":         "",
+		"authority":      s.info.Authority,
+		"genesis_hash":   s.info.GenesisHash.String(),
+		"startup_time":   strconv.FormatInt(s.startup.UnixNano()/int64(time.Millisecond), 10),
+	}
+
+	if s.network != nil {
+		msg["network_id"] = s.network.NetworkState().PeerID
+	}
+
+	return msg
+}
+
+func (s *Service) intervalMessage() (map[string]interface{}, error) {
+	best, err := s.blockState.BestBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	finalized, err := s.blockState.GetFinalizedHeader(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := 0
+	if s.network != nil {
+		peers = s.network.Health().Peers
+	}
+
+	return map[string]interface{}{
+		"msg":              "system.interval",
+		"best":             best.Hash().String(),
+		"height":           best.Number.Uint64(),
+		"finalized_hash":   finalized.Hash().String(),
+		"finalized_height": finalized.Number.Uint64(),
+		"peers":            peers,
+	}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package telemetry
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBlockState struct {
+	best, finalized *types.Header
+}
+
+func (m *mockBlockState) BestBlockHeader() (*types.Header, error) {
+	return m.best, nil
+}
+
+func (m *mockBlockState) GetFinalizedHeader(_, _ uint64) (*types.Header, error) {
+	return m.finalized, nil
+}
+
+type mockNetwork struct{}
+
+func (mockNetwork) Health() common.Health {
+	return common.Health{Peers: 3}
+}
+
+func (mockNetwork) NetworkState() common.NetworkState {
+	return common.NetworkState{PeerID: "QmPeer"}
+}
+
+func newTestServer(t *testing.T) (string, <-chan map[string]interface{}) {
+	msgs := make(chan map[string]interface{}, 16)
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close() //nolint
+
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			msgs <- msg
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http"), msgs
+}
+
+func receive(t *testing.T, msgs <-chan map[string]interface{}) map[string]interface{} {
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for telemetry message")
+	}
+	return nil
+}
+
+func TestService(t *testing.T) {
+	url, msgs := newTestServer(t)
+
+	best := &types.Header{Number: big.NewInt(7), Digest: types.NewEmptyDigest()}
+	finalized := &types.Header{Number: big.NewInt(5), Digest: types.NewEmptyDigest()}
+	info := &Info{
+		Name:           "alice",
+		Chain:          "gssmr",
+		Implementation: "gossamer",
+		Version:        "0.3.0",
+		GenesisHash:    common.Hash{1},
+		Authority:      true,
+	}
+
+	s := NewService(info, []*genesis.TelemetryEndpoint{{Address: url}}, &mockBlockState{best, finalized},
+		mockNetwork{}, 10*time.Millisecond)
+	require.NoError(t, s.Start())
+	defer s.Stop() //nolint
+
+	msg := receive(t, msgs)
+	require.Equal(t, "system.connected", msg["msg"])
+	require.Equal(t, "alice", msg["name"])
+	require.Equal(t, "gssmr", msg["chain"])
+	require.Equal(t, "gossamer", msg["implementation"])
+	require.Equal(t, true, msg["authority"])
+	require.Equal(t, common.Hash{1}.String(), msg["genesis_hash"])
+	require.Equal(t, "QmPeer", msg["network_id"])
+	require.Equal(t, "INFO", msg["level"])
+
+	msg = receive(t, msgs)
+	require.Equal(t, "system.interval", msg["msg"])
+	require.Equal(t, best.Hash().String(), msg["best"])
+	require.Equal(t, float64(7), msg["height"])
+	require.Equal(t, finalized.Hash().String(), msg["finalized_hash"])
+	require.Equal(t, float64(5), msg["finalized_height"])
+	require.Equal(t, float64(3), msg["peers"])
+}
+
+func TestService_Reconnect(t *testing.T) {
+	url, msgs := newTestServer(t)
+
+	// the first endpoint is unreachable, it must not stop the service reporting to the second one
+	endpoints := []*genesis.TelemetryEndpoint{{Address: "ws://127.0.0.1:1"}, {Address: url}}
+	s := NewService(&Info{}, endpoints, &mockBlockState{
+		best:      &types.Header{Number: big.NewInt(0), Digest: types.NewEmptyDigest()},
+		finalized: &types.Header{Number: big.NewInt(0), Digest: types.NewEmptyDigest()},
+	}, nil, 10*time.Millisecond)
+	require.NoError(t, s.Start())
+
+	msg := receive(t, msgs)
+	require.Equal(t, "system.connected", msg["msg"])
+	require.NotContains(t, msg, "network_id")
+
+	require.NoError(t, s.Stop())
+}
+
+func TestService_InvalidEndpoint(t *testing.T) {
+	s := NewService(&Info{}, []*genesis.TelemetryEndpoint{{Address: "/ip4/127.0.0.1/udp/1"}}, nil, nil, 0)
+	require.Error(t, s.Start())
+	require.NoError(t, s.Stop())
+}
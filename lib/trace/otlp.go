@@ -0,0 +1,218 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	otlpTracesPath   = "/v1/traces"
+	otlpSpanKindInt  = 1 // SPAN_KIND_INTERNAL
+	otlpStatusError  = 2 // STATUS_CODE_ERROR
+	otlpScopeName    = "github.com/ChainSafe/gossamer"
+	otlpExportTimout = 10 * time.Second
+)
+
+// OTLPExporter exports spans to an OpenTelemetry collector using OTLP over HTTP with JSON encoding
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	resource    []Attribute
+	client      *http.Client
+}
+
+// NewOTLPExporter returns an exporter that sends spans to the collector at the given endpoint,
+// eg. http://localhost:4318
+func NewOTLPExporter(endpoint, serviceName string) *OTLPExporter {
+	return &OTLPExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		serviceName: serviceName,
+		client: &http.Client{
+			Timeout: otlpExportTimout,
+		},
+	}
+}
+
+// AddResourceAttributes adds attributes describing the node to every exported batch of spans
+func (e *OTLPExporter) AddResourceAttributes(attrs ...Attribute) {
+	e.resource = append(e.resource, attrs...)
+}
+
+// Export sends the spans to the collector
+func (e *OTLPExporter) Export(spans []*Span) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return err
+	}
+
+	res, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned status %s", res.Status)
+	}
+
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope   `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []*otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *OTLPExporter) encode(spans []*Span) *otlpRequest {
+	scope := &otlpScopeSpans{
+		Scope: otlpScope{Name: otlpScopeName},
+	}
+
+	for _, s := range spans {
+		scope.Spans = append(scope.Spans, encodeSpan(s))
+	}
+
+	resource := []*otlpKeyValue{encodeAttribute(Attribute{Key: "service.name", Value: e.serviceName})}
+	for _, attr := range e.resource {
+		resource = append(resource, encodeAttribute(attr))
+	}
+
+	return &otlpRequest{
+		ResourceSpans: []*otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: resource,
+				},
+				ScopeSpans: []*otlpScopeSpans{scope},
+			},
+		},
+	}
+}
+
+func encodeSpan(s *Span) *otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	os := &otlpSpan{
+		TraceID:           s.ctx.TraceID.String(),
+		SpanID:            s.ctx.SpanID.String(),
+		Name:              s.name,
+		Kind:              otlpSpanKindInt,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+	}
+
+	if s.parent != (SpanID{}) {
+		os.ParentSpanID = s.parent.String()
+	}
+
+	for _, attr := range s.attrs {
+		os.Attributes = append(os.Attributes, encodeAttribute(attr))
+	}
+
+	if s.err != nil {
+		os.Status = &otlpStatus{
+			Code:    otlpStatusError,
+			Message: s.err.Error(),
+		}
+	}
+
+	return os
+}
+
+func encodeAttribute(attr Attribute) *otlpKeyValue {
+	kv := &otlpKeyValue{Key: attr.Key}
+
+	switch v := attr.Value.(type) {
+	case bool:
+		kv.Value.BoolValue = &v
+	case int:
+		i := strconv.FormatInt(int64(v), 10)
+		kv.Value.IntValue = &i
+	case int64:
+		i := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &i
+	case uint32:
+		i := strconv.FormatUint(uint64(v), 10)
+		kv.Value.IntValue = &i
+	case uint64:
+		i := strconv.FormatUint(v, 10)
+		kv.Value.IntValue = &i
+	case float64:
+		kv.Value.DoubleValue = &v
+	case string:
+		kv.Value.StringValue = &v
+	default:
+		str := fmt.Sprintf("%v", v)
+		kv.Value.StringValue = &str
+	}
+
+	return kv
+}
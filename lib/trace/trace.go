@@ -0,0 +1,204 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type contextKey struct{}
+
+var (
+	globalTracer *Tracer
+	globalLock   sync.RWMutex
+)
+
+// TraceID identifies a trace, it is shared by every span of the trace
+type TraceID [16]byte
+
+// String returns the hex encoding of the trace ID
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the hex encoding of the span ID
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is the identity of a span that is propagated to its children
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// ParseSpanContext decodes a hex encoded trace ID and span ID, such as the ones carried by a
+// W3C traceparent header
+func ParseSpanContext(traceID, spanID string) (SpanContext, error) {
+	sc := SpanContext{}
+
+	tid, err := hex.DecodeString(traceID)
+	if err != nil || len(tid) != len(sc.TraceID) {
+		return sc, fmt.Errorf("invalid trace ID %q", traceID)
+	}
+
+	sid, err := hex.DecodeString(spanID)
+	if err != nil || len(sid) != len(sc.SpanID) {
+		return sc, fmt.Errorf("invalid span ID %q", spanID)
+	}
+
+	copy(sc.TraceID[:], tid)
+	copy(sc.SpanID[:], sid)
+	return sc, nil
+}
+
+// Attribute is a key-value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a timed operation within a trace. A nil *Span is valid and ignores every call, it is
+// returned by StartSpan when tracing is disabled.
+type Span struct {
+	tracer *Tracer
+	name   string
+	ctx    SpanContext
+	parent SpanID
+	start  time.Time
+	end    time.Time
+	err    error
+
+	mu    sync.Mutex
+	attrs []Attribute
+}
+
+// StartSpan starts a span as a child of the span in the given context, or as the root of a new
+// trace if there is none. The returned context carries the new span.
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s := &Span{
+		tracer: t,
+		name:   name,
+		start:  time.Now(),
+		attrs:  attrs,
+	}
+
+	if parent, ok := ctx.Value(contextKey{}).(SpanContext); ok {
+		s.ctx.TraceID = parent.TraceID
+		s.parent = parent.SpanID
+	} else {
+		_, _ = rand.Read(s.ctx.TraceID[:])
+	}
+	_, _ = rand.Read(s.ctx.SpanID[:])
+
+	return context.WithValue(ctx, contextKey{}, s.ctx), s
+}
+
+// WithSpan calls fn in a span started as a child of the span in the given context. The span is ended when fn
+// returns, even if it panics, and marked as failed if fn returns an error.
+func WithSpan(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...Attribute) (err error) {
+	ctx, span := StartSpan(ctx, name, attrs...)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	return fn(ctx)
+}
+
+// ContextWithRemoteParent returns a context whose spans are children of a span started by
+// another process
+func ContextWithRemoteParent(ctx context.Context, parent SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, parent)
+}
+
+// Context returns the identity of the span
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, Attribute{Key: key, Value: value})
+}
+
+// SetError marks the span as failed with the given error, nil errors are ignored
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// End ends the span and queues it for export
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	s.tracer.record(s)
+}
+
+// Enabled returns true if spans are being recorded
+func Enabled() bool {
+	return getTracer() != nil
+}
+
+func getTracer() *Tracer {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return globalTracer
+}
+
+func setTracer(t *Tracer) {
+	globalLock.Lock()
+	defer globalLock.Unlock()
+	globalTracer = t
+}
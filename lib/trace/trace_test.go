@@ -0,0 +1,143 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockExporter struct {
+	lock  sync.Mutex
+	spans []*Span
+}
+
+func (e *mockExporter) Export(spans []*Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestStartSpan_Disabled(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "test")
+	require.Nil(t, span)
+	require.Equal(t, context.Background(), ctx)
+
+	// calls on a nil span are no-ops
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.End()
+	require.False(t, Enabled())
+}
+
+func TestTracer_ExportsNestedSpans(t *testing.T) {
+	exp := &mockExporter{}
+	tracer := NewTracer(exp)
+	require.NoError(t, tracer.Start())
+	require.True(t, Enabled())
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartSpan(ctx, "child", Attribute{Key: "number", Value: 1})
+	child.SetError(errors.New("failed"))
+	child.End()
+	parent.End()
+
+	require.NoError(t, tracer.Stop())
+	require.False(t, Enabled())
+
+	require.Len(t, exp.spans, 2)
+	require.Equal(t, "child", exp.spans[0].name)
+	require.Equal(t, parent.Context().TraceID, child.Context().TraceID)
+	require.Equal(t, parent.Context().SpanID, child.parent)
+	require.NotEqual(t, parent.Context().SpanID, child.Context().SpanID)
+}
+
+func TestWithSpan(t *testing.T) {
+	exp := &mockExporter{}
+	tracer := NewTracer(exp)
+	require.NoError(t, tracer.Start())
+
+	failed := errors.New("failed")
+	err := WithSpan(context.Background(), "failing", func(context.Context) error {
+		return failed
+	})
+	require.Equal(t, failed, err)
+
+	// the span is ended even if the function panics
+	require.Panics(t, func() {
+		_ = WithSpan(context.Background(), "panicking", func(context.Context) error {
+			panic("panic")
+		})
+	})
+
+	require.NoError(t, tracer.Stop())
+	require.Len(t, exp.spans, 2)
+	require.Equal(t, "failing", exp.spans[0].name)
+	require.Equal(t, failed, exp.spans[0].err)
+	require.Equal(t, "panicking", exp.spans[1].name)
+}
+
+func TestContextWithRemoteParent(t *testing.T) {
+	remote, err := ParseSpanContext("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	tracer := NewTracer(&mockExporter{})
+	require.NoError(t, tracer.Start())
+	defer tracer.Stop() //nolint
+
+	_, span := StartSpan(ContextWithRemoteParent(context.Background(), remote), "rpc")
+	require.Equal(t, remote.TraceID, span.Context().TraceID)
+	require.Equal(t, remote.SpanID, span.parent)
+
+	_, err = ParseSpanContext("invalid", "00f067aa0ba902b7")
+	require.Error(t, err)
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var req otlpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, otlpTracesPath, r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, "gossamer")
+	exp.AddResourceAttributes(Attribute{Key: "node.display_name", Value: "validator-1"})
+	tracer := NewTracer(exp)
+	require.NoError(t, tracer.Start())
+
+	_, span := StartSpan(context.Background(), "import_block", Attribute{Key: "number", Value: uint64(10)})
+	span.End()
+	require.NoError(t, tracer.Stop())
+
+	require.Len(t, req.ResourceSpans, 1)
+	require.Len(t, req.ResourceSpans[0].Resource.Attributes, 2)
+	require.Equal(t, "validator-1", *req.ResourceSpans[0].Resource.Attributes[1].Value.StringValue)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	require.Equal(t, "import_block", spans[0].Name)
+	require.Equal(t, span.Context().TraceID.String(), spans[0].TraceID)
+	require.Equal(t, "10", *spans[0].Attributes[0].Value.IntValue)
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trace
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/ChainSafe/log15"
+)
+
+const (
+	// DefaultBatchSize is the number of ended spans that triggers an export
+	DefaultBatchSize = 512
+	// DefaultFlushInterval is the maximum time an ended span waits before being exported
+	DefaultFlushInterval = 5 * time.Second
+	// maxQueueSize is the number of spans kept while the exporter is unavailable, further spans are dropped
+	maxQueueSize = 8192
+)
+
+var logger = log.New("pkg", "trace")
+
+// Exporter sends ended spans to a tracing backend
+type Exporter interface {
+	Export(spans []*Span) error
+}
+
+// Tracer batches ended spans and periodically passes them to its exporter. Spans are only recorded
+// while the tracer is started.
+type Tracer struct {
+	exporter      Exporter
+	batchSize     int
+	flushInterval time.Duration
+
+	lock    sync.Mutex
+	queue   []*Span
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewTracer returns a tracer that exports spans using the given exporter
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{
+		exporter:      exporter,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start sets the tracer as the recorder of all new spans and begins exporting
+func (t *Tracer) Start() error {
+	setTracer(t)
+
+	t.wg.Add(1)
+	go t.run()
+	return nil
+}
+
+// Stop stops recording spans and exports the ones that are still queued
+func (t *Tracer) Stop() error {
+	if getTracer() == t {
+		setTracer(nil)
+	}
+
+	close(t.stopCh)
+	t.wg.Wait()
+	return nil
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.flush()
+		case <-t.flushCh:
+			t.flush()
+		case <-t.stopCh:
+			t.flush()
+			return
+		}
+	}
+}
+
+func (t *Tracer) record(s *Span) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.queue) >= maxQueueSize {
+		logger.Debug("span queue full, dropping span", "name", s.name)
+		return
+	}
+
+	t.queue = append(t.queue, s)
+	if len(t.queue) < t.batchSize {
+		return
+	}
+
+	select {
+	case t.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+func (t *Tracer) flush() {
+	t.lock.Lock()
+	spans := t.queue
+	t.queue = nil
+	t.lock.Unlock()
+
+	for len(spans) > 0 {
+		n := t.batchSize
+		if n > len(spans) {
+			n = len(spans)
+		}
+
+		err := t.exporter.Export(spans[:n])
+		if err != nil {
+			logger.Warn("failed to export spans", "count", n, "error", err)
+		}
+		spans = spans[n:]
+	}
+}
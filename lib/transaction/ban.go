@@ -0,0 +1,190 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DefaultMaxValidationFailures is the number of times a transaction may fail validation before it is banned
+const DefaultMaxValidationFailures = 3
+
+// DefaultBanDuration is how long a transaction is banned for after repeatedly failing validation
+const DefaultBanDuration = time.Minute * 30
+
+// maxTrackedFailures is the number of transactions whose validation failures are tracked at once. Once it is
+// reached the least recently failed transaction is forgotten, so that a flood of distinct invalid transactions
+// can't grow the list.
+const maxTrackedFailures = 4096
+
+// BanStats holds metrics about transactions banned by a BanList
+type BanStats struct {
+	Banned    int    // number of currently banned transactions
+	TotalBans uint64 // number of transactions that have been banned
+	Rejected  uint64 // number of times a banned transaction was rejected
+}
+
+// BanList tracks transactions that fail validation and bans those that fail repeatedly, so that they
+// aren't re-validated every time they are received
+type BanList struct {
+	mu          sync.Mutex
+	failures    map[common.Hash]*list.Element
+	lru         *list.List                // of *failureEntry, most recently failed first
+	banned      map[common.Hash]time.Time // transaction hash -> ban expiry
+	maxFailures uint32
+	duration    time.Duration
+	stats       BanStats
+}
+
+type failureEntry struct {
+	hash  common.Hash
+	count uint32
+}
+
+// NewBanList returns a new BanList that bans transactions after maxFailures failed validations
+// for the given duration
+func NewBanList(maxFailures uint32, duration time.Duration) *BanList {
+	return &BanList{
+		failures:    make(map[common.Hash]*list.Element),
+		lru:         list.New(),
+		banned:      make(map[common.Hash]time.Time),
+		maxFailures: maxFailures,
+		duration:    duration,
+	}
+}
+
+// SetDuration sets how long transactions are banned for. It doesn't affect existing bans.
+func (b *BanList) SetDuration(duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.duration = duration
+}
+
+// RecordFailure records a failed validation of the transaction with the given hash. It returns true if
+// the transaction has now failed enough times to be banned.
+func (b *BanList) RecordFailure(hash common.Hash) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elem, has := b.failures[hash]
+	if has {
+		b.lru.MoveToFront(elem)
+	} else {
+		if len(b.failures) >= maxTrackedFailures {
+			b.removeFailures(b.lru.Back().Value.(*failureEntry).hash)
+		}
+
+		elem = b.lru.PushFront(&failureEntry{hash: hash})
+		b.failures[hash] = elem
+	}
+
+	entry := elem.Value.(*failureEntry)
+	entry.count++
+	if entry.count < b.maxFailures {
+		return false
+	}
+
+	b.removeFailures(hash)
+	b.banned[hash] = time.Now().Add(b.duration)
+	b.stats.TotalBans++
+	return true
+}
+
+// Ban bans the transaction with the given hash, regardless of how many times it failed validation
+func (b *BanList) Ban(hash common.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeFailures(hash)
+	b.banned[hash] = time.Now().Add(b.duration)
+	b.stats.TotalBans++
+}
+
+// removeFailures stops tracking the failures of the given transaction. b.mu must be held.
+func (b *BanList) removeFailures(hash common.Hash) {
+	if elem, has := b.failures[hash]; has {
+		b.lru.Remove(elem)
+		delete(b.failures, hash)
+	}
+}
+
+// IsBanned returns true if the transaction with the given hash is currently banned. Each call for a banned
+// transaction is counted as a rejection.
+func (b *BanList) IsBanned(hash common.Hash) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiry, has := b.banned[hash]
+	if !has {
+		return false
+	}
+
+	if time.Now().After(expiry) {
+		delete(b.banned, hash)
+		return false
+	}
+
+	b.stats.Rejected++
+	return true
+}
+
+// Stats returns the current ban metrics. Expired bans are removed before counting.
+func (b *BanList) Stats() BanStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for hash, expiry := range b.banned {
+		if now.After(expiry) {
+			delete(b.banned, hash)
+		}
+	}
+
+	stats := b.stats
+	stats.Banned = len(b.banned)
+	return stats
+}
+
+// ServeHTTP writes the ban metrics in the Prometheus text format
+func (b *BanList) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	stats := b.Stats()
+
+	buf.WriteString("# HELP gossamer_transaction_banned Number of currently banned transactions.\n")
+	buf.WriteString("# TYPE gossamer_transaction_banned gauge\n")
+	fmt.Fprintf(&buf, "gossamer_transaction_banned %d\n", stats.Banned)
+
+	buf.WriteString("# HELP gossamer_transaction_bans_total Number of transactions that have been banned.\n")
+	buf.WriteString("# TYPE gossamer_transaction_bans_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_transaction_bans_total %d\n", stats.TotalBans)
+
+	buf.WriteString("# HELP gossamer_transaction_ban_rejections_total Number of times a banned transaction was " +
+		"rejected.\n")
+	buf.WriteString("# TYPE gossamer_transaction_ban_rejections_total counter\n")
+	fmt.Fprintf(&buf, "gossamer_transaction_ban_rejections_total %d\n", stats.Rejected)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanList(t *testing.T) {
+	b := NewBanList(2, time.Minute)
+	hash := common.Hash{1}
+
+	require.False(t, b.RecordFailure(hash))
+	require.False(t, b.IsBanned(hash))
+	require.True(t, b.RecordFailure(hash))
+	require.True(t, b.IsBanned(hash))
+	require.False(t, b.IsBanned(common.Hash{2}))
+
+	stats := b.Stats()
+	require.Equal(t, 1, stats.Banned)
+	require.Equal(t, uint64(1), stats.TotalBans)
+	require.Equal(t, uint64(1), stats.Rejected)
+}
+
+func TestBanList_ServeHTTP(t *testing.T) {
+	b := NewBanList(1, time.Minute)
+	require.True(t, b.RecordFailure(common.Hash{1}))
+	b.Ban(common.Hash{2})
+	require.True(t, b.IsBanned(common.Hash{1}))
+
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, "gossamer_transaction_banned 2\n"))
+	require.True(t, strings.Contains(body, "gossamer_transaction_bans_total 2\n"))
+	require.True(t, strings.Contains(body, "gossamer_transaction_ban_rejections_total 1\n"))
+}
+
+func TestBanList_Ban(t *testing.T) {
+	b := NewBanList(2, time.Minute)
+	hash := common.Hash{1}
+
+	b.Ban(hash)
+	require.True(t, b.IsBanned(hash))
+	require.Equal(t, uint64(1), b.Stats().TotalBans)
+}
+
+func TestBanList_Expiry(t *testing.T) {
+	b := NewBanList(1, time.Millisecond)
+	hash := common.Hash{1}
+
+	require.True(t, b.RecordFailure(hash))
+	time.Sleep(time.Millisecond * 5)
+	require.False(t, b.IsBanned(hash))
+
+	stats := b.Stats()
+	require.Equal(t, 0, stats.Banned)
+	require.Equal(t, uint64(1), stats.TotalBans)
+	require.Equal(t, uint64(0), stats.Rejected)
+}
+
+func TestBanList_MaxTrackedFailures(t *testing.T) {
+	b := NewBanList(3, time.Minute)
+	for i := 0; i < maxTrackedFailures; i++ {
+		b.RecordFailure(common.Hash{byte(i), byte(i >> 8)})
+	}
+	require.Equal(t, maxTrackedFailures, len(b.failures))
+
+	// failing again makes the first transaction the most recently failed one
+	require.False(t, b.RecordFailure(common.Hash{0}))
+
+	// only the least recently failed transaction is forgotten
+	b.RecordFailure(common.Hash{0xff, 0xff, 0xff})
+	require.Equal(t, maxTrackedFailures, len(b.failures))
+	require.Equal(t, b.lru.Len(), len(b.failures))
+	require.NotContains(t, b.failures, common.Hash{1})
+	require.Contains(t, b.failures, common.Hash{0})
+	require.Contains(t, b.failures, common.Hash{2})
+}
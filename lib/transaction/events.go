@@ -0,0 +1,52 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// PoolEventType is the type of a transaction pool event
+type PoolEventType string
+
+const (
+	// PoolEventImported is emitted when a valid transaction is added to the pool
+	PoolEventImported PoolEventType = "imported"
+	// PoolEventReady is emitted when a transaction is added to the queue of transactions ready for block inclusion
+	PoolEventReady PoolEventType = "ready"
+	// PoolEventFuture is emitted when a transaction that isn't valid yet is added to the future queue
+	PoolEventFuture PoolEventType = "future"
+	// PoolEventFuturePromoted is emitted when a future transaction becomes valid and is moved to the pool
+	PoolEventFuturePromoted PoolEventType = "futurePromoted"
+	// PoolEventDropped is emitted when a future transaction is dropped after being held for too long, or when a
+	// transaction is evicted from the full pool to make room for one with a higher priority
+	PoolEventDropped PoolEventType = "dropped"
+	// PoolEventBanned is emitted when a transaction is banned after repeatedly failing validation
+	PoolEventBanned PoolEventType = "banned"
+	// PoolEventInBlock is emitted when a transaction is removed from the pool, queue or future queue because it's
+	// included in a block
+	PoolEventInBlock PoolEventType = "inBlock"
+	// PoolEventBroadcast is emitted when a transaction submitted to this node is gossiped to peers
+	PoolEventBroadcast PoolEventType = "broadcast"
+)
+
+// PoolEvent is a change of the state of a transaction in the transaction pool
+type PoolEvent struct {
+	Type  PoolEventType
+	Hash  common.Hash // hash of the extrinsic
+	Block common.Hash // block the extrinsic was included in, only set for PoolEventInBlock
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// DefaultFutureQueueLimit is the maximum number of transactions held in a FutureQueue
+const DefaultFutureQueueLimit = 1024
+
+// DefaultFutureLifetime is the number of blocks a transaction is held in a FutureQueue before it's dropped
+const DefaultFutureLifetime = 256
+
+// maxFutureWakeInterval is the maximum number of blocks between two revalidations of a future transaction
+const maxFutureWakeInterval = 32
+
+// ErrFutureQueueFull is returned when a transaction is added to a full FutureQueue
+var ErrFutureQueueFull = errors.New("future transaction queue is full")
+
+// FutureTransaction is a transaction that isn't valid yet, eg. because its nonce or era is ahead of the chain
+type FutureTransaction struct {
+	Extrinsic types.Extrinsic
+	WakeAt    uint64 // block number at which the transaction is revalidated, 0 for the next imported block
+	Since     uint64 // block number at which the transaction was first revalidated
+	retries   uint
+}
+
+// FutureQueue holds transactions that aren't valid yet until the block at which they are revalidated. The number
+// of blocks between two revalidations doubles every time a transaction is still not valid, so that transactions
+// that are far in the future aren't revalidated for every imported block.
+type FutureQueue struct {
+	mu       sync.Mutex
+	txs      map[common.Hash]*FutureTransaction
+	limit    int
+	lifetime uint64
+}
+
+// NewFutureQueue returns a new FutureQueue holding up to DefaultFutureQueueLimit transactions
+func NewFutureQueue() *FutureQueue {
+	return &FutureQueue{
+		txs:      make(map[common.Hash]*FutureTransaction),
+		limit:    DefaultFutureQueueLimit,
+		lifetime: DefaultFutureLifetime,
+	}
+}
+
+// Add adds a transaction to the queue, to be revalidated at the next imported block. Adding a transaction that's
+// already queued does nothing.
+func (q *FutureQueue) Add(ext types.Extrinsic) (common.Hash, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	hash := ext.Hash()
+	if _, has := q.txs[hash]; has {
+		return hash, nil
+	}
+
+	if len(q.txs) >= q.limit {
+		return hash, ErrFutureQueueFull
+	}
+
+	q.txs[hash] = &FutureTransaction{Extrinsic: ext}
+	return hash, nil
+}
+
+// Ready removes and returns the transactions that are due to be revalidated at the given block
+func (q *FutureQueue) Ready(number uint64) []*FutureTransaction {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ready []*FutureTransaction
+	for hash, ft := range q.txs {
+		if ft.WakeAt > number {
+			continue
+		}
+
+		if ft.Since == 0 {
+			ft.Since = number
+		}
+
+		ready = append(ready, ft)
+		delete(q.txs, hash)
+	}
+
+	return ready
+}
+
+// Reschedule adds back a transaction returned by Ready that still isn't valid at the given block. It returns false
+// if the transaction has been held for longer than the queue lifetime, in which case it's dropped.
+func (q *FutureQueue) Reschedule(ft *FutureTransaction, number uint64) bool {
+	if number-ft.Since >= q.lifetime {
+		return false
+	}
+
+	ft.retries++
+	interval := uint64(1) << ft.retries
+	if interval > maxFutureWakeInterval {
+		interval = maxFutureWakeInterval
+	}
+	ft.WakeAt = number + interval
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.txs) >= q.limit {
+		return false
+	}
+
+	q.txs[ft.Extrinsic.Hash()] = ft
+	return true
+}
+
+// Remove removes the transaction with the given hash from the queue. It returns true if the transaction was in the
+// queue.
+func (q *FutureQueue) Remove(hash common.Hash) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, has := q.txs[hash]
+	delete(q.txs, hash)
+	return has
+}
+
+// Has returns true if the transaction with the given hash is in the queue
+func (q *FutureQueue) Has(hash common.Hash) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, has := q.txs[hash]
+	return has
+}
+
+// Len returns the number of transactions in the queue
+func (q *FutureQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.txs)
+}
+
+// Size returns the total encoded size of the transactions in the queue
+func (q *FutureQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	size := 0
+	for _, ft := range q.txs {
+		size += len(ft.Extrinsic)
+	}
+	return size
+}
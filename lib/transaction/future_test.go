@@ -0,0 +1,76 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFutureQueue(t *testing.T) {
+	q := NewFutureQueue()
+	ext := types.Extrinsic{1, 2, 3}
+
+	hash, err := q.Add(ext)
+	require.NoError(t, err)
+	require.Equal(t, ext.Hash(), hash)
+	require.True(t, q.Has(hash))
+
+	// the transaction is revalidated at the next block
+	ready := q.Ready(10)
+	require.Len(t, ready, 1)
+	require.Equal(t, uint64(10), ready[0].Since)
+	require.Equal(t, 0, q.Len())
+
+	// and then after an increasing number of blocks
+	require.True(t, q.Reschedule(ready[0], 10))
+	require.Empty(t, q.Ready(11))
+	ready = q.Ready(12)
+	require.Len(t, ready, 1)
+
+	require.True(t, q.Reschedule(ready[0], 12))
+	require.Empty(t, q.Ready(15))
+	require.Len(t, q.Ready(16), 1)
+
+	q.Remove(hash)
+	require.False(t, q.Has(hash))
+}
+
+func TestFutureQueue_Lifetime(t *testing.T) {
+	q := NewFutureQueue()
+	_, err := q.Add(types.Extrinsic{1})
+	require.NoError(t, err)
+
+	ready := q.Ready(1)
+	require.Len(t, ready, 1)
+	require.False(t, q.Reschedule(ready[0], 1+DefaultFutureLifetime))
+	require.Equal(t, 0, q.Len())
+}
+
+func TestFutureQueue_Limit(t *testing.T) {
+	q := NewFutureQueue()
+	q.limit = 1
+
+	_, err := q.Add(types.Extrinsic{1})
+	require.NoError(t, err)
+	_, err = q.Add(types.Extrinsic{1})
+	require.NoError(t, err)
+	_, err = q.Add(types.Extrinsic{2})
+	require.Equal(t, ErrFutureQueueFull, err)
+}
@@ -1,14 +1,35 @@
 package transaction
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/ChainSafe/gossamer/lib/common"
 )
 
+// DefaultPoolMaxSize is the default maximum number of transactions in the pool
+const DefaultPoolMaxSize = 8192
+
+// DefaultPoolMaxBytes is the default maximum total encoded size of the transactions in the pool
+const DefaultPoolMaxBytes = 20 * 1024 * 1024
+
+var (
+	// ErrPoolCountLimit is returned when a transaction is rejected because the pool holds the maximum number of
+	// transactions and none of them may be evicted in favour of it
+	ErrPoolCountLimit = errors.New("transaction pool count limit reached")
+	// ErrPoolSizeLimit is returned when a transaction is rejected because the pool holds the maximum total size of
+	// transactions and not enough of them may be evicted in favour of it
+	ErrPoolSizeLimit = errors.New("transaction pool size limit reached")
+)
+
 // Pool represents the transaction pool
 type Pool struct {
 	transactions map[common.Hash]*ValidTransaction
+	maxSize      int
+	maxBytes     int
+	size         int  // total encoded size of the transactions
+	preferLocal  bool // if set, remote transactions are evicted before local ones
 	mu           sync.RWMutex
 }
 
@@ -16,7 +37,45 @@ type Pool struct {
 func NewPool() *Pool {
 	return &Pool{
 		transactions: make(map[common.Hash]*ValidTransaction),
+		maxSize:      DefaultPoolMaxSize,
+		maxBytes:     DefaultPoolMaxBytes,
+	}
+}
+
+// SetPreferLocal sets whether local transactions are kept in favour of remote ones when the pool is full
+func (p *Pool) SetPreferLocal(preferLocal bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.preferLocal = preferLocal
+}
+
+// SetLimits sets the maximum number of transactions and their maximum total encoded size in bytes.
+// A limit of 0 keeps the current limit. It only affects transactions inserted afterwards.
+func (p *Pool) SetLimits(count, bytes int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if count > 0 {
+		p.maxSize = count
 	}
+
+	if bytes > 0 {
+		p.maxBytes = bytes
+	}
+}
+
+// Len returns the number of transactions in the pool
+func (p *Pool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.transactions)
+}
+
+// Size returns the total encoded size of the transactions in the pool
+func (p *Pool) Size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.size
 }
 
 // Transactions returns all the transactions in the pool
@@ -34,18 +93,116 @@ func (p *Pool) Transactions() []*ValidTransaction {
 	return txs
 }
 
-// Insert inserts a transaction into the pool
-func (p *Pool) Insert(tx *ValidTransaction) common.Hash {
+// Insert inserts a transaction into the pool. If the pool is full, transactions that should be evicted before the
+// new one, ie. the ones with a lower priority, are evicted to make room for it. If there aren't enough of them,
+// the transaction is rejected with ErrPoolCountLimit or ErrPoolSizeLimit.
+func (p *Pool) Insert(tx *ValidTransaction) (common.Hash, error) {
+	hash, _, err := p.InsertEvicting(tx)
+	return hash, err
+}
+
+// InsertEvicting inserts a transaction into the pool like Insert, and also returns the hashes of the transactions
+// that were evicted to make room for it
+func (p *Pool) InsertEvicting(tx *ValidTransaction) (common.Hash, []common.Hash, error) {
 	hash := tx.Extrinsic.Hash()
 	p.mu.Lock()
 	defer p.mu.Unlock()
+
+	if _, has := p.transactions[hash]; has {
+		p.transactions[hash] = tx
+		return hash, nil, nil
+	}
+
+	if len(tx.Extrinsic) > p.maxBytes {
+		return hash, nil, p.limitError(ErrPoolSizeLimit)
+	}
+
+	var (
+		evicted = make(map[common.Hash]bool)
+		count   = len(p.transactions)
+		size    = p.size
+	)
+
+	for count >= p.maxSize || size+len(tx.Extrinsic) > p.maxBytes {
+		candidate, worst := p.evictionCandidate(evicted)
+		if worst == nil || !p.evictsBefore(worst, tx) {
+			if count >= p.maxSize {
+				return hash, nil, p.limitError(ErrPoolCountLimit)
+			}
+			return hash, nil, p.limitError(ErrPoolSizeLimit)
+		}
+
+		evicted[candidate] = true
+		count--
+		size -= len(worst.Extrinsic)
+	}
+
+	var hashes []common.Hash
+	for h := range evicted {
+		p.remove(h)
+		hashes = append(hashes, h)
+	}
+
 	p.transactions[hash] = tx
-	return hash
+	p.size += len(tx.Extrinsic)
+	return hash, hashes, nil
 }
 
-// Remove removes a transaction from the pool
-func (p *Pool) Remove(hash common.Hash) {
+// limitError returns the given limit error along with the value of the limit
+func (p *Pool) limitError(err error) error {
+	if err == ErrPoolCountLimit {
+		return fmt.Errorf("%w: maximum of %d transactions", err, p.maxSize)
+	}
+
+	return fmt.Errorf("%w: maximum of %d bytes", err, p.maxBytes)
+}
+
+// evictionCandidate returns the transaction that should be evicted first, ie. the one with the lowest priority,
+// ignoring the given transactions. If local transactions are preferred, remote transactions are always evicted
+// before local ones. The pool must be locked.
+func (p *Pool) evictionCandidate(ignore map[common.Hash]bool) (common.Hash, *ValidTransaction) {
+	var (
+		candidate common.Hash
+		worst     *ValidTransaction
+	)
+
+	for hash, tx := range p.transactions {
+		if ignore[hash] {
+			continue
+		}
+
+		if worst == nil || p.evictsBefore(tx, worst) {
+			candidate, worst = hash, tx
+		}
+	}
+
+	return candidate, worst
+}
+
+// evictsBefore returns true if a should be evicted before b
+func (p *Pool) evictsBefore(a, b *ValidTransaction) bool {
+	if p.preferLocal && a.Local != b.Local {
+		return !a.Local
+	}
+
+	return a.Validity.Priority < b.Validity.Priority
+}
+
+// Remove removes a transaction from the pool. It returns true if the transaction was in the pool.
+func (p *Pool) Remove(hash common.Hash) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	return p.remove(hash)
+}
+
+// remove removes a transaction from the pool, the pool must be locked
+func (p *Pool) remove(hash common.Hash) bool {
+	tx, has := p.transactions[hash]
+	if !has {
+		return false
+	}
+
+	p.size -= len(tx.Extrinsic)
 	delete(p.transactions, hash)
+	return true
 }
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"errors"
 	"sort"
 	"testing"
 
@@ -35,7 +36,8 @@ func TestPool(t *testing.T) {
 	p := NewPool()
 	hashes := make([]common.Hash, len(tests))
 	for i, tx := range tests {
-		h := p.Insert(tx)
+		h, err := p.Insert(tx)
+		require.NoError(t, err)
 		hashes[i] = h
 	}
 
@@ -50,3 +52,90 @@ func TestPool(t *testing.T) {
 	}
 	require.Equal(t, 0, len(p.Transactions()))
 }
+
+func TestPool_EvictsLowestPriority(t *testing.T) {
+	p := NewPool()
+	p.maxSize = 2
+
+	low := &ValidTransaction{Extrinsic: []byte("a"), Validity: &Validity{Priority: 1}}
+	high := &ValidTransaction{Extrinsic: []byte("b"), Validity: &Validity{Priority: 4}}
+	p.Insert(low)
+	p.Insert(high)
+
+	tx := &ValidTransaction{Extrinsic: []byte("c"), Validity: &Validity{Priority: 2}}
+	p.Insert(tx)
+	require.Equal(t, 2, p.Len())
+
+	transactions := p.Transactions()
+	require.NotContains(t, transactions, low)
+	require.Contains(t, transactions, high)
+	require.Contains(t, transactions, tx)
+}
+
+func TestPool_PreferLocal(t *testing.T) {
+	p := NewPool()
+	p.maxSize = 2
+	p.SetPreferLocal(true)
+
+	local := &ValidTransaction{Extrinsic: []byte("a"), Validity: &Validity{Priority: 1}, Local: true}
+	remote := &ValidTransaction{Extrinsic: []byte("b"), Validity: &Validity{Priority: 4}}
+	p.Insert(local)
+	p.Insert(remote)
+
+	tx := &ValidTransaction{Extrinsic: []byte("c"), Validity: &Validity{Priority: 5}}
+	p.Insert(tx)
+	require.Equal(t, 2, p.Len())
+
+	transactions := p.Transactions()
+	require.Contains(t, transactions, local)
+	require.NotContains(t, transactions, remote)
+	require.Contains(t, transactions, tx)
+}
+
+func TestPool_RejectsWhenFull(t *testing.T) {
+	p := NewPool()
+	p.SetLimits(2, 0)
+
+	low := &ValidTransaction{Extrinsic: []byte("a"), Validity: &Validity{Priority: 1}}
+	high := &ValidTransaction{Extrinsic: []byte("b"), Validity: &Validity{Priority: 4}}
+	_, err := p.Insert(low)
+	require.NoError(t, err)
+	_, err = p.Insert(high)
+	require.NoError(t, err)
+
+	// a transaction that doesn't rank above any transaction in the pool is rejected
+	tx := &ValidTransaction{Extrinsic: []byte("c"), Validity: &Validity{Priority: 1}}
+	_, err = p.Insert(tx)
+	require.True(t, errors.Is(err, ErrPoolCountLimit))
+	require.Contains(t, err.Error(), "maximum of 2 transactions")
+	require.Equal(t, 2, p.Len())
+}
+
+func TestPool_SizeLimit(t *testing.T) {
+	p := NewPool()
+	p.SetLimits(0, 8)
+
+	low := &ValidTransaction{Extrinsic: []byte("aaaa"), Validity: &Validity{Priority: 1}}
+	high := &ValidTransaction{Extrinsic: []byte("bbbb"), Validity: &Validity{Priority: 4}}
+	_, err := p.Insert(low)
+	require.NoError(t, err)
+	_, err = p.Insert(high)
+	require.NoError(t, err)
+	require.Equal(t, 8, p.Size())
+
+	// the lowest priority transaction is evicted to make room
+	tx := &ValidTransaction{Extrinsic: []byte("cc"), Validity: &Validity{Priority: 2}}
+	_, err = p.Insert(tx)
+	require.NoError(t, err)
+	require.Equal(t, 6, p.Size())
+	require.NotContains(t, p.Transactions(), low)
+
+	// a transaction larger than the limit is always rejected
+	big := &ValidTransaction{Extrinsic: []byte("ddddddddd"), Validity: &Validity{Priority: 10}}
+	_, err = p.Insert(big)
+	require.True(t, errors.Is(err, ErrPoolSizeLimit))
+	require.Contains(t, err.Error(), "maximum of 8 bytes")
+
+	p.Remove(tx.Extrinsic.Hash())
+	require.Equal(t, 4, p.Size())
+}
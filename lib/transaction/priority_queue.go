@@ -19,6 +19,7 @@ package transaction
 import (
 	"container/heap"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -35,6 +36,10 @@ type Item struct {
 
 	priority uint64 // The priority of the item in the queue.
 
+	// preferred is set for local transactions if local transactions are preferred. Preferred items are
+	// ordered before other items having the same priority value.
+	preferred bool
+
 	// The order is an monotonically increasing sequence and is used to differentiate between `Item`
 	// having the same priority value.
 	order uint64
@@ -51,6 +56,9 @@ func (pq priorityQueue) Len() int { return len(pq) }
 func (pq priorityQueue) Less(i, j int) bool {
 	// For Item having same priority value we compare them based on their insertion order(FIFO).
 	if pq[i].priority == pq[j].priority {
+		if pq[i].preferred != pq[j].preferred {
+			return pq[i].preferred
+		}
 		return pq[i].order < pq[j].order
 	}
 	// We want Pop to give us the highest, not lowest, priority so we use greater than here.
@@ -82,38 +90,75 @@ func (pq *priorityQueue) Pop() interface{} {
 
 // PriorityQueue is a thread safe wrapper over `priorityQueue`
 type PriorityQueue struct {
-	pq        priorityQueue
-	currOrder uint64
-	txs       map[common.Hash]*Item
+	pq          priorityQueue
+	currOrder   uint64
+	txs         map[common.Hash]*Item
+	preferLocal bool
+	maxSize     int
+	maxBytes    int
+	size        int // total encoded size of the transactions
 	sync.Mutex
 }
 
 // NewPriorityQueue creates new instance of PriorityQueue
 func NewPriorityQueue() *PriorityQueue {
 	spq := &PriorityQueue{
-		pq:  make(priorityQueue, 0),
-		txs: make(map[common.Hash]*Item),
+		pq:       make(priorityQueue, 0),
+		txs:      make(map[common.Hash]*Item),
+		maxSize:  DefaultPoolMaxSize,
+		maxBytes: DefaultPoolMaxBytes,
 	}
 	heap.Init(&spq.pq)
 	return spq
 }
 
+// SetPreferLocal sets whether local transactions are ordered before remote transactions having the same priority.
+// It only affects transactions pushed afterwards.
+func (spq *PriorityQueue) SetPreferLocal(preferLocal bool) {
+	spq.Lock()
+	defer spq.Unlock()
+	spq.preferLocal = preferLocal
+}
+
+// SetLimits sets the maximum number of transactions in the queue and their maximum total encoded size in bytes.
+// A limit of 0 keeps the current limit.
+func (spq *PriorityQueue) SetLimits(count, bytes int) {
+	spq.Lock()
+	defer spq.Unlock()
+
+	if count > 0 {
+		spq.maxSize = count
+	}
+
+	if bytes > 0 {
+		spq.maxBytes = bytes
+	}
+}
+
 // RemoveExtrinsic removes an extrinsic from the queue
 func (spq *PriorityQueue) RemoveExtrinsic(ext types.Extrinsic) {
+	spq.Remove(ext.Hash())
+}
+
+// Remove removes the transaction with the given hash from the queue. It returns true if the transaction was in the
+// queue.
+func (spq *PriorityQueue) Remove(hash common.Hash) bool {
 	spq.Lock()
 	defer spq.Unlock()
 
-	hash := ext.Hash()
 	item, ok := spq.txs[hash]
 	if !ok {
-		return
+		return false
 	}
 
 	heap.Remove(&spq.pq, item.index)
 	delete(spq.txs, hash)
+	spq.size -= len(item.data.Extrinsic)
+	return true
 }
 
-// Push inserts a valid transaction with priority p into the queue
+// Push inserts a valid transaction with priority p into the queue. If the queue is full, ErrPoolCountLimit or
+// ErrPoolSizeLimit is returned.
 func (spq *PriorityQueue) Push(txn *ValidTransaction) (common.Hash, error) {
 	spq.Lock()
 	defer spq.Unlock()
@@ -123,15 +168,25 @@ func (spq *PriorityQueue) Push(txn *ValidTransaction) (common.Hash, error) {
 		return hash, ErrTransactionExists
 	}
 
+	if len(spq.txs) >= spq.maxSize {
+		return hash, fmt.Errorf("%w: maximum of %d transactions", ErrPoolCountLimit, spq.maxSize)
+	}
+
+	if spq.size+len(txn.Extrinsic) > spq.maxBytes {
+		return hash, fmt.Errorf("%w: maximum of %d bytes", ErrPoolSizeLimit, spq.maxBytes)
+	}
+
 	item := &Item{
-		data:     txn,
-		hash:     hash,
-		order:    spq.currOrder,
-		priority: txn.Validity.Priority,
+		data:      txn,
+		hash:      hash,
+		order:     spq.currOrder,
+		priority:  txn.Validity.Priority,
+		preferred: spq.preferLocal && txn.Local,
 	}
 	spq.currOrder++
 	heap.Push(&spq.pq, item)
 	spq.txs[hash] = item
+	spq.size += len(txn.Extrinsic)
 
 	return hash, nil
 }
@@ -147,9 +202,17 @@ func (spq *PriorityQueue) Pop() *ValidTransaction {
 
 	item := heap.Pop(&spq.pq).(*Item)
 	delete(spq.txs, item.hash)
+	spq.size -= len(item.data.Extrinsic)
 	return item.data
 }
 
+// Size returns the total encoded size of the transactions in the queue
+func (spq *PriorityQueue) Size() int {
+	spq.Lock()
+	defer spq.Unlock()
+	return spq.size
+}
+
 // Peek returns the next item without removing it from the queue
 func (spq *PriorityQueue) Peek() *ValidTransaction {
 	spq.Lock()
@@ -17,8 +17,11 @@
 package transaction
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestPriorityQueue(t *testing.T) {
@@ -259,3 +262,48 @@ func TestRemoveExtrinsic(t *testing.T) {
 		t.Fatalf("Fail: got %v expected %v", res, tests[1])
 	}
 }
+
+func TestPriorityQueue_PreferLocal(t *testing.T) {
+	pq := NewPriorityQueue()
+	pq.SetPreferLocal(true)
+
+	remote := &ValidTransaction{Extrinsic: []byte("a"), Validity: &Validity{Priority: 2}}
+	local := &ValidTransaction{Extrinsic: []byte("b"), Validity: &Validity{Priority: 2}, Local: true}
+	high := &ValidTransaction{Extrinsic: []byte("c"), Validity: &Validity{Priority: 3}}
+
+	for _, tx := range []*ValidTransaction{remote, local, high} {
+		_, err := pq.Push(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []*ValidTransaction{high, local, remote}
+	for _, tx := range expected {
+		if res := pq.Pop(); !reflect.DeepEqual(res, tx) {
+			t.Fatalf("Fail: got %v expected %v", res, tx)
+		}
+	}
+}
+
+func TestPriorityQueue_Limits(t *testing.T) {
+	pq := NewPriorityQueue()
+	pq.SetLimits(2, 6)
+
+	_, err := pq.Push(&ValidTransaction{Extrinsic: []byte("aaa"), Validity: &Validity{Priority: 1}})
+	require.NoError(t, err)
+
+	_, err = pq.Push(&ValidTransaction{Extrinsic: []byte("bbbb"), Validity: &Validity{Priority: 1}})
+	require.True(t, errors.Is(err, ErrPoolSizeLimit))
+
+	_, err = pq.Push(&ValidTransaction{Extrinsic: []byte("cc"), Validity: &Validity{Priority: 1}})
+	require.NoError(t, err)
+
+	_, err = pq.Push(&ValidTransaction{Extrinsic: []byte("d"), Validity: &Validity{Priority: 1}})
+	require.True(t, errors.Is(err, ErrPoolCountLimit))
+
+	// popping a transaction frees its space
+	require.NotNil(t, pq.Pop())
+	_, err = pq.Push(&ValidTransaction{Extrinsic: []byte("d"), Validity: &Validity{Priority: 1}})
+	require.NoError(t, err)
+}
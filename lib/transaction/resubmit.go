@@ -0,0 +1,124 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// maxTrackedLocal is the number of local transactions that are remembered after leaving the pool. Once it is
+// reached the list is reset, so that a flood of submissions can't grow it.
+const maxTrackedLocal = 4096
+
+// LocalList remembers the hashes of transactions submitted to this node via RPC, so that they keep their
+// preference when they're resubmitted after the block including them is retracted
+type LocalList struct {
+	mu     sync.Mutex
+	hashes map[common.Hash]struct{}
+}
+
+// NewLocalList returns a new LocalList
+func NewLocalList() *LocalList {
+	return &LocalList{
+		hashes: make(map[common.Hash]struct{}),
+	}
+}
+
+// Add records that the transaction with the given hash was submitted locally
+func (l *LocalList) Add(hash common.Hash) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, has := l.hashes[hash]; !has && len(l.hashes) >= maxTrackedLocal {
+		l.hashes = make(map[common.Hash]struct{})
+	}
+
+	l.hashes[hash] = struct{}{}
+}
+
+// Has returns true if the transaction with the given hash was submitted locally
+func (l *LocalList) Has(hash common.Hash) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, has := l.hashes[hash]
+	return has
+}
+
+// ResubmitResult is the outcome of resubmitting a transaction of a retracted block
+type ResubmitResult string
+
+const (
+	// ResubmitReady is recorded when a transaction is revalidated and added back to the pool
+	ResubmitReady ResubmitResult = "ready"
+	// ResubmitFuture is recorded when a transaction isn't valid yet on the new chain and is added to the future queue
+	ResubmitFuture ResubmitResult = "future"
+	// ResubmitInvalid is recorded when a transaction is no longer valid on the new chain, or is rejected by the pool
+	ResubmitInvalid ResubmitResult = "invalid"
+	// ResubmitSkipped is recorded when a transaction is banned, or is also included in the new chain
+	ResubmitSkipped ResubmitResult = "skipped"
+)
+
+// resubmitResults are the results reported by ResubmitStats, in order
+var resubmitResults = []ResubmitResult{ResubmitReady, ResubmitFuture, ResubmitInvalid, ResubmitSkipped}
+
+// ResubmitStats counts the transactions of retracted blocks resubmitted on chain re-orgs, by result
+type ResubmitStats struct {
+	mu     sync.Mutex
+	counts map[ResubmitResult]uint64
+}
+
+// NewResubmitStats returns a new ResubmitStats
+func NewResubmitStats() *ResubmitStats {
+	return &ResubmitStats{
+		counts: make(map[ResubmitResult]uint64),
+	}
+}
+
+// Record counts a resubmitted transaction with the given result
+func (s *ResubmitStats) Record(result ResubmitResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[result]++
+}
+
+// Count returns the number of resubmitted transactions with the given result
+func (s *ResubmitStats) Count(result ResubmitResult) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[result]
+}
+
+// ServeHTTP writes the resubmission counts in the Prometheus text format
+func (s *ResubmitStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+
+	buf.WriteString("# HELP gossamer_transaction_resubmissions_total Number of transactions of retracted blocks " +
+		"resubmitted to the pool.\n")
+	buf.WriteString("# TYPE gossamer_transaction_resubmissions_total counter\n")
+	for _, result := range resubmitResults {
+		fmt.Fprintf(&buf, "gossamer_transaction_resubmissions_total{result=%q} %d\n", result, s.Count(result))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package transaction
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalList(t *testing.T) {
+	l := NewLocalList()
+	l.Add(common.Hash{1})
+	require.True(t, l.Has(common.Hash{1}))
+	require.False(t, l.Has(common.Hash{2}))
+
+	for i := 0; i < maxTrackedLocal; i++ {
+		l.Add(common.Hash{byte(i), byte(i >> 8), 1})
+	}
+	require.False(t, l.Has(common.Hash{1}))
+	require.Equal(t, 1, len(l.hashes))
+}
+
+func TestResubmitStats_ServeHTTP(t *testing.T) {
+	s := NewResubmitStats()
+	s.Record(ResubmitReady)
+	s.Record(ResubmitReady)
+	s.Record(ResubmitSkipped)
+	require.Equal(t, uint64(2), s.Count(ResubmitReady))
+	require.Equal(t, uint64(0), s.Count(ResubmitInvalid))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	require.True(t, strings.Contains(body, `gossamer_transaction_resubmissions_total{result="ready"} 2`))
+	require.True(t, strings.Contains(body, `gossamer_transaction_resubmissions_total{result="future"} 0`))
+	require.True(t, strings.Contains(body, `gossamer_transaction_resubmissions_total{result="skipped"} 1`))
+}
@@ -47,6 +47,7 @@ func NewValidity(priority uint64, requires, provides [][]byte, longevity uint64,
 type ValidTransaction struct {
 	Extrinsic types.Extrinsic
 	Validity  *Validity
+	Local     bool // set if the transaction was submitted to this node via RPC rather than received from a peer
 }
 
 // NewValidTransaction returns ValidTransaction
@@ -44,6 +44,71 @@ func (t *Trie) PutChild(keyToChild []byte, child *Trie) error {
 	return nil
 }
 
+// LoadChildren builds a child trie from each of the given child storages, keyed by the hex encoded key of the
+// child trie without the child storage prefix, and puts it into the trie at key :child_storage:default:[keyToChild]
+func (t *Trie) LoadChildren(children map[string]map[string]string) error {
+	for keyToChild, data := range children {
+		key, err := common.HexToBytes(keyToChild)
+		if err != nil {
+			return err
+		}
+
+		child := NewEmptyTrieWithHasher(t.Hasher())
+		err = child.Load(data)
+		if err != nil {
+			return fmt.Errorf("failed to load child trie %s: %w", keyToChild, err)
+		}
+
+		err = t.PutChild(key, child)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ChildTries returns the child tries of the trie that are in memory, by their root hash
+func (t *Trie) ChildTries() map[common.Hash]*Trie {
+	children := make(map[common.Hash]*Trie, len(t.children))
+	for hash, child := range t.children {
+		if child != nil {
+			children[hash] = child
+		}
+	}
+
+	return children
+}
+
+// ChildRoots returns the root hashes of the child tries that the trie holds at keys :child_storage:default:*
+func (t *Trie) ChildRoots() ([]common.Hash, error) {
+	var roots []common.Hash
+	for _, key := range t.GetKeysWithPrefix(ChildStorageKeyPrefix) {
+		value, err := t.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(value) != common.HashLength {
+			return nil, fmt.Errorf("invalid child trie root at key %x", key)
+		}
+		roots = append(roots, common.BytesToHash(value))
+	}
+
+	return roots, nil
+}
+
+// SetChildTrie attaches a child trie that was loaded separately, such as from the database, under its root hash
+func (t *Trie) SetChildTrie(child *Trie) error {
+	hash, err := child.Hash()
+	if err != nil {
+		return err
+	}
+
+	t.children[hash] = child
+	return nil
+}
+
 // GetChild returns the child trie at key :child_storage:[keyToChild]
 func (t *Trie) GetChild(keyToChild []byte) (*Trie, error) {
 	key := append(ChildStorageKeyPrefix, keyToChild...)
@@ -84,3 +84,87 @@ func TestPutAndGetFromChild(t *testing.T) {
 		t.Fatalf("Fail: got %x expected %x", valueRes, testValue)
 	}
 }
+
+func TestLoadChildren(t *testing.T) {
+	children := map[string]map[string]string{
+		"0x64656661756c74": {
+			"0x6b6579": "0x76616c7565",
+		},
+	}
+
+	parentTrie := NewEmptyTrie()
+	err := parentTrie.LoadChildren(children)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valueRes, err := parentTrie.GetFromChild([]byte("default"), []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(valueRes, []byte("value")) {
+		t.Fatalf("Fail: got %x expected %x", valueRes, []byte("value"))
+	}
+
+	// the child trie is copied along with the main trie
+	cp, err := parentTrie.DeepCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valueRes, err = cp.GetFromChild([]byte("default"), []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(valueRes, []byte("value")) {
+		t.Fatalf("Fail: got %x expected %x", valueRes, []byte("value"))
+	}
+}
+
+func TestChildRoots(t *testing.T) {
+	child := NewEmptyTrie()
+	err := child.Put([]byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentTrie := NewEmptyTrie()
+	err = parentTrie.PutChild([]byte("default"), child)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots, err := parentTrie.ChildRoots()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(roots) != 1 || roots[0] != child.MustHash() {
+		t.Fatalf("Fail: got %v expected %s", roots, child.MustHash())
+	}
+
+	// a child trie that's attached separately is found by its key
+	decoded := NewEmptyTrie()
+	for k, v := range parentTrie.Entries() {
+		err = decoded.Put([]byte(k), v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = decoded.SetChildTrie(child)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valueRes, err := decoded.GetFromChild([]byte("default"), []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(valueRes, []byte("value")) {
+		t.Fatalf("Fail: got %x expected %x", valueRes, []byte("value"))
+	}
+}
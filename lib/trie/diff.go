@@ -0,0 +1,181 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Change is an entry that differs between two tries. Old is nil if the entry was added and New is nil if it was
+// removed.
+type Change struct {
+	Key []byte
+	Old []byte
+	New []byte
+}
+
+// Diff returns the entries with the given prefix that were added, changed or removed between the from and to tries,
+// sorted by key. The tries are walked together and the subtries that have the same hash in both are skipped. The
+// tries must not be modified while they're compared.
+func Diff(from, to *Trie, prefix []byte) ([]*Change, error) {
+	d := &differ{
+		from:          from,
+		to:            to,
+		fromHasher:    newCachingHasher(from.hash),
+		toHasher:      newCachingHasher(to.hash),
+		prefix:        prefix,
+		prefixNibbles: keyToNibbles(prefix),
+		changes:       []*Change{},
+	}
+
+	err := d.diff(from.root, to.root, []byte{}, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(d.changes, func(i, j int) bool {
+		return bytes.Compare(d.changes[i].Key, d.changes[j].Key) < 0
+	})
+	return d.changes, nil
+}
+
+type differ struct {
+	from, to             *Trie
+	fromHasher, toHasher *Hasher
+	prefix               []byte
+	prefixNibbles        []byte
+	changes              []*Change
+}
+
+// diff compares the subtries at the given nodes, whose parents' keys followed by their indexes are the given paths
+func (d *differ) diff(from, to node, fromPath, toPath []byte) error {
+	fromKey := fullKey(from, fromPath)
+	toKey := fullKey(to, toPath)
+
+	fromMatches := from != nil && d.mayHavePrefix(fromKey)
+	toMatches := to != nil && d.mayHavePrefix(toKey)
+	if !fromMatches && !toMatches {
+		return nil
+	}
+
+	if from != nil && to != nil {
+		if bytes.Equal(fromKey, toKey) {
+			fromHash, err := d.fromHasher.Hash(from)
+			if err != nil {
+				return err
+			}
+
+			toHash, err := d.toHasher.Hash(to)
+			if err != nil {
+				return err
+			}
+
+			if bytes.Equal(fromHash, toHash) {
+				return nil
+			}
+
+			fromValue, fromHas := nodeValue(from)
+			toValue, toHas := nodeValue(to)
+			if fromHas || toHas {
+				d.compare(nibblesToKeyLE(fromKey), fromValue, fromHas, toValue, toHas)
+			}
+
+			fromChildren := nodeChildren(from)
+			toChildren := nodeChildren(to)
+			for i := range fromChildren {
+				err = d.diff(fromChildren[i], toChildren[i], append(fromKey, byte(i)), append(toKey, byte(i)))
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	// the subtries are shaped differently, so their entries are compared one by one
+	fromEntries := d.from.entries(from, fromPath, make(map[string][]byte))
+	toEntries := d.to.entries(to, toPath, make(map[string][]byte))
+
+	for k, v := range toEntries {
+		old, has := fromEntries[k]
+		d.compare([]byte(k), old, has, v, true)
+	}
+
+	for k, v := range fromEntries {
+		if _, has := toEntries[k]; !has {
+			d.compare([]byte(k), v, true, nil, false)
+		}
+	}
+
+	return nil
+}
+
+// mayHavePrefix returns true if the keys of a subtrie whose root has the given key, in nibbles, may have the prefix
+func (d *differ) mayHavePrefix(key []byte) bool {
+	l := len(key)
+	if len(d.prefixNibbles) < l {
+		l = len(d.prefixNibbles)
+	}
+	return bytes.Equal(key[:l], d.prefixNibbles[:l])
+}
+
+// compare records a change if the values at the given key differ and the key has the prefix
+func (d *differ) compare(key, oldValue []byte, oldHas bool, newValue []byte, newHas bool) {
+	if !bytes.HasPrefix(key, d.prefix) {
+		return
+	}
+
+	if oldHas == newHas && bytes.Equal(oldValue, newValue) {
+		return
+	}
+
+	d.changes = append(d.changes, &Change{Key: key, Old: oldValue, New: newValue})
+}
+
+// fullKey returns the key of the node, in nibbles, given the path of the node. The key of an empty subtrie is its path.
+func fullKey(n node, path []byte) []byte {
+	var partial []byte
+	switch c := n.(type) {
+	case *branch:
+		partial = c.key
+	case *leaf:
+		partial = c.key
+	}
+
+	return append(append([]byte{}, path...), partial...)
+}
+
+// nodeValue returns the value of the node, and false if it has none
+func nodeValue(n node) ([]byte, bool) {
+	switch c := n.(type) {
+	case *branch:
+		return c.value, c.value != nil
+	case *leaf:
+		return c.value, true
+	}
+	return nil, false
+}
+
+// nodeChildren returns the children of the node, leaves have none
+func nodeChildren(n node) [16]node {
+	if b, ok := n.(*branch); ok {
+		return b.children
+	}
+	return [16]node{}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// diffEntries compares the entries of the tries one by one
+func diffEntries(from, to *Trie, prefix []byte) []*Change {
+	oldEntries := from.Entries()
+	newEntries := to.Entries()
+
+	changes := []*Change{}
+	for k, v := range newEntries {
+		old, has := oldEntries[k]
+		if !bytes.HasPrefix([]byte(k), prefix) || (has && bytes.Equal(old, v)) {
+			continue
+		}
+		changes = append(changes, &Change{Key: []byte(k), Old: old, New: v})
+	}
+
+	for k, v := range oldEntries {
+		if _, has := newEntries[k]; has || !bytes.HasPrefix([]byte(k), prefix) {
+			continue
+		}
+		changes = append(changes, &Change{Key: []byte(k), Old: v})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].Key, changes[j].Key) < 0
+	})
+	return changes
+}
+
+func TestDiff(t *testing.T) {
+	from := NewEmptyTrie()
+	for _, k := range []string{"aa", "ab", "ac", "xy"} {
+		require.NoError(t, from.Put([]byte(k), []byte(k)))
+	}
+
+	to, err := from.DeepCopy()
+	require.NoError(t, err)
+
+	changes, err := Diff(from, to, nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	require.NoError(t, to.Delete([]byte("aa")))
+	require.NoError(t, to.Put([]byte("ab"), []byte("changed")))
+	require.NoError(t, to.Put([]byte("abc"), []byte("abc")))
+	require.NoError(t, to.Put([]byte("xz"), []byte("xz")))
+
+	expected := []*Change{
+		{Key: []byte("aa"), Old: []byte("aa")},
+		{Key: []byte("ab"), Old: []byte("ab"), New: []byte("changed")},
+		{Key: []byte("abc"), New: []byte("abc")},
+	}
+
+	changes, err = Diff(from, to, []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, expected, changes)
+
+	changes, err = Diff(from, to, nil)
+	require.NoError(t, err)
+	require.Equal(t, diffEntries(from, to, nil), changes)
+}
+
+func TestDiff_Random(t *testing.T) {
+	rt := GenerateRandomTests(t, 1000)
+
+	from := NewEmptyTrie()
+	for _, test := range rt {
+		require.NoError(t, from.Put(test.key, test.value))
+	}
+
+	to, err := from.DeepCopy()
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1)) //nolint
+	for i, test := range rt {
+		switch r.Intn(4) {
+		case 0:
+			require.NoError(t, to.Delete(test.key))
+		case 1:
+			require.NoError(t, to.Put(test.key, []byte{byte(i)}))
+		case 2:
+			require.NoError(t, to.Put(append(test.key, byte(i)), test.value))
+		}
+	}
+
+	for _, prefix := range [][]byte{nil, rt[0].key[:1], rt[1].key} {
+		changes, err := Diff(from, to, prefix)
+		require.NoError(t, err)
+		require.Equal(t, diffEntries(from, to, prefix), changes)
+	}
+}
@@ -21,20 +21,21 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/scale"
 )
 
 // Encode traverses the trie recursively, encodes each node, SCALE encodes the encoded node, and appends them all together
 func (t *Trie) Encode() ([]byte, error) {
-	return encodeRecursive(t.root, []byte{})
+	return encodeRecursive(t.root, t.Hasher(), []byte{})
 }
 
-func encodeRecursive(n node, enc []byte) ([]byte, error) {
+func encodeRecursive(n node, hash common.HashFunc, enc []byte) ([]byte, error) {
 	if n == nil {
 		return []byte{}, nil
 	}
 
-	nenc, err := n.encode()
+	nenc, err := n.encode(hash)
 	if err != nil {
 		return enc, err
 	}
@@ -50,7 +51,7 @@ func encodeRecursive(n node, enc []byte) ([]byte, error) {
 	case *branch:
 		for _, child := range n.children {
 			if child != nil {
-				enc, err = encodeRecursive(child, enc)
+				enc, err = encodeRecursive(child, hash, enc)
 				if err != nil {
 					return enc, err
 				}
@@ -17,31 +17,61 @@
 package trie
 
 import (
-	"hash"
-
-	"golang.org/x/crypto/blake2b"
+	"github.com/ChainSafe/gossamer/lib/common"
 )
 
 // Hasher is a wrapper around a hash function
 type Hasher struct {
-	hash hash.Hash
+	hash  common.HashFunc
+	cache map[node][]byte // if not nil, the hashes of the nodes hashed so far
 }
 
-// NewHasher create new Hasher instance
+// NewHasher create new Hasher instance using blake2b
 func NewHasher() (*Hasher, error) {
-	h, err := blake2b.New256(nil)
-	if err != nil {
-		return nil, err
+	return newHasher(common.Blake2bHash), nil
+}
+
+func newHasher(hash common.HashFunc) *Hasher {
+	return &Hasher{
+		hash: hash,
 	}
+}
 
+// newCachingHasher returns a Hasher that remembers the hashes of the nodes, so that the nodes of a subtrie aren't
+// encoded again when it's hashed from each of its ancestors. The nodes must not be modified while it's used.
+func newCachingHasher(hash common.HashFunc) *Hasher {
 	return &Hasher{
-		hash: h,
-	}, nil
+		hash:  hash,
+		cache: make(map[node][]byte),
+	}
 }
 
 // Hash encodes the node and then hashes it if its encoded length is > 32 bytes
 func (h *Hasher) Hash(n node) (res []byte, err error) {
-	encNode, err := n.encode()
+	if h.cache == nil {
+		return h.hashNode(n)
+	}
+
+	if res, has := h.cache[n]; has {
+		return res, nil
+	}
+
+	res, err = h.hashNode(n)
+	if err != nil {
+		return nil, err
+	}
+
+	h.cache[n] = res
+	return res, nil
+}
+
+func (h *Hasher) hashNode(n node) (res []byte, err error) {
+	var encNode []byte
+	if b, ok := n.(*branch); ok {
+		encNode, err = b.encodeWithHasher(h)
+	} else {
+		encNode, err = n.encode(h.hash)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +82,10 @@ func (h *Hasher) Hash(n node) (res []byte, err error) {
 	}
 
 	// otherwise, hash encoded node
-	_, err = h.hash.Write(encNode)
-	if err == nil {
-		res = h.hash.Sum(nil)
+	hash, err := h.hash(encNode)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, err
+	return hash[:], nil
 }
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"math/rand"
 	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
 )
 
 func generateRandBytes(size int) []byte {
@@ -48,17 +50,19 @@ func TestNewHasher(t *testing.T) {
 		t.Fatal("did not create new hasher")
 	}
 
-	_, err = hasher.hash.Write([]byte("noot"))
+	sum, err := hasher.hash([]byte("noot"))
 	if err != nil {
 		t.Error(err)
 	}
 
-	sum := hasher.hash.Sum(nil)
-	if sum == nil {
-		t.Error("did not sum hash")
+	expected, err := common.Blake2bHash([]byte("noot"))
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	hasher.hash.Reset()
+	if sum != expected {
+		t.Error("did not hash with blake2b by default")
+	}
 }
 
 func TestHashLeaf(t *testing.T) {
@@ -99,7 +103,7 @@ func TestHashShort(t *testing.T) {
 	}
 
 	n := &leaf{key: generateRandBytes(2), value: generateRandBytes(3)}
-	expected, err := n.encode()
+	expected, err := n.encode(common.Blake2bHash)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -113,3 +117,44 @@ func TestHashShort(t *testing.T) {
 		t.Errorf("did not return encoded node padded to 32 bytes: got %s", h)
 	}
 }
+
+func TestNewEmptyTrieWithHasher(t *testing.T) {
+	blake := NewEmptyTrie()
+	keccak := NewEmptyTrieWithHasher(common.Keccak256)
+	for i := 0; i < 16; i++ {
+		key, value := generateRandBytes(32), generateRandBytes(64)
+		if err := blake.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := keccak.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enc, err := keccak.EncodeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := common.Keccak256(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keccak.MustHash() != expected {
+		t.Error("did not hash the root with the trie's hash function")
+	}
+
+	if keccak.MustHash() == blake.MustHash() {
+		t.Error("did not hash the nodes with the trie's hash function")
+	}
+
+	cp, err := keccak.DeepCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cp.MustHash() != expected {
+		t.Error("copy of the trie does not use the trie's hash function")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"unsafe"
+)
+
+var (
+	branchSize = uint64(unsafe.Sizeof(branch{}))
+	leafSize   = uint64(unsafe.Sizeof(leaf{}))
+)
+
+// MemoryUsage returns the approximate number of bytes held by the nodes of the trie and its child tries
+func (t *Trie) MemoryUsage() uint64 {
+	size := nodeMemoryUsage(t.root)
+	for _, child := range t.children {
+		size += child.MemoryUsage()
+	}
+
+	return size
+}
+
+func nodeMemoryUsage(n node) uint64 {
+	switch n := n.(type) {
+	case *branch:
+		size := branchSize + uint64(cap(n.key)+cap(n.value))
+		for _, child := range n.children {
+			size += nodeMemoryUsage(child)
+		}
+		return size
+	case *leaf:
+		return leafSize + uint64(cap(n.key)+cap(n.value))
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_MemoryUsage(t *testing.T) {
+	trie := NewEmptyTrie()
+	require.Equal(t, uint64(0), trie.MemoryUsage())
+
+	err := trie.Put([]byte("noot"), []byte("washere"))
+	require.NoError(t, err)
+	single := trie.MemoryUsage()
+	require.True(t, single >= leafSize+uint64(len("washere")))
+
+	err = trie.Put([]byte("noodle"), make([]byte, 1024))
+	require.NoError(t, err)
+	require.True(t, trie.MemoryUsage() >= single+1024+branchSize)
+
+	// child tries are included
+	child := NewEmptyTrie()
+	err = child.Put([]byte("child"), make([]byte, 512))
+	require.NoError(t, err)
+	before := trie.MemoryUsage()
+	err = trie.PutChild([]byte("keytochild"), child)
+	require.NoError(t, err)
+	require.True(t, trie.MemoryUsage() >= before+512)
+}
@@ -51,7 +51,7 @@ import (
 
 // node is the interface for trie methods
 type node interface {
-	encode() ([]byte, error)
+	encode(hash common.HashFunc) ([]byte, error)
 	decode(r io.Reader, h byte) error
 	isDirty() bool
 	setDirty(dirty bool)
@@ -120,12 +120,13 @@ func (b *branch) setKey(key []byte) {
 // Encode is the high-level function wrapping the encoding for different node types
 // encoding has the following format:
 // NodeHeader | Extra partial key length | Partial Key | Value
-func encode(n node) ([]byte, error) {
+// The children of branches are hashed with the given hash function.
+func encode(n node, hash common.HashFunc) ([]byte, error) {
 	switch n := n.(type) {
 	case *branch:
-		return n.encode()
+		return n.encode(hash)
 	case *leaf:
-		return n.encode()
+		return n.encode(hash)
 	case nil:
 		return []byte{0}, nil
 	}
@@ -133,8 +134,14 @@ func encode(n node) ([]byte, error) {
 	return nil, nil
 }
 
-// Encode encodes a branch with the encoding specified at the top of this package
-func (b *branch) encode() ([]byte, error) {
+// Encode encodes a branch with the encoding specified at the top of this package, hashing its children with the
+// given hash function
+func (b *branch) encode(hash common.HashFunc) ([]byte, error) {
+	return b.encodeWithHasher(newHasher(hash))
+}
+
+// encodeWithHasher encodes a branch, hashing its children with the given hasher
+func (b *branch) encodeWithHasher(hasher *Hasher) ([]byte, error) {
 	encoding, err := b.header()
 	if err != nil {
 		return nil, err
@@ -155,11 +162,6 @@ func (b *branch) encode() ([]byte, error) {
 
 	for _, child := range b.children {
 		if child != nil {
-			hasher, err := NewHasher()
-			if err != nil {
-				return nil, err
-			}
-
 			encChild, err := hasher.Hash(child)
 			if err != nil {
 				return encoding, err
@@ -176,7 +178,7 @@ func (b *branch) encode() ([]byte, error) {
 }
 
 // Encode encodes a leaf with the encoding specified at the top of this package
-func (l *leaf) encode() ([]byte, error) {
+func (l *leaf) encode(_ common.HashFunc) ([]byte, error) {
 	encoding, err := l.header()
 	if err != nil {
 		return nil, err
@@ -182,7 +182,7 @@ func TestBranchEncode(t *testing.T) {
 			}
 		}
 
-		res, err := b.encode()
+		res, err := b.encode(common.Blake2bHash)
 		if !bytes.Equal(res, expected) {
 			t.Errorf("Fail when encoding node: got %x expected %x", res, expected)
 		} else if err != nil {
@@ -215,7 +215,7 @@ func TestLeafEncode(t *testing.T) {
 
 		expected = append(expected, buf.Bytes()...)
 
-		res, err := l.encode()
+		res, err := l.encode(common.Blake2bHash)
 		if !bytes.Equal(res, expected) {
 			t.Errorf("Fail when encoding node: got %x expected %x", res, expected)
 		} else if err != nil {
@@ -242,7 +242,7 @@ func TestEncodeRoot(t *testing.T) {
 				t.Errorf("Fail to get key %x with value %x: got %x", test.key, test.value, val)
 			}
 
-			_, err = encode(trie.root)
+			_, err = encode(trie.root, common.Blake2bHash)
 			if err != nil {
 				t.Errorf("Fail to encode trie root: %s", err)
 			}
@@ -268,7 +268,7 @@ func TestBranchDecode(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		enc, err := test.encode()
+		enc, err := test.encode(common.Blake2bHash)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -304,7 +304,7 @@ func TestLeafDecode(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		enc, err := test.encode()
+		enc, err := test.encode(common.Blake2bHash)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -347,7 +347,7 @@ func TestDecode(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		enc, err := test.encode()
+		enc, err := test.encode(common.Blake2bHash)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -31,15 +31,13 @@ func (t *Trie) StringWithEncoding() string {
 }
 
 func (t *Trie) string(str string, current node, prefix []byte, withEncoding bool) string {
-	h, err := NewHasher()
-	if err != nil {
-		return ""
-	}
+	h := newHasher(t.Hasher())
 
 	var encoding []byte
 	var hash []byte
+	var err error
 	if withEncoding && current != nil {
-		encoding, err = current.encode()
+		encoding, err = current.encode(t.Hasher())
 		if err != nil {
 			return ""
 		}
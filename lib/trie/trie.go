@@ -34,27 +34,44 @@ var EmptyHash, _ = NewEmptyTrie().Hash()
 type Trie struct {
 	root     node
 	children map[common.Hash]*Trie
+	hash     common.HashFunc // hashes the nodes, blake2b if nil
 }
 
-// NewEmptyTrie creates a trie with a nil root
+// NewEmptyTrie creates a trie with a nil root, whose nodes are hashed with blake2b
 func NewEmptyTrie() *Trie {
+	return NewEmptyTrieWithHasher(common.Blake2bHash)
+}
+
+// NewEmptyTrieWithHasher creates a trie with a nil root, whose nodes are hashed with the given hash function
+func NewEmptyTrieWithHasher(hash common.HashFunc) *Trie {
 	return &Trie{
 		root:     nil,
 		children: make(map[common.Hash]*Trie),
+		hash:     hash,
 	}
 }
 
-// NewTrie creates a trie with an existing root node
+// NewTrie creates a trie with an existing root node, whose nodes are hashed with blake2b
 func NewTrie(root node) *Trie {
 	return &Trie{
 		root:     root,
 		children: make(map[common.Hash]*Trie),
+		hash:     common.Blake2bHash,
+	}
+}
+
+// Hasher returns the hash function of the trie's nodes
+func (t *Trie) Hasher() common.HashFunc {
+	if t.hash == nil {
+		return common.Blake2bHash
 	}
+
+	return t.hash
 }
 
-// DeepCopy makes a new trie and copies over the existing trie into the new trie
+// DeepCopy makes a new trie and copies over the existing trie and its child tries into the new trie
 func (t *Trie) DeepCopy() (*Trie, error) {
-	cp := NewEmptyTrie()
+	cp := NewEmptyTrieWithHasher(t.Hasher())
 	for k, v := range t.Entries() {
 		err := cp.Put([]byte(k), v)
 		if err != nil {
@@ -62,6 +79,18 @@ func (t *Trie) DeepCopy() (*Trie, error) {
 		}
 	}
 
+	for hash, child := range t.children {
+		if child == nil {
+			continue
+		}
+
+		childCp, err := child.DeepCopy()
+		if err != nil {
+			return nil, err
+		}
+		cp.children[hash] = childCp
+	}
+
 	return cp, nil
 }
 
@@ -72,7 +101,7 @@ func (t *Trie) RootNode() node { //nolint
 
 // EncodeRoot returns the encoded root of the trie
 func (t *Trie) EncodeRoot() ([]byte, error) {
-	return encode(t.RootNode())
+	return encode(t.RootNode(), t.Hasher())
 }
 
 // MustHash returns the hashed root of the trie. It panics if it fails to hash the root node.
@@ -92,7 +121,7 @@ func (t *Trie) Hash() (common.Hash, error) {
 		return [32]byte{}, err
 	}
 
-	return common.Blake2bHash(encRoot)
+	return t.Hasher()(encRoot)
 }
 
 // Entries returns all the key-value pairs in the trie as a map of keys to values
@@ -433,6 +462,74 @@ func (t *Trie) addAllKeys(parent node, prefix []byte, keys [][]byte) [][]byte {
 	return keys
 }
 
+// GetKeysPaged returns up to limit keys with the given prefix that come strictly after startKey, in lexicographic
+// order. Only the parts of the trie that may hold such keys are visited.
+func (t *Trie) GetKeysPaged(prefix, startKey []byte, limit int) [][]byte {
+	keys := [][]byte{}
+	if limit <= 0 {
+		return keys
+	}
+
+	return t.getKeysPaged(t.root, []byte{}, keyToNibbles(prefix), keyToNibbles(startKey), limit, keys)
+}
+
+// getKeysPaged appends the keys of the subtrie at the given node to keys in lexicographic order, as described by
+// GetKeysPaged. The path is the key of the node's parent followed by the node's index in it, in nibbles.
+func (t *Trie) getKeysPaged(n node, path, prefix, start []byte, limit int, keys [][]byte) [][]byte {
+	if len(keys) >= limit {
+		return keys
+	}
+
+	var (
+		partial  []byte
+		hasValue bool
+		children []node
+	)
+
+	switch c := n.(type) {
+	case *branch:
+		partial = c.key
+		hasValue = c.value != nil
+		children = c.children[:]
+	case *leaf:
+		partial = c.key
+		hasValue = true
+	case nil:
+		return keys
+	}
+
+	key := append(append([]byte{}, path...), partial...)
+
+	// all the keys of the subtrie start with the node's key, so it can be skipped if that isn't compatible with the
+	// prefix or comes before the start key
+	l := len(key)
+	if len(prefix) < l {
+		l = len(prefix)
+	}
+	if !bytes.Equal(key[:l], prefix[:l]) {
+		return keys
+	}
+
+	l = len(key)
+	if len(start) < l {
+		l = len(start)
+	}
+	if bytes.Compare(key[:l], start[:l]) < 0 {
+		return keys
+	}
+
+	// the node's own key comes before the keys of its children
+	if hasValue && len(key) >= len(prefix) && bytes.Compare(key, start) > 0 {
+		keys = append(keys, nibblesToKeyLE(key))
+	}
+
+	for i, child := range children {
+		keys = t.getKeysPaged(child, append(key, byte(i)), prefix, start, limit, keys)
+	}
+
+	return keys
+}
+
 // Get returns the value for key stored in the trie at the corresponding key
 func (t *Trie) Get(key []byte) (value []byte, err error) {
 	l, err := t.tryGet(key)
@@ -578,6 +578,43 @@ func TestGetKeysWithPrefix(t *testing.T) {
 	}
 }
 
+func TestGetKeysPaged(t *testing.T) {
+	trie := NewEmptyTrie()
+
+	tests := []Test{
+		{key: []byte{0xf2}, value: []byte("pho"), op: PUT},
+		{key: []byte{0x07, 0x3b}, value: []byte("noodles"), op: PUT},
+		{key: []byte{0x01, 0x35, 0x79}, value: []byte("gnocchi"), op: PUT},
+		{key: []byte{0x07, 0x3a}, value: []byte("ramen"), op: PUT},
+		{key: []byte{0x01, 0x35}, value: []byte("spaghetti"), op: PUT},
+	}
+
+	for _, test := range tests {
+		trie.Put(test.key, test.value)
+	}
+
+	cases := []struct {
+		prefix   []byte
+		start    []byte
+		limit    int
+		expected [][]byte
+	}{
+		{nil, nil, 10, [][]byte{{0x01, 0x35}, {0x01, 0x35, 0x79}, {0x07, 0x3a}, {0x07, 0x3b}, {0xf2}}},
+		{[]byte{0x01}, []byte{0x01, 0x35}, 10, [][]byte{{0x01, 0x35, 0x79}}},
+		{nil, []byte{0x02}, 2, [][]byte{{0x07, 0x3a}, {0x07, 0x3b}}},
+		{[]byte{0x07}, nil, 1, [][]byte{{0x07, 0x3a}}},
+		{[]byte{0x07}, []byte{0x07, 0x3b}, 10, [][]byte{}},
+		{nil, nil, 0, [][]byte{}},
+	}
+
+	for _, tc := range cases {
+		keys := trie.GetKeysPaged(tc.prefix, tc.start, tc.limit)
+		if !reflect.DeepEqual(keys, tc.expected) {
+			t.Fatalf("Fail: got %v expected %v", keys, tc.expected)
+		}
+	}
+}
+
 func TestNextKey(t *testing.T) {
 	trie := NewEmptyTrie()
 
@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 const (
@@ -78,6 +79,7 @@ func install(debug bool) {
 	if debug {
 		cmd.Args = append(cmd.Args, "-gcflags=\"all=-N -l\"")
 	}
+	cmd.Args = append(cmd.Args, "-ldflags", buildLdflags())
 	cmd.Args = append(cmd.Args, packages...)
 
 	fmt.Println("Build Gossamer", strings.Join(cmd.Args, " \\\n"))
@@ -89,6 +91,18 @@ func install(debug bool) {
 
 }
 
+// buildLdflags returns the linker flags used to embed the git commit and build date into the binary
+func buildLdflags() string {
+	commit := "unknown"
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err == nil {
+		commit = strings.TrimSpace(string(out))
+	}
+
+	date := time.Now().UTC().Format(time.RFC3339)
+	return fmt.Sprintf("-X main.gitCommit=%s -X main.buildDate=%s", commit, date)
+}
+
 func lint() {
 
 	verbose := flag.Bool("v", false, "Whether to log verbosely")
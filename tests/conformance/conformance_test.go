@@ -0,0 +1,202 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/tests/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var conformanceSuite = "conformance"
+
+func TestMain(m *testing.M) {
+	_, _ = fmt.Fprintln(os.Stdout, "Going to start RPC conformance suite test")
+
+	// Start all tests
+	code := m.Run()
+	os.Exit(code)
+}
+
+type conformanceCase struct {
+	description string
+	method      string
+	params      string
+	// ignore lists result paths that are expected to differ between implementations
+	ignore map[string]bool
+}
+
+// genesisHash is replaced in a case's params by the genesis hash returned by the reference node
+const genesisHash = "$GENESIS"
+
+var conformanceCases = []*conformanceCase{
+	{
+		description: "system_chain",
+		method:      "system_chain",
+		params:      "[]",
+	},
+	{
+		description: "system_properties",
+		method:      "system_properties",
+		params:      "[]",
+	},
+	{
+		description: "chain_getBlockHash genesis",
+		method:      "chain_getBlockHash",
+		params:      "[0]",
+	},
+	{
+		description: "chain_getHeader genesis",
+		method:      "chain_getHeader",
+		params:      `["` + genesisHash + `"]`,
+	},
+	{
+		description: "chain_getBlock genesis",
+		method:      "chain_getBlock",
+		params:      `["` + genesisHash + `"]`,
+		// substrate does not return justifications for the genesis block
+		ignore: map[string]bool{".justification": true},
+	},
+	{
+		description: "chain_getRuntimeVersion genesis",
+		method:      "chain_getRuntimeVersion",
+		params:      `["` + genesisHash + `"]`,
+	},
+	{
+		description: "state_getRuntimeVersion genesis",
+		method:      "state_getRuntimeVersion",
+		params:      `["` + genesisHash + `"]`,
+	},
+	{
+		description: "state_getMetadata genesis",
+		method:      "state_getMetadata",
+		params:      `["` + genesisHash + `"]`,
+	},
+	{
+		description: "state_getStorageHash :code",
+		method:      "state_getStorageHash",
+		params:      `["0x3a636f6465", "` + genesisHash + `"]`,
+	},
+	{
+		description: "state_getStorageSize :code",
+		method:      "state_getStorageSize",
+		params:      `["0x3a636f6465", "` + genesisHash + `"]`,
+	},
+	{
+		description: "state_getStorage missing key",
+		method:      "state_getStorage",
+		params:      `["0xdeadbeef", "` + genesisHash + `"]`,
+	},
+	{
+		description: "chain_getBlockHash unknown block",
+		method:      "chain_getBlockHash",
+		params:      "[1000000]",
+	},
+}
+
+// TestRPCConformance runs the same JSON-RPC calls against a gossamer node and a reference Substrate node started
+// with the same raw genesis (chain/gssmr/genesis-raw.json) and compares the responses field-by-field.
+// The reference node's http endpoint is given by the REFERENCE_RPC environment variable.
+func TestRPCConformance(t *testing.T) {
+	if utils.MODE != conformanceSuite {
+		_, _ = fmt.Fprintln(os.Stdout, "Going to skip RPC conformance suite tests")
+		return
+	}
+
+	if utils.REFERENCE_RPC == "" {
+		t.Skip("REFERENCE_RPC is not set, skipping RPC conformance suite")
+	}
+
+	utils.CreateConfigNoBabe()
+	defer os.Remove(utils.ConfigNoBABE)
+
+	t.Log("starting gossamer...")
+	nodes, err := utils.InitializeAndStartNodes(t, 1, utils.GenesisDefault, utils.ConfigNoBABE)
+	require.Nil(t, err)
+
+	defer func() {
+		t.Log("going to tear down gossamer...")
+		errList := utils.TearDown(t, nodes)
+		require.Len(t, errList, 0)
+	}()
+
+	time.Sleep(time.Second) // give server a second to start
+
+	gossamerEndpoint := utils.NewEndpoint(strconv.Itoa(utils.BaseRPCPort))
+
+	resp, err := utils.PostRPCResponse("chain_getBlockHash", utils.REFERENCE_RPC, "[0]")
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+
+	var hash string
+	err = json.Unmarshal(resp.Result, &hash)
+	require.NoError(t, err)
+
+	for _, test := range conformanceCases {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			compareResponses(t, test, gossamerEndpoint, strings.ReplaceAll(test.params, genesisHash, hash))
+		})
+	}
+}
+
+func compareResponses(t *testing.T, test *conformanceCase, endpoint, params string) {
+	expected, err := utils.PostRPCResponse(test.method, utils.REFERENCE_RPC, params)
+	require.NoError(t, err)
+
+	actual, err := utils.PostRPCResponse(test.method, endpoint, params)
+	require.NoError(t, err)
+
+	if expected.Error != nil {
+		require.NotNil(t, actual.Error, "expected error %q, got result %s", expected.Error.Message, actual.Result)
+		return
+	}
+
+	require.Nil(t, actual.Error, "unexpected error")
+
+	diffs, err := utils.DiffJSON(actual.Result, expected.Result, test.ignore)
+	require.NoError(t, err)
+	require.Empty(t, diffs, "responses to %s %s differ", test.method, params)
+}
+
+func TestDiffJSON(t *testing.T) {
+	a := []byte(`{"number":"0x1","digest":{"logs":["0x01","0x02"]},"extra":1}`)
+	b := []byte(`{"number":"0x1","digest":{"logs":["0x01","0x03"]},"parentHash":"0x00"}`)
+
+	diffs, err := utils.DiffJSON(a, a, nil)
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	diffs, err = utils.DiffJSON(a, b, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		".digest.logs[1]: 0x02 != 0x03",
+		".extra: unexpected field with value 1",
+		".parentHash: missing, expected 0x00",
+	}, diffs)
+
+	diffs, err = utils.DiffJSON(a, b, map[string]bool{".digest.logs": true, ".extra": true, ".parentHash": true})
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+}
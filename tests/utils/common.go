@@ -33,6 +33,9 @@ var (
 
 	NETWORK_SIZE = os.Getenv("NETWORK_SIZE")
 
+	// REFERENCE_RPC is the http endpoint of the reference (Substrate) node used by the conformance suite
+	REFERENCE_RPC = os.Getenv("REFERENCE_RPC")
+
 	ContentTypeJSON   = "application/json"
 	dialTimeout       = 60 * time.Second
 	httpClientTimeout = 120 * time.Second
@@ -0,0 +1,128 @@
+// Copyright 2020 ChainSafe Systems (ON) Corp.
+// This file is part of gossamer.
+//
+// The gossamer library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gossamer library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gossamer library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PostRPCResponse sends the given request to the endpoint and returns the decoded JSON-RPC response
+func PostRPCResponse(method, host, params string) (*ServerResponse, error) {
+	body, err := PostRPC(method, host, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(ServerResponse)
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response %s: %w", string(body), err)
+	}
+
+	return response, nil
+}
+
+// DiffJSON compares two JSON documents field-by-field and returns a description of every path at which they differ.
+// Paths are formatted as `.block.header.number` or `.logs[0]`; any path contained in ignore is not compared.
+func DiffJSON(a, b json.RawMessage, ignore map[string]bool) ([]string, error) {
+	var va, vb interface{}
+	if err := decodeJSON(a, &va); err != nil {
+		return nil, err
+	}
+
+	if err := decodeJSON(b, &vb); err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	diffValues("", va, vb, ignore, &diffs)
+	return diffs, nil
+}
+
+func decodeJSON(in json.RawMessage, out *interface{}) error {
+	if len(in) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(in))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+func diffValues(path string, a, b interface{}, ignore map[string]bool, diffs *[]string) {
+	if ignore[path] {
+		return
+	}
+
+	switch va := a.(type) {
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", path, a, b))
+			return
+		}
+
+		keys := make(map[string]struct{})
+		for k := range va {
+			keys[k] = struct{}{}
+		}
+		for k := range vb {
+			keys[k] = struct{}{}
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			ca, hasA := va[k]
+			cb, hasB := vb[k]
+			switch {
+			case !hasA:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: missing, expected %v", path, k, cb))
+			case !hasB:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: unexpected field with value %v", path, k, ca))
+			default:
+				diffValues(path+"."+k, ca, cb, ignore, diffs)
+			}
+		}
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", path, a, b))
+			return
+		}
+
+		if len(va) != len(vb) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length %d != %d", path, len(va), len(vb)))
+			return
+		}
+
+		for i := range va {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), va[i], vb[i], ignore, diffs)
+		}
+	default:
+		if a != b {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", path, a, b))
+		}
+	}
+}
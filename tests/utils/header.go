@@ -41,13 +41,17 @@ func HeaderResponseToHeader(t *testing.T, header *modules.ChainBlockHeaderRespon
 	extrinsicsRoot, err := common.HexToHash(header.ExtrinsicsRoot)
 	require.NoError(t, err)
 
-	digest := [][]byte{}
+	digest := types.NewEmptyDigest()
 
 	for _, l := range header.Digest.Logs {
 		var d []byte
 		d, err = common.HexToBytes(l)
 		require.NoError(t, err)
-		digest = append(digest, d)
+
+		var item types.DigestItem
+		item, err = types.DecodeDigestItem(d)
+		require.NoError(t, err)
+		digest = append(digest, item)
 	}
 
 	h, err := types.NewHeader(parentHash, number, stateRoot, extrinsicsRoot, digest)
@@ -33,4 +33,8 @@ var (
 
 	// DEV METHODS
 	DevControl = "dev_control"
+
+	// BABE METHODS
+	BabeGetConfiguration   = "babe_getConfiguration"
+	BabeGetAuthorshipStats = "babe_getAuthorshipStats"
 )